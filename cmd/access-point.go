@@ -0,0 +1,153 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/signer"
+)
+
+// mrapSuffix ends the aliases of the multi-region access points of AWS S3.
+const mrapSuffix = ".mrap"
+
+// accessPoint is an access point of AWS S3 addressed in place of a bucket,
+// e.g. 's3/arn:aws:s3:us-west-2:123456789012:accesspoint/logs/2023/'.
+type accessPoint struct {
+	// path is the ARN, or the alias, of the access point in the paths.
+	path string
+	// name is the bucket given to the SDK, removed from the requests.
+	name string
+	// host is the endpoint of the access point.
+	host string
+	// region is the signing region, empty for multi-region access points.
+	region string
+}
+
+// parseAccessPoint returns the access point addressed by the bucket and the
+// object of a path, and the object within the access point. Access points
+// are given by their ARN, multi-region access points by their ARN or, when
+// amazon is set, by their alias. It returns nil for the other buckets.
+func parseAccessPoint(bucket, object string, amazon bool) (*accessPoint, string) {
+	if amazon && strings.HasSuffix(bucket, mrapSuffix) {
+		return &accessPoint{
+			path: bucket,
+			name: bucket,
+			host: bucket + ".accesspoint.s3-global.amazonaws.com",
+		}, object
+	}
+
+	// arn:partition:s3:region:account:accesspoint/name or accesspoint:name
+	fields := strings.Split(bucket, ":")
+	if len(fields) < 6 || fields[0] != "arn" || fields[2] != "s3" || fields[5] != "accesspoint" {
+		return nil, object
+	}
+	name, path := "", bucket
+	switch len(fields) {
+	case 6:
+		name, object, _ = strings.Cut(object, "/")
+		path += "/" + name
+	case 7:
+		name = fields[6]
+	}
+	partition, region, account := fields[1], fields[3], fields[4]
+	if name == "" || account == "" {
+		return nil, object
+	}
+	if region == "" {
+		return &accessPoint{
+			path: path,
+			name: name,
+			host: name + ".accesspoint.s3-global.amazonaws.com",
+		}, object
+	}
+	domain := "amazonaws.com"
+	if partition == "aws-cn" {
+		domain += ".cn"
+	}
+	return &accessPoint{
+		path:   path,
+		name:   name,
+		host:   name + "-" + account + ".s3-accesspoint." + region + "." + domain,
+		region: region,
+	}, object
+}
+
+// withAccessPoint returns the credentials given to the SDK and the transport
+// sending its requests to the access point ap. The requests are signed by
+// the transport, with SigV4A for multi-region access points.
+func withAccessPoint(config *Config, ap *accessPoint, creds *credentials.Credentials, transport http.RoundTripper) (*credentials.Credentials, http.RoundTripper, error) {
+	t := accessPointTransport{transport: transport, accessPoint: ap, creds: creds}
+	if ap.region == "" {
+		sigV4A, e := sigV4AScheme{}.wrap(config, transport)
+		if e != nil {
+			return nil, nil, e
+		}
+		t.transport, t.creds = sigV4A, nil
+	}
+	return credentials.NewStaticV4("", "", ""), t, nil
+}
+
+type accessPointTransport struct {
+	transport   http.RoundTripper
+	accessPoint *accessPoint
+	// creds signs with SigV4, nil when the next transport signs.
+	creds *credentials.Credentials
+}
+
+func (t accessPointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "https"
+	req.URL.Host, req.Host = t.accessPoint.host, t.accessPoint.host
+	req.URL.Path = accessPointRequestPath(req.URL.Path, t.accessPoint.name)
+	if req.URL.RawPath != "" {
+		req.URL.RawPath = accessPointRequestPath(req.URL.RawPath, t.accessPoint.name)
+	}
+	if t.creds == nil {
+		return t.transport.RoundTrip(req)
+	}
+
+	value, e := t.creds.Get()
+	if e != nil {
+		return nil, e
+	}
+	if value.SignerType.IsAnonymous() {
+		return t.transport.RoundTrip(req)
+	}
+	if req.Header.Get("X-Amz-Content-Sha256") == "" {
+		payloadHash := unsignedPayload
+		if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+			payloadHash = emptySHA256
+		}
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+	req = signer.SignV4(*req, value.AccessKeyID, value.SecretAccessKey, value.SessionToken, t.accessPoint.region)
+	return t.transport.RoundTrip(req)
+}
+
+// accessPointRequestPath returns the path of a request of the SDK to the
+// bucket name, without the bucket.
+func accessPointRequestPath(path, name string) string {
+	path = strings.TrimPrefix(path, "/"+name)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
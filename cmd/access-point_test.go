@@ -0,0 +1,73 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+)
+
+func TestParseAccessPoint(t *testing.T) {
+	testCases := []struct {
+		bucket, object string
+		amazon         bool
+		want           *accessPoint
+		wantObject     string
+	}{
+		{"mybucket", "a/b", true, nil, "a/b"},
+		{"arn:aws:s3:us-west-2:123456789012:accesspoint", "logs/2023/a.log", true, &accessPoint{
+			path:   "arn:aws:s3:us-west-2:123456789012:accesspoint/logs",
+			name:   "logs",
+			host:   "logs-123456789012.s3-accesspoint.us-west-2.amazonaws.com",
+			region: "us-west-2",
+		}, "2023/a.log"},
+		{"arn:aws:s3:us-west-2:123456789012:accesspoint:logs", "a.log", false, &accessPoint{
+			path:   "arn:aws:s3:us-west-2:123456789012:accesspoint:logs",
+			name:   "logs",
+			host:   "logs-123456789012.s3-accesspoint.us-west-2.amazonaws.com",
+			region: "us-west-2",
+		}, "a.log"},
+		{"arn:aws-cn:s3:cn-north-1:123456789012:accesspoint", "logs", true, &accessPoint{
+			path:   "arn:aws-cn:s3:cn-north-1:123456789012:accesspoint/logs",
+			name:   "logs",
+			host:   "logs-123456789012.s3-accesspoint.cn-north-1.amazonaws.com.cn",
+			region: "cn-north-1",
+		}, ""},
+		{"arn:aws:s3::123456789012:accesspoint", "mfzwi23gnjvgw.mrap/a", true, &accessPoint{
+			path: "arn:aws:s3::123456789012:accesspoint/mfzwi23gnjvgw.mrap",
+			name: "mfzwi23gnjvgw.mrap",
+			host: "mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com",
+		}, "a"},
+		{"mfzwi23gnjvgw.mrap", "a", true, &accessPoint{
+			path: "mfzwi23gnjvgw.mrap",
+			name: "mfzwi23gnjvgw.mrap",
+			host: "mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com",
+		}, "a"},
+		{"mfzwi23gnjvgw.mrap", "a", false, nil, "a"},
+		{"arn:aws:s3:us-west-2:123456789012:accesspoint", "", true, nil, ""},
+		{"arn:aws:sqs:us-west-2:123456789012:accesspoint", "logs", true, nil, "logs"},
+	}
+	for i, testCase := range testCases {
+		got, gotObject := parseAccessPoint(testCase.bucket, testCase.object, testCase.amazon)
+		if (got == nil) != (testCase.want == nil) || got != nil && *got != *testCase.want {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.want, got)
+		}
+		if gotObject != testCase.wantObject {
+			t.Errorf("Test %d: expected object %q, got %q", i+1, testCase.wantObject, gotObject)
+		}
+	}
+}
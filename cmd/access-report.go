@@ -0,0 +1,330 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	iampolicy "github.com/minio/pkg/iam/policy"
+)
+
+var accessReportCmd = cli.Command{
+	Name:         "access-report",
+	Usage:        "summarize who can read and write a bucket or a prefix",
+	Action:       mainAccessReport,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Combine the anonymous access of the bucket policy, the IAM policies of the users and groups
+  and the share links generated with 'mc share' into a single report of the access to a bucket
+  or a prefix. Publicly readable prefixes are shown in red.
+
+  The IAM policies are only reported for MinIO servers, with credentials allowed to list the
+  policies, users and groups. The root user always has full access.
+
+EXAMPLES:
+  1. Show who can read and write the bucket 'mybucket'.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+
+  2. Show who can read and write the prefix 'reports/' in JSON format.
+     {{.Prompt}} {{.HelpName}} --json myminio/mybucket/reports/
+`,
+}
+
+// bucketAccessRule is the anonymous access to a prefix from the bucket policy.
+type bucketAccessRule struct {
+	Resource string `json:"resource"`
+	Access   string `json:"access"`
+}
+
+// bucketAccessPrincipal is the access granted to a user or a group by their
+// IAM policies.
+type bucketAccessPrincipal struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Read     bool     `json:"read"`
+	Write    bool     `json:"write"`
+	List     bool     `json:"list"`
+	Policies []string `json:"policies"`
+}
+
+// bucketAccessShare is an unexpired share link of an object of the target.
+type bucketAccessShare struct {
+	Type      string    `json:"type"`
+	ObjectURL string    `json:"objectURL"`
+	Expiry    time.Time `json:"expiry"`
+}
+
+// bucketAccessMessage is the access report of a bucket or a prefix.
+type bucketAccessMessage struct {
+	Status     string                  `json:"status"`
+	Target     string                  `json:"target"`
+	Anonymous  string                  `json:"anonymous"`
+	Public     bool                    `json:"public"`
+	Rules      []bucketAccessRule      `json:"rules,omitempty"`
+	Principals []bucketAccessPrincipal `json:"principals,omitempty"`
+	IAMError   string                  `json:"iamError,omitempty"`
+	Shares     []bucketAccessShare     `json:"shares,omitempty"`
+}
+
+// isPublicRead tells whether an anonymous access allows reading.
+func isPublicRead(access string) bool {
+	return access == "readonly" || access == "readwrite"
+}
+
+func bucketAccessFlags(read, write, list bool) string {
+	flags := []byte("---")
+	if read {
+		flags[0] = 'r'
+	}
+	if write {
+		flags[1] = 'w'
+	}
+	if list {
+		flags[2] = 'l'
+	}
+	return string(flags)
+}
+
+// Colorized message for console printing.
+func (a bucketAccessMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, console.Colorize("ReportHeader", "Access to "+a.Target))
+
+	anonymousTheme := "ReportPrivate"
+	switch {
+	case isPublicRead(a.Anonymous):
+		anonymousTheme = "ReportPublic"
+	case a.Anonymous == "custom":
+		anonymousTheme = "ReportWarn"
+	}
+	fmt.Fprintf(&b, "  Anonymous : %s\n", console.Colorize(anonymousTheme, a.Anonymous))
+	for _, rule := range a.Rules {
+		ruleTheme := "ReportPrivate"
+		if isPublicRead(rule.Access) {
+			ruleTheme = "ReportPublic"
+		}
+		fmt.Fprintf(&b, "    %s\n", console.Colorize(ruleTheme, fmt.Sprintf("%s => %s", rule.Resource, rule.Access)))
+	}
+
+	fmt.Fprintln(&b, console.Colorize("ReportHeader", "  IAM policies (r: read, w: write, l: list)"))
+	switch {
+	case a.IAMError != "":
+		fmt.Fprintf(&b, "    %s\n", console.Colorize("ReportWarn", "Unavailable: "+a.IAMError))
+	case len(a.Principals) == 0:
+		fmt.Fprintln(&b, "    No users or groups")
+	}
+	table := newPrettyTable("  ", Field{"", 4}, Field{"", 5}, Field{"ReportPrincipal", 24}, Field{"", 3}, Field{"", -1})
+	for _, p := range a.Principals {
+		fmt.Fprintln(&b, table.buildRow("", p.Type, p.Name, bucketAccessFlags(p.Read, p.Write, p.List), strings.Join(p.Policies, ",")))
+	}
+
+	fmt.Fprintln(&b, console.Colorize("ReportHeader", "  Share links"))
+	if len(a.Shares) == 0 {
+		fmt.Fprintln(&b, "    No share links")
+	}
+	for _, share := range a.Shares {
+		expiry := timeDurationToHumanizedDuration(time.Until(share.Expiry)).StringShort()
+		fmt.Fprintf(&b, "    %-8s %s (expires in %s)\n", share.Type, share.ObjectURL, expiry)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON'ified message for scripting.
+func (a bucketAccessMessage) JSON() string {
+	a.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(a, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// iamPolicyAccess evaluates the read, write and list access a policy grants
+// on a bucket or a prefix.
+func iamPolicyAccess(p *iampolicy.Policy, bucket, prefix string) (read, write, list bool) {
+	read = p.IsAllowed(iampolicy.Args{Action: iampolicy.GetObjectAction, BucketName: bucket, ObjectName: prefix})
+	write = p.IsAllowed(iampolicy.Args{Action: iampolicy.PutObjectAction, BucketName: bucket, ObjectName: prefix})
+	list = p.IsAllowed(iampolicy.Args{
+		Action:          iampolicy.ListBucketAction,
+		BucketName:      bucket,
+		ConditionValues: map[string][]string{"prefix": {prefix}},
+	})
+	return read, write, list
+}
+
+// bucketAccessIAM returns the users and groups with access to a bucket or a
+// prefix through the policies attached to them.
+func bucketAccessIAM(ctx context.Context, aliasedURL, bucket, prefix string) ([]bucketAccessPrincipal, *probe.Error) {
+	client, err := newAdminClient(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	policies, e := client.ListCannedPolicies(ctx)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	access := map[string][3]bool{}
+	for name, raw := range policies {
+		p, e := iampolicy.ParseConfig(bytes.NewReader(raw))
+		if e != nil {
+			continue
+		}
+		read, write, list := iamPolicyAccess(p, bucket, prefix)
+		access[name] = [3]bool{read, write, list}
+	}
+	principal := func(kind, name, attached string) (bucketAccessPrincipal, bool) {
+		pr := bucketAccessPrincipal{Type: kind, Name: name}
+		for _, policyName := range splitPolicies(attached) {
+			a := access[policyName]
+			if !a[0] && !a[1] && !a[2] {
+				continue
+			}
+			pr.Read, pr.Write, pr.List = pr.Read || a[0], pr.Write || a[1], pr.List || a[2]
+			pr.Policies = append(pr.Policies, policyName)
+		}
+		return pr, len(pr.Policies) > 0
+	}
+
+	var principals []bucketAccessPrincipal
+	users, e := client.ListUsers(ctx)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	for name, user := range users {
+		if pr, ok := principal("user", name, user.PolicyName); ok {
+			principals = append(principals, pr)
+		}
+	}
+	groups, e := client.ListGroups(ctx)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	for _, name := range groups {
+		desc, e := client.GetGroupDescription(ctx, name)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(name)
+		}
+		if pr, ok := principal("group", name, desc.Policy); ok {
+			principals = append(principals, pr)
+		}
+	}
+	sort.Slice(principals, func(i, j int) bool {
+		if principals[i].Type != principals[j].Type {
+			return principals[i].Type == "group"
+		}
+		return principals[i].Name < principals[j].Name
+	})
+	return principals, nil
+}
+
+// bucketAccessShares returns the unexpired share links of the objects under
+// a URL, as saved by 'mc share'.
+func bucketAccessShares(urlStr string) (shares []bucketAccessShare) {
+	for kind, filename := range map[string]string{
+		"download": getShareDownloadsFile(),
+		"upload":   getShareUploadsFile(),
+	} {
+		shareDB := newShareDBV1()
+		if err := shareDB.Load(filename); err != nil {
+			continue
+		}
+		for _, share := range shareDB.Shares {
+			if share.state() == shareStateActive && strings.HasPrefix(share.URL, urlStr) {
+				shares = append(shares, bucketAccessShare{
+					Type:      kind,
+					ObjectURL: share.URL,
+					Expiry:    share.Date.Add(share.Expiry),
+				})
+			}
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].ObjectURL != shares[j].ObjectURL {
+			return shares[i].ObjectURL < shares[j].ObjectURL
+		}
+		return shares[i].Type < shares[j].Type
+	})
+	return shares
+}
+
+// mainAccessReport is the handle for "mc access-report" command.
+func mainAccessReport(cliCtx *cli.Context) error {
+	ctx, cancelAccessReport := context.WithCancel(globalContext)
+	defer cancelAccessReport()
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("ReportHeader", color.New(color.Bold))
+	console.SetColor("ReportPublic", color.New(color.FgRed, color.Bold))
+	console.SetColor("ReportPrivate", color.New(color.FgGreen))
+	console.SetColor("ReportPrincipal", color.New(color.FgCyan))
+	console.SetColor("ReportWarn", color.New(color.FgYellow))
+
+	targetURL := cliCtx.Args().First()
+	_, urlStr, _ := mustExpandAlias(targetURL)
+	clnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+	s3Clnt, ok := clnt.(*S3Client)
+	if !ok {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Access reports are only supported on S3 targets.")
+	}
+	bucket, prefix := s3Clnt.url2BucketAndObject()
+	if bucket == "" {
+		fatalIf(errInvalidArgument().Trace(targetURL), "A bucket or a prefix is needed.")
+	}
+
+	msg := bucketAccessMessage{Target: targetURL}
+	msg.Anonymous, _, err = clnt.GetAccess(ctx)
+	fatalIf(err.Trace(targetURL), "Unable to get the bucket policy of `"+targetURL+"`.")
+	msg.Public = isPublicRead(msg.Anonymous)
+	rules, err := clnt.GetAccessRules(ctx)
+	fatalIf(err.Trace(targetURL), "Unable to get the bucket policy of `"+targetURL+"`.")
+	for resource, access := range rules {
+		msg.Rules = append(msg.Rules, bucketAccessRule{Resource: resource, Access: access})
+		msg.Public = msg.Public || isPublicRead(access)
+	}
+	sort.Slice(msg.Rules, func(i, j int) bool { return msg.Rules[i].Resource < msg.Rules[j].Resource })
+
+	if msg.Principals, err = bucketAccessIAM(ctx, targetURL, bucket, prefix); err != nil {
+		msg.IAMError = err.ToGoError().Error()
+	}
+	msg.Shares = bucketAccessShares(urlStr)
+
+	printMsg(msg)
+	return nil
+}
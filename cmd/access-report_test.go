@@ -0,0 +1,54 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	iampolicy "github.com/minio/pkg/iam/policy"
+)
+
+func TestIAMPolicyAccess(t *testing.T) {
+	p, e := iampolicy.ParseConfig(strings.NewReader(`{
+ "Version": "2012-10-17",
+ "Statement": [
+  {"Effect": "Allow", "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::mybucket/reports/*"]},
+  {"Effect": "Allow", "Action": ["s3:ListBucket"], "Resource": ["arn:aws:s3:::mybucket"], "Condition": {"StringLike": {"s3:prefix": ["reports/*"]}}},
+  {"Effect": "Allow", "Action": ["s3:PutObject"], "Resource": ["arn:aws:s3:::mybucket/uploads/*"]}
+ ]
+}`))
+	if e != nil {
+		t.Fatal(e)
+	}
+	testCases := []struct {
+		bucket, prefix    string
+		read, write, list bool
+	}{
+		{"mybucket", "reports/", true, false, true},
+		{"mybucket", "uploads/", false, true, false},
+		{"mybucket", "", false, false, false},
+		{"otherbucket", "reports/", false, false, false},
+	}
+	for _, tc := range testCases {
+		read, write, list := iamPolicyAccess(p, tc.bucket, tc.prefix)
+		if read != tc.read || write != tc.write || list != tc.list {
+			t.Errorf("%s/%s: got %v %v %v, want %v %v %v", tc.bucket, tc.prefix, read, write, list, tc.read, tc.write, tc.list)
+		}
+	}
+}
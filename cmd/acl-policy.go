@@ -0,0 +1,405 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/policy"
+	"github.com/minio/minio-go/v7/pkg/set"
+	"github.com/minio/pkg/console"
+)
+
+// The groups of the grantees of the ACLs of AWS S3.
+const (
+	aclAllUsers           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	aclAuthenticatedUsers = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+	aclLogDelivery        = "http://acs.amazonaws.com/groups/s3/LogDelivery"
+)
+
+// aclResourcePrefix prefixes the buckets and the objects in the resources
+// of the policies.
+const aclResourcePrefix = "arn:aws:s3:::"
+
+// accessControlPolicy is the access control list of a bucket or an object.
+type accessControlPolicy struct {
+	XMLName xml.Name   `xml:"AccessControlPolicy"`
+	Owner   aclGrantee `xml:"Owner"`
+	Grants  []aclGrant `xml:"AccessControlList>Grant"`
+}
+
+// aclGrant grants a permission, READ, WRITE, READ_ACP, WRITE_ACP or
+// FULL_CONTROL, to a grantee.
+type aclGrant struct {
+	Grantee    aclGrantee `xml:"Grantee"`
+	Permission string     `xml:"Permission"`
+}
+
+// aclGrantee is an account, given by its canonical ID or its email, or a
+// group given by its URI.
+type aclGrantee struct {
+	ID           string `xml:"ID"`
+	DisplayName  string `xml:"DisplayName"`
+	EmailAddress string `xml:"EmailAddress"`
+	URI          string `xml:"URI"`
+}
+
+func (g aclGrantee) String() string {
+	switch {
+	case g.URI != "":
+		return g.URI[strings.LastIndex(g.URI, "/")+1:]
+	case g.EmailAddress != "":
+		return g.EmailAddress
+	case g.DisplayName != "":
+		return g.DisplayName + " (" + g.ID + ")"
+	}
+	return g.ID
+}
+
+// aclUnmappedGrant is a grant without an equivalent anonymous policy.
+type aclUnmappedGrant struct {
+	Grantee    string `json:"grantee"`
+	Permission string `json:"permission"`
+	Reason     string `json:"reason"`
+}
+
+// mapACL returns whether the ACL allows anonymous reads, the only access
+// mapped to bucket policies, and the other grants. The grants of the owner
+// are implied and ignored.
+func mapACL(acl *accessControlPolicy) (public bool, unmapped []aclUnmappedGrant) {
+	for _, grant := range acl.Grants {
+		if grant.Grantee.ID != "" && grant.Grantee.ID == acl.Owner.ID {
+			continue
+		}
+		reason := ""
+		switch grant.Grantee.URI {
+		case aclAllUsers:
+			switch grant.Permission {
+			case "READ":
+				public = true
+			case "FULL_CONTROL":
+				public = true
+				reason = "only the anonymous reads are mapped"
+			default:
+				reason = "anonymous writes and ACL accesses are not mapped"
+			}
+		case aclAuthenticatedUsers:
+			reason = "grants to all the AWS accounts have no equivalent"
+		case aclLogDelivery:
+			reason = "the server access logs are not migrated"
+		default:
+			reason = "grants to other accounts are not mapped, use IAM policies"
+		}
+		if reason != "" {
+			unmapped = append(unmapped, aclUnmappedGrant{
+				Grantee:    grant.Grantee.String(),
+				Permission: grant.Permission,
+				Reason:     reason,
+			})
+		}
+	}
+	return public, unmapped
+}
+
+// aclPolicyResources returns the keys of the public objects, collapsed
+// into the widest prefix, 'dir/*', under which all the objects of the
+// bucket are public. The objects at the root of the bucket are never
+// collapsed, which would make all the future objects public.
+func aclPolicyResources(objects map[string]bool) []string {
+	total, public := map[string]int{}, map[string]int{}
+	prefixes := func(key string) []string {
+		var ps []string
+		for i, c := range key {
+			if c == '/' && i < len(key)-1 {
+				ps = append(ps, key[:i+1])
+			}
+		}
+		return ps
+	}
+	for key, isPublic := range objects {
+		for _, p := range prefixes(key) {
+			total[p]++
+			if isPublic {
+				public[p]++
+			}
+		}
+	}
+	resources := set.NewStringSet()
+	for key, isPublic := range objects {
+		if !isPublic {
+			continue
+		}
+		resource := key
+		for _, p := range prefixes(key) {
+			if public[p] == total[p] {
+				resource = p + "*"
+				break
+			}
+		}
+		resources.Add(resource)
+	}
+	return resources.ToSlice()
+}
+
+// aclPolicyTarget collects the accesses of a target bucket mapped from the
+// ACLs of the copied objects and of their buckets.
+type aclPolicyTarget struct {
+	url      string
+	bucket   string
+	listable bool
+	objects  map[string]bool
+}
+
+// bucketObjects returns the copied objects with their access and, when
+// some are public, the other objects of the bucket as private. The
+// prefixes holding objects which were not copied are not made public.
+func (t *aclPolicyTarget) bucketObjects(ctx context.Context, clnt Client) (map[string]bool, *probe.Error) {
+	objects := map[string]bool{}
+	hasPublic := false
+	for key, public := range t.objects {
+		objects[key] = public
+		hasPublic = hasPublic || public
+	}
+	if !hasPublic {
+		return objects, nil
+	}
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			return nil, content.Err.Trace(t.url)
+		}
+		_, key := url2BucketAndObject(&content.URL)
+		if _, ok := objects[key]; !ok {
+			objects[key] = false
+		}
+	}
+	return objects, nil
+}
+
+// aclPolicyMapper maps the ACLs of the sources of cp --map-acl-to-policy
+// to the anonymous policies of the targets, which are set once the copy
+// is done.
+type aclPolicyMapper struct {
+	mu       sync.Mutex
+	buckets  map[string]bool
+	targets  map[string]*aclPolicyTarget
+	unmapped []aclUnmappedMessage
+}
+
+func newACLPolicyMapper() *aclPolicyMapper {
+	return &aclPolicyMapper{buckets: map[string]bool{}, targets: map[string]*aclPolicyTarget{}}
+}
+
+// record reads the ACLs of a copied object and of its bucket, the sources
+// which aren't on S3 have no ACLs.
+func (m *aclPolicyMapper) record(ctx context.Context, cpURLs URLs) *probe.Error {
+	sourceURL := cpURLs.SourceContent.URL
+	clnt, err := newClientFromAlias(cpURLs.SourceAlias, sourceURL.String())
+	if err != nil {
+		return err.Trace(sourceURL.String())
+	}
+	s3Clnt, ok := clnt.(*S3Client)
+	if !ok {
+		return nil
+	}
+	sourceBucket, sourceObject := s3Clnt.url2BucketAndObject()
+	acl, err := s3Clnt.GetACL(ctx, cpURLs.SourceContent.VersionID)
+	if err != nil {
+		return err.Trace(sourceURL.String())
+	}
+	public, unmapped := mapACL(acl)
+
+	targetURL := cpURLs.TargetContent.URL
+	targetBucket, targetObject := url2BucketAndObject(&targetURL)
+	bucketURL := targetURL
+	bucketURL.Path = string(bucketURL.Separator) + targetBucket
+	targetKey := cpURLs.TargetAlias + "|" + bucketURL.String()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.targets[targetKey]
+	if !ok {
+		target = &aclPolicyTarget{url: bucketURL.String(), bucket: targetBucket, objects: map[string]bool{}}
+		m.targets[targetKey] = target
+	}
+	target.objects[targetObject] = public
+	m.addUnmapped(cpURLs.SourceAlias, sourceBucket, sourceObject, unmapped)
+
+	// The bucket ACLs only grant listings, read once per source and
+	// target buckets.
+	bucketKey := cpURLs.SourceAlias + "/" + sourceBucket + "|" + targetKey
+	if m.buckets[bucketKey] {
+		return nil
+	}
+	m.buckets[bucketKey] = true
+	sourceBucketURL := sourceURL
+	sourceBucketURL.Path = strings.TrimSuffix(sourceURL.Path, sourceObject)
+	clnt, err = newClientFromAlias(cpURLs.SourceAlias, sourceBucketURL.String())
+	if err != nil {
+		return err.Trace(sourceBucketURL.String())
+	}
+	if acl, err = clnt.(*S3Client).GetACL(ctx, ""); err != nil {
+		return err.Trace(sourceBucketURL.String())
+	}
+	public, unmapped = mapACL(acl)
+	target.listable = target.listable || public
+	m.addUnmapped(cpURLs.SourceAlias, sourceBucket, "", unmapped)
+	return nil
+}
+
+func (m *aclPolicyMapper) addUnmapped(alias, bucket, object string, grants []aclUnmappedGrant) {
+	source := strings.TrimSuffix(alias+"/"+bucket+"/"+object, "/")
+	for _, grant := range grants {
+		m.unmapped = append(m.unmapped, aclUnmappedMessage{Source: source, Grant: grant})
+	}
+}
+
+// apply adds the statements mapped from the ACLs to the policies of the
+// targets, the existing statements are kept.
+func (m *aclPolicyMapper) apply(ctx context.Context) ([]aclPolicyMessage, *probe.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.targets))
+	for key := range m.targets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var msgs []aclPolicyMessage
+	for _, key := range keys {
+		target := m.targets[key]
+		alias, _, _ := strings.Cut(key, "|")
+		clnt, err := newClientFromAlias(alias, target.url)
+		if err != nil {
+			return msgs, err.Trace(target.url)
+		}
+		objects, err := target.bucketObjects(ctx, clnt)
+		if err != nil {
+			return msgs, err.Trace(target.url)
+		}
+		msg := aclPolicyMessage{Target: target.url, List: target.listable, Objects: aclPolicyResources(objects)}
+		if !msg.List && len(msg.Objects) == 0 {
+			continue
+		}
+		_, policyStr, err := clnt.GetAccess(ctx)
+		if err != nil {
+			return msgs, err.Trace(target.url)
+		}
+		p := policy.BucketAccessPolicy{Version: "2012-10-17"}
+		if policyStr != "" {
+			if e := json.Unmarshal([]byte(policyStr), &p); e != nil {
+				return msgs, probe.NewError(e).Trace(target.url)
+			}
+		}
+		p.Statements = addACLPolicyStatements(p.Statements, target.bucket, msg.List, msg.Objects)
+		data, e := json.Marshal(p)
+		if e != nil {
+			return msgs, probe.NewError(e)
+		}
+		if err = clnt.SetAccess(ctx, string(data), true); err != nil {
+			return msgs, err.Trace(target.url)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// addACLPolicyStatements adds the anonymous listing of the bucket and the
+// anonymous reads of the objects to statements, unless they are already
+// there.
+func addACLPolicyStatements(statements []policy.Statement, bucket string, list bool, objects []string) []policy.Statement {
+	add := func(action string, resources set.StringSet) {
+		for _, statement := range statements {
+			if statement.Effect == "Allow" && statement.Principal.AWS.Contains("*") && statement.Conditions == nil &&
+				statement.Actions.Equals(set.CreateStringSet(action)) && statement.Resources.Equals(resources) {
+				return
+			}
+		}
+		statements = append(statements, policy.Statement{
+			Actions:   set.CreateStringSet(action),
+			Effect:    "Allow",
+			Principal: policy.User{AWS: set.CreateStringSet("*")},
+			Resources: resources,
+		})
+	}
+	if list {
+		add("s3:ListBucket", set.CreateStringSet(aclResourcePrefix+bucket))
+	}
+	if len(objects) > 0 {
+		resources := set.NewStringSet()
+		for _, object := range objects {
+			resources.Add(aclResourcePrefix + bucket + "/" + object)
+		}
+		add("s3:GetObject", resources)
+	}
+	return statements
+}
+
+// aclPolicyMessage container for the accesses mapped from the ACLs to the
+// anonymous policy of a bucket.
+type aclPolicyMessage struct {
+	Status  string   `json:"status"`
+	Target  string   `json:"target"`
+	List    bool     `json:"list"`
+	Objects []string `json:"objects,omitempty"`
+}
+
+func (a aclPolicyMessage) String() string {
+	var accesses []string
+	if a.List {
+		accesses = append(accesses, "listing")
+	}
+	if len(a.Objects) > 0 {
+		accesses = append(accesses, "reads of `"+strings.Join(a.Objects, "`, `")+"`")
+	}
+	return console.Colorize("ACLPolicy", fmt.Sprintf("Allowed the anonymous %s on `%s` from the ACLs.", strings.Join(accesses, " and "), a.Target))
+}
+
+func (a aclPolicyMessage) JSON() string {
+	a.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(a, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// aclUnmappedMessage container for a grant of an ACL which was not mapped
+// to a policy.
+type aclUnmappedMessage struct {
+	Status string           `json:"status"`
+	Source string           `json:"source"`
+	Grant  aclUnmappedGrant `json:"grant"`
+}
+
+func (a aclUnmappedMessage) String() string {
+	return console.Colorize("ACLUnmapped", fmt.Sprintf("Unable to map the grant of %s to `%s` on `%s`: %s.",
+		a.Grant.Permission, a.Grant.Grantee, a.Source, a.Grant.Reason))
+}
+
+func (a aclUnmappedMessage) JSON() string {
+	a.Status = "unmapped"
+	jsonMessageBytes, e := json.MarshalIndent(a, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
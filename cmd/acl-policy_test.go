@@ -0,0 +1,143 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestMapACL(t *testing.T) {
+	const owner = `<Owner><ID>75aa57f09aa0c8caeab4f8c24e99d10f8e7faeebf76c078efc7c6caea54ba06a</ID><DisplayName>owner</DisplayName></Owner>`
+	const ownerGrant = `<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>75aa57f09aa0c8caeab4f8c24e99d10f8e7faeebf76c078efc7c6caea54ba06a</ID><DisplayName>owner</DisplayName></Grantee><Permission>FULL_CONTROL</Permission></Grant>`
+	group := func(uri, permission string) string {
+		return `<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>` + uri + `</URI></Grantee><Permission>` + permission + `</Permission></Grant>`
+	}
+	testCases := []struct {
+		grants   string
+		public   bool
+		unmapped []aclUnmappedGrant
+	}{
+		{ownerGrant, false, nil},
+		{ownerGrant + group(aclAllUsers, "READ"), true, nil},
+		{
+			group(aclAllUsers, "FULL_CONTROL"), true,
+			[]aclUnmappedGrant{{"AllUsers", "FULL_CONTROL", "only the anonymous reads are mapped"}},
+		},
+		{
+			group(aclAllUsers, "WRITE") + group(aclAuthenticatedUsers, "READ"), false,
+			[]aclUnmappedGrant{
+				{"AllUsers", "WRITE", "anonymous writes and ACL accesses are not mapped"},
+				{"AuthenticatedUsers", "READ", "grants to all the AWS accounts have no equivalent"},
+			},
+		},
+		{
+			`<Grant><Grantee><EmailAddress>ops@example.com</EmailAddress></Grantee><Permission>READ</Permission></Grant>`, false,
+			[]aclUnmappedGrant{{"ops@example.com", "READ", "grants to other accounts are not mapped, use IAM policies"}},
+		},
+	}
+	for i, testCase := range testCases {
+		acl := &accessControlPolicy{}
+		data := `<AccessControlPolicy xmlns="http://s3.amazonaws.com/doc/2006-03-01/">` + owner + `<AccessControlList>` + testCase.grants + `</AccessControlList></AccessControlPolicy>`
+		if e := xml.Unmarshal([]byte(data), acl); e != nil {
+			t.Fatal(e)
+		}
+		public, unmapped := mapACL(acl)
+		if public != testCase.public {
+			t.Errorf("Test %d: expected public %v, got %v", i+1, testCase.public, public)
+		}
+		if !reflect.DeepEqual(unmapped, testCase.unmapped) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.unmapped, unmapped)
+		}
+	}
+}
+
+func TestACLPolicyResources(t *testing.T) {
+	testCases := []struct {
+		objects   map[string]bool
+		resources []string
+	}{
+		{map[string]bool{"a.txt": false}, []string{}},
+		{map[string]bool{"a.txt": true, "img/b.png": true}, []string{"a.txt", "img/*"}},
+		{
+			map[string]bool{"a.txt": false, "b.txt": true, "img/b.png": true, "img/logo/c.png": true},
+			[]string{"b.txt", "img/*"},
+		},
+		{
+			map[string]bool{"docs/a.pdf": false, "docs/pub/b.pdf": true, "docs/pub/c.pdf": true, "docs/d.pdf": true},
+			[]string{"docs/d.pdf", "docs/pub/*"},
+		},
+	}
+	for i, testCase := range testCases {
+		if resources := aclPolicyResources(testCase.objects); !reflect.DeepEqual(resources, testCase.resources) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.resources, resources)
+		}
+	}
+
+	statements := addACLPolicyStatements(nil, "site", true, []string{"img/*"})
+	if len(statements) != 2 || !statements[1].Resources.Contains("arn:aws:s3:::site/img/*") {
+		t.Fatalf("unexpected statements %+v", statements)
+	}
+	// The statements already in the policy are not added again.
+	if again := addACLPolicyStatements(statements, "site", true, []string{"img/*"}); len(again) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(again))
+	}
+}
+
+func TestACLPolicyBucketObjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		w.Write([]byte(`<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Name>site</Name><IsTruncated>false</IsTruncated>` +
+			`<Contents><Key>a.txt</Key><Size>1</Size><LastModified>2023-01-01T00:00:00.000Z</LastModified></Contents>` +
+			`<Contents><Key>img/b.png</Key><Size>1</Size><LastModified>2023-01-01T00:00:00.000Z</LastModified></Contents>` +
+			`<Contents><Key>img/private.png</Key><Size>1</Size><LastModified>2023-01-01T00:00:00.000Z</LastModified></Contents>` +
+			`</ListBucketResult>`))
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/site"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := S3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The objects of the bucket which were not copied keep their prefix private.
+	target := &aclPolicyTarget{url: conf.HostURL, bucket: "site", objects: map[string]bool{"a.txt": true, "img/b.png": true}}
+	objects, err := target.bucketObjects(context.Background(), clnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]bool{"a.txt": true, "img/b.png": true, "img/private.png": false}
+	if !reflect.DeepEqual(objects, expected) {
+		t.Fatalf("expected %v, got %v", expected, objects)
+	}
+	if resources := aclPolicyResources(objects); !reflect.DeepEqual(resources, []string{"a.txt", "img/b.png"}) {
+		t.Errorf("unexpected resources %v", resources)
+	}
+}
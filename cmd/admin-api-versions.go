@@ -0,0 +1,217 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/olekukonko/tablewriter"
+)
+
+var adminAPIVersionsCmd = cli.Command{
+	Name:         "api-versions",
+	Usage:        "display the admin and S3 API features supported by the server",
+	Action:       mainAdminAPIVersions,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS
+
+  Each feature is detected with a read-only request to the server, except checksums which are
+  detected from the release of the server. Nothing is changed on the server.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Display the features supported by the server at 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. Check from a script whether the server at 'myminio' supports batch jobs.
+     {{.Prompt}} {{.HelpName}} --json myminio | jq -e '.features[] | select(.name == "batch-jobs") | .supported'
+`,
+}
+
+// checksumsRelease is the first MinIO release supporting the additional
+// S3 checksums.
+var checksumsRelease = time.Date(2022, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+// apiFeature is a feature of the server and whether it is supported.
+type apiFeature struct {
+	Name      string `json:"name"`
+	Supported bool   `json:"supported"`
+	// Unknown is set when the support could not be detected.
+	Unknown bool   `json:"unknown,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+type apiVersionsMessage struct {
+	Status   string       `json:"status"`
+	Alias    string       `json:"alias"`
+	Version  string       `json:"version,omitempty"`
+	Backend  string       `json:"backend,omitempty"`
+	Features []apiFeature `json:"features"`
+}
+
+func (m apiVersionsMessage) String() string {
+	var s strings.Builder
+	s.WriteString(console.Colorize("Alias", m.Alias))
+	if m.Version != "" {
+		s.WriteString(" (release " + m.Version + ", " + m.Backend + ")")
+	}
+	s.WriteString("\n")
+
+	table := tablewriter.NewWriter(&s)
+	table.SetAutoWrapText(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+	for _, f := range m.Features {
+		status := console.Colorize("failCell", crossTickCell)
+		switch {
+		case f.Unknown:
+			status = console.Colorize("warnCell", "? ")
+		case f.Supported:
+			status = console.Colorize("passCell", tickCell)
+		}
+		table.Append([]string{"  " + status, f.Name, f.Detail})
+	}
+	table.Render()
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+func (m apiVersionsMessage) JSON() string {
+	m.Status = "success"
+	jsonBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonBytes)
+}
+
+// apiFeatureOf returns the feature detected with the error of a request
+// to the server: older servers reject unknown APIs as not implemented.
+func apiFeatureOf(name string, e error, detail string) apiFeature {
+	f := apiFeature{Name: name}
+	if e == nil {
+		f.Supported, f.Detail = true, detail
+		return f
+	}
+	code := madmin.ToErrorResponse(e).Code
+	switch {
+	case strings.Contains(code, "NotImplemented"), strings.Contains(code, "NotSupported"),
+		strings.HasPrefix(code, "404"), strings.HasPrefix(code, "405"), strings.HasPrefix(code, "501"),
+		strings.HasPrefix(e.Error(), "unknown sub-system"):
+		f.Detail = "not supported by the server"
+	case code == "AccessDenied":
+		f.Unknown, f.Detail = true, "access denied"
+	default:
+		f.Unknown, f.Detail = true, e.Error()
+	}
+	return f
+}
+
+// parseServerRelease parses the version reported by MinIO servers,
+// their release time.
+func parseServerRelease(version string) (time.Time, bool) {
+	version = strings.TrimPrefix(version, "RELEASE.")
+	if t, e := time.Parse(time.RFC3339, version); e == nil {
+		return t, true
+	}
+	// Release tags use dashes in the time of day.
+	if t, e := time.Parse("2006-01-02T15-04-05Z", version); e == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// discoverAPIFeatures probes the server for the features scripts commonly
+// depend on, version is the release of the server.
+func discoverAPIFeatures(ctx context.Context, client *madmin.AdminClient, version string) (features []apiFeature) {
+	_, e := client.ListBatchJobs(ctx, &madmin.ListBatchJobsFilter{})
+	features = append(features, apiFeatureOf("batch-jobs", e, "supported"))
+
+	tiers, e := client.ListTiers(ctx)
+	features = append(features, apiFeatureOf("tiering", e, fmt.Sprintf("%d tier(s) configured", len(tiers))))
+
+	sr, e := client.SiteReplicationInfo(ctx)
+	detail := "supported, not enabled"
+	if sr.Enabled {
+		detail = fmt.Sprintf("enabled with %d site(s)", len(sr.Sites))
+	}
+	features = append(features, apiFeatureOf("site-replication", e, detail))
+
+	_, e = client.HelpConfigKV(ctx, madmin.LambdaWebhookSubSys, "", false)
+	features = append(features, apiFeatureOf("object-lambda", e, "supported"))
+
+	checksums := apiFeature{Name: "checksums"}
+	if release, ok := parseServerRelease(version); ok {
+		checksums.Supported = !release.Before(checksumsRelease)
+		checksums.Detail = "detected from the server release"
+	} else {
+		checksums.Unknown, checksums.Detail = true, "unknown server release"
+	}
+	features = append(features, checksums)
+	return features
+}
+
+// mainAdminAPIVersions is the handle for "mc admin api-versions" command.
+func mainAdminAPIVersions(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	console.SetColor("Alias", color.New(color.FgCyan, color.Bold))
+	console.SetColor("passCell", color.New(color.FgGreen, color.Bold))
+	console.SetColor("failCell", color.New(color.FgRed, color.Bold))
+	console.SetColor("warnCell", color.New(color.FgYellow, color.Bold))
+
+	aliasedURL := ctx.Args().Get(0)
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	info, e := client.ServerInfo(globalContext)
+	fatalIf(probe.NewError(e), "Unable to get the server information.")
+
+	msg := apiVersionsMessage{Alias: aliasedURL}
+	for _, server := range info.Servers {
+		if server.Version != "" {
+			msg.Version = server.Version
+			break
+		}
+	}
+	msg.Features = discoverAPIFeatures(globalContext, client, msg.Version)
+	msg.Backend = string(info.Backend.Type)
+	printMsg(msg)
+	return nil
+}
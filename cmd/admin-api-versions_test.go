@@ -0,0 +1,59 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestAPIFeatureDetection(t *testing.T) {
+	for version, expected := range map[string]time.Time{
+		"2023-05-04T21:44:30Z":         time.Date(2023, 5, 4, 21, 44, 30, 0, time.UTC),
+		"RELEASE.2022-06-02T02-11-04Z": time.Date(2022, 6, 2, 2, 11, 4, 0, time.UTC),
+	} {
+		if got, ok := parseServerRelease(version); !ok || !got.Equal(expected) {
+			t.Errorf("%s: expected %s, got %s", version, expected, got)
+		}
+	}
+	if _, ok := parseServerRelease("DEVELOPMENT.GOGET"); ok {
+		t.Errorf("expected development versions to be unknown")
+	}
+
+	testCases := []struct {
+		e         error
+		supported bool
+		unknown   bool
+	}{
+		{nil, true, false},
+		{madmin.ErrorResponse{Code: "NotImplemented"}, false, false},
+		{madmin.ErrorResponse{Code: "404 Not Found"}, false, false},
+		{madmin.ErrorResponse{Code: "XMinioConfigError", Message: "unknown sub-system lambda_webhook"}, false, false},
+		{madmin.ErrorResponse{Code: "AccessDenied"}, false, true},
+		{errors.New("connection refused"), false, true},
+	}
+	for i, testCase := range testCases {
+		f := apiFeatureOf("feature", testCase.e, "")
+		if f.Supported != testCase.supported || f.Unknown != testCase.unknown {
+			t.Errorf("Test %d: expected supported %v unknown %v, got %+v", i+1, testCase.supported, testCase.unknown, f)
+		}
+	}
+}
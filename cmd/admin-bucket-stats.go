@@ -0,0 +1,300 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminBucketStatsFlags = []cli.Flag{
+	cli.DurationFlag{
+		Name:  "duration",
+		Usage: "sample the requests for this duration (e.g. `5m`)",
+		Value: time.Minute,
+	},
+}
+
+var adminBucketStatsCmd = cli.Command{
+	Name:         "stats",
+	Usage:        "display the request rates and errors of a bucket",
+	Action:       mainAdminBucketStats,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(adminBucketStatsFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [--duration DURATION] ALIAS/BUCKET
+
+  The S3 requests of the bucket are traced for --duration, one minute by default, and summarized
+  per API at the end: the request rate, the errors, the bytes received and sent, and the latency.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Display the request statistics of mybucket over 5 minutes.
+     {{.Prompt}} {{.HelpName}} --duration 5m myminio/mybucket
+`,
+}
+
+// bucketAPIStats are the statistics of the requests of an S3 API.
+type bucketAPIStats struct {
+	API        string        `json:"api"`
+	Count      int64         `json:"count"`
+	Rate       float64       `json:"rate"`
+	Errors4xx  int64         `json:"errors4xx"`
+	Errors5xx  int64         `json:"errors5xx"`
+	BytesIn    int64         `json:"bytesIn"`
+	BytesOut   int64         `json:"bytesOut"`
+	AvgLatency time.Duration `json:"avgLatency"`
+
+	totalLatency time.Duration
+}
+
+// bucketStatsMessage are the statistics of the requests of a bucket
+// sampled for a duration.
+type bucketStatsMessage struct {
+	Status        string           `json:"status"`
+	Bucket        string           `json:"bucket"`
+	Duration      time.Duration    `json:"duration"`
+	Count         int64            `json:"count"`
+	Rate          float64          `json:"rate"`
+	Errors4xx     int64            `json:"errors4xx"`
+	Errors5xx     int64            `json:"errors5xx"`
+	ErrorRate     float64          `json:"errorRate"`
+	BytesIn       int64            `json:"bytesIn"`
+	BytesOut      int64            `json:"bytesOut"`
+	MaxConcurrent int              `json:"maxConcurrent"`
+	APIs          []bucketAPIStats `json:"apis"`
+}
+
+func (m bucketStatsMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Requests of %s over %s\n", console.Colorize("Bucket", m.Bucket), m.Duration.Round(time.Second))
+	fmt.Fprintf(&b, "  Requests      : %d (%.2f/s)\n", m.Count, m.Rate)
+	fmt.Fprintf(&b, "  Errors        : %s (%.2f%%), %d 4xx, %d 5xx\n",
+		console.Colorize("ErrStatus", strconv.FormatInt(m.Errors4xx+m.Errors5xx, 10)), m.ErrorRate*100, m.Errors4xx, m.Errors5xx)
+	fmt.Fprintf(&b, "  Received      : %s\n", humanize.IBytes(uint64(m.BytesIn)))
+	fmt.Fprintf(&b, "  Sent          : %s\n", humanize.IBytes(uint64(m.BytesOut)))
+	fmt.Fprintf(&b, "  Max concurrent: %d\n", m.MaxConcurrent)
+	if len(m.APIs) == 0 {
+		return b.String()
+	}
+
+	table := newPrettyTable("  ",
+		Field{"FuncName", 32},
+		Field{"Count", 10},
+		Field{"Count", 10},
+		Field{"ErrStatus", 8},
+		Field{"ErrStatus", 8},
+		Field{"Stat", 10},
+		Field{"Stat", 10},
+		Field{"Stat", 10},
+	)
+	fmt.Fprintln(&b)
+	b.WriteString(table.buildRow("API", "Count", "Rate", "4xx", "5xx", "Received", "Sent", "Latency"))
+	for _, api := range m.APIs {
+		b.WriteString("\n")
+		b.WriteString(table.buildRow(api.API, strconv.FormatInt(api.Count, 10), fmt.Sprintf("%.2f/s", api.Rate),
+			strconv.FormatInt(api.Errors4xx, 10), strconv.FormatInt(api.Errors5xx, 10),
+			humanize.IBytes(uint64(api.BytesIn)), humanize.IBytes(uint64(api.BytesOut)),
+			api.AvgLatency.Round(time.Microsecond).String()))
+	}
+	return b.String()
+}
+
+func (m bucketStatsMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// bucketStats aggregates the traced S3 requests of a bucket.
+type bucketStats struct {
+	bucket    string
+	apis      map[string]*bucketAPIStats
+	intervals [][2]time.Time
+}
+
+func newBucketStats(bucket string) *bucketStats {
+	return &bucketStats{bucket: bucket, apis: map[string]*bucketAPIStats{}}
+}
+
+// add counts a traced request of the bucket, other calls are ignored.
+func (s *bucketStats) add(trace madmin.TraceInfo) {
+	if trace.TraceType != madmin.TraceS3 || trace.HTTP == nil {
+		return
+	}
+	if bucket, _, _ := strings.Cut(strings.TrimPrefix(trace.Path, "/"), "/"); bucket != s.bucket {
+		return
+	}
+	name := strings.TrimPrefix(trace.FuncName, "s3.")
+	api, ok := s.apis[name]
+	if !ok {
+		api = &bucketAPIStats{API: name}
+		s.apis[name] = api
+	}
+	api.Count++
+	switch status := trace.HTTP.RespInfo.StatusCode; {
+	case status >= 500:
+		api.Errors5xx++
+	case status >= 400:
+		api.Errors4xx++
+	}
+	api.BytesIn += int64(trace.HTTP.CallStats.InputBytes)
+	api.BytesOut += int64(trace.HTTP.CallStats.OutputBytes)
+	api.totalLatency += trace.Duration
+
+	start := trace.HTTP.ReqInfo.Time
+	if start.IsZero() {
+		start = trace.Time
+	}
+	s.intervals = append(s.intervals, [2]time.Time{start, start.Add(trace.Duration)})
+}
+
+// maxConcurrent returns the largest number of requests which were being
+// served at the same time.
+func (s *bucketStats) maxConcurrent() int {
+	type event struct {
+		t     time.Time
+		delta int
+	}
+	events := make([]event, 0, 2*len(s.intervals))
+	for _, interval := range s.intervals {
+		events = append(events, event{interval[0], 1}, event{interval[1], -1})
+	}
+	// Requests ending when others start don't overlap them.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].t.Equal(events[j].t) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].t.Before(events[j].t)
+	})
+	var current, max int
+	for _, e := range events {
+		current += e.delta
+		if current > max {
+			max = current
+		}
+	}
+	return max
+}
+
+// message returns the statistics of the requests over duration, the APIs
+// are sorted by decreasing number of requests.
+func (s *bucketStats) message(duration time.Duration) bucketStatsMessage {
+	m := bucketStatsMessage{
+		Bucket:        s.bucket,
+		Duration:      duration,
+		MaxConcurrent: s.maxConcurrent(),
+		APIs:          []bucketAPIStats{},
+	}
+	for _, api := range s.apis {
+		api.Rate = float64(api.Count) / duration.Seconds()
+		api.AvgLatency = api.totalLatency / time.Duration(api.Count)
+		m.Count += api.Count
+		m.Errors4xx += api.Errors4xx
+		m.Errors5xx += api.Errors5xx
+		m.BytesIn += api.BytesIn
+		m.BytesOut += api.BytesOut
+		m.APIs = append(m.APIs, *api)
+	}
+	sort.Slice(m.APIs, func(i, j int) bool {
+		if m.APIs[i].Count != m.APIs[j].Count {
+			return m.APIs[i].Count > m.APIs[j].Count
+		}
+		return m.APIs[i].API < m.APIs[j].API
+	})
+	m.Rate = float64(m.Count) / duration.Seconds()
+	if m.Count > 0 {
+		m.ErrorRate = float64(m.Errors4xx+m.Errors5xx) / float64(m.Count)
+	}
+	return m
+}
+
+// checkAdminBucketStatsSyntax - validate all the passed arguments
+func checkAdminBucketStatsSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if ctx.Duration("duration") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("duration")), "--duration requires a positive duration.")
+	}
+	_, bucket := url2Alias(ctx.Args().Get(0))
+	if bucket = strings.Trim(bucket, "/"); bucket == "" || strings.Contains(bucket, "/") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Please provide a bucket, ALIAS/BUCKET.")
+	}
+}
+
+// mainAdminBucketStats is the handler for "mc admin bucket stats" command.
+func mainAdminBucketStats(ctx *cli.Context) error {
+	checkAdminBucketStatsSyntax(ctx)
+
+	console.SetColor("Bucket", color.New(color.FgGreen, color.Bold))
+	console.SetColor("FuncName", color.New(color.Bold))
+	console.SetColor("Count", color.New(color.FgYellow))
+	console.SetColor("ErrStatus", color.New(color.FgRed))
+	console.SetColor("Stat", color.New(color.FgCyan))
+
+	aliasedURL := ctx.Args().Get(0)
+	_, bucket := url2Alias(aliasedURL)
+	bucket = strings.Trim(bucket, "/")
+
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize admin client.")
+
+	duration := ctx.Duration("duration")
+	ctxt, cancel := context.WithTimeout(globalContext, duration)
+	defer cancel()
+
+	stats := newBucketStats(bucket)
+	start := time.Now()
+	for traceInfo := range client.ServiceTrace(ctxt, madmin.ServiceTraceOpts{S3: true}) {
+		if traceInfo.Err != nil {
+			// The end of --duration.
+			if ctxt.Err() != nil {
+				break
+			}
+			fatalIf(probe.NewError(traceInfo.Err), "Unable to trace the requests of the bucket.")
+		}
+		stats.add(traceInfo.Trace)
+	}
+
+	// The trace ended before --duration.
+	if elapsed := time.Since(start); elapsed < duration {
+		duration = elapsed
+	}
+	printMsg(stats.message(duration))
+	return nil
+}
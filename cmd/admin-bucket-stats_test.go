@@ -0,0 +1,68 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestBucketStats(t *testing.T) {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	trace := func(funcName, path string, statusCode, in, out int, start, duration time.Duration) madmin.TraceInfo {
+		return madmin.TraceInfo{
+			TraceType: madmin.TraceS3,
+			FuncName:  funcName,
+			Path:      path,
+			Duration:  duration,
+			HTTP: &madmin.TraceHTTPStats{
+				ReqInfo:   madmin.TraceRequestInfo{Time: base.Add(start)},
+				RespInfo:  madmin.TraceResponseInfo{StatusCode: statusCode},
+				CallStats: madmin.TraceCallStats{InputBytes: in, OutputBytes: out},
+			},
+		}
+	}
+
+	stats := newBucketStats("photos")
+	stats.add(trace("s3.GetObject", "/photos/a.jpg", 200, 0, 100, 0, 3*time.Second))
+	stats.add(trace("s3.GetObject", "/photos/b.jpg", 404, 0, 10, time.Second, time.Second))
+	stats.add(trace("s3.PutObject", "/photos/c.jpg", 503, 50, 0, 2*time.Second, 2*time.Second))
+	// Starts when the first GetObject ends.
+	stats.add(trace("s3.PutObject", "/photos/d.jpg", 200, 30, 0, 3*time.Second, time.Second))
+	stats.add(trace("s3.GetObject", "/videos/a.mp4", 200, 0, 1000, 0, time.Second))
+	stats.add(madmin.TraceInfo{TraceType: madmin.TraceStorage, FuncName: "storage.ReadAll", Path: "/photos"})
+
+	m := stats.message(10 * time.Second)
+	if m.Count != 4 || m.Errors4xx != 1 || m.Errors5xx != 1 || m.BytesIn != 80 || m.BytesOut != 110 {
+		t.Fatalf("unexpected totals %+v", m)
+	}
+	if m.Rate != 0.4 || m.ErrorRate != 0.5 {
+		t.Fatalf("expected rate 0.4 and error rate 0.5, got %v and %v", m.Rate, m.ErrorRate)
+	}
+	if m.MaxConcurrent != 2 {
+		t.Fatalf("expected 2 concurrent requests, got %d", m.MaxConcurrent)
+	}
+	if len(m.APIs) != 2 || m.APIs[0].API != "GetObject" || m.APIs[1].API != "PutObject" {
+		t.Fatalf("unexpected APIs %+v", m.APIs)
+	}
+	if m.APIs[0].AvgLatency != 2*time.Second {
+		t.Fatalf("expected an average latency of 2s, got %s", m.APIs[0].AvgLatency)
+	}
+}
@@ -23,6 +23,7 @@ var adminBucketSubcommands = []cli.Command{
 	adminBucketRemoteCmd,
 	adminBucketQuotaCmd,
 	adminBucketInfoCmd,
+	adminBucketStatsCmd,
 }
 
 var adminBucketCmd = cli.Command{
@@ -32,6 +32,7 @@ import (
 	json "github.com/minio/colorjson"
 	"github.com/minio/madmin-go/v2"
 	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/set"
 	"github.com/minio/pkg/console"
 )
 
@@ -41,7 +42,7 @@ var adminClusterBucketImportCmd = cli.Command{
 	Action:          mainClusterBucketImport,
 	OnUsageError:    onUsageError,
 	Before:          setGlobalsFromContext,
-	Flags:           globalFlags,
+	Flags:           append([]cli.Flag{importConflictFlag}, globalFlags...),
 	HideHelpCommand: true,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
@@ -55,6 +56,12 @@ FLAGS:
 EXAMPLES:
   1. Recover bucket metadata for all buckets from previously saved bucket metadata backup.
      {{.Prompt}} {{.HelpName}} myminio /backups/myminio-bucket-metadata.zip
+
+  2. Restore bucket metadata into a non-empty cluster, leaving existing buckets untouched.
+     {{.Prompt}} {{.HelpName}} --on-conflict skip myminio /backups/myminio-bucket-metadata.zip
+
+  3. Restore only the bucket configurations missing on existing buckets.
+     {{.Prompt}} {{.HelpName}} --on-conflict merge myminio /backups/myminio-bucket-metadata.zip
 `,
 }
 
@@ -68,6 +75,9 @@ func checkBucketImportSyntax(ctx *cli.Context) {
 func mainClusterBucketImport(ctx *cli.Context) error {
 	// Check for command syntax
 	checkBucketImportSyntax(ctx)
+	strategy, e := parseImportConflictStrategy(ctx.String("on-conflict"))
+	fatalIf(probe.NewError(e), "Invalid --on-conflict flag:")
+	setImportReportColors()
 	console.SetColor("Name", color.New(color.Bold, color.FgCyan))
 	console.SetColor("success", color.New(color.Bold, color.FgGreen))
 	console.SetColor("warning", color.New(color.Bold, color.FgYellow))
@@ -91,12 +101,9 @@ func mainClusterBucketImport(ctx *cli.Context) error {
 	defer f.Close()
 	r = f
 
-	_, e = zip.NewReader(r.(io.ReaderAt), sz)
+	zr, e := zip.NewReader(r.(io.ReaderAt), sz)
 	fatalIf(probe.NewError(e).Trace(args...), fmt.Sprintf("Unable to read zip file %s", args.Get(1)))
 
-	f, e = os.Open(args.Get(1))
-	fatalIf(probe.NewError(e).Trace(args...), "Unable to get bucket metadata")
-
 	// Create a new MinIO Admin Client
 	client, err := newAdminClient(aliasedURL)
 	if err != nil {
@@ -109,7 +116,9 @@ func mainClusterBucketImport(ctx *cli.Context) error {
 	aliasedURL = filepath.Clean(aliasedURL)
 	_, bucket := url2Alias(aliasedURL)
 
-	rpt, e := client.ImportBucketMetadata(context.Background(), bucket, f)
+	report, content := prepareBucketMetaImport(client, zr, bucket, strategy)
+
+	rpt, e := client.ImportBucketMetadata(context.Background(), bucket, io.NopCloser(bytes.NewReader(content)))
 	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to import bucket metadata.")
 
 	printMsg(importMetaMsg{
@@ -118,10 +127,66 @@ func mainClusterBucketImport(ctx *cli.Context) error {
 		URL:                  aliasedURL,
 		Op:                   ctx.Command.Name,
 	})
+	printMsg(report)
 
 	return nil
 }
 
+// prepareBucketMetaImport - applies the conflict strategy to the bucket metadata
+// archive, returns the import report and the archive to be sent to the server.
+func prepareBucketMetaImport(client *madmin.AdminClient, zr *zip.Reader, bucket string, strategy importConflictStrategy) (importReportMessage, []byte) {
+	archive := make(map[string][]string)
+	for _, f := range zr.File {
+		b, cfg, ok := splitBucketMetaEntry(f.Name)
+		if !ok || (bucket != "" && b != bucket) {
+			continue
+		}
+		archive[b] = append(archive[b], cfg)
+	}
+
+	accInfo, e := client.AccountInfo(globalContext, madmin.AccountOpts{})
+	fatalIf(probe.NewError(e), "Unable to list buckets on the target.")
+
+	existing := make(map[string]set.StringSet)
+	for _, bi := range accInfo.Buckets {
+		if _, ok := archive[bi.Name]; !ok {
+			continue
+		}
+		existing[bi.Name] = set.NewStringSet()
+		if strategy != importConflictMerge {
+			continue
+		}
+		rc, e := client.ExportBucketMetadata(globalContext, bi.Name)
+		fatalIf(probe.NewError(e).Trace(bi.Name), "Unable to fetch current bucket metadata.")
+		data, e := io.ReadAll(rc)
+		rc.Close()
+		fatalIf(probe.NewError(e).Trace(bi.Name), "Unable to fetch current bucket metadata.")
+		tzr, e := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		fatalIf(probe.NewError(e).Trace(bi.Name), "Unable to read current bucket metadata.")
+		for _, f := range tzr.File {
+			if _, cfg, ok := splitBucketMetaEntry(f.Name); ok {
+				existing[bi.Name].Add(cfg)
+			}
+		}
+	}
+
+	entries := planBucketMetaImport(archive, existing, strategy)
+	imported := set.NewStringSet()
+	for _, entry := range entries {
+		for _, cfg := range entry.Imported {
+			imported.Add(entry.Name + "/" + cfg)
+		}
+	}
+
+	content, e := rewriteZip(zr, func(name string, data []byte) ([]byte, bool, error) {
+		b, cfg, ok := splitBucketMetaEntry(name)
+		return data, ok && imported.Contains(b+"/"+cfg), nil
+	})
+	fatalIf(probe.NewError(e), "Unable to prepare bucket metadata for import.")
+
+	return importReportMessage{Strategy: string(strategy), Entries: entries}, content
+}
+
 type importMetaMsg struct {
 	madmin.BucketMetaImportErrs
 	Op     string
@@ -18,7 +18,9 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -26,6 +28,7 @@ import (
 
 	"github.com/klauspost/compress/zip"
 	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
 	"github.com/kirolous/mc/pkg/probe"
 	"github.com/minio/pkg/console"
 )
@@ -36,7 +39,7 @@ var adminClusterIAMImportCmd = cli.Command{
 	Action:          mainClusterIAMImport,
 	OnUsageError:    onUsageError,
 	Before:          setGlobalsFromContext,
-	Flags:           globalFlags,
+	Flags:           append([]cli.Flag{importConflictFlag}, globalFlags...),
 	HideHelpCommand: true,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
@@ -51,6 +54,11 @@ EXAMPLES:
   1. Set IAM info from previously exported metadata zip file.
      {{.Prompt}} {{.HelpName}} myminio /tmp/myminio-iam-info.zip
 
+  2. Import IAM info into a non-empty cluster, keeping users, groups and policies already present.
+     {{.Prompt}} {{.HelpName}} --on-conflict skip myminio /tmp/myminio-iam-info.zip
+
+  3. Import IAM info, combining group members and policy mappings with the existing ones.
+     {{.Prompt}} {{.HelpName}} --on-conflict merge myminio /tmp/myminio-iam-info.zip
 `,
 }
 
@@ -64,6 +72,9 @@ func checkIAMImportSyntax(ctx *cli.Context) {
 func mainClusterIAMImport(ctx *cli.Context) error {
 	// Check for command syntax
 	checkIAMImportSyntax(ctx)
+	strategy, e := parseImportConflictStrategy(ctx.String("on-conflict"))
+	fatalIf(probe.NewError(e), "Invalid --on-conflict flag:")
+	setImportReportColors()
 
 	// Get the alias parameter from cli
 	args := ctx.Args()
@@ -82,12 +93,9 @@ func mainClusterIAMImport(ctx *cli.Context) error {
 	defer f.Close()
 	r = f
 
-	_, e = zip.NewReader(r.(io.ReaderAt), sz)
+	zr, e := zip.NewReader(r.(io.ReaderAt), sz)
 	fatalIf(probe.NewError(e).Trace(args...), fmt.Sprintf("Unable to read zip file %s", args.Get(1)))
 
-	f, e = os.Open(args.Get(1))
-	fatalIf(probe.NewError(e).Trace(args...), "Unable to get IAM info")
-
 	// Create a new MinIO Admin Client
 	client, err := newAdminClient(aliasedURL)
 	if err != nil {
@@ -95,11 +103,46 @@ func mainClusterIAMImport(ctx *cli.Context) error {
 		return nil
 	}
 
-	e = client.ImportIAM(context.Background(), f)
+	report, content := prepareIAMImport(client, zr, strategy)
+
+	e = client.ImportIAM(context.Background(), io.NopCloser(bytes.NewReader(content)))
 	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to import IAM info.")
 
 	if !globalJSON {
 		console.Infof("IAM info imported to %s from %s\n", aliasedURL, args.Get(1))
 	}
+	printMsg(report)
 	return nil
 }
+
+// prepareIAMImport - applies the conflict strategy to the IAM archive, returns
+// the import report and the archive to be sent to the server.
+func prepareIAMImport(client *madmin.AdminClient, zr *zip.Reader, strategy importConflictStrategy) (importReportMessage, []byte) {
+	imported, e := readIAMArchive(zr)
+	fatalIf(probe.NewError(e), "Unable to read IAM info.")
+
+	rc, e := client.ExportIAM(globalContext)
+	fatalIf(probe.NewError(e), "Unable to fetch current IAM info from the target.")
+	data, e := io.ReadAll(rc)
+	rc.Close()
+	fatalIf(probe.NewError(e), "Unable to fetch current IAM info from the target.")
+	tzr, e := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	fatalIf(probe.NewError(e), "Unable to read current IAM info from the target.")
+	target, e := readIAMArchive(tzr)
+	fatalIf(probe.NewError(e), "Unable to read current IAM info from the target.")
+
+	plan, entries, e := planIAMImport(imported, target, strategy)
+	fatalIf(probe.NewError(e), "Unable to prepare IAM info for import.")
+
+	content, e := rewriteZip(zr, func(name string, data []byte) ([]byte, bool, error) {
+		entities, ok := plan[iamEntityType(name)]
+		if !ok {
+			return data, true, nil
+		}
+		out, e := json.Marshal(entities)
+		return out, true, e
+	})
+	fatalIf(probe.NewError(e), "Unable to prepare IAM info for import.")
+
+	return importReportMessage{Strategy: string(strategy), Entries: entries}, content
+}
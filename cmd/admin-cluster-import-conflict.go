@@ -0,0 +1,352 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/klauspost/compress/zip"
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7/pkg/set"
+	"github.com/minio/pkg/console"
+)
+
+// importConflictStrategy decides what happens when an imported bucket
+// or IAM entity already exists on the target cluster.
+type importConflictStrategy string
+
+const (
+	importConflictSkip      importConflictStrategy = "skip"
+	importConflictOverwrite importConflictStrategy = "overwrite"
+	importConflictMerge     importConflictStrategy = "merge"
+)
+
+// import actions reported per bucket and IAM entity
+const (
+	importActionCreated     = "created"
+	importActionOverwritten = "overwritten"
+	importActionMerged      = "merged"
+	importActionSkipped     = "skipped"
+)
+
+var importConflictFlag = cli.StringFlag{
+	Name:  "on-conflict",
+	Value: string(importConflictOverwrite),
+	Usage: "action on entities already present on the target, one of 'skip', 'overwrite' or 'merge'",
+}
+
+func parseImportConflictStrategy(s string) (importConflictStrategy, error) {
+	switch strategy := importConflictStrategy(strings.ToLower(s)); strategy {
+	case importConflictSkip, importConflictOverwrite, importConflictMerge:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown conflict strategy '%s', valid values are 'skip', 'overwrite' and 'merge'", s)
+}
+
+// importReportEntry - outcome of the import of a single bucket or IAM entity
+type importReportEntry struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type,omitempty"`
+	Action   string   `json:"action"`
+	Imported []string `json:"imported,omitempty"`
+	Skipped  []string `json:"skipped,omitempty"`
+}
+
+// importReportMessage - container for the post-import report
+type importReportMessage struct {
+	Status   string              `json:"status"`
+	Strategy string              `json:"strategy"`
+	Entries  []importReportEntry `json:"entries"`
+}
+
+func (r importReportMessage) count(action string) (n int) {
+	for _, e := range r.Entries {
+		if e.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+func (r importReportMessage) String() string {
+	var b strings.Builder
+	for _, e := range r.Entries {
+		name := e.Name
+		if e.Type != "" {
+			name = e.Type + "/" + e.Name
+		}
+		fmt.Fprintf(&b, "%-12s %s", console.Colorize("importAction-"+e.Action, e.Action), name)
+		if len(e.Imported) > 0 {
+			fmt.Fprintf(&b, " (imported: %s)", strings.Join(e.Imported, ", "))
+		}
+		if len(e.Skipped) > 0 {
+			fmt.Fprintf(&b, " (kept: %s)", strings.Join(e.Skipped, ", "))
+		}
+		fmt.Fprintln(&b)
+	}
+	fmt.Fprintf(&b, "Summary (--on-conflict=%s): %d created, %d overwritten, %d merged, %d skipped",
+		r.Strategy,
+		r.count(importActionCreated), r.count(importActionOverwritten),
+		r.count(importActionMerged), r.count(importActionSkipped))
+	return b.String()
+}
+
+func (r importReportMessage) JSON() string {
+	r.Status = "success"
+	return toJSON(r)
+}
+
+// rewriteZip copies the entries of zr into a new zip archive. fn returns the
+// (possibly rewritten) content of an entry and whether it should be kept.
+func rewriteZip(zr *zip.Reader, fn func(name string, data []byte) ([]byte, bool, error)) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		data, e := readZipEntry(f)
+		if e != nil {
+			return nil, e
+		}
+		data, keep, e := fn(f.Name, data)
+		if e != nil {
+			return nil, e
+		}
+		if !keep {
+			continue
+		}
+		w, e := zw.Create(f.Name)
+		if e != nil {
+			return nil, e
+		}
+		if _, e = w.Write(data); e != nil {
+			return nil, e
+		}
+	}
+	if e := zw.Close(); e != nil {
+		return nil, e
+	}
+	return buf.Bytes(), nil
+}
+
+func readZipEntry(f *zip.File) ([]byte, error) {
+	rc, e := f.Open()
+	if e != nil {
+		return nil, e
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// splitBucketMetaEntry - bucket metadata archives store every
+// configuration as <bucket>/<config-file>.
+func splitBucketMetaEntry(name string) (bucket, cfg string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(name, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// planBucketMetaImport decides for each bucket configuration in the archive
+// whether it gets imported. existing maps the buckets present on the target
+// to the set of configurations they already have.
+func planBucketMetaImport(archive map[string][]string, existing map[string]set.StringSet, strategy importConflictStrategy) []importReportEntry {
+	buckets := make([]string, 0, len(archive))
+	for bucket := range archive {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+
+	entries := make([]importReportEntry, 0, len(buckets))
+	for _, bucket := range buckets {
+		cfgs := archive[bucket]
+		sort.Strings(cfgs)
+		entry := importReportEntry{Name: bucket}
+		targetCfgs, found := existing[bucket]
+		switch {
+		case !found:
+			entry.Action = importActionCreated
+			entry.Imported = cfgs
+		case strategy == importConflictOverwrite:
+			entry.Action = importActionOverwritten
+			entry.Imported = cfgs
+		case strategy == importConflictSkip:
+			entry.Action = importActionSkipped
+			entry.Skipped = cfgs
+		default:
+			for _, cfg := range cfgs {
+				if targetCfgs.Contains(cfg) {
+					entry.Skipped = append(entry.Skipped, cfg)
+				} else {
+					entry.Imported = append(entry.Imported, cfg)
+				}
+			}
+			entry.Action = importActionMerged
+			if len(entry.Imported) == 0 {
+				entry.Action = importActionSkipped
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// iamEntityType - the IAM entity type stored in an IAM export file
+// such as iam-assets/users.json
+func iamEntityType(name string) string {
+	return strings.TrimSuffix(path.Base(name), path.Ext(name))
+}
+
+// mergeIAMEntity merges an imported IAM entity into the one present on the target.
+// Group members and mapped policies are combined, any other entity is kept as is.
+func mergeIAMEntity(entityType string, target, imported json.RawMessage) (json.RawMessage, bool, error) {
+	var field, sep string
+	switch {
+	case entityType == "groups":
+		field = "members"
+	case strings.HasSuffix(entityType, "mappings"):
+		field, sep = "policy", ","
+	default:
+		return target, false, nil
+	}
+
+	var tm, im map[string]interface{}
+	if e := json.Unmarshal(target, &tm); e != nil {
+		return nil, false, e
+	}
+	if e := json.Unmarshal(imported, &im); e != nil {
+		return nil, false, e
+	}
+
+	values := func(v interface{}) []string {
+		var out []string
+		switch v := v.(type) {
+		case string:
+			for _, s := range strings.Split(v, sep) {
+				if s = strings.TrimSpace(s); s != "" {
+					out = append(out, s)
+				}
+			}
+		case []interface{}:
+			for _, s := range v {
+				if s, ok := s.(string); ok {
+					out = append(out, s)
+				}
+			}
+		}
+		return out
+	}
+
+	merged := set.CreateStringSet(values(tm[field])...)
+	for _, v := range values(im[field]) {
+		merged.Add(v)
+	}
+	if sep != "" {
+		tm[field] = strings.Join(merged.ToSlice(), sep)
+	} else {
+		tm[field] = merged.ToSlice()
+	}
+	out, e := json.Marshal(tm)
+	return out, true, e
+}
+
+// planIAMImport computes the IAM entities to import per entity type, given
+// the entities already present on the target.
+func planIAMImport(imported, target map[string]map[string]json.RawMessage, strategy importConflictStrategy) (map[string]map[string]json.RawMessage, []importReportEntry, error) {
+	types := make([]string, 0, len(imported))
+	for t := range imported {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	plan := make(map[string]map[string]json.RawMessage, len(imported))
+	var entries []importReportEntry
+	for _, t := range types {
+		names := make([]string, 0, len(imported[t]))
+		for name := range imported[t] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		plan[t] = make(map[string]json.RawMessage, len(names))
+		for _, name := range names {
+			entry := importReportEntry{Name: name, Type: t}
+			current, found := target[t][name]
+			switch {
+			case !found:
+				entry.Action = importActionCreated
+				plan[t][name] = imported[t][name]
+			case strategy == importConflictOverwrite:
+				entry.Action = importActionOverwritten
+				plan[t][name] = imported[t][name]
+			case strategy == importConflictSkip:
+				entry.Action = importActionSkipped
+			default:
+				merged, ok, e := mergeIAMEntity(t, current, imported[t][name])
+				if e != nil {
+					return nil, nil, fmt.Errorf("unable to merge %s/%s: %w", t, name, e)
+				}
+				entry.Action = importActionSkipped
+				if ok {
+					entry.Action = importActionMerged
+					plan[t][name] = merged
+				}
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return plan, entries, nil
+}
+
+// readIAMArchive returns the IAM entities of an IAM export archive per entity type.
+func readIAMArchive(zr *zip.Reader) (map[string]map[string]json.RawMessage, error) {
+	entities := make(map[string]map[string]json.RawMessage)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || path.Ext(f.Name) != ".json" {
+			continue
+		}
+		data, e := readZipEntry(f)
+		if e != nil {
+			return nil, e
+		}
+		m := make(map[string]json.RawMessage)
+		if len(bytes.TrimSpace(data)) > 0 {
+			if e = json.Unmarshal(data, &m); e != nil {
+				return nil, fmt.Errorf("unable to parse %s: %w", f.Name, e)
+			}
+		}
+		entities[iamEntityType(f.Name)] = m
+	}
+	return entities, nil
+}
+
+func setImportReportColors() {
+	console.SetColor("importAction-"+importActionCreated, color.New(color.FgGreen))
+	console.SetColor("importAction-"+importActionOverwritten, color.New(color.FgYellow))
+	console.SetColor("importAction-"+importActionMerged, color.New(color.FgCyan))
+	console.SetColor("importAction-"+importActionSkipped, color.New(color.FgHiBlack))
+}
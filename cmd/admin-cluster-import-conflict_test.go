@@ -0,0 +1,113 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/set"
+)
+
+func TestPlanBucketMetaImport(t *testing.T) {
+	archive := map[string][]string{
+		"newbucket": {"policy.json"},
+		"oldbucket": {"policy.json", "lifecycle.xml"},
+	}
+	existing := map[string]set.StringSet{
+		"oldbucket": set.CreateStringSet("policy.json"),
+	}
+
+	testCases := []struct {
+		strategy importConflictStrategy
+		expected []importReportEntry
+	}{
+		{importConflictOverwrite, []importReportEntry{
+			{Name: "newbucket", Action: importActionCreated, Imported: []string{"policy.json"}},
+			{Name: "oldbucket", Action: importActionOverwritten, Imported: []string{"lifecycle.xml", "policy.json"}},
+		}},
+		{importConflictSkip, []importReportEntry{
+			{Name: "newbucket", Action: importActionCreated, Imported: []string{"policy.json"}},
+			{Name: "oldbucket", Action: importActionSkipped, Skipped: []string{"lifecycle.xml", "policy.json"}},
+		}},
+		{importConflictMerge, []importReportEntry{
+			{Name: "newbucket", Action: importActionCreated, Imported: []string{"policy.json"}},
+			{Name: "oldbucket", Action: importActionMerged, Imported: []string{"lifecycle.xml"}, Skipped: []string{"policy.json"}},
+		}},
+	}
+	for i, testCase := range testCases {
+		entries := planBucketMetaImport(archive, existing, testCase.strategy)
+		if !reflect.DeepEqual(entries, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, entries)
+		}
+	}
+}
+
+func TestPlanIAMImportMerge(t *testing.T) {
+	imported := map[string]map[string]json.RawMessage{
+		"groups":        {"devs": json.RawMessage(`{"members":["bob"]}`)},
+		"policies":      {"readonly": json.RawMessage(`{"Version":"2012-10-17"}`)},
+		"user_mappings": {"bob": json.RawMessage(`{"policy":"readwrite"}`)},
+	}
+	target := map[string]map[string]json.RawMessage{
+		"groups":        {"devs": json.RawMessage(`{"members":["alice"]}`)},
+		"policies":      {"readonly": json.RawMessage(`{"Version":"2012-10-17"}`)},
+		"user_mappings": {"bob": json.RawMessage(`{"policy":"readonly"}`)},
+	}
+
+	plan, entries, e := planIAMImport(imported, target, importConflictMerge)
+	if e != nil {
+		t.Fatal(e)
+	}
+	actions := map[string]string{}
+	for _, entry := range entries {
+		actions[entry.Type+"/"+entry.Name] = entry.Action
+	}
+	expected := map[string]string{
+		"groups/devs":       importActionMerged,
+		"policies/readonly": importActionSkipped,
+		"user_mappings/bob": importActionMerged,
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Fatalf("expected %v, got %v", expected, actions)
+	}
+	if _, ok := plan["policies"]["readonly"]; ok {
+		t.Fatal("skipped policy must not be part of the import")
+	}
+
+	var group struct {
+		Members []string `json:"members"`
+	}
+	if e = json.Unmarshal(plan["groups"]["devs"], &group); e != nil {
+		t.Fatal(e)
+	}
+	if !set.CreateStringSet(group.Members...).Equals(set.CreateStringSet("alice", "bob")) {
+		t.Fatalf("unexpected merged members %v", group.Members)
+	}
+
+	var mapping struct {
+		Policy string `json:"policy"`
+	}
+	if e = json.Unmarshal(plan["user_mappings"]["bob"], &mapping); e != nil {
+		t.Fatal(e)
+	}
+	if mapping.Policy != "readonly,readwrite" {
+		t.Fatalf("unexpected merged policy %s", mapping.Policy)
+	}
+}
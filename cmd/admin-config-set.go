@@ -19,23 +19,34 @@ package cmd
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
 	"github.com/minio/madmin-go/v2"
 	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/set"
 	"github.com/minio/pkg/console"
 )
 
+var adminConfigSetFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "validate the keys and display the changes without applying them",
+	},
+}
+
 var adminConfigSetCmd = cli.Command{
 	Name:         "set",
 	Usage:        "interactively set a config key parameters",
 	Before:       setGlobalsFromContext,
 	Action:       mainAdminConfigSet,
 	OnUsageError: onUsageError,
-	Flags:        append(adminConfigEnvFlags, globalFlags...),
+	Flags:        append(append(adminConfigEnvFlags, adminConfigSetFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -54,6 +65,9 @@ EXAMPLES:
 
   3. Change healing settings on a distributed MinIO server setup.
      {{.Prompt}} {{.HelpName}} mydist/ heal max_delay=300ms max_io=50
+
+  4. Validate new scanner settings and display the changes, without applying them.
+     {{.Prompt}} {{.HelpName}} --dry-run myminio/ scanner speed=slow cycle=1m
 `,
 }
 
@@ -85,6 +99,176 @@ func (u configSetMessage) JSON() string {
 	return string(statusJSONBytes)
 }
 
+// configKeyChange is a key of a dry run of config set.
+type configKeyChange struct {
+	Key         string `json:"key"`
+	Current     string `json:"current"`
+	New         string `json:"new"`
+	EnvOverride string `json:"envOverride,omitempty"`
+}
+
+// configSetDryRunMessage displays the changes of config set without
+// applying them.
+type configSetDryRunMessage struct {
+	Status  string            `json:"status"`
+	SubSys  string            `json:"subSys"`
+	Target  string            `json:"target,omitempty"`
+	Changes []configKeyChange `json:"changes"`
+	Invalid []string          `json:"invalid,omitempty"`
+	Restart bool              `json:"restart"`
+}
+
+func (u configSetDryRunMessage) String() string {
+	var b strings.Builder
+	name := u.SubSys
+	if u.Target != "" {
+		name += madmin.SubSystemSeparator + u.Target
+	}
+	fmt.Fprintln(&b, console.Colorize("DryRun", "Dry run of the settings of "+name+", nothing was applied."))
+	for _, c := range u.Changes {
+		if c.Current == c.New {
+			fmt.Fprintf(&b, "  %s: %q (unchanged)\n", c.Key, c.New)
+		} else {
+			fmt.Fprintf(&b, "  %s: %q -> %s\n", c.Key, c.Current, console.Colorize("SetConfigSuccess", strconv.Quote(c.New)))
+		}
+		if c.EnvOverride != "" {
+			fmt.Fprintf(&b, "    %s\n", console.Colorize("Invalid", "overridden by the environment variable "+c.EnvOverride))
+		}
+	}
+	for _, invalid := range u.Invalid {
+		fmt.Fprintf(&b, "  %s\n", console.Colorize("Invalid", invalid))
+	}
+	if u.Restart {
+		fmt.Fprint(&b, "Applying the settings requires a restart of the server.")
+	} else {
+		fmt.Fprint(&b, "The settings would be applied without a restart.")
+	}
+	return b.String()
+}
+
+func (u configSetDryRunMessage) JSON() string {
+	u.Status = "success"
+	if len(u.Invalid) > 0 {
+		u.Status = "error"
+	}
+	statusJSONBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(statusJSONBytes)
+}
+
+// configDynamicSubSystems are the sub-systems which the server applies
+// without a restart.
+var configDynamicSubSystems = set.CreateStringSet(
+	madmin.APISubSys,
+	madmin.CompressionSubSys,
+	madmin.ScannerSubSys,
+	madmin.HealSubSys,
+	madmin.SubnetSubSys,
+	madmin.CallhomeSubSys,
+	"drive",
+	madmin.LoggerWebhookSubSys,
+	madmin.AuditWebhookSubSys,
+	madmin.AuditKafkaSubSys,
+	madmin.StorageClassSubSys,
+	madmin.CacheSubSys,
+)
+
+// validateConfigValue checks value against the type of a key in the help
+// of its sub-system. Empty values reset keys to their default.
+func validateConfigValue(value, valueType string) error {
+	if value == "" {
+		return nil
+	}
+	var e error
+	switch valueType {
+	case "on|off":
+		if value != madmin.EnableOn && value != madmin.EnableOff {
+			e = fmt.Errorf("expected on or off")
+		}
+	case "duration":
+		_, e = time.ParseDuration(value)
+	case "number":
+		_, e = strconv.ParseFloat(value, 64)
+	case "url":
+		var u *url.URL
+		if u, e = url.Parse(value); e == nil && (u.Scheme == "" || u.Host == "") {
+			e = fmt.Errorf("expected a URL with a scheme and a host")
+		}
+	}
+	return e
+}
+
+// configSetDryRun validates the keys of input against the help of their
+// sub-system and compares them with the current config of the server.
+func configSetDryRun(client *madmin.AdminClient, input string) (configSetDryRunMessage, *probe.Error) {
+	proposed, e := madmin.ParseServerConfigOutput(input)
+	if e != nil {
+		return configSetDryRunMessage{}, probe.NewError(e)
+	}
+	if len(proposed) != 1 {
+		return configSetDryRunMessage{}, errInvalidArgument().Trace(input)
+	}
+	cfg := proposed[0]
+	msg := configSetDryRunMessage{
+		SubSys:  cfg.SubSystem,
+		Target:  cfg.Target,
+		Restart: !configDynamicSubSystems.Contains(cfg.SubSystem),
+	}
+
+	help, e := client.HelpConfigKV(globalContext, cfg.SubSystem, "", false)
+	if e != nil {
+		return msg, probe.NewError(e)
+	}
+	types := make(map[string]string, len(help.KeysHelp))
+	for _, kh := range help.KeysHelp {
+		types[kh.Key] = kh.Type
+	}
+	if cfg.Target != "" && !help.MultipleTargets {
+		msg.Invalid = append(msg.Invalid, fmt.Sprintf("%s does not have targets", cfg.SubSystem))
+	}
+
+	// The config of the whole sub-system has all its targets, new targets
+	// don't have a current config.
+	buf, e := client.GetConfigKV(globalContext, cfg.SubSystem)
+	if e != nil {
+		return msg, probe.NewError(e)
+	}
+	current, e := madmin.ParseServerConfigOutput(string(buf))
+	if e != nil {
+		return msg, probe.NewError(e)
+	}
+	var currentCfg madmin.SubsysConfig
+	for _, c := range current {
+		if c.SubSystem == cfg.SubSystem && c.Target == cfg.Target {
+			currentCfg = c
+		}
+	}
+
+	for _, kv := range cfg.KV {
+		valueType, ok := types[kv.Key]
+		if !ok {
+			msg.Invalid = append(msg.Invalid, fmt.Sprintf("%s: unknown key of %s", kv.Key, cfg.SubSystem))
+			continue
+		}
+		if e := validateConfigValue(kv.Value, valueType); e != nil {
+			msg.Invalid = append(msg.Invalid, fmt.Sprintf("%s: invalid %s value %q: %v", kv.Key, valueType, kv.Value, e))
+		}
+		change := configKeyChange{Key: kv.Key, New: kv.Value}
+		for _, ckv := range currentCfg.KV {
+			if ckv.Key != kv.Key {
+				continue
+			}
+			change.Current = ckv.Value
+			if ckv.EnvOverride != nil {
+				change.EnvOverride = ckv.EnvOverride.Name
+			}
+		}
+		msg.Changes = append(msg.Changes, change)
+	}
+	return msg, nil
+}
+
 // checkAdminConfigSetSyntax - validate all the passed arguments
 func checkAdminConfigSetSyntax(ctx *cli.Context) {
 	if !ctx.Args().Present() && len(ctx.Args()) < 1 {
@@ -125,6 +309,18 @@ func mainAdminConfigSet(ctx *cli.Context) error {
 
 	}
 
+	if ctx.Bool("dry-run") {
+		console.SetColor("DryRun", color.New(color.Bold))
+		console.SetColor("Invalid", color.New(color.FgRed))
+		msg, err := configSetDryRun(client, input)
+		fatalIf(err, "Unable to validate '%s'", input)
+		printMsg(msg)
+		if len(msg.Invalid) > 0 {
+			return exitStatus(globalErrorExitStatus)
+		}
+		return nil
+	}
+
 	// Call set config API
 	restart, e := client.SetConfigKV(globalContext, input)
 	fatalIf(probe.NewError(e), "Unable to set '%s' to server", input)
@@ -0,0 +1,55 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestValidateConfigValue(t *testing.T) {
+	testCases := []struct {
+		value, valueType string
+		valid            bool
+	}{
+		{"", "duration", true},
+		{"on", "on|off", true},
+		{"yes", "on|off", false},
+		{"1m30s", "duration", true},
+		{"1 minute", "duration", false},
+		{"50", "number", true},
+		{"fifty", "number", false},
+		{"http://localhost:8080/minio/events", "url", true},
+		{"localhost:8080", "url", false},
+		{"anything goes", "sentence", true},
+	}
+	for i, testCase := range testCases {
+		if e := validateConfigValue(testCase.value, testCase.valueType); (e == nil) != testCase.valid {
+			t.Errorf("Test %d: %q as %s: expected valid %v, got %v", i+1, testCase.value, testCase.valueType, testCase.valid, e)
+		}
+	}
+
+	cfg, e := madmin.ParseServerConfigOutput(`notify_webhook:1 endpoint="http://localhost:8080" queue_limit=`)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(cfg) != 1 || cfg[0].Target != "1" || len(cfg[0].KV) != 2 || cfg[0].KV[0].Value != "http://localhost:8080" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminDriveFormatStatusCmd = cli.Command{
+	Name:         "format-status",
+	Usage:        "show the format status of the drives of every erasure set",
+	Action:       mainAdminDriveFormatStatus,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+  An erasure set is formatted when all its drives are formatted, drives replaced with new ones
+  are unformatted until they are healed.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the format status of the erasure sets of 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio/
+`,
+}
+
+// driveFormatStatusMessage is the format status of the drives of an erasure set.
+type driveFormatStatusMessage struct {
+	Status      string `json:"status"`
+	Pool        int    `json:"pool"`
+	Set         int    `json:"set"`
+	Drives      int    `json:"drives"`
+	Formatted   int    `json:"formatted"`
+	Unformatted int    `json:"unformatted"`
+	Offline     int    `json:"offline"`
+	Failed      int    `json:"failed"`
+}
+
+// driveFormatStatus counts the format status of the drives of every erasure
+// set, drives are expected in the order of their set.
+func driveFormatStatus(drives []madmin.Disk) (sets []driveFormatStatusMessage) {
+	for _, d := range drives {
+		if n := len(sets); n == 0 || sets[n-1].Pool != d.PoolIndex+1 || sets[n-1].Set != d.SetIndex+1 {
+			sets = append(sets, driveFormatStatusMessage{Pool: d.PoolIndex + 1, Set: d.SetIndex + 1})
+		}
+		s := &sets[len(sets)-1]
+		s.Drives++
+		switch d.State {
+		case madmin.DriveStateOk:
+			s.Formatted++
+		case madmin.DriveStateUnformatted:
+			s.Unformatted++
+		case madmin.DriveStateOffline:
+			s.Offline++
+		default:
+			s.Failed++
+		}
+	}
+	return sets
+}
+
+func driveFormatStatusTable(theme string) PrettyTable {
+	return newPrettyTable("  ",
+		Field{"", 9},
+		Field{theme, 12},
+		Field{"", 10},
+		Field{"", 12},
+		Field{"", 8},
+		Field{"", -1},
+	)
+}
+
+// Colorized message for console printing.
+func (s driveFormatStatusMessage) String() string {
+	state, theme := "formatted", "DriveOK"
+	if s.Formatted < s.Drives {
+		state, theme = "degraded", "DriveFail"
+	}
+	return driveFormatStatusTable(theme).buildRow(
+		fmt.Sprintf("%d/%d", s.Pool, s.Set), state,
+		fmt.Sprintf("%d/%d", s.Formatted, s.Drives),
+		fmt.Sprint(s.Unformatted), fmt.Sprint(s.Offline), fmt.Sprint(s.Failed))
+}
+
+// JSON'ified message for scripting.
+func (s driveFormatStatusMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainAdminDriveFormatStatus is the handle for "mc admin drive format-status" command.
+func mainAdminDriveFormatStatus(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setAdminDriveColors()
+
+	aliasedURL := ctx.Args().First()
+	drives, err := fetchAdminDrives(aliasedURL)
+	fatalIf(err, "Unable to get the drives of `"+aliasedURL+"`.")
+
+	if !globalJSON {
+		console.Println(console.Colorize("DriveHeader", driveFormatStatusTable("").buildRow(
+			"POOL/SET", "STATUS", "FORMATTED", "UNFORMATTED", "OFFLINE", "FAILED")))
+	}
+	for _, s := range driveFormatStatus(drives) {
+		printMsg(s)
+	}
+	return nil
+}
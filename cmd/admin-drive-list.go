@@ -0,0 +1,181 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminDriveListFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "unhealthy",
+		Usage: "only list the drives which are offline, healing or failed",
+	},
+}
+
+var adminDriveListCmd = cli.Command{
+	Name:         "list",
+	Usage:        "list the drives with their health and last error",
+	Action:       mainAdminDriveList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(adminDriveListFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+  The drives are identified by their model and the UUID of their format, the health is ok,
+  healing, offline or failed, with the state reported by the server as the last error.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List the drives of 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio/
+
+  2. List the drives of 'myminio' which need attention.
+     {{.Prompt}} {{.HelpName}} --unhealthy myminio/
+`,
+}
+
+// Health of a drive.
+const (
+	driveHealthOK      = "ok"
+	driveHealthHealing = "healing"
+	driveHealthOffline = "offline"
+	driveHealthFailed  = "failed"
+)
+
+// driveHealth returns the health of a drive from its state.
+func driveHealth(d madmin.Disk) string {
+	switch {
+	case d.State == madmin.DriveStateOffline:
+		return driveHealthOffline
+	case d.State != madmin.DriveStateOk:
+		return driveHealthFailed
+	case d.Healing:
+		return driveHealthHealing
+	}
+	return driveHealthOK
+}
+
+// driveListMessage is a drive of the cluster.
+type driveListMessage struct {
+	Status     string `json:"status"`
+	Endpoint   string `json:"endpoint"`
+	Pool       int    `json:"pool"`
+	Set        int    `json:"set"`
+	Index      int    `json:"index"`
+	Model      string `json:"model,omitempty"`
+	UUID       string `json:"uuid,omitempty"`
+	Health     string `json:"health"`
+	LastError  string `json:"lastError,omitempty"`
+	UsedSpace  uint64 `json:"usedSpace"`
+	TotalSpace uint64 `json:"totalSpace"`
+}
+
+func newDriveListMessage(d madmin.Disk) driveListMessage {
+	msg := driveListMessage{
+		Endpoint:   d.Endpoint,
+		Pool:       d.PoolIndex + 1,
+		Set:        d.SetIndex + 1,
+		Index:      d.DiskIndex + 1,
+		Model:      d.Model,
+		UUID:       d.UUID,
+		Health:     driveHealth(d),
+		UsedSpace:  d.UsedSpace,
+		TotalSpace: d.TotalSpace,
+	}
+	if d.State != madmin.DriveStateOk {
+		msg.LastError = d.State
+	}
+	return msg
+}
+
+func driveListTable(healthTheme string) PrettyTable {
+	return newPrettyTable("  ",
+		Field{"", 9},
+		Field{"", 40},
+		Field{"", 20},
+		Field{"", 36},
+		Field{healthTheme, 8},
+		Field{"", 20},
+		Field{"", -1},
+	)
+}
+
+// Colorized message for console printing.
+func (d driveListMessage) String() string {
+	healthTheme := "DriveOK"
+	switch d.Health {
+	case driveHealthHealing:
+		healthTheme = "DriveHealing"
+	case driveHealthOffline, driveHealthFailed:
+		healthTheme = "DriveFail"
+	}
+	usage := "-"
+	if d.TotalSpace > 0 {
+		usage = fmt.Sprintf("%s/%s", humanize.IBytes(d.UsedSpace), humanize.IBytes(d.TotalSpace))
+	}
+	return driveListTable(healthTheme).buildRow(
+		fmt.Sprintf("%d/%d/%d", d.Pool, d.Set, d.Index),
+		d.Endpoint, d.Model, d.UUID, d.Health, usage, d.LastError)
+}
+
+// JSON'ified message for scripting.
+func (d driveListMessage) JSON() string {
+	d.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainAdminDriveList is the handle for "mc admin drive list" command.
+func mainAdminDriveList(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setAdminDriveColors()
+
+	aliasedURL := ctx.Args().First()
+	drives, err := fetchAdminDrives(aliasedURL)
+	fatalIf(err, "Unable to get the drives of `"+aliasedURL+"`.")
+
+	if !globalJSON {
+		console.Println(console.Colorize("DriveHeader", driveListTable("").buildRow(
+			"POOL/SET", "ENDPOINT", "MODEL", "UUID", "HEALTH", "USED", "LAST ERROR")))
+	}
+	for _, d := range drives {
+		msg := newDriveListMessage(d)
+		if ctx.Bool("unhealthy") && msg.Health == driveHealthOK {
+			continue
+		}
+		printMsg(msg)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminDriveSubcommands = []cli.Command{
+	adminDriveListCmd,
+	adminDriveFormatStatusCmd,
+}
+
+var adminDriveCmd = cli.Command{
+	Name:            "drive",
+	Usage:           "list the drives of a MinIO cluster and their format status",
+	Action:          mainAdminDrive,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     adminDriveSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainAdminDrive is the handle for "mc admin drive" command.
+func mainAdminDrive(ctx *cli.Context) error {
+	commandNotFound(ctx, adminDriveSubcommands)
+	return nil
+}
+
+// fetchAdminDrives returns the drives of all the servers of an alias, in the
+// order of their pool, erasure set and index in the set.
+func fetchAdminDrives(aliasedURL string) ([]madmin.Disk, *probe.Error) {
+	client, err := newAdminClient(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	info, e := client.ServerInfo(globalContext)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(aliasedURL)
+	}
+	var drives []madmin.Disk
+	for _, srv := range info.Servers {
+		drives = append(drives, srv.Disks...)
+	}
+	sort.SliceStable(drives, func(i, j int) bool {
+		a, b := drives[i], drives[j]
+		if a.PoolIndex != b.PoolIndex {
+			return a.PoolIndex < b.PoolIndex
+		}
+		if a.SetIndex != b.SetIndex {
+			return a.SetIndex < b.SetIndex
+		}
+		return a.DiskIndex < b.DiskIndex
+	})
+	return drives, nil
+}
+
+func setAdminDriveColors() {
+	console.SetColor("DriveHeader", color.New(color.Bold, color.Underline))
+	console.SetColor("DriveOK", color.New(color.FgGreen))
+	console.SetColor("DriveHealing", color.New(color.FgYellow))
+	console.SetColor("DriveFail", color.New(color.FgRed, color.Bold))
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestDriveFormatStatus(t *testing.T) {
+	drives := []madmin.Disk{
+		{SetIndex: 0, State: madmin.DriveStateOk},
+		{SetIndex: 0, State: madmin.DriveStateOk, Healing: true},
+		{SetIndex: 1, State: madmin.DriveStateOk},
+		{SetIndex: 1, State: madmin.DriveStateUnformatted},
+		{SetIndex: 1, State: madmin.DriveStateOffline},
+		{PoolIndex: 1, SetIndex: 0, State: madmin.DriveStateFaulty},
+	}
+	want := []driveFormatStatusMessage{
+		{Pool: 1, Set: 1, Drives: 2, Formatted: 2},
+		{Pool: 1, Set: 2, Drives: 3, Formatted: 1, Unformatted: 1, Offline: 1},
+		{Pool: 2, Set: 1, Drives: 1, Failed: 1},
+	}
+	if got := driveFormatStatus(drives); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	for i, health := range []string{driveHealthOK, driveHealthHealing, driveHealthOK, driveHealthFailed, driveHealthOffline, driveHealthFailed} {
+		if got := driveHealth(drives[i]); got != health {
+			t.Errorf("drive %d: expected health %s, got %s", i, health, got)
+		}
+	}
+}
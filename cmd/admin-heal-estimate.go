@@ -0,0 +1,175 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// Failure types of the items found by a dry run heal.
+const (
+	healFailureMissingParts = "missingParts"
+	healFailureBitrot       = "bitrot"
+	healFailureMissingDrive = "missingDrive"
+)
+
+// healEstimate accumulates the items a dry run heal would heal.
+type healEstimate struct {
+	// endpointSets maps the endpoints of the drives to their erasure set.
+	endpointSets map[string]setIndex
+
+	objectsScanned, objectsToHeal int64
+	itemsToHeal, bytesToHeal      int64
+	byFailure                     map[string]int64
+	bySet                         map[setIndex]int64
+	unknownSet                    int64
+}
+
+func newHealEstimate(disks []madmin.Disk) *healEstimate {
+	e := &healEstimate{
+		endpointSets: make(map[string]setIndex, len(disks)),
+		byFailure:    make(map[string]int64),
+		bySet:        make(map[setIndex]int64),
+	}
+	for _, d := range disks {
+		e.endpointSets[d.Endpoint] = setIndex{pool: d.PoolIndex, set: d.SetIndex}
+	}
+	return e
+}
+
+// add counts an item if any of its drives is not ok before the heal.
+func (e *healEstimate) add(item madmin.HealResultItem) {
+	if item.Type == madmin.HealItemObject {
+		e.objectsScanned++
+	}
+	failures := map[string]bool{}
+	set, setFound := setIndex{}, false
+	for _, d := range item.Before.Drives {
+		switch d.State {
+		case madmin.DriveStateMissing:
+			failures[healFailureMissingParts] = true
+		case madmin.DriveStateCorrupt:
+			failures[healFailureBitrot] = true
+		case madmin.DriveStateOffline:
+			failures[healFailureMissingDrive] = true
+		}
+		if !setFound {
+			set, setFound = e.endpointSets[d.Endpoint]
+		}
+	}
+	if len(failures) == 0 {
+		return
+	}
+
+	e.itemsToHeal++
+	if item.Type == madmin.HealItemObject {
+		e.objectsToHeal++
+		if item.ObjectSize > 0 {
+			e.bytesToHeal += item.ObjectSize
+		}
+	}
+	for failure := range failures {
+		e.byFailure[failure]++
+	}
+	if setFound {
+		e.bySet[set]++
+	} else {
+		e.unknownSet++
+	}
+}
+
+// healEstimateSet is the number of items to heal in an erasure set.
+type healEstimateSet struct {
+	Pool  int   `json:"pool"`
+	Set   int   `json:"set"`
+	Items int64 `json:"items"`
+}
+
+// healEstimateMessage is the summary of a dry run heal.
+type healEstimateMessage struct {
+	Status         string            `json:"status"`
+	Type           string            `json:"type"`
+	ObjectsScanned int64             `json:"objectsScanned"`
+	ObjectsToHeal  int64             `json:"objectsToHeal"`
+	ItemsToHeal    int64             `json:"itemsToHeal"`
+	BytesToHeal    int64             `json:"bytesToHeal"`
+	ByFailure      map[string]int64  `json:"byFailure"`
+	Sets           []healEstimateSet `json:"sets,omitempty"`
+	UnknownSet     int64             `json:"unknownSet,omitempty"`
+}
+
+func (e *healEstimate) message() healEstimateMessage {
+	msg := healEstimateMessage{
+		Type:           "estimate",
+		ObjectsScanned: e.objectsScanned,
+		ObjectsToHeal:  e.objectsToHeal,
+		ItemsToHeal:    e.itemsToHeal,
+		BytesToHeal:    e.bytesToHeal,
+		ByFailure: map[string]int64{
+			healFailureMissingParts: e.byFailure[healFailureMissingParts],
+			healFailureBitrot:       e.byFailure[healFailureBitrot],
+			healFailureMissingDrive: e.byFailure[healFailureMissingDrive],
+		},
+		UnknownSet: e.unknownSet,
+	}
+	for set, items := range e.bySet {
+		msg.Sets = append(msg.Sets, healEstimateSet{Pool: set.pool + 1, Set: set.set + 1, Items: items})
+	}
+	sort.Slice(msg.Sets, func(i, j int) bool {
+		if msg.Sets[i].Pool != msg.Sets[j].Pool {
+			return msg.Sets[i].Pool < msg.Sets[j].Pool
+		}
+		return msg.Sets[i].Set < msg.Sets[j].Set
+	})
+	return msg
+}
+
+// Colorized message for console printing.
+func (m healEstimateMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, console.Colorize("HealBackgroundTitle", "Dry run, nothing was healed:"))
+	fmt.Fprintf(&b, "  %s/%s objects (%s) and %s items need healing\n",
+		humanize.Comma(m.ObjectsToHeal), humanize.Comma(m.ObjectsScanned),
+		humanize.IBytes(uint64(m.BytesToHeal)), humanize.Comma(m.ItemsToHeal))
+	fmt.Fprintf(&b, "  Missing parts : %s\n", humanize.Comma(m.ByFailure[healFailureMissingParts]))
+	fmt.Fprintf(&b, "  Bitrot        : %s\n", humanize.Comma(m.ByFailure[healFailureBitrot]))
+	fmt.Fprintf(&b, "  Missing drive : %s\n", humanize.Comma(m.ByFailure[healFailureMissingDrive]))
+	for _, s := range m.Sets {
+		fmt.Fprintf(&b, "  Pool %d, set %d : %s items\n", s.Pool, s.Set, humanize.Comma(s.Items))
+	}
+	if m.UnknownSet > 0 {
+		fmt.Fprintf(&b, "  Unknown set   : %s items\n", humanize.Comma(m.UnknownSet))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON'ified message for scripting.
+func (m healEstimateMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestHealEstimate(t *testing.T) {
+	e := newHealEstimate([]madmin.Disk{
+		{Endpoint: "http://server1/d1", PoolIndex: 0, SetIndex: 1},
+		{Endpoint: "http://server2/d1", PoolIndex: 0, SetIndex: 1},
+	})
+	item := func(size int64, states ...string) madmin.HealResultItem {
+		i := madmin.HealResultItem{Type: madmin.HealItemObject, ObjectSize: size}
+		for n, state := range states {
+			endpoint := "http://server1/d1"
+			if n > 0 {
+				endpoint = "http://server2/d1"
+			}
+			i.Before.Drives = append(i.Before.Drives, madmin.HealDriveInfo{Endpoint: endpoint, State: state})
+		}
+		return i
+	}
+	e.add(item(10, madmin.DriveStateOk, madmin.DriveStateOk))
+	e.add(item(20, madmin.DriveStateMissing, madmin.DriveStateCorrupt))
+	e.add(item(30, madmin.DriveStateOk, madmin.DriveStateOffline))
+	e.add(madmin.HealResultItem{Type: madmin.HealItemMetadata, Before: struct {
+		Drives []madmin.HealDriveInfo `json:"drives"`
+	}{Drives: []madmin.HealDriveInfo{{Endpoint: "http://server3/d1", State: madmin.DriveStateMissing}}}})
+
+	msg := e.message()
+	if msg.ObjectsScanned != 3 || msg.ObjectsToHeal != 2 || msg.ItemsToHeal != 3 || msg.BytesToHeal != 50 {
+		t.Fatalf("unexpected counts %+v", msg)
+	}
+	if msg.ByFailure[healFailureMissingParts] != 2 || msg.ByFailure[healFailureBitrot] != 1 || msg.ByFailure[healFailureMissingDrive] != 1 {
+		t.Fatalf("unexpected failures %v", msg.ByFailure)
+	}
+	if len(msg.Sets) != 1 || msg.Sets[0] != (healEstimateSet{Pool: 1, Set: 2, Items: 2}) || msg.UnknownSet != 1 {
+		t.Fatalf("unexpected sets %v, unknown %d", msg.Sets, msg.UnknownSet)
+	}
+}
@@ -94,6 +94,9 @@ type uiData struct {
 	// health color code.
 	HealthCols map[col]int64
 
+	// Estimate accumulates the items to heal of a dry run.
+	Estimate *healEstimate
+
 	// channel to receive a prompt string to indicate activity on
 	// the terminal
 	CurChan (<-chan string)
@@ -110,6 +113,9 @@ func (ui *uiData) updateStats(i madmin.HealResultItem) error {
 		ui.ObjectsScanned++
 	}
 	ui.ItemsScanned++
+	if ui.Estimate != nil {
+		ui.Estimate.add(i)
+	}
 
 	beforeUp, afterUp := i.GetOnlineCounts()
 	if afterUp > beforeUp {
@@ -51,7 +51,7 @@ var adminHealFlags = []cli.Flag{
 	},
 	cli.BoolFlag{
 		Name:  "dry-run, n",
-		Usage: "only inspect data, but do not mutate",
+		Usage: "only inspect data and estimate the objects to heal, but do not mutate",
 	},
 	cli.BoolFlag{
 		Name:  "force-start, f",
@@ -99,6 +99,9 @@ FLAGS:
 EXAMPLES:
   1. Monitor healing status on a running server at alias 'myminio':
      {{.Prompt}} {{.HelpName}} myminio/
+
+  2. Estimate the objects of 'mybucket' to heal, grouped by failure type and erasure set, without healing them:
+     {{.Prompt}} {{.HelpName}} --recursive --dry-run myminio/mybucket
 `,
 }
 
@@ -690,6 +693,16 @@ func mainAdminHeal(ctx *cli.Context) error {
 		HealthCols:            make(map[col]int64),
 		CurChan:               cursorAnimate(),
 	}
+	if opts.DryRun {
+		// Map the drives of the heal results to their erasure set.
+		info, e := adminClnt.ServerInfo(globalContext)
+		fatalIf(probe.NewError(e), "Unable to get the server information.")
+		var disks []madmin.Disk
+		for _, srv := range info.Servers {
+			disks = append(disks, srv.Disks...)
+		}
+		ui.Estimate = newHealEstimate(disks)
+	}
 
 	res, e := ui.DisplayAndFollowHealStatus(aliasedURL)
 	if e != nil {
@@ -701,5 +714,8 @@ func mainAdminHeal(ctx *cli.Context) error {
 			fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to display heal status.")
 		}
 	}
+	if ui.Estimate != nil {
+		printMsg(ui.Estimate.message())
+	}
 	return nil
 }
@@ -0,0 +1,293 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// adminFleetParallel is the number of aliases queried at the same time.
+const adminFleetParallel = 16
+
+var adminFleetFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "all-aliases",
+		Usage: "run against all the configured aliases",
+	},
+}
+
+// isAdminFleet tells whether the command runs against several aliases,
+// given as arguments, as an alias group or with --all-aliases.
+func isAdminFleet(ctx *cli.Context) bool {
+	if ctx.Bool("all-aliases") || len(ctx.Args()) > 1 {
+		return true
+	}
+	alias, _ := url2Alias(ctx.Args().First())
+	_, ok := getAliasGroup(alias)
+	return ok
+}
+
+// adminFleetAliases returns the aliases a fleet command runs against, in
+// the order of the arguments with the groups expanded, or all the aliases
+// sorted with --all-aliases.
+func adminFleetAliases(ctx *cli.Context) (aliases []string) {
+	if ctx.Bool("all-aliases") {
+		if len(ctx.Args()) > 0 {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--all-aliases cannot be used with targets.")
+		}
+		mcCfg, err := loadMcConfig()
+		fatalIf(err.Trace(), "Unable to load the mc configuration.")
+		for alias := range mcCfg.Aliases {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+		return aliases
+	}
+
+	seen := map[string]bool{}
+	for _, arg := range ctx.Args() {
+		alias, _ := url2Alias(arg)
+		members, ok := getAliasGroup(alias)
+		if !ok {
+			members = []string{alias}
+		}
+		for _, member := range members {
+			if !seen[member] {
+				seen[member] = true
+				aliases = append(aliases, member)
+			}
+		}
+	}
+	return aliases
+}
+
+// adminFleetInfo is the server information of an alias of the fleet.
+type adminFleetInfo struct {
+	alias string
+	info  madmin.InfoMessage
+	err   *probe.Error
+}
+
+// fetchAdminFleetInfo gets the server information of the aliases
+// concurrently, the results are in the order of the aliases.
+func fetchAdminFleetInfo(aliases []string) []adminFleetInfo {
+	results := make([]adminFleetInfo, len(aliases))
+	sem := make(chan struct{}, adminFleetParallel)
+	var wg sync.WaitGroup
+	for i, alias := range aliases {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, alias string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i].alias = alias
+			client, err := newAdminClient(alias)
+			if err != nil {
+				results[i].err = err.Trace(alias)
+				return
+			}
+			info, e := client.ServerInfo(globalContext)
+			results[i].info, results[i].err = info, probe.NewError(e).Trace(alias)
+		}(i, alias)
+	}
+	wg.Wait()
+	return results
+}
+
+// adminInfoFleetMessage is a row of the comparison table of the aliases.
+type adminInfoFleetMessage struct {
+	Status        string   `json:"status"`
+	Alias         string   `json:"alias"`
+	Error         string   `json:"error,omitempty"`
+	Health        string   `json:"health"`
+	Versions      []string `json:"versions,omitempty"`
+	Servers       int      `json:"servers"`
+	ServersOnline int      `json:"serversOnline"`
+	Drives        int      `json:"drives"`
+	DrivesOnline  int      `json:"drivesOnline"`
+	Healing       int      `json:"healing"`
+	RawUsed       uint64   `json:"rawUsed"`
+	RawTotal      uint64   `json:"rawTotal"`
+	Usage         uint64   `json:"usage"`
+	Buckets       uint64   `json:"buckets"`
+	Objects       uint64   `json:"objects"`
+}
+
+// Health of an alias of the fleet.
+const (
+	fleetHealthy     = "healthy"
+	fleetHealing     = "healing"
+	fleetDegraded    = "degraded"
+	fleetUnreachable = "unreachable"
+)
+
+// newAdminInfoFleetMessage summarizes the server information of an alias.
+func newAdminInfoFleetMessage(r adminFleetInfo) adminInfoFleetMessage {
+	msg := adminInfoFleetMessage{Alias: r.alias}
+	if r.err != nil {
+		msg.Health, msg.Error = fleetUnreachable, r.err.ToGoError().Error()
+		return msg
+	}
+
+	snapshot := newAdminInfoSnapshot(r.info, time.Now())
+	versions := map[string]bool{}
+	for _, version := range snapshot.Versions {
+		versions[version] = true
+	}
+	for version := range versions {
+		msg.Versions = append(msg.Versions, version)
+	}
+	sort.Strings(msg.Versions)
+	msg.Servers = len(snapshot.Servers)
+	for _, state := range snapshot.Servers {
+		if state == string(madmin.ItemOnline) {
+			msg.ServersOnline++
+		}
+	}
+	msg.Drives = len(snapshot.Drives)
+	for _, state := range snapshot.Drives {
+		if state == madmin.DriveStateOk {
+			msg.DrivesOnline++
+		}
+	}
+	msg.Healing = len(snapshot.Healing)
+	msg.RawUsed, msg.RawTotal = snapshot.RawUsed, snapshot.RawTotal
+	msg.Usage, msg.Buckets, msg.Objects = snapshot.Usage, snapshot.Buckets, snapshot.Objects
+
+	switch {
+	case msg.ServersOnline < msg.Servers || msg.DrivesOnline < msg.Drives:
+		msg.Health = fleetDegraded
+	case msg.Healing > 0:
+		msg.Health = fleetHealing
+	default:
+		msg.Health = fleetHealthy
+	}
+	return msg
+}
+
+func adminInfoFleetTable(healthTheme, versionTheme string) PrettyTable {
+	return newPrettyTable("  ",
+		Field{"FleetAlias", 16},
+		Field{healthTheme, 11},
+		Field{versionTheme, 30},
+		Field{"", 7},
+		Field{"", 9},
+		Field{"", 22},
+		Field{"", 10},
+		Field{"", -1},
+	)
+}
+
+// adminInfoFleetHeader is the header of the comparison table.
+func adminInfoFleetHeader() string {
+	return console.Colorize("FleetHeader", adminInfoFleetTable("", "").buildRow(
+		"ALIAS", "HEALTH", "VERSION", "SERVERS", "DRIVES", "RAW USED", "BUCKETS", "OBJECTS"))
+}
+
+// Colorized message for console printing.
+func (m adminInfoFleetMessage) String() string {
+	healthTheme := "FleetHealthy"
+	switch m.Health {
+	case fleetHealing:
+		healthTheme = "FleetHealing"
+	case fleetDegraded, fleetUnreachable:
+		healthTheme = "FleetFail"
+	}
+	if m.Error != "" {
+		return newPrettyTable("  ",
+			Field{"FleetAlias", 16},
+			Field{healthTheme, 11},
+			Field{"", -1},
+		).buildRow(m.Alias, m.Health, m.Error)
+	}
+	version, versionTheme := strings.Join(m.Versions, ","), ""
+	if len(m.Versions) > 1 {
+		version, versionTheme = fmt.Sprintf("mixed (%d)", len(m.Versions)), "FleetHealing"
+	}
+	return adminInfoFleetTable(healthTheme, versionTheme).buildRow(
+		m.Alias,
+		m.Health,
+		version,
+		fmt.Sprintf("%d/%d", m.ServersOnline, m.Servers),
+		fmt.Sprintf("%d/%d", m.DrivesOnline, m.Drives),
+		fmt.Sprintf("%s/%s", humanize.IBytes(m.RawUsed), humanize.IBytes(m.RawTotal)),
+		humanize.Comma(int64(m.Buckets)),
+		humanize.Comma(int64(m.Objects)),
+	)
+}
+
+// JSON'ified message for scripting.
+func (m adminInfoFleetMessage) JSON() string {
+	m.Status = "success"
+	if m.Error != "" {
+		m.Status = "error"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func setAdminFleetColors() {
+	console.SetColor("FleetHeader", color.New(color.Bold, color.Underline))
+	console.SetColor("FleetAlias", color.New(color.FgCyan, color.Bold))
+	console.SetColor("FleetHealthy", color.New(color.FgGreen))
+	console.SetColor("FleetHealing", color.New(color.FgYellow))
+	console.SetColor("FleetFail", color.New(color.FgRed, color.Bold))
+}
+
+// mainAdminInfoFleet prints the comparison table of the server information
+// of several aliases, and exits with an error status if any is unreachable.
+func mainAdminInfoFleet(ctx *cli.Context) {
+	if ctx.Bool("save") || ctx.IsSet("diff") {
+		fatalIf(errInvalidArgument(), "--save and --diff cannot be used with several aliases.")
+	}
+	aliases := adminFleetAliases(ctx)
+	if len(aliases) == 0 {
+		fatalIf(errDummy(), "No aliases are configured.")
+	}
+	setAdminFleetColors()
+
+	if !globalJSON {
+		console.Println(adminInfoFleetHeader())
+	}
+	var unreachable int
+	for _, r := range fetchAdminFleetInfo(aliases) {
+		msg := newAdminInfoFleetMessage(r)
+		if msg.Health == fleetUnreachable {
+			unreachable++
+		}
+		printMsg(msg)
+	}
+	if unreachable > 0 {
+		fatalIf(errDummy(), fmt.Sprintf("Unable to get the server information of %d of %d aliases.", unreachable, len(aliases)))
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestAdminInfoFleetMessage(t *testing.T) {
+	server := func(endpoint, state, version string, drives ...madmin.Disk) madmin.ServerProperties {
+		return madmin.ServerProperties{Endpoint: endpoint, State: state, Version: version, Disks: drives}
+	}
+	drive := func(endpoint, state string, healing bool) madmin.Disk {
+		return madmin.Disk{Endpoint: endpoint, State: state, Healing: healing, TotalSpace: 100, UsedSpace: 10}
+	}
+
+	testCases := []struct {
+		name     string
+		info     madmin.InfoMessage
+		err      *probe.Error
+		health   string
+		versions []string
+	}{
+		{
+			name: "healthy",
+			info: madmin.InfoMessage{Servers: []madmin.ServerProperties{
+				server("s1", "online", "v1", drive("s1/d1", madmin.DriveStateOk, false)),
+				server("s2", "online", "v1", drive("s2/d1", madmin.DriveStateOk, false)),
+			}},
+			health:   fleetHealthy,
+			versions: []string{"v1"},
+		},
+		{
+			name: "healing",
+			info: madmin.InfoMessage{Servers: []madmin.ServerProperties{
+				server("s1", "online", "v1", drive("s1/d1", madmin.DriveStateOk, true)),
+				server("s2", "online", "v2", drive("s2/d1", madmin.DriveStateOk, false)),
+			}},
+			health:   fleetHealing,
+			versions: []string{"v1", "v2"},
+		},
+		{
+			name: "degraded",
+			info: madmin.InfoMessage{Servers: []madmin.ServerProperties{
+				server("s1", "online", "v1", drive("s1/d1", madmin.DriveStateOk, true)),
+				server("s2", "offline", "", drive("s2/d1", "offline", false)),
+			}},
+			health:   fleetDegraded,
+			versions: []string{"v1"},
+		},
+		{
+			name:   "unreachable",
+			err:    probe.NewError(errors.New("connection refused")),
+			health: fleetUnreachable,
+		},
+	}
+	for _, tc := range testCases {
+		msg := newAdminInfoFleetMessage(adminFleetInfo{alias: tc.name, info: tc.info, err: tc.err})
+		if msg.Health != tc.health {
+			t.Errorf("%s: expected health %s, got %s", tc.name, tc.health, msg.Health)
+		}
+		if !reflect.DeepEqual(msg.Versions, tc.versions) {
+			t.Errorf("%s: expected versions %v, got %v", tc.name, tc.versions, msg.Versions)
+		}
+		if tc.err == nil && (msg.Servers != 2 || msg.Drives != 2 || msg.RawTotal != 200 || msg.RawUsed != 20) {
+			t.Errorf("%s: unexpected totals %+v", tc.name, msg)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// adminInfoHistoryDir is the directory of the snapshots saved by
+// 'mc admin info --save', one JSON lines file per alias.
+const adminInfoHistoryDir = "info-history"
+
+// adminInfoSnapshot is the state of a cluster at a point in time.
+type adminInfoSnapshot struct {
+	Time         time.Time         `json:"time"`
+	DeploymentID string            `json:"deploymentID,omitempty"`
+	Buckets      uint64            `json:"buckets"`
+	Objects      uint64            `json:"objects"`
+	Usage        uint64            `json:"usage"`
+	RawTotal     uint64            `json:"rawTotal"`
+	RawUsed      uint64            `json:"rawUsed"`
+	Servers      map[string]string `json:"servers"`  // endpoint to state
+	Versions     map[string]string `json:"versions"` // endpoint to version
+	Drives       map[string]string `json:"drives"`   // endpoint to state
+	Healing      []string          `json:"healing,omitempty"`
+}
+
+func newAdminInfoSnapshot(info madmin.InfoMessage, now time.Time) adminInfoSnapshot {
+	snapshot := adminInfoSnapshot{
+		Time:         now.UTC(),
+		DeploymentID: info.DeploymentID,
+		Buckets:      info.Buckets.Count,
+		Objects:      info.Objects.Count,
+		Usage:        info.Usage.Size,
+		Servers:      make(map[string]string),
+		Versions:     make(map[string]string),
+		Drives:       make(map[string]string),
+	}
+	for _, srv := range info.Servers {
+		snapshot.Servers[srv.Endpoint] = srv.State
+		if srv.Version != "" {
+			snapshot.Versions[srv.Endpoint] = srv.Version
+		}
+		for _, disk := range srv.Disks {
+			snapshot.RawTotal += disk.TotalSpace
+			snapshot.RawUsed += disk.UsedSpace
+			snapshot.Drives[disk.Endpoint] = disk.State
+			if disk.Healing {
+				snapshot.Healing = append(snapshot.Healing, disk.Endpoint)
+			}
+		}
+	}
+	sort.Strings(snapshot.Healing)
+	return snapshot
+}
+
+func getAdminInfoHistoryFile(alias string) string {
+	return filepath.Join(mustGetMcConfigDir(), adminInfoHistoryDir, alias+".jsonl")
+}
+
+// saveAdminInfoSnapshot appends a snapshot to the history of the alias.
+func saveAdminInfoSnapshot(alias string, snapshot adminInfoSnapshot) *probe.Error {
+	filename := getAdminInfoHistoryFile(alias)
+	if e := os.MkdirAll(filepath.Dir(filename), 0o700); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	data, e := json.Marshal(snapshot)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	f, e := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	if _, e = f.Write(append(data, '\n')); e != nil {
+		f.Close()
+		return probe.NewError(e).Trace(filename)
+	}
+	if e = f.Close(); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	return nil
+}
+
+// loadAdminInfoSnapshots returns the history of the alias, oldest first.
+func loadAdminInfoSnapshots(alias string) ([]adminInfoSnapshot, *probe.Error) {
+	filename := getAdminInfoHistoryFile(alias)
+	f, e := os.Open(filename)
+	if os.IsNotExist(e) {
+		return nil, nil
+	}
+	if e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	defer f.Close()
+
+	var snapshots []adminInfoSnapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for scanner.Scan() {
+		var snapshot adminInfoSnapshot
+		if e = json.Unmarshal(scanner.Bytes(), &snapshot); e != nil {
+			return nil, probe.NewError(e).Trace(filename)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if e = scanner.Err(); e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return snapshots, nil
+}
+
+// adminInfoBaseline returns the latest snapshot taken at or before since,
+// or the oldest one if the history is shorter than the period.
+func adminInfoBaseline(snapshots []adminInfoSnapshot, since time.Time) (adminInfoSnapshot, bool) {
+	if len(snapshots) == 0 {
+		return adminInfoSnapshot{}, false
+	}
+	baseline := snapshots[0]
+	for _, snapshot := range snapshots {
+		if snapshot.Time.After(since) {
+			break
+		}
+		baseline = snapshot
+	}
+	return baseline, true
+}
+
+// adminInfoUpgrade is a version change of a server.
+type adminInfoUpgrade struct {
+	Server string `json:"server"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// adminInfoDiffMessage container for the changes of a cluster over a period.
+type adminInfoDiffMessage struct {
+	Status string    `json:"status"`
+	Alias  string    `json:"alias"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+
+	BucketsDelta  int64  `json:"bucketsDelta"`
+	ObjectsDelta  int64  `json:"objectsDelta"`
+	UsageDelta    int64  `json:"usageDelta"`
+	RawTotalDelta int64  `json:"rawTotalDelta"`
+	RawUsedDelta  int64  `json:"rawUsedDelta"`
+	Usage         uint64 `json:"usage"`
+
+	DrivesOffline  []string           `json:"drivesOffline,omitempty"`
+	DrivesOnline   []string           `json:"drivesOnline,omitempty"`
+	DrivesAdded    []string           `json:"drivesAdded,omitempty"`
+	DrivesRemoved  []string           `json:"drivesRemoved,omitempty"`
+	ServersOffline []string           `json:"serversOffline,omitempty"`
+	ServersOnline  []string           `json:"serversOnline,omitempty"`
+	Upgrades       []adminInfoUpgrade `json:"upgrades,omitempty"`
+	HealingBefore  int                `json:"healingBefore"`
+	HealingNow     int                `json:"healingNow"`
+}
+
+// diffStates compares the states of drives or servers.
+func diffStates(before, now map[string]string) (offline, online, added, removed []string) {
+	// Servers are "online" and drives "ok" when available.
+	isUp := func(state string) bool {
+		return state == string(madmin.ItemOnline) || state == madmin.DriveStateOk
+	}
+	for endpoint, state := range now {
+		old, ok := before[endpoint]
+		switch {
+		case !ok:
+			added = append(added, endpoint)
+		case isUp(old) && !isUp(state):
+			offline = append(offline, endpoint)
+		case !isUp(old) && isUp(state):
+			online = append(online, endpoint)
+		}
+	}
+	for endpoint := range before {
+		if _, ok := now[endpoint]; !ok {
+			removed = append(removed, endpoint)
+		}
+	}
+	for _, s := range [][]string{offline, online, added, removed} {
+		sort.Strings(s)
+	}
+	return offline, online, added, removed
+}
+
+func diffAdminInfoSnapshots(alias string, before, now adminInfoSnapshot) adminInfoDiffMessage {
+	msg := adminInfoDiffMessage{
+		Alias:         alias,
+		From:          before.Time,
+		To:            now.Time,
+		BucketsDelta:  int64(now.Buckets) - int64(before.Buckets),
+		ObjectsDelta:  int64(now.Objects) - int64(before.Objects),
+		UsageDelta:    int64(now.Usage) - int64(before.Usage),
+		RawTotalDelta: int64(now.RawTotal) - int64(before.RawTotal),
+		RawUsedDelta:  int64(now.RawUsed) - int64(before.RawUsed),
+		Usage:         now.Usage,
+		HealingBefore: len(before.Healing),
+		HealingNow:    len(now.Healing),
+	}
+	msg.DrivesOffline, msg.DrivesOnline, msg.DrivesAdded, msg.DrivesRemoved = diffStates(before.Drives, now.Drives)
+	msg.ServersOffline, msg.ServersOnline, _, _ = diffStates(before.Servers, now.Servers)
+	for server, version := range now.Versions {
+		if old, ok := before.Versions[server]; ok && old != version {
+			msg.Upgrades = append(msg.Upgrades, adminInfoUpgrade{Server: server, From: old, To: version})
+		}
+	}
+	sort.Slice(msg.Upgrades, func(i, j int) bool { return msg.Upgrades[i].Server < msg.Upgrades[j].Server })
+	return msg
+}
+
+// signedIBytes formats a size delta with its sign.
+func signedIBytes(delta int64) string {
+	if delta < 0 {
+		return "-" + humanize.IBytes(uint64(-delta))
+	}
+	return "+" + humanize.IBytes(uint64(delta))
+}
+
+func (d adminInfoDiffMessage) JSON() string {
+	d.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (d adminInfoDiffMessage) String() string {
+	console.SetColor("Info", color.New(color.FgGreen, color.Bold))
+	console.SetColor("InfoFail", color.New(color.FgRed, color.Bold))
+	console.SetColor("InfoWarning", color.New(color.FgYellow, color.Bold))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Changes of %s since %s (%s ago):\n", console.Colorize("Info", d.Alias),
+		d.From.Local().Format(printDate), d.To.Sub(d.From).Round(time.Minute))
+	fmt.Fprintf(&b, "  Usage    : %s (%s now)\n", signedIBytes(d.UsageDelta), humanize.IBytes(d.Usage))
+	fmt.Fprintf(&b, "  Raw      : %s used, %s total\n", signedIBytes(d.RawUsedDelta), signedIBytes(d.RawTotalDelta))
+	fmt.Fprintf(&b, "  Objects  : %+d\n", d.ObjectsDelta)
+	fmt.Fprintf(&b, "  Buckets  : %+d\n", d.BucketsDelta)
+
+	list := func(title, color string, endpoints []string) {
+		for _, endpoint := range endpoints {
+			fmt.Fprintf(&b, "  %s: %s\n", console.Colorize(color, title), endpoint)
+		}
+	}
+	list("Server offline", "InfoFail", d.ServersOffline)
+	list("Server online ", "Info", d.ServersOnline)
+	list("Drive offline ", "InfoFail", d.DrivesOffline)
+	list("Drive online  ", "Info", d.DrivesOnline)
+	list("Drive added   ", "Info", d.DrivesAdded)
+	list("Drive removed ", "InfoWarning", d.DrivesRemoved)
+	for _, upgrade := range d.Upgrades {
+		fmt.Fprintf(&b, "  %s: %s %s -> %s\n", console.Colorize("Info", "Upgraded      "), upgrade.Server, upgrade.From, upgrade.To)
+	}
+	if d.HealingBefore != d.HealingNow {
+		fmt.Fprintf(&b, "  Healing  : %d -> %d drive(s)\n", d.HealingBefore, d.HealingNow)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
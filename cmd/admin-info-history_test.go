@@ -0,0 +1,71 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestDiffAdminInfoSnapshots(t *testing.T) {
+	day := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	info := madmin.InfoMessage{
+		Objects: madmin.Objects{Count: 10},
+		Usage:   madmin.Usage{Size: 1 << 30},
+		Servers: []madmin.ServerProperties{{
+			Endpoint: "node1:9000",
+			State:    "online",
+			Version:  "2023-02-01T00-00-00Z",
+			Disks: []madmin.Disk{
+				{Endpoint: "node1:9000/data1", State: "ok", TotalSpace: 100, UsedSpace: 10},
+				{Endpoint: "node1:9000/data2", State: "ok", TotalSpace: 100, UsedSpace: 10},
+			},
+		}},
+	}
+	before := newAdminInfoSnapshot(info, day)
+
+	info.Objects.Count = 25
+	info.Servers[0].Version = "2023-03-01T00-00-00Z"
+	info.Servers[0].Disks[1].State = "offline"
+	info.Servers[0].Disks[0].Healing = true
+	now := newAdminInfoSnapshot(info, day.Add(24*time.Hour))
+
+	d := diffAdminInfoSnapshots("myminio", before, now)
+	if d.ObjectsDelta != 15 || d.UsageDelta != 0 || d.RawTotalDelta != 0 {
+		t.Errorf("unexpected deltas %+v", d)
+	}
+	if !reflect.DeepEqual(d.DrivesOffline, []string{"node1:9000/data2"}) {
+		t.Errorf("expected data2 offline, got %v", d.DrivesOffline)
+	}
+	if len(d.Upgrades) != 1 || d.Upgrades[0].To != "2023-03-01T00-00-00Z" {
+		t.Errorf("expected an upgrade, got %v", d.Upgrades)
+	}
+	if d.HealingBefore != 0 || d.HealingNow != 1 {
+		t.Errorf("expected 1 healing drive, got %d", d.HealingNow)
+	}
+
+	snapshots := []adminInfoSnapshot{before, now}
+	if baseline, _ := adminInfoBaseline(snapshots, day.Add(12*time.Hour)); !baseline.Time.Equal(day) {
+		t.Errorf("expected the first snapshot, got %v", baseline.Time)
+	}
+	if baseline, _ := adminInfoBaseline(snapshots, day.Add(-time.Hour)); !baseline.Time.Equal(day) {
+		t.Errorf("expected the oldest snapshot, got %v", baseline.Time)
+	}
+}
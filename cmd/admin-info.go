@@ -36,18 +36,32 @@ import (
 	"github.com/minio/pkg/console"
 )
 
+var adminInfoFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "save",
+		Usage: "append a snapshot of the capacity, drives, versions and healing state to the local history",
+	},
+	cli.StringFlag{
+		Name:  "diff",
+		Usage: "show what changed since the snapshot saved the given duration ago, e.g. 1d",
+	},
+}
+
 var adminInfoCmd = cli.Command{
 	Name:         "info",
 	Usage:        "display MinIO server information",
 	Action:       mainAdminInfo,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
+	Flags:        append(append(adminInfoFlags, adminFleetFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} TARGET
+  {{.HelpName}} TARGET [TARGET...]
+
+  With several targets, an alias group or --all-aliases, the aliases are queried concurrently
+  and compared in a table of their health, versions, servers, drives and capacity.
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -55,6 +69,18 @@ FLAGS:
 EXAMPLES:
   1. Get server information of the 'play' MinIO server.
      {{.Prompt}} {{.HelpName}} play/
+
+  2. Save a snapshot of the 'play' MinIO server to the history, e.g. from a cron job.
+     {{.Prompt}} {{.HelpName}} --save play/
+
+  3. Show what changed on the 'play' MinIO server over the last day.
+     {{.Prompt}} {{.HelpName}} --diff 1d play/
+
+  4. Compare the clusters of the alias group 'prod' and the alias 'dr'.
+     {{.Prompt}} {{.HelpName}} prod dr
+
+  5. Compare all the configured aliases.
+     {{.Prompt}} {{.HelpName}} --all-aliases
 `,
 }
 
@@ -314,13 +340,22 @@ func (u clusterStruct) JSON() string {
 
 // checkAdminInfoSyntax - validate arguments passed by a user
 func checkAdminInfoSyntax(ctx *cli.Context) {
-	if len(ctx.Args()) == 0 || len(ctx.Args()) > 1 {
+	if len(ctx.Args()) == 0 && !ctx.Bool("all-aliases") {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
+	if diff := ctx.String("diff"); diff != "" {
+		if _, e := ParseDuration(diff); e != nil {
+			fatalIf(probe.NewError(e).Trace(diff), "Unable to parse --diff.")
+		}
+	}
 }
 
 func mainAdminInfo(ctx *cli.Context) error {
 	checkAdminInfoSyntax(ctx)
+	if isAdminFleet(ctx) {
+		mainAdminInfoFleet(ctx)
+		return nil
+	}
 
 	// Get the alias parameter from cli
 	args := ctx.Args()
@@ -341,6 +376,29 @@ func mainAdminInfo(ctx *cli.Context) error {
 		clusterInfo.Error = ""
 	}
 	clusterInfo.Info = admInfo
+
+	if ctx.Bool("save") || ctx.IsSet("diff") {
+		fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to get server information.")
+
+		alias, _ := url2Alias(aliasedURL)
+		snapshot := newAdminInfoSnapshot(admInfo, time.Now())
+		if ctx.IsSet("diff") {
+			period, _ := ParseDuration(ctx.String("diff"))
+			snapshots, err := loadAdminInfoSnapshots(alias)
+			fatalIf(err, "Unable to load the server information history.")
+			baseline, ok := adminInfoBaseline(snapshots, snapshot.Time.Add(-time.Duration(period)))
+			if !ok {
+				fatalIf(errDummy().Trace(alias), "No server information history, save snapshots with 'mc admin info --save' first.")
+			}
+			printMsg(diffAdminInfoSnapshots(alias, baseline, snapshot))
+		}
+		if ctx.Bool("save") {
+			fatalIf(saveAdminInfoSnapshot(alias, snapshot), "Unable to save the server information snapshot.")
+		}
+		if ctx.IsSet("diff") {
+			return nil
+		}
+	}
 	printMsg(clusterInfo)
 
 	return nil
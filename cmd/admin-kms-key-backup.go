@@ -0,0 +1,155 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+var adminKMSBackupKeysFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "pattern",
+		Usage: "back up only the master keys matching the pattern",
+		Value: "*",
+	},
+}
+
+var adminKMSBackupKeysCmd = cli.Command{
+	Name:         "backup",
+	Usage:        "save the inventory of the master keys of the KMS to a file",
+	Action:       mainAdminKMSBackupKeys,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(adminKMSBackupKeysFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET FILE
+
+  The KMS never releases key material: the backup records the name, creator,
+  creation time and health of every master key, so that an escrow of the keys
+  can be checked against it and restored with 'mc admin kms key import'.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Save the inventory of all the master keys to keys.json.
+     $ {{.HelpName}} play keys.json
+  2. Save the inventory of the master keys whose name starts with 'tenant-'.
+     $ {{.HelpName}} --pattern 'tenant-*' play tenant-keys.json
+`,
+}
+
+// kmsKeyBackup is the content of a backup of the master keys.
+type kmsKeyBackup struct {
+	KMS        string             `json:"kms"`
+	DefaultKey string             `json:"defaultKey"`
+	Time       time.Time          `json:"time"`
+	Keys       []kmsKeyBackupInfo `json:"keys"`
+}
+
+type kmsKeyBackupInfo struct {
+	Name       string `json:"name"`
+	CreatedAt  string `json:"createdAt,omitempty"`
+	CreatedBy  string `json:"createdBy,omitempty"`
+	Encryption bool   `json:"encryption"`
+	Decryption bool   `json:"decryption"`
+}
+
+type kmsKeyBackupMsg struct {
+	Status string `json:"status"`
+	File   string `json:"file"`
+	Keys   int    `json:"keys"`
+}
+
+func (k kmsKeyBackupMsg) JSON() string {
+	k.Status = "success"
+	kmsBytes, e := json.MarshalIndent(k, "", "    ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(kmsBytes)
+}
+
+func (k kmsKeyBackupMsg) String() string {
+	return fmt.Sprintf("Saved the inventory of %d master key(s) to `%s`.", k.Keys, k.File)
+}
+
+// mainAdminKMSBackupKeys is the handler for the "mc admin kms key backup" command.
+func mainAdminKMSBackupKeys(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	client, err := newAdminClient(ctx.Args().Get(0))
+	fatalIf(err, "Unable to get a configured admin connection.")
+
+	backup, err := kmsKeyInventory(globalContext, client, ctx.String("pattern"))
+	fatalIf(err, "Unable to back up the master keys")
+
+	data, e := json.MarshalIndent(backup, "", "  ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	file := ctx.Args().Get(1)
+	e = os.WriteFile(file, append(data, '\n'), 0o600)
+	fatalIf(probe.NewError(e).Trace(file), "Unable to save the backup")
+
+	printMsg(kmsKeyBackupMsg{File: file, Keys: len(backup.Keys)})
+	return nil
+}
+
+// kmsKeyInventory returns the master keys of the KMS matching pattern, with
+// whether each of them can encrypt and decrypt.
+func kmsKeyInventory(ctx context.Context, client *madmin.AdminClient, pattern string) (kmsKeyBackup, *probe.Error) {
+	status, e := client.KMSStatus(ctx)
+	if e != nil {
+		return kmsKeyBackup{}, probe.NewError(e)
+	}
+	keys, e := client.ListKeys(ctx, pattern)
+	if e != nil {
+		return kmsKeyBackup{}, probe.NewError(e).Trace(pattern)
+	}
+
+	backup := kmsKeyBackup{
+		KMS:        status.Name,
+		DefaultKey: status.DefaultKeyID,
+		Time:       time.Now().UTC(),
+		Keys:       make([]kmsKeyBackupInfo, 0, len(keys)),
+	}
+	for _, key := range keys {
+		info := kmsKeyBackupInfo{
+			Name:      key.Name,
+			CreatedAt: key.CreatedAt,
+			CreatedBy: key.CreatedBy,
+		}
+		if keyStatus, e := client.GetKeyStatus(ctx, key.Name); e == nil {
+			info.Encryption = keyStatus.EncryptionErr == ""
+			info.Decryption = keyStatus.DecryptionErr == ""
+		}
+		backup.Keys = append(backup.Keys, info)
+	}
+	return backup, nil
+}
@@ -23,8 +23,8 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
 	"github.com/kirolous/mc/pkg/probe"
-	"github.com/minio/pkg/console"
 	"golang.org/x/term"
 )
 
@@ -63,8 +63,31 @@ func mainAdminKMSCreateKey(ctx *cli.Context) error {
 	e := client.CreateKey(globalContext, keyID)
 	fatalIf(probe.NewError(e), "Failed to create master key")
 
-	if term.IsTerminal(int(os.Stdout.Fd())) {
-		console.Println(color.GreenString(fmt.Sprintf("Created master key `%s` successfully", keyID)))
+	if globalJSON || term.IsTerminal(int(os.Stdout.Fd())) {
+		printMsg(kmsKeyMsg{Key: keyID, Operation: "create"})
 	}
 	return nil
 }
+
+// kmsKeyMsg reports a change of a master key.
+type kmsKeyMsg struct {
+	Status    string `json:"status"`
+	Key       string `json:"key"`
+	Operation string `json:"operation"`
+}
+
+func (k kmsKeyMsg) JSON() string {
+	k.Status = "success"
+	kmsBytes, e := json.MarshalIndent(k, "", "    ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(kmsBytes)
+}
+
+func (k kmsKeyMsg) String() string {
+	operation := "Created"
+	if k.Operation == "import" {
+		operation = "Imported"
+	}
+	return color.GreenString(fmt.Sprintf("%s master key `%s` successfully", operation, k.Key))
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"golang.org/x/term"
+)
+
+var adminKMSImportKeyCmd = cli.Command{
+	Name:         "import",
+	Usage:        "import a master key into the KMS",
+	Action:       mainAdminKMSImportKey,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET KEY_NAME FILE
+
+  FILE holds the key material in the JSON format of the KMS, for KES:
+  {"bytes": "<base64 encoded 256 bit key>"}. Use '-' to read it from STDIN.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Import the master key 'my-key' from an escrow file.
+     $ {{.HelpName}} play my-key my-key.json
+`,
+}
+
+// mainAdminKMSImportKey is the handler for the "mc admin kms key import" command.
+func mainAdminKMSImportKey(ctx *cli.Context) error {
+	if len(ctx.Args()) != 3 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	client, err := newAdminClient(ctx.Args().Get(0))
+	fatalIf(err, "Unable to get a configured admin connection.")
+
+	keyID, keyFile := ctx.Args().Get(1), ctx.Args().Get(2)
+	var content []byte
+	var e error
+	if keyFile == "-" {
+		content, e = io.ReadAll(os.Stdin)
+	} else {
+		content, e = os.ReadFile(keyFile)
+	}
+	fatalIf(probe.NewError(e).Trace(keyFile), "Unable to read the key material")
+
+	e = client.ImportKey(globalContext, keyID, content)
+	fatalIf(probe.NewError(e), "Failed to import master key")
+
+	if globalJSON || term.IsTerminal(int(os.Stdout.Fd())) {
+		printMsg(kmsKeyMsg{Key: keyID, Operation: "import"})
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminKMSListKeysCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list the master keys of the KMS",
+	Action:       mainAdminKMSListKeys,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET [PATTERN]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all the master keys of the KMS.
+     $ {{.HelpName}} play
+  2. List the master keys whose name starts with 'tenant-'.
+     $ {{.HelpName}} play 'tenant-*'
+`,
+}
+
+type kmsKeyInfoMsg struct {
+	Status    string `json:"status"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	CreatedBy string `json:"createdBy,omitempty"`
+}
+
+func (k kmsKeyInfoMsg) JSON() string {
+	k.Status = "success"
+	kmsBytes, e := json.MarshalIndent(k, "", "    ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(kmsBytes)
+}
+
+func (k kmsKeyInfoMsg) String() string {
+	return fmt.Sprintf("%-30s %-25s %s", console.Colorize("KMSKey", k.Name), k.CreatedAt, k.CreatedBy)
+}
+
+// mainAdminKMSListKeys is the handler for the "mc admin kms key list" command.
+func mainAdminKMSListKeys(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 || len(ctx.Args()) > 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	console.SetColor("KMSKey", color.New(color.Bold))
+
+	client, err := newAdminClient(ctx.Args().Get(0))
+	fatalIf(err, "Unable to get a configured admin connection.")
+
+	pattern := "*"
+	if len(ctx.Args()) == 2 {
+		pattern = ctx.Args().Get(1)
+	}
+	keys, e := client.ListKeys(globalContext, pattern)
+	fatalIf(probe.NewError(e), "Failed to list master keys")
+
+	for _, key := range keys {
+		printMsg(kmsKeyInfoMsg{
+			Name:      key.Name,
+			CreatedAt: key.CreatedAt,
+			CreatedBy: key.CreatedBy,
+		})
+	}
+	return nil
+}
@@ -22,11 +22,14 @@ import "github.com/minio/cli"
 var adminKMSKeySubcommands = []cli.Command{
 	adminKMSCreateKeyCmd,
 	adminKMSKeyStatusCmd,
+	adminKMSListKeysCmd,
+	adminKMSImportKeyCmd,
+	adminKMSBackupKeysCmd,
 }
 
 var adminKMSKeyCmd = cli.Command{
 	Name:            "key",
-	Usage:           "manage KMS master keys: Request key status information",
+	Usage:           "manage KMS master keys",
 	Action:          mainAdminKMSKey,
 	Before:          setGlobalsFromContext,
 	Flags:           globalFlags,
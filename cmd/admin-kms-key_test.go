@@ -0,0 +1,100 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKMSKeyInventory(t *testing.T) {
+	var pattern string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/key/list"):
+			pattern = r.URL.Query().Get("pattern")
+			w.Write([]byte(`[{"name":"minio-default","createdAt":"2023-01-01T00:00:00Z","createdBy":"admin"},{"name":"tenant-a"},{"name":"tenant-b"}]`))
+		case strings.HasSuffix(r.URL.Path, "/key/status"):
+			switch r.URL.Query().Get("key-id") {
+			case "tenant-a":
+				w.Write([]byte(`{"key-id":"tenant-a","decryption-error":"key is disabled"}`))
+			case "tenant-b":
+				// The health of a key whose status is unknown is not reported.
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				w.Write([]byte(`{"key-id":"minio-default"}`))
+			}
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			w.Write([]byte(`{"name":"kes","default-key-id":"minio-default"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	client, err := newAdminClient("myminio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup, err := kmsKeyInventory(context.Background(), client, "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pattern != "*" {
+		t.Errorf("expected the keys matching *, listed %q", pattern)
+	}
+	if backup.KMS != "kes" || backup.DefaultKey != "minio-default" || backup.Time.IsZero() {
+		t.Errorf("unexpected backup %+v", backup)
+	}
+	expected := []kmsKeyBackupInfo{
+		{Name: "minio-default", CreatedAt: "2023-01-01T00:00:00Z", CreatedBy: "admin", Encryption: true, Decryption: true},
+		{Name: "tenant-a", Encryption: true},
+		{Name: "tenant-b"},
+	}
+	if !reflect.DeepEqual(backup.Keys, expected) {
+		t.Errorf("expected keys %+v, got %+v", expected, backup.Keys)
+	}
+}
+
+func TestKMSKeyMessages(t *testing.T) {
+	testCases := []struct {
+		msg      message
+		expected []string
+	}{
+		{kmsKeyMsg{Key: "my-key", Operation: "create"}, []string{"Created master key `my-key` successfully", `"operation":"create"`}},
+		{kmsKeyMsg{Key: "my-key", Operation: "import"}, []string{"Imported master key `my-key` successfully", `"operation":"import"`}},
+		{kmsKeyInfoMsg{Name: "tenant-a", CreatedBy: "admin"}, []string{"tenant-a", `"name":"tenant-a"`, `"createdBy":"admin"`}},
+		{kmsKeyBackupMsg{File: "keys.json", Keys: 3}, []string{"Saved the inventory of 3 master key(s) to `keys.json`.", `"keys":3`}},
+	}
+	for i, testCase := range testCases {
+		s := testCase.msg.String() + testCase.msg.JSON()
+		if !strings.Contains(s, `"status":"success"`) {
+			t.Errorf("Test %d: expected a success status in %s", i+1, s)
+		}
+		for _, expected := range testCase.expected {
+			if !strings.Contains(s, expected) {
+				t.Errorf("Test %d: expected %q in %s", i+1, expected, s)
+			}
+		}
+	}
+}
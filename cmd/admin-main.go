@@ -32,6 +32,7 @@ var adminCmdSubcommands = []cli.Command{
 	adminServiceCmd,
 	adminServerUpdateCmd,
 	adminInfoCmd,
+	adminAPIVersionsCmd,
 	adminInspectCmd,
 	adminUserCmd,
 	adminGroupCmd,
@@ -39,8 +40,11 @@ var adminCmdSubcommands = []cli.Command{
 	adminReplicateCmd,
 	adminIDPCmd,
 	adminConfigCmd,
+	adminNotifyCmd,
 	adminDecommissionCmd,
 	adminHealCmd,
+	adminDriveCmd,
+	adminNodeCmd,
 	adminPrometheusCmd,
 	adminKMSCmd,
 	adminHealthCmd(),
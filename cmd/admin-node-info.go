@@ -0,0 +1,146 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminNodeInfoCmd = cli.Command{
+	Name:         "info",
+	Usage:        "show the information of a single node",
+	Action:       mainAdminNodeInfo,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET NODE
+
+  NODE is the endpoint of the node as shown by 'mc admin node list', the port may be omitted.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the information of the node 'minio2:9000' of 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio/ minio2:9000
+`,
+}
+
+// nodeInfoMessage is the information of a single node.
+type nodeInfoMessage struct {
+	nodeMessage
+	CommitID       string             `json:"commitID,omitempty"`
+	NumCPU         int                `json:"numCPU,omitempty"`
+	RuntimeVersion string             `json:"runtimeVersion,omitempty"`
+	MemAlloc       uint64             `json:"memAlloc,omitempty"`
+	Network        map[string]string  `json:"network,omitempty"`
+	Drives         []driveListMessage `json:"drives,omitempty"`
+}
+
+// Colorized message for console printing.
+func (n nodeInfoMessage) String() string {
+	var b strings.Builder
+	stateTheme := "NodeOnline"
+	if n.State != string(madmin.ItemOnline) {
+		stateTheme = "NodeOffline"
+	}
+	fmt.Fprintf(&b, "%s  %s\n", console.Colorize("NodeHeader", n.Endpoint), console.Colorize(stateTheme, n.State))
+	if n.Pool > 0 {
+		fmt.Fprintf(&b, "   Pool: %d\n", n.Pool)
+	}
+	fmt.Fprintf(&b, "   Uptime: %s\n", n.uptime())
+	if n.Version != "" {
+		fmt.Fprintf(&b, "   Version: %s (%s)\n", n.Version, n.CommitID)
+	}
+	if n.NumCPU > 0 {
+		fmt.Fprintf(&b, "   Runtime: %s, %d CPUs, %s allocated\n", n.RuntimeVersion, n.NumCPU, humanize.IBytes(n.MemAlloc))
+	}
+
+	peers := make([]string, 0, len(n.Network))
+	for peer := range n.Network {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+	fmt.Fprintf(&b, "   Network: %d/%d OK\n", n.NetworkOnline, n.NetworkTotal)
+	for _, peer := range peers {
+		if n.Network[peer] != string(madmin.ItemOnline) {
+			fmt.Fprintf(&b, "      %s: %s\n", peer, console.Colorize("NodeOffline", n.Network[peer]))
+		}
+	}
+
+	fmt.Fprintf(&b, "   Drives: %d/%d OK\n", n.DrivesOnline, n.DrivesOnline+n.DrivesOffline)
+	for _, d := range n.Drives {
+		b.WriteString("      " + d.String() + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON'ified message for scripting.
+func (n nodeInfoMessage) JSON() string {
+	n.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(n, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainAdminNodeInfo is the handle for "mc admin node info" command.
+func mainAdminNodeInfo(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setAdminNodeColors()
+	setAdminDriveColors()
+
+	aliasedURL, node := ctx.Args().Get(0), ctx.Args().Get(1)
+	servers, err := fetchAdminNodes(aliasedURL)
+	fatalIf(err, "Unable to get the nodes of `"+aliasedURL+"`.")
+
+	for _, srv := range servers {
+		if !matchAdminNode(srv, node) {
+			continue
+		}
+		msg := nodeInfoMessage{
+			nodeMessage:    newNodeMessage(srv),
+			CommitID:       srv.CommitID,
+			NumCPU:         srv.NumCPU,
+			RuntimeVersion: srv.RuntimeVersion,
+			MemAlloc:       srv.MemStats.Alloc,
+			Network:        srv.Network,
+		}
+		for _, d := range srv.Disks {
+			msg.Drives = append(msg.Drives, newDriveListMessage(d))
+		}
+		printMsg(msg)
+		return nil
+	}
+	fatalIf(errInvalidArgument().Trace(node), fmt.Sprintf("Node `%s` is not part of `%s`.", node, aliasedURL))
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminNodeListCmd = cli.Command{
+	Name:         "list",
+	Usage:        "list the nodes with their state, version and drives",
+	Action:       mainAdminNodeList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List the nodes of 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio/
+`,
+}
+
+func nodeListTable(stateTheme string) PrettyTable {
+	return newPrettyTable("  ",
+		Field{"", 40},
+		Field{stateTheme, 8},
+		Field{"", 5},
+		Field{"", 10},
+		Field{"", 36},
+		Field{"", 10},
+		Field{"", -1},
+	)
+}
+
+// Colorized message for console printing.
+func (n nodeMessage) String() string {
+	stateTheme := "NodeOnline"
+	if n.State != string(madmin.ItemOnline) {
+		stateTheme = "NodeOffline"
+	}
+	pool := "-"
+	if n.Pool > 0 {
+		pool = fmt.Sprint(n.Pool)
+	}
+	drives := fmt.Sprintf("%d/%d", n.DrivesOnline, n.DrivesOnline+n.DrivesOffline)
+	if n.DrivesHealing > 0 {
+		drives += fmt.Sprintf(" (%d healing)", n.DrivesHealing)
+	}
+	return nodeListTable(stateTheme).buildRow(n.Endpoint, n.State, pool, n.uptime(), n.Version,
+		drives, fmt.Sprintf("%d/%d", n.NetworkOnline, n.NetworkTotal))
+}
+
+// JSON'ified message for scripting.
+func (n nodeMessage) JSON() string {
+	n.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(n, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func setAdminNodeColors() {
+	console.SetColor("NodeHeader", color.New(color.Bold, color.Underline))
+	console.SetColor("NodeOnline", color.New(color.FgGreen))
+	console.SetColor("NodeOffline", color.New(color.FgRed, color.Bold))
+}
+
+// mainAdminNodeList is the handle for "mc admin node list" command.
+func mainAdminNodeList(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setAdminNodeColors()
+
+	aliasedURL := ctx.Args().First()
+	servers, err := fetchAdminNodes(aliasedURL)
+	fatalIf(err, "Unable to get the nodes of `"+aliasedURL+"`.")
+
+	if !globalJSON {
+		console.Println(console.Colorize("NodeHeader", nodeListTable("").buildRow(
+			"ENDPOINT", "STATE", "POOL", "UPTIME", "VERSION", "DRIVES", "NETWORK")))
+	}
+	for _, srv := range servers {
+		printMsg(newNodeMessage(srv))
+	}
+	return nil
+}
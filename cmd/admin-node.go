@@ -0,0 +1,129 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+var adminNodeSubcommands = []cli.Command{
+	adminNodeListCmd,
+	adminNodeInfoCmd,
+}
+
+var adminNodeCmd = cli.Command{
+	Name:            "node",
+	Usage:           "list the nodes of a MinIO cluster and show their information",
+	Action:          mainAdminNode,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     adminNodeSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainAdminNode is the handle for "mc admin node" command.
+func mainAdminNode(ctx *cli.Context) error {
+	commandNotFound(ctx, adminNodeSubcommands)
+	return nil
+}
+
+// nodeMessage is the summary of a node of the cluster.
+type nodeMessage struct {
+	Status        string `json:"status"`
+	Endpoint      string `json:"endpoint"`
+	State         string `json:"state"`
+	Pool          int    `json:"pool,omitempty"`
+	Uptime        int64  `json:"uptime,omitempty"`
+	Version       string `json:"version,omitempty"`
+	DrivesOnline  int    `json:"drivesOnline"`
+	DrivesOffline int    `json:"drivesOffline"`
+	DrivesHealing int    `json:"drivesHealing"`
+	NetworkOnline int    `json:"networkOnline"`
+	NetworkTotal  int    `json:"networkTotal"`
+}
+
+func newNodeMessage(srv madmin.ServerProperties) nodeMessage {
+	msg := nodeMessage{
+		Endpoint:     srv.Endpoint,
+		State:        srv.State,
+		Pool:         srv.PoolNumber,
+		Uptime:       srv.Uptime,
+		Version:      srv.Version,
+		NetworkTotal: len(srv.Network),
+	}
+	for _, d := range srv.Disks {
+		switch driveHealth(d) {
+		case driveHealthOK:
+			msg.DrivesOnline++
+		case driveHealthHealing:
+			msg.DrivesOnline++
+			msg.DrivesHealing++
+		default:
+			msg.DrivesOffline++
+		}
+	}
+	for _, state := range srv.Network {
+		if state == string(madmin.ItemOnline) {
+			msg.NetworkOnline++
+		}
+	}
+	return msg
+}
+
+func (n nodeMessage) uptime() string {
+	if n.State != string(madmin.ItemOnline) || n.Uptime == 0 {
+		return "-"
+	}
+	return timeDurationToHumanizedDuration(time.Duration(n.Uptime) * time.Second).StringShort()
+}
+
+// fetchAdminNodes returns the nodes of an alias.
+func fetchAdminNodes(aliasedURL string) ([]madmin.ServerProperties, *probe.Error) {
+	client, err := newAdminClient(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	info, e := client.ServerInfo(globalContext)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(aliasedURL)
+	}
+	return info.Servers, nil
+}
+
+// matchAdminNode reports whether a node is the one given on the command
+// line, either as its endpoint or as its host name without the port.
+func matchAdminNode(srv madmin.ServerProperties, node string) bool {
+	node = strings.TrimSuffix(node, "/")
+	if u, e := url.Parse(node); e == nil && u.Host != "" {
+		node = u.Host
+	}
+	if srv.Endpoint == node {
+		return true
+	}
+	host := srv.Endpoint
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	return host == node
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestMatchAdminNode(t *testing.T) {
+	srv := madmin.ServerProperties{Endpoint: "minio2:9000"}
+	testCases := []struct {
+		node  string
+		match bool
+	}{
+		{"minio2:9000", true},
+		{"minio2", true},
+		{"http://minio2:9000/", true},
+		{"minio2:9001", false},
+		{"minio", false},
+	}
+	for _, tc := range testCases {
+		if got := matchAdminNode(srv, tc.node); got != tc.match {
+			t.Errorf("%s: expected %v, got %v", tc.node, tc.match, got)
+		}
+	}
+}
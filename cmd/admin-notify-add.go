@@ -0,0 +1,157 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// adminNotifyAddFlags are the parameters of all kinds of targets.
+var adminNotifyAddFlags = func() (flags []cli.Flag) {
+	seen := map[string]bool{}
+	for _, kind := range notifyKindNames() {
+		for _, p := range notifyKinds[kind] {
+			if seen[p.key] {
+				continue
+			}
+			seen[p.key] = true
+			if p.isBool {
+				flags = append(flags, cli.BoolFlag{Name: p.flag(), Usage: p.usage})
+			} else {
+				flags = append(flags, cli.StringFlag{Name: p.flag(), Usage: p.usage})
+			}
+		}
+	}
+	return flags
+}()
+
+var adminNotifyAddCmd = cli.Command{
+	Name:         "add",
+	Usage:        "add or update a notification target",
+	Action:       mainAdminNotifyAdd,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(adminNotifyAddFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET KIND [NAME]
+
+KIND:
+  webhook  --endpoint [--auth-token] [--client-cert] [--client-key]
+  kafka    --brokers --topic [--sasl-username] [--sasl-password] [--sasl-mechanism] [--tls] [--tls-skip-verify]
+  amqp     --url [--exchange] [--exchange-type] [--routing-key] [--mandatory] [--durable] [--delivery-mode]
+  nats     --address --subject [--username] [--password] [--token] [--tls] [--tls-skip-verify]
+
+  All kinds accept --queue-dir and --queue-limit. The default target of the kind is set if NAME is omitted.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Add a webhook target named 'audit'.
+     {{.Prompt}} {{.HelpName}} myminio webhook audit --endpoint https://events.example.com/minio --auth-token "Bearer secret"
+
+  2. Add a Kafka target named 'ingest' storing undelivered events on the server.
+     {{.Prompt}} {{.HelpName}} myminio kafka ingest --brokers kafka1:9092,kafka2:9092 --topic minio --queue-dir /var/minio/events
+
+  3. Add the default NATS target.
+     {{.Prompt}} {{.HelpName}} myminio nats --address nats.example.com:4222 --subject minio.events --tls
+`,
+}
+
+// notifyTargetConfig returns the config of a target set with the values
+// of its parameters, the target is enabled.
+func notifyTargetConfig(kind, name string, values map[string]string) (string, error) {
+	config := []string{notifySubsys(kind, name), "enable=on"}
+	params := map[string]bool{}
+	for _, p := range notifyKinds[kind] {
+		params[p.key] = true
+		value, ok := values[p.key]
+		if !ok {
+			if p.required {
+				return "", fmt.Errorf("--%s is required for %s targets", p.flag(), kind)
+			}
+			continue
+		}
+		if strings.Contains(value, `"`) {
+			return "", fmt.Errorf("--%s cannot contain '\"'", p.flag())
+		}
+		config = append(config, fmt.Sprintf(`%s="%s"`, p.key, value))
+	}
+	for key := range values {
+		if !params[key] {
+			return "", fmt.Errorf("--%s is not a parameter of %s targets", strings.ReplaceAll(key, "_", "-"), kind)
+		}
+	}
+	return strings.Join(config, " "), nil
+}
+
+// mainAdminNotifyAdd is the handle for "mc admin notify add" command.
+func mainAdminNotifyAdd(ctx *cli.Context) error {
+	aliasedURL, kind, name := checkNotifyTargetArgs(ctx, false)
+
+	console.SetColor("SetConfigSuccess", color.New(color.FgGreen, color.Bold))
+
+	values := map[string]string{}
+	seen := map[string]bool{}
+	for _, kind := range notifyKindNames() {
+		for _, p := range notifyKinds[kind] {
+			if seen[p.key] || !ctx.IsSet(p.flag()) {
+				continue
+			}
+			seen[p.key] = true
+			if p.isBool {
+				values[p.key] = "off"
+				if ctx.Bool(p.flag()) {
+					values[p.key] = "on"
+				}
+			} else {
+				values[p.key] = ctx.String(p.flag())
+			}
+		}
+	}
+	config, e := notifyTargetConfig(kind, name, values)
+	if e != nil {
+		fatalIf(probe.NewError(e), "Invalid parameters.")
+	}
+	if name != "" && strings.ContainsAny(name, " =:") {
+		fatalIf(probe.NewError(errors.New("the name cannot contain spaces, '=' or ':'")).Trace(name), "Invalid target name.")
+	}
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	restart, e := client.SetConfigKV(globalContext, config)
+	fatalIf(probe.NewError(e), "Unable to add the %s target.", kind)
+
+	printMsg(configSetMessage{
+		targetAlias: aliasedURL,
+		restart:     restart,
+	})
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminNotifyListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list notification targets",
+	Action:       mainAdminNotifyList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET [KIND]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all notification targets with their ARN and status.
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. List the webhook targets.
+     {{.Prompt}} {{.HelpName}} myminio webhook
+`,
+}
+
+// notifyTargetMessage is a notification target.
+type notifyTargetMessage struct {
+	Status       string `json:"status"`
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	Enabled      bool   `json:"enabled"`
+	Address      string `json:"address"`
+	ARN          string `json:"arn,omitempty"`
+	TargetStatus string `json:"targetStatus,omitempty"`
+}
+
+// Colorized message for console printing.
+func (m notifyTargetMessage) String() string {
+	enabled := "off"
+	if m.Enabled {
+		enabled = "on"
+	}
+	status := m.TargetStatus
+	if status == "" {
+		status = "-"
+	}
+	statusColor := "NotifyOffline"
+	if strings.EqualFold(status, "online") {
+		statusColor = "NotifyOnline"
+	}
+	return newPrettyTable("  ",
+		Field{"NotifyKind", 8},
+		Field{"NotifyName", 16},
+		Field{"", 3},
+		Field{statusColor, 8},
+		Field{"NotifyARN", 40},
+		Field{"", -1},
+	).buildRow(m.Kind, m.Name, enabled, status, m.ARN, m.Address)
+}
+
+// JSON'ified message for scripting.
+func (m notifyTargetMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// notifyTargetARN returns the ARN of an enabled target, empty if the
+// server doesn't report it.
+func notifyTargetARN(info madmin.InfoMessage, kind, name string) string {
+	for _, arn := range info.SQSARN {
+		if strings.HasSuffix(arn, ":"+notifyTargetName(name)+":"+kind) {
+			return arn
+		}
+	}
+	return ""
+}
+
+// notifyTargetStatus returns the status of an enabled target, the status
+// of the first server not reporting it online if any.
+func notifyTargetStatus(info madmin.InfoMessage, kind, name string) (status string) {
+	for _, notifications := range info.Services.Notifications {
+		for _, targets := range notifications[kind] {
+			st, ok := targets[notifyTargetName(name)]
+			if !ok {
+				continue
+			}
+			if !strings.EqualFold(st.Status, "online") {
+				return st.Status
+			}
+			status = st.Status
+		}
+	}
+	return status
+}
+
+// mainAdminNotifyList is the handle for "mc admin notify list" command.
+func mainAdminNotifyList(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) < 1 || len(args) > 2 {
+		showCommandHelpAndExit(ctx, 1)
+	}
+	aliasedURL := args.Get(0)
+	kinds := notifyKindNames()
+	if kind := args.Get(1); kind != "" {
+		if _, ok := notifyKinds[kind]; !ok {
+			fatalIf(errInvalidArgument().Trace(kind), "Unknown kind of notification target, expected one of %s.", strings.Join(kinds, ", "))
+		}
+		kinds = []string{kind}
+	}
+
+	console.SetColor("NotifyKind", color.New(color.FgCyan))
+	console.SetColor("NotifyName", color.New(color.Bold))
+	console.SetColor("NotifyOnline", color.New(color.FgGreen))
+	console.SetColor("NotifyOffline", color.New(color.FgRed))
+	console.SetColor("NotifyARN", color.New(color.FgYellow))
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	info, e := client.ServerInfo(globalContext)
+	fatalIf(probe.NewError(e), "Unable to get the server information.")
+
+	for _, kind := range kinds {
+		buf, e := client.GetConfigKV(globalContext, "notify_"+kind)
+		fatalIf(probe.NewError(e), "Unable to get the %s targets.", kind)
+		configs, e := madmin.ParseServerConfigOutput(string(buf))
+		fatalIf(probe.NewError(e), "Unable to parse the %s targets.", kind)
+
+		addressKey := notifyKinds[kind][0].key
+		for _, config := range configs {
+			// The default target is always reported, even if not configured.
+			address, _ := config.Lookup(addressKey)
+			if address == "" {
+				continue
+			}
+			enable, _ := config.Lookup(madmin.EnableKey)
+			msg := notifyTargetMessage{
+				Kind:    kind,
+				Name:    notifyTargetName(config.Target),
+				Enabled: enable == madmin.EnableOn,
+				Address: address,
+			}
+			if msg.Enabled {
+				msg.ARN = notifyTargetARN(info, kind, config.Target)
+				msg.TargetStatus = notifyTargetStatus(info, kind, config.Target)
+			}
+			printMsg(msg)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminNotifyRemoveCmd = cli.Command{
+	Name:         "remove",
+	ShortName:    "rm",
+	Usage:        "remove a notification target",
+	Action:       mainAdminNotifyRemove,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET KIND [NAME]
+
+  The default target of the kind is removed if NAME is omitted.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove the webhook target named 'audit'.
+     {{.Prompt}} {{.HelpName}} myminio webhook audit
+`,
+}
+
+// mainAdminNotifyRemove is the handle for "mc admin notify remove" command.
+func mainAdminNotifyRemove(ctx *cli.Context) error {
+	aliasedURL, kind, name := checkNotifyTargetArgs(ctx, false)
+
+	console.SetColor("SetConfigSuccess", color.New(color.FgGreen, color.Bold))
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	restart, e := client.DelConfigKV(globalContext, notifySubsys(kind, name))
+	fatalIf(probe.NewError(e), "Unable to remove the %s target.", kind)
+
+	printMsg(configSetMessage{
+		targetAlias: aliasedURL,
+		restart:     restart,
+	})
+	return nil
+}
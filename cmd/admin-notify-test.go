@@ -0,0 +1,160 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminNotifyTestFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "bucket",
+		Usage: "bucket where the test object is created",
+	},
+}
+
+var adminNotifyTestCmd = cli.Command{
+	Name:         "test",
+	Usage:        "send a test event to a notification target",
+	Action:       mainAdminNotifyTest,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(adminNotifyTestFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} --bucket BUCKET TARGET KIND [NAME]
+
+DESCRIPTION:
+  The target is temporarily subscribed to the put events of a unique prefix of the bucket, and an
+  object is put and removed in this prefix. The status of the target reported by the servers tells
+  whether the event was delivered, or stored in the queue directory of the target.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Send a test event to the webhook target named 'audit'.
+     {{.Prompt}} {{.HelpName}} --bucket mybucket myminio webhook audit
+`,
+}
+
+// notifyTestMessage is the result of a test event sent to a target.
+type notifyTestMessage struct {
+	Status       string `json:"status"`
+	Kind         string `json:"kind"`
+	Name         string `json:"name"`
+	ARN          string `json:"arn"`
+	Object       string `json:"object"`
+	TargetStatus string `json:"targetStatus"`
+}
+
+// Colorized message for console printing.
+func (m notifyTestMessage) String() string {
+	if strings.EqualFold(m.TargetStatus, "online") {
+		return console.Colorize("NotifyOnline", fmt.Sprintf("Test event for `%s` sent to %s target `%s`, the target is online.", m.Object, m.Kind, m.Name))
+	}
+	return console.Colorize("NotifyOffline", fmt.Sprintf("Test event for `%s` sent to %s target `%s`, the target is %s: the event is queued if the target has a queue directory, else it is lost.",
+		m.Object, m.Kind, m.Name, strings.ToLower(m.TargetStatus)))
+}
+
+// JSON'ified message for scripting.
+func (m notifyTestMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainAdminNotifyTest is the handle for "mc admin notify test" command.
+func mainAdminNotifyTest(cliCtx *cli.Context) error {
+	aliasedURL, kind, name := checkNotifyTargetArgs(cliCtx, false)
+	bucket := cliCtx.String("bucket")
+	if bucket == "" {
+		fatalIf(errInvalidArgument(), "--bucket is required to send a test event.")
+	}
+
+	console.SetColor("NotifyOnline", color.New(color.FgGreen, color.Bold))
+	console.SetColor("NotifyOffline", color.New(color.FgRed, color.Bold))
+
+	ctx, cancelNotifyTest := context.WithCancel(globalContext)
+	defer cancelNotifyTest()
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	info, e := client.ServerInfo(ctx)
+	fatalIf(probe.NewError(e), "Unable to get the server information.")
+	arn := notifyTargetARN(info, kind, name)
+	if arn == "" {
+		fatalIf(errDummy().Trace(kind, name), "The %s target `%s` is not enabled on the server.", kind, notifyTargetName(name))
+	}
+
+	alias, _, _ := mustExpandAlias(aliasedURL)
+	prefix := ".mc-notify-test/" + uuid.NewString() + "/"
+	bucketClnt := newComposeClient(alias + "/" + bucket)
+	err = bucketClnt.AddNotificationConfig(ctx, arn, []string{"put"}, prefix, "", false)
+	fatalIf(err.Trace(bucket), "Unable to subscribe the target to the events of `%s`.", bucket)
+
+	object := alias + "/" + bucket + "/" + prefix + "event"
+	objectClnt := newComposeClient(object)
+	body := fmt.Sprintf("mc admin notify test %s %s\n", kind, notifyTargetName(name))
+	_, err = objectClnt.Put(ctx, strings.NewReader(body), int64(len(body)), nil, PutOptions{})
+	if err == nil {
+		contentCh := make(chan *ClientContent, 1)
+		contentCh <- &ClientContent{URL: objectClnt.GetURL()}
+		close(contentCh)
+		for result := range objectClnt.Remove(ctx, false, false, false, false, contentCh) {
+			errorIf(result.Err.Trace(object), "Unable to remove the test object `%s`.", object)
+		}
+	}
+	rerr := bucketClnt.RemoveNotificationConfig(ctx, arn, "put", prefix, "")
+	errorIf(rerr.Trace(bucket), "Unable to unsubscribe the target from the events of `%s`.", bucket)
+	fatalIf(err.Trace(object), "Unable to put the test object `%s`.", object)
+
+	// Give the servers the time to send the event before checking the
+	// status of the target.
+	time.Sleep(time.Second)
+	info, e = client.ServerInfo(ctx)
+	fatalIf(probe.NewError(e), "Unable to get the server information.")
+
+	status := notifyTargetStatus(info, kind, name)
+	if status == "" {
+		status = "unknown"
+	}
+	printMsg(notifyTestMessage{
+		Kind:         kind,
+		Name:         notifyTargetName(name),
+		ARN:          arn,
+		Object:       object,
+		TargetStatus: status,
+	})
+	return nil
+}
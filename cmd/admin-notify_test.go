@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestNotifyTargetConfig(t *testing.T) {
+	testCases := []struct {
+		kind, name string
+		values     map[string]string
+		expected   string
+		success    bool
+	}{
+		{"webhook", "audit", map[string]string{"endpoint": "http://hook:8080", "auth_token": "Bearer x"}, `notify_webhook:audit enable=on endpoint="http://hook:8080" auth_token="Bearer x"`, true},
+		{"nats", "", map[string]string{"address": "nats:4222", "subject": "minio", "tls": "on"}, `notify_nats enable=on address="nats:4222" subject="minio" tls="on"`, true},
+		// Missing required parameter.
+		{"kafka", "ingest", map[string]string{"brokers": "kafka:9092"}, "", false},
+		// Parameter of another kind.
+		{"webhook", "", map[string]string{"endpoint": "http://hook:8080", "topic": "minio"}, "", false},
+		{"amqp", "", map[string]string{"url": `amqp://"`}, "", false},
+	}
+	for i, testCase := range testCases {
+		config, e := notifyTargetConfig(testCase.kind, testCase.name, testCase.values)
+		if testCase.success != (e == nil) {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if config != testCase.expected {
+			t.Fatalf("Test %d: expected %s, got %s", i+1, testCase.expected, config)
+		}
+	}
+}
+
+func TestNotifyTargetStatus(t *testing.T) {
+	info := madmin.InfoMessage{
+		SQSARN: []string{"arn:minio:sqs::_:webhook", "arn:minio:sqs::audit:webhook"},
+		Services: madmin.Services{
+			Notifications: []map[string][]madmin.TargetIDStatus{
+				{"webhook": {{"audit": {Status: "Online"}}, {"_": {Status: "Online"}}}},
+				{"webhook": {{"audit": {Status: "Offline"}}}},
+			},
+		},
+	}
+	if arn := notifyTargetARN(info, "webhook", "audit"); arn != "arn:minio:sqs::audit:webhook" {
+		t.Fatalf("unexpected ARN %s", arn)
+	}
+	if arn := notifyTargetARN(info, "webhook", ""); arn != "arn:minio:sqs::_:webhook" {
+		t.Fatalf("unexpected ARN %s", arn)
+	}
+	if status := notifyTargetStatus(info, "webhook", "audit"); status != "Offline" {
+		t.Fatalf("unexpected status %s", status)
+	}
+	if status := notifyTargetStatus(info, "webhook", ""); status != "Online" {
+		t.Fatalf("unexpected status %s", status)
+	}
+	if status := notifyTargetStatus(info, "kafka", ""); status != "" {
+		t.Fatalf("unexpected status %s", status)
+	}
+}
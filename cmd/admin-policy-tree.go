@@ -0,0 +1,295 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminPolicyTreeFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "dot",
+		Usage: "print the tree as a graph in the DOT language of Graphviz",
+	},
+}
+
+var adminPolicyTreeCmd = cli.Command{
+	Name:         "tree",
+	Usage:        "show the policies of users, directly attached and inherited from groups",
+	Action:       mainAdminPolicyTree,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(adminPolicyTreeFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the policies of all users, and the groups they inherit policies from.
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. Render the users, groups and policies as an image with Graphviz.
+     {{.Prompt}} {{.HelpName}} --dot myminio | dot -Tsvg -o policies.svg
+`,
+}
+
+// policyTreeGroup is a group a user is member of.
+type policyTreeGroup struct {
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	Policies []string `json:"policies"`
+}
+
+// policyTreeMessage is a user with its attached policies and groups.
+type policyTreeMessage struct {
+	Status     string            `json:"status"`
+	User       string            `json:"user"`
+	UserStatus string            `json:"userStatus"`
+	Policies   []string          `json:"policies"`
+	Groups     []policyTreeGroup `json:"groups"`
+}
+
+// Colorized message for console printing.
+func (m policyTreeMessage) String() string {
+	var b strings.Builder
+	user := m.User
+	if m.UserStatus == string(madmin.AccountDisabled) {
+		user += " (disabled)"
+	}
+	b.WriteString(console.Colorize("TreeUser", user))
+
+	nrChildren := len(m.Policies) + len(m.Groups)
+	if nrChildren == 0 {
+		b.WriteString("\n└─ " + console.Colorize("TreeNone", "no policy"))
+	}
+	branch := func(i int, indent string) (string, string) {
+		if i == nrChildren-1 {
+			return indent + "└─ ", indent + "   "
+		}
+		return indent + "├─ ", indent + "│  "
+	}
+
+	i := 0
+	for _, policy := range m.Policies {
+		prefix, _ := branch(i, "")
+		b.WriteString("\n" + prefix + console.Colorize("TreePolicy", policy))
+		i++
+	}
+	for _, group := range m.Groups {
+		prefix, indent := branch(i, "")
+		name := "group " + group.Name
+		if group.Status == string(madmin.GroupDisabled) {
+			name += " (disabled)"
+		}
+		b.WriteString("\n" + prefix + console.Colorize("TreeGroup", name))
+		if len(group.Policies) == 0 {
+			b.WriteString("\n" + indent + "└─ " + console.Colorize("TreeNone", "no policy"))
+		}
+		for j, policy := range group.Policies {
+			prefix := indent + "├─ "
+			if j == len(group.Policies)-1 {
+				prefix = indent + "└─ "
+			}
+			b.WriteString("\n" + prefix + console.Colorize("TreePolicy", policy))
+		}
+		i++
+	}
+	return b.String()
+}
+
+// JSON'ified message for scripting.
+func (m policyTreeMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// policyTreeDOTMessage is the graph of users, groups and policies in
+// the DOT language.
+type policyTreeDOTMessage struct {
+	Status string `json:"status"`
+	DOT    string `json:"dot"`
+}
+
+// Colorized message for console printing.
+func (m policyTreeDOTMessage) String() string {
+	return m.DOT
+}
+
+// JSON'ified message for scripting.
+func (m policyTreeDOTMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// policyTreeDOT returns the graph of users, groups and policies in the
+// DOT language, edges go from users to groups and policies, and from
+// groups to policies.
+func policyTreeDOT(users []policyTreeMessage) string {
+	nodes := map[string]string{}
+	var edges []string
+	node := func(kind, name string) string {
+		id := fmt.Sprintf("%q", kind+":"+name)
+		if _, ok := nodes[id]; !ok {
+			nodes[id] = fmt.Sprintf("%s [label=%q, shape=%s];", id, name, map[string]string{
+				"user":   "ellipse",
+				"group":  "box",
+				"policy": "note",
+			}[kind])
+		}
+		return id
+	}
+	edgeSet := map[string]bool{}
+	edge := func(from, to string) {
+		e := from + " -> " + to + ";"
+		if !edgeSet[e] {
+			edgeSet[e] = true
+			edges = append(edges, e)
+		}
+	}
+
+	for _, user := range users {
+		u := node("user", user.User)
+		for _, policy := range user.Policies {
+			edge(u, node("policy", policy))
+		}
+		for _, group := range user.Groups {
+			g := node("group", group.Name)
+			edge(u, g)
+			for _, policy := range group.Policies {
+				edge(g, node("policy", policy))
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	b.WriteString("digraph policies {\n  rankdir=LR;\n")
+	for _, id := range ids {
+		b.WriteString("  " + nodes[id] + "\n")
+	}
+	for _, e := range edges {
+		b.WriteString("  " + e + "\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// splitPolicies splits a comma separated list of policies.
+func splitPolicies(policies string) []string {
+	list := []string{}
+	for _, policy := range strings.Split(policies, ",") {
+		if policy = strings.TrimSpace(policy); policy != "" {
+			list = append(list, policy)
+		}
+	}
+	return list
+}
+
+// mainAdminPolicyTree is the handler for "mc admin policy tree" command.
+func mainAdminPolicyTree(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1)
+	}
+
+	console.SetColor("TreeUser", color.New(color.FgGreen, color.Bold))
+	console.SetColor("TreeGroup", color.New(color.FgBlue))
+	console.SetColor("TreePolicy", color.New(color.FgYellow))
+	console.SetColor("TreeNone", color.New(color.FgHiBlack))
+
+	aliasedURL := ctx.Args().Get(0)
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	users, e := client.ListUsers(globalContext)
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to list users.")
+
+	groupNames, e := client.ListGroups(globalContext)
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to list groups.")
+
+	groups := make(map[string]policyTreeGroup, len(groupNames))
+	for _, name := range groupNames {
+		desc, e := client.GetGroupDescription(globalContext, name)
+		fatalIf(probe.NewError(e).Trace(name), "Unable to get the description of group `%s`.", name)
+		groups[name] = policyTreeGroup{
+			Name:     name,
+			Status:   desc.Status,
+			Policies: splitPolicies(desc.Policy),
+		}
+	}
+
+	accessKeys := make([]string, 0, len(users))
+	for k := range users {
+		accessKeys = append(accessKeys, k)
+	}
+	sort.Strings(accessKeys)
+
+	tree := make([]policyTreeMessage, 0, len(accessKeys))
+	for _, k := range accessKeys {
+		user := users[k]
+		msg := policyTreeMessage{
+			User:       k,
+			UserStatus: string(user.Status),
+			Policies:   splitPolicies(user.PolicyName),
+			Groups:     []policyTreeGroup{},
+		}
+		memberOf := append([]string{}, user.MemberOf...)
+		sort.Strings(memberOf)
+		for _, name := range memberOf {
+			group, ok := groups[name]
+			if !ok {
+				group = policyTreeGroup{Name: name, Policies: []string{}}
+			}
+			msg.Groups = append(msg.Groups, group)
+		}
+		tree = append(tree, msg)
+	}
+
+	if ctx.Bool("dot") {
+		printMsg(policyTreeDOTMessage{DOT: policyTreeDOT(tree)})
+		return nil
+	}
+	for _, msg := range tree {
+		printMsg(msg)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPolicyTreeDOT(t *testing.T) {
+	dot := policyTreeDOT([]policyTreeMessage{
+		{
+			User:     "alice",
+			Policies: []string{"readwrite"},
+			Groups:   []policyTreeGroup{{Name: "devs", Policies: []string{"diagnostics", "readwrite"}}},
+		},
+		{
+			User:   "bob",
+			Groups: []policyTreeGroup{{Name: "devs", Policies: []string{"diagnostics", "readwrite"}}},
+		},
+	})
+
+	for _, expected := range []string{
+		`"user:alice" [label="alice", shape=ellipse];`,
+		`"group:devs" [label="devs", shape=box];`,
+		`"policy:readwrite" [label="readwrite", shape=note];`,
+		`"user:alice" -> "policy:readwrite";`,
+		`"user:bob" -> "group:devs";`,
+		`"group:devs" -> "policy:diagnostics";`,
+	} {
+		if strings.Count(dot, expected) != 1 {
+			t.Errorf("expected %s once in\n%s", expected, dot)
+		}
+	}
+}
+
+func TestSplitPolicies(t *testing.T) {
+	if policies := splitPolicies("readwrite, diagnostics,"); strings.Join(policies, "|") != "readwrite|diagnostics" {
+		t.Fatalf("unexpected policies %v", policies)
+	}
+	if policies := splitPolicies(""); len(policies) != 0 {
+		t.Fatalf("unexpected policies %v", policies)
+	}
+}
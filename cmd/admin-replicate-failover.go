@@ -0,0 +1,556 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/minio/pkg/console"
+)
+
+var adminReplicateFailoverFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "recover",
+		Usage: "re-add a recovered site to site replication and resync it",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "run the pre-flight checks and display the plan without changing anything",
+	},
+	cli.BoolFlag{
+		Name:  "force",
+		Usage: "carry out the plan, removing the failed site or re-adding the recovered site",
+	},
+	cli.BoolFlag{
+		Name:  "repoint-aliases",
+		Usage: "point the local aliases of the failed site at the surviving site",
+	},
+}
+
+var adminReplicateFailoverCmd = cli.Command{
+	Name:         "failover",
+	Usage:        "remove a failed site from site replication, and later re-add it",
+	Action:       mainAdminReplicateFailover,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(globalFlags, adminReplicateFailoverFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS FAILED-SITE [--dry-run | --force]
+  {{.HelpName}} --recover ALIAS [ALIAS...] RECOVERED-ALIAS [--dry-run | --force]
+
+  The first form removes FAILED-SITE, a site name or deployment id, from the site replication
+  of ALIAS. The second form re-adds RECOVERED-ALIAS to the site replication of the surviving
+  ALIAS sites and resyncs it. Both run pre-flight checks and display the plan first, --force
+  carries it out.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+
+EXAMPLES:
+  1. Review the plan to remove the site "minio2", which is down, from the site replication of minio1.
+     {{.Prompt}} {{.HelpName}} minio1 minio2 --dry-run
+
+  2. Remove the site "minio2" and point the local aliases of minio2 at minio1.
+     {{.Prompt}} {{.HelpName}} minio1 minio2 --force --repoint-aliases
+
+  3. Re-add minio2, back online, to the site replication of minio1 and minio3 and resync it.
+     {{.Prompt}} {{.HelpName}} --recover minio1 minio3 minio2 --force
+`,
+}
+
+// Status of the checks and steps of a failover.
+const (
+	failoverOK      = "ok"
+	failoverWarning = "warning"
+	failoverFailed  = "failed"
+	failoverPlanned = "planned"
+	failoverManual  = "manual"
+	failoverDone    = "done"
+)
+
+// failoverStep is a pre-flight check or a step of the plan of a failover.
+type failoverStep struct {
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+
+	run func(ctx context.Context) error
+}
+
+// failoverMessage is the checklist and the plan of a failover.
+type failoverMessage struct {
+	Status string         `json:"status"`
+	Op     string         `json:"op"`
+	DryRun bool           `json:"dryRun,omitempty"`
+	Checks []failoverStep `json:"checks"`
+	Plan   []failoverStep `json:"plan"`
+}
+
+func (m failoverMessage) JSON() string {
+	m.Status = "success"
+	if !m.checksPassed() {
+		m.Status = "error"
+	}
+	for _, s := range m.Plan {
+		if s.Status == failoverFailed {
+			m.Status = "error"
+		}
+	}
+	return toJSON(m)
+}
+
+func (m failoverMessage) String() string {
+	mark := func(status string) string {
+		switch status {
+		case failoverOK, failoverDone:
+			return console.Colorize("FailoverOK", tickCell)
+		case failoverWarning:
+			return console.Colorize("FailoverWarning", "! ")
+		case failoverFailed:
+			return console.Colorize("FailoverFailed", crossTickCell)
+		}
+		return "  "
+	}
+	var b strings.Builder
+	b.WriteString(console.Colorize("FailoverHeader", "Pre-flight checklist:") + "\n")
+	for _, c := range m.Checks {
+		fmt.Fprintf(&b, " %s %s", mark(c.Status), c.Description)
+		if c.Error != "" {
+			fmt.Fprintf(&b, ": %s", c.Error)
+		}
+		b.WriteString("\n")
+	}
+	if len(m.Plan) == 0 {
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+	header := "Plan:"
+	if m.DryRun {
+		header = "Plan (dry run, nothing changed):"
+	}
+	b.WriteString("\n" + console.Colorize("FailoverHeader", header) + "\n")
+	for i, s := range m.Plan {
+		fmt.Fprintf(&b, " %s %d. %s", mark(s.Status), i+1, s.Description)
+		switch {
+		case s.Error != "":
+			fmt.Fprintf(&b, ": %s", s.Error)
+		case s.Status == failoverManual:
+			b.WriteString(" (manual)")
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// checksPassed returns false when a pre-flight check failed.
+func (m failoverMessage) checksPassed() bool {
+	for _, c := range m.Checks {
+		if c.Status == failoverFailed {
+			return false
+		}
+	}
+	return true
+}
+
+func checkAdminReplicateFailoverSyntax(ctx *cli.Context) {
+	argsNr := len(ctx.Args())
+	if argsNr == 0 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if ctx.Bool("recover") {
+		if argsNr < 2 {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...),
+				"Need at least one surviving alias and the recovered alias.")
+		}
+		if ctx.Bool("repoint-aliases") {
+			fatalIf(errInvalidArgument(), "--repoint-aliases cannot be used with --recover.")
+		}
+	} else if argsNr != 2 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...),
+			"Need exactly one alias and the name of the failed site.")
+	}
+	if ctx.Bool("dry-run") && ctx.Bool("force") {
+		fatalIf(errInvalidArgument(), "--dry-run and --force cannot be used together.")
+	}
+}
+
+func mainAdminReplicateFailover(ctx *cli.Context) error {
+	checkAdminReplicateFailoverSyntax(ctx)
+
+	console.SetColor("FailoverHeader", color.New(color.FgCyan, color.Bold))
+	console.SetColor("FailoverOK", color.New(color.FgGreen))
+	console.SetColor("FailoverWarning", color.New(color.FgYellow))
+	console.SetColor("FailoverFailed", color.New(color.FgRed))
+
+	args := ctx.Args()
+	var msg failoverMessage
+	if ctx.Bool("recover") {
+		msg = planSiteRecovery(globalContext, args[:len(args)-1], args[len(args)-1])
+	} else {
+		msg = planSiteFailover(globalContext, args.Get(0), args.Get(1), ctx.Bool("repoint-aliases"))
+	}
+
+	dryRun := !ctx.Bool("force")
+	if !msg.checksPassed() {
+		msg.Plan = nil
+		printMsg(msg)
+		fatalIf(errDummy().Trace(args...), "Pre-flight checks failed, nothing was changed.")
+	}
+	if dryRun {
+		msg.DryRun = true
+		printMsg(msg)
+		if !ctx.Bool("dry-run") {
+			console.Infoln("Review the plan and run again with --force to carry it out. This operation is *IRREVERSIBLE*.")
+		}
+		return nil
+	}
+
+	// Carry out the plan, stopping at the first failed step.
+	failed := false
+	for i := range msg.Plan {
+		step := &msg.Plan[i]
+		if step.run == nil || failed {
+			continue
+		}
+		if e := step.run(globalContext); e != nil {
+			step.Status, step.Error = failoverFailed, e.Error()
+			failed = true
+			continue
+		}
+		step.Status = failoverDone
+	}
+	printMsg(msg)
+	if failed {
+		fatalIf(errDummy().Trace(args...), "Unable to complete the plan.")
+	}
+	return nil
+}
+
+// planSiteFailover checks that failedSite can be removed from the site
+// replication of aliasedURL and returns the plan to do it.
+func planSiteFailover(ctx context.Context, aliasedURL, failedSite string, repointAliases bool) (msg failoverMessage) {
+	msg.Op = "failover"
+	check := func(description string, e error) bool {
+		if e != nil {
+			msg.Checks = append(msg.Checks, failoverStep{Description: description, Status: failoverFailed, Error: e.Error()})
+			return false
+		}
+		msg.Checks = append(msg.Checks, failoverStep{Description: description, Status: failoverOK})
+		return true
+	}
+
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize admin connection.")
+
+	serverInfo, e := client.ServerInfo(ctx)
+	if !check(fmt.Sprintf("Site `%s` is reachable", aliasedURL), e) {
+		return msg
+	}
+	info, e := client.SiteReplicationInfo(ctx)
+	if e == nil && !info.Enabled {
+		e = fmt.Errorf("site replication is not enabled")
+	}
+	if !check(fmt.Sprintf("Site replication is enabled on `%s`", aliasedURL), e) {
+		return msg
+	}
+
+	var failed madmin.PeerInfo
+	var survivors []madmin.PeerInfo
+	for _, site := range info.Sites {
+		if site.Name == failedSite || site.DeploymentID == failedSite {
+			failed = site
+		} else {
+			survivors = append(survivors, site)
+		}
+	}
+	e = nil
+	if failed.DeploymentID == "" {
+		e = fmt.Errorf("no such site, the sites are %s", srSiteNames(info.Sites))
+	}
+	if !check(fmt.Sprintf("Site `%s` is part of site replication", failedSite), e) {
+		return msg
+	}
+	e = nil
+	if failed.DeploymentID == serverInfo.DeploymentID {
+		e = fmt.Errorf("run the failover from one of the other sites")
+	}
+	if !check(fmt.Sprintf("Site `%s` is not the failed site", aliasedURL), e) {
+		return msg
+	}
+
+	// Removing a site that is still up splits the data between sites
+	// which are no longer replicated, warn about it.
+	alias, _, _ := mustExpandAlias(aliasedURL)
+	if srSiteHealthy(ctx, alias, failed.Endpoint) {
+		msg.Checks = append(msg.Checks, failoverStep{
+			Description: fmt.Sprintf("Site `%s` (%s) still answers health checks, make sure clients no longer write to it", failed.Name, failed.Endpoint),
+			Status:      failoverWarning,
+		})
+	} else {
+		check(fmt.Sprintf("Site `%s` (%s) is down", failed.Name, failed.Endpoint), nil)
+	}
+
+	msg.Plan = append(msg.Plan, failoverStep{
+		Description: fmt.Sprintf("Remove site `%s` from the site replication of `%s`", failed.Name, aliasedURL),
+		Status:      failoverPlanned,
+		run: func(ctx context.Context) error {
+			st, e := client.SiteReplicationRemove(ctx, madmin.SRRemoveReq{SiteNames: []string{failed.Name}})
+			if e == nil && st.ErrDetail != "" {
+				e = fmt.Errorf("%s", st.ErrDetail)
+			}
+			return e
+		},
+	})
+
+	survivorURL := client.GetEndpointURL().String()
+	for _, alias := range srAliasesOf(failed.Endpoint) {
+		alias := alias
+		step := failoverStep{
+			Description: fmt.Sprintf("Point the alias `%s` at %s", alias, survivorURL),
+			Status:      failoverManual,
+		}
+		if repointAliases {
+			step.Status = failoverPlanned
+			step.run = func(context.Context) error {
+				return repointAlias(alias, survivorURL)
+			}
+		}
+		msg.Plan = append(msg.Plan, step)
+	}
+
+	endpoints := make([]string, 0, len(survivors))
+	for _, site := range survivors {
+		endpoints = append(endpoints, site.Endpoint)
+	}
+	sort.Strings(endpoints)
+	msg.Plan = append(msg.Plan, failoverStep{
+		Description: fmt.Sprintf("Point the DNS records and load balancers of %s at %s", srHost(failed.Endpoint), strings.Join(endpoints, ", ")),
+		Status:      failoverManual,
+	}, failoverStep{
+		Description: fmt.Sprintf("Once `%s` is back, re-add it with 'mc admin replicate failover --recover %s ALIAS', ALIAS pointing at %s", failed.Name, aliasedURL, failed.Endpoint),
+		Status:      failoverManual,
+	})
+	return msg
+}
+
+// planSiteRecovery checks that recoveredAlias can be added back to the site
+// replication of the surviving aliases and returns the plan to do it.
+func planSiteRecovery(ctx context.Context, aliases []string, recoveredAlias string) (msg failoverMessage) {
+	msg.Op = "recover"
+	check := func(description string, e error) bool {
+		if e != nil {
+			msg.Checks = append(msg.Checks, failoverStep{Description: description, Status: failoverFailed, Error: e.Error()})
+			return false
+		}
+		msg.Checks = append(msg.Checks, failoverStep{Description: description, Status: failoverOK})
+		return true
+	}
+
+	clients := make(map[string]*madmin.AdminClient, len(aliases)+1)
+	deploymentIDs := make(map[string]string, len(aliases)+1)
+	for _, alias := range append(append([]string{}, aliases...), recoveredAlias) {
+		client, err := newAdminClient(alias)
+		fatalIf(err.Trace(alias), "Unable to initialize admin connection.")
+		serverInfo, e := client.ServerInfo(ctx)
+		if !check(fmt.Sprintf("Site `%s` is reachable", alias), e) {
+			return msg
+		}
+		clients[alias], deploymentIDs[alias] = client, serverInfo.DeploymentID
+	}
+
+	client := clients[aliases[0]]
+	info, e := client.SiteReplicationInfo(ctx)
+	if e == nil && !info.Enabled {
+		e = fmt.Errorf("site replication is not enabled")
+	}
+	if !check(fmt.Sprintf("Site replication is enabled on `%s`", aliases[0]), e) {
+		return msg
+	}
+	members := make(map[string]bool, len(info.Sites))
+	for _, site := range info.Sites {
+		members[site.DeploymentID] = true
+	}
+	e = nil
+	var missing []string
+	for _, alias := range aliases {
+		if !members[deploymentIDs[alias]] {
+			missing = append(missing, alias)
+		}
+	}
+	if len(info.Sites) != len(aliases) || len(missing) > 0 {
+		e = fmt.Errorf("give an alias for each of the sites %s", srSiteNames(info.Sites))
+	}
+	if !check("All the surviving sites are given", e) {
+		return msg
+	}
+	e = nil
+	if members[deploymentIDs[recoveredAlias]] {
+		e = fmt.Errorf("already part of site replication")
+	}
+	if !check(fmt.Sprintf("Site `%s` is not part of site replication", recoveredAlias), e) {
+		return msg
+	}
+
+	recovered := clients[recoveredAlias]
+	stale, e := recovered.SiteReplicationInfo(ctx)
+	if !check(fmt.Sprintf("Site replication configuration of `%s` is readable", recoveredAlias), e) {
+		return msg
+	}
+	if stale.Enabled {
+		// The peer removal only clears the configuration of the recovered
+		// site, a removal of all sites would also clear the surviving ones.
+		msg.Plan = append(msg.Plan, failoverStep{
+			Description: fmt.Sprintf("Clear the stale site replication configuration of `%s`", recoveredAlias),
+			Status:      failoverPlanned,
+			run: func(ctx context.Context) error {
+				_, e := recovered.SRPeerRemove(ctx, madmin.SRRemoveReq{RemoveAll: true})
+				return e
+			},
+		})
+	}
+
+	msg.Plan = append(msg.Plan, failoverStep{
+		Description: fmt.Sprintf("Add `%s` to the site replication of %s", recoveredAlias, strings.Join(aliases, ", ")),
+		Status:      failoverPlanned,
+		run: func(ctx context.Context) error {
+			sites := make([]madmin.PeerSite, 0, len(aliases)+1)
+			for _, alias := range append(append([]string{}, aliases...), recoveredAlias) {
+				accessKey, secretKey := clients[alias].GetAccessAndSecretKey()
+				sites = append(sites, madmin.PeerSite{
+					Name:      alias,
+					Endpoint:  clients[alias].GetEndpointURL().String(),
+					AccessKey: accessKey,
+					SecretKey: secretKey,
+				})
+			}
+			st, e := client.SiteReplicationAdd(ctx, sites)
+			if e == nil && st.ErrDetail != "" {
+				e = fmt.Errorf("%s", st.ErrDetail)
+			}
+			return e
+		},
+	}, failoverStep{
+		Description: fmt.Sprintf("Resync the buckets of `%s` to `%s`", aliases[0], recoveredAlias),
+		Status:      failoverPlanned,
+		run: func(ctx context.Context) error {
+			info, e := client.SiteReplicationInfo(ctx)
+			if e != nil {
+				return e
+			}
+			var peer madmin.PeerInfo
+			for _, site := range info.Sites {
+				if site.DeploymentID == deploymentIDs[recoveredAlias] {
+					peer = site
+				}
+			}
+			if peer.DeploymentID == "" {
+				return fmt.Errorf("site %s not found after being added", recoveredAlias)
+			}
+			st, e := client.SiteReplicationResyncOp(ctx, peer, madmin.SiteResyncStart)
+			if e == nil && st.ErrDetail != "" {
+				e = fmt.Errorf("%s", st.ErrDetail)
+			}
+			return e
+		},
+	}, failoverStep{
+		Description: fmt.Sprintf("Follow the resync with 'mc admin replicate resync status %s %s' and point the DNS records back once it completes", aliases[0], recoveredAlias),
+		Status:      failoverManual,
+	})
+	return msg
+}
+
+// srSiteNames returns the sorted names of the sites.
+func srSiteNames(sites []madmin.PeerInfo) string {
+	names := make([]string, 0, len(sites))
+	for _, site := range sites {
+		names = append(names, site.Name)
+	}
+	sort.Strings(names)
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// srHost returns the host of endpoint, endpoint itself if not a URL.
+func srHost(endpoint string) string {
+	if u, e := url.Parse(endpoint); e == nil && u.Host != "" {
+		return u.Host
+	}
+	return endpoint
+}
+
+// srSiteHealthy returns true if the site at endpoint answers health checks.
+// The TLS settings are the ones of the alias of the site, or of alias
+// when the site has none.
+func srSiteHealthy(ctx context.Context, alias, endpoint string) bool {
+	if aliases := srAliasesOf(endpoint); len(aliases) > 0 {
+		alias = aliases[0]
+	}
+	anonClient, err := newAnonymousClientFromConfig(NewS3Config(endpoint, mustGetHostConfig(alias)))
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	result, e := anonClient.Healthy(ctx, madmin.HealthOpts{})
+	return e == nil && result.Healthy
+}
+
+// srAliasesOf returns the local aliases pointing at the host of endpoint.
+func srAliasesOf(endpoint string) []string {
+	mcCfg, err := loadMcConfig()
+	if err != nil {
+		return nil
+	}
+	host := srHost(endpoint)
+	var aliases []string
+	for alias, aliasCfg := range mcCfg.Aliases {
+		if srHost(aliasCfg.URL) == host {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// repointAlias changes the URL of alias, keeping all its other settings.
+func repointAlias(alias, url string) error {
+	mcCfg, err := loadMcConfig()
+	if err != nil {
+		return err.ToGoError()
+	}
+	aliasCfg, ok := mcCfg.Aliases[alias]
+	if !ok {
+		return fmt.Errorf("alias %s not found", alias)
+	}
+	aliasCfg.URL = url
+	mcCfg.Aliases[alias] = aliasCfg
+	if err = saveMcConfig(mcCfg); err != nil {
+		return err.ToGoError()
+	}
+	return nil
+}
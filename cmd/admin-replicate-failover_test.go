@@ -0,0 +1,92 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestSRHost(t *testing.T) {
+	testCases := []struct {
+		endpoint, host string
+	}{
+		{"https://site1.example.com:9000", "site1.example.com:9000"},
+		{"http://site2.example.com/", "site2.example.com"},
+		{"site3.example.com:9000", "site3.example.com:9000"},
+	}
+	for i, testCase := range testCases {
+		if host := srHost(testCase.endpoint); host != testCase.host {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.host, host)
+		}
+	}
+}
+
+// The health checks of the sites trust the CAs of their aliases.
+func TestSRSiteHealthy(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if e := os.WriteFile(caBundle, ca, 0o600); e != nil {
+		t.Fatal(e)
+	}
+
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	defer func(insecure bool) { globalInsecure = insecure }(globalInsecure)
+	var aliases map[string]aliasConfigV10
+	loadMcConfig = func() (*configV10, *probe.Error) {
+		conf := newConfigV10()
+		for alias, aliasCfg := range aliases {
+			conf.Aliases[alias] = aliasCfg
+		}
+		return conf, nil
+	}
+
+	testCases := []struct {
+		aliases  map[string]aliasConfigV10
+		insecure bool
+		healthy  bool
+	}{
+		// The CA of the alias of the command is used for sites without alias.
+		{map[string]aliasConfigV10{"site1": {URL: "https://site1.example.com", CABundle: caBundle}}, false, true},
+		{map[string]aliasConfigV10{"site1": {URL: "https://site1.example.com"}}, false, false},
+		{map[string]aliasConfigV10{"site1": {URL: "https://site1.example.com"}}, true, true},
+		// The alias of the site takes precedence.
+		{map[string]aliasConfigV10{"site1": {URL: "https://site1.example.com"}, "site2": {URL: server.URL, CABundle: caBundle}}, false, true},
+	}
+	for i, testCase := range testCases {
+		aliases = testCase.aliases
+		globalInsecure = testCase.insecure
+		if healthy := srSiteHealthy(context.Background(), "site1", server.URL); healthy != testCase.healthy {
+			t.Errorf("Test %d: expected %t, got %t", i+1, testCase.healthy, healthy)
+		}
+	}
+
+	if got := srAliasesOf(server.URL); !reflect.DeepEqual(got, []string{"site2"}) {
+		t.Errorf("expected [site2], got %v", got)
+	}
+}
@@ -21,7 +21,11 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
@@ -67,6 +71,23 @@ var adminReplicateStatusFlags = []cli.Flag{
 		Name:  "group",
 		Usage: "display group sync status",
 	},
+	cli.BoolFlag{
+		Name:  "unhealthy-only",
+		Usage: "display only the entities and settings out of sync",
+	},
+	cli.BoolFlag{
+		Name:  "divergence",
+		Usage: "list the settings out of sync of every entity, site by site",
+	},
+	cli.BoolFlag{
+		Name:  "watch, w",
+		Usage: "refresh the status in place until interrupted",
+	},
+	cli.DurationFlag{
+		Name:  "interval",
+		Usage: "refresh interval in watch mode",
+		Value: 5 * time.Second,
+	},
 }
 
 // Some cell values
@@ -106,16 +127,31 @@ EXAMPLES:
 
     4. Drill down and view site replication status of user "foo"
        {{.Prompt}} {{.HelpName}} minio1 --user foo
+
+    5. List the buckets, policies, users and groups out of sync and the settings that diverge on each site
+       {{.Prompt}} {{.HelpName}} minio1 --unhealthy-only --divergence
+
+    6. Watch the site replication status of buckets, refreshing every 10 seconds
+       {{.Prompt}} {{.HelpName}} minio1 --buckets --watch --interval 10s
 `,
 }
 
 type srStatus struct {
 	madmin.SRStatusInfo
-	opts madmin.SRStatusOptions
+	opts          madmin.SRStatusOptions
+	unhealthyOnly bool
+	divergence    bool
 }
 
 func (i srStatus) JSON() string {
-	bs, e := json.MarshalIndent(madmin.SRStatusInfo(i.SRStatusInfo), "", " ")
+	var v interface{} = i.SRStatusInfo
+	if i.divergence {
+		v = struct {
+			madmin.SRStatusInfo
+			Divergences []srDivergence `json:"divergences"`
+		}{i.SRStatusInfo, srDivergences(i.SRStatusInfo)}
+	}
+	bs, e := json.MarshalIndent(v, "", " ")
 	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
 	return string(bs)
 }
@@ -151,7 +187,7 @@ func (i srStatus) String() string {
 		legendFields = append(legendFields, Field{"sname", 15})
 	}
 
-	if i.opts.Buckets {
+	if i.opts.Buckets && !(i.unhealthyOnly && len(info.BucketStats) == 0) {
 		messages = append(messages,
 			console.Colorize("SummaryHdr", "Bucket replication status:"))
 		switch {
@@ -188,7 +224,7 @@ func (i srStatus) String() string {
 			}
 		}
 	}
-	if i.opts.Policies {
+	if i.opts.Policies && !(i.unhealthyOnly && len(i.PolicyStats) == 0) {
 		messages = append(messages,
 			console.Colorize("SummaryHdr", "Policy replication status:"))
 		switch {
@@ -227,7 +263,7 @@ func (i srStatus) String() string {
 			}
 		}
 	}
-	if i.opts.Users {
+	if i.opts.Users && !(i.unhealthyOnly && len(i.UserStats) == 0) {
 		messages = append(messages,
 			console.Colorize("SummaryHdr", "User replication status:"))
 		switch {
@@ -268,7 +304,7 @@ func (i srStatus) String() string {
 
 		}
 	}
-	if i.opts.Groups {
+	if i.opts.Groups && !(i.unhealthyOnly && len(i.GroupStats) == 0) {
 		messages = append(messages,
 			console.Colorize("SummaryHdr", "Group replication status:"))
 		switch {
@@ -320,6 +356,13 @@ func (i srStatus) String() string {
 
 	}
 
+	if i.unhealthyOnly && len(messages) == 0 {
+		messages = append(messages, console.Colorize("Summary", "All entities in sync\n"))
+	}
+	if i.divergence {
+		messages = append(messages, i.divergenceList()...)
+	}
+
 	return console.Colorize("UserMessage", strings.Join(messages, "\n"))
 }
 
@@ -335,6 +378,37 @@ func (i srStatus) siteHeader(siteNames []string, legend string) string {
 	).buildRow(legendHdr...))
 }
 
+// filterRows returns the rows of a drill-down, only those out of sync
+// with --unhealthy-only.
+func (i srStatus) filterRows(rows []string, mismatches []bool) []string {
+	if !i.unhealthyOnly {
+		return rows
+	}
+	var unhealthy []string
+	for r, row := range rows {
+		if mismatches[r] {
+			unhealthy = append(unhealthy, row)
+		}
+	}
+	if len(unhealthy) == 0 {
+		unhealthy = append(unhealthy, console.Colorize("Summary", "All settings in sync"))
+	}
+	return unhealthy
+}
+
+// divergenceList lists the settings out of sync of every entity.
+func (i srStatus) divergenceList() []string {
+	divergences := srDivergences(i.SRStatusInfo)
+	messages := []string{console.Colorize("SummaryHdr", "Divergence:")}
+	if len(divergences) == 0 {
+		return append(messages, console.Colorize("Summary", "No divergence found\n"))
+	}
+	for _, d := range divergences {
+		messages = append(messages, console.Colorize("WarningMessage", d.String()))
+	}
+	return append(messages, "")
+}
+
 func (i srStatus) getTheme(match bool) string {
 	theme := "UserMessage"
 	if !match {
@@ -385,6 +459,7 @@ func (i srStatus) getBucketStatusSummary(siteNames []string, nameIDMap map[strin
 		}
 	}
 	rows := make([]string, len(rowLegend))
+	mismatches := make([]bool, len(rowLegend))
 	for j, sname := range siteNames {
 		dID := nameIDMap[sname]
 		ss := i.SRStatusInfo.BucketStats[i.opts.EntityValue][dID]
@@ -395,26 +470,32 @@ func (i srStatus) getBucketStatusSummary(siteNames []string, nameIDMap map[strin
 				theme, msgStr = syncStatus(ss.TagMismatch, ss.HasTagsSet)
 				tags = append(tags, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			case 1:
 				theme, msgStr = syncStatus(ss.PolicyMismatch, ss.HasPolicySet)
 				bpolicies = append(bpolicies, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			case 2:
 				theme, msgStr = syncStatus(ss.QuotaCfgMismatch, ss.HasQuotaCfgSet)
 				quota = append(quota, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			case 3:
 				theme, msgStr = syncStatus(ss.OLockConfigMismatch, ss.HasOLockConfigSet)
 				retention = append(retention, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			case 4:
 				theme, msgStr = syncStatus(ss.SSEConfigMismatch, ss.HasSSECfgSet)
 				encryption = append(encryption, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			case 5:
 				theme, msgStr = syncStatus(ss.ReplicationCfgMismatch, ss.HasReplicationCfg)
 				replication = append(replication, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 
 			}
 		}
@@ -442,7 +523,7 @@ func (i srStatus) getBucketStatusSummary(siteNames []string, nameIDMap map[strin
 
 		}
 	}
-	messages = append(messages, rows...)
+	messages = append(messages, i.filterRows(rows, mismatches)...)
 	return messages
 }
 
@@ -469,6 +550,7 @@ func (i srStatus) getPolicyStatusSummary(siteNames []string, nameIDMap map[strin
 	detailFields[0][0] = Field{"Entity", 15}
 	policies = append(policies, "Policy")
 	rows := make([]string, len(rowLegend))
+	mismatches := make([]bool, len(rowLegend))
 	for j, sname := range siteNames {
 		dID := nameIDMap[sname]
 		ss := i.SRStatusInfo.PolicyStats[i.opts.EntityValue][dID]
@@ -479,6 +561,7 @@ func (i srStatus) getPolicyStatusSummary(siteNames []string, nameIDMap map[strin
 				theme, msgStr = syncStatus(ss.PolicyMismatch, ss.HasPolicy)
 				policies = append(policies, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			}
 		}
 	}
@@ -494,7 +577,7 @@ func (i srStatus) getPolicyStatusSummary(siteNames []string, nameIDMap map[strin
 	siteHdr := i.siteHeader(siteNames, legend)
 	messages = append(messages, siteHdr)
 
-	messages = append(messages, rows...)
+	messages = append(messages, i.filterRows(rows, mismatches)...)
 	return messages
 }
 
@@ -528,6 +611,7 @@ func (i srStatus) getUserStatusSummary(siteNames []string, nameIDMap map[string]
 		}
 	}
 	rows := make([]string, len(rowLegend))
+	mismatches := make([]bool, len(rowLegend))
 	for j, sname := range siteNames {
 		dID := nameIDMap[sname]
 		ss := i.SRStatusInfo.UserStats[i.opts.EntityValue][dID]
@@ -538,10 +622,12 @@ func (i srStatus) getUserStatusSummary(siteNames []string, nameIDMap map[string]
 				theme, msgStr = syncStatus(ss.UserInfoMismatch, ss.HasUser)
 				users = append(users, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			case 1:
 				theme, msgStr = syncStatus(ss.PolicyMismatch, ss.HasPolicyMapping)
 				policyMapping = append(policyMapping, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			}
 		}
 	}
@@ -560,7 +646,7 @@ func (i srStatus) getUserStatusSummary(siteNames []string, nameIDMap map[string]
 	siteHdr := i.siteHeader(siteNames, legend)
 	messages = append(messages, siteHdr)
 
-	messages = append(messages, rows...)
+	messages = append(messages, i.filterRows(rows, mismatches)...)
 	return messages
 }
 
@@ -593,6 +679,7 @@ func (i srStatus) getGroupStatusSummary(siteNames []string, nameIDMap map[string
 		}
 	}
 	rows := make([]string, len(rowLegend))
+	mismatches := make([]bool, len(rowLegend))
 	// b := i.opts.EntityValue
 	for j, sname := range siteNames {
 		dID := nameIDMap[sname]
@@ -605,10 +692,12 @@ func (i srStatus) getGroupStatusSummary(siteNames []string, nameIDMap map[string
 				theme, msgStr = syncStatus(ss.GroupDescMismatch, ss.HasGroup)
 				groups = append(groups, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			case 1:
 				theme, msgStr = syncStatus(ss.PolicyMismatch, ss.HasPolicyMapping)
 				policyMapping = append(policyMapping, msgStr)
 				detailFields[r][j+1] = Field{theme, fieldLen}
+				mismatches[r] = mismatches[r] || msgStr == crossTickCell
 			}
 		}
 	}
@@ -627,7 +716,7 @@ func (i srStatus) getGroupStatusSummary(siteNames []string, nameIDMap map[string
 	siteHdr := i.siteHeader(siteNames, legend)
 	messages = append(messages, siteHdr)
 
-	messages = append(messages, rows...)
+	messages = append(messages, i.filterRows(rows, mismatches)...)
 	return messages
 }
 
@@ -700,15 +789,254 @@ func mainAdminReplicationStatus(ctx *cli.Context) error {
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 	opts := srStatusOpts(ctx)
-	info, e := client.SRStatusInfo(globalContext, opts)
+	getStatus := func() (srStatus, error) {
+		info, e := client.SRStatusInfo(globalContext, opts)
+		if e != nil {
+			return srStatus{}, e
+		}
+		unhealthyOnly := ctx.Bool("unhealthy-only")
+		if unhealthyOnly && !opts.IsEntitySet() {
+			info = srUnhealthyOnly(info)
+		}
+		return srStatus{
+			SRStatusInfo:  info,
+			opts:          opts,
+			unhealthyOnly: unhealthyOnly,
+			divergence:    ctx.Bool("divergence"),
+		}, nil
+	}
+
+	if ctx.Bool("watch") {
+		watchSRStatus(getStatus, ctx.Duration("interval"))
+		return nil
+	}
+
+	status, e := getStatus()
 	fatalIf(probe.NewError(e).Trace(args...), "Unable to get cluster replication status")
+	printMsg(status)
 
-	printMsg(srStatus{
-		SRStatusInfo: info,
-		opts:         opts,
+	return nil
+}
+
+// watchSRStatus refreshes the site replication status every interval until
+// interrupted. With --json, every status is printed on its own line.
+func watchSRStatus(getStatus func() (srStatus, error), interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if globalJSON {
+		for {
+			status, e := getStatus()
+			fatalIf(probe.NewError(e), "Unable to get cluster replication status")
+			printMsg(status)
+			select {
+			case <-globalContext.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}
+
+	ui := tea.NewProgram(initSRStatusUI())
+	go func() {
+		for {
+			status, e := getStatus()
+			if e != nil {
+				ui.Send(e)
+				return
+			}
+			ui.Send(status)
+			select {
+			case <-globalContext.Done():
+				ui.Quit()
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	m, e := ui.Run()
+	fatalIf(probe.NewError(e), "Unable to get cluster replication status")
+	if e = m.(*srStatusUI).err; e != nil {
+		fatalIf(probe.NewError(e), "Unable to get cluster replication status")
+	}
+}
+
+func initSRStatusUI() *srStatusUI {
+	s := spinner.New()
+	s.Spinner = spinner.Points
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return &srStatusUI{spinner: s}
+}
+
+type srStatusUI struct {
+	spinner  spinner.Model
+	current  *srStatus
+	updated  time.Time
+	err      error
+	quitting bool
+}
+
+func (m *srStatusUI) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m *srStatusUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			m.quitting = true
+			return m, tea.Quit
+		default:
+			return m, nil
+		}
+	case srStatus:
+		m.current = &msg
+		m.updated = time.Now()
+		return m, nil
+	case error:
+		m.err = msg
+		m.quitting = true
+		return m, tea.Quit
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	default:
+		return m, nil
+	}
+}
+
+func (m *srStatusUI) View() string {
+	var s strings.Builder
+	if !m.quitting {
+		s.WriteString(m.spinner.View())
+	}
+	if m.current == nil {
+		s.WriteString("\n")
+		return s.String()
+	}
+	s.WriteString(" Updated " + m.updated.Format("15:04:05") + "\n")
+	s.WriteString(m.current.String())
+	s.WriteString("\n")
+	return s.String()
+}
+
+// srDivergence is an entity out of sync on a site.
+type srDivergence struct {
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	Site       string   `json:"site"`
+	Missing    bool     `json:"missing,omitempty"`
+	Mismatches []string `json:"mismatches,omitempty"`
+}
+
+func (d srDivergence) String() string {
+	what := strings.Join(d.Mismatches, ", ")
+	if d.Missing {
+		what = "missing"
+	}
+	return fmt.Sprintf("%s%s `%s` on %s: %s", crossTickCell, d.Type, d.Name, d.Site, what)
+}
+
+// srDivergences returns the entities out of sync of info, sorted by type,
+// name and site.
+func srDivergences(info madmin.SRStatusInfo) []srDivergence {
+	siteName := func(deploymentID string) string {
+		if peer, ok := info.Sites[deploymentID]; ok && peer.Name != "" {
+			return peer.Name
+		}
+		return deploymentID
+	}
+	var divergences []srDivergence
+	add := func(typ, name, deploymentID string, missing bool, mismatches map[string]bool) {
+		d := srDivergence{Type: typ, Name: name, Site: siteName(deploymentID), Missing: missing}
+		for _, setting := range []string{"info", "tags", "versioning", "object lock", "policy", "policy mapping", "encryption", "replication", "quota", "deleted"} {
+			if mismatches[setting] {
+				d.Mismatches = append(d.Mismatches, setting)
+			}
+		}
+		if d.Missing || len(d.Mismatches) > 0 {
+			divergences = append(divergences, d)
+		}
+	}
+	for bucket, sites := range info.BucketStats {
+		for dID, ss := range sites {
+			add("bucket", bucket, dID, !ss.HasBucket && !ss.BucketMarkedDeleted, map[string]bool{
+				"tags":        ss.TagMismatch,
+				"versioning":  ss.VersioningConfigMismatch,
+				"object lock": ss.OLockConfigMismatch,
+				"policy":      ss.PolicyMismatch,
+				"encryption":  ss.SSEConfigMismatch,
+				"replication": ss.ReplicationCfgMismatch,
+				"quota":       ss.QuotaCfgMismatch,
+				"deleted":     ss.BucketMarkedDeleted,
+			})
+		}
+	}
+	for policy, sites := range info.PolicyStats {
+		for dID, ss := range sites {
+			add("policy", policy, dID, !ss.HasPolicy, map[string]bool{"policy": ss.PolicyMismatch})
+		}
+	}
+	for user, sites := range info.UserStats {
+		for dID, ss := range sites {
+			add("user", user, dID, !ss.HasUser, map[string]bool{
+				"info":           ss.UserInfoMismatch,
+				"policy mapping": ss.PolicyMismatch,
+			})
+		}
+	}
+	for group, sites := range info.GroupStats {
+		for dID, ss := range sites {
+			add("group", group, dID, !ss.HasGroup, map[string]bool{
+				"info":           ss.GroupDescMismatch,
+				"policy mapping": ss.PolicyMismatch,
+			})
+		}
+	}
+
+	order := map[string]int{"bucket": 0, "policy": 1, "user": 2, "group": 3}
+	sort.Slice(divergences, func(i, j int) bool {
+		a, b := divergences[i], divergences[j]
+		switch {
+		case a.Type != b.Type:
+			return order[a.Type] < order[b.Type]
+		case a.Name != b.Name:
+			return a.Name < b.Name
+		}
+		return a.Site < b.Site
 	})
+	return divergences
+}
 
-	return nil
+// srUnhealthyOnly removes the entities in sync on all sites from info.
+func srUnhealthyOnly(info madmin.SRStatusInfo) madmin.SRStatusInfo {
+	unhealthy := make(map[string]bool)
+	for _, d := range srDivergences(info) {
+		unhealthy[d.Type+"/"+d.Name] = true
+	}
+	for bucket := range info.BucketStats {
+		if !unhealthy["bucket/"+bucket] {
+			delete(info.BucketStats, bucket)
+		}
+	}
+	for policy := range info.PolicyStats {
+		if !unhealthy["policy/"+policy] {
+			delete(info.PolicyStats, policy)
+		}
+	}
+	for user := range info.UserStats {
+		if !unhealthy["user/"+user] {
+			delete(info.UserStats, user)
+		}
+	}
+	for group := range info.GroupStats {
+		if !unhealthy["group/"+group] {
+			delete(info.GroupStats, group)
+		}
+	}
+	return info
 }
 
 func syncStatus(mismatch, set bool) (string, string) {
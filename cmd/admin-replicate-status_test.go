@@ -0,0 +1,69 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestSRDivergences(t *testing.T) {
+	info := madmin.SRStatusInfo{
+		Enabled: true,
+		Sites: map[string]madmin.PeerInfo{
+			"d1": {Name: "site1"},
+			"d2": {Name: "site2"},
+		},
+		BucketStats: map[string]map[string]madmin.SRBucketStatsSummary{
+			"photos": {
+				"d1": {HasBucket: true},
+				"d2": {HasBucket: true, TagMismatch: true, QuotaCfgMismatch: true},
+			},
+			"logs": {
+				"d1": {HasBucket: true},
+				"d2": {HasBucket: true},
+			},
+		},
+		UserStats: map[string]map[string]madmin.SRUserStatsSummary{
+			"alice": {
+				"d1": {HasUser: true},
+				"d2": {},
+			},
+		},
+	}
+
+	want := []srDivergence{
+		{Type: "bucket", Name: "photos", Site: "site2", Mismatches: []string{"tags", "quota"}},
+		{Type: "user", Name: "alice", Site: "site2", Missing: true},
+	}
+	if got := srDivergences(info); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	info = srUnhealthyOnly(info)
+	if _, ok := info.BucketStats["logs"]; ok {
+		t.Fatal("bucket in sync not removed")
+	}
+	if _, ok := info.BucketStats["photos"]; !ok {
+		t.Fatal("bucket out of sync removed")
+	}
+	if _, ok := info.UserStats["alice"]; !ok {
+		t.Fatal("user out of sync removed")
+	}
+}
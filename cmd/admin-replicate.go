@@ -26,6 +26,7 @@ var adminReplicateSubcommands = []cli.Command{
 	adminReplicateInfoCmd,
 	adminReplicateStatusCmd,
 	adminReplicateResyncCmd,
+	adminReplicateFailoverCmd,
 }
 
 var adminReplicateCmd = cli.Command{
@@ -18,45 +18,73 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
 	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/madmin-go/v2"
 	"github.com/minio/pkg/console"
 )
 
+var adminServiceFreezeFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "for",
+		Usage: "unfreeze after the duration (e.g. 5m) or when interrupted, sent by mc which must keep running",
+	},
+}
+
 var adminServiceFreezeCmd = cli.Command{
 	Name:         "freeze",
 	Usage:        "freeze S3 API calls on MinIO cluster",
 	Action:       mainAdminServiceFreeze,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
-	Hidden:       true, // this command is hidden on purpose, please do not enable it.
+	Flags:        append(adminServiceFreezeFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
   {{.HelpName}} TARGET
 
+  Incoming S3 API calls wait until the cluster is unfrozen. Without --for the cluster stays frozen
+  until 'mc admin service unfreeze'. With --for, {{.HelpName}} keeps running and unfreezes the
+  cluster once the duration elapsed, or earlier when interrupted.
+
+  NOTE: The server has no deadline for a freeze, the unfreeze of --for is sent by mc. If mc is
+  killed, crashes or loses the connection to the cluster before, the cluster stays frozen until
+  'mc admin service unfreeze'.
+
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 EXAMPLES:
   1. Freeze all S3 API calls on MinIO server at 'myminio/'.
      {{.Prompt}} {{.HelpName}} myminio/
+
+  2. Freeze all S3 API calls on MinIO server at 'myminio/' for at most 5 minutes, press Ctrl-C to unfreeze earlier.
+     {{.Prompt}} {{.HelpName}} --for 5m myminio/
 `,
 }
 
 // serviceFreezeCommand is container for service freeze command success and failure messages.
 type serviceFreezeCommand struct {
-	Status    string `json:"status"`
-	ServerURL string `json:"serverURL"`
+	Status    string    `json:"status"`
+	ServerURL string    `json:"serverURL"`
+	Until     time.Time `json:"until,omitempty"`
 }
 
 // String colorized service freeze command message.
 func (s serviceFreezeCommand) String() string {
-	return console.Colorize("ServiceFreeze", "Freeze command successfully sent to `"+s.ServerURL+"`.")
+	msg := "Freeze command successfully sent to `" + s.ServerURL + "`."
+	if !s.Until.IsZero() {
+		msg += fmt.Sprintf(" Unfreezing at %s if mc keeps running, press Ctrl-C to unfreeze earlier.", s.Until.Local().Format(printDate))
+	}
+	return console.Colorize("ServiceFreeze", msg)
 }
 
 // JSON jsonified service freeze command message.
@@ -72,6 +100,13 @@ func checkAdminServiceFreezeSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
+	if ctx.IsSet("for") {
+		d, e := time.ParseDuration(ctx.String("for"))
+		fatalIf(probe.NewError(e), "Unable to parse --for argument.")
+		if d <= 0 {
+			fatalIf(errInvalidArgument().Trace(ctx.String("for")), "--for requires a positive duration.")
+		}
+	}
 }
 
 func mainAdminServiceFreeze(ctx *cli.Context) error {
@@ -81,6 +116,7 @@ func mainAdminServiceFreeze(ctx *cli.Context) error {
 	// Set color.
 	console.SetColor("ServiceFreeze", color.New(color.FgGreen, color.Bold))
 	console.SetColor("FailedServiceFreeze", color.New(color.FgRed, color.Bold))
+	console.SetColor("ServiceUnfreeze", color.New(color.FgGreen, color.Bold))
 
 	// Get the alias parameter from cli
 	args := ctx.Args()
@@ -89,11 +125,49 @@ func mainAdminServiceFreeze(ctx *cli.Context) error {
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 
-	// Freeze the specified MinIO server
-	fatalIf(probe.NewError(client.ServiceFreeze(globalContext)), "Unable to freeze the server.")
+	if !ctx.IsSet("for") {
+		// Freeze the specified MinIO server
+		fatalIf(probe.NewError(client.ServiceFreeze(globalContext)), "Unable to freeze the server.")
 
-	// Success..
-	printMsg(serviceFreezeCommand{Status: "success", ServerURL: aliasedURL})
+		// Success..
+		printMsg(serviceFreezeCommand{Status: "success", ServerURL: aliasedURL})
+		return nil
+	}
 
+	d, _ := time.ParseDuration(ctx.String("for"))
+	fatalIf(freezeServerFor(globalContext, client, aliasedURL, d), "Unable to freeze the server.")
+	return nil
+}
+
+// freezeServerFor freezes the server until the duration elapsed or the
+// context is canceled. The server is unfrozen by this process on any exit
+// of mc once it was frozen, it stays frozen if mc is killed.
+func freezeServerFor(ctx context.Context, client *madmin.AdminClient, aliasedURL string, d time.Duration) *probe.Error {
+	var once sync.Once
+	unfreeze := func(int) {
+		once.Do(func() {
+			uctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if e := client.ServiceUnfreeze(uctx); e != nil {
+				errorIf(probe.NewError(e), "Unable to unfreeze the server, run `mc admin service unfreeze %s`.", aliasedURL)
+				return
+			}
+			printMsg(serviceUnfreezeCommand{Status: "success", ServerURL: aliasedURL})
+		})
+	}
+	onExit(unfreeze)
+
+	if e := client.ServiceFreeze(ctx); e != nil {
+		return probe.NewError(e)
+	}
+	printMsg(serviceFreezeCommand{Status: "success", ServerURL: aliasedURL, Until: time.Now().Add(d)})
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	unfreeze(0)
 	return nil
 }
@@ -0,0 +1,74 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFreezeServerFor(t *testing.T) {
+	var mu sync.Mutex
+	var actions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		actions = append(actions, r.URL.Query().Get("action"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	client, err := newAdminClient("myminio")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The server is unfrozen once the duration elapsed.
+	start := time.Now()
+	if err = freezeServerFor(context.Background(), client, "myminio", 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected the server to stay frozen for the duration, unfrozen after %v", elapsed)
+	}
+	if !reflect.DeepEqual(actions, []string{"freeze", "unfreeze"}) {
+		t.Errorf("unexpected actions %v", actions)
+	}
+
+	// The server is unfrozen earlier when interrupted.
+	actions = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start = time.Now()
+	if err = freezeServerFor(ctx, client, "myminio", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the server to be unfrozen when interrupted, unfrozen after %v", elapsed)
+	}
+	if !reflect.DeepEqual(actions, []string{"freeze", "unfreeze"}) {
+		t.Errorf("unexpected actions %v", actions)
+	}
+}
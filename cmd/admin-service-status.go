@@ -0,0 +1,144 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var adminServiceStatusCmd = cli.Command{
+	Name:         "status",
+	Usage:        "show the state, uptime and version of the servers",
+	Action:       mainAdminServiceStatus,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(adminFleetFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET [TARGET...]
+
+  The aliases given as targets, as alias groups or with --all-aliases are queried concurrently,
+  and every server of every alias is shown.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the status of the servers of 'myminio/'.
+     {{.Prompt}} {{.HelpName}} myminio/
+
+  2. Compare the servers of all the configured aliases.
+     {{.Prompt}} {{.HelpName}} --all-aliases
+`,
+}
+
+// serviceStatusMessage is the status of a server of an alias, or the
+// error of an unreachable alias.
+type serviceStatusMessage struct {
+	Status   string `json:"status"`
+	Alias    string `json:"alias"`
+	Error    string `json:"error,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	State    string `json:"state,omitempty"`
+	Uptime   int64  `json:"uptime,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+func serviceStatusTable(stateTheme string) PrettyTable {
+	return newPrettyTable("  ",
+		Field{"FleetAlias", 16},
+		Field{"", 32},
+		Field{stateTheme, 11},
+		Field{"", 16},
+		Field{"", -1},
+	)
+}
+
+// Colorized message for console printing.
+func (s serviceStatusMessage) String() string {
+	if s.Error != "" {
+		return newPrettyTable("  ",
+			Field{"FleetAlias", 16},
+			Field{"FleetFail", -1},
+		).buildRow(s.Alias, s.Error)
+	}
+	stateTheme := "FleetHealthy"
+	uptime := humanize.RelTime(time.Now(), time.Now().Add(time.Duration(s.Uptime)*time.Second), "", "")
+	if s.State != string(madmin.ItemOnline) {
+		stateTheme, uptime = "FleetFail", "-"
+	}
+	return serviceStatusTable(stateTheme).buildRow(s.Alias, s.Endpoint, s.State, uptime, s.Version)
+}
+
+// JSON jsonified service status message.
+func (s serviceStatusMessage) JSON() string {
+	s.Status = "success"
+	if s.Error != "" {
+		s.Status = "error"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainAdminServiceStatus is the handle for "mc admin service status" command.
+func mainAdminServiceStatus(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 && !ctx.Bool("all-aliases") {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	aliases := adminFleetAliases(ctx)
+	if len(aliases) == 0 {
+		fatalIf(errDummy(), "No aliases are configured.")
+	}
+	setAdminFleetColors()
+
+	if !globalJSON {
+		console.Println(console.Colorize("FleetHeader", serviceStatusTable("").buildRow("ALIAS", "ENDPOINT", "STATE", "UPTIME", "VERSION")))
+	}
+	var unreachable int
+	for _, r := range fetchAdminFleetInfo(aliases) {
+		if r.err != nil {
+			unreachable++
+			printMsg(serviceStatusMessage{Alias: r.alias, Error: r.err.ToGoError().Error()})
+			continue
+		}
+		for _, srv := range r.info.Servers {
+			printMsg(serviceStatusMessage{
+				Alias:    r.alias,
+				Endpoint: srv.Endpoint,
+				State:    srv.State,
+				Uptime:   srv.Uptime,
+				Version:  srv.Version,
+			})
+		}
+	}
+	if unreachable > 0 {
+		fatalIf(errDummy(), fmt.Sprintf("Unable to get the status of %d of %d aliases.", unreachable, len(aliases)))
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ package cmd
 import "github.com/minio/cli"
 
 var adminServiceSubcommands = []cli.Command{
+	adminServiceStatusCmd,
 	adminServiceRestartCmd,
 	adminServiceStopCmd,
 	adminServiceUnfreezeCmd,
@@ -28,7 +29,7 @@ var adminServiceSubcommands = []cli.Command{
 
 var adminServiceCmd = cli.Command{
 	Name:            "service",
-	Usage:           "restart, stop and unfreeze a MinIO cluster",
+	Usage:           "show status, restart, stop, freeze and unfreeze a MinIO cluster",
 	Action:          mainAdminService,
 	Before:          setGlobalsFromContext,
 	Flags:           globalFlags,
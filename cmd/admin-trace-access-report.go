@@ -0,0 +1,143 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+// accessReportKey identifies a row of the access report.
+type accessReportKey struct {
+	accessKey string
+	bucket    string
+	apiClass  string
+}
+
+// accessReport aggregates traced S3 requests per access key, bucket and
+// class of API.
+type accessReport map[accessReportKey]*accessReportMessage
+
+// accessReportMessage is a row of the access report.
+type accessReportMessage struct {
+	Status    string   `json:"status"`
+	AccessKey string   `json:"accessKey"`
+	Bucket    string   `json:"bucket"`
+	APIClass  string   `json:"apiClass"`
+	Count     int64    `json:"count"`
+	Errors    int64    `json:"errors"`
+	APIs      []string `json:"apis"`
+}
+
+// Colorized message for console printing.
+func (m accessReportMessage) String() string {
+	bucket := m.Bucket
+	if bucket == "" {
+		bucket = "-"
+	}
+	return newPrettyTable("  ",
+		Field{"AccessKey", 20},
+		Field{"Bucket", 24},
+		Field{"APIClass", 6},
+		Field{"Count", 10},
+		Field{"ErrStatus", 10},
+		Field{"FuncName", -1},
+	).buildRow(m.AccessKey, bucket, m.APIClass, strconv.FormatInt(m.Count, 10), strconv.FormatInt(m.Errors, 10), strings.Join(m.APIs, ","))
+}
+
+// JSON'ified message for scripting.
+func (m accessReportMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// add counts a traced request, other calls than S3 are ignored.
+func (r accessReport) add(trace madmin.TraceInfo) {
+	if trace.TraceType != madmin.TraceS3 || trace.HTTP == nil {
+		return
+	}
+	api := strings.TrimPrefix(trace.FuncName, "s3.")
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(trace.Path, "/"), "/")
+	key := accessReportKey{
+		accessKey: traceAccessKey(trace.HTTP.ReqInfo),
+		bucket:    bucket,
+		apiClass:  apiClass(api),
+	}
+	m, ok := r[key]
+	if !ok {
+		m = &accessReportMessage{
+			AccessKey: key.accessKey,
+			Bucket:    key.bucket,
+			APIClass:  key.apiClass,
+		}
+		r[key] = m
+	}
+	m.Count++
+	if trace.HTTP.RespInfo.StatusCode >= 400 || trace.Error != "" {
+		m.Errors++
+	}
+	if i := sort.SearchStrings(m.APIs, api); i == len(m.APIs) || m.APIs[i] != api {
+		m.APIs = append(m.APIs, "")
+		copy(m.APIs[i+1:], m.APIs[i:])
+		m.APIs[i] = api
+	}
+}
+
+// rows returns the rows of the report sorted by access key, bucket and
+// class of API.
+func (r accessReport) rows() []accessReportMessage {
+	rows := make([]accessReportMessage, 0, len(r))
+	for _, m := range r {
+		rows = append(rows, *m)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].AccessKey != rows[j].AccessKey {
+			return rows[i].AccessKey < rows[j].AccessKey
+		}
+		if rows[i].Bucket != rows[j].Bucket {
+			return rows[i].Bucket < rows[j].Bucket
+		}
+		return rows[i].APIClass < rows[j].APIClass
+	})
+	return rows
+}
+
+// apiClass returns the class of an S3 API: read, write, list, delete
+// or other.
+func apiClass(api string) string {
+	switch {
+	case strings.HasPrefix(api, "List"):
+		return "list"
+	case strings.HasPrefix(api, "Delete"), strings.HasPrefix(api, "Abort"):
+		return "delete"
+	case strings.HasPrefix(api, "Get"), strings.HasPrefix(api, "Head"), strings.HasPrefix(api, "Select"):
+		return "read"
+	case strings.HasPrefix(api, "Put"), strings.HasPrefix(api, "Copy"), strings.HasPrefix(api, "Create"),
+		strings.HasPrefix(api, "Complete"), strings.HasPrefix(api, "Upload"), strings.HasPrefix(api, "NewMultipart"),
+		strings.HasPrefix(api, "PostPolicy"), strings.HasPrefix(api, "Restore"):
+		return "write"
+	}
+	return "other"
+}
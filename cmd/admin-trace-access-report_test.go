@@ -0,0 +1,65 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestAccessReport(t *testing.T) {
+	trace := func(funcName, path, auth string, statusCode int) madmin.TraceInfo {
+		return madmin.TraceInfo{
+			TraceType: madmin.TraceS3,
+			FuncName:  funcName,
+			Path:      path,
+			HTTP: &madmin.TraceHTTPStats{
+				ReqInfo:  madmin.TraceRequestInfo{Headers: http.Header{"Authorization": []string{auth}}},
+				RespInfo: madmin.TraceResponseInfo{StatusCode: statusCode},
+			},
+		}
+	}
+	alice := "AWS4-HMAC-SHA256 Credential=alice/20230101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=x"
+
+	report := accessReport{}
+	report.add(trace("s3.GetObject", "/photos/a.jpg", alice, 200))
+	report.add(trace("s3.HeadObject", "/photos/b.jpg", alice, 404))
+	report.add(trace("s3.PutObject", "/photos/c.jpg", alice, 200))
+	report.add(trace("s3.ListBuckets", "/", "", 403))
+	report.add(madmin.TraceInfo{TraceType: madmin.TraceStorage, FuncName: "storage.ReadAll"})
+
+	rows := report.rows()
+	var got []string
+	for _, row := range rows {
+		got = append(got, strings.Join([]string{row.AccessKey, row.Bucket, row.APIClass, strings.Join(row.APIs, "+")}, " "))
+	}
+	expected := []string{
+		"alice photos read GetObject+HeadObject",
+		"alice photos write PutObject",
+		"anonymous  list ListBuckets",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+	if rows[0].Count != 2 || rows[0].Errors != 1 || rows[2].Errors != 1 {
+		t.Fatalf("unexpected counts %+v", rows)
+	}
+}
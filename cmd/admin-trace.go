@@ -94,6 +94,14 @@ var adminTraceFlags = []cli.Flag{
 		Name:  "filter-size",
 		Usage: "filter size, use with filter (see UNITS)",
 	},
+	cli.DurationFlag{
+		Name:  "duration",
+		Usage: "stop tracing after this duration (e.g. `10m`), 0 to trace until interrupted",
+	},
+	cli.BoolFlag{
+		Name:  "access-report",
+		Usage: "print the number of S3 requests per access key, bucket and class of API at the end of --duration",
+	},
 }
 
 // traceCallTypes contains all call types and flags to apply when selected.
@@ -208,6 +216,9 @@ EXAMPLES:
   
   8. Show trace only for requests operations duration greater than 5ms
      {{.Prompt}} {{.HelpName}} --response-duration 5ms myminio
+
+  9. Show which access keys used which buckets during 10 minutes
+     {{.Prompt}} {{.HelpName}} --access-report --duration 10m myminio
 `,
 }
 
@@ -228,6 +239,13 @@ func checkAdminTraceSyntax(ctx *cli.Context) {
 	if ctx.Bool("all") && len(ctx.StringSlice("call")) > 0 {
 		fatalIf(errDummy().Trace(), "You cannot specify both --all and --call flags at the same time.")
 	}
+
+	if ctx.Duration("duration") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("duration")), "--duration cannot be negative.")
+	}
+	if ctx.Bool("access-report") && ctx.Duration("duration") == 0 {
+		fatalIf(errInvalidArgument().Trace(), "--access-report requires --duration.")
+	}
 }
 
 func printTrace(verbose bool, traceInfo madmin.ServiceTraceInfo) {
@@ -445,6 +463,11 @@ func mainAdminTrace(ctx *cli.Context) error {
 	console.SetColor("Response", color.New(color.FgGreen))
 	console.SetColor("Extra", color.New(color.FgBlue))
 	console.SetColor("Body", color.New(color.FgYellow))
+
+	console.SetColor("AccessKey", color.New(color.FgBlue))
+	console.SetColor("Bucket", color.New(color.FgGreen))
+	console.SetColor("APIClass", color.New(color.FgCyan))
+	console.SetColor("Count", color.New(color.FgYellow))
 	for _, c := range colors {
 		console.SetColor(fmt.Sprintf("Node%d", c), color.New(c))
 	}
@@ -456,6 +479,9 @@ func mainAdminTrace(ctx *cli.Context) error {
 	}
 
 	ctxt, cancel := context.WithCancel(globalContext)
+	if duration := ctx.Duration("duration"); duration > 0 {
+		ctxt, cancel = context.WithTimeout(globalContext, duration)
+	}
 	defer cancel()
 
 	opts, e := tracingOpts(ctx, ctx.StringSlice("call"))
@@ -463,17 +489,34 @@ func mainAdminTrace(ctx *cli.Context) error {
 
 	mopts := matchingOpts(ctx)
 
+	var report accessReport
+	if ctx.Bool("access-report") {
+		report = accessReport{}
+	}
+
 	// Start listening on all trace activity.
 	traceCh := client.ServiceTrace(ctxt, opts)
 	for traceInfo := range traceCh {
 		if traceInfo.Err != nil {
+			// The end of --duration.
+			if ctxt.Err() != nil {
+				break
+			}
 			fatalIf(probe.NewError(traceInfo.Err), "Unable to listen to http trace")
 		}
-		if matchTrace(mopts, traceInfo) {
-			printTrace(verbose, traceInfo)
+		if !matchTrace(mopts, traceInfo) {
+			continue
 		}
+		if report != nil {
+			report.add(traceInfo.Trace)
+			continue
+		}
+		printTrace(verbose, traceInfo)
 	}
 
+	for _, row := range report.rows() {
+		printMsg(row)
+	}
 	return nil
 }
 
@@ -21,6 +21,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
@@ -92,6 +93,10 @@ type userMessage struct {
 	PolicyName string   `json:"policyName,omitempty"`
 	UserStatus string   `json:"userStatus,omitempty"`
 	MemberOf   []string `json:"memberOf,omitempty"`
+
+	// ServiceAccounts is the number of service accounts of the user,
+	// only reported by list --detailed.
+	ServiceAccounts *int `json:"serviceAccounts,omitempty"`
 }
 
 func (u userMessage) String() string {
@@ -101,6 +106,16 @@ func (u userMessage) String() string {
 		accessFieldMaxLen := 20
 		policyFieldMaxLen := 20
 
+		if u.ServiceAccounts != nil {
+			return newPrettyTable("  ",
+				Field{"UserStatus", userFieldMaxLen},
+				Field{"AccessKey", accessFieldMaxLen},
+				Field{"PolicyName", policyFieldMaxLen},
+				Field{"SvcAccts", 8},
+				Field{"MemberOf", -1},
+			).buildRow(u.UserStatus, u.AccessKey, u.PolicyName, strconv.Itoa(*u.ServiceAccounts), strings.Join(u.MemberOf, ","))
+		}
+
 		// Create a new pretty table with cols configuration
 		return newPrettyTable("  ",
 			Field{"UserStatus", userFieldMaxLen},
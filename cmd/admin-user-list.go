@@ -18,12 +18,22 @@
 package cmd
 
 import (
+	"sort"
+
 	"github.com/fatih/color"
 	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
 	"github.com/kirolous/mc/pkg/probe"
 	"github.com/minio/pkg/console"
 )
 
+var adminUserListFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "detailed",
+		Usage: "display all the policies, the groups and the number of service accounts of each user",
+	},
+}
+
 var adminUserListCmd = cli.Command{
 	Name:         "list",
 	ShortName:    "ls",
@@ -31,7 +41,7 @@ var adminUserListCmd = cli.Command{
 	Action:       mainAdminUserList,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
+	Flags:        append(adminUserListFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -44,6 +54,9 @@ FLAGS:
 EXAMPLES:
   1. List all users on MinIO server.
      {{.Prompt}} {{.HelpName}} myminio
+
+  2. List all users on MinIO server with their policies, groups and number of service accounts.
+     {{.Prompt}} {{.HelpName}} --detailed myminio
 `,
 }
 
@@ -63,6 +76,8 @@ func mainAdminUserList(ctx *cli.Context) error {
 	console.SetColor("AccessKey", color.New(color.FgBlue))
 	console.SetColor("PolicyName", color.New(color.FgYellow))
 	console.SetColor("UserStatus", color.New(color.FgCyan))
+	console.SetColor("SvcAccts", color.New(color.FgMagenta))
+	console.SetColor("MemberOf", color.New(color.FgHiBlue))
 
 	// Get the alias parameter from cli
 	args := ctx.Args()
@@ -75,14 +90,51 @@ func mainAdminUserList(ctx *cli.Context) error {
 	users, e := client.ListUsers(globalContext)
 	fatalIf(probe.NewError(e).Trace(args...), "Unable to list user")
 
-	for k, v := range users {
-		printMsg(userMessage{
-			op:         ctx.Command.Name,
+	var svcAccounts func(accessKey string) (int, *probe.Error)
+	if ctx.Bool("detailed") {
+		svcAccounts = func(accessKey string) (int, *probe.Error) {
+			svcList, e := client.ListServiceAccounts(globalContext, accessKey)
+			if e != nil {
+				return 0, probe.NewError(e).Trace(accessKey)
+			}
+			return len(svcList.Accounts), nil
+		}
+	}
+	msgs, err := userListMessages(ctx.Command.Name, users, svcAccounts)
+	fatalIf(err, "Unable to list the service accounts of the users.")
+	for _, msg := range msgs {
+		printMsg(msg)
+	}
+	return nil
+}
+
+// userListMessages returns the messages of the users ordered by access key,
+// with the number of service accounts of each user when svcAccounts is set.
+func userListMessages(op string, users map[string]madmin.UserInfo, svcAccounts func(accessKey string) (int, *probe.Error)) ([]userMessage, *probe.Error) {
+	accessKeys := make([]string, 0, len(users))
+	for k := range users {
+		accessKeys = append(accessKeys, k)
+	}
+	sort.Strings(accessKeys)
+
+	msgs := make([]userMessage, 0, len(accessKeys))
+	for _, k := range accessKeys {
+		v := users[k]
+		msg := userMessage{
+			op:         op,
 			AccessKey:  k,
 			PolicyName: v.PolicyName,
 			MemberOf:   v.MemberOf,
 			UserStatus: string(v.Status),
-		})
+		}
+		if svcAccounts != nil {
+			count, err := svcAccounts(k)
+			if err != nil {
+				return nil, err
+			}
+			msg.ServiceAccounts = &count
+		}
+		msgs = append(msgs, msg)
 	}
-	return nil
+	return msgs, nil
 }
@@ -0,0 +1,118 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestUserListMessages(t *testing.T) {
+	users := map[string]madmin.UserInfo{
+		"zoe":   {PolicyName: "readwrite", Status: madmin.AccountEnabled},
+		"alice": {PolicyName: "readonly", MemberOf: []string{"dev", "ops"}, Status: madmin.AccountDisabled},
+		"bob":   {Status: madmin.AccountEnabled},
+	}
+	svcAccounts := map[string]int{"alice": 2, "zoe": 1}
+
+	testCases := []struct {
+		detailed bool
+		fail     string
+		keys     []string
+		counts   []int
+		err      bool
+	}{
+		{false, "", []string{"alice", "bob", "zoe"}, nil, false},
+		{true, "", []string{"alice", "bob", "zoe"}, []int{2, 0, 1}, false},
+		{true, "bob", nil, nil, true},
+		// Service accounts are not listed without --detailed.
+		{false, "bob", []string{"alice", "bob", "zoe"}, nil, false},
+	}
+	for i, testCase := range testCases {
+		var calls []string
+		var count func(string) (int, *probe.Error)
+		if testCase.detailed {
+			count = func(accessKey string) (int, *probe.Error) {
+				calls = append(calls, accessKey)
+				if accessKey == testCase.fail {
+					return 0, probe.NewError(errors.New("access denied"))
+				}
+				return svcAccounts[accessKey], nil
+			}
+		}
+		msgs, err := userListMessages("list", users, count)
+		if (err != nil) != testCase.err {
+			t.Fatalf("Test %d: expected error %t, got %v", i+1, testCase.err, err)
+		}
+		if testCase.err {
+			continue
+		}
+		if !testCase.detailed && calls != nil {
+			t.Errorf("Test %d: expected no service account lookups, got %q", i+1, calls)
+		}
+		var keys []string
+		var counts []int
+		for _, msg := range msgs {
+			keys = append(keys, msg.AccessKey)
+			if msg.op != "list" {
+				t.Errorf("Test %d: expected op list, got %q", i+1, msg.op)
+			}
+			if (msg.ServiceAccounts != nil) != testCase.detailed {
+				t.Errorf("Test %d: unexpected service accounts of %s: %v", i+1, msg.AccessKey, msg.ServiceAccounts)
+			}
+			if msg.ServiceAccounts != nil {
+				counts = append(counts, *msg.ServiceAccounts)
+			}
+			user := users[msg.AccessKey]
+			if msg.PolicyName != user.PolicyName || msg.UserStatus != string(user.Status) || !reflect.DeepEqual(msg.MemberOf, user.MemberOf) {
+				t.Errorf("Test %d: unexpected message for %s: %+v", i+1, msg.AccessKey, msg)
+			}
+		}
+		if !reflect.DeepEqual(keys, testCase.keys) {
+			t.Errorf("Test %d: expected users %q, got %q", i+1, testCase.keys, keys)
+		}
+		if !reflect.DeepEqual(counts, testCase.counts) {
+			t.Errorf("Test %d: expected service accounts %v, got %v", i+1, testCase.counts, counts)
+		}
+	}
+}
+
+func TestUserListMessageString(t *testing.T) {
+	count := 3
+	testCases := []struct {
+		msg      userMessage
+		svcAccts bool
+	}{
+		{userMessage{op: "list", AccessKey: "alice", UserStatus: "enabled"}, false},
+		{userMessage{op: "list", AccessKey: "alice", UserStatus: "enabled", ServiceAccounts: &count}, true},
+	}
+	for i, testCase := range testCases {
+		s := testCase.msg.String()
+		if !strings.Contains(s, "alice") {
+			t.Errorf("Test %d: expected the access key in %q", i+1, s)
+		}
+		if hasCount := strings.Contains(s, "  3  "); hasCount != testCase.svcAccts {
+			t.Errorf("Test %d: expected service accounts column %t, got %q", i+1, testCase.svcAccts, s)
+		}
+	}
+}
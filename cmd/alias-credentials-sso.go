@@ -0,0 +1,202 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	ini "gopkg.in/ini.v1"
+)
+
+// awsConfigProfile - the settings of a profile of the AWS config file
+// which are not static keys.
+type awsConfigProfile struct {
+	CredentialProcess string
+
+	// IAM Identity Center (SSO), either set on the profile or through
+	// an sso-session section.
+	SSOSession   string
+	SSOStartURL  string
+	SSORegion    string
+	SSOAccountID string
+	SSORoleName  string
+}
+
+// isSSO tells whether the profile gets its credentials from IAM Identity Center.
+func (p awsConfigProfile) isSSO() bool {
+	return p.SSOAccountID != "" && p.SSORoleName != ""
+}
+
+// awsConfigFile returns the path of the AWS config file.
+func awsConfigFile() string {
+	if path := os.Getenv("AWS_CONFIG_FILE"); path != "" {
+		return path
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".aws", "config")
+}
+
+// loadAWSConfigProfile reads a profile of the AWS config file, nil when
+// the file or the profile doesn't exist.
+func loadAWSConfigProfile(path, profile string) (*awsConfigProfile, error) {
+	if _, e := os.Stat(path); os.IsNotExist(e) {
+		return nil, nil
+	}
+	cfg, e := ini.Load(path)
+	if e != nil {
+		return nil, e
+	}
+	name := "profile " + profile
+	if profile == "default" && !cfg.HasSection(name) {
+		name = "default"
+	}
+	if !cfg.HasSection(name) {
+		return nil, nil
+	}
+	section := cfg.Section(name)
+	p := &awsConfigProfile{
+		CredentialProcess: section.Key("credential_process").String(),
+		SSOSession:        section.Key("sso_session").String(),
+		SSOStartURL:       section.Key("sso_start_url").String(),
+		SSORegion:         section.Key("sso_region").String(),
+		SSOAccountID:      section.Key("sso_account_id").String(),
+		SSORoleName:       section.Key("sso_role_name").String(),
+	}
+	if p.SSOSession != "" {
+		session := cfg.Section("sso-session " + p.SSOSession)
+		p.SSOStartURL = session.Key("sso_start_url").String()
+		p.SSORegion = session.Key("sso_region").String()
+	}
+	if p.isSSO() && (p.SSOStartURL == "" || p.SSORegion == "") {
+		return nil, fmt.Errorf("the SSO profile `%s` needs sso_start_url and sso_region", profile)
+	}
+	return p, nil
+}
+
+// ssoCredentials - credentials provider exchanging the token cached by
+// 'aws sso login' for the credentials of the role of an SSO profile.
+type ssoCredentials struct {
+	credentials.Expiry
+
+	Profile awsConfigProfile
+	// Endpoint of the SSO portal, derived from the SSO region when empty.
+	Endpoint string
+	// CacheDir holds the tokens cached by the AWS CLI, ~/.aws/sso/cache when empty.
+	CacheDir string
+
+	retrieved bool
+}
+
+// ssoCachedToken - a token cached by 'aws sso login'.
+type ssoCachedToken struct {
+	AccessToken string    `json:"accessToken"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// ssoRoleCredentials - response of the GetRoleCredentials API of the SSO portal.
+type ssoRoleCredentials struct {
+	RoleCredentials struct {
+		AccessKeyID     string `json:"accessKeyId"`
+		SecretAccessKey string `json:"secretAccessKey"`
+		SessionToken    string `json:"sessionToken"`
+		Expiration      int64  `json:"expiration"` // milliseconds since epoch
+	} `json:"roleCredentials"`
+}
+
+// cachedToken reads the token of the profile, the AWS CLI names the cache
+// file after the SHA1 of the session name, or of the start URL without a session.
+func (s *ssoCredentials) cachedToken() (string, error) {
+	dir := s.CacheDir
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".aws", "sso", "cache")
+	}
+	key := s.Profile.SSOStartURL
+	if s.Profile.SSOSession != "" {
+		key = s.Profile.SSOSession
+	}
+	sum := sha1.Sum([]byte(key))
+	buf, e := os.ReadFile(filepath.Join(dir, hex.EncodeToString(sum[:])+".json"))
+	if e != nil {
+		return "", fmt.Errorf("no SSO token cached for %s, run 'aws sso login': %w", key, e)
+	}
+	var token ssoCachedToken
+	if e = json.Unmarshal(buf, &token); e != nil {
+		return "", fmt.Errorf("invalid SSO token cache: %w", e)
+	}
+	if token.AccessToken == "" || time.Now().After(token.ExpiresAt) {
+		return "", fmt.Errorf("the SSO token of %s expired, run 'aws sso login'", key)
+	}
+	return token.AccessToken, nil
+}
+
+// Retrieve gets the credentials of the role from the SSO portal.
+func (s *ssoCredentials) Retrieve() (credentials.Value, error) {
+	token, e := s.cachedToken()
+	if e != nil {
+		return credentials.Value{}, e
+	}
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://portal.sso." + s.Profile.SSORegion + ".amazonaws.com"
+	}
+	query := url.Values{}
+	query.Set("account_id", s.Profile.SSOAccountID)
+	query.Set("role_name", s.Profile.SSORoleName)
+	req, e := http.NewRequestWithContext(globalContext, http.MethodGet, endpoint+"/federation/credentials?"+query.Encode(), nil)
+	if e != nil {
+		return credentials.Value{}, e
+	}
+	req.Header.Set("x-amz-sso_bearer_token", token)
+	resp, e := http.DefaultClient.Do(req)
+	if e != nil {
+		return credentials.Value{}, e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return credentials.Value{}, fmt.Errorf("SSO portal returned %s", resp.Status)
+	}
+	var out ssoRoleCredentials
+	if e = json.NewDecoder(resp.Body).Decode(&out); e != nil {
+		return credentials.Value{}, fmt.Errorf("invalid SSO portal response: %w", e)
+	}
+
+	s.retrieved = true
+	// Refresh a minute ahead to not sign requests with expiring credentials.
+	s.SetExpiration(time.UnixMilli(out.RoleCredentials.Expiration), time.Minute)
+	return credentials.Value{
+		AccessKeyID:     out.RoleCredentials.AccessKeyID,
+		SecretAccessKey: out.RoleCredentials.SecretAccessKey,
+		SessionToken:    out.RoleCredentials.SessionToken,
+		SignerType:      credentials.SignatureV4,
+	}, nil
+}
+
+// IsExpired returns true when the credentials need to be retrieved again.
+func (s *ssoCredentials) IsExpired() bool {
+	return !s.retrieved || s.Expiry.IsExpired()
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/shlex"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Supported alias credential sources other than static keys.
+const (
+	credentialSourceAWSProfile = "aws-profile"
+	credentialSourceProcess    = "process"
+	credentialSourceIAM        = "iam"
+)
+
+var aliasCredentialFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "from-aws-profile",
+		Usage: "load the credentials from a profile of the AWS credentials or config file, SSO profiles included",
+	},
+	cli.StringFlag{
+		Name:  "credential-process",
+		Usage: "command printing the credentials in the AWS 'credential_process' JSON format",
+	},
+	cli.BoolFlag{
+		Name:  "iam",
+		Usage: "use the credentials of the IAM role of the EC2 instance, ECS task or EKS service account",
+	},
+}
+
+// aliasCredentialSource - where an alias gets its credentials from
+// instead of the static access and secret keys.
+type aliasCredentialSource struct {
+	Type    string `json:"type"`
+	Profile string `json:"profile,omitempty"`
+	Command string `json:"command,omitempty"`
+}
+
+// String returns a short description of the credential source.
+func (s aliasCredentialSource) String() string {
+	switch s.Type {
+	case credentialSourceAWSProfile:
+		return "aws profile " + s.Profile
+	case credentialSourceProcess:
+		return "credential process"
+	case credentialSourceIAM:
+		return "IAM role"
+	}
+	return s.Type
+}
+
+// aliasCredentialSourceFromFlags returns the credential source selected
+// by the alias set flags, nil when the static keys are used.
+func aliasCredentialSourceFromFlags(ctx *cli.Context) *aliasCredentialSource {
+	var sources []*aliasCredentialSource
+	if ctx.IsSet("from-aws-profile") {
+		sources = append(sources, &aliasCredentialSource{Type: credentialSourceAWSProfile, Profile: ctx.String("from-aws-profile")})
+	}
+	if ctx.IsSet("credential-process") {
+		sources = append(sources, &aliasCredentialSource{Type: credentialSourceProcess, Command: ctx.String("credential-process")})
+	}
+	if ctx.Bool("iam") {
+		sources = append(sources, &aliasCredentialSource{Type: credentialSourceIAM})
+	}
+	switch len(sources) {
+	case 0:
+		return nil
+	case 1:
+		return sources[0]
+	}
+	fatalIf(errInvalidArgument().Trace(), "Only one of --from-aws-profile, --credential-process and --iam can be used.")
+	return nil
+}
+
+var (
+	aliasCredentialsMu    sync.Mutex
+	aliasCredentialsCache = map[aliasCredentialSource]*credentials.Credentials{}
+)
+
+// getCredentials returns the credentials of the source. They are shared
+// by all the clients of the source and refreshed once they expire, so
+// long running transfers keep working with temporary credentials.
+func (s aliasCredentialSource) getCredentials() *credentials.Credentials {
+	aliasCredentialsMu.Lock()
+	defer aliasCredentialsMu.Unlock()
+
+	if creds, ok := aliasCredentialsCache[s]; ok {
+		return creds
+	}
+
+	var creds *credentials.Credentials
+	switch s.Type {
+	case credentialSourceAWSProfile:
+		creds = awsProfileCredentials(s.Profile)
+	case credentialSourceProcess:
+		creds = credentials.New(&processCredentials{Command: s.Command})
+	case credentialSourceIAM:
+		// Picks up IRSA web identity tokens, ECS task roles and EC2 instance profiles.
+		creds = credentials.NewIAM("")
+	default:
+		creds = credentials.New(&credentials.Static{})
+	}
+	aliasCredentialsCache[s] = creds
+	return creds
+}
+
+// awsProfileCredentials returns the credentials of an AWS profile: the SSO
+// role or the credential process of the profile in the AWS config file,
+// otherwise the keys of the shared credentials file.
+func awsProfileCredentials(profile string) *credentials.Credentials {
+	p, e := loadAWSConfigProfile(awsConfigFile(), profile)
+	switch {
+	case e != nil:
+		errorIf(probe.NewError(e).Trace(profile), "Unable to read the AWS config file.")
+	case p != nil && p.isSSO():
+		return credentials.New(&ssoCredentials{Profile: *p})
+	case p != nil && p.CredentialProcess != "":
+		return credentials.New(&processCredentials{Command: p.CredentialProcess})
+	}
+	return credentials.NewFileAWSCredentials("", profile)
+}
+
+// verify makes sure credentials can be obtained from the source.
+func (s aliasCredentialSource) verify() *probe.Error {
+	if s.Type == credentialSourceProcess && strings.TrimSpace(s.Command) == "" {
+		return errInvalidArgument().Trace(s.Command)
+	}
+	v, e := s.getCredentials().Get()
+	if e != nil {
+		return probe.NewError(e).Trace(s.String())
+	}
+	if v.AccessKeyID == "" || v.SecretAccessKey == "" {
+		return probe.NewError(fmt.Errorf("no credentials found in %s", s)).Trace(s.String())
+	}
+	return nil
+}
+
+// processCredentials - credentials provider running an external command
+// which prints the credentials as described for 'credential_process' in
+// https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-sourcing-external.html
+type processCredentials struct {
+	credentials.Expiry
+
+	Command string
+
+	// static credentials are retrieved once.
+	static    bool
+	retrieved bool
+}
+
+// processCredentialsOutput - output of the credential process.
+type processCredentialsOutput struct {
+	Version         int       `json:"Version"`
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"SessionToken"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// Retrieve runs the credential process.
+func (p *processCredentials) Retrieve() (credentials.Value, error) {
+	args, e := shlex.Split(p.Command)
+	if e != nil {
+		return credentials.Value{}, fmt.Errorf("invalid credential process command: %w", e)
+	}
+	if len(args) == 0 {
+		return credentials.Value{}, errors.New("empty credential process command")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(globalContext, args[0], args[1:]...)
+	cmd.Stderr = &stderr
+	out, e := cmd.Output()
+	if e != nil {
+		return credentials.Value{}, fmt.Errorf("credential process failed: %w: %s", e, strings.TrimSpace(stderr.String()))
+	}
+
+	var output processCredentialsOutput
+	if e = json.Unmarshal(out, &output); e != nil {
+		return credentials.Value{}, fmt.Errorf("invalid credential process output: %w", e)
+	}
+	if output.Version != 1 {
+		return credentials.Value{}, fmt.Errorf("unsupported credential process output version %d", output.Version)
+	}
+
+	p.retrieved = true
+	p.static = output.Expiration.IsZero()
+	if !p.static {
+		// Refresh a minute ahead to not sign requests with expiring credentials.
+		p.SetExpiration(output.Expiration, time.Minute)
+	}
+	return credentials.Value{
+		AccessKeyID:     output.AccessKeyID,
+		SecretAccessKey: output.SecretAccessKey,
+		SessionToken:    output.SessionToken,
+		SignerType:      credentials.SignatureV4,
+	}, nil
+}
+
+// IsExpired returns true when the credentials need to be retrieved again.
+func (p *processCredentials) IsExpired() bool {
+	if !p.retrieved {
+		return true
+	}
+	if p.static {
+		return false
+	}
+	return p.Expiry.IsExpired()
+}
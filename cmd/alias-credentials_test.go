@@ -0,0 +1,162 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+const testAWSConfig = `[default]
+region = us-east-1
+
+[profile dev]
+sso_session = corp
+sso_account_id = 111122223333
+sso_role_name = Developer
+
+[profile legacy]
+sso_start_url = https://legacy.awsapps.com/start
+sso_region = eu-west-1
+sso_account_id = 444455556666
+sso_role_name = ReadOnly
+
+[profile tool]
+credential_process = /opt/bin/creds --profile "my team"
+
+[profile broken]
+sso_account_id = 1
+sso_role_name = Admin
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = us-west-2
+`
+
+func TestLoadAWSConfigProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if e := os.WriteFile(path, []byte(testAWSConfig), 0o600); e != nil {
+		t.Fatal(e)
+	}
+
+	testCases := []struct {
+		profile string
+		want    *awsConfigProfile
+		invalid bool
+	}{
+		{profile: "dev", want: &awsConfigProfile{
+			SSOSession: "corp", SSOStartURL: "https://corp.awsapps.com/start", SSORegion: "us-west-2",
+			SSOAccountID: "111122223333", SSORoleName: "Developer",
+		}},
+		{profile: "legacy", want: &awsConfigProfile{
+			SSOStartURL: "https://legacy.awsapps.com/start", SSORegion: "eu-west-1",
+			SSOAccountID: "444455556666", SSORoleName: "ReadOnly",
+		}},
+		{profile: "tool", want: &awsConfigProfile{CredentialProcess: `/opt/bin/creds --profile "my team"`}},
+		{profile: "default", want: &awsConfigProfile{}},
+		{profile: "missing"},
+		{profile: "broken", invalid: true},
+	}
+	for i, testCase := range testCases {
+		p, e := loadAWSConfigProfile(path, testCase.profile)
+		if testCase.invalid {
+			if e == nil {
+				t.Errorf("Test %d: expected an error", i+1)
+			}
+			continue
+		}
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if (p == nil) != (testCase.want == nil) || (p != nil && *p != *testCase.want) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.want, p)
+		}
+	}
+}
+
+func TestSSOCredentials(t *testing.T) {
+	expiration := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/federation/credentials" || r.Header.Get("x-amz-sso_bearer_token") != "token" ||
+			r.URL.Query().Get("account_id") != "111122223333" || r.URL.Query().Get("role_name") != "Developer" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprintf(w, `{"roleCredentials":{"accessKeyId":"AK","secretAccessKey":"SK","sessionToken":"ST","expiration":%d}}`,
+			expiration.UnixMilli())
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	sum := sha1.Sum([]byte("corp"))
+	cacheFile := filepath.Join(cacheDir, hex.EncodeToString(sum[:])+".json")
+	writeToken := func(expiresAt time.Time) {
+		token := fmt.Sprintf(`{"accessToken":"token","expiresAt":%q}`, expiresAt.UTC().Format(time.RFC3339))
+		if e := os.WriteFile(cacheFile, []byte(token), 0o600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	profile := awsConfigProfile{SSOSession: "corp", SSORegion: "us-west-2", SSOAccountID: "111122223333", SSORoleName: "Developer"}
+	s := &ssoCredentials{Profile: profile, Endpoint: server.URL, CacheDir: cacheDir}
+	if _, e := s.Retrieve(); e == nil {
+		t.Fatal("expected an error without a cached token")
+	}
+
+	writeToken(time.Now().Add(-time.Minute))
+	if _, e := s.Retrieve(); e == nil {
+		t.Fatal("expected an error with an expired token")
+	}
+
+	writeToken(time.Now().Add(time.Hour))
+	v, e := s.Retrieve()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if v.AccessKeyID != "AK" || v.SecretAccessKey != "SK" || v.SessionToken != "ST" {
+		t.Errorf("unexpected credentials %+v", v)
+	}
+	if s.IsExpired() {
+		t.Error("expected the credentials to be valid until their expiration")
+	}
+}
+
+func TestProcessCredentialsQuoting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	p := &processCredentials{Command: `sh -c 'echo "{\"Version\": 1, \"AccessKeyId\": \"$0\", \"SecretAccessKey\": \"secret key\"}"' "my key"`}
+	v, e := p.Retrieve()
+	if e != nil {
+		t.Fatal(e)
+	}
+	if v.AccessKeyID != "my key" || v.SecretAccessKey != "secret key" {
+		t.Errorf("unexpected credentials %+v", v)
+	}
+	if p.IsExpired() {
+		t.Error("expected credentials without expiration to be static")
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var aliasDefaultsCmd = cli.Command{
+	Name:            "defaults",
+	Usage:           "show or set default flags of an alias",
+	Action:          mainAliasDefaults,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS [FLAG=VALUE...]
+
+  Default flags are applied to every command operating on the alias,
+  unless the flag is given on the command line. When a command uses
+  several aliases, the defaults of the last one, usually the target,
+  take precedence. An empty VALUE removes the default.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the default flags of "myminio".
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. Upload to "myminio" with the storage class REDUCED_REDUNDANCY and a custom header by default.
+     {{.Prompt}} {{.HelpName}} myminio storage-class=REDUCED_REDUNDANCY "attr=Cache-Control=max-age=90000"
+
+  3. Skip the TLS verification of "mylab" by default.
+     {{.Prompt}} {{.HelpName}} mylab insecure=true
+
+  4. Remove the default storage class of "myminio".
+     {{.Prompt}} {{.HelpName}} myminio storage-class=
+`,
+}
+
+// aliasDefaultsMessage container for alias default flags
+type aliasDefaultsMessage struct {
+	Status   string            `json:"status"`
+	Alias    string            `json:"alias"`
+	Defaults map[string]string `json:"defaults"`
+}
+
+func (m aliasDefaultsMessage) String() string {
+	if len(m.Defaults) == 0 {
+		return console.Colorize("AliasMessage", "No default flags set for `"+m.Alias+"`.")
+	}
+	names := make([]string, 0, len(m.Defaults))
+	for name := range m.Defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(console.Colorize("Alias", m.Alias) + "\n")
+	for _, name := range names {
+		b.WriteString("  --" + name + " " + m.Defaults[name] + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (m aliasDefaultsMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+// collectFlagNames returns the names of all the flags of the commands.
+func collectFlagNames(cmds []cli.Command, names map[string]bool) {
+	for _, cmd := range cmds {
+		for _, flag := range cmd.Flags {
+			for _, name := range strings.Split(flag.GetName(), ",") {
+				names[strings.TrimSpace(name)] = true
+			}
+		}
+		collectFlagNames(cmd.Subcommands, names)
+	}
+}
+
+// mainAliasDefaults is the handle for "mc alias defaults" command.
+func mainAliasDefaults(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) == 0 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	console.SetColor("AliasMessage", color.New(color.FgGreen))
+	console.SetColor("Alias", color.New(color.FgCyan, color.Bold))
+
+	alias := cleanAlias(args.Get(0))
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	aliasCfg, ok := conf.Aliases[alias]
+	if !ok {
+		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+	}
+
+	if len(args) > 1 {
+		root := ctx
+		for root.Parent() != nil {
+			root = root.Parent()
+		}
+		flagNames := map[string]bool{}
+		collectFlagNames(root.App.Commands, flagNames)
+
+		if aliasCfg.Defaults == nil {
+			aliasCfg.Defaults = make(map[string]string)
+		}
+		for _, arg := range args.Tail() {
+			name, value, found := strings.Cut(arg, "=")
+			name = strings.TrimLeft(name, "-")
+			if !found || !flagNames[name] {
+				fatalIf(errInvalidArgument().Trace(arg), "Invalid default flag `"+arg+"`, expected FLAG=VALUE with a known flag.")
+			}
+			if value == "" {
+				delete(aliasCfg.Defaults, name)
+				continue
+			}
+			aliasCfg.Defaults[name] = value
+		}
+		if len(aliasCfg.Defaults) == 0 {
+			aliasCfg.Defaults = nil
+		}
+
+		conf.Aliases[alias] = aliasCfg
+		err = saveMcConfig(conf)
+		fatalIf(err.Trace(alias), "Unable to save the default flags of `"+alias+"` in config version `"+globalMCConfigVersion+"`.")
+	}
+
+	printMsg(aliasDefaultsMessage{Alias: alias, Defaults: aliasCfg.Defaults})
+	return nil
+}
+
+// applyAliasDefaults sets the default flags of the aliases found in the
+// arguments of the command, for the flags not set on the command line.
+func applyAliasDefaults(ctx *cli.Context) {
+	if loadMcConfig == nil {
+		// Config not initialized yet.
+		return
+	}
+	conf, err := loadMcConfig()
+	if err != nil {
+		return
+	}
+
+	// The flags given on the command line are looked up before any default
+	// is set, and each flag is set once, from the last alias having it.
+	explicit := map[string]bool{}
+	args := ctx.Args()
+	for _, arg := range args {
+		alias, _ := url2Alias(arg)
+		for name := range conf.Aliases[alias].Defaults {
+			explicit[name] = ctx.IsSet(name) || ctx.GlobalIsSet(name)
+		}
+	}
+	applied := map[string]bool{}
+	for i := len(args) - 1; i >= 0; i-- {
+		alias, _ := url2Alias(args[i])
+		aliasCfg, ok := conf.Aliases[alias]
+		if !ok {
+			continue
+		}
+		for name, value := range aliasCfg.Defaults {
+			if explicit[name] || applied[name] {
+				continue
+			}
+			// Not all the commands know all the flags.
+			if e := ctx.Set(name, value); e == nil {
+				applied[name] = true
+				aliasDefaultsApplied[name] = true
+			}
+		}
+	}
+}
+
+// isAliasDefaultTrue tells whether a boolean flag was turned on by an
+// alias default, a default set to false leaves it off.
+func isAliasDefaultTrue(ctx *cli.Context, name string) bool {
+	return aliasDefaultsApplied[name] && (ctx.Bool(name) || ctx.GlobalBool(name))
+}
+
+// aliasDefaultsApplied records the flags set from alias defaults,
+// cli.Context.IsSet() does not report flags set after parsing.
+var aliasDefaultsApplied = map[string]bool{}
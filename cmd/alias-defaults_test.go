@@ -0,0 +1,79 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+)
+
+func TestApplyAliasDefaults(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) {
+		conf := newConfigV10()
+		conf.Aliases["lab"] = aliasConfigV10{URL: "https://lab", Defaults: map[string]string{"insecure": "true", "storage-class": "LAB"}}
+		conf.Aliases["prod"] = aliasConfigV10{URL: "https://prod", Defaults: map[string]string{"insecure": "false", "storage-class": "PROD"}}
+		return conf, nil
+	}
+	defer func(applied map[string]bool) { aliasDefaultsApplied = applied }(aliasDefaultsApplied)
+
+	var insecure bool
+	var storageClass string
+	app := cli.NewApp()
+	app.Commands = []cli.Command{{
+		Name: "cp",
+		Flags: []cli.Flag{
+			cli.BoolFlag{Name: "insecure"},
+			cli.StringFlag{Name: "storage-class"},
+		},
+		Action: func(ctx *cli.Context) error {
+			applyAliasDefaults(ctx)
+			insecure = ctx.IsSet("insecure") || isAliasDefaultTrue(ctx, "insecure")
+			storageClass = ctx.String("storage-class")
+			return nil
+		},
+	}}
+
+	testCases := []struct {
+		args         []string
+		insecure     bool
+		storageClass string
+	}{
+		// A default set to false leaves the flag off.
+		{[]string{"mc", "cp", "file", "prod/bucket"}, false, "PROD"},
+		{[]string{"mc", "cp", "file", "lab/bucket"}, true, "LAB"},
+		// The flags of the command line win.
+		{[]string{"mc", "cp", "--storage-class", "CLI", "file", "lab/bucket"}, true, "CLI"},
+		// The last alias, usually the target, wins.
+		{[]string{"mc", "cp", "lab/bucket/file", "prod/bucket"}, false, "PROD"},
+		{[]string{"mc", "cp", "prod/bucket/file", "lab/bucket"}, true, "LAB"},
+		{[]string{"mc", "cp", "file", "other/bucket"}, false, ""},
+	}
+	for i, testCase := range testCases {
+		aliasDefaultsApplied = map[string]bool{}
+		if e := app.Run(testCase.args); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if insecure != testCase.insecure || storageClass != testCase.storageClass {
+			t.Errorf("Test %d: expected insecure=%t storage-class=%q, got insecure=%t storage-class=%q",
+				i+1, testCase.insecure, testCase.storageClass, insecure, storageClass)
+		}
+	}
+}
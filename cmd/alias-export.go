@@ -0,0 +1,254 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"golang.org/x/term"
+)
+
+// mcEnvAliasBundlePassword is the passphrase of encrypted alias bundles.
+const mcEnvAliasBundlePassword = "MC_ALIAS_BUNDLE_PASSWORD"
+
+// aliasBundleVersion is the version of the alias bundle format.
+const aliasBundleVersion = "1"
+
+// aliasBundle is written by 'mc alias export' and read by 'mc alias import --bundle'.
+type aliasBundle struct {
+	Version string                    `json:"version"`
+	Aliases map[string]aliasConfigV10 `json:"aliases"`
+}
+
+var aliasExportFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "encrypt",
+		Usage: "encrypt the bundle with a passphrase",
+	},
+	cli.StringFlag{
+		Name:  "file",
+		Usage: "write the bundle to a file instead of the standard output",
+	},
+}
+
+var aliasExportCmd = cli.Command{
+	Name:            "export",
+	Usage:           "export aliases to a bundle to be imported on another machine",
+	Action:          mainAliasExport,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(aliasExportFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [ALIAS...]
+
+  All the aliases are exported when none is given. The bundle holds the secret
+  keys of the aliases, use --encrypt to protect them with a passphrase. The
+  passphrase is prompted for or read from MC_ALIAS_BUNDLE_PASSWORD.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Export the aliases "prod" and "staging" to an encrypted bundle.
+     {{.Prompt}} {{.HelpName}} --encrypt --file team-aliases.enc prod staging
+
+  2. Import the bundle on another machine.
+     {{.Prompt}} mc alias import --bundle team-aliases.enc
+`,
+}
+
+// aliasExportMessage is printed when the bundle is written to a file.
+type aliasExportMessage struct {
+	Status    string   `json:"status"`
+	File      string   `json:"file"`
+	Aliases   []string `json:"aliases"`
+	Encrypted bool     `json:"encrypted"`
+}
+
+func (m aliasExportMessage) String() string {
+	msg := fmt.Sprintf("Exported %d alias(es) to `%s`", len(m.Aliases), m.File)
+	if m.Encrypted {
+		msg += " encrypted with a passphrase"
+	}
+	return console.Colorize("AliasMessage", msg+".")
+}
+
+func (m aliasExportMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// getBundlePassword returns the passphrase of an alias bundle from
+// MC_ALIAS_BUNDLE_PASSWORD, or prompts for it on a terminal.
+func getBundlePassword(confirm bool) (string, *probe.Error) {
+	if password, ok := os.LookupEnv(mcEnvAliasBundlePassword); ok {
+		if password == "" {
+			return "", probe.NewError(errors.New("empty bundle passphrase"))
+		}
+		return password, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", probe.NewError(fmt.Errorf("set %s to give the bundle passphrase", mcEnvAliasBundlePassword))
+	}
+	readPassword := func(prompt string) (string, *probe.Error) {
+		fmt.Fprint(os.Stderr, console.Colorize(cred, prompt))
+		password, e := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if e != nil {
+			return "", probe.NewError(e)
+		}
+		return string(password), nil
+	}
+	password, err := readPassword("Enter bundle passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", probe.NewError(errors.New("empty bundle passphrase"))
+	}
+	if confirm {
+		again, err := readPassword("Confirm bundle passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != password {
+			return "", probe.NewError(errors.New("the passphrases do not match"))
+		}
+	}
+	return password, nil
+}
+
+// newAliasBundle returns the bundle of the given aliases, or of all the
+// aliases, with their secret keys resolved from the secret stores.
+func newAliasBundle(mcCfg *configV10, aliases []string) (aliasBundle, *probe.Error) {
+	bundle := aliasBundle{Version: aliasBundleVersion, Aliases: map[string]aliasConfigV10{}}
+	if len(aliases) == 0 {
+		for alias := range mcCfg.Aliases {
+			aliases = append(aliases, alias)
+		}
+	}
+	for _, alias := range aliases {
+		alias = cleanAlias(alias)
+		aliasCfg, ok := mcCfg.Aliases[alias]
+		if !ok {
+			return bundle, errInvalidAliasedURL(alias).Trace(alias)
+		}
+		if err := loadAliasSecret(alias, &aliasCfg); err != nil {
+			return bundle, err.Trace(alias)
+		}
+		bundle.Aliases[alias] = aliasCfg
+	}
+	return bundle, nil
+}
+
+// encodeAliasBundle marshals the bundle, encrypted when a password is given.
+func encodeAliasBundle(bundle aliasBundle, password string) ([]byte, *probe.Error) {
+	data, e := json.MarshalIndent(bundle, "", " ")
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if password == "" {
+		return append(data, '\n'), nil
+	}
+	data, e = madmin.EncryptData(password, data)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return data, nil
+}
+
+// decodeAliasBundle parses a bundle, the passphrase is only asked for
+// when the bundle is encrypted.
+func decodeAliasBundle(data []byte, getPassword func() (string, *probe.Error)) (aliasBundle, *probe.Error) {
+	var bundle aliasBundle
+	if !json.Valid(data) {
+		password, err := getPassword()
+		if err != nil {
+			return bundle, err
+		}
+		if data, e := madmin.DecryptData(password, bytes.NewReader(data)); e == nil {
+			return decodeAliasBundle(data, getPassword)
+		}
+		return bundle, probe.NewError(errors.New("unable to decrypt the bundle with the given passphrase"))
+	}
+	if e := json.Unmarshal(data, &bundle); e != nil {
+		return bundle, probe.NewError(e)
+	}
+	if bundle.Version != aliasBundleVersion {
+		return bundle, probe.NewError(fmt.Errorf("unsupported alias bundle version '%s'", bundle.Version))
+	}
+	return bundle, nil
+}
+
+func mainAliasExport(ctx *cli.Context) error {
+	for _, alias := range ctx.Args() {
+		if !isValidAlias(cleanAlias(alias)) {
+			fatalIf(errInvalidAlias(alias), "Invalid alias.")
+		}
+	}
+
+	file := ctx.String("file")
+	if ctx.Bool("encrypt") && file == "" && isTerminal() {
+		fatalIf(errInvalidArgument(), "An encrypted bundle cannot be written to a terminal, use --file.")
+	}
+
+	mcCfg, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
+
+	bundle, err := newAliasBundle(mcCfg, ctx.Args())
+	fatalIf(err, "Unable to export the aliases.")
+
+	var password string
+	if ctx.Bool("encrypt") {
+		password, err = getBundlePassword(true)
+		fatalIf(err, "Unable to read the bundle passphrase.")
+	}
+	data, err := encodeAliasBundle(bundle, password)
+	fatalIf(err, "Unable to export the aliases.")
+
+	if file == "" {
+		_, e := os.Stdout.Write(data)
+		fatalIf(probe.NewError(e), "Unable to write the bundle.")
+		return nil
+	}
+	e := os.WriteFile(file, data, 0o600)
+	fatalIf(probe.NewError(e).Trace(file), "Unable to write the bundle to `"+file+"`.")
+
+	msg := aliasExportMessage{File: file, Encrypted: password != ""}
+	for alias := range bundle.Aliases {
+		msg.Aliases = append(msg.Aliases, alias)
+	}
+	sort.Strings(msg.Aliases)
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestAliasBundle(t *testing.T) {
+	bundle := aliasBundle{Version: aliasBundleVersion, Aliases: map[string]aliasConfigV10{
+		"prod": {URL: "https://prod.example.com", AccessKey: "access", SecretKey: "secret1234", API: "s3v4", Path: "auto"},
+	}}
+	password := func(p string) func() (string, *probe.Error) {
+		return func() (string, *probe.Error) { return p, nil }
+	}
+
+	for _, p := range []string{"", "passphrase"} {
+		data, err := encodeAliasBundle(bundle, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodeAliasBundle(data, password(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Aliases["prod"].SecretKey != "secret1234" {
+			t.Errorf("expected the secret key to be kept, got %+v", got.Aliases["prod"])
+		}
+	}
+
+	data, err := encodeAliasBundle(bundle, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = decodeAliasBundle(data, password("wrong")); err == nil {
+		t.Error("expected an error with a wrong passphrase")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"github.com/minio/cli"
+)
+
+var aliasGroupAddCmd = cli.Command{
+	Name:         "add",
+	Usage:        "add or replace a group of aliases",
+	Action:       mainAliasGroupAdd,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} GROUP ALIAS [ALIAS...]
+
+  A group can be used in place of an alias with the read-only commands
+  ls, stat, du, admin info and replicate status, running the command
+  against every alias of the group.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Add a group 'prod' for three sites:
+     {{.Prompt}} {{.HelpName}} prod site1 site2 site3
+
+  2. List the buckets of all sites in the group 'prod':
+     {{.Prompt}} mc ls prod
+`,
+}
+
+// checkAliasGroupAddSyntax - verifies input arguments to 'alias group add'.
+func checkAliasGroupAddSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	group := cleanAlias(args.Get(0))
+	if !isValidAlias(group) {
+		fatalIf(errDummy().Trace(group), "Invalid group name `"+group+"`.")
+	}
+	if mustGetHostConfig(group) != nil {
+		fatalIf(errDummy().Trace(group), "Group name `"+group+"` is already used by an alias.")
+	}
+	for _, alias := range args.Tail() {
+		aliasMustExist(cleanAlias(alias))
+	}
+}
+
+// mainAliasGroupAdd is the handle for "mc alias group add" command.
+func mainAliasGroupAdd(ctx *cli.Context) error {
+	checkAliasGroupAddSyntax(ctx)
+	setAliasGroupColors()
+
+	args := ctx.Args()
+	group := cleanAlias(args.Get(0))
+
+	var aliases []string
+	seen := map[string]bool{}
+	for _, alias := range args.Tail() {
+		alias = cleanAlias(alias)
+		if !seen[alias] {
+			seen[alias] = true
+			aliases = append(aliases, alias)
+		}
+	}
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	if conf.Groups == nil {
+		conf.Groups = make(map[string][]string)
+	}
+	conf.Groups[group] = aliases
+
+	err = saveMcConfig(conf)
+	fatalIf(err.Trace(group), "Unable to save group `"+group+"` in config version `"+globalMCConfigVersion+"`.")
+
+	printMsg(aliasGroupMessage{Group: group, Aliases: aliases})
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"sort"
+
+	"github.com/minio/cli"
+)
+
+var aliasGroupListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list groups of aliases",
+	Action:       mainAliasGroupList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [GROUP]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all groups:
+     {{.Prompt}} {{.HelpName}}
+
+  2. List the aliases of the group 'prod':
+     {{.Prompt}} {{.HelpName}} prod
+`,
+}
+
+// mainAliasGroupList is the handle for "mc alias group list" command.
+func mainAliasGroupList(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setAliasGroupColors()
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	group := cleanAlias(ctx.Args().Get(0))
+	if group != "" {
+		if _, ok := conf.Groups[group]; !ok {
+			fatalIf(errDummy().Trace(group), "No such group `"+group+"` found.")
+		}
+	}
+
+	groups := make([]string, 0, len(conf.Groups))
+	for name := range conf.Groups {
+		if group == "" || name == group {
+			groups = append(groups, name)
+		}
+	}
+	sort.Strings(groups)
+
+	for _, name := range groups {
+		printMsg(aliasGroupMessage{op: "list", Group: name, Aliases: conf.Groups[name]})
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"github.com/minio/cli"
+)
+
+var aliasGroupRemoveCmd = cli.Command{
+	Name:         "remove",
+	ShortName:    "rm",
+	Usage:        "remove a group of aliases",
+	Action:       mainAliasGroupRemove,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} GROUP
+
+  The aliases of the group are not removed.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove the group 'prod':
+     {{.Prompt}} {{.HelpName}} prod
+`,
+}
+
+// mainAliasGroupRemove is the handle for "mc alias group remove" command.
+func mainAliasGroupRemove(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setAliasGroupColors()
+
+	group := cleanAlias(ctx.Args().Get(0))
+
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	if _, ok := conf.Groups[group]; !ok {
+		fatalIf(errDummy().Trace(group), "No such group `"+group+"` found.")
+	}
+	delete(conf.Groups, group)
+
+	err = saveMcConfig(conf)
+	fatalIf(err.Trace(group), "Unable to remove group `"+group+"` in config version `"+globalMCConfigVersion+"`.")
+
+	printMsg(aliasGroupMessage{op: "remove", Group: group})
+	return nil
+}
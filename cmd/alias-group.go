@@ -0,0 +1,258 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var aliasGroupSubcommands = []cli.Command{
+	aliasGroupAddCmd,
+	aliasGroupListCmd,
+	aliasGroupRemoveCmd,
+}
+
+var aliasGroupCmd = cli.Command{
+	Name:            "group",
+	Usage:           "manage named groups of aliases",
+	Action:          mainAliasGroup,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     aliasGroupSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainAliasGroup is the handle for "mc alias group" command.
+func mainAliasGroup(ctx *cli.Context) error {
+	commandNotFound(ctx, aliasGroupSubcommands)
+	return nil
+	// Sub-commands like "add", "list" have their own main.
+}
+
+// aliasGroupMessage container for alias group messages
+type aliasGroupMessage struct {
+	op      string
+	Status  string   `json:"status"`
+	Group   string   `json:"group"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+func (m aliasGroupMessage) String() string {
+	switch m.op {
+	case "list":
+		return console.Colorize("AliasGroup", m.Group) + ": " + strings.Join(m.Aliases, ", ")
+	case "remove":
+		return console.Colorize("AliasMessage", "Removed group `"+m.Group+"` successfully.")
+	}
+	return console.Colorize("AliasMessage", "Added group `"+m.Group+"` with "+strings.Join(m.Aliases, ", ")+" successfully.")
+}
+
+func (m aliasGroupMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+func setAliasGroupColors() {
+	console.SetColor("AliasMessage", color.New(color.FgGreen))
+	console.SetColor("AliasGroup", color.New(color.FgCyan, color.Bold))
+	console.SetColor("AliasGroupError", color.New(color.FgRed, color.Bold))
+}
+
+// getAliasGroup returns the members of the alias group, an alias
+// always takes precedence over a group with the same name.
+func getAliasGroup(name string) ([]string, bool) {
+	mcCfg, err := loadMcConfig()
+	if err != nil {
+		return nil, false
+	}
+	if _, ok := mcCfg.Aliases[name]; ok {
+		return nil, false
+	}
+	members, ok := mcCfg.Groups[name]
+	return members, ok && len(members) > 0
+}
+
+// aliasGroupResult - output of a command run against one member of a group.
+type aliasGroupResult struct {
+	Alias   string            `json:"alias"`
+	Status  string            `json:"status"`
+	Error   string            `json:"error,omitempty"`
+	Results []json.RawMessage `json:"results,omitempty"`
+}
+
+// aliasGroupRunMessage - merged output of a command run against a group.
+type aliasGroupRunMessage struct {
+	Status  string             `json:"status"`
+	Group   string             `json:"group"`
+	Aliases []aliasGroupResult `json:"aliases"`
+}
+
+// commandLineArgs rebuilds the arguments running the command of ctx again,
+// from the parsed command rather than os.Args which is not the command
+// run in 'mc shell'. The positional arguments found in replace are replaced.
+func commandLineArgs(ctx *cli.Context, replace map[string]string) []string {
+	args := strings.Fields(commandPath(ctx))
+	for _, f := range ctx.Command.Flags {
+		name := strings.TrimSpace(strings.Split(f.GetName(), ",")[0])
+		local := ctx.IsSet(name)
+		if !local && !ctx.GlobalIsSet(name) {
+			continue
+		}
+		flag := "--" + name
+		switch f.(type) {
+		case cli.BoolFlag, cli.BoolTFlag:
+			v := ctx.Bool(name)
+			if !local {
+				v = ctx.GlobalBool(name)
+			}
+			args = append(args, fmt.Sprintf("%s=%t", flag, v))
+		case cli.StringSliceFlag:
+			v := ctx.StringSlice(name)
+			if !local {
+				v = ctx.GlobalStringSlice(name)
+			}
+			for _, s := range v {
+				args = append(args, flag+"="+s)
+			}
+		case cli.IntFlag:
+			v := ctx.Int(name)
+			if !local {
+				v = ctx.GlobalInt(name)
+			}
+			args = append(args, fmt.Sprintf("%s=%d", flag, v))
+		case cli.Int64Flag:
+			v := ctx.Int64(name)
+			if !local {
+				v = ctx.GlobalInt64(name)
+			}
+			args = append(args, fmt.Sprintf("%s=%d", flag, v))
+		case cli.DurationFlag:
+			v := ctx.Duration(name)
+			if !local {
+				v = ctx.GlobalDuration(name)
+			}
+			args = append(args, flag+"="+v.String())
+		default:
+			v := ctx.String(name)
+			if !local {
+				v = ctx.GlobalString(name)
+			}
+			args = append(args, flag+"="+v)
+		}
+	}
+
+	positional := []string(ctx.Args())
+	for _, arg := range positional {
+		if strings.HasPrefix(arg, "-") {
+			args = append(args, "--")
+			break
+		}
+	}
+	for _, arg := range positional {
+		if r, ok := replace[arg]; ok {
+			arg = r
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+// runOnAliasGroup runs the current command once for every alias of the group
+// when the target of the command is an alias group. Each run is a separate
+// process so a failing site does not stop the remaining ones. The output is
+// printed per alias, or merged into a single document with --json. Exits
+// with an error status when the command failed for any alias. Returns
+// false when the target is not a group.
+func runOnAliasGroup(ctx *cli.Context) bool {
+	var target, group, path string
+	var members []string
+	for _, arg := range ctx.Args() {
+		alias, p := url2Alias(arg)
+		if m, ok := getAliasGroup(alias); ok {
+			target, group, path, members = arg, alias, p, m
+			break
+		}
+	}
+	if group == "" {
+		return false
+	}
+
+	executable, e := os.Executable()
+	fatalIf(probe.NewError(e), "Unable to find the mc executable.")
+
+	setAliasGroupColors()
+
+	var failed bool
+	groupMsg := aliasGroupRunMessage{Status: "success", Group: group}
+	for _, member := range members {
+		args := commandLineArgs(ctx, map[string]string{target: urlJoinPath(member, path)})
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(globalContext, executable, args...)
+		cmd.Stdout, cmd.Stderr, cmd.Env = &stdout, &stderr, os.Environ()
+		e := cmd.Run()
+		failed = failed || e != nil
+
+		if !globalJSON {
+			console.Println(console.Colorize("AliasGroup", fmt.Sprintf("── %s ──", member)))
+			os.Stdout.Write(stdout.Bytes())
+			if e != nil {
+				console.Println(console.Colorize("AliasGroupError", strings.TrimSpace(stderr.String())))
+			}
+			console.Println()
+			continue
+		}
+
+		result := aliasGroupResult{Alias: member, Status: "success"}
+		dec := json.NewDecoder(io.MultiReader(&stdout, &stderr))
+		for {
+			var v json.RawMessage
+			if dec.Decode(&v) != nil {
+				break
+			}
+			result.Results = append(result.Results, v)
+		}
+		if e != nil {
+			result.Status, result.Error = "error", e.Error()
+			groupMsg.Status = "error"
+		}
+		groupMsg.Aliases = append(groupMsg.Aliases, result)
+	}
+
+	if globalJSON {
+		sort.Slice(groupMsg.Aliases, func(i, j int) bool {
+			return groupMsg.Aliases[i].Alias < groupMsg.Aliases[j].Alias
+		})
+		console.Println(toJSON(groupMsg))
+	}
+	if failed {
+		exitProcess(globalErrorExitStatus)
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestCommandLineArgs(t *testing.T) {
+	var got []string
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{cli.BoolFlag{Name: "json"}}
+	app.Commands = []cli.Command{{
+		Name:     "ls",
+		HelpName: "mc ls",
+		Flags: []cli.Flag{
+			cli.BoolFlag{Name: "recursive, r"},
+			cli.StringFlag{Name: "rewind"},
+			cli.IntFlag{Name: "depth"},
+			cli.DurationFlag{Name: "interval"},
+			cli.StringSliceFlag{Name: "exclude"},
+			cli.BoolFlag{Name: "json"},
+		},
+		Action: func(ctx *cli.Context) error {
+			got = commandLineArgs(ctx, map[string]string{"prod/bucket": "site1/bucket"})
+			return nil
+		},
+	}}
+
+	testCases := []struct {
+		args []string
+		want []string
+	}{
+		{
+			args: []string{"mc", "ls", "prod/bucket"},
+			want: []string{"ls", "site1/bucket"},
+		},
+		{
+			args: []string{"mc", "--json", "ls", "-r", "--rewind", "1d", "--depth", "2", "--interval", "1m",
+				"--exclude", "a*", "--exclude", "b*", "prod/bucket", "other/bucket"},
+			want: []string{"ls", "--recursive=true", "--rewind=1d", "--depth=2", "--interval=" + time.Minute.String(),
+				"--exclude=a*", "--exclude=b*", "--json=true", "site1/bucket", "other/bucket"},
+		},
+		{
+			args: []string{"mc", "ls", "--", "-odd", "prod/bucket"},
+			want: []string{"ls", "--", "-odd", "site1/bucket"},
+		},
+	}
+	for i, testCase := range testCases {
+		got = nil
+		if e := app.Run(testCase.args); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if !reflect.DeepEqual(got, testCase.want) {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.want, got)
+		}
+	}
+}
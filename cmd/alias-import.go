@@ -20,6 +20,7 @@ package cmd
 import (
 	"encoding/json"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/kirolous/mc/pkg/probe"
@@ -27,6 +28,14 @@ import (
 	"github.com/minio/cli"
 )
 
+var aliasImportFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "bundle",
+		Usage: "import the aliases of a bundle created by 'mc alias export'",
+	},
+	aliasSecretStoreFlag,
+}
+
 var aliasImportCmd = cli.Command{
 	Name:            "import",
 	ShortName:       "i",
@@ -34,13 +43,14 @@ var aliasImportCmd = cli.Command{
 	Action:          mainAliasImport,
 	OnUsageError:    onUsageError,
 	Before:          setGlobalsFromContext,
-	Flags:           globalFlags,
+	Flags:           append(aliasImportFlags, globalFlags...),
 	HideHelpCommand: true,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
   {{.HelpName}} ALIAS ./credentials.json
+  {{.HelpName}} --bundle FILE [ALIAS...]
 
   Credentials to be imported must be in the following JSON format:
   
@@ -52,6 +62,9 @@ USAGE:
     "path": "auto"
   }
 
+  A bundle imports all its aliases, or only the given ones. The passphrase of
+  an encrypted bundle is prompted for or read from MC_ALIAS_BUNDLE_PASSWORD.
+
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
@@ -61,6 +74,9 @@ EXAMPLES:
 
   2. Import the credentials through standard input as 'myminio' to the config:
      {{ .Prompt }} cat credentials.json | {{ .HelpName }} myminio/
+
+  3. Import the aliases "prod" and "staging" from an encrypted bundle, keeping the secret keys in the OS keychain:
+     {{ .Prompt }} {{ .HelpName }} --bundle team-aliases.enc --secret-store keychain prod staging
 `,
 }
 
@@ -69,6 +85,20 @@ func checkAliasImportSyntax(ctx *cli.Context) {
 	args := ctx.Args()
 	argsNr := len(args)
 
+	if store := ctx.String("secret-store"); !isValidSecretStore(store) {
+		fatalIf(errInvalidArgument().Trace(store),
+			"Unrecognized secret store. Valid options are `[keychain, encrypted]`.")
+	}
+
+	if ctx.IsSet("bundle") {
+		for _, alias := range args {
+			if !isValidAlias(cleanAlias(alias)) {
+				fatalIf(errInvalidAlias(alias), "Invalid alias.")
+			}
+		}
+		return
+	}
+
 	if argsNr == 0 {
 		showCommandHelpAndExit(ctx, 1)
 	}
@@ -101,7 +131,7 @@ func checkCredentialsSyntax(credentials aliasConfigV10) {
 
 	if credentials.API != "" && !isValidAPI(credentials.API) { // Empty value set to default "S3v4".
 		fatalIf(errInvalidArgument().Trace(credentials.API),
-			"Unrecognized API signature. Valid options are `[S3v4, S3v2]`.")
+			"Unrecognized API signature. Valid options are `[S3v4, S3v2, azure, gcs, sftp, hdfs]`.")
 	}
 	if !isValidPath(credentials.Path) {
 		fatalIf(errInvalidArgument().Trace(credentials.Path),
@@ -111,8 +141,6 @@ func checkCredentialsSyntax(credentials aliasConfigV10) {
 
 // importAlias - set an alias config based on imported values.
 func importAlias(alias string, aliasCfgV10 aliasConfigV10) aliasMessage {
-	checkCredentialsSyntax(aliasCfgV10)
-
 	mcCfgV10, err := loadMcConfig()
 	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config `"+mustGetMcConfigPath()+"`.")
 
@@ -129,6 +157,44 @@ func importAlias(alias string, aliasCfgV10 aliasConfigV10) aliasMessage {
 	}
 }
 
+// importAliasBundle - import the aliases of a bundle, all of them when
+// none is selected.
+func importAliasBundle(bundleFile string, aliases []string, store string) {
+	input, e := os.ReadFile(bundleFile)
+	fatalIf(probe.NewError(e).Trace(bundleFile), "Unable to read the bundle `"+bundleFile+"`.")
+
+	bundle, err := decodeAliasBundle(input, func() (string, *probe.Error) {
+		return getBundlePassword(false)
+	})
+	fatalIf(err.Trace(bundleFile), "Unable to read the bundle `"+bundleFile+"`.")
+
+	if len(aliases) == 0 {
+		for alias := range bundle.Aliases {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+	}
+	for _, alias := range aliases {
+		alias = cleanAlias(alias)
+		aliasCfg, ok := bundle.Aliases[alias]
+		if !ok {
+			fatalIf(errInvalidArgument().Trace(alias), "No such alias `"+alias+"` in the bundle.")
+		}
+		if aliasCfg.CredentialSource == nil {
+			checkCredentialsSyntax(aliasCfg)
+		} else if !isValidHostURL(aliasCfg.URL) {
+			fatalIf(errInvalidURL(aliasCfg.URL), "Invalid URL.")
+		}
+		if store != "" && aliasCfg.CredentialSource == nil {
+			fatalIf(storeAliasSecret(alias, &aliasCfg, store), "Unable to store the secret key of `"+alias+"`.")
+		}
+		msg := importAlias(alias, aliasCfg)
+		msg.op = "import"
+		msg.SecretKey = ""
+		printMsg(msg)
+	}
+}
+
 func mainAliasImport(cli *cli.Context) error {
 	var (
 		args  = cli.Args()
@@ -136,6 +202,11 @@ func mainAliasImport(cli *cli.Context) error {
 	)
 
 	checkAliasImportSyntax(cli)
+	if cli.IsSet("bundle") {
+		importAliasBundle(cli.String("bundle"), args, cli.String("secret-store"))
+		return nil
+	}
+
 	var credentialsJSON aliasConfigV10
 
 	credsFile := strings.TrimSpace(args.Get(1))
@@ -148,6 +219,10 @@ func mainAliasImport(cli *cli.Context) error {
 	e = json.Unmarshal(input, &credentialsJSON)
 	fatalIf(probe.NewError(e).Trace(args...), "Unable to parse input credentials")
 
+	checkCredentialsSyntax(credentialsJSON)
+	if store := cli.String("secret-store"); store != "" {
+		fatalIf(storeAliasSecret(alias, &credentialsJSON, store), "Unable to store the secret key of `"+alias+"`.")
+	}
 	msg := importAlias(alias, credentialsJSON)
 	msg.op = cli.Command.Name
 
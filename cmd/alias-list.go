@@ -99,7 +99,7 @@ func printAliases(aliases ...aliasMessage) {
 			// Format properly for alignment based on alias length only in non json mode.
 			alias.Alias = fmt.Sprintf("%-*.*s", maxAlias, maxAlias, alias.Alias)
 		}
-		if alias.AccessKey == "" || alias.SecretKey == "" {
+		if (alias.AccessKey == "" || alias.SecretKey == "") && alias.Credentials == "" {
 			alias.AccessKey = ""
 			alias.SecretKey = ""
 			alias.API = ""
@@ -131,6 +131,12 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 				SecretKey:   v.SecretKey,
 				API:         v.API,
 			}
+			if v.CredentialSource != nil {
+				aliasMsg.Credentials = v.CredentialSource.String()
+			}
+			if v.SecretStore != "" {
+				aliasMsg.SecretKey = "<" + v.SecretStore + ">"
+			}
 
 			if deprecated {
 				aliasMsg.Lookup = v.Path
@@ -152,6 +158,12 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 			SecretKey:   v.SecretKey,
 			API:         v.API,
 		}
+		if v.CredentialSource != nil {
+			aliasMsg.Credentials = v.CredentialSource.String()
+		}
+		if v.SecretStore != "" {
+			aliasMsg.SecretKey = "<" + v.SecretStore + ">"
+		}
 
 		if deprecated {
 			aliasMsg.Lookup = v.Path
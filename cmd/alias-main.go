@@ -42,6 +42,12 @@ var aliasSubcommands = []cli.Command{
 	aliasListCmd,
 	aliasRemoveCmd,
 	aliasImportCmd,
+	aliasExportCmd,
+	aliasGroupCmd,
+	aliasDefaultsCmd,
+	aliasUseCmd,
+	aliasVerifyCmd,
+	aliasProtectCmd,
 }
 
 var aliasCmd = cli.Command{
@@ -72,6 +78,7 @@ type aliasMessage struct {
 	SecretKey   string `json:"secretKey,omitempty"`
 	API         string `json:"api,omitempty"`
 	Path        string `json:"path,omitempty"`
+	Credentials string `json:"credentials,omitempty"`
 	// Deprecated field, replaced by Path
 	Lookup string `json:"lookup,omitempty"`
 }
@@ -91,12 +98,17 @@ func (h aliasMessage) String() string {
 			Row{"API", "API"},
 			Row{"Path", "Path"},
 		)
+		// Show where the keys come from when not static
+		accessKey := h.AccessKey
+		if h.Credentials != "" {
+			accessKey = "<" + h.Credentials + ">"
+		}
 		// Handle deprecated lookup
 		path := h.Path
 		if path == "" {
 			path = h.Lookup
 		}
-		return t.buildRecord(h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, path)
+		return t.buildRecord(h.Alias, h.URL, accessKey, h.SecretKey, h.API, path)
 	case "remove":
 		return console.Colorize("AliasMessage", "Removed `"+h.Alias+"` successfully.")
 	case "add": // add is deprecated
@@ -0,0 +1,329 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+	"golang.org/x/term"
+)
+
+var aliasProtectFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "readonly",
+		Usage: "reject all the commands modifying the alias",
+	},
+	cli.BoolFlag{
+		Name:  "guard",
+		Usage: "ask for the alias name before running destructive commands on the alias",
+	},
+	cli.BoolFlag{
+		Name:  "none",
+		Usage: "remove the protection of the alias",
+	},
+}
+
+var aliasProtectCmd = cli.Command{
+	Name:            "protect",
+	Usage:           "protect an alias from modifications",
+	Action:          mainAliasProtect,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(aliasProtectFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS [--readonly | --guard | --none]
+
+  Read-only aliases reject every command not known to only read them,
+  e.g cp, rm, put or admin config set. Guarded aliases require typing
+  the alias name before running destructive commands like rm --recursive,
+  rb --force, mirror --remove or admin service stop. The protection is client side only.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the protection of the alias "prod".
+     {{.Prompt}} {{.HelpName}} prod
+
+  2. Make the alias "archive" read-only.
+     {{.Prompt}} {{.HelpName}} archive --readonly
+
+  3. Ask for confirmation before destructive commands on "prod".
+     {{.Prompt}} {{.HelpName}} prod --guard
+
+  4. Remove the protection of "prod".
+     {{.Prompt}} {{.HelpName}} prod --none
+`,
+}
+
+// Protection modes of an alias.
+const (
+	aliasProtectionNone     = ""
+	aliasProtectionReadOnly = "readonly"
+	aliasProtectionGuard    = "guard"
+)
+
+// aliasProtectMessage container for alias protection
+type aliasProtectMessage struct {
+	Status     string `json:"status"`
+	Alias      string `json:"alias"`
+	Protection string `json:"protection"`
+}
+
+func (m aliasProtectMessage) String() string {
+	switch m.Protection {
+	case aliasProtectionReadOnly:
+		return console.Colorize("AliasMessage", "Alias `"+m.Alias+"` is read-only.")
+	case aliasProtectionGuard:
+		return console.Colorize("AliasMessage", "Alias `"+m.Alias+"` is guarded against destructive commands.")
+	}
+	return console.Colorize("AliasMessage", "Alias `"+m.Alias+"` is not protected.")
+}
+
+func (m aliasProtectMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+// mainAliasProtect is the handle for "mc alias protect" command.
+func mainAliasProtect(ctx *cli.Context) error {
+	var modes int
+	for _, flag := range []string{"readonly", "guard", "none"} {
+		if ctx.Bool(flag) {
+			modes++
+		}
+	}
+	if len(ctx.Args()) != 1 || modes > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	console.SetColor("AliasMessage", color.New(color.FgGreen))
+
+	alias := cleanAlias(ctx.Args().Get(0))
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	aliasCfg, ok := conf.Aliases[alias]
+	if !ok {
+		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+	}
+
+	if modes == 1 {
+		switch {
+		case ctx.Bool("readonly"):
+			aliasCfg.Protection = aliasProtectionReadOnly
+		case ctx.Bool("guard"):
+			aliasCfg.Protection = aliasProtectionGuard
+		default:
+			aliasCfg.Protection = aliasProtectionNone
+		}
+		conf.Aliases[alias] = aliasCfg
+		err = saveMcConfig(conf)
+		fatalIf(err.Trace(alias), "Unable to save the protection of `"+alias+"` in config version `"+globalMCConfigVersion+"`.")
+	}
+
+	printMsg(aliasProtectMessage{Alias: alias, Protection: aliasCfg.Protection})
+	return nil
+}
+
+// Commands writing only to their last argument, the others are sources.
+var targetOnlyCommands = map[string]bool{
+	"cp":             true,
+	"mirror":         true,
+	"od":             true,
+	"put":            true,
+	"compose":        true,
+	"website deploy": true,
+}
+
+// Commands never modifying an alias, every other command is assumed to
+// modify its arguments so that new commands are protected by default.
+var readOnlyCommands = map[string]bool{
+	"ls": true, "cat": true, "get": true, "head": true, "tail": true, "find": true,
+	"sql": true, "stat": true, "tree": true, "du": true, "diff": true, "watch": true,
+	"ready": true, "ping": true, "verify": true, "access-report": true,
+	"share download": true, "ilm tier check": true, "replicate bandwidth status": true,
+	"admin speedtest": true, "admin health": true, "admin trace": true,
+	"admin console": true, "admin logs": true, "admin top api": true, "admin top locks": true,
+	"admin scanner trace": true, "admin policy entities": true, "admin policy tree": true,
+	"admin prometheus generate": true, "admin prometheus metrics": true,
+	"support diag": true, "support perf": true, "support inspect": true, "support profile": true,
+	"support top api": true, "support top drive": true, "support top locks": true,
+	"migrate assess": true, "inventory generate": true, "batch generate": true, "batch validate": true,
+}
+
+// Commands managing the local configuration, their arguments are not aliases to modify.
+var localCommands = map[string]bool{
+	"alias": true, "config": true, "completion": true, "update": true, "shell": true,
+	"session": true, "plugin": true, "daemon": true,
+}
+
+// Subcommands named with one of these verbs only read their arguments.
+var readOnlyVerbs = map[string]bool{
+	"info": true, "list": true, "ls": true, "status": true, "get": true, "export": true,
+	"show": true, "stats": true, "history": true, "describe": true, "failures": true,
+	"verify": true, "test": true, "diff": true, "usage": true, "format-status": true,
+	"report": true, "api-versions": true, "inspect": true, "check": true,
+}
+
+// Read-only subcommands given as first argument, e.g "mc anonymous get".
+var readOnlyArgVerbs = map[string]map[string]bool{
+	"anonymous": {"get": true, "get-json": true, "list": true, "links": true, "audit": true},
+	"policy":    {"get": true, "get-json": true, "list": true, "links": true},
+}
+
+// destructiveCommands returns true when the command needs a confirmation on guarded aliases.
+var destructiveCommands = map[string]func(ctx *cli.Context) bool{
+	"rm":                       func(ctx *cli.Context) bool { return ctx.Bool("recursive") || ctx.Bool("versions") || ctx.Bool("force") },
+	"rb":                       func(ctx *cli.Context) bool { return ctx.Bool("force") },
+	"mirror":                   func(ctx *cli.Context) bool { return ctx.Bool("remove") },
+	"admin service stop":       func(ctx *cli.Context) bool { return true },
+	"admin service restart":    func(ctx *cli.Context) bool { return true },
+	"admin decommission start": func(ctx *cli.Context) bool { return true },
+	"admin replicate failover": func(ctx *cli.Context) bool { return true },
+}
+
+// commandPath returns the command without the program name, e.g "admin service stop".
+func commandPath(ctx *cli.Context) string {
+	if ctx.Command.HelpName == "" {
+		return ""
+	}
+	fields := strings.Fields(ctx.Command.HelpName)
+	return strings.Join(fields[1:], " ")
+}
+
+// isReadOnlyCommand returns true when the command does not modify its arguments.
+func isReadOnlyCommand(ctx *cli.Context, path string) bool {
+	fields := strings.Fields(path)
+	switch {
+	case localCommands[fields[0]]:
+		return true
+	case readOnlyCommands[path]:
+		return true
+	case len(fields) > 1 && readOnlyVerbs[fields[len(fields)-1]]:
+		return true
+	case readOnlyArgVerbs[path][ctx.Args().First()]:
+		return true
+	case path == "mount":
+		return ctx.Bool("read-only")
+	case path == "apply":
+		return ctx.Bool("check") || ctx.Bool("plan")
+	}
+	return false
+}
+
+// protectedArgs returns the arguments modified by the command.
+func protectedArgs(ctx *cli.Context, path string) []string {
+	args := []string(ctx.Args())
+	switch {
+	case len(args) == 0:
+		return nil
+	case targetOnlyCommands[path] || readOnlyArgVerbs[path] != nil:
+		return args[len(args)-1:]
+	case strings.HasPrefix(path, "admin ") && !strings.HasPrefix(path, "admin replicate "):
+		// The alias comes first, site replication commands take several.
+		return args[:1]
+	}
+	return args
+}
+
+// protectedAliases returns the aliases modified by the command.
+func protectedAliases(ctx *cli.Context, path string) (aliases []string) {
+	if isReadOnlyCommand(ctx, path) {
+		return nil
+	}
+	args := protectedArgs(ctx, path)
+	seen := map[string]bool{}
+	add := func(alias string) {
+		if alias != "" && !seen[alias] {
+			seen[alias] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	for _, arg := range args {
+		if alias, _ := url2Alias(arg); mustGetHostConfig(alias) != nil {
+			add(alias)
+		}
+	}
+	if len(aliases) > 0 {
+		return aliases
+	}
+	// Without an explicit alias, the arguments are relative to the current alias.
+	for _, arg := range args {
+		alias, _, _ := mustExpandAlias(arg)
+		add(alias)
+	}
+	return aliases
+}
+
+// checkAliasProtection rejects mutating commands on read-only aliases and
+// asks for confirmation before destructive commands on guarded aliases.
+func checkAliasProtection(ctx *cli.Context) {
+	path := commandPath(ctx)
+	if path == "" || loadMcConfig == nil {
+		return
+	}
+	for _, alias := range protectedAliases(ctx, path) {
+		checkAliasWritable(ctx, alias, path)
+	}
+}
+
+// checkAliasWritable exits when the alias is read-only, and asks for
+// confirmation when it is guarded and the command is destructive.
+func checkAliasWritable(ctx *cli.Context, alias, path string) {
+	if alias == "" {
+		return
+	}
+	conf, err := loadMcConfig()
+	if err != nil {
+		return
+	}
+	aliasCfg, ok := conf.Aliases[alias]
+	if !ok {
+		return
+	}
+	switch aliasCfg.Protection {
+	case aliasProtectionReadOnly:
+		fatalIf(errDummy().Trace(alias), "Alias `"+alias+"` is read-only, `mc "+path+"` is not allowed. Use 'mc alias protect "+alias+" --none' to allow it.")
+	case aliasProtectionGuard:
+		if destructive, ok := destructiveCommands[path]; ok && destructive(ctx) {
+			confirmGuardedAlias(alias, path)
+		}
+	}
+}
+
+// confirmGuardedAlias asks to type the alias name, exits if it does not match.
+func confirmGuardedAlias(alias, path string) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fatalIf(errDummy().Trace(alias), "Alias `"+alias+"` is guarded, `mc "+path+"` needs an interactive confirmation.")
+	}
+	fmt.Fprint(os.Stderr, console.Colorize(cred, fmt.Sprintf("Alias `%s` is guarded. Type the alias name to run `mc %s`: ", alias, path)))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if strings.TrimSpace(answer) != alias {
+		fatalIf(errDummy().Trace(alias), "Confirmation does not match, `mc "+path+"` aborted.")
+	}
+}
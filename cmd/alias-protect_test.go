@@ -0,0 +1,91 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+)
+
+func TestProtectedAliases(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) {
+		conf := newConfigV10()
+		conf.Aliases["prod"] = aliasConfigV10{URL: "https://prod", Protection: aliasProtectionReadOnly}
+		conf.Aliases["dev"] = aliasConfigV10{URL: "https://dev"}
+		return conf, nil
+	}
+	t.Setenv(mcEnvAlias, "prod")
+
+	var got []string
+	newCommand := func(path string, flags ...cli.Flag) cli.Command {
+		fields := strings.Fields(path)
+		return cli.Command{
+			Name:     fields[len(fields)-1],
+			HelpName: "mc " + path,
+			Flags:    flags,
+			Action: func(ctx *cli.Context) error {
+				got = protectedAliases(ctx, commandPath(ctx))
+				return nil
+			},
+		}
+	}
+	app := cli.NewApp()
+	app.Commands = []cli.Command{
+		newCommand("cp"), newCommand("put"), newCommand("ls"), newCommand("rm"),
+		newCommand("anonymous"), newCommand("mount", cli.BoolFlag{Name: "read-only"}),
+		newCommand("meta set"), newCommand("cors add-rule"), newCommand("admin config get"),
+		newCommand("admin replicate failover"), newCommand("alias remove"),
+	}
+
+	testCases := []struct {
+		args []string
+		want []string
+	}{
+		// Sources are not modified.
+		{[]string{"cp", "prod/bucket/object", "dev/bucket"}, []string{"dev"}},
+		{[]string{"put", "file", "prod/bucket"}, []string{"prod"}},
+		{[]string{"ls", "prod/bucket"}, nil},
+		{[]string{"rm", "prod/bucket/object"}, []string{"prod"}},
+		// Without an alias, the arguments are relative to the current alias.
+		{[]string{"rm", "bucket/object"}, []string{"prod"}},
+		{[]string{"set", "prod/bucket/object", "key=value"}, []string{"prod"}},
+		{[]string{"set", "bucket/object", "key=value"}, []string{"prod"}},
+		{[]string{"add-rule", "dev/bucket", "rules.json"}, []string{"dev"}},
+		{[]string{"anonymous", "get", "prod/bucket"}, nil},
+		{[]string{"anonymous", "set", "public", "prod/bucket"}, []string{"prod"}},
+		{[]string{"mount", "prod/bucket", "/mnt"}, []string{"prod"}},
+		{[]string{"mount", "--read-only", "prod/bucket", "/mnt"}, nil},
+		{[]string{"get", "prod"}, nil},
+		{[]string{"failover", "dev", "prod"}, []string{"dev", "prod"}},
+		{[]string{"remove", "prod"}, nil},
+	}
+	for i, testCase := range testCases {
+		got = nil
+		if e := app.Run(append([]string{"mc"}, testCase.args...)); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if !reflect.DeepEqual(got, testCase.want) {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.want, got)
+		}
+	}
+}
@@ -83,6 +83,12 @@ func mainAliasRemove(ctx *cli.Context) error {
 // aliasMustExist confirms that a given alias is present in Aliases array, returns error if not found
 
 func aliasMustExist(alias string) {
+	// Avoid unlocking the secret key when the alias is in the config.
+	if conf, err := loadMcConfig(); err == nil {
+		if _, ok := conf.Aliases[alias]; ok {
+			return
+		}
+	}
 	hostConfig := mustGetHostConfig(alias)
 	if hostConfig == nil {
 		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
@@ -97,8 +103,13 @@ func removeAlias(alias string) aliasMessage {
 	// check if alias is valid
 	aliasMustExist(alias)
 
-	// Remove the alias from the config.
+	// Remove the alias and its secret key from the config.
+	removeAliasSecret(alias, conf.Aliases[alias])
 	delete(conf.Aliases, alias)
+	globalBucketRegions.Reset(alias)
+	if conf.CurrentAlias == alias {
+		conf.CurrentAlias = ""
+	}
 
 	err = saveMcConfig(conf)
 	fatalIf(err.Trace(alias), "Unable to save the delete alias in config version `"+globalMCConfigVersion+"`.")
@@ -0,0 +1,162 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+)
+
+// Supported stores for alias secret keys, the default is plaintext in config.json.
+const (
+	secretStoreKeychain  = "keychain"
+	secretStoreEncrypted = "encrypted"
+)
+
+const (
+	// mcEnvConfigPassword unlocks the encrypted secret keys.
+	mcEnvConfigPassword = "MC_CONFIG_PASSWORD"
+
+	// service name of the secret keys in the OS keychain.
+	keychainService = "mc"
+)
+
+var aliasSecretStoreFlag = cli.StringFlag{
+	Name:  "secret-store",
+	Usage: "store the secret key in the OS keychain or encrypted by a passphrase. Valid options are '[keychain, encrypted]'",
+}
+
+func isValidSecretStore(store string) bool {
+	switch store {
+	case "", secretStoreKeychain, secretStoreEncrypted:
+		return true
+	}
+	return false
+}
+
+// keychainUser returns the keychain account of the alias
+func keychainUser(alias string) string {
+	return alias + "@" + mustGetMcConfigPath()
+}
+
+var (
+	configPasswordOnce sync.Once
+	configPassword     string
+	configPasswordErr  *probe.Error
+)
+
+// getConfigPassword returns the passphrase for encrypted secret keys
+// from MC_CONFIG_PASSWORD, or prompts for it once on a terminal.
+func getConfigPassword() (string, *probe.Error) {
+	configPasswordOnce.Do(func() {
+		if password, ok := os.LookupEnv(mcEnvConfigPassword); ok {
+			configPassword = password
+			return
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			configPasswordErr = probe.NewError(fmt.Errorf("set %s to unlock the encrypted secret keys", mcEnvConfigPassword))
+			return
+		}
+		fmt.Fprint(os.Stderr, console.Colorize(cred, "Enter config password: "))
+		password, e := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if e != nil {
+			configPasswordErr = probe.NewError(e)
+			return
+		}
+		configPassword = string(password)
+	})
+	if configPasswordErr == nil && configPassword == "" {
+		return "", probe.NewError(errors.New("empty config password"))
+	}
+	return configPassword, configPasswordErr
+}
+
+// storeAliasSecret moves the secret key of the alias config to the requested
+// store, the value left in the config is a reference or the encrypted key.
+func storeAliasSecret(alias string, aliasCfg *aliasConfigV10, store string) *probe.Error {
+	aliasCfg.SecretStore = store
+	switch store {
+	case secretStoreKeychain:
+		if e := keyring.Set(keychainService, keychainUser(alias), aliasCfg.SecretKey); e != nil {
+			return probe.NewError(e).Trace(alias)
+		}
+		aliasCfg.SecretKey = ""
+	case secretStoreEncrypted:
+		password, err := getConfigPassword()
+		if err != nil {
+			return err.Trace(alias)
+		}
+		data, e := madmin.EncryptData(password, []byte(aliasCfg.SecretKey))
+		if e != nil {
+			return probe.NewError(e).Trace(alias)
+		}
+		aliasCfg.SecretKey = base64.StdEncoding.EncodeToString(data)
+	}
+	return nil
+}
+
+// loadAliasSecret resolves the secret key of an alias config read from
+// config.json when it is kept outside of plaintext.
+func loadAliasSecret(alias string, aliasCfg *aliasConfigV10) *probe.Error {
+	switch aliasCfg.SecretStore {
+	case secretStoreKeychain:
+		secretKey, e := keyring.Get(keychainService, keychainUser(alias))
+		if e != nil {
+			return probe.NewError(fmt.Errorf("unable to read the secret key of '%s' from the keychain: %w", alias, e))
+		}
+		aliasCfg.SecretKey = secretKey
+	case secretStoreEncrypted:
+		data, e := base64.StdEncoding.DecodeString(aliasCfg.SecretKey)
+		if e != nil {
+			return probe.NewError(e).Trace(alias)
+		}
+		password, err := getConfigPassword()
+		if err != nil {
+			return err.Trace(alias)
+		}
+		secretKey, e := madmin.DecryptData(password, bytes.NewReader(data))
+		if e != nil {
+			return probe.NewError(fmt.Errorf("unable to decrypt the secret key of '%s' with the given password", alias))
+		}
+		aliasCfg.SecretKey = string(secretKey)
+	default:
+		return nil
+	}
+	aliasCfg.SecretStore = ""
+	return nil
+}
+
+// removeAliasSecret removes the secret key of the alias from the keychain.
+func removeAliasSecret(alias string, aliasCfg aliasConfigV10) {
+	if aliasCfg.SecretStore == secretStoreKeychain {
+		if e := keyring.Delete(keychainService, keychainUser(alias)); e != nil && !errors.Is(e, keyring.ErrNotFound) {
+			errorIf(probe.NewError(e), "Unable to remove the secret key of `"+alias+"` from the keychain.")
+		}
+	}
+}
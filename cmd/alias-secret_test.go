@@ -0,0 +1,95 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestIsValidSecretStore(t *testing.T) {
+	testCases := []struct {
+		store string
+		valid bool
+	}{
+		{"", true},
+		{secretStoreKeychain, true},
+		{secretStoreEncrypted, true},
+		{"plaintext", false},
+		{"Keychain", false},
+	}
+	for i, testCase := range testCases {
+		if valid := isValidSecretStore(testCase.store); valid != testCase.valid {
+			t.Errorf("Test %d: expected %t for %q", i+1, testCase.valid, testCase.store)
+		}
+	}
+}
+
+func TestAliasSecretStores(t *testing.T) {
+	defer func(dir, profile string) {
+		setMcConfigDir(dir)
+		setMcConfigProfile(profile)
+	}(mcCustomConfigDir, mcConfigProfile)
+	setMcConfigDir(t.TempDir())
+	setMcConfigProfile("")
+
+	resetPassword := func() {
+		configPasswordOnce = sync.Once{}
+		configPassword, configPasswordErr = "", nil
+	}
+	defer resetPassword()
+	keyring.MockInit()
+
+	for _, store := range []string{secretStoreKeychain, secretStoreEncrypted} {
+		resetPassword()
+		t.Setenv(mcEnvConfigPassword, "passphrase")
+
+		aliasCfg := aliasConfigV10{AccessKey: "minio", SecretKey: "minio123"}
+		if err := storeAliasSecret("myminio", &aliasCfg, store); err != nil {
+			t.Fatalf("%s: %v", store, err)
+		}
+		if aliasCfg.SecretKey == "minio123" || aliasCfg.SecretStore != store {
+			t.Fatalf("%s: expected the secret key out of the config, got %+v", store, aliasCfg)
+		}
+
+		loaded := aliasCfg
+		if err := loadAliasSecret("myminio", &loaded); err != nil {
+			t.Fatalf("%s: %v", store, err)
+		}
+		if loaded.SecretKey != "minio123" || loaded.SecretStore != "" {
+			t.Errorf("%s: unexpected loaded alias %+v", store, loaded)
+		}
+
+		if store == secretStoreEncrypted {
+			resetPassword()
+			t.Setenv(mcEnvConfigPassword, "wrong")
+			loaded = aliasCfg
+			if err := loadAliasSecret("myminio", &loaded); err == nil {
+				t.Error("expected a wrong password to fail")
+			}
+		}
+	}
+
+	// Plaintext secret keys are left as they are.
+	aliasCfg := aliasConfigV10{SecretKey: "minio123"}
+	if err := loadAliasSecret("myminio", &aliasCfg); err != nil || aliasCfg.SecretKey != "minio123" {
+		t.Errorf("unexpected plaintext alias %+v, %v", aliasCfg, err)
+	}
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
+	"golang.org/x/term"
+)
+
+// aliasWizard prompts for the settings of an alias on the terminal.
+type aliasWizard struct {
+	reader *bufio.Reader
+}
+
+// ask prompts for a value, def is returned for an empty answer.
+func (w aliasWizard) ask(question, def string) string {
+	if def != "" {
+		question += " [" + def + "]"
+	}
+	fmt.Print(console.Colorize(cred, question+": "))
+	answer, _ := w.reader.ReadString('\n')
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def
+	}
+	return answer
+}
+
+// secret prompts for a value without echoing it.
+func (w aliasWizard) secret(question string) string {
+	fmt.Print(console.Colorize(cred, question+": "))
+	value, _ := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	return string(value)
+}
+
+// choose prompts until the first letter of the answer is one of choices,
+// def is returned for an empty answer.
+func (w aliasWizard) choose(question, choices string, def byte) byte {
+	for {
+		answer := strings.ToLower(w.ask(question, string(def)))
+		if answer != "" && strings.IndexByte(choices, answer[0]) >= 0 {
+			return answer[0]
+		}
+	}
+}
+
+func (w aliasWizard) pass(format string, args ...interface{}) {
+	fmt.Println(console.Colorize("passCell", tickCell) + fmt.Sprintf(format, args...))
+}
+
+func (w aliasWizard) fail(format string, args ...interface{}) {
+	fmt.Println(console.Colorize("failCell", crossTickCell) + fmt.Sprintf(format, args...))
+}
+
+// isCertificateError returns true if e is a failed verification of the
+// certificate of the server.
+func isCertificateError(e error) bool {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		invalid          x509.CertificateInvalidError
+		hostname         x509.HostnameError
+	)
+	return errors.As(e, &unknownAuthority) || errors.As(e, &invalid) || errors.As(e, &hostname) ||
+		strings.Contains(e.Error(), "certificate is not trusted") /* darwin specific error message */
+}
+
+// printCertificate displays what the user needs to decide whether to
+// trust a certificate.
+func printCertificate(cert *x509.Certificate) {
+	fingerprint := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	fmt.Printf("  Subject     : %s\n", cert.Subject)
+	fmt.Printf("  Issuer      : %s\n", cert.Issuer)
+	if len(cert.DNSNames) > 0 || len(cert.IPAddresses) > 0 {
+		names := append([]string{}, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			names = append(names, ip.String())
+		}
+		fmt.Printf("  Names       : %s\n", strings.Join(names, ", "))
+	}
+	fmt.Printf("  Valid       : %s to %s\n", cert.NotBefore.Format(printDate), cert.NotAfter.Format(printDate))
+	fmt.Printf("  Fingerprint : %s\n", color.YellowString(hex.EncodeToString(fingerprint[:])))
+}
+
+// connect checks that the endpoint answers, and that its certificate is
+// trusted. An untrusted certificate is displayed before offering to trust
+// it, when signed by an unknown authority, or to skip its verification.
+func (w aliasWizard) connect(ctx context.Context, alias, endpoint string) (peerCert *x509.Certificate, retry bool) {
+	req, e := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if e != nil {
+		w.fail("Invalid endpoint: %v", e)
+		return nil, true
+	}
+	client := http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: globalRootCAs, InsecureSkipVerify: globalInsecure},
+		},
+	}
+	resp, e := client.Do(req)
+	if e == nil {
+		resp.Body.Close()
+		if req.URL.Scheme == "https" {
+			w.pass("Connected, the TLS certificate is trusted")
+		} else {
+			w.pass("Connected, plain HTTP: credentials and data are not encrypted")
+		}
+		return nil, false
+	}
+	if !isCertificateError(e) {
+		w.fail("Unable to connect: %v", e)
+		return nil, true
+	}
+
+	w.fail("The TLS certificate is not trusted: %v", errors.Unwrap(e))
+	cert, e := fetchPeerCertificate(ctx, endpoint)
+	if e != nil {
+		w.fail("Unable to read the certificate: %v", e)
+		return nil, true
+	}
+	printCertificate(cert)
+
+	// Trusting the certificate only helps when its issuer is unknown.
+	var unknownAuthority x509.UnknownAuthorityError
+	_, verifyErr := cert.Verify(x509.VerifyOptions{Roots: globalRootCAs, DNSName: req.URL.Hostname()})
+	canTrust := errors.As(verifyErr, &unknownAuthority)
+	question, choices := "Continue [i]nsecure, without verifying certificates, or [a]bort", "ia"
+	if canTrust {
+		question, choices = "[T]rust this certificate, continue [i]nsecure, or [a]bort", "tia"
+	}
+	switch w.choose(question, choices, 'a') {
+	case 't':
+		certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		if e = os.WriteFile(filepath.Join(mustGetCAsDir(), alias+".crt"), certPEM, 0o644); e != nil {
+			fatalIf(probe.NewError(e), "Unable to save the certificate.")
+		}
+		w.pass("Certificate saved to the CAs of mc")
+		return cert, false
+	case 'i':
+		globalInsecure = true
+		fmt.Println("Certificates are not verified, use --insecure with the commands using this alias.")
+		return nil, false
+	}
+	fatalIf(errDummy().Trace(endpoint), "Aborted, the alias was not saved.")
+	return nil, false
+}
+
+// probeBucketStyle returns the path setting of the alias: auto when the
+// bucket lookup chosen automatically works with bucket, otherwise the one
+// which works.
+func probeBucketStyle(ctx context.Context, s3Config *Config, bucket string) (path, detail string) {
+	works := func(lookup minio.BucketLookupType) bool {
+		cfg := *s3Config
+		cfg.Lookup = lookup
+		clnt, err := S3New(&cfg)
+		if err != nil {
+			return false
+		}
+		_, e := clnt.(*S3Client).api.GetBucketLocation(ctx, bucket)
+		return e == nil || minio.ToErrorResponse(e).Code == "AccessDenied"
+	}
+	virtual, pathStyle := works(minio.BucketLookupDNS), works(minio.BucketLookupPath)
+	autoVirtual := isVirtualHostStyle(newClientURL(s3Config.HostURL).Host, minio.BucketLookupAuto)
+	switch {
+	case autoVirtual && virtual, !autoVirtual && pathStyle:
+		return "auto", "the style chosen automatically works"
+	case virtual:
+		return "off", "only virtual-host style works"
+	case pathStyle:
+		return "on", "only path style works"
+	}
+	return "auto", "neither style could be checked"
+}
+
+// probeRegion returns the region of the buckets, empty when they are in
+// several regions.
+func probeRegion(ctx context.Context, api *minio.Client, buckets []minio.BucketInfo) (region, detail string) {
+	regions := map[string]bool{}
+	for i, bucket := range buckets {
+		// A few buckets are enough.
+		if i == 10 {
+			break
+		}
+		location, e := api.GetBucketLocation(ctx, bucket.Name)
+		if e != nil {
+			continue
+		}
+		if location == "" {
+			location = "us-east-1"
+		}
+		regions[location] = true
+		region = location
+	}
+	switch len(regions) {
+	case 0:
+		return "", "unable to get the location of the buckets"
+	case 1:
+		return region, region
+	}
+	return "", "the buckets are in several regions, looked up per bucket"
+}
+
+// aliasSetWizard prompts for the settings of an alias and probes the
+// server with them before saving the alias.
+func aliasSetWizard(cli *cli.Context) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || globalJSON {
+		fatalIf(errInvalidArgument(), "--interactive requires a terminal.")
+	}
+	if len(cli.Args()) > 2 {
+		fatalIf(errInvalidArgument().Trace(cli.Args()...), "--interactive only accepts an alias and a URL.")
+	}
+	console.SetColor(cred, color.New(color.FgYellow, color.Italic))
+	console.SetColor("passCell", color.New(color.FgGreen, color.Bold))
+	console.SetColor("failCell", color.New(color.FgRed, color.Bold))
+
+	ctx, cancel := context.WithCancel(globalContext)
+	defer cancel()
+	w := aliasWizard{reader: bufio.NewReader(os.Stdin)}
+
+	alias := cleanAlias(cli.Args().Get(0))
+	for !isValidAlias(alias) {
+		if alias != "" {
+			w.fail("Invalid alias %s", alias)
+		}
+		alias = cleanAlias(w.ask("Alias", ""))
+	}
+
+	// Endpoint and TLS.
+	var peerCert *x509.Certificate
+	endpoint := trimTrailingSeparator(cli.Args().Get(1))
+	for {
+		if endpoint == "" {
+			endpoint = trimTrailingSeparator(w.ask("Endpoint URL", "https://"))
+		}
+		if scheme := newClientURL(endpoint).Scheme; !isValidHostURL(endpoint) || (scheme != "http" && scheme != "https") {
+			w.fail("Invalid endpoint URL %s, e.g. https://minio.example.com:9000", endpoint)
+			endpoint = ""
+			continue
+		}
+		var retry bool
+		if peerCert, retry = w.connect(ctx, alias, endpoint); !retry {
+			break
+		}
+		endpoint = ""
+	}
+
+	aliasCfg := aliasConfigV10{
+		URL:     endpoint,
+		Path:    "auto",
+		Proxy:   cli.String("proxy"),
+		NoProxy: cli.String("no-proxy"),
+	}
+	fatalIf(validateProxy(aliasCfg.Proxy).Trace(aliasCfg.Proxy), "Invalid proxy.")
+	fatalIf(setAliasTLS(cli, &aliasCfg), "Invalid TLS settings.")
+
+	// Credentials and signature.
+	var (
+		s3Config *Config
+		api      *minio.Client
+		buckets  []minio.BucketInfo
+	)
+	for attempt := 1; ; attempt++ {
+		aliasCfg.AccessKey = w.ask("Access Key", aliasCfg.AccessKey)
+		aliasCfg.SecretKey = w.secret("Secret Key")
+		if !isValidAccessKey(aliasCfg.AccessKey) || !isValidSecretKey(aliasCfg.SecretKey) {
+			w.fail("Invalid access key or secret key")
+			continue
+		}
+		var err *probe.Error
+		s3Config, err = BuildS3Config(ctx, aliasCfg, "", peerCert)
+		if err == nil {
+			var clnt Client
+			if clnt, err = S3New(s3Config); err == nil {
+				api = clnt.(*S3Client).api
+				var e error
+				buckets, e = api.ListBuckets(ctx)
+				if e != nil && minio.ToErrorResponse(e).Code != "AccessDenied" {
+					err = probe.NewError(e)
+				}
+			}
+		}
+		if err == nil {
+			w.pass("Credentials are valid, signature %s, %d bucket(s) visible", s3Config.Signature, len(buckets))
+			break
+		}
+		w.fail("The credentials were rejected: %v", err.ToGoError())
+		if attempt == 3 {
+			fatalIf(err.Trace(alias, endpoint), "Unable to initialize new alias from the provided credentials.")
+		}
+	}
+	aliasCfg.URL = s3Config.HostURL
+	aliasCfg.API = s3Config.Signature
+
+	// Bucket lookup and region, from the visible buckets.
+	if len(buckets) == 0 {
+		w.pass("Bucket lookup auto, region looked up per bucket: no bucket to probe")
+	} else {
+		var detail string
+		aliasCfg.Path, detail = probeBucketStyle(ctx, s3Config, buckets[0].Name)
+		w.pass("Bucket lookup %s: %s", aliasCfg.Path, detail)
+		aliasCfg.Region, detail = probeRegion(ctx, api, buckets)
+		w.pass("Region: %s", detail)
+	}
+	if region := cli.String("region"); region != "" {
+		aliasCfg.Region = region
+	}
+
+	if w.choose(fmt.Sprintf("Save alias %s for %s? (y/n)", alias, aliasCfg.URL), "yn", 'y') != 'y' {
+		fatalIf(errDummy().Trace(alias), "Aborted, the alias was not saved.")
+	}
+
+	setAliasTimeouts(&aliasCfg)
+	setAliasTransport(&aliasCfg)
+	setAliasRequestPayer(&aliasCfg)
+	if store := cli.String("secret-store"); store != "" {
+		err := storeAliasSecret(alias, &aliasCfg, store)
+		fatalIf(err.Trace(alias), "Unable to store the secret key in the "+store+" secret store.")
+	}
+	msg := setAlias(alias, aliasCfg)
+	msg.op = "set"
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestAliasWizardChoose(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected byte
+	}{
+		// An empty answer picks the default.
+		{"\n", 'a'},
+		{"Trust\n", 't'},
+		{"  i \n", 'i'},
+		// The question is asked again until the answer is a choice.
+		{"x\n\n", 'a'},
+		{"yes\nI\n", 'i'},
+	}
+	for i, testCase := range testCases {
+		w := aliasWizard{reader: bufio.NewReader(strings.NewReader(testCase.input))}
+		if choice := w.choose("Continue", "tia", 'a'); choice != testCase.expected {
+			t.Errorf("Test %d: expected %c, got %c", i+1, testCase.expected, choice)
+		}
+	}
+
+	w := aliasWizard{reader: bufio.NewReader(strings.NewReader("\nminio\n"))}
+	if answer := w.ask("Endpoint URL", "https://"); answer != "https://" {
+		t.Errorf("expected the default answer, got %q", answer)
+	}
+	if answer := w.ask("Access Key", "admin"); answer != "minio" {
+		t.Errorf("expected minio, got %q", answer)
+	}
+}
+
+func TestAliasWizardConnect(t *testing.T) {
+	defer func(dir, profile string) {
+		setMcConfigDir(dir)
+		setMcConfigProfile(profile)
+	}(mcCustomConfigDir, mcConfigProfile)
+	setMcConfigDir(t.TempDir())
+	setMcConfigProfile("")
+	if e := os.MkdirAll(mustGetCAsDir(), 0o700); e != nil {
+		t.Fatal(e)
+	}
+	defer func(insecure bool, rootCAs *x509.CertPool) { globalInsecure, globalRootCAs = insecure, rootCAs }(globalInsecure, globalRootCAs)
+	globalRootCAs = x509.NewCertPool()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	secure := httptest.NewTLSServer(handler)
+	defer secure.Close()
+	closed := httptest.NewServer(handler)
+	closed.Close()
+
+	testCases := []struct {
+		endpoint string
+		input    string
+		retry    bool
+		insecure bool
+		trusted  bool
+	}{
+		{plain.URL, "", false, false, false},
+		// An endpoint which does not answer is asked again.
+		{closed.URL, "", true, false, false},
+		{"http://%zz", "", true, false, false},
+		// The certificate of an unknown authority is trusted or skipped.
+		{secure.URL, "t\n", false, false, true},
+		{secure.URL, "i\n", false, true, false},
+	}
+	for i, testCase := range testCases {
+		globalInsecure = false
+		w := aliasWizard{reader: bufio.NewReader(strings.NewReader(testCase.input))}
+		peerCert, retry := w.connect(context.Background(), "myminio", testCase.endpoint)
+		if retry != testCase.retry {
+			t.Errorf("Test %d: expected retry %t, got %t", i+1, testCase.retry, retry)
+		}
+		if globalInsecure != testCase.insecure {
+			t.Errorf("Test %d: expected insecure %t, got %t", i+1, testCase.insecure, globalInsecure)
+		}
+		if (peerCert != nil) != testCase.trusted {
+			t.Errorf("Test %d: expected trusted %t, got %v", i+1, testCase.trusted, peerCert)
+		}
+		if !testCase.trusted {
+			continue
+		}
+		// The trusted certificate is saved to the CAs of mc.
+		buf, e := os.ReadFile(filepath.Join(mustGetCAsDir(), "myminio.crt"))
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if block, _ := pem.Decode(buf); block == nil || !secure.Certificate().Equal(peerCert) || string(block.Bytes) != string(peerCert.Raw) {
+			t.Errorf("Test %d: unexpected certificate saved %s", i+1, buf)
+		}
+	}
+}
+
+func TestProbeRegion(t *testing.T) {
+	locations := map[string]string{"eu1": "eu-west-1", "eu2": "eu-west-1", "us1": "", "ap1": "ap-south-1"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		location, ok := locations[strings.Trim(r.URL.Path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist</Message></Error>`))
+			return
+		}
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">` + location + `</LocationConstraint>`))
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+
+	testCases := []struct {
+		buckets []string
+		region  string
+	}{
+		{[]string{"eu1", "eu2"}, "eu-west-1"},
+		// An empty location is the default region.
+		{[]string{"us1"}, "us-east-1"},
+		// The buckets whose location is unknown are ignored.
+		{[]string{"missing", "eu1"}, "eu-west-1"},
+		{[]string{"missing"}, ""},
+		// Buckets in several regions are looked up per bucket.
+		{[]string{"eu1", "ap1"}, ""},
+	}
+	for i, testCase := range testCases {
+		var buckets []minio.BucketInfo
+		for _, name := range testCase.buckets {
+			buckets = append(buckets, minio.BucketInfo{Name: name})
+		}
+		// A new client each time, the locations are cached by the client.
+		clnt, err := S3New(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		region, detail := probeRegion(context.Background(), clnt.(*S3Client).api, buckets)
+		if region != testCase.region {
+			t.Errorf("Test %d: expected region %q, got %q (%s)", i+1, testCase.region, region, detail)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+// mcEnvAlias overrides the current alias of the config.
+const mcEnvAlias = "MC_ALIAS"
+
+var aliasUseFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "clear",
+		Usage: "unset the current alias",
+	},
+}
+
+var aliasUseCmd = cli.Command{
+	Name:            "use",
+	Usage:           "set the alias used when a command omits the alias",
+	Action:          mainAliasUse,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(aliasUseFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [ALIAS]
+
+  With a current alias, arguments not starting with an alias are
+  relative to the current alias, unless their first path component
+  exists locally or they start with '/', '.' or '~'. MC_ALIAS takes
+  precedence over the current alias of the configuration file.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the current alias.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Use "myminio" when a command omits the alias, i.e. list the objects of "myminio/mybucket".
+     {{.Prompt}} {{.HelpName}} myminio
+     {{.Prompt}} mc ls mybucket
+
+  3. Use "staging" for the commands of the current shell only.
+     {{.Prompt}} export MC_ALIAS=staging
+
+  4. Unset the current alias.
+     {{.Prompt}} {{.HelpName}} --clear
+`,
+}
+
+// aliasUseMessage container for the current alias
+type aliasUseMessage struct {
+	Status string `json:"status"`
+	Alias  string `json:"alias"`
+	Source string `json:"source,omitempty"`
+}
+
+func (m aliasUseMessage) String() string {
+	switch {
+	case m.Alias == "":
+		return console.Colorize("AliasMessage", "No current alias set.")
+	case m.Source == mcEnvAlias:
+		return console.Colorize("AliasMessage", "Using `"+m.Alias+"` (set by "+mcEnvAlias+").")
+	}
+	return console.Colorize("AliasMessage", "Using `"+m.Alias+"`.")
+}
+
+func (m aliasUseMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+// getCurrentAlias returns the alias used when the arguments of a command
+// do not start with an alias, and where it has been set.
+func getCurrentAlias() (alias, source string) {
+	if alias = os.Getenv(mcEnvAlias); alias != "" {
+		return alias, mcEnvAlias
+	}
+	if loadMcConfig == nil {
+		return "", ""
+	}
+	conf, err := loadMcConfig()
+	if err != nil {
+		return "", ""
+	}
+	return conf.CurrentAlias, "config"
+}
+
+// globalCurrentAlias is set once an argument has been resolved
+// relative to the current alias, it is shown in error messages.
+var globalCurrentAlias string
+
+// isExplicitLocalPath returns true for the arguments which are never
+// resolved relative to the current alias: absolute and dotted paths,
+// and relative paths whose first component exists locally, so that
+// 'dir/new-file' stays local once 'dir' exists.
+func isExplicitLocalPath(path string) bool {
+	if path == "" || filepath.IsAbs(path) || filepath.VolumeName(path) != "" {
+		return true
+	}
+	if strings.HasPrefix(path, ".") || strings.HasPrefix(path, "~") {
+		return true
+	}
+	first := path
+	if i := strings.IndexAny(path, "/"+string(filepath.Separator)); i > 0 {
+		first = path[:i]
+	}
+	_, e := os.Lstat(first)
+	return e == nil
+}
+
+// mainAliasUse is the handle for "mc alias use" command.
+func mainAliasUse(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 || (ctx.Bool("clear") && len(ctx.Args()) > 0) {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	console.SetColor("AliasMessage", color.New(color.FgGreen))
+
+	if !ctx.Bool("clear") && len(ctx.Args()) == 0 {
+		alias, source := getCurrentAlias()
+		printMsg(aliasUseMessage{Alias: alias, Source: source})
+		return nil
+	}
+
+	alias := cleanAlias(ctx.Args().Get(0))
+	conf, err := loadMcConfig()
+	fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+
+	if alias != "" {
+		if _, ok := conf.Aliases[alias]; !ok {
+			fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+		}
+	}
+	conf.CurrentAlias = alias
+
+	err = saveMcConfig(conf)
+	fatalIf(err.Trace(alias), "Unable to save the current alias in config version `"+globalMCConfigVersion+"`.")
+
+	printMsg(aliasUseMessage{Alias: alias, Source: "config"})
+	return nil
+}
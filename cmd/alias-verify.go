@@ -0,0 +1,352 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
+	"github.com/olekukonko/tablewriter"
+)
+
+var aliasVerifyFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "all",
+		Usage: "verify all the aliases",
+	},
+}
+
+var aliasVerifyCmd = cli.Command{
+	Name:            "verify",
+	Usage:           "check connectivity, TLS, credentials and capabilities of aliases",
+	Action:          mainAliasVerify,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(aliasVerifyFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [ALIAS...|--all]
+
+  Region and capabilities are checked against the first bucket
+  of the alias, they are skipped when there is no bucket.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Verify the alias "myminio".
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. Verify all the aliases.
+     {{.Prompt}} {{.HelpName}} --all
+`,
+}
+
+// Status of an alias check.
+const (
+	verifyPass = "pass"
+	verifyWarn = "warn"
+	verifyFail = "fail"
+	verifySkip = "skip"
+)
+
+// certExpiryWarning - warn about certificates expiring in less than this.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// aliasVerifyCheck - outcome of one check
+type aliasVerifyCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// aliasVerifyMessage container for alias verify results
+type aliasVerifyMessage struct {
+	Status string             `json:"status"`
+	Alias  string             `json:"alias"`
+	URL    string             `json:"URL"`
+	Checks []aliasVerifyCheck `json:"checks"`
+}
+
+func (m *aliasVerifyMessage) add(name, status, format string, args ...interface{}) {
+	m.Checks = append(m.Checks, aliasVerifyCheck{Name: name, Status: status, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (m aliasVerifyMessage) failed() bool {
+	for _, c := range m.Checks {
+		if c.Status == verifyFail {
+			return true
+		}
+	}
+	return false
+}
+
+func (m aliasVerifyMessage) String() string {
+	var s strings.Builder
+	s.WriteString(console.Colorize("Alias", m.Alias) + " (" + m.URL + ")\n")
+
+	table := tablewriter.NewWriter(&s)
+	table.SetAutoWrapText(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+	for _, c := range m.Checks {
+		var status string
+		switch c.Status {
+		case verifyPass:
+			status = console.Colorize("passCell", tickCell)
+		case verifyFail:
+			status = console.Colorize("failCell", crossTickCell)
+		case verifyWarn:
+			status = console.Colorize("warnCell", "!")
+		default:
+			status = blankCell
+		}
+		table.Append([]string{"  " + status, c.Name, c.Detail})
+	}
+	table.Render()
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+func (m aliasVerifyMessage) JSON() string {
+	m.Status = "success"
+	if m.failed() {
+		m.Status = "error"
+	}
+	return toJSON(m)
+}
+
+// awsRegionalHost matches the regional endpoints of AWS S3.
+var awsRegionalHost = regexp.MustCompile(`^s3[.-](?:dualstack\.)?([a-z0-9-]+)\.amazonaws\.com$`)
+
+// verifyAlias runs all the checks of an alias.
+func verifyAlias(ctx context.Context, alias string, aliasCfg *aliasConfigV10) aliasVerifyMessage {
+	msg := aliasVerifyMessage{Alias: alias, URL: aliasCfg.URL}
+
+	u, e := url.Parse(aliasCfg.URL)
+	if e != nil || u.Host == "" {
+		msg.add("url", verifyFail, "invalid URL")
+		return msg
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), map[bool]string{true: "443", false: "80"}[u.Scheme == "https"])
+	}
+
+	// Reachability
+	start := time.Now()
+	conn, e := (&net.Dialer{Timeout: 10 * time.Second}).DialContext(ctx, "tcp", host)
+	if e != nil {
+		msg.add("reachable", verifyFail, "%v", e)
+		return msg
+	}
+	conn.Close()
+	msg.add("reachable", verifyPass, "connected in %s", time.Since(start).Round(time.Millisecond))
+
+	// TLS validity and expiry
+	if u.Scheme == "https" {
+		msg.verifyTLS(host, u.Hostname(), NewS3Config(aliasCfg.URL, aliasCfg))
+	} else {
+		msg.add("tls", verifyWarn, "plain HTTP, credentials and data are not encrypted")
+	}
+
+	// Credentials
+	clnt, err := newClient(alias)
+	if err != nil {
+		msg.add("credentials", verifyFail, "%v", err.ToGoError())
+		return msg
+	}
+	s3Clnt, ok := clnt.(*S3Client)
+	if !ok {
+		msg.add("credentials", verifyFail, "not an S3 alias")
+		return msg
+	}
+	api := s3Clnt.api
+
+	buckets, e := api.ListBuckets(ctx)
+	switch {
+	case e == nil:
+		msg.add("credentials", verifyPass, "%d bucket(s) visible", len(buckets))
+	case minio.ToErrorResponse(e).Code == "AccessDenied":
+		// Signature accepted, listing is not allowed by policy.
+		msg.add("credentials", verifyPass, "valid, bucket listing denied by policy")
+	default:
+		msg.add("credentials", verifyFail, "%v", e)
+		return msg
+	}
+
+	if len(buckets) == 0 {
+		for _, name := range []string{"region", "versioning", "locking", "select"} {
+			msg.add(name, verifySkip, "no bucket to check against")
+		}
+		return msg
+	}
+	bucket := buckets[0].Name
+
+	// Region
+	region, e := api.GetBucketLocation(ctx, bucket)
+	switch {
+	case e != nil:
+		msg.add("region", verifyFail, "%v", e)
+	default:
+		if region == "" {
+			region = "us-east-1"
+		}
+		if m := awsRegionalHost.FindStringSubmatch(u.Hostname()); m != nil && m[1] != region {
+			msg.add("region", verifyFail, "endpoint is in %s but bucket '%s' is in %s", m[1], bucket, region)
+		} else {
+			msg.add("region", verifyPass, "%s", region)
+		}
+	}
+
+	// Capabilities
+	if _, e = api.GetBucketVersioning(ctx, bucket); e != nil {
+		msg.addCapability("versioning", e)
+	} else {
+		msg.add("versioning", verifyPass, "supported")
+	}
+
+	if _, _, _, _, e = api.GetObjectLockConfig(ctx, bucket); e != nil &&
+		minio.ToErrorResponse(e).Code != "ObjectLockConfigurationNotFoundError" {
+		msg.addCapability("locking", e)
+	} else {
+		msg.add("locking", verifyPass, "supported")
+	}
+
+	_, e = api.SelectObjectContent(ctx, bucket, "mc-alias-verify-"+uuid.NewString(), minio.SelectObjectOptions{
+		Expression:          "select * from S3Object",
+		ExpressionType:      minio.QueryExpressionTypeSQL,
+		InputSerialization:  minio.SelectObjectInputSerialization{CSV: &minio.CSVInputOptions{}},
+		OutputSerialization: minio.SelectObjectOutputSerialization{CSV: &minio.CSVOutputOptions{}},
+	})
+	if e != nil && minio.ToErrorResponse(e).Code != "NoSuchKey" {
+		msg.addCapability("select", e)
+	} else {
+		msg.add("select", verifyPass, "supported")
+	}
+	return msg
+}
+
+// addCapability records a capability as unsupported when the server says so,
+// as failed otherwise.
+func (m *aliasVerifyMessage) addCapability(name string, e error) {
+	switch minio.ToErrorResponse(e).Code {
+	case "NotImplemented", "MethodNotAllowed":
+		m.add(name, verifyWarn, "not supported by the server")
+	case "AccessDenied":
+		m.add(name, verifySkip, "access denied")
+	default:
+		m.add(name, verifyFail, "%v", e)
+	}
+}
+
+// verifyTLS checks the certificate chain and its expiry date.
+func (m *aliasVerifyMessage) verifyTLS(host, serverName string, config *Config) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	tlsConfig := config.tlsConfig(&tls.Config{RootCAs: globalRootCAs, ServerName: serverName, MinVersion: tls.VersionTLS12})
+
+	conn, e := tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	verified := e == nil
+	if e != nil {
+		// Connect again to report the expiry date.
+		tlsConfig.InsecureSkipVerify = true
+		var e2 error
+		if conn, e2 = tls.DialWithDialer(dialer, "tcp", host, tlsConfig); e2 != nil {
+			m.add("tls", verifyFail, "%v", e)
+			return
+		}
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		m.add("tls", verifyFail, "no certificate presented")
+		return
+	}
+	expiry := certs[0].NotAfter
+	left := time.Until(expiry)
+	switch {
+	case left <= 0:
+		m.add("tls", verifyFail, "certificate expired on %s", expiry.Format(printDate))
+	case !verified && globalInsecure:
+		m.add("tls", verifyWarn, "certificate not trusted (%v), expires in %d days", e, int(left.Hours()/24))
+	case !verified:
+		m.add("tls", verifyFail, "%v", e)
+	case left < certExpiryWarning:
+		m.add("tls", verifyWarn, "certificate expires in %d days", int(left.Hours()/24))
+	default:
+		m.add("tls", verifyPass, "certificate valid until %s", expiry.Format(printDate))
+	}
+}
+
+// mainAliasVerify is the handle for "mc alias verify" command.
+func mainAliasVerify(ctx *cli.Context) error {
+	args := ctx.Args()
+	if (len(args) == 0) == !ctx.Bool("all") {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	console.SetColor("Alias", color.New(color.FgCyan, color.Bold))
+	console.SetColor("passCell", color.New(color.FgGreen, color.Bold))
+	console.SetColor("failCell", color.New(color.FgRed, color.Bold))
+	console.SetColor("warnCell", color.New(color.FgYellow, color.Bold))
+
+	aliases := []string(args)
+	if ctx.Bool("all") {
+		conf, err := loadMcConfig()
+		fatalIf(err.Trace(globalMCConfigVersion), "Unable to load config version `"+globalMCConfigVersion+"`.")
+		for alias := range conf.Aliases {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+	}
+
+	var failed bool
+	for _, alias := range aliases {
+		alias = cleanAlias(alias)
+		aliasCfg := mustGetHostConfig(alias)
+		if aliasCfg == nil {
+			fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+		}
+		msg := verifyAlias(globalContext, alias, aliasCfg)
+		failed = failed || msg.failed()
+		printMsg(msg)
+	}
+	if failed {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
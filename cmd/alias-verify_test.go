@@ -0,0 +1,96 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestAliasVerifyCapability(t *testing.T) {
+	testCases := []struct {
+		e      error
+		status string
+	}{
+		{minio.ErrorResponse{Code: "NotImplemented"}, verifyWarn},
+		{minio.ErrorResponse{Code: "MethodNotAllowed"}, verifyWarn},
+		{minio.ErrorResponse{Code: "AccessDenied"}, verifySkip},
+		{minio.ErrorResponse{Code: "InternalError"}, verifyFail},
+		{errors.New("connection reset"), verifyFail},
+	}
+	for i, testCase := range testCases {
+		var msg aliasVerifyMessage
+		msg.addCapability("select", testCase.e)
+		if msg.Checks[0].Status != testCase.status {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.status, msg.Checks[0].Status)
+		}
+		if msg.failed() != (testCase.status == verifyFail) {
+			t.Errorf("Test %d: unexpected failed() %t", i+1, msg.failed())
+		}
+	}
+}
+
+func TestAWSRegionalHost(t *testing.T) {
+	testCases := []struct {
+		host   string
+		region string
+	}{
+		{"s3.eu-west-1.amazonaws.com", "eu-west-1"},
+		{"s3-us-west-2.amazonaws.com", "us-west-2"},
+		{"s3.dualstack.ap-south-1.amazonaws.com", "ap-south-1"},
+		{"s3.amazonaws.com", ""},
+		{"play.min.io", ""},
+	}
+	for i, testCase := range testCases {
+		var region string
+		if m := awsRegionalHost.FindStringSubmatch(testCase.host); m != nil {
+			region = m[1]
+		}
+		if region != testCase.region {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.region, region)
+		}
+	}
+}
+
+func TestAliasVerifyTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	defer func(insecure bool) { globalInsecure = insecure }(globalInsecure)
+	for i, testCase := range []struct {
+		insecure bool
+		status   string
+	}{
+		// The certificate of the test server is self-signed.
+		{false, verifyFail},
+		{true, verifyWarn},
+	} {
+		globalInsecure = testCase.insecure
+		var msg aliasVerifyMessage
+		msg.verifyTLS(u.Host, u.Hostname(), &Config{})
+		if len(msg.Checks) != 1 || msg.Checks[0].Status != testCase.status {
+			t.Errorf("Test %d: expected %s, got %+v", i+1, testCase.status, msg.Checks)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// anonymousAuditMessage is a bucket or prefix accessible anonymously.
+type anonymousAuditMessage struct {
+	Status     string      `json:"status"`
+	Bucket     string      `json:"bucket"`
+	Resource   string      `json:"resource"`
+	Permission accessPerms `json:"permission"`
+}
+
+// anonymousAuditEffect describes what anyone can do with a permission.
+func anonymousAuditEffect(perm accessPerms) string {
+	switch perm {
+	case accessDownload:
+		return "anyone can list and download"
+	case accessUpload:
+		return "anyone can upload"
+	case accessPublic:
+		return "anyone can list, download and upload"
+	case accessCustom:
+		return "custom bucket policy, review it with 'mc anonymous get-json'"
+	}
+	return ""
+}
+
+// String colorized anonymous audit message.
+func (s anonymousAuditMessage) String() string {
+	theme := "AnonymousPublic"
+	if s.Permission == accessCustom {
+		theme = "AnonymousCustom"
+	}
+	return console.Colorize(theme, fmt.Sprintf("%-40s %-9s", s.Resource, s.Permission)) + " " + anonymousAuditEffect(s.Permission)
+}
+
+// JSON jsonified anonymous audit message.
+func (s anonymousAuditMessage) JSON() string {
+	s.Status = "success"
+	anonymousJSONBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(anonymousJSONBytes)
+}
+
+// anonymousAuditSummary counts the buckets accessible anonymously.
+type anonymousAuditSummary struct {
+	Status  string `json:"status"`
+	Alias   string `json:"alias"`
+	Buckets int    `json:"buckets"`
+	Public  int    `json:"public"`
+	Failed  int    `json:"failed,omitempty"`
+}
+
+// String colorized anonymous audit summary.
+func (s anonymousAuditSummary) String() string {
+	msg := fmt.Sprintf("Audited %d bucket(s) of `%s`, %d accessible anonymously.", s.Buckets, s.Alias, s.Public)
+	if s.Failed > 0 {
+		msg += fmt.Sprintf(" Unable to audit %d bucket(s).", s.Failed)
+	}
+	return console.Colorize("Anonymous", msg)
+}
+
+// JSON jsonified anonymous audit summary.
+func (s anonymousAuditSummary) JSON() string {
+	s.Status = "success"
+	anonymousJSONBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(anonymousJSONBytes)
+}
+
+// anonymousAuditBucket returns the anonymous permissions of a bucket, by
+// resource. A bucket policy which does not map to a permission is custom.
+func anonymousAuditBucket(ctx context.Context, bucketURL, bucket string) ([]anonymousAuditMessage, *probe.Error) {
+	rules, err := doGetAccessRules(ctx, bucketURL)
+	if err != nil {
+		return nil, err.Trace(bucketURL)
+	}
+	var msgs []anonymousAuditMessage
+	for resource, perm := range rules {
+		p := stringToAccessPerm(perm)
+		if p == accessPrivate || p == "" {
+			continue
+		}
+		msgs = append(msgs, anonymousAuditMessage{Bucket: bucket, Resource: resource, Permission: p})
+	}
+	if len(msgs) == 0 {
+		perm, _, err := doGetAccess(ctx, bucketURL)
+		if err != nil {
+			return nil, err.Trace(bucketURL)
+		}
+		if perm == accessCustom {
+			msgs = append(msgs, anonymousAuditMessage{Bucket: bucket, Resource: bucket, Permission: accessCustom})
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Resource < msgs[j].Resource })
+	return msgs, nil
+}
+
+// Run anonymous audit command
+func runAnonymousAuditCmd(args cli.Args) error {
+	ctx, cancelAnonymousAudit := context.WithCancel(globalContext)
+	defer cancelAnonymousAudit()
+
+	alias, _ := url2Alias(args.First())
+	console.SetColor("AnonymousPublic", color.New(color.FgRed, color.Bold))
+	console.SetColor("AnonymousCustom", color.New(color.FgYellow, color.Bold))
+	clnt, err := newClient(alias)
+	fatalIf(err.Trace(alias), "Unable to initialize target `"+alias+"`.")
+	buckets, err := clnt.ListBuckets(ctx)
+	fatalIf(err.Trace(alias), "Unable to list the buckets of `"+alias+"`.")
+
+	summary := anonymousAuditSummary{Alias: alias, Buckets: len(buckets)}
+	for _, b := range buckets {
+		bucket := path.Base(b.URL.Path)
+		bucketURL := alias + "/" + bucket
+		msgs, err := anonymousAuditBucket(ctx, bucketURL, bucket)
+		if err != nil {
+			errorIf(err, "Unable to audit the anonymous access of `"+bucketURL+"`.")
+			summary.Failed++
+			continue
+		}
+		if len(msgs) > 0 {
+			summary.Public++
+		}
+		for _, msg := range msgs {
+			msg.Resource = strings.TrimSuffix(msg.Resource, "*")
+			printMsg(msg)
+		}
+	}
+	printMsg(summary)
+	if summary.Failed > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
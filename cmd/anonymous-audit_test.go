@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/policy"
+	"github.com/minio/minio-go/v7/pkg/set"
+)
+
+func TestAnonymousAuditBucket(t *testing.T) {
+	var bucketPolicy string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		if bucketPolicy == "" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchBucketPolicy</Code><Message>The bucket policy does not exist</Message></Error>`))
+			return
+		}
+		w.Write([]byte(bucketPolicy))
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	// statements grants anonymous access to the prefixes of the bucket.
+	statements := func(perm policy.BucketPolicy, prefixes ...string) []policy.Statement {
+		var s []policy.Statement
+		for _, prefix := range prefixes {
+			s = policy.SetPolicy(s, perm, "site", prefix)
+		}
+		return s
+	}
+	custom := []policy.Statement{{
+		Effect:    "Allow",
+		Principal: policy.User{AWS: set.CreateStringSet("arn:aws:iam::123456789012:user/web")},
+		Actions:   set.CreateStringSet("s3:GetObject"),
+		Resources: set.CreateStringSet("arn:aws:s3:::site/*"),
+	}}
+
+	testCases := []struct {
+		statements []policy.Statement
+		expected   []anonymousAuditMessage
+	}{
+		// Without a bucket policy nothing is accessible anonymously.
+		{nil, nil},
+		{statements(policy.BucketPolicyReadOnly, ""), []anonymousAuditMessage{
+			{Bucket: "site", Resource: "site/*", Permission: accessDownload},
+		}},
+		{statements(policy.BucketPolicyWriteOnly, "uploads"), []anonymousAuditMessage{
+			{Bucket: "site", Resource: "site/uploads*", Permission: accessUpload},
+		}},
+		// Each prefix is reported with its own permission, in order.
+		{append(statements(policy.BucketPolicyReadWrite, "public"), statements(policy.BucketPolicyReadOnly, "img")...), []anonymousAuditMessage{
+			{Bucket: "site", Resource: "site/img*", Permission: accessDownload},
+			{Bucket: "site", Resource: "site/public*", Permission: accessPublic},
+		}},
+		// A policy which grants nothing anonymously is reported as custom.
+		{custom, []anonymousAuditMessage{
+			{Bucket: "site", Resource: "site", Permission: accessCustom},
+		}},
+	}
+	for i, testCase := range testCases {
+		bucketPolicy = ""
+		if testCase.statements != nil {
+			buf, e := json.Marshal(policy.BucketAccessPolicy{Version: "2012-10-17", Statements: testCase.statements})
+			if e != nil {
+				t.Fatal(e)
+			}
+			bucketPolicy = string(buf)
+		}
+		msgs, err := anonymousAuditBucket(context.Background(), "myminio/site", "site")
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(msgs, testCase.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, msgs)
+		}
+	}
+}
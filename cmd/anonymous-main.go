@@ -56,6 +56,7 @@ USAGE:
   {{.HelpName}} [FLAGS] get TARGET
   {{.HelpName}} [FLAGS] get-json TARGET
   {{.HelpName}} [FLAGS] list TARGET
+  {{.HelpName}} [FLAGS] audit ALIAS
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -93,6 +94,9 @@ EXAMPLES:
 
   9. List public object URLs recursively.
      {{.Prompt}} {{.HelpName}} --recursive links s3/shared/
+
+  10. Audit all the buckets of 'myminio' for anonymous access.
+     {{.Prompt}} {{.HelpName}} audit myminio
 `,
 }
 
@@ -219,8 +223,8 @@ func checkAnonymousSyntax(ctx *cli.Context) {
 		if argsLength != 2 {
 			showCommandHelpAndExit(ctx, 1)
 		}
-	case "links":
-		// Always expect an argument after links cmd
+	case "links", "audit":
+		// Always expect an argument after links and audit cmds
 		if argsLength != 2 {
 			showCommandHelpAndExit(ctx, 1)
 		}
@@ -491,6 +495,9 @@ func mainAnonymous(ctx *cli.Context) error {
 	case "links":
 		// anonymous links alias/bucket/prefix
 		runAnonymousLinksCmd(ctx.Args().Tail(), ctx.Bool("recursive"))
+	case "audit":
+		// anonymous audit alias
+		return runAnonymousAuditCmd(ctx.Args().Tail())
 	default:
 		// Shows command example and exit
 		showCommandHelpAndExit(ctx, 1)
@@ -0,0 +1,195 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// appendMinComposeSize is the minimum size of all the sources of a
+// composition but the last one.
+const appendMinComposeSize = 5 * humanize.MiByte
+
+var appendCmd = cli.Command{
+	Name:         "append",
+	Usage:        "append a file to an object",
+	Action:       mainAppend,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(ioFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] FILE ALIAS/BUCKET/OBJECT
+
+  Append the content of a file to an object, the object is created when it
+  doesn't exist. Directory buckets of S3 Express One Zone append on the
+  server. Otherwise the file is uploaded to a temporary object which is
+  composed with the object by multipart copies, the object must not change
+  in the meantime. Objects smaller than 5 MiB can't be composed, they are
+  downloaded and uploaded again with the file.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Append the log of the day to an object.
+     {{.Prompt}} {{.HelpName}} /var/log/app.log myminio/logs/app.log
+
+  2. Append to an object of a directory bucket on the server.
+     {{.Prompt}} {{.HelpName}} events.json s3/events--use1-az4--x-s3/events.json
+`,
+}
+
+// appendMessage container for an object a file was appended to
+type appendMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	// Appended is the size of the file, Size of the object.
+	Appended int64 `json:"appended"`
+	Size     int64 `json:"size"`
+	// Method is 'append' when appended by the server, 'compose', 'rewrite'
+	// when the object was uploaded again, 'create' for new objects.
+	Method string `json:"method"`
+}
+
+func (a appendMessage) String() string {
+	return console.Colorize("Append", fmt.Sprintf("`%s` -> `%s`", a.Source, a.Target)) +
+		fmt.Sprintf(" (%s appended, %s, %s)", humanize.IBytes(uint64(a.Appended)), humanize.IBytes(uint64(a.Size)), a.Method)
+}
+
+func (a appendMessage) JSON() string {
+	a.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(a, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// appendComposed uploads the file to a temporary object next to the target
+// and composes them, the temporary object is always removed.
+func appendComposed(ctx context.Context, clnt *S3Client, targetURL string, st *ClientContent, f io.Reader, size int64, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+	target := composeSource(targetURL, st.ETag, encKeyDB)
+	tmpURL := targetURL + ".mc-append-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	tmpClnt := newComposeClient(tmpURL)
+	if _, err := tmpClnt.Put(ctx, f, size, nil, PutOptions{sse: target.Encryption, md5: true}); err != nil {
+		return err
+	}
+	defer func() {
+		contentCh := make(chan *ClientContent, 1)
+		contentCh <- &ClientContent{URL: tmpClnt.GetURL()}
+		close(contentCh)
+		for result := range tmpClnt.Remove(ctx, false, false, false, false, contentCh) {
+			errorIf(result.Err.Trace(tmpURL), "Unable to remove the temporary object `"+tmpURL+"`.")
+		}
+	}()
+
+	tmp := composeSource(tmpURL, "", encKeyDB)
+	_, err := clnt.Compose(ctx, target.Encryption, target, tmp)
+	return err
+}
+
+func checkAppendSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+	target := ctx.Args().Get(1)
+	if alias, _, _ := mustExpandAlias(target); alias == "" || strings.HasSuffix(target, "/") {
+		fatalIf(errInvalidArgument().Trace(target), "The target must be an object on an alias.")
+	}
+}
+
+// mainAppend is the handle for "mc append" command.
+func mainAppend(cliCtx *cli.Context) error {
+	ctx, cancelAppend := context.WithCancel(globalContext)
+	defer cancelAppend()
+
+	checkAppendSyntax(cliCtx)
+	console.SetColor("Append", color.New(color.FgGreen))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	source, targetURL := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+	f, e := os.Open(source)
+	fatalIf(probe.NewError(e), "Unable to open `"+source+"`.")
+	defer f.Close()
+	fi, e := f.Stat()
+	fatalIf(probe.NewError(e), "Unable to open `"+source+"`.")
+	if !fi.Mode().IsRegular() {
+		fatalIf(errInvalidArgument().Trace(source), "The source must be a file.")
+	}
+
+	clnt := newComposeClient(targetURL)
+	sse := composeSource(targetURL, "", encKeyDB).Encryption
+	st, err := clnt.Stat(ctx, StatOptions{sse: sse})
+	if err != nil && !errors.As(err.ToGoError(), &ObjectMissing{}) {
+		fatalIf(err.Trace(targetURL), "Unable to stat `"+targetURL+"`.")
+	}
+	if st != nil && st.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(targetURL), "The target must be an object.")
+	}
+
+	bucket, _ := clnt.url2BucketAndObject()
+	var method string
+	switch {
+	case st == nil:
+		method = "create"
+		_, err = clnt.Put(ctx, f, fi.Size(), nil, PutOptions{sse: sse, md5: true})
+	case isDirectoryBucket(bucket):
+		method = "append"
+		err = clnt.AppendObject(ctx, f, fi.Size(), st.Size)
+	case st.Size < appendMinComposeSize:
+		method = "rewrite"
+		var reader io.ReadCloser
+		reader, err = clnt.Get(ctx, GetOptions{SSE: sse, VersionID: st.VersionID})
+		if err == nil {
+			_, err = clnt.Put(ctx, io.MultiReader(reader, f), st.Size+fi.Size(), nil, PutOptions{sse: sse, md5: true})
+			reader.Close()
+		}
+	default:
+		method = "compose"
+		err = appendComposed(ctx, clnt, targetURL, st, f, fi.Size(), encKeyDB)
+	}
+	fatalIf(err.Trace(targetURL), "Unable to append `"+source+"` to `"+targetURL+"`.")
+
+	st, err = clnt.Stat(ctx, StatOptions{sse: sse})
+	fatalIf(err.Trace(targetURL), "Unable to stat `"+targetURL+"`.")
+	printMsg(appendMessage{
+		Source:   source,
+		Target:   targetURL,
+		Appended: fi.Size(),
+		Size:     st.Size,
+		Method:   method,
+	})
+	return nil
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/minio/pkg/console"
+)
+
+var applyFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "file, f",
+		Usage: "YAML document describing the desired state",
+	},
+	cli.BoolFlag{
+		Name:  "plan",
+		Usage: "only print the changes, do not apply them",
+	},
+	cli.BoolFlag{
+		Name:  "check",
+		Usage: "print the differences as JSON and exit with status 7 on drift, do not apply them",
+	},
+}
+
+var applyCmd = cli.Command{
+	Name:         "apply",
+	Usage:        "apply a declarative document of buckets, policies, users and groups",
+	Action:       mainApply,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(applyFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} --file FILE [ALIAS]
+
+  The live state of the resources listed by the document is compared to it,
+  the plan of the changes is printed then applied. Resources and fields not
+  listed are left untouched, nothing is ever removed. ALIAS overrides the
+  'alias' of the document.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DOCUMENT:
+  alias: myminio
+  policies:
+    - name: photos-readonly
+      document: {"Version": "2012-10-17", "Statement": [...]}
+  buckets:
+    - name: photos
+      region: us-east-1          # only used to create the bucket
+      objectLock: false          # only used to create the bucket
+      versioning: enabled        # enabled or suspended
+      anonymous: download        # private, public, download or upload
+      tags: {team: media}
+      lifecycle: {"Rules": [...]}  # as exported by 'mc ilm rule export'
+  users:
+    - accessKey: alice
+      secretKey: ${ALICE_SECRET} # only used to create the user
+      status: enabled
+      policies: [photos-readonly]
+  groups:
+    - name: media
+      members: [alice]
+      status: enabled
+      policies: [readwrite]
+
+EXAMPLES:
+  1. Print the changes needed for 'myminio' to match 'state.yaml'.
+     {{.Prompt}} {{.HelpName}} --plan -f state.yaml myminio
+
+  2. Apply 'state.yaml' to the alias it names.
+     {{.Prompt}} {{.HelpName}} -f state.yaml
+
+  3. Fail a pipeline when 'myminio' drifted from 'baseline.yaml'.
+     {{.Prompt}} {{.HelpName}} --check -f baseline.yaml myminio
+`,
+}
+
+// applyChangeMessage container for a change of the plan
+type applyChangeMessage struct {
+	Status string `json:"status"`
+	applyChange
+	Applied bool `json:"applied"`
+}
+
+func (m applyChangeMessage) String() string {
+	if m.Action == applyCreate {
+		return console.Colorize("ApplyCreate", fmt.Sprintf("+ %s %s", m.Resource, m.Name))
+	}
+	current, desired := m.Current, m.Desired
+	switch m.Field {
+	case "document", "lifecycle":
+		// Documents are too long to be printed, see --json.
+		current, desired = "", "(changed)"
+	}
+	if current == "" {
+		current = "-"
+	}
+	if desired == "" {
+		desired = "-"
+	}
+	return console.Colorize("ApplyUpdate", fmt.Sprintf("~ %s %s %s: %s => %s", m.Resource, m.Name, m.Field, current, desired))
+}
+
+func (m applyChangeMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// applySummaryMessage container for the result of `mc apply`
+type applySummaryMessage struct {
+	Status  string `json:"status"`
+	Alias   string `json:"alias"`
+	Changes int    `json:"changes"`
+	Applied bool   `json:"applied"`
+}
+
+func (m applySummaryMessage) String() string {
+	switch {
+	case m.Changes == 0:
+		return console.Colorize("ApplySummary", "No changes, `"+m.Alias+"` matches the document.")
+	case m.Applied:
+		return console.Colorize("ApplySummary", fmt.Sprintf("Applied %d change(s) to `%s`.", m.Changes, m.Alias))
+	default:
+		return console.Colorize("ApplySummary", fmt.Sprintf("Plan: %d change(s) to `%s`.", m.Changes, m.Alias))
+	}
+}
+
+func (m applySummaryMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// applyCheckMessage container for the drift reported by `mc apply --check`
+type applyCheckMessage struct {
+	Status  string        `json:"status"`
+	Alias   string        `json:"alias"`
+	Drift   bool          `json:"drift"`
+	Changes []applyChange `json:"changes"`
+}
+
+// String is JSON as well, the drift is meant to be read by tools.
+func (m applyCheckMessage) String() string {
+	return m.JSON()
+}
+
+func (m applyCheckMessage) JSON() string {
+	m.Status = "success"
+	if m.Changes == nil {
+		m.Changes = []applyChange{}
+	}
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkApply returns the drift report of the changes and the exit status
+// of `mc apply --check`, exitStatusDrift when the live state deviates.
+func checkApply(alias string, changes []applyChange) (applyCheckMessage, error) {
+	msg := applyCheckMessage{Alias: alias, Drift: len(changes) > 0, Changes: changes}
+	if msg.Drift {
+		return msg, exitStatus(exitStatusDrift)
+	}
+	return msg, nil
+}
+
+// loadApplyTarget loads the document and the live state of its alias.
+func loadApplyTarget(ctx *cli.Context) (*applyState, *liveState, string, *madmin.AdminClient) {
+	file := ctx.String("file")
+	if file == "" || len(ctx.Args()) > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	state, err := loadApplyState(file)
+	fatalIf(err.Trace(file), "Unable to load the document `"+file+"`.")
+
+	alias := state.Alias
+	if ctx.Args().Present() {
+		alias = cleanAlias(ctx.Args().First())
+	}
+	if alias == "" {
+		fatalIf(errInvalidArgument().Trace(file), "No alias given, set it in the document or as argument.")
+	}
+
+	var admClient *madmin.AdminClient
+	if state.needsAdmin() {
+		admClient, err = newAdminClient(alias)
+		fatalIf(err.Trace(alias), "Unable to initialize admin connection.")
+	}
+
+	live, err := fetchLiveState(globalContext, alias, state, admClient)
+	fatalIf(err.Trace(alias), "Unable to read the state of `"+alias+"`.")
+	return state, live, alias, admClient
+}
+
+// mainApply is the handle for "mc apply" command.
+func mainApply(ctx *cli.Context) error {
+	console.SetColor("ApplyCreate", color.New(color.FgGreen))
+	console.SetColor("ApplyUpdate", color.New(color.FgYellow))
+	console.SetColor("ApplySummary", color.New(color.Bold))
+
+	state, live, alias, admClient := loadApplyTarget(ctx)
+	changes := planApply(state, live)
+
+	if ctx.Bool("check") {
+		msg, e := checkApply(alias, changes)
+		printMsg(msg)
+		return e
+	}
+
+	apply := !ctx.Bool("plan")
+	if apply && len(changes) > 0 {
+		checkAliasWritable(ctx, alias, commandPath(ctx))
+	}
+	for _, change := range changes {
+		if apply {
+			err := executeChange(globalContext, alias, admClient, change)
+			fatalIf(err.Trace(alias, change.Resource, change.Name, change.Field), "Unable to apply the change to "+change.Resource+" `"+change.Name+"`.")
+		}
+		printMsg(applyChangeMessage{applyChange: change, Applied: apply})
+	}
+	printMsg(applySummaryMessage{Alias: alias, Changes: len(changes), Applied: apply})
+	return nil
+}
@@ -0,0 +1,576 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	iampolicy "github.com/minio/pkg/iam/policy"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// applyState is the declarative document of `mc apply`, only the
+// resources and the fields it lists are managed.
+type applyState struct {
+	Alias    string        `yaml:"alias,omitempty" json:"alias,omitempty"`
+	Policies []applyPolicy `yaml:"policies,omitempty" json:"policies,omitempty"`
+	Buckets  []applyBucket `yaml:"buckets,omitempty" json:"buckets,omitempty"`
+	Users    []applyUser   `yaml:"users,omitempty" json:"users,omitempty"`
+	Groups   []applyGroup  `yaml:"groups,omitempty" json:"groups,omitempty"`
+}
+
+type applyPolicy struct {
+	Name     string      `yaml:"name" json:"name"`
+	Document interface{} `yaml:"document" json:"document"`
+
+	document string
+}
+
+type applyBucket struct {
+	Name       string            `yaml:"name" json:"name"`
+	Region     string            `yaml:"region,omitempty" json:"region,omitempty"`
+	ObjectLock bool              `yaml:"objectLock,omitempty" json:"objectLock,omitempty"`
+	Versioning string            `yaml:"versioning,omitempty" json:"versioning,omitempty"`
+	Anonymous  string            `yaml:"anonymous,omitempty" json:"anonymous,omitempty"`
+	Tags       map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Lifecycle  interface{}       `yaml:"lifecycle,omitempty" json:"lifecycle,omitempty"`
+
+	lifecycle string
+}
+
+type applyUser struct {
+	AccessKey string   `yaml:"accessKey" json:"accessKey"`
+	SecretKey string   `yaml:"secretKey,omitempty" json:"-"`
+	Status    string   `yaml:"status,omitempty" json:"status,omitempty"`
+	Policies  []string `yaml:"policies,omitempty" json:"policies,omitempty"`
+}
+
+type applyGroup struct {
+	Name     string   `yaml:"name" json:"name"`
+	Status   string   `yaml:"status,omitempty" json:"status,omitempty"`
+	Members  []string `yaml:"members,omitempty" json:"members,omitempty"`
+	Policies []string `yaml:"policies,omitempty" json:"policies,omitempty"`
+}
+
+// liveState is the state of the resources listed by a document, the
+// resources missing on the server are absent from the maps.
+type liveState struct {
+	Policies map[string]string
+	Buckets  map[string]applyBucket
+	Users    map[string]applyUser
+	Groups   map[string]applyGroup
+}
+
+// applyChange is a step of the plan bringing the live state to the
+// desired state.
+type applyChange struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	Field    string `json:"field,omitempty"`
+	Current  string `json:"current,omitempty"`
+	Desired  string `json:"desired,omitempty"`
+
+	secret     string
+	objectLock bool
+}
+
+// Actions of the changes.
+const (
+	applyCreate = "create"
+	applyUpdate = "update"
+)
+
+// yamlToJSON converts the maps decoded from YAML to maps with string
+// keys, so they can be encoded to JSON.
+func yamlToJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = yamlToJSON(val)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = yamlToJSON(v[i])
+		}
+	}
+	return v
+}
+
+// normalizePolicy encodes a policy document in its canonical form.
+func normalizePolicy(document []byte) (string, error) {
+	p, e := iampolicy.ParseConfig(bytes.NewReader(document))
+	if e != nil {
+		return "", e
+	}
+	buf, e := json.Marshal(p)
+	return string(buf), e
+}
+
+// normalizeLifecycle encodes a lifecycle configuration in its canonical
+// form, an empty configuration is an empty string.
+func normalizeLifecycle(config *lifecycle.Configuration) string {
+	if config == nil || config.Empty() {
+		return ""
+	}
+	buf, _ := json.Marshal(config)
+	return string(buf)
+}
+
+// encodeTags encodes tags in the form accepted by SetTags.
+func encodeTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, url.QueryEscape(k)+"="+url.QueryEscape(tags[k]))
+	}
+	return strings.Join(values, "&")
+}
+
+// joinNames returns a sorted comma separated list.
+func joinNames(names []string) string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// splitNames is the reverse of joinNames.
+func splitNames(names string) []string {
+	var list []string
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// loadApplyState reads and validates a document.
+func loadApplyState(file string) (*applyState, *probe.Error) {
+	data, e := os.ReadFile(file)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	var state applyState
+	if e = yaml.UnmarshalStrict(data, &state); e != nil {
+		return nil, probe.NewError(e)
+	}
+
+	for i, p := range state.Policies {
+		if p.Name == "" {
+			return nil, probe.NewError(errors.New("policy without a name"))
+		}
+		document, ok := p.Document.(string)
+		if !ok {
+			buf, e := json.Marshal(yamlToJSON(p.Document))
+			if e != nil {
+				return nil, probe.NewError(e).Trace(p.Name)
+			}
+			document = string(buf)
+		}
+		if state.Policies[i].document, e = normalizePolicy([]byte(document)); e != nil {
+			return nil, probe.NewError(e).Trace(p.Name)
+		}
+	}
+
+	for i, b := range state.Buckets {
+		if b.Name == "" {
+			return nil, probe.NewError(errors.New("bucket without a name"))
+		}
+		switch strings.ToLower(b.Versioning) {
+		case "", "enabled", "suspended":
+		default:
+			return nil, probe.NewError(fmt.Errorf("`%s`: versioning must be enabled or suspended", b.Name))
+		}
+		if b.Anonymous != "" && !accessPerms(b.Anonymous).isValidAccessPERM() {
+			return nil, probe.NewError(fmt.Errorf("`%s`: anonymous must be one of private, public, download or upload", b.Name))
+		}
+		if b.Lifecycle != nil {
+			buf, e := json.Marshal(yamlToJSON(b.Lifecycle))
+			if e != nil {
+				return nil, probe.NewError(e).Trace(b.Name)
+			}
+			config := lifecycle.NewConfiguration()
+			if e = json.Unmarshal(buf, config); e != nil {
+				return nil, probe.NewError(e).Trace(b.Name)
+			}
+			state.Buckets[i].lifecycle = normalizeLifecycle(config)
+		}
+	}
+
+	for i, u := range state.Users {
+		if u.AccessKey == "" {
+			return nil, probe.NewError(errors.New("user without an access key"))
+		}
+		// Secrets are usually kept out of the document.
+		state.Users[i].SecretKey = os.ExpandEnv(u.SecretKey)
+		switch u.Status {
+		case "", string(madmin.AccountEnabled), string(madmin.AccountDisabled):
+		default:
+			return nil, probe.NewError(fmt.Errorf("`%s`: status must be enabled or disabled", u.AccessKey))
+		}
+	}
+
+	for _, g := range state.Groups {
+		if g.Name == "" {
+			return nil, probe.NewError(errors.New("group without a name"))
+		}
+		switch g.Status {
+		case "", string(madmin.GroupEnabled), string(madmin.GroupDisabled):
+		default:
+			return nil, probe.NewError(fmt.Errorf("`%s`: status must be enabled or disabled", g.Name))
+		}
+	}
+	return &state, nil
+}
+
+// needsAdmin returns true when the document manages IAM resources.
+func (s *applyState) needsAdmin() bool {
+	return len(s.Policies) > 0 || len(s.Users) > 0 || len(s.Groups) > 0
+}
+
+// fetchLiveBucket reads the fields of a bucket managed by the document.
+func fetchLiveBucket(ctx context.Context, alias string, desired applyBucket) (applyBucket, *probe.Error) {
+	bucketURL := alias + "/" + desired.Name
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return applyBucket{}, err.Trace(bucketURL)
+	}
+	live := applyBucket{Name: desired.Name}
+	if desired.Versioning != "" {
+		config, err := clnt.GetVersion(ctx)
+		if err != nil {
+			return live, err.Trace(bucketURL)
+		}
+		live.Versioning = strings.ToLower(config.Status)
+	}
+	if desired.Anonymous != "" {
+		perm, _, err := doGetAccess(ctx, bucketURL)
+		if err != nil {
+			return live, err.Trace(bucketURL)
+		}
+		live.Anonymous = string(perm)
+	}
+	if desired.Tags != nil {
+		tags, err := clnt.GetTags(ctx, "")
+		if err != nil && minio.ToErrorResponse(err.ToGoError()).Code != "NoSuchTagSet" {
+			return live, err.Trace(bucketURL)
+		}
+		live.Tags = tags
+	}
+	if desired.Lifecycle != nil {
+		config, err := clnt.GetLifecycle(ctx)
+		if err != nil && minio.ToErrorResponse(err.ToGoError()).Code != "NoSuchLifecycleConfiguration" {
+			return live, err.Trace(bucketURL)
+		}
+		live.lifecycle = normalizeLifecycle(config)
+	}
+	return live, nil
+}
+
+// fetchLiveState reads the state of the resources listed by the document.
+func fetchLiveState(ctx context.Context, alias string, state *applyState, admClient *madmin.AdminClient) (*liveState, *probe.Error) {
+	live := &liveState{
+		Policies: map[string]string{},
+		Buckets:  map[string]applyBucket{},
+		Users:    map[string]applyUser{},
+		Groups:   map[string]applyGroup{},
+	}
+
+	if len(state.Buckets) > 0 {
+		clnt, err := newClient(alias)
+		if err != nil {
+			return nil, err.Trace(alias)
+		}
+		buckets, err := clnt.ListBuckets(ctx)
+		if err != nil {
+			return nil, err.Trace(alias)
+		}
+		exists := map[string]bool{}
+		for _, b := range buckets {
+			exists[path.Base(b.URL.Path)] = true
+		}
+		for _, desired := range state.Buckets {
+			if !exists[desired.Name] {
+				continue
+			}
+			bucket, err := fetchLiveBucket(ctx, alias, desired)
+			if err != nil {
+				return nil, err
+			}
+			live.Buckets[desired.Name] = bucket
+		}
+	}
+
+	if admClient == nil {
+		return live, nil
+	}
+
+	if len(state.Policies) > 0 {
+		policies, e := admClient.ListCannedPolicies(ctx)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(alias)
+		}
+		for name, document := range policies {
+			if normalized, e := normalizePolicy(document); e == nil {
+				live.Policies[name] = normalized
+			}
+		}
+	}
+
+	if len(state.Users) > 0 {
+		users, e := admClient.ListUsers(ctx)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(alias)
+		}
+		for accessKey, info := range users {
+			live.Users[accessKey] = applyUser{
+				AccessKey: accessKey,
+				Status:    string(info.Status),
+				Policies:  splitNames(info.PolicyName),
+			}
+		}
+	}
+
+	if len(state.Groups) > 0 {
+		groups, e := admClient.ListGroups(ctx)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(alias)
+		}
+		for _, name := range groups {
+			desc, e := admClient.GetGroupDescription(ctx, name)
+			if e != nil {
+				return nil, probe.NewError(e).Trace(alias, name)
+			}
+			live.Groups[name] = applyGroup{
+				Name:     name,
+				Status:   desc.Status,
+				Members:  desc.Members,
+				Policies: splitNames(desc.Policy),
+			}
+		}
+	}
+	return live, nil
+}
+
+// planApply computes the changes bringing the live state to the
+// desired state. Policies come first to be attached, users before the
+// groups they are members of.
+func planApply(state *applyState, live *liveState) []applyChange {
+	var changes []applyChange
+	update := func(resource, name, field, current, desired string) {
+		if current != desired {
+			changes = append(changes, applyChange{Action: applyUpdate, Resource: resource, Name: name, Field: field, Current: current, Desired: desired})
+		}
+	}
+
+	for _, p := range state.Policies {
+		current, ok := live.Policies[p.Name]
+		if !ok {
+			changes = append(changes, applyChange{Action: applyCreate, Resource: "policy", Name: p.Name, Desired: p.document})
+			continue
+		}
+		update("policy", p.Name, "document", current, p.document)
+	}
+
+	for _, b := range state.Buckets {
+		current, ok := live.Buckets[b.Name]
+		if !ok {
+			changes = append(changes, applyChange{Action: applyCreate, Resource: "bucket", Name: b.Name, Desired: b.Region, objectLock: b.ObjectLock})
+			current = applyBucket{Anonymous: string(accessPrivate)}
+		}
+		if b.Versioning != "" {
+			update("bucket", b.Name, "versioning", current.Versioning, strings.ToLower(b.Versioning))
+		}
+		if b.Anonymous != "" {
+			desired := b.Anonymous
+			if desired == string(accessNone) {
+				desired = string(accessPrivate)
+			}
+			update("bucket", b.Name, "anonymous", current.Anonymous, desired)
+		}
+		if b.Tags != nil {
+			update("bucket", b.Name, "tags", encodeTags(current.Tags), encodeTags(b.Tags))
+		}
+		if b.Lifecycle != nil {
+			update("bucket", b.Name, "lifecycle", current.lifecycle, b.lifecycle)
+		}
+	}
+
+	for _, u := range state.Users {
+		current, ok := live.Users[u.AccessKey]
+		if !ok {
+			changes = append(changes, applyChange{Action: applyCreate, Resource: "user", Name: u.AccessKey, secret: u.SecretKey})
+			current = applyUser{Status: string(madmin.AccountEnabled)}
+		}
+		if u.Status != "" {
+			update("user", u.AccessKey, "status", current.Status, u.Status)
+		}
+		if u.Policies != nil {
+			update("user", u.AccessKey, "policies", joinNames(current.Policies), joinNames(u.Policies))
+		}
+	}
+
+	for _, g := range state.Groups {
+		current, ok := live.Groups[g.Name]
+		if !ok {
+			changes = append(changes, applyChange{Action: applyCreate, Resource: "group", Name: g.Name, Desired: joinNames(g.Members)})
+			current = applyGroup{Status: string(madmin.GroupEnabled), Members: g.Members}
+		}
+		if g.Members != nil {
+			update("group", g.Name, "members", joinNames(current.Members), joinNames(g.Members))
+		}
+		if g.Status != "" {
+			update("group", g.Name, "status", current.Status, g.Status)
+		}
+		if g.Policies != nil {
+			update("group", g.Name, "policies", joinNames(current.Policies), joinNames(g.Policies))
+		}
+	}
+	return changes
+}
+
+// diffNames returns the names to add and to remove to go from the
+// current to the desired list.
+func diffNames(current, desired string) (add, remove []string) {
+	c, d := map[string]bool{}, map[string]bool{}
+	for _, name := range splitNames(current) {
+		c[name] = true
+	}
+	for _, name := range splitNames(desired) {
+		d[name] = true
+		if !c[name] {
+			add = append(add, name)
+		}
+	}
+	for _, name := range splitNames(current) {
+		if !d[name] {
+			remove = append(remove, name)
+		}
+	}
+	return add, remove
+}
+
+// executeChange applies a change of the plan.
+func executeChange(ctx context.Context, alias string, admClient *madmin.AdminClient, c applyChange) *probe.Error {
+	bucketURL := alias + "/" + c.Name
+	switch c.Resource + "/" + c.Field {
+	case "policy/", "policy/document":
+		return probe.NewError(admClient.AddCannedPolicy(ctx, c.Name, []byte(c.Desired)))
+	case "bucket/":
+		clnt, err := newClient(bucketURL)
+		if err != nil {
+			return err
+		}
+		return clnt.MakeBucket(ctx, c.Desired, false, c.objectLock)
+	case "bucket/versioning":
+		clnt, err := newClient(bucketURL)
+		if err != nil {
+			return err
+		}
+		status := "enable"
+		if c.Desired == "suspended" {
+			status = "suspend"
+		}
+		return clnt.SetVersion(ctx, status, nil, false)
+	case "bucket/anonymous":
+		return doSetAccess(ctx, bucketURL, accessPerms(c.Desired))
+	case "bucket/tags":
+		clnt, err := newClient(bucketURL)
+		if err != nil {
+			return err
+		}
+		if c.Desired == "" {
+			return clnt.DeleteTags(ctx, "")
+		}
+		return clnt.SetTags(ctx, "", c.Desired)
+	case "bucket/lifecycle":
+		clnt, err := newClient(bucketURL)
+		if err != nil {
+			return err
+		}
+		config := lifecycle.NewConfiguration()
+		if c.Desired != "" {
+			if e := json.Unmarshal([]byte(c.Desired), config); e != nil {
+				return probe.NewError(e)
+			}
+		}
+		return clnt.SetLifecycle(ctx, config)
+	case "user/":
+		if c.secret == "" {
+			return probe.NewError(fmt.Errorf("`%s`: secretKey is required to create a user", c.Name))
+		}
+		return probe.NewError(admClient.AddUser(ctx, c.Name, c.secret))
+	case "user/status":
+		return probe.NewError(admClient.SetUserStatus(ctx, c.Name, madmin.AccountStatus(c.Desired)))
+	case "user/policies", "group/policies":
+		add, remove := diffNames(c.Current, c.Desired)
+		req := madmin.PolicyAssociationReq{User: c.Name}
+		if c.Resource == "group" {
+			req = madmin.PolicyAssociationReq{Group: c.Name}
+		}
+		if len(add) > 0 {
+			req.Policies = add
+			if e := admClient.AttachPolicy(ctx, req); e != nil {
+				return probe.NewError(e)
+			}
+		}
+		if len(remove) > 0 {
+			req.Policies = remove
+			if e := admClient.DetachPolicy(ctx, req); e != nil {
+				return probe.NewError(e)
+			}
+		}
+		return nil
+	case "group/":
+		// Groups are created by adding their members.
+		return probe.NewError(admClient.UpdateGroupMembers(ctx, madmin.GroupAddRemove{Group: c.Name, Members: splitNames(c.Desired)}))
+	case "group/members":
+		add, remove := diffNames(c.Current, c.Desired)
+		if len(add) > 0 {
+			if e := admClient.UpdateGroupMembers(ctx, madmin.GroupAddRemove{Group: c.Name, Members: add}); e != nil {
+				return probe.NewError(e)
+			}
+		}
+		if len(remove) > 0 {
+			return probe.NewError(admClient.UpdateGroupMembers(ctx, madmin.GroupAddRemove{Group: c.Name, Members: remove, IsRemove: true}))
+		}
+		return nil
+	case "group/status":
+		return probe.NewError(admClient.SetGroupStatus(ctx, c.Name, madmin.GroupStatus(c.Desired)))
+	}
+	return errInvalidArgument().Trace(c.Resource, c.Field)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+const testApplyDocument = `alias: myminio
+policies:
+  - name: photos-readonly
+    document:
+      Version: "2012-10-17"
+      Statement:
+        - Effect: Allow
+          Action: ["s3:GetObject"]
+          Resource: ["arn:aws:s3:::photos/*"]
+buckets:
+  - name: photos
+    versioning: enabled
+    tags: {team: media}
+  - name: logs
+    anonymous: private
+    versioning: suspended
+users:
+  - accessKey: alice
+    secretKey: ${TEST_APPLY_SECRET}
+    policies: [photos-readonly]
+groups:
+  - name: media
+    members: [alice, bob]
+    status: enabled
+`
+
+func TestPlanApply(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.yaml")
+	if e := os.WriteFile(file, []byte(testApplyDocument), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	t.Setenv("TEST_APPLY_SECRET", "alicesecret")
+
+	state, err := loadApplyState(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Users[0].SecretKey != "alicesecret" {
+		t.Fatalf("secret key not expanded: %q", state.Users[0].SecretKey)
+	}
+
+	live := &liveState{
+		Policies: map[string]string{"photos-readonly": state.Policies[0].document},
+		Buckets: map[string]applyBucket{
+			"logs": {Name: "logs", Anonymous: "private", Versioning: "enabled"},
+		},
+		Users: map[string]applyUser{},
+		Groups: map[string]applyGroup{
+			"media": {Name: "media", Status: "enabled", Members: []string{"bob", "carol"}},
+		},
+	}
+
+	type change struct{ action, resource, name, field, current, desired string }
+	expected := []change{
+		{applyCreate, "bucket", "photos", "", "", ""},
+		{applyUpdate, "bucket", "photos", "versioning", "", "enabled"},
+		{applyUpdate, "bucket", "photos", "tags", "", "team=media"},
+		{applyUpdate, "bucket", "logs", "versioning", "enabled", "suspended"},
+		{applyCreate, "user", "alice", "", "", ""},
+		{applyUpdate, "user", "alice", "policies", "", "photos-readonly"},
+		{applyUpdate, "group", "media", "members", "bob,carol", "alice,bob"},
+	}
+	var got []change
+	for _, c := range planApply(state, live) {
+		got = append(got, change{c.Action, c.Resource, c.Name, c.Field, c.Current, c.Desired})
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	add, remove := diffNames("bob,carol", "alice,bob")
+	if !reflect.DeepEqual(add, []string{"alice"}) || !reflect.DeepEqual(remove, []string{"carol"}) {
+		t.Fatalf("unexpected members diff %v %v", add, remove)
+	}
+}
+
+func TestCheckApply(t *testing.T) {
+	defer func(porcelain bool) { globalPorcelain = porcelain }(globalPorcelain)
+
+	drift := []applyChange{{Action: "update", Resource: "bucket", Name: "photos", Field: "versioning", Current: "suspended", Desired: "enabled"}}
+	testCases := []struct {
+		changes   []applyChange
+		porcelain bool
+		status    int
+		json      string
+	}{
+		{nil, false, 0, `{"status":"success","alias":"myminio","drift":false,"changes":[]}`},
+		// The drift status is kept without --porcelain.
+		{drift, false, exitStatusDrift, `{"status":"success","alias":"myminio","drift":true,"changes":[{"action":"update","resource":"bucket","name":"photos","field":"versioning","current":"suspended","desired":"enabled"}]}`},
+		{drift, true, exitStatusDrift, ""},
+	}
+	for i, testCase := range testCases {
+		globalPorcelain = testCase.porcelain
+		msg, e := checkApply("myminio", testCase.changes)
+		status := 0
+		if e != nil {
+			exitErr, ok := e.(cli.ExitCoder)
+			if !ok {
+				t.Fatalf("Test %d: expected an exit status, got %v", i+1, e)
+			}
+			status = exitErr.ExitCode()
+		}
+		if status != testCase.status {
+			t.Errorf("Test %d: expected exit status %d, got %d", i+1, testCase.status, status)
+		}
+		// String is JSON as well.
+		if testCase.json != "" && msg.String() != testCase.json {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.json, msg.String())
+		}
+	}
+}
@@ -86,23 +86,44 @@ func completeAdminConfigKeys(aliasPath string, keyPrefix string) (prediction []s
 func completeS3Path(s3Path string) (prediction []string) {
 	// Convert alias/bucket/incompl to alias/bucket/ to list its contents
 	parentDirPath := filepath.Dir(s3Path) + "/"
-	clnt, err := newClient(parentDirPath)
-	if err != nil {
-		return nil
-	}
 
 	// Calculate alias from the path
 	alias := splitStr(s3Path, "/", 3)[0]
 
-	// List dirPath content and only pick elements that corresponds
-	// to the path that we want to complete
-	for content := range clnt.List(globalContext, ListOptions{Recursive: false, ShowDir: DirFirst}) {
-		cmplS3Path := alias + getKey(content)
-		if content.Type.IsDir() {
-			if !strings.HasSuffix(cmplS3Path, "/") {
-				cmplS3Path += "/"
+	// Bucket names may be cached, see `mc completion --cache-buckets`
+	listBuckets := parentDirPath == alias+"/"
+	var paths []string
+	cached := false
+	if listBuckets {
+		paths, cached = loadCompletionCache(alias)
+	}
+
+	if !cached {
+		clnt, err := newClient(parentDirPath)
+		if err != nil {
+			return nil
+		}
+
+		// List dirPath content
+		for content := range clnt.List(globalContext, ListOptions{Recursive: false, ShowDir: DirFirst}) {
+			if content.Err != nil {
+				return nil
+			}
+			cmplS3Path := alias + getKey(content)
+			if content.Type.IsDir() {
+				if !strings.HasSuffix(cmplS3Path, "/") {
+					cmplS3Path += "/"
+				}
 			}
+			paths = append(paths, cmplS3Path)
 		}
+		if listBuckets {
+			saveCompletionCache(alias, paths)
+		}
+	}
+
+	// Only pick elements that corresponds to the path that we want to complete
+	for _, cmplS3Path := range paths {
 		if strings.HasPrefix(cmplS3Path, s3Path) {
 			prediction = append(prediction, cmplS3Path)
 		}
@@ -237,19 +258,25 @@ var completeCmds = map[string]complete.Predictor{
 	"/rb":        complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
 	"/cat":       complete.PredictOr(s3Completer, fsCompleter),
 	"/head":      complete.PredictOr(s3Completer, fsCompleter),
+	"/tail":      complete.PredictOr(s3Completer, fsCompleter),
 	"/diff":      complete.PredictOr(s3Completer, fsCompleter),
 	"/find":      complete.PredictOr(s3Completer, fsCompleter),
 	"/mirror":    complete.PredictOr(s3Completer, fsCompleter),
 	"/pipe":      complete.PredictOr(s3Completer, fsCompleter),
+	"/get":       s3Completer,
+	"/put":       complete.PredictOr(fsCompleter, s3Completer),
+	"/append":    complete.PredictOr(fsCompleter, s3Completer),
+	"/compose":   s3Completer,
 	"/stat":      complete.PredictOr(s3Completer, fsCompleter),
 	"/watch":     complete.PredictOr(s3Completer, fsCompleter),
 	"/anonymous": complete.PredictOr(s3Completer, fsCompleter),
 	"/tree":      complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
 	"/du":        complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
 
-	"/retention/set":   s3Completer,
-	"/retention/clear": s3Completer,
-	"/retention/info":  s3Completer,
+	"/retention/set":    s3Completer,
+	"/retention/clear":  s3Completer,
+	"/retention/info":   s3Completer,
+	"/retention/report": s3Completer,
 
 	"/legalhold/set":   s3Completer,
 	"/legalhold/clear": s3Completer,
@@ -262,9 +289,20 @@ var completeCmds = map[string]complete.Predictor{
 	"/event/list":   s3Complete{deepLevel: 2},
 	"/event/remove": s3Complete{deepLevel: 2},
 
-	"/encrypt/set":   s3Complete{deepLevel: 2},
-	"/encrypt/info":  s3Complete{deepLevel: 2},
-	"/encrypt/clear": s3Complete{deepLevel: 2},
+	"/encrypt/set":    s3Complete{deepLevel: 2},
+	"/encrypt/info":   s3Complete{deepLevel: 2},
+	"/encrypt/clear":  s3Complete{deepLevel: 2},
+	"/encrypt/report": s3Completer,
+
+	"/cors/set":         s3Complete{deepLevel: 2},
+	"/cors/get":         s3Complete{deepLevel: 2},
+	"/cors/remove":      s3Complete{deepLevel: 2},
+	"/cors/add-rule":    s3Complete{deepLevel: 2},
+	"/cors/remove-rule": s3Complete{deepLevel: 2},
+	"/cors/test":        s3Complete{deepLevel: 2},
+
+	"/website/deploy": complete.PredictOr(s3Completer, fsCompleter),
+	"/website/status": s3Complete{deepLevel: 2},
 
 	"/replicate/add":    s3Complete{deepLevel: 2},
 	"/replicate/edit":   s3Complete{deepLevel: 2},
@@ -273,16 +311,23 @@ var completeCmds = map[string]complete.Predictor{
 	"/replicate/remove": s3Complete{deepLevel: 2},
 	"/replicate/diff":   s3Complete{deepLevel: 2},
 
-	"/replicate/export":        s3Complete{deepLevel: 2},
-	"/replicate/import":        s3Complete{deepLevel: 2},
-	"/replicate/status":        s3Complete{deepLevel: 2},
-	"/replicate/resync/start":  s3Complete{deepLevel: 3},
-	"/replicate/resync/status": s3Complete{deepLevel: 3},
+	"/replicate/export":           s3Complete{deepLevel: 2},
+	"/replicate/import":           s3Complete{deepLevel: 2},
+	"/replicate/status":           s3Complete{deepLevel: 2},
+	"/replicate/resync/start":     s3Complete{deepLevel: 3},
+	"/replicate/resync/status":    s3Complete{deepLevel: 3},
+	"/replicate/bandwidth/status": s3Complete{deepLevel: 3},
+
+	"/access-report": s3Complete{deepLevel: 2},
 
 	"/tag/list":   s3Completer,
 	"/tag/remove": s3Completer,
 	"/tag/set":    s3Completer,
 
+	"/meta/get":    s3Completer,
+	"/meta/set":    s3Completer,
+	"/meta/remove": s3Completer,
+
 	"/version/info":    s3Complete{deepLevel: 2},
 	"/version/enable":  s3Complete{deepLevel: 2},
 	"/version/suspend": s3Complete{deepLevel: 2},
@@ -294,8 +339,26 @@ var completeCmds = map[string]complete.Predictor{
 
 	"/share/download": s3Completer,
 	"/share/list":     nil,
+	"/share/revoke":   nil,
 	"/share/upload":   s3Completer,
 
+	"/completion": nil,
+	"/browse":     s3Completer,
+	"/shell":      s3Completer,
+	"/daemon":     nil,
+	"/mount":      s3Completer,
+	"/apply":      aliasCompleter,
+	"/checksum":   s3Completer,
+
+	"/inventory/generate": s3Completer,
+
+	"/migrate/assess": s3Completer,
+
+	"/plugin/list": nil,
+
+	"/checksum/verify": s3Completer,
+	"/verify":          s3Completer,
+
 	"/ilm/list":    s3Complete{deepLevel: 2},
 	"/ilm/add":     s3Complete{deepLevel: 2},
 	"/ilm/edit":    s3Complete{deepLevel: 2},
@@ -317,8 +380,9 @@ var completeCmds = map[string]complete.Predictor{
 	// Admin API commands MinIO only.
 	"/admin/heal": s3Completer,
 
-	"/admin/info": aliasCompleter,
-	"/admin/logs": aliasCompleter,
+	"/admin/info":         aliasCompleter,
+	"/admin/api-versions": aliasCompleter,
+	"/admin/logs":         aliasCompleter,
 
 	"/admin/config/get":     adminConfigCompleter,
 	"/admin/config/set":     adminConfigCompleter,
@@ -347,9 +411,16 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/top/locks": aliasCompleter,
 	"/admin/top/api":   aliasCompleter,
 
+	"/admin/drive/list":          aliasCompleter,
+	"/admin/drive/format-status": aliasCompleter,
+
+	"/admin/node/list": aliasCompleter,
+	"/admin/node/info": aliasCompleter,
+
 	"/admin/scanner/status": aliasCompleter,
 	"/admin/scanner/trace":  aliasCompleter,
 
+	"/admin/service/status":   aliasCompleter,
 	"/admin/service/stop":     aliasCompleter,
 	"/admin/service/restart":  aliasCompleter,
 	"/admin/service/freeze":   aliasCompleter,
@@ -395,6 +466,12 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/policy/attach":   aliasCompleter,
 	"/admin/policy/detach":   aliasCompleter,
 	"/admin/policy/entities": aliasCompleter,
+	"/admin/policy/tree":     aliasCompleter,
+
+	"/admin/notify/add":    aliasCompleter,
+	"/admin/notify/list":   aliasCompleter,
+	"/admin/notify/remove": aliasCompleter,
+	"/admin/notify/test":   aliasCompleter,
 
 	"/admin/user/add":     aliasCompleter,
 	"/admin/user/disable": aliasCompleter,
@@ -427,9 +504,13 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/bucket/remote/remove": aliasCompleter,
 	"/admin/bucket/quota":         aliasCompleter,
 	"/admin/bucket/info":          s3Complete{deepLevel: 2},
+	"/admin/bucket/stats":         s3Complete{deepLevel: 2},
 
 	"/admin/kms/key/create": aliasCompleter,
 	"/admin/kms/key/status": aliasCompleter,
+	"/admin/kms/key/list":   aliasCompleter,
+	"/admin/kms/key/import": aliasCompleter,
+	"/admin/kms/key/backup": aliasCompleter,
 
 	"/admin/subnet/health":   aliasCompleter,
 	"/admin/subnet/register": aliasCompleter,
@@ -441,12 +522,13 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/tier/remove": nil,
 	"/admin/tier/verify": nil,
 
-	"/ilm/tier/info":   nil,
-	"/ilm/tier/list":   nil,
-	"/ilm/tier/add":    nil,
-	"/ilm/tier/update": nil,
-	"/ilm/tier/check":  nil,
-	"/ilm/tier/remove": nil,
+	"/ilm/tier/info":    nil,
+	"/ilm/tier/list":    nil,
+	"/ilm/tier/add":     nil,
+	"/ilm/tier/update":  nil,
+	"/ilm/tier/check":   nil,
+	"/ilm/tier/remove":  nil,
+	"/ilm/tier/migrate": nil,
 
 	"/admin/replicate/add":           aliasCompleter,
 	"/admin/replicate/update":        aliasCompleter,
@@ -457,6 +539,7 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/replicate/resync/start":  aliasCompleter,
 	"/admin/replicate/resync/cancel": aliasCompleter,
 	"/admin/replicate/resync/status": aliasCompleter,
+	"/admin/replicate/failover":      aliasCompleter,
 
 	"/admin/cluster/bucket/export": aliasCompleter,
 	"/admin/cluster/bucket/import": aliasCompleter,
@@ -467,6 +550,16 @@ var completeCmds = map[string]complete.Predictor{
 	"/alias/list":   aliasCompleter,
 	"/alias/remove": aliasCompleter,
 	"/alias/import": nil,
+	"/alias/export": aliasCompleter,
+
+	"/alias/defaults": aliasCompleter,
+	"/alias/use":      aliasCompleter,
+	"/alias/verify":   aliasCompleter,
+	"/alias/protect":  aliasCompleter,
+
+	"/alias/group/add":    nil,
+	"/alias/group/list":   nil,
+	"/alias/group/remove": nil,
 
 	"/support/callhome":     aliasCompleter,
 	"/support/register":     aliasCompleter,
@@ -486,19 +579,32 @@ var completeCmds = map[string]complete.Predictor{
 
 	"/license/register": aliasCompleter,
 	"/license/info":     aliasCompleter,
+	"/license/usage":    aliasCompleter,
 	"/license/update":   aliasCompleter,
+	"/license/apply":    aliasCompleter,
 
 	"/update":         nil,
 	"/ready":          aliasCompleter,
 	"/ping":           aliasCompleter,
 	"/od":             nil,
 	"/batch/generate": aliasCompleter,
+	"/batch/validate": fsCompleter,
 	"/batch/start":    aliasCompleter,
 	"/batch/list":     aliasCompleter,
 	"/batch/status":   aliasCompleter,
+	"/batch/failures": aliasCompleter,
 	"/batch/describe": aliasCompleter,
 	"/batch/cancel":   aliasCompleter,
 
+	"/session/list":   nil,
+	"/session/resume": nil,
+	"/session/abort":  nil,
+
+	"/batch/schedule/add":    aliasCompleter,
+	"/batch/schedule/list":   aliasCompleter,
+	"/batch/schedule/remove": nil,
+	"/batch/schedule/run":    nil,
+
 	"/quota/set":   aliasCompleter,
 	"/quota/info":  aliasCompleter,
 	"/quota/clear": aliasCompleter,
@@ -546,10 +652,17 @@ func cmdToCompleteCmd(cmd cli.Command, parentPath string) complete.Command {
 
 // Main function to answer to bash completion calls
 func mainComplete() error {
+	// Answer to bash completion call
+	complete.New(filepath.Base(os.Args[0]), mcCompleteCommand(appCmds)).Run()
+	return nil
+}
+
+// mcCompleteCommand returns the completion of the commands.
+func mcCompleteCommand(cmds []cli.Command) complete.Command {
 	// Recursively register all commands and subcommands
 	// along with global and local flags
 	complCmds := make(complete.Commands)
-	for _, cmd := range appCmds {
+	for _, cmd := range cmds {
 		if cmd.Hidden {
 			continue
 		}
@@ -559,11 +672,8 @@ func mainComplete() error {
 		}
 	}
 	complFlags := flagsToCompleteFlags(globalFlags)
-	mcComplete := complete.Command{
+	return complete.Command{
 		Sub:         complCmds,
 		GlobalFlags: complFlags,
 	}
-	// Answer to bash completion call
-	complete.New(filepath.Base(os.Args[0]), mcComplete).Run()
-	return nil
 }
@@ -0,0 +1,219 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var batchFailuresFlags = []cli.Flag{
+	cli.DurationFlag{
+		Name:  "duration",
+		Usage: "stop collecting failures after the given duration (default: until the job ends)",
+	},
+}
+
+var batchFailuresCmd = cli.Command{
+	Name:         "failures",
+	Usage:        "follow the objects a running batch job fails to process",
+	Action:       mainBatchFailures,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(batchFailuresFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET JOBID
+
+  This is a live tail of the failures traced by the servers while the
+  command runs, objects which failed before it started are not listed:
+  the servers keep no report of the failed objects of a job. The list
+  is shown once the job completes or fails, or the collection duration
+  has elapsed.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all objects the JOB fails to process until it completes:
+     {{.Prompt}} {{.HelpName}} myminio KwSysDpxcBU9FNhGkn2dCf
+
+  2. Collect the failures of the JOB for 10 minutes:
+     {{.Prompt}} {{.HelpName}} --duration 10m myminio KwSysDpxcBU9FNhGkn2dCf
+`,
+}
+
+// batchFailureMessage - an object a batch job failed to process
+type batchFailureMessage struct {
+	Status    string    `json:"status"`
+	JobID     string    `json:"jobID"`
+	Object    string    `json:"object"`
+	VersionID string    `json:"versionID,omitempty"`
+	Attempts  string    `json:"attempts,omitempty"`
+	Node      string    `json:"node"`
+	Time      time.Time `json:"time"`
+	Error     string    `json:"error"`
+}
+
+func (m batchFailureMessage) String() string {
+	object := m.Object
+	if m.VersionID != "" {
+		object += " (" + m.VersionID + ")"
+	}
+	return fmt.Sprintf("%s %s %s",
+		console.Colorize("BatchFailureTime", m.Time.Format(printDate)),
+		console.Colorize("BatchFailureObject", object),
+		console.Colorize("BatchFailureError", m.Error))
+}
+
+func (m batchFailureMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+// batchFailureFromTrace converts a batch job trace entry into a failure,
+// ok is false if the entry is not a failure of the given job.
+func batchFailureFromTrace(jobID string, t madmin.TraceInfo) (m batchFailureMessage, ok bool) {
+	if t.Error == "" {
+		return m, false
+	}
+	if id, found := t.Custom["jobID"]; found && id != jobID {
+		return m, false
+	}
+	return batchFailureMessage{
+		JobID:     jobID,
+		Object:    t.Path,
+		VersionID: t.Custom["versionID"],
+		Attempts:  t.Custom["attempts"],
+		Node:      t.NodeName,
+		Time:      t.Time,
+		Error:     t.Error,
+	}, true
+}
+
+// checkBatchFailuresSyntax - validate all the passed arguments
+func checkBatchFailuresSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainBatchFailures is the handle for "mc batch failures" command.
+func mainBatchFailures(ctx *cli.Context) error {
+	checkBatchFailuresSyntax(ctx)
+
+	console.SetColor("BatchFailureTime", color.New(color.FgGreen))
+	console.SetColor("BatchFailureObject", color.New(color.Bold))
+	console.SetColor("BatchFailureError", color.New(color.FgRed))
+
+	aliasedURL := ctx.Args().Get(0)
+	jobID := ctx.Args().Get(1)
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize admin client.")
+
+	ctxt, cancel := context.WithCancel(globalContext)
+	defer cancel()
+	if d := ctx.Duration("duration"); d > 0 {
+		ctxt, cancel = context.WithTimeout(ctxt, d)
+		defer cancel()
+	}
+
+	desc, e := client.DescribeBatchJob(ctxt, jobID)
+	fatalIf(probe.NewError(e), "Unable to lookup job")
+
+	job, e := parseBatchJobDefinition([]byte(desc))
+	fatalIf(probe.NewError(e), "Unable to parse the job definition")
+
+	opts := madmin.ServiceTraceOpts{OnlyErrors: true}
+	switch job.Type() {
+	case madmin.BatchJobReplicate:
+		opts.BatchReplication = true
+	case madmin.BatchJobKeyRotate:
+		opts.BatchKeyRotation = true
+	default:
+		fatalIf(errInvalidArgument().Trace(string(job.Type())), "Listing failures is not supported for %s jobs", job.Type())
+	}
+
+	// stop once the job has ended
+	go func() {
+		e := client.Metrics(ctxt, madmin.MetricsOptions{
+			Type:    madmin.MetricsBatchJobs,
+			ByJobID: jobID,
+		}, func(metrics madmin.RealtimeMetrics) {
+			if metrics.Aggregated.BatchJobs == nil {
+				return
+			}
+			if job := metrics.Aggregated.BatchJobs.Jobs[jobID]; job.Complete || job.Failed {
+				cancel()
+			}
+		})
+		if e != nil && !errors.Is(e, context.Canceled) && !errors.Is(e, context.DeadlineExceeded) {
+			errorIf(probe.NewError(e), "Unable to follow the job status")
+		}
+	}()
+
+	if !globalJSON {
+		console.Infoln("Collecting failures of job " + jobID + "...")
+	}
+
+	var failures []batchFailureMessage
+	for traceInfo := range client.ServiceTrace(ctxt, opts) {
+		if traceInfo.Err != nil {
+			fatalIf(probe.NewError(traceInfo.Err), "Unable to listen for batch job failures")
+		}
+		failure, ok := batchFailureFromTrace(jobID, traceInfo.Trace)
+		if !ok {
+			continue
+		}
+		if globalJSON {
+			printMsg(failure)
+			continue
+		}
+		failures = append(failures, failure)
+	}
+
+	if globalJSON {
+		return nil
+	}
+
+	var b strings.Builder
+	for _, failure := range failures {
+		fmt.Fprintln(&b, failure.String())
+	}
+	fmt.Fprintf(&b, "%d failed objects\n", len(failures))
+	if isTerminal() {
+		// page long failure lists
+		globalHelpPager.Write([]byte(b.String()))
+	} else {
+		fmt.Print(b.String())
+	}
+	return nil
+}
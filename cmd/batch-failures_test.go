@@ -0,0 +1,63 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestBatchFailureFromTrace(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		trace    madmin.TraceInfo
+		ok       bool
+		expected batchFailureMessage
+	}{
+		// Entries without an error are not failures.
+		{madmin.TraceInfo{Path: "bucket/object", Custom: map[string]string{"jobID": "job"}}, false, batchFailureMessage{}},
+		// Failures of other jobs are skipped.
+		{madmin.TraceInfo{Path: "bucket/object", Error: "access denied", Custom: map[string]string{"jobID": "other"}}, false, batchFailureMessage{}},
+		{
+			madmin.TraceInfo{
+				NodeName: "node1", Time: now, Path: "bucket/object", Error: "access denied",
+				Custom: map[string]string{"jobID": "job", "versionID": "v1", "attempts": "3"},
+			},
+			true,
+			batchFailureMessage{JobID: "job", Object: "bucket/object", VersionID: "v1", Attempts: "3", Node: "node1", Time: now, Error: "access denied"},
+		},
+		// Entries which do not name their job are attributed to the followed job.
+		{
+			madmin.TraceInfo{NodeName: "node1", Time: now, Path: "bucket/object", Error: "access denied"},
+			true,
+			batchFailureMessage{JobID: "job", Object: "bucket/object", Node: "node1", Time: now, Error: "access denied"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		m, ok := batchFailureFromTrace("job", testCase.trace)
+		if ok != testCase.ok {
+			t.Fatalf("Test %d: expected ok %t, got %t", i+1, testCase.ok, ok)
+		}
+		if m != testCase.expected {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, m)
+		}
+	}
+}
@@ -18,21 +18,34 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/minio/cli"
 	"github.com/minio/madmin-go/v2"
 	"github.com/kirolous/mc/pkg/probe"
+	yaml "gopkg.in/yaml.v2"
 )
 
+var batchGenerateFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "interactive, i",
+		Usage: "build the job definition by answering prompts",
+	},
+}
+
 var batchGenerateCmd = cli.Command{
 	Name:         "generate",
 	Usage:        "generate a new batch job definition",
 	Action:       mainBatchGenerate,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
+	Flags:        append(batchGenerateFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -47,12 +60,15 @@ FLAGS:
 EXAMPLES:
   1. Generate a new batch 'replication' job definition:
      {{.Prompt}} {{.HelpName}} myminio replicate > replication.yaml
+
+  2. Build a new batch 'keyrotate' job definition interactively:
+     {{.Prompt}} {{.HelpName}} --interactive myminio keyrotate
 `,
 }
 
 func supportedJobTypes() string {
 	var builder strings.Builder
-	for _, jobType := range madmin.SupportedJobTypes {
+	for _, jobType := range batchJobTypes {
 		builder.WriteString("  - ")
 		builder.WriteString(string(jobType))
 		builder.WriteString("\n")
@@ -80,13 +96,26 @@ func mainBatchGenerate(ctx *cli.Context) error {
 	adminClient, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 
-	switch jobType {
-	case string(madmin.BatchJobReplicate):
-	case string(madmin.BatchJobKeyRotate):
-	default:
+	if !isBatchJobType(jobType) {
 		fatalIf(errInvalidArgument().Trace(jobType), "Unable to generate a job template for the specified job type")
 	}
 
+	if ctx.Bool("interactive") {
+		job, e := promptBatchJob(newBatchJobPrompter(os.Stdin, os.Stderr), madmin.BatchJobType(jobType))
+		fatalIf(probe.NewError(e), "Unable to build %s job", jobType)
+
+		out, e := yaml.Marshal(job)
+		fatalIf(probe.NewError(e), "Unable to generate %s", jobType)
+
+		fmt.Print(string(out))
+		return nil
+	}
+
+	if jobType == string(batchJobExpire) {
+		fmt.Print(batchJobExpireTemplate)
+		return nil
+	}
+
 	out, e := adminClient.GenerateBatchJob(globalContext, madmin.GenerateBatchJobOpts{
 		Type: madmin.BatchJobType(jobType),
 	})
@@ -95,3 +124,215 @@ func mainBatchGenerate(ctx *cli.Context) error {
 	fmt.Println(string(out))
 	return nil
 }
+
+// batchJobPrompter asks the questions of the interactive job builder,
+// prompts are written to w so that stdout only carries the job definition.
+type batchJobPrompter struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func newBatchJobPrompter(r io.Reader, w io.Writer) *batchJobPrompter {
+	return &batchJobPrompter{r: bufio.NewReader(r), w: w}
+}
+
+// ask prompts for a value, an empty answer selects the default value.
+func (p *batchJobPrompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.w, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.w, "%s: ", question)
+	}
+	answer, e := p.r.ReadString('\n')
+	if e != nil && (e != io.EOF || answer == "") {
+		if e == io.EOF {
+			return "", errors.New("input ended before the job definition was complete")
+		}
+		return "", e
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// askRequired prompts until a non empty value is given.
+func (p *batchJobPrompter) askRequired(question string) (string, error) {
+	for {
+		answer, e := p.ask(question, "")
+		if e != nil || answer != "" {
+			return answer, e
+		}
+		fmt.Fprintln(p.w, "A value is required.")
+	}
+}
+
+// askChoice prompts until one of the choices is given.
+func (p *batchJobPrompter) askChoice(question string, choices ...string) (string, error) {
+	for {
+		answer, e := p.ask(fmt.Sprintf("%s (%s)", question, strings.Join(choices, "/")), choices[0])
+		if e != nil {
+			return "", e
+		}
+		for _, c := range choices {
+			if answer == c {
+				return answer, nil
+			}
+		}
+		fmt.Fprintf(p.w, "Please answer one of %s.\n", strings.Join(choices, ", "))
+	}
+}
+
+func (p *batchJobPrompter) askYesNo(question string) (bool, error) {
+	answer, e := p.askChoice(question, "n", "y")
+	return answer == "y", e
+}
+
+func (p *batchJobPrompter) askInt(question string, def int) (int, error) {
+	for {
+		answer, e := p.ask(question, strconv.Itoa(def))
+		if e != nil {
+			return 0, e
+		}
+		if n, e := strconv.Atoi(answer); e == nil && n >= 0 {
+			return n, nil
+		}
+		fmt.Fprintln(p.w, "Please answer with a non negative number.")
+	}
+}
+
+func (p *batchJobPrompter) askReplicateEndpoint(name string) (ep batchJobReplicateEndpoint, e error) {
+	ep.Type = "minio"
+	if ep.Bucket, e = p.askRequired(name + " bucket"); e != nil {
+		return ep, e
+	}
+	if ep.Prefix, e = p.ask(name+" prefix (optional)", ""); e != nil {
+		return ep, e
+	}
+	remote, e := p.askYesNo("Is the " + name + " a remote cluster?")
+	if e != nil || !remote {
+		return ep, e
+	}
+	if ep.Endpoint, e = p.askRequired(name + " endpoint URL"); e != nil {
+		return ep, e
+	}
+	if ep.Credentials.AccessKey, e = p.askRequired(name + " access key"); e != nil {
+		return ep, e
+	}
+	ep.Credentials.SecretKey, e = p.askRequired(name + " secret key")
+	return ep, e
+}
+
+func (p *batchJobPrompter) askFlags(withMetadataFilters bool) (flags batchJobFlags, e error) {
+	if flags.Filter.NewerThan, e = p.ask("Only objects newer than (e.g. 7d, optional)", ""); e != nil {
+		return flags, e
+	}
+	if flags.Filter.OlderThan, e = p.ask("Only objects older than (e.g. 7d, optional)", ""); e != nil {
+		return flags, e
+	}
+	if withMetadataFilters {
+		tag, e := p.ask("Only objects with tag key=value (optional)", "")
+		if e != nil {
+			return flags, e
+		}
+		if k, v, ok := strings.Cut(tag, "="); ok {
+			flags.Filter.Tags = []batchJobKV{{Key: k, Value: v}}
+		}
+	}
+	if flags.Notify.Endpoint, e = p.ask("Notification endpoint (optional)", ""); e != nil {
+		return flags, e
+	}
+	if flags.Retry.Attempts, e = p.askInt("Retry attempts", 10); e != nil {
+		return flags, e
+	}
+	flags.Retry.Delay = "500ms"
+	return flags, nil
+}
+
+// promptBatchJob builds a job definition of the given type from the answers to the prompts.
+func promptBatchJob(p *batchJobPrompter, jobType madmin.BatchJobType) (job batchJobDefinition, e error) {
+	switch jobType {
+	case madmin.BatchJobReplicate:
+		r := &batchJobReplicate{APIVersion: batchJobAPIVersion}
+		if r.Source, e = p.askReplicateEndpoint("Source"); e != nil {
+			return job, e
+		}
+		if r.Target, e = p.askReplicateEndpoint("Target"); e != nil {
+			return job, e
+		}
+		if r.Source.isRemote() && r.Target.isRemote() {
+			return job, errors.New("either source or target must be the local cluster")
+		}
+		if r.Flags, e = p.askFlags(!r.Source.isRemote()); e != nil {
+			return job, e
+		}
+		job.Replicate = r
+	case madmin.BatchJobKeyRotate:
+		k := &batchJobKeyRotate{APIVersion: batchJobAPIVersion}
+		if k.Bucket, e = p.askRequired("Bucket"); e != nil {
+			return job, e
+		}
+		if k.Prefix, e = p.ask("Prefix (optional)", ""); e != nil {
+			return job, e
+		}
+		if k.Encryption.Type, e = p.askChoice("Encryption type", "sse-s3", "sse-kms"); e != nil {
+			return job, e
+		}
+		if k.Encryption.Type == "sse-kms" {
+			if k.Encryption.Key, e = p.askRequired("New KMS key"); e != nil {
+				return job, e
+			}
+		}
+		if k.Flags, e = p.askFlags(true); e != nil {
+			return job, e
+		}
+		job.KeyRotate = k
+	case batchJobExpire:
+		x := &batchJobExpireSpec{APIVersion: batchJobAPIVersion}
+		if x.Bucket, e = p.askRequired("Bucket"); e != nil {
+			return job, e
+		}
+		if x.Prefix, e = p.ask("Prefix (optional)", ""); e != nil {
+			return job, e
+		}
+		for {
+			var rule batchJobExpireRule
+			if rule.Type, e = p.askChoice("Expire", "object", "deleted"); e != nil {
+				return job, e
+			}
+			if rule.Name, e = p.ask("Object name pattern (optional)", ""); e != nil {
+				return job, e
+			}
+			if rule.OlderThan, e = p.ask("Older than (e.g. 30d, optional)", ""); e != nil {
+				return job, e
+			}
+			if rule.Purge.RetainVersions, e = p.askInt("Versions to retain", 0); e != nil {
+				return job, e
+			}
+			x.Rules = append(x.Rules, rule)
+			more, e := p.askYesNo("Add another rule?")
+			if e != nil {
+				return job, e
+			}
+			if !more {
+				break
+			}
+		}
+		if x.Notify.Endpoint, e = p.ask("Notification endpoint (optional)", ""); e != nil {
+			return job, e
+		}
+		if x.Retry.Attempts, e = p.askInt("Retry attempts", 10); e != nil {
+			return job, e
+		}
+		x.Retry.Delay = "500ms"
+		job.Expire = x
+	default:
+		return job, fmt.Errorf("unsupported job type %s", jobType)
+	}
+
+	if errs := job.validate(); len(errs) > 0 {
+		return job, errs[0]
+	}
+	return job, nil
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minio/madmin-go/v2"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// batchJobExpire is not known to madmin yet, servers supporting
+// it accept the job definition as any other batch job.
+const batchJobExpire madmin.BatchJobType = "expire"
+
+// batchJobAPIVersion is the only job definition version understood by the server.
+const batchJobAPIVersion = "v1"
+
+// batchJobTypes - job types the client can generate and validate
+var batchJobTypes = []madmin.BatchJobType{
+	madmin.BatchJobReplicate,
+	madmin.BatchJobKeyRotate,
+	batchJobExpire,
+}
+
+func isBatchJobType(jobType string) bool {
+	for _, t := range batchJobTypes {
+		if string(t) == jobType {
+			return true
+		}
+	}
+	return false
+}
+
+const batchJobExpireTemplate = `expire:
+  apiVersion: v1
+  bucket: BUCKET # Bucket where this job will expire matching objects from
+  prefix: PREFIX # (Optional) Prefix under which this job will expire objects matching the rules below.
+  rules:
+    - type: object # regular objects with zero or more older versions
+      name: NAME # match object names that satisfy the wildcard expression.
+      olderThan: "7d" # match objects older than this value
+      createdBefore: "date" # match objects created before "date"
+      purge:
+        # retainVersions: 0 # (default) delete all versions of the object. This option is the fastest.
+        # retainVersions: 5 # keep the latest 5 versions of the object.
+
+    - type: deleted # objects with delete marker as their latest version
+      name: NAME # match object names that satisfy the wildcard expression.
+      olderThan: "10h" # match objects older than this value
+      createdBefore: "date" # match objects created before "date"
+      purge:
+        # retainVersions: 0 # (default) delete all versions of the object. This option is the fastest.
+        # retainVersions: 5 # keep the latest 5 versions of the object including delete markers.
+
+  notify:
+    endpoint: "https://notify.endpoint" # notification endpoint to receive job completion status
+    token: "Bearer xxxxx" # optional authentication token for the notification endpoint
+
+  retry:
+    attempts: 10 # number of retries for the job before giving up
+    delay: "500ms" # least amount of delay between each retry
+`
+
+type batchJobCredentials struct {
+	AccessKey    string `yaml:"accessKey,omitempty"`
+	SecretKey    string `yaml:"secretKey,omitempty"`
+	SessionToken string `yaml:"sessionToken,omitempty"`
+}
+
+type batchJobKV struct {
+	Key   string `yaml:"key"`
+	Value string `yaml:"value"`
+}
+
+type batchJobFilter struct {
+	NewerThan     string       `yaml:"newerThan,omitempty"`
+	OlderThan     string       `yaml:"olderThan,omitempty"`
+	CreatedAfter  string       `yaml:"createdAfter,omitempty"`
+	CreatedBefore string       `yaml:"createdBefore,omitempty"`
+	Tags          []batchJobKV `yaml:"tags,omitempty"`
+	Metadata      []batchJobKV `yaml:"metadata,omitempty"`
+	KMSKeyID      string       `yaml:"kmskey,omitempty"`
+}
+
+type batchJobNotify struct {
+	Endpoint string `yaml:"endpoint,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+}
+
+type batchJobRetry struct {
+	Attempts int    `yaml:"attempts,omitempty"`
+	Delay    string `yaml:"delay,omitempty"`
+}
+
+type batchJobFlags struct {
+	Filter batchJobFilter `yaml:"filter,omitempty"`
+	Notify batchJobNotify `yaml:"notify,omitempty"`
+	Retry  batchJobRetry  `yaml:"retry,omitempty"`
+}
+
+type batchJobReplicateEndpoint struct {
+	Type        string              `yaml:"type"`
+	Bucket      string              `yaml:"bucket"`
+	Prefix      string              `yaml:"prefix,omitempty"`
+	Endpoint    string              `yaml:"endpoint,omitempty"`
+	Path        string              `yaml:"path,omitempty"`
+	Credentials batchJobCredentials `yaml:"credentials,omitempty"`
+}
+
+func (e batchJobReplicateEndpoint) isRemote() bool {
+	return e.Endpoint != ""
+}
+
+type batchJobReplicate struct {
+	APIVersion string                    `yaml:"apiVersion"`
+	Source     batchJobReplicateEndpoint `yaml:"source"`
+	Target     batchJobReplicateEndpoint `yaml:"target"`
+	Flags      batchJobFlags             `yaml:"flags,omitempty"`
+}
+
+type batchJobEncryption struct {
+	Type    string `yaml:"type"`
+	Key     string `yaml:"key,omitempty"`
+	Context string `yaml:"context,omitempty"`
+}
+
+type batchJobKeyRotate struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Bucket     string             `yaml:"bucket"`
+	Prefix     string             `yaml:"prefix,omitempty"`
+	Encryption batchJobEncryption `yaml:"encryption"`
+	Flags      batchJobFlags      `yaml:"flags,omitempty"`
+}
+
+type batchJobExpirePurge struct {
+	RetainVersions int `yaml:"retainVersions"`
+}
+
+type batchJobExpireRule struct {
+	Type          string              `yaml:"type"`
+	Name          string              `yaml:"name,omitempty"`
+	OlderThan     string              `yaml:"olderThan,omitempty"`
+	CreatedBefore string              `yaml:"createdBefore,omitempty"`
+	Tags          []batchJobKV        `yaml:"tags,omitempty"`
+	Metadata      []batchJobKV        `yaml:"metadata,omitempty"`
+	Purge         batchJobExpirePurge `yaml:"purge,omitempty"`
+}
+
+type batchJobExpireSpec struct {
+	APIVersion string               `yaml:"apiVersion"`
+	Bucket     string               `yaml:"bucket"`
+	Prefix     string               `yaml:"prefix,omitempty"`
+	Rules      []batchJobExpireRule `yaml:"rules"`
+	Notify     batchJobNotify       `yaml:"notify,omitempty"`
+	Retry      batchJobRetry        `yaml:"retry,omitempty"`
+}
+
+// batchJobDefinition - client side schema of a batch job definition,
+// exactly one of the job types is expected to be set.
+type batchJobDefinition struct {
+	Replicate *batchJobReplicate  `yaml:"replicate,omitempty"`
+	KeyRotate *batchJobKeyRotate  `yaml:"keyrotate,omitempty"`
+	Expire    *batchJobExpireSpec `yaml:"expire,omitempty"`
+}
+
+// Type returns the job type of the definition.
+func (j batchJobDefinition) Type() madmin.BatchJobType {
+	switch {
+	case j.Replicate != nil:
+		return madmin.BatchJobReplicate
+	case j.KeyRotate != nil:
+		return madmin.BatchJobKeyRotate
+	case j.Expire != nil:
+		return batchJobExpire
+	}
+	return ""
+}
+
+// parseBatchJobDefinition parses a job definition, unknown fields are rejected.
+func parseBatchJobDefinition(buf []byte) (batchJobDefinition, error) {
+	var job batchJobDefinition
+	if e := yaml.UnmarshalStrict(buf, &job); e != nil {
+		return job, e
+	}
+	n := 0
+	for _, set := range []bool{job.Replicate != nil, job.KeyRotate != nil, job.Expire != nil} {
+		if set {
+			n++
+		}
+	}
+	switch n {
+	case 0:
+		return job, errors.New("no job found, expected one of 'replicate', 'keyrotate' or 'expire'")
+	case 1:
+		return job, nil
+	}
+	return job, errors.New("only one job can be defined per file")
+}
+
+// validateBatchDuration validates durations such as "7d10h31s"
+func validateBatchDuration(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, e := ParseDuration(value); e != nil {
+		return fmt.Errorf("%s: invalid duration '%s'", field, value)
+	}
+	return nil
+}
+
+func validateBatchDate(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, e := time.Parse(time.RFC3339, value); e != nil {
+		return fmt.Errorf("%s: invalid date '%s', expected RFC3339 format such as 2006-01-02T15:04:05Z", field, value)
+	}
+	return nil
+}
+
+func validateBatchFlags(prefix string, flags batchJobFlags) (errs []error) {
+	for _, e := range []error{
+		validateBatchDuration(prefix+"filter.newerThan", flags.Filter.NewerThan),
+		validateBatchDuration(prefix+"filter.olderThan", flags.Filter.OlderThan),
+		validateBatchDate(prefix+"filter.createdAfter", flags.Filter.CreatedAfter),
+		validateBatchDate(prefix+"filter.createdBefore", flags.Filter.CreatedBefore),
+		validateBatchRetry(prefix+"retry", flags.Retry),
+	} {
+		if e != nil {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+func validateBatchRetry(field string, retry batchJobRetry) error {
+	if retry.Attempts < 0 {
+		return fmt.Errorf("%s.attempts: must not be negative", field)
+	}
+	if retry.Delay == "" {
+		return nil
+	}
+	if _, e := time.ParseDuration(retry.Delay); e != nil {
+		return fmt.Errorf("%s.delay: invalid duration '%s'", field, retry.Delay)
+	}
+	return nil
+}
+
+// validate checks the definition against the job schema, all problems found are returned.
+func (j batchJobDefinition) validate() (errs []error) {
+	required := func(field, value string) {
+		switch value {
+		case "", "BUCKET", "ACCESS-KEY", "SECRET-KEY":
+			// empty or left as a template placeholder
+			errs = append(errs, fmt.Errorf("%s: value is required", field))
+		}
+	}
+	apiVersion := func(field, value string) {
+		if value != batchJobAPIVersion {
+			errs = append(errs, fmt.Errorf("%s: unsupported version '%s', expected '%s'", field, value, batchJobAPIVersion))
+		}
+	}
+
+	switch {
+	case j.Replicate != nil:
+		r := j.Replicate
+		apiVersion("replicate.apiVersion", r.APIVersion)
+		for i, ep := range []batchJobReplicateEndpoint{r.Source, r.Target} {
+			field := "replicate.source"
+			if i == 1 {
+				field = "replicate.target"
+			}
+			if ep.Type != "minio" && ep.Type != "" {
+				errs = append(errs, fmt.Errorf("%s.type: unsupported type '%s', valid values are 'minio'", field, ep.Type))
+			}
+			required(field+".bucket", ep.Bucket)
+			if ep.isRemote() {
+				required(field+".credentials.accessKey", ep.Credentials.AccessKey)
+				required(field+".credentials.secretKey", ep.Credentials.SecretKey)
+			}
+		}
+		if r.Source.isRemote() && r.Target.isRemote() {
+			errs = append(errs, errors.New("replicate: either source or target must be local, both have an endpoint"))
+		}
+		errs = append(errs, validateBatchFlags("replicate.flags.", r.Flags)...)
+	case j.KeyRotate != nil:
+		k := j.KeyRotate
+		apiVersion("keyrotate.apiVersion", k.APIVersion)
+		required("keyrotate.bucket", k.Bucket)
+		switch k.Encryption.Type {
+		case "sse-s3":
+		case "sse-kms":
+			required("keyrotate.encryption.key", k.Encryption.Key)
+		default:
+			errs = append(errs, fmt.Errorf("keyrotate.encryption.type: unsupported type '%s', valid values are 'sse-s3' and 'sse-kms'", k.Encryption.Type))
+		}
+		errs = append(errs, validateBatchFlags("keyrotate.flags.", k.Flags)...)
+	case j.Expire != nil:
+		x := j.Expire
+		apiVersion("expire.apiVersion", x.APIVersion)
+		required("expire.bucket", x.Bucket)
+		if len(x.Rules) == 0 {
+			errs = append(errs, errors.New("expire.rules: at least one rule is required"))
+		}
+		for i, rule := range x.Rules {
+			field := fmt.Sprintf("expire.rules[%d]", i)
+			if rule.Type != "object" && rule.Type != "deleted" {
+				errs = append(errs, fmt.Errorf("%s.type: unsupported type '%s', valid values are 'object' and 'deleted'", field, rule.Type))
+			}
+			if e := validateBatchDuration(field+".olderThan", rule.OlderThan); e != nil {
+				errs = append(errs, e)
+			}
+			if e := validateBatchDate(field+".createdBefore", rule.CreatedBefore); e != nil {
+				errs = append(errs, e)
+			}
+			if rule.Purge.RetainVersions < 0 {
+				errs = append(errs, fmt.Errorf("%s.purge.retainVersions: must not be negative", field))
+			}
+		}
+		if e := validateBatchRetry("expire.retry", x.Retry); e != nil {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestParseBatchJobDefinition(t *testing.T) {
+	testCases := []struct {
+		job      string
+		jobType  madmin.BatchJobType
+		parseErr bool
+		errs     int
+	}{
+		{
+			job: `keyrotate:
+  apiVersion: v1
+  bucket: mybucket
+  encryption:
+    type: sse-s3
+`,
+			jobType: madmin.BatchJobKeyRotate,
+		},
+		{
+			job: `keyrotate:
+  apiVersion: v2
+  bucket: BUCKET
+  encryption:
+    type: sse-kms
+`,
+			jobType: madmin.BatchJobKeyRotate,
+			errs:    3,
+		},
+		{
+			job: `replicate:
+  apiVersion: v1
+  source:
+    type: minio
+    bucket: src
+    endpoint: https://remote:9000
+  target:
+    type: minio
+    bucket: dst
+  flags:
+    filter:
+      olderThan: "7x"
+`,
+			jobType: madmin.BatchJobReplicate,
+			errs:    3,
+		},
+		{
+			job:     batchJobExpireTemplate,
+			jobType: batchJobExpire,
+			errs:    3,
+		},
+		{
+			job: `keyrotate:
+  apiVersion: v1
+  bucket: mybucket
+  unknown: field
+`,
+			parseErr: true,
+		},
+		{
+			job:      `apiVersion: v1`,
+			parseErr: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		job, e := parseBatchJobDefinition([]byte(testCase.job))
+		if testCase.parseErr {
+			if e == nil {
+				t.Errorf("Test %d: expected a parse error", i+1)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("Test %d: unexpected parse error %v", i+1, e)
+			continue
+		}
+		if job.Type() != testCase.jobType {
+			t.Errorf("Test %d: expected job type %s, got %s", i+1, testCase.jobType, job.Type())
+		}
+		if errs := job.validate(); len(errs) != testCase.errs {
+			t.Errorf("Test %d: expected %d validation errors, got %v", i+1, testCase.errs, errs)
+		}
+	}
+}
+
+func TestPromptBatchJob(t *testing.T) {
+	answers := strings.Join([]string{
+		"mybucket", // bucket
+		"",         // prefix
+		"sse-kms",  // encryption type
+		"my-key",   // kms key
+		"",         // newer than
+		"30d",      // older than
+		"",         // tag filter
+		"",         // notification endpoint
+		"",         // retry attempts
+	}, "\n") + "\n"
+
+	job, e := promptBatchJob(newBatchJobPrompter(strings.NewReader(answers), io.Discard), madmin.BatchJobKeyRotate)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	out, e := yaml.Marshal(job)
+	if e != nil {
+		t.Fatal(e)
+	}
+	parsed, e := parseBatchJobDefinition(out)
+	if e != nil {
+		t.Fatalf("generated job definition does not parse: %v\n%s", e, out)
+	}
+	if errs := parsed.validate(); len(errs) > 0 {
+		t.Fatalf("generated job definition is invalid: %v", errs)
+	}
+	if parsed.KeyRotate.Encryption.Key != "my-key" || parsed.KeyRotate.Flags.Filter.OlderThan != "30d" || parsed.KeyRotate.Flags.Retry.Attempts != 10 {
+		t.Fatalf("unexpected job definition\n%s", out)
+	}
+
+	if _, e = promptBatchJob(newBatchJobPrompter(strings.NewReader("mybucket\n"), io.Discard), madmin.BatchJobKeyRotate); e == nil {
+		t.Fatal("expected an error on incomplete input")
+	}
+}
@@ -21,12 +21,15 @@ import "github.com/minio/cli"
 
 var batchSubcommands = []cli.Command{
 	batchGenerateCmd,
+	batchValidateCmd,
 	batchStartCmd,
 	batchListCmd,
 	batchStatusCmd,
+	batchFailuresCmd,
 	batchDescribeCmd,
 	// batchSuspendResumeCmd,
 	batchCancelCmd,
+	batchScheduleCmd,
 }
 
 var batchCmd = cli.Command{
@@ -0,0 +1,148 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var batchScheduleAddFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "every",
+		Usage: "interval between two runs of the job, e.g. 12h, 7d",
+	},
+}
+
+var batchScheduleAddCmd = cli.Command{
+	Name:         "add",
+	Usage:        "start a batch job at a regular interval",
+	Action:       mainBatchScheduleAdd,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(batchScheduleAddFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} --every INTERVAL TARGET JOBFILE
+
+  Schedules are kept in the mc configuration folder and started by
+  'mc batch schedule run', which must be kept running.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Rotate the encryption keys of the objects in a bucket every day:
+     {{.Prompt}} {{.HelpName}} --every 24h myminio ./keyrotate.yaml
+
+  2. Run an expiry sweep every week:
+     {{.Prompt}} {{.HelpName}} --every 7d myminio ./expire.yaml
+`,
+}
+
+// batchScheduleMessage container for batch schedule messages
+type batchScheduleMessage struct {
+	op       string
+	Status   string          `json:"status"`
+	Schedule batchScheduleV1 `json:"schedule"`
+	NextRun  time.Time       `json:"nextRun"`
+}
+
+// String colorized batch schedule message
+func (m batchScheduleMessage) String() string {
+	switch m.op {
+	case "remove":
+		return console.Colorize("BatchSchedule", fmt.Sprintf("Removed schedule `%s` of '%s' on '%s'", m.Schedule.ID, m.Schedule.JobFile, m.Schedule.Alias))
+	case "list":
+		lastRun := "never"
+		if !m.Schedule.LastRun.IsZero() {
+			lastRun = m.Schedule.LastRun.Local().Format(printDate)
+			if m.Schedule.LastError != "" {
+				lastRun += " (failed: " + m.Schedule.LastError + ")"
+			} else if m.Schedule.LastJobID != "" {
+				lastRun += " (job " + m.Schedule.LastJobID + ")"
+			}
+		}
+		return fmt.Sprintf("%s  %-10s %-10s every %-10s next: %s  last: %s  %s",
+			console.Colorize("BatchScheduleID", m.Schedule.ID), m.Schedule.Alias, m.Schedule.JobType,
+			m.Schedule.Every, m.NextRun.Local().Format(printDate), lastRun, m.Schedule.JobFile)
+	}
+	return console.Colorize("BatchSchedule", fmt.Sprintf("Scheduled '%s' job `%s` on '%s' every %s, run 'mc batch schedule run' to start it",
+		m.Schedule.JobType, m.Schedule.ID, m.Schedule.Alias, m.Schedule.Every))
+}
+
+// JSON jsonified batch schedule message
+func (m batchScheduleMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+func setBatchScheduleColors() {
+	console.SetColor("BatchSchedule", color.New(color.FgGreen, color.Bold))
+	console.SetColor("BatchScheduleID", color.New(color.FgCyan, color.Bold))
+	console.SetColor("BatchScheduleError", color.New(color.FgRed, color.Bold))
+}
+
+// mainBatchScheduleAdd is the handle for "mc batch schedule add" command.
+func mainBatchScheduleAdd(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 || !ctx.IsSet("every") {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setBatchScheduleColors()
+
+	args := ctx.Args()
+	aliasedURL := args.Get(0)
+	jobFile := args.Get(1)
+
+	every, e := ParseDuration(ctx.String("every"))
+	fatalIf(probe.NewError(e).Trace(ctx.String("every")), "Invalid --every interval")
+	if every < Minute {
+		fatal(errInvalidArgument().Trace(ctx.String("every")), "The interval must be at least one minute")
+	}
+
+	// Make sure the alias exists
+	_, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	buf, e := os.ReadFile(jobFile)
+	fatalIf(probe.NewError(e), "Unable to read %s", jobFile)
+
+	job, e := parseBatchJobDefinition(buf)
+	fatalIf(probe.NewError(e), "Unable to parse %s", jobFile)
+	if errs := job.validate(); len(errs) > 0 {
+		fatal(probe.NewError(errs[0]), "Invalid job definition, run 'mc batch validate' for details")
+	}
+
+	alias, _ := url2Alias(aliasedURL)
+	var s batchScheduleV1
+	err = updateBatchSchedules(func(db *batchScheduleDBV1) *probe.Error {
+		s = db.Add(alias, jobFile, string(job.Type()), string(buf), time.Duration(every))
+		return nil
+	})
+	fatalIf(err, "Unable to save batch schedules.")
+
+	printMsg(batchScheduleMessage{Schedule: s, NextRun: s.NextRun()})
+	return nil
+}
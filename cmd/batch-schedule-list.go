@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var batchScheduleListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list recurring batch jobs",
+	Action:       mainBatchScheduleList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [TARGET]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all scheduled batch jobs:
+     {{.Prompt}} {{.HelpName}}
+
+  2. List the batch jobs scheduled on 'myminio':
+     {{.Prompt}} {{.HelpName}} myminio
+`,
+}
+
+// mainBatchScheduleList is the handle for "mc batch schedule list" command.
+func mainBatchScheduleList(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setBatchScheduleColors()
+
+	var alias string
+	if len(ctx.Args()) == 1 {
+		alias, _ = url2Alias(ctx.Args().Get(0))
+	}
+
+	db, err := loadBatchSchedules()
+	fatalIf(err, "Unable to load batch schedules.")
+
+	schedules := db.Sorted()
+	if len(schedules) == 0 && !globalJSON {
+		console.Infoln("No batch jobs scheduled.")
+		return nil
+	}
+	for _, s := range schedules {
+		if alias != "" && s.Alias != alias {
+			continue
+		}
+		printMsg(batchScheduleMessage{op: "list", Schedule: s, NextRun: s.NextRun()})
+	}
+	return nil
+}
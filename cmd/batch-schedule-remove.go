@@ -0,0 +1,73 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+)
+
+var batchScheduleRemoveCmd = cli.Command{
+	Name:         "remove",
+	ShortName:    "rm",
+	Usage:        "remove a recurring batch job",
+	Action:       mainBatchScheduleRemove,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} SCHEDULEID
+
+  Jobs already started by the schedule are not cancelled, use 'mc batch cancel' for those.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove the schedule '8f2b1a3c':
+     {{.Prompt}} {{.HelpName}} 8f2b1a3c
+`,
+}
+
+// mainBatchScheduleRemove is the handle for "mc batch schedule remove" command.
+func mainBatchScheduleRemove(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setBatchScheduleColors()
+
+	id := ctx.Args().Get(0)
+
+	var s batchScheduleV1
+	var found bool
+	err := updateBatchSchedules(func(db *batchScheduleDBV1) *probe.Error {
+		if s, found = db.Schedules[id]; found {
+			delete(db.Schedules, id)
+		}
+		return nil
+	})
+	fatalIf(err, "Unable to save batch schedules.")
+	if !found {
+		fatal(errDummy().Trace(id), "No batch job scheduled with ID `"+id+"`.")
+	}
+
+	printMsg(batchScheduleMessage{op: "remove", Schedule: s, NextRun: s.NextRun()})
+	return nil
+}
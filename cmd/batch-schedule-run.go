@@ -0,0 +1,171 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var batchScheduleRunFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "once",
+		Usage: "start the jobs currently due and exit",
+	},
+}
+
+var batchScheduleRunCmd = cli.Command{
+	Name:         "run",
+	Usage:        "start scheduled batch jobs when they are due",
+	Action:       mainBatchScheduleRun,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(batchScheduleRunFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+
+  Runs in the foreground and starts every scheduled job when it is due,
+  e.g. as a system service. A job missed while not running is started once
+  as soon as the scheduler runs again.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Run the scheduler:
+     {{.Prompt}} {{.HelpName}}
+
+  2. Start all jobs which are currently due, then exit:
+     {{.Prompt}} {{.HelpName}} --once
+`,
+}
+
+// batchScheduleRunMessage - outcome of a scheduled job start
+type batchScheduleRunMessage struct {
+	Status     string `json:"status"`
+	ScheduleID string `json:"scheduleID"`
+	Alias      string `json:"alias"`
+	JobID      string `json:"jobID,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (m batchScheduleRunMessage) String() string {
+	if m.Error != "" {
+		return console.Colorize("BatchScheduleError", fmt.Sprintf("Unable to start scheduled job `%s` on '%s': %s", m.ScheduleID, m.Alias, m.Error))
+	}
+	return console.Colorize("BatchSchedule", fmt.Sprintf("Started job `%s` on '%s' for schedule `%s`", m.JobID, m.Alias, m.ScheduleID))
+}
+
+func (m batchScheduleRunMessage) JSON() string {
+	m.Status = "success"
+	if m.Error != "" {
+		m.Status = "error"
+	}
+	return toJSON(m)
+}
+
+// claimDueBatchSchedules marks the schedules due at the given time as run
+// while the schedules file is locked, so that concurrent schedulers start
+// every job only once. It returns the claimed schedules.
+func claimDueBatchSchedules(now time.Time) (claimed []batchScheduleV1, err *probe.Error) {
+	err = updateBatchSchedules(func(db *batchScheduleDBV1) *probe.Error {
+		for _, s := range db.Due(now) {
+			s.LastRun, s.LastJobID, s.LastError = now, "", ""
+			db.Update(s)
+			claimed = append(claimed, s)
+		}
+		return nil
+	})
+	return claimed, err
+}
+
+// runDueBatchSchedules starts all jobs due at the given time and persists the outcome.
+// It returns the time the next job is due.
+func runDueBatchSchedules(now time.Time) time.Time {
+	claimed, err := claimDueBatchSchedules(now)
+	fatalIf(err, "Unable to claim the due batch schedules.")
+
+	for i, s := range claimed {
+		msg := batchScheduleRunMessage{ScheduleID: s.ID, Alias: s.Alias}
+
+		client, err := newAdminClient(s.Alias)
+		if err == nil {
+			res, e := client.StartBatchJob(globalContext, s.Job)
+			err = probe.NewError(e)
+			if e == nil {
+				err = nil
+				claimed[i].LastJobID = res.ID
+			}
+		}
+		if err != nil {
+			claimed[i].LastError = err.ToGoError().Error()
+		}
+		msg.JobID, msg.Error = claimed[i].LastJobID, claimed[i].LastError
+		printMsg(msg)
+	}
+
+	// The schedules added or removed meanwhile are kept as they are on disk.
+	var db *batchScheduleDBV1
+	err = updateBatchSchedules(func(disk *batchScheduleDBV1) *probe.Error {
+		for _, s := range claimed {
+			disk.Update(s)
+		}
+		db = disk
+		return nil
+	})
+	fatalIf(err, "Unable to save batch schedules.")
+
+	var next time.Time
+	for _, s := range db.Sorted() {
+		next = s.NextRun()
+		break
+	}
+	return next
+}
+
+// mainBatchScheduleRun is the handle for "mc batch schedule run" command.
+func mainBatchScheduleRun(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setBatchScheduleColors()
+
+	const maxWait = time.Minute // pick up schedules added while running
+	for {
+		next := runDueBatchSchedules(UTCNow())
+		if ctx.Bool("once") {
+			return nil
+		}
+
+		wait := maxWait
+		if !next.IsZero() && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		select {
+		case <-globalContext.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/quick"
+)
+
+const batchSchedulesFile = "batch-schedules.json"
+
+var batchScheduleSubcommands = []cli.Command{
+	batchScheduleAddCmd,
+	batchScheduleListCmd,
+	batchScheduleRemoveCmd,
+	batchScheduleRunCmd,
+}
+
+var batchScheduleCmd = cli.Command{
+	Name:            "schedule",
+	Usage:           "manage recurring batch jobs",
+	Action:          mainBatchSchedule,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     batchScheduleSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainBatchSchedule is the handle for "mc batch schedule" command.
+func mainBatchSchedule(ctx *cli.Context) error {
+	commandNotFound(ctx, batchScheduleSubcommands)
+	return nil
+	// Sub-commands like "add", "list" have their own main.
+}
+
+// batchScheduleV1 - a batch job started by the client at a fixed interval.
+type batchScheduleV1 struct {
+	ID        string        `json:"id"`
+	Alias     string        `json:"alias"`
+	JobFile   string        `json:"jobFile"`
+	JobType   string        `json:"jobType"`
+	Job       string        `json:"job"` // job definition as of when the schedule was added
+	Every     time.Duration `json:"every"`
+	Created   time.Time     `json:"created"`
+	LastRun   time.Time     `json:"lastRun,omitempty"`
+	LastJobID string        `json:"lastJobID,omitempty"`
+	LastError string        `json:"lastError,omitempty"`
+}
+
+// NextRun returns when the job is due next, a new schedule is due immediately.
+func (s batchScheduleV1) NextRun() time.Time {
+	if s.LastRun.IsZero() {
+		return s.Created
+	}
+	return s.LastRun.Add(s.Every)
+}
+
+// JSON file persisting the client side batch job schedules.
+type batchScheduleDBV1 struct {
+	Version string `json:"version"`
+	mutex   *sync.Mutex
+
+	// key is the schedule ID.
+	Schedules map[string]batchScheduleV1 `json:"schedules"`
+}
+
+func newBatchScheduleDBV1() *batchScheduleDBV1 {
+	return &batchScheduleDBV1{
+		Version:   "1",
+		Schedules: make(map[string]batchScheduleV1),
+		mutex:     &sync.Mutex{},
+	}
+}
+
+func getBatchSchedulesFile() string {
+	return filepath.Join(mustGetMcConfigDir(), batchSchedulesFile)
+}
+
+// loadBatchSchedules loads the schedules from disk, a missing file is an empty schedule list.
+func loadBatchSchedules() (*batchScheduleDBV1, *probe.Error) {
+	db := newBatchScheduleDBV1()
+	filename := getBatchSchedulesFile()
+	if _, e := os.Stat(filename); os.IsNotExist(e) {
+		return db, nil
+	}
+
+	qs, e := quick.NewConfig(newBatchScheduleDBV1(), nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Load(filename); e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	for k, v := range qs.Data().(*batchScheduleDBV1).Schedules {
+		db.Schedules[k] = v
+	}
+	return db, nil
+}
+
+// Save persists the schedules to disk.
+func (db *batchScheduleDBV1) Save() *probe.Error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	filename := getBatchSchedulesFile()
+	qs, e := quick.NewConfig(db, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Save(filename); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	return nil
+}
+
+// updateBatchSchedules reloads the schedules, applies fn and saves them while
+// the file is locked, so that the changes of concurrent runs of mc are kept.
+func updateBatchSchedules(fn func(db *batchScheduleDBV1) *probe.Error) *probe.Error {
+	filename := getBatchSchedulesFile()
+	if e := os.MkdirAll(filepath.Dir(filename), 0o700); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	unlock, err := lockConfigFile(filename)
+	if err != nil {
+		return err.Trace(filename)
+	}
+	defer unlock()
+
+	db, err := loadBatchSchedules()
+	if err != nil {
+		return err
+	}
+	if err = fn(db); err != nil {
+		return err
+	}
+	return db.Save()
+}
+
+// Add registers a new schedule and returns it.
+func (db *batchScheduleDBV1) Add(alias, jobFile, jobType, job string, every time.Duration) batchScheduleV1 {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	s := batchScheduleV1{
+		ID:      strings.Split(uuid.NewString(), "-")[0],
+		Alias:   alias,
+		JobFile: jobFile,
+		JobType: jobType,
+		Job:     job,
+		Every:   every,
+		Created: UTCNow(),
+	}
+	db.Schedules[s.ID] = s
+	return s
+}
+
+// Sorted returns the schedules ordered by their next run.
+func (db *batchScheduleDBV1) Sorted() []batchScheduleV1 {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	schedules := make([]batchScheduleV1, 0, len(db.Schedules))
+	for _, s := range db.Schedules {
+		schedules = append(schedules, s)
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		if !schedules[i].NextRun().Equal(schedules[j].NextRun()) {
+			return schedules[i].NextRun().Before(schedules[j].NextRun())
+		}
+		return schedules[i].ID < schedules[j].ID
+	})
+	return schedules
+}
+
+// Due returns the schedules due at the given time.
+func (db *batchScheduleDBV1) Due(now time.Time) (due []batchScheduleV1) {
+	for _, s := range db.Sorted() {
+		if !s.NextRun().After(now) {
+			due = append(due, s)
+		}
+	}
+	return due
+}
+
+// Update stores the outcome of a schedule run.
+func (db *batchScheduleDBV1) Update(s batchScheduleV1) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+
+	if _, ok := db.Schedules[s.ID]; ok {
+		db.Schedules[s.ID] = s
+	}
+}
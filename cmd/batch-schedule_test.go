@@ -0,0 +1,150 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestBatchScheduleDue(t *testing.T) {
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db := newBatchScheduleDBV1()
+	db.Schedules["new"] = batchScheduleV1{ID: "new", Every: time.Hour, Created: created}
+	db.Schedules["ran"] = batchScheduleV1{ID: "ran", Every: time.Hour, Created: created, LastRun: created.Add(30 * time.Minute)}
+	db.Schedules["later"] = batchScheduleV1{ID: "later", Every: 24 * time.Hour, Created: created, LastRun: created}
+
+	testCases := []struct {
+		now time.Time
+		due []string
+	}{
+		{created, []string{"new"}},
+		{created.Add(90 * time.Minute), []string{"new", "ran"}},
+		{created.Add(24 * time.Hour), []string{"new", "ran", "later"}},
+	}
+	for i, testCase := range testCases {
+		var due []string
+		for _, s := range db.Due(testCase.now) {
+			due = append(due, s.ID)
+		}
+		if len(due) != len(testCase.due) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.due, due)
+		}
+		for j := range due {
+			if due[j] != testCase.due[j] {
+				t.Errorf("Test %d: expected %v, got %v", i+1, testCase.due, due)
+			}
+		}
+	}
+	if next := db.Schedules["ran"].NextRun(); !next.Equal(created.Add(90 * time.Minute)) {
+		t.Errorf("unexpected next run %s", next)
+	}
+}
+
+func TestUpdateBatchSchedules(t *testing.T) {
+	defer func(dir, profile string) {
+		setMcConfigDir(dir)
+		setMcConfigProfile(profile)
+	}(mcCustomConfigDir, mcConfigProfile)
+	setMcConfigDir(t.TempDir())
+	setMcConfigProfile("")
+
+	var a, b batchScheduleV1
+	add := func(s *batchScheduleV1) {
+		if err := updateBatchSchedules(func(db *batchScheduleDBV1) *probe.Error {
+			*s = db.Add("myminio", "job.yaml", "replicate", "job", time.Hour)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(&a)
+
+	// A run loaded the schedules before b was added and a removed.
+	running, err := loadBatchSchedules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	add(&b)
+	if err = updateBatchSchedules(func(db *batchScheduleDBV1) *probe.Error {
+		delete(db.Schedules, a.ID)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ran := running.Schedules[a.ID]
+	ran.LastRun = time.Now()
+	if err = updateBatchSchedules(func(db *batchScheduleDBV1) *probe.Error {
+		db.Update(ran)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := loadBatchSchedules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := db.Schedules[a.ID]; ok {
+		t.Error("expected the removed schedule to stay removed")
+	}
+	if _, ok := db.Schedules[b.ID]; !ok {
+		t.Error("expected the added schedule to be kept")
+	}
+}
+
+func TestClaimDueBatchSchedules(t *testing.T) {
+	defer func(dir, profile string) {
+		setMcConfigDir(dir)
+		setMcConfigProfile(profile)
+	}(mcCustomConfigDir, mcConfigProfile)
+	setMcConfigDir(t.TempDir())
+	setMcConfigProfile("")
+
+	var s batchScheduleV1
+	if err := updateBatchSchedules(func(db *batchScheduleDBV1) *probe.Error {
+		s = db.Add("myminio", "job.yaml", "replicate", "job", time.Hour)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := s.Created.Add(time.Minute)
+	testCases := []struct {
+		now     time.Time
+		claimed int
+	}{
+		{now, 1},
+		// Another scheduler finds the schedule already claimed.
+		{now, 0},
+		{now.Add(30 * time.Minute), 0},
+		{now.Add(time.Hour), 1},
+	}
+	for i, testCase := range testCases {
+		claimed, err := claimDueBatchSchedules(testCase.now)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if len(claimed) != testCase.claimed {
+			t.Errorf("Test %d: expected %d claimed schedules, got %d", i+1, testCase.claimed, len(claimed))
+		}
+	}
+}
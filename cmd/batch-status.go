@@ -17,13 +17,22 @@ import (
 	"github.com/olekukonko/tablewriter"
 )
 
+var batchStatusFlags = []cli.Flag{
+	cli.DurationFlag{
+		Name:  "interval",
+		Usage: "interval between the refreshes of the job status",
+		Value: time.Second,
+	},
+	notifyOnCompletionFlag,
+}
+
 var batchStatusCmd = cli.Command{
 	Name:            "status",
 	Usage:           "summarize job events on MinIO server in real-time",
 	Action:          mainBatchStatus,
 	OnUsageError:    onUsageError,
 	Before:          setGlobalsFromContext,
-	Flags:           globalFlags,
+	Flags:           append(batchStatusFlags, globalFlags...),
 	HideHelpCommand: true,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
@@ -31,12 +40,21 @@ var batchStatusCmd = cli.Command{
 USAGE:
   {{.HelpName}} TARGET JOBID
 
+  The status is refreshed in place until the job completes or fails,
+  with the throughput of the job and the estimated time to completion.
+
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 EXAMPLES:
-   1. Display current in-progress JOB events.
+   1. Follow the progress of a JOB until it completes.
       {{.Prompt}} {{.HelpName}} myminio/ KwSysDpxcBU9FNhGkn2dCf
+
+   2. Follow the progress of a JOB, refreshing every 5 seconds.
+      {{.Prompt}} {{.HelpName}} --interval 5s myminio/ KwSysDpxcBU9FNhGkn2dCf
+
+   3. Follow a JOB and show a desktop notification when it completes or fails.
+      {{.Prompt}} {{.HelpName}} --notify-on-completion desktop myminio/ KwSysDpxcBU9FNhGkn2dCf
 `,
 }
 
@@ -49,6 +67,7 @@ func checkBatchStatusSyntax(ctx *cli.Context) {
 
 func mainBatchStatus(ctx *cli.Context) error {
 	checkBatchStatusSyntax(ctx)
+	enableCompletionNotify(ctx)
 
 	aliasedURL := ctx.Args().Get(0)
 	jobID := ctx.Args().Get(1)
@@ -60,24 +79,34 @@ func mainBatchStatus(ctx *cli.Context) error {
 	ctxt, cancel := context.WithCancel(globalContext)
 	defer cancel()
 
-	_, e := client.DescribeBatchJob(ctxt, jobID)
+	desc, e := client.DescribeBatchJob(ctxt, jobID)
 	fatalIf(probe.NewError(e), "Unable to lookup job status")
 
-	ui := tea.NewProgram(initBatchJobMetricsUI(jobID))
+	// the total is only an estimate used for the ETA, ignore failures
+	total := estimateBatchJobObjects(ctxt, client, desc)
+
+	ui := tea.NewProgram(initBatchJobMetricsUI(jobID, total))
 	go func() {
 		opts := madmin.MetricsOptions{
-			Type:    madmin.MetricsBatchJobs,
-			ByJobID: jobID,
+			Type:     madmin.MetricsBatchJobs,
+			ByJobID:  jobID,
+			Interval: ctx.Duration("interval"),
 		}
 		e := client.Metrics(ctxt, opts, func(metrics madmin.RealtimeMetrics) {
 			if globalJSON {
 				printMsg(metricsMessage{RealtimeMetrics: metrics})
-				return
 			}
 			if metrics.Aggregated.BatchJobs != nil {
 				job := metrics.Aggregated.BatchJobs.Jobs[jobID]
-				ui.Send(job)
-				if job.Complete {
+				if !globalJSON {
+					ui.Send(job)
+				}
+				if job.Complete || job.Failed {
+					var bytes int64
+					if job.Replicate != nil {
+						bytes = job.Replicate.BytesTransferred
+					}
+					setNotifyOutcome(getBatchJobProgress(job, total).Objects, bytes, job.Failed)
 					cancel()
 				}
 			}
@@ -85,6 +114,11 @@ func mainBatchStatus(ctx *cli.Context) error {
 		if e != nil && !errors.Is(e, context.Canceled) {
 			fatalIf(probe.NewError(e).Trace(ctx.Args()...), "Unable to get current batch status")
 		}
+		// The server ended the metrics, there is nothing left to show.
+		cancel()
+		if !globalJSON {
+			ui.Quit()
+		}
 	}()
 
 	if !globalJSON {
@@ -92,18 +126,89 @@ func mainBatchStatus(ctx *cli.Context) error {
 			cancel()
 			fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to get current batch status")
 		}
+	} else {
+		<-ctxt.Done()
 	}
 
 	return nil
 }
 
-func initBatchJobMetricsUI(jobID string) *batchJobMetricsUI {
+// estimateBatchJobObjects returns the number of objects in the bucket the job
+// operates on, or 0 if it cannot be determined. When the job is limited to a
+// prefix or filters objects, this is an upper bound.
+func estimateBatchJobObjects(ctx context.Context, client *madmin.AdminClient, desc string) int64 {
+	job, e := parseBatchJobDefinition([]byte(desc))
+	if e != nil {
+		return 0
+	}
+	var bucket string
+	switch {
+	case job.Replicate != nil:
+		if job.Replicate.Source.isRemote() {
+			return 0
+		}
+		bucket = job.Replicate.Source.Bucket
+	case job.KeyRotate != nil:
+		bucket = job.KeyRotate.Bucket
+	case job.Expire != nil:
+		bucket = job.Expire.Bucket
+	}
+	accInfo, e := client.AccountInfo(ctx, madmin.AccountOpts{})
+	if e != nil {
+		return 0
+	}
+	for _, bi := range accInfo.Buckets {
+		if bi.Name == bucket {
+			return int64(bi.Objects)
+		}
+	}
+	return 0
+}
+
+// batchJobProgress - progress figures of a batch job
+type batchJobProgress struct {
+	Objects       int64         `json:"objects"`
+	ObjectsFailed int64         `json:"objectsFailed"`
+	ObjectsPerSec float64       `json:"objectsPerSec"`
+	BytesPerSec   float64       `json:"bytesPerSec,omitempty"`
+	Elapsed       time.Duration `json:"elapsed"`
+	ETA           time.Duration `json:"eta,omitempty"`
+}
+
+// getBatchJobProgress computes the throughput of the job and, when the
+// total number of objects is known, the estimated time to completion.
+func getBatchJobProgress(job madmin.JobMetric, total int64) (p batchJobProgress) {
+	var bytes int64
+	switch {
+	case job.Replicate != nil:
+		p.Objects, p.ObjectsFailed = job.Replicate.Objects, job.Replicate.ObjectsFailed
+		bytes = job.Replicate.BytesTransferred
+	case job.KeyRotate != nil:
+		p.Objects, p.ObjectsFailed = job.KeyRotate.Objects, job.KeyRotate.ObjectsFailed
+	}
+
+	p.Elapsed = job.LastUpdate.Sub(job.StartTime)
+	if p.Elapsed <= 0 {
+		return p
+	}
+	p.ObjectsPerSec = float64(p.Objects+p.ObjectsFailed) / p.Elapsed.Seconds()
+	p.BytesPerSec = float64(bytes) / p.Elapsed.Seconds()
+
+	remaining := total - p.Objects - p.ObjectsFailed
+	if !job.Complete && remaining > 0 && p.ObjectsPerSec > 0 {
+		p.ETA = time.Duration(float64(remaining) / p.ObjectsPerSec * float64(time.Second)).Round(time.Second)
+	}
+	return p
+}
+
+func initBatchJobMetricsUI(jobID string, total int64) *batchJobMetricsUI {
 	s := spinner.New()
 	s.Spinner = spinner.Points
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 	return &batchJobMetricsUI{
 		spinner: s,
 		jobID:   jobID,
+		total:   total,
 	}
 }
 
@@ -112,6 +217,7 @@ type batchJobMetricsUI struct {
 	spinner  spinner.Model
 	quitting bool
 	jobID    string
+	total    int64
 }
 
 func (m *batchJobMetricsUI) Init() tea.Cmd {
@@ -130,7 +236,7 @@ func (m *batchJobMetricsUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case madmin.JobMetric:
 		m.current = msg
-		if msg.Complete {
+		if msg.Complete || msg.Failed {
 			m.quitting = true
 			return m, tea.Quit
 		}
@@ -147,6 +253,30 @@ func (m *batchJobMetricsUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *batchJobMetricsUI) View() string {
 	var s strings.Builder
 
+	if !m.quitting {
+		s.WriteString(m.spinner.View())
+	} else {
+		if m.current.Complete || m.current.Failed {
+			if !m.current.Failed && getBatchJobProgress(m.current, 0).ObjectsFailed == 0 {
+				s.WriteString(m.spinner.Style.Render((tickCell + tickCell + tickCell)))
+			} else {
+				s.WriteString(m.spinner.Style.Render((crossTickCell + crossTickCell + crossTickCell)))
+			}
+		}
+	}
+	s.WriteString("\n")
+	s.WriteString(renderBatchJobMetric(m.current, m.total))
+
+	if m.quitting {
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// renderBatchJobMetric renders the status table of a batch job.
+func renderBatchJobMetric(job madmin.JobMetric, total int64) string {
+	var s strings.Builder
+
 	// Set table header
 	table := tablewriter.NewWriter(&s)
 	table.SetAutoWrapText(false)
@@ -169,43 +299,37 @@ func (m *batchJobMetricsUI) View() string {
 		})
 	}
 
-	if !m.quitting {
-		s.WriteString(m.spinner.View())
-	} else {
-		if m.current.Complete {
-			if m.current.Replicate.ObjectsFailed == 0 {
-				s.WriteString(m.spinner.Style.Render((tickCell + tickCell + tickCell)))
-			} else {
-				s.WriteString(m.spinner.Style.Render((crossTickCell + crossTickCell + crossTickCell)))
-			}
+	p := getBatchJobProgress(job, total)
+	addLine("JobType: ", job.JobType)
+	addLine("Objects: ", p.Objects)
+	addLine("FailedObjects: ", p.ObjectsFailed)
+	if p.Elapsed > 0 {
+		if job.Replicate != nil {
+			addLine("Throughput: ", fmt.Sprintf("%s/s", humanize.IBytes(uint64(p.BytesPerSec))))
 		}
+		addLine("IOPs: ", fmt.Sprintf("%.2f objs/s", p.ObjectsPerSec))
 	}
-	s.WriteString("\n")
-
-	switch m.current.JobType {
-	case string(madmin.BatchJobReplicate):
-		accElapsedTime := m.current.LastUpdate.Sub(m.current.StartTime)
-
-		addLine("JobType: ", m.current.JobType)
-		addLine("Objects: ", m.current.Replicate.Objects)
-		addLine("Versions: ", m.current.Replicate.Objects)
-		addLine("FailedObjects: ", m.current.Replicate.ObjectsFailed)
-		if accElapsedTime > 0 {
-			bytesTransferredPerSec := float64(int64(time.Second)*m.current.Replicate.BytesTransferred) / float64(accElapsedTime)
-			objectsPerSec := float64(int64(time.Second)*m.current.Replicate.Objects) / float64(accElapsedTime)
-			addLine("Throughput: ", fmt.Sprintf("%s/s", humanize.IBytes(uint64(bytesTransferredPerSec))))
-			addLine("IOPs: ", fmt.Sprintf("%.2f objs/s", objectsPerSec))
-		}
-		addLine("Transferred: ", humanize.IBytes(uint64(m.current.Replicate.BytesTransferred)))
-		addLine("Elapsed: ", accElapsedTime.String())
-		addLine("CurrObjName: ", m.current.Replicate.Object)
+	switch {
+	case job.Replicate != nil:
+		addLine("Transferred: ", humanize.IBytes(uint64(job.Replicate.BytesTransferred)))
+	}
+	addLine("Elapsed: ", p.Elapsed.Round(time.Second).String())
+	if p.ETA > 0 {
+		addLine("ETA: ", "~"+p.ETA.String())
+	}
+	switch {
+	case job.Replicate != nil:
+		addLine("CurrObjName: ", job.Replicate.Object)
+	case job.KeyRotate != nil:
+		addLine("CurrObjName: ", job.KeyRotate.Object)
+	}
+	if job.Complete {
+		addLine("Status: ", "complete")
+	} else if job.Failed {
+		addLine("Status: ", "failed")
 	}
 
 	table.AppendBulk(data)
 	table.Render()
-
-	if m.quitting {
-		s.WriteString("\n")
-	}
 	return s.String()
 }
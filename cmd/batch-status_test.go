@@ -0,0 +1,83 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestGetBatchJobProgress(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		job      madmin.JobMetric
+		total    int64
+		expected batchJobProgress
+	}{
+		// Nothing processed yet, no throughput without elapsed time.
+		{
+			madmin.JobMetric{StartTime: start, LastUpdate: start, Replicate: &madmin.ReplicateInfo{}},
+			100,
+			batchJobProgress{},
+		},
+		// A clock going backwards does not yield a negative throughput.
+		{
+			madmin.JobMetric{StartTime: start, LastUpdate: start.Add(-time.Second), Replicate: &madmin.ReplicateInfo{Objects: 10}},
+			100,
+			batchJobProgress{Objects: 10, Elapsed: -time.Second},
+		},
+		// Failed objects count in the throughput and the remaining objects.
+		{
+			madmin.JobMetric{StartTime: start, LastUpdate: start.Add(10 * time.Second), Replicate: &madmin.ReplicateInfo{Objects: 15, ObjectsFailed: 5, BytesTransferred: 1000}},
+			100,
+			batchJobProgress{Objects: 15, ObjectsFailed: 5, ObjectsPerSec: 2, BytesPerSec: 100, Elapsed: 10 * time.Second, ETA: 40 * time.Second},
+		},
+		// No ETA without a total.
+		{
+			madmin.JobMetric{StartTime: start, LastUpdate: start.Add(10 * time.Second), KeyRotate: &madmin.KeyRotationInfo{Objects: 20}},
+			0,
+			batchJobProgress{Objects: 20, ObjectsPerSec: 2, Elapsed: 10 * time.Second},
+		},
+		// No ETA past the estimated total.
+		{
+			madmin.JobMetric{StartTime: start, LastUpdate: start.Add(10 * time.Second), KeyRotate: &madmin.KeyRotationInfo{Objects: 20}},
+			10,
+			batchJobProgress{Objects: 20, ObjectsPerSec: 2, Elapsed: 10 * time.Second},
+		},
+		// No ETA once the job is complete.
+		{
+			madmin.JobMetric{StartTime: start, LastUpdate: start.Add(10 * time.Second), Complete: true, KeyRotate: &madmin.KeyRotationInfo{Objects: 20}},
+			100,
+			batchJobProgress{Objects: 20, ObjectsPerSec: 2, Elapsed: 10 * time.Second},
+		},
+		// Jobs without progress figures.
+		{
+			madmin.JobMetric{StartTime: start, LastUpdate: start.Add(10 * time.Second)},
+			100,
+			batchJobProgress{Elapsed: 10 * time.Second},
+		},
+	}
+
+	for i, testCase := range testCases {
+		if p := getBatchJobProgress(testCase.job, testCase.total); p != testCase.expected {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, p)
+		}
+	}
+}
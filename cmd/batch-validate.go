@@ -0,0 +1,317 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var batchValidateCmd = cli.Command{
+	Name:         "validate",
+	Usage:        "validate a batch job definition before starting it",
+	Action:       mainBatchValidate,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} JOBFILE TARGET
+
+  The job is checked against the template the admin API generates for its
+  type, fields outside of the template are reported as unknown.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Validate a batch 'replication' job definition against the cluster 'myminio':
+     {{.Prompt}} {{.HelpName}} ./replication.yaml myminio
+`,
+}
+
+// batchValidateCheck - result of a single validation step
+type batchValidateCheck struct {
+	Check string `json:"check"`
+	Error string `json:"error,omitempty"`
+}
+
+// batchValidateMessage container for batch validate messages
+type batchValidateMessage struct {
+	Status  string               `json:"status"`
+	JobFile string               `json:"jobFile"`
+	Type    string               `json:"type,omitempty"`
+	Checks  []batchValidateCheck `json:"checks"`
+}
+
+func (m batchValidateMessage) failed() bool {
+	for _, c := range m.Checks {
+		if c.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// String colorized batch validate message
+func (m batchValidateMessage) String() string {
+	var b strings.Builder
+	for _, c := range m.Checks {
+		if c.Error == "" {
+			fmt.Fprintln(&b, console.Colorize("BatchValidatePass", tickCell)+c.Check)
+			continue
+		}
+		fmt.Fprintln(&b, console.Colorize("BatchValidateFail", crossTickCell)+c.Check+": "+c.Error)
+	}
+	if m.failed() {
+		b.WriteString(console.Colorize("BatchValidateFail", fmt.Sprintf("'%s' is not a valid %s job", m.JobFile, m.Type)))
+	} else {
+		b.WriteString(console.Colorize("BatchValidatePass", fmt.Sprintf("'%s' is a valid %s job", m.JobFile, m.Type)))
+	}
+	return b.String()
+}
+
+// JSON jsonified batch validate message
+func (m batchValidateMessage) JSON() string {
+	m.Status = "success"
+	if m.failed() {
+		m.Status = "error"
+	}
+	return toJSON(m)
+}
+
+// checkBatchValidateSyntax - validate all the passed arguments
+func checkBatchValidateSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainBatchValidate is the handle for "mc batch validate" command.
+func mainBatchValidate(ctx *cli.Context) error {
+	checkBatchValidateSyntax(ctx)
+
+	console.SetColor("BatchValidatePass", color.New(color.FgGreen, color.Bold))
+	console.SetColor("BatchValidateFail", color.New(color.FgRed, color.Bold))
+
+	args := ctx.Args()
+	jobFile := args.Get(0)
+	aliasedURL := args.Get(1)
+
+	buf, e := os.ReadFile(jobFile)
+	fatalIf(probe.NewError(e), "Unable to read %s", jobFile)
+
+	job, e := parseBatchJobDefinition(buf)
+	fatalIf(probe.NewError(e), "Unable to parse %s", jobFile)
+
+	msg := batchValidateMessage{JobFile: jobFile, Type: string(job.Type())}
+	addCheck := func(check string, err error) {
+		c := batchValidateCheck{Check: check}
+		if err != nil {
+			c.Error = err.Error()
+		}
+		msg.Checks = append(msg.Checks, c)
+	}
+
+	errs := job.validate()
+	if len(errs) == 0 {
+		addCheck("job definition matches the "+msg.Type+" schema", nil)
+	}
+	for _, err := range errs {
+		addCheck("job definition matches the "+msg.Type+" schema", err)
+	}
+
+	// Create a new MinIO Admin Client
+	adminClient, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	// The template generated for the job type is the reference schema of
+	// the fields known to the server.
+	reference, e := adminClient.GenerateBatchJob(globalContext, madmin.GenerateBatchJobOpts{Type: job.Type()})
+	if e != nil {
+		e = fmt.Errorf("no reference schema for %s jobs: %w", msg.Type, e)
+	}
+	addCheck("server supports "+msg.Type+" jobs", e)
+	if e == nil {
+		unknown, e := unknownBatchJobFields(buf, reference)
+		if e == nil && len(unknown) > 0 {
+			e = fmt.Errorf("unknown fields %s", strings.Join(unknown, ", "))
+		}
+		addCheck("job definition only uses fields of the server "+msg.Type+" schema", e)
+	}
+
+	alias, _ := url2Alias(aliasedURL)
+	for _, ref := range job.bucketReferences(alias) {
+		addCheck(fmt.Sprintf("bucket '%s' is accessible on %s", ref.bucket, ref.location()), ref.verify())
+	}
+
+	printMsg(msg)
+	if msg.failed() {
+		exitProcess(globalErrorExitStatus)
+	}
+	return nil
+}
+
+// batchJobTemplateFieldRegex matches the optional fields commented out
+// in the job templates, such as "    # endpoint: ENDPOINT".
+var batchJobTemplateFieldRegex = regexp.MustCompile(`(?m)^(\s*)# ?(\s*(?:- )?[A-Za-z]+:.*)$`)
+
+// batchJobFields returns the paths of all the fields of a job definition,
+// such as "replicate.source.bucket", list items are named "[]".
+func batchJobFields(buf []byte) (map[string]bool, error) {
+	var def interface{}
+	if e := yaml.Unmarshal(buf, &def); e != nil {
+		return nil, e
+	}
+	fields := map[string]bool{}
+	var walk func(path string, v interface{})
+	walk = func(path string, v interface{}) {
+		switch v := v.(type) {
+		case map[interface{}]interface{}:
+			for key, value := range v {
+				field := fmt.Sprint(key)
+				if path != "" {
+					field = path + "." + field
+				}
+				fields[field] = true
+				walk(field, value)
+			}
+		case []interface{}:
+			for _, value := range v {
+				walk(path+"[]", value)
+			}
+		}
+	}
+	walk("", def)
+	return fields, nil
+}
+
+// unknownBatchJobFields returns the fields of the job definition which are
+// not in the reference template of its type, optional fields of the
+// template are commented out and known as well.
+func unknownBatchJobFields(job []byte, reference string) ([]string, error) {
+	known, e := batchJobFields([]byte(batchJobTemplateFieldRegex.ReplaceAllString(reference, "$1$2")))
+	if e != nil {
+		return nil, fmt.Errorf("unable to parse the reference schema: %w", e)
+	}
+	fields, e := batchJobFields(job)
+	if e != nil {
+		return nil, e
+	}
+	var unknown []string
+	for field := range fields {
+		if !known[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	sort.Strings(unknown)
+	// Only report the outermost unknown fields.
+	outermost := unknown[:0]
+	for _, field := range unknown {
+		if n := len(outermost); n > 0 && (strings.HasPrefix(field, outermost[n-1]+".") || strings.HasPrefix(field, outermost[n-1]+"[]")) {
+			continue
+		}
+		outermost = append(outermost, field)
+	}
+	return outermost, nil
+}
+
+// batchJobBucketRef - a bucket referenced by a job, either on the
+// cluster running the job or on a remote endpoint.
+type batchJobBucketRef struct {
+	alias    string
+	bucket   string
+	endpoint string
+	creds    batchJobCredentials
+	path     string
+}
+
+func (r batchJobBucketRef) location() string {
+	if r.endpoint != "" {
+		return r.endpoint
+	}
+	return r.alias
+}
+
+// verify checks that the bucket exists and is accessible with
+// the credentials in the job definition.
+func (r batchJobBucketRef) verify() error {
+	var (
+		clnt Client
+		err  *probe.Error
+	)
+	if r.endpoint == "" {
+		clnt, err = newClient(r.alias + "/" + r.bucket)
+	} else {
+		path := r.path
+		if path == "" {
+			path = "auto"
+		}
+		clnt, err = S3New(NewS3Config(urlJoinPath(r.endpoint, r.bucket), &aliasConfigV10{
+			URL:          r.endpoint,
+			AccessKey:    r.creds.AccessKey,
+			SecretKey:    r.creds.SecretKey,
+			SessionToken: r.creds.SessionToken,
+			API:          "S3v4",
+			Path:         path,
+		}))
+	}
+	if err != nil {
+		return err.ToGoError()
+	}
+	if _, err = clnt.Stat(globalContext, StatOptions{}); err != nil {
+		return err.ToGoError()
+	}
+	return nil
+}
+
+// bucketReferences returns all buckets referenced by the job.
+func (j batchJobDefinition) bucketReferences(alias string) (refs []batchJobBucketRef) {
+	switch {
+	case j.Replicate != nil:
+		for _, ep := range []batchJobReplicateEndpoint{j.Replicate.Source, j.Replicate.Target} {
+			if ep.Bucket == "" {
+				continue
+			}
+			refs = append(refs, batchJobBucketRef{
+				alias:    alias,
+				bucket:   ep.Bucket,
+				endpoint: ep.Endpoint,
+				creds:    ep.Credentials,
+				path:     ep.Path,
+			})
+		}
+	case j.KeyRotate != nil && j.KeyRotate.Bucket != "":
+		refs = append(refs, batchJobBucketRef{alias: alias, bucket: j.KeyRotate.Bucket})
+	case j.Expire != nil && j.Expire.Bucket != "":
+		refs = append(refs, batchJobBucketRef{alias: alias, bucket: j.Expire.Bucket})
+	}
+	return refs
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestUnknownBatchJobFields(t *testing.T) {
+	testCases := []struct {
+		job       string
+		reference string
+		unknown   []string
+	}{
+		// The templates are valid against themselves.
+		{madmin.BatchJobReplicateTemplate, madmin.BatchJobReplicateTemplate, nil},
+		{madmin.BatchJobKeyRotateTemplate, madmin.BatchJobKeyRotateTemplate, nil},
+		// Fields commented out in the template are optional fields.
+		{
+			`replicate:
+  apiVersion: v1
+  source:
+    type: minio
+    bucket: src
+    endpoint: https://remote:9000
+    credentials:
+      accessKey: minio
+      secretKey: minio123
+  target:
+    type: minio
+    bucket: dst
+  flags:
+    filter:
+      tags:
+        - key: name
+          value: pick*
+`,
+			madmin.BatchJobReplicateTemplate,
+			nil,
+		},
+		// Fields of the client schema unknown to the server.
+		{
+			`keyrotate:
+  apiVersion: v1
+  bucket: mybucket
+  encryption:
+    type: sse-s3
+  snowball:
+    enable: true
+    batch: 10
+  flags:
+    filter:
+      tags:
+        - key: name
+          value: pick*
+          regex: true
+`,
+			madmin.BatchJobKeyRotateTemplate,
+			[]string{"keyrotate.flags.filter.tags[].regex", "keyrotate.snowball"},
+		},
+		// A job of another type.
+		{
+			`keyrotate:
+  apiVersion: v1
+  bucket: mybucket
+`,
+			madmin.BatchJobReplicateTemplate,
+			[]string{"keyrotate"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		unknown, e := unknownBatchJobFields([]byte(testCase.job), testCase.reference)
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if !reflect.DeepEqual(unknown, testCase.unknown) {
+			t.Errorf("Test %d: expected unknown fields %v, got %v", i+1, testCase.unknown, unknown)
+		}
+	}
+}
@@ -0,0 +1,597 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+var browseCmd = cli.Command{
+	Name:         "browse",
+	Usage:        "browse buckets and objects interactively",
+	Action:       mainBrowse,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS[/BUCKET[/PREFIX]]
+
+KEYS:
+  up/down, k/j     move the selection
+  enter, right, l  open a bucket or prefix, show the metadata of an object
+  backspace, left  go to the parent prefix
+  p                preview the beginning of an object
+  c                copy the object to another location
+  d                remove the object
+  t                set the tags of the object
+  r                refresh the listing
+  q, ctrl+c        quit
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Browse all the buckets of the alias "myminio".
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. Browse the bucket "mybucket".
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+`,
+}
+
+// browsePreviewSize is the number of bytes of an object shown in a preview.
+const browsePreviewSize = 16 << 10
+
+type browseMode int
+
+const (
+	browseModeList browseMode = iota
+	browseModeView
+	browseModeConfirmRemove
+	browseModeCopy
+	browseModeTag
+)
+
+type browseEntry struct {
+	name    string
+	content *ClientContent
+}
+
+type browseListMsg struct {
+	path    string
+	entries []browseEntry
+	err     *probe.Error
+}
+
+type browseViewMsg struct {
+	text string
+	err  *probe.Error
+}
+
+type browseDoneMsg struct {
+	status string
+	err    *probe.Error
+}
+
+type browseModel struct {
+	path    string
+	entries []browseEntry
+	cursor  int
+	offset  int
+	width   int
+	height  int
+	loading bool
+
+	mode   browseMode
+	view   []string
+	input  string
+	status string
+}
+
+var (
+	browseHeaderStyle   = lipgloss.NewStyle().Bold(true)
+	browseDirStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true)
+	browseSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	browseStatusStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	browseErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// listBrowsePath lists the buckets or the objects under the prefix.
+func listBrowsePath(urlStr string) tea.Cmd {
+	return func() tea.Msg {
+		clnt, err := newClient(urlStr)
+		if err != nil {
+			return browseListMsg{path: urlStr, err: err.Trace(urlStr)}
+		}
+		prefix := clnt.GetURL().Path
+		if !strings.HasSuffix(prefix, string(clnt.GetURL().Separator)) {
+			prefix += string(clnt.GetURL().Separator)
+		}
+
+		var entries []browseEntry
+		for content := range clnt.List(globalContext, ListOptions{ShowDir: DirFirst}) {
+			if content.Err != nil {
+				return browseListMsg{path: urlStr, err: content.Err.Trace(urlStr)}
+			}
+			name := strings.TrimPrefix(content.URL.Path, prefix)
+			name = strings.TrimPrefix(name, string(content.URL.Separator))
+			if content.Type.IsDir() && !strings.HasSuffix(name, "/") {
+				name += "/"
+			}
+			entries = append(entries, browseEntry{name: name, content: content})
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			iDir, jDir := entries[i].content.Type.IsDir(), entries[j].content.Type.IsDir()
+			if iDir != jDir {
+				return iDir
+			}
+			return entries[i].name < entries[j].name
+		})
+		return browseListMsg{path: urlStr, entries: entries}
+	}
+}
+
+// statBrowseObject shows the metadata and the tags of an object.
+func statBrowseObject(urlStr string) tea.Cmd {
+	return func() tea.Msg {
+		clnt, content, err := url2Stat(globalContext, urlStr, "", true, nil, time.Time{}, false)
+		if err != nil {
+			return browseViewMsg{err: err.Trace(urlStr)}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Name      : %s\n", urlStr)
+		fmt.Fprintf(&b, "Date      : %s\n", content.Time.Local().Format(printDate))
+		fmt.Fprintf(&b, "Size      : %s\n", humanize.IBytes(uint64(content.Size)))
+		if content.ETag != "" {
+			fmt.Fprintf(&b, "ETag      : %s\n", content.ETag)
+		}
+		if content.VersionID != "" {
+			fmt.Fprintf(&b, "VersionID : %s\n", content.VersionID)
+		}
+		if content.StorageClass != "" {
+			fmt.Fprintf(&b, "Class     : %s\n", content.StorageClass)
+		}
+		writeSorted := func(title string, kv map[string]string) {
+			if len(kv) == 0 {
+				return
+			}
+			fmt.Fprintf(&b, "%s:\n", title)
+			keys := make([]string, 0, len(kv))
+			for k := range kv {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Fprintf(&b, "  %s: %s\n", k, kv[k])
+			}
+		}
+		writeSorted("Metadata  ", content.Metadata)
+		if tags, err := clnt.GetTags(globalContext, content.VersionID); err == nil {
+			writeSorted("Tags      ", tags)
+		}
+		return browseViewMsg{text: b.String()}
+	}
+}
+
+// previewBrowseObject shows the beginning of an object, as text when
+// it is valid UTF-8 and as a hex dump otherwise.
+func previewBrowseObject(urlStr string) tea.Cmd {
+	return func() tea.Msg {
+		reader, err := getSourceStreamFromURL(globalContext, urlStr, nil, getSourceOpts{})
+		if err != nil {
+			return browseViewMsg{err: err.Trace(urlStr)}
+		}
+		defer reader.Close()
+
+		buf, e := io.ReadAll(io.LimitReader(reader, browsePreviewSize))
+		if e != nil {
+			return browseViewMsg{err: probe.NewError(e).Trace(urlStr)}
+		}
+		if utf8.Valid(buf) && !bytes.ContainsRune(buf, 0) {
+			return browseViewMsg{text: string(buf)}
+		}
+		return browseViewMsg{text: hex.Dump(buf)}
+	}
+}
+
+// copyBrowseObject copies an object, the name of the object is kept when
+// the target ends with a separator.
+func copyBrowseObject(sourceURL, targetURL string) tea.Cmd {
+	return func() tea.Msg {
+		if strings.HasSuffix(targetURL, "/") {
+			targetURL += path.Base(sourceURL)
+		}
+		_, content, err := url2Stat(globalContext, sourceURL, "", false, nil, time.Time{}, false)
+		if err != nil {
+			return browseDoneMsg{err: err.Trace(sourceURL)}
+		}
+		sourceAlias, _, _ := mustExpandAlias(sourceURL)
+		targetAlias, targetURLFull, _ := mustExpandAlias(targetURL)
+		urls := makeCopyContentTypeA(sourceAlias, content, targetAlias, targetURLFull)
+		if urls = uploadSourceToTargetURL(globalContext, urls, nil, nil, false, false); urls.Error != nil {
+			return browseDoneMsg{err: urls.Error.Trace(sourceURL, targetURL)}
+		}
+		return browseDoneMsg{status: "Copied `" + sourceURL + "` to `" + targetURL + "`."}
+	}
+}
+
+// removeBrowseObject removes an object.
+func removeBrowseObject(urlStr string) tea.Cmd {
+	return func() tea.Msg {
+		clnt, err := newClient(urlStr)
+		if err != nil {
+			return browseDoneMsg{err: err.Trace(urlStr)}
+		}
+		contentCh := make(chan *ClientContent, 1)
+		contentCh <- &ClientContent{URL: clnt.GetURL()}
+		close(contentCh)
+		for result := range clnt.Remove(globalContext, false, false, false, false, contentCh) {
+			if result.Err != nil {
+				return browseDoneMsg{err: result.Err.Trace(urlStr)}
+			}
+		}
+		return browseDoneMsg{status: "Removed `" + urlStr + "`."}
+	}
+}
+
+// tagBrowseObject replaces the tags of an object.
+func tagBrowseObject(urlStr, tags string) tea.Cmd {
+	return func() tea.Msg {
+		clnt, err := newClient(urlStr)
+		if err != nil {
+			return browseDoneMsg{err: err.Trace(urlStr)}
+		}
+		if tags == "" {
+			err = clnt.DeleteTags(globalContext, "")
+		} else {
+			err = clnt.SetTags(globalContext, "", tags)
+		}
+		if err != nil {
+			return browseDoneMsg{err: err.Trace(urlStr)}
+		}
+		return browseDoneMsg{status: "Tags of `" + urlStr + "` updated."}
+	}
+}
+
+func (m *browseModel) selected() *browseEntry {
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return nil
+	}
+	return &m.entries[m.cursor]
+}
+
+// selectedObject returns the URL of the selected object, directories
+// cannot be copied, removed or tagged here.
+func (m *browseModel) selectedObject() (string, bool) {
+	entry := m.selected()
+	if entry == nil || entry.content.Type.IsDir() {
+		m.status = "Select an object first."
+		return "", false
+	}
+	return m.path + entry.name, true
+}
+
+func (m *browseModel) open(urlStr string) tea.Cmd {
+	m.loading = true
+	m.status = ""
+	return listBrowsePath(urlStr)
+}
+
+// parentBrowsePath returns the parent prefix, the alias is the top.
+func parentBrowsePath(urlStr string) (string, bool) {
+	trimmed := strings.TrimSuffix(urlStr, "/")
+	i := strings.LastIndex(trimmed, "/")
+	if i < 0 {
+		return urlStr, false
+	}
+	return trimmed[:i+1], true
+}
+
+func (m *browseModel) listHeight() int {
+	// Lines used by the header and the status.
+	h := m.height - 4
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+func (m browseModel) Init() tea.Cmd {
+	return listBrowsePath(m.path)
+}
+
+func (m browseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case browseListMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = "Unable to list `" + msg.path + "`: " + msg.err.ToGoError().Error()
+			return m, nil
+		}
+		if msg.path != m.path {
+			m.cursor, m.offset = 0, 0
+		}
+		m.path, m.entries = msg.path, msg.entries
+		if m.cursor >= len(m.entries) {
+			m.cursor = len(m.entries) - 1
+		}
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+		return m, nil
+	case browseViewMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = msg.err.ToGoError().Error()
+			return m, nil
+		}
+		m.mode = browseModeView
+		m.view = strings.Split(strings.TrimSuffix(msg.text, "\n"), "\n")
+		m.offset = 0
+		return m, nil
+	case browseDoneMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = msg.err.ToGoError().Error()
+			return m, nil
+		}
+		m.status = msg.status
+		return m, listBrowsePath(m.path)
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case browseModeView:
+			return m.updateView(msg)
+		case browseModeConfirmRemove:
+			m.mode = browseModeList
+			if msg.String() == "y" || msg.String() == "Y" {
+				if urlStr, ok := m.selectedObject(); ok {
+					m.loading = true
+					return m, removeBrowseObject(urlStr)
+				}
+			}
+			m.status = "Remove canceled."
+			return m, nil
+		case browseModeCopy, browseModeTag:
+			return m.updateInput(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m browseModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+	case "pgup":
+		m.cursor -= m.listHeight()
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+	case "pgdown":
+		m.cursor += m.listHeight()
+		if m.cursor > len(m.entries)-1 {
+			m.cursor = len(m.entries) - 1
+		}
+	case "enter", "right", "l":
+		entry := m.selected()
+		if entry == nil || m.loading {
+			break
+		}
+		if entry.content.Type.IsDir() {
+			return m, m.open(m.path + entry.name)
+		}
+		m.loading = true
+		return m, statBrowseObject(m.path + entry.name)
+	case "backspace", "left", "h":
+		if parent, ok := parentBrowsePath(m.path); ok && !m.loading {
+			return m, m.open(parent)
+		}
+	case "r":
+		return m, m.open(m.path)
+	case "p":
+		if urlStr, ok := m.selectedObject(); ok {
+			m.loading = true
+			return m, previewBrowseObject(urlStr)
+		}
+	case "d":
+		if _, ok := m.selectedObject(); ok {
+			m.mode = browseModeConfirmRemove
+		}
+	case "c":
+		if _, ok := m.selectedObject(); ok {
+			m.mode, m.input = browseModeCopy, m.path
+		}
+	case "t":
+		if urlStr, ok := m.selectedObject(); ok {
+			m.mode, m.input = browseModeTag, ""
+			if clnt, err := newClient(urlStr); err == nil {
+				if tags, err := clnt.GetTags(globalContext, ""); err == nil {
+					var kvs []string
+					for k, v := range tags {
+						kvs = append(kvs, k+"="+v)
+					}
+					sort.Strings(kvs)
+					m.input = strings.Join(kvs, "&")
+				}
+			}
+		}
+	}
+
+	// Keep the selection visible.
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.listHeight() {
+		m.offset = m.cursor - m.listHeight() + 1
+	}
+	return m, nil
+}
+
+func (m browseModel) updateView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "enter", "left", "h", "backspace":
+		m.mode = browseModeList
+		m.offset = 0
+		if m.cursor >= m.listHeight() {
+			m.offset = m.cursor - m.listHeight() + 1
+		}
+	case "up", "k":
+		if m.offset > 0 {
+			m.offset--
+		}
+	case "down", "j":
+		if m.offset < len(m.view)-1 {
+			m.offset++
+		}
+	}
+	return m, nil
+}
+
+func (m browseModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = browseModeList
+		m.status = "Canceled."
+	case tea.KeyEnter:
+		mode := m.mode
+		m.mode = browseModeList
+		urlStr, ok := m.selectedObject()
+		if !ok {
+			break
+		}
+		m.loading = true
+		if mode == browseModeCopy {
+			return m, copyBrowseObject(urlStr, strings.TrimSpace(m.input))
+		}
+		return m, tagBrowseObject(urlStr, strings.TrimSpace(m.input))
+	case tea.KeyBackspace:
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m browseModel) View() string {
+	var s strings.Builder
+	s.WriteString(browseHeaderStyle.Render(m.path))
+	s.WriteString("\n\n")
+
+	height := m.listHeight()
+	switch m.mode {
+	case browseModeView:
+		for i := m.offset; i < len(m.view) && i < m.offset+height; i++ {
+			s.WriteString(m.view[i] + "\n")
+		}
+		s.WriteString("\n" + browseStatusStyle.Render("q: back  up/down: scroll"))
+		return s.String()
+	}
+
+	if len(m.entries) == 0 && !m.loading {
+		s.WriteString(browseStatusStyle.Render("(empty)") + "\n")
+	}
+	for i := m.offset; i < len(m.entries) && i < m.offset+height; i++ {
+		entry := m.entries[i]
+		line := fmt.Sprintf("%-23s %10s  %s", entry.content.Time.Local().Format(printDate),
+			humanize.IBytes(uint64(entry.content.Size)), entry.name)
+		if entry.content.Type.IsDir() {
+			line = fmt.Sprintf("%-23s %10s  %s", "", "", browseDirStyle.Render(entry.name))
+		}
+		if i == m.cursor {
+			line = browseSelectedStyle.Render(line)
+		}
+		s.WriteString(line + "\n")
+	}
+	for i := len(m.entries) - m.offset; i < height; i++ {
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	switch {
+	case m.mode == browseModeConfirmRemove:
+		s.WriteString(browseErrorStyle.Render("Remove the object? (y/N)"))
+	case m.mode == browseModeCopy:
+		s.WriteString("Copy to: " + m.input + "█")
+	case m.mode == browseModeTag:
+		s.WriteString("Tags (key=value&...): " + m.input + "█")
+	case m.loading:
+		s.WriteString(browseStatusStyle.Render("Loading..."))
+	case m.status != "":
+		s.WriteString(browseStatusStyle.Render(m.status))
+	default:
+		s.WriteString(browseStatusStyle.Render("enter: open  p: preview  c: copy  d: remove  t: tags  r: refresh  q: quit"))
+	}
+	return s.String()
+}
+
+// mainBrowse is the handle for "mc browse" command.
+func mainBrowse(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if !isTerminal() {
+		fatalIf(errInvalidArgument(), "`mc browse` requires a terminal.")
+	}
+
+	urlStr := ctx.Args().First()
+	if !strings.HasSuffix(urlStr, "/") {
+		urlStr += "/"
+	}
+	_, err := newClient(urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to initialize target `"+urlStr+"`.")
+
+	p := tea.NewProgram(browseModel{path: urlStr, loading: true}, tea.WithAltScreen())
+	if _, e := p.Run(); e != nil {
+		fatalIf(probe.NewError(e), "Unable to browse `"+urlStr+"`.")
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestParentBrowsePath(t *testing.T) {
+	testCases := []struct {
+		path   string
+		parent string
+		ok     bool
+	}{
+		{"myminio/bucket/prefix/", "myminio/bucket/", true},
+		{"myminio/bucket/", "myminio/", true},
+		// The alias is the top.
+		{"myminio/", "myminio/", false},
+		{"myminio", "myminio", false},
+	}
+	for i, testCase := range testCases {
+		parent, ok := parentBrowsePath(testCase.path)
+		if parent != testCase.parent || ok != testCase.ok {
+			t.Errorf("Test %d: expected %q %t, got %q %t", i+1, testCase.parent, testCase.ok, parent, ok)
+		}
+	}
+}
+
+func TestListBrowsePath(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newConfigV10(), nil }
+	dir := t.TempDir()
+	for _, name := range []string{"b.txt", "a.txt", "photos/1.png", "docs/readme.md"} {
+		if e := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o700); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0o600); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	msg, ok := listBrowsePath(dir + "/")().(browseListMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	// The directories are listed first.
+	var names []string
+	for _, entry := range msg.entries {
+		names = append(names, entry.name)
+	}
+	if expected := []string{"docs/", "photos/", "a.txt", "b.txt"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %q, got %q", expected, names)
+	}
+
+	if msg, _ = listBrowsePath(filepath.Join(dir, "missing") + "/")().(browseListMsg); msg.err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+func TestPreviewBrowseObject(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newConfigV10(), nil }
+	dir := t.TempDir()
+	testCases := []struct {
+		content  string
+		expected string
+	}{
+		{"hello\nworld\n", "hello\nworld\n"},
+		// Binary content is displayed as a hex dump.
+		{"\x00\x01\x02", "00000000  00 01 02"},
+		{"\xff\xfe", "00000000  ff fe"},
+	}
+	for i, testCase := range testCases {
+		name := filepath.Join(dir, "object")
+		if e := os.WriteFile(name, []byte(testCase.content), 0o600); e != nil {
+			t.Fatal(e)
+		}
+		msg := previewBrowseObject(name)().(browseViewMsg)
+		if msg.err != nil {
+			t.Fatalf("Test %d: %v", i+1, msg.err)
+		}
+		if !strings.HasPrefix(msg.text, testCase.expected) {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, msg.text)
+		}
+	}
+}
+
+func TestBrowseModelUpdate(t *testing.T) {
+	key := func(s string) tea.KeyMsg {
+		switch s {
+		case "enter":
+			return tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			return tea.KeyMsg{Type: tea.KeyEsc}
+		case "backspace":
+			return tea.KeyMsg{Type: tea.KeyBackspace}
+		case "pgdown":
+			return tea.KeyMsg{Type: tea.KeyPgDown}
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	entries := []browseEntry{
+		{name: "docs/", content: &ClientContent{Type: os.ModeDir}},
+		{name: "a.txt", content: &ClientContent{}},
+		{name: "b.txt", content: &ClientContent{}},
+		{name: "c.txt", content: &ClientContent{}},
+		{name: "d.txt", content: &ClientContent{}},
+	}
+
+	testCases := []struct {
+		keys    []string
+		cursor  int
+		offset  int
+		mode    browseMode
+		input   string
+		status  string
+		loading bool
+	}{
+		// The selection stays visible in the 2 lines of the list.
+		{[]string{"j", "j"}, 2, 1, browseModeList, "", "", false},
+		{[]string{"k"}, 0, 0, browseModeList, "", "", false},
+		{[]string{"j", "j", "j"}, 3, 2, browseModeList, "", "", false},
+		{[]string{"pgdown", "pgdown", "pgdown", "j"}, 4, 3, browseModeList, "", "", false},
+		// Directories are opened, objects are displayed.
+		{[]string{"enter"}, 0, 0, browseModeList, "", "", true},
+		{[]string{"j", "enter"}, 1, 0, browseModeList, "", "", true},
+		// Only objects can be removed, copied or tagged.
+		{[]string{"d"}, 0, 0, browseModeList, "", "Select an object first.", false},
+		{[]string{"j", "d"}, 1, 0, browseModeConfirmRemove, "", "", false},
+		{[]string{"j", "d", "n"}, 1, 0, browseModeList, "", "Remove canceled.", false},
+		{[]string{"j", "d", "y"}, 1, 0, browseModeList, "", "", true},
+		{[]string{"j", "c"}, 1, 0, browseModeCopy, "myminio/bucket/", "", false},
+		{[]string{"j", "c", "b", "a", "backspace", "k", "/"}, 1, 0, browseModeCopy, "myminio/bucket/bk/", "", false},
+		{[]string{"j", "c", "esc"}, 1, 0, browseModeList, "myminio/bucket/", "Canceled.", false},
+		{[]string{"j", "c", "enter"}, 1, 0, browseModeList, "myminio/bucket/", "", true},
+	}
+	for i, testCase := range testCases {
+		var model tea.Model = browseModel{path: "myminio/bucket/", entries: entries, height: 6}
+		for _, k := range testCase.keys {
+			model, _ = model.Update(key(k))
+		}
+		m := model.(browseModel)
+		if m.cursor != testCase.cursor || m.offset != testCase.offset {
+			t.Errorf("Test %d: expected cursor %d offset %d, got %d %d", i+1, testCase.cursor, testCase.offset, m.cursor, m.offset)
+		}
+		if m.mode != testCase.mode || m.input != testCase.input || m.status != testCase.status || m.loading != testCase.loading {
+			t.Errorf("Test %d: expected mode %d input %q status %q loading %t, got %d %q %q %t", i+1,
+				testCase.mode, testCase.input, testCase.status, testCase.loading, m.mode, m.input, m.status, m.loading)
+		}
+	}
+
+	// A new listing selects the first entry of another path.
+	var model tea.Model = browseModel{path: "myminio/bucket/", entries: entries, cursor: 3, offset: 2, loading: true}
+	model, _ = model.Update(browseListMsg{path: "myminio/bucket/docs/", entries: entries[1:2]})
+	if m := model.(browseModel); m.cursor != 0 || m.offset != 0 || m.loading || m.path != "myminio/bucket/docs/" {
+		t.Errorf("unexpected model after listing %+v", m)
+	}
+}
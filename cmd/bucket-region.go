@@ -0,0 +1,251 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"github.com/minio/minio-go/v7/pkg/signer"
+)
+
+// bucketRegionCache is the region of the buckets of each alias, so that
+// an alias of a multi-region endpoint, e.g. AWS S3, addresses each bucket
+// in its own region rather than being redirected.
+type bucketRegionCache struct {
+	mutex   sync.Mutex
+	regions map[string]map[string]string // alias -> bucket -> region
+	lookups map[string]bool              // buckets already looked up
+}
+
+var globalBucketRegions = &bucketRegionCache{
+	regions: map[string]map[string]string{},
+	lookups: map[string]bool{},
+}
+
+func getBucketRegionCachePath(alias string) string {
+	return filepath.Join(mustGetMcConfigDir(), "region-cache", alias+".json")
+}
+
+// load returns the bucket regions of the alias, read once from disk.
+func (c *bucketRegionCache) load(alias string) map[string]string {
+	regions, ok := c.regions[alias]
+	if !ok {
+		regions = map[string]string{}
+		if buf, e := os.ReadFile(getBucketRegionCachePath(alias)); e == nil {
+			json.Unmarshal(buf, &regions)
+		}
+		c.regions[alias] = regions
+	}
+	return regions
+}
+
+// Get returns the cached region of the bucket, empty when unknown.
+func (c *bucketRegionCache) Get(alias, bucket string) string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.load(alias)[bucket]
+}
+
+// Set caches the region of the bucket, saving the cache of the alias when
+// the region changed.
+func (c *bucketRegionCache) Set(alias, bucket, region string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	regions := c.load(alias)
+	if regions[bucket] == region {
+		return
+	}
+	regions[bucket] = region
+	buf, e := json.Marshal(regions)
+	if e != nil {
+		return
+	}
+	path := getBucketRegionCachePath(alias)
+	if e = os.MkdirAll(filepath.Dir(path), 0o700); e != nil {
+		return
+	}
+	os.WriteFile(path, buf, 0o600)
+}
+
+// lookupOnce returns true the first time it is called for the bucket.
+func (c *bucketRegionCache) lookupOnce(alias, bucket string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := alias + "/" + bucket
+	if c.lookups[key] {
+		return false
+	}
+	c.lookups[key] = true
+	return true
+}
+
+// Reset forgets the bucket regions of the alias, e.g. when its endpoint changed.
+func (c *bucketRegionCache) Reset(alias string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.regions, alias)
+	os.Remove(getBucketRegionCachePath(alias))
+}
+
+// getBucketRegion returns the region of the requests to bucket, empty to
+// keep the region of the alias. Unknown buckets of AWS S3 are looked up
+// once, the cache is otherwise filled by the redirects of the server.
+func getBucketRegion(config *Config, hostName, bucket string) string {
+	// SigV4A and the custom schemes sign for the regions of the alias.
+	if config.Alias == "" || bucket == "" || config.AuthScheme != "" || strings.EqualFold(config.Signature, "S3v2") {
+		return ""
+	}
+	if region := globalBucketRegions.Get(config.Alias, bucket); region != "" {
+		return region
+	}
+	endpoint := url.URL{Scheme: "https", Host: hostName}
+	if !s3utils.IsAmazonEndpoint(endpoint) || s3utils.IsAmazonFIPSEndpoint(endpoint) ||
+		s3utils.IsAmazonPrivateLinkEndpoint(endpoint) || isAmazonAccelerated(hostName) ||
+		!globalBucketRegions.lookupOnce(config.Alias, bucket) {
+		return ""
+	}
+	region := lookupBucketRegion(config, hostName, bucket)
+	if region != "" {
+		globalBucketRegions.Set(config.Alias, bucket, region)
+	}
+	return region
+}
+
+// lookupBucketRegion returns the region of bucket reported by AWS S3 in
+// x-amz-bucket-region, even to anonymous requests and in redirects.
+func lookupBucketRegion(config *Config, hostName, bucket string) string {
+	ctx, cancel := context.WithTimeout(globalContext, 10*time.Second)
+	defer cancel()
+
+	scheme := newClientURL(config.HostURL).Scheme
+	req, e := http.NewRequestWithContext(ctx, http.MethodHead, scheme+"://"+hostName+"/"+url.PathEscape(bucket), nil)
+	if e != nil {
+		return ""
+	}
+	var transport http.RoundTripper = config.Transport
+	if config.Transport == nil {
+		transport = &http.Transport{
+			Proxy:           config.proxyFunc(http.ProxyFromEnvironment),
+			TLSClientConfig: config.tlsConfig(&tls.Config{RootCAs: globalRootCAs, InsecureSkipVerify: config.Insecure}),
+		}
+	}
+	client := http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, e := client.Do(req)
+	if e != nil {
+		return ""
+	}
+	resp.Body.Close()
+	return resp.Header.Get("x-amz-bucket-region")
+}
+
+// withBucketRegion returns transport caching the bucket regions reported
+// by the server when redirecting, e.g. 301 PermanentRedirect, for the
+// next clients of the alias. Redirected requests without a body are sent
+// again to the region of the bucket, signed with creds when not nil.
+func withBucketRegion(transport http.RoundTripper, alias, endpointHost string, creds *credentials.Credentials) http.RoundTripper {
+	if alias == "" {
+		return transport
+	}
+	endpoint := url.URL{Host: endpointHost}
+	return bucketRegionTransport{transport: transport, alias: alias, endpointHost: endpoint.Hostname(), creds: creds}
+}
+
+type bucketRegionTransport struct {
+	transport    http.RoundTripper
+	alias        string
+	endpointHost string
+	creds        *credentials.Credentials
+}
+
+func (t bucketRegionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		return nil, e
+	}
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusBadRequest:
+		region := resp.Header.Get("x-amz-bucket-region")
+		bucket := requestBucket(req.URL, t.endpointHost)
+		if region == "" || bucket == "" {
+			break
+		}
+		globalBucketRegions.Set(t.alias, bucket, region)
+		if redirected := t.redirect(req, bucket, region); redirected != nil {
+			resp.Body.Close()
+			return t.transport.RoundTrip(redirected)
+		}
+	}
+	return resp, nil
+}
+
+// redirect returns req signed for region and, for AWS S3, sent to the
+// endpoint of the region, nil when the request cannot be sent again.
+func (t bucketRegionTransport) redirect(req *http.Request, bucket, region string) *http.Request {
+	if t.creds == nil || (req.Body != nil && req.Body != http.NoBody) ||
+		strings.Contains(req.Header.Get("Authorization"), "/"+region+"/") {
+		return nil
+	}
+	value, e := t.creds.Get()
+	if e != nil || !value.SignerType.IsV4() {
+		return nil
+	}
+	redirected := req.Clone(req.Context())
+	if endpoint := (url.URL{Host: t.endpointHost}); s3utils.IsAmazonEndpoint(endpoint) &&
+		!s3utils.IsAmazonFIPSEndpoint(endpoint) && !s3utils.IsAmazonPrivateLinkEndpoint(endpoint) {
+		host := "s3.dualstack." + region + ".amazonaws.com"
+		if strings.HasPrefix(region, "cn-") {
+			host += ".cn"
+		}
+		if strings.HasPrefix(req.URL.Hostname(), bucket+".") {
+			host = bucket + "." + host
+		}
+		redirected.URL.Host, redirected.Host = host, host
+	}
+	redirected.Header.Del("Authorization")
+	redirected.Header.Del("X-Amz-Date")
+	return signer.SignV4(*redirected, value.AccessKeyID, value.SecretAccessKey, value.SessionToken, region)
+}
+
+// requestBucket returns the bucket of a request to endpointHost, or to a
+// regional endpoint of AWS S3, in virtual-host or path style.
+func requestBucket(u *url.URL, endpointHost string) string {
+	host := u.Hostname()
+	if i := strings.IndexByte(host, '.'); i > 0 {
+		if rest := host[i+1:]; rest == endpointHost || s3utils.IsAmazonEndpoint(url.URL{Host: rest}) {
+			return host[:i]
+		}
+	}
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	return bucket
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/tls"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRequestBucket(t *testing.T) {
+	testCases := []struct {
+		target, endpointHost string
+		want                 string
+	}{
+		{"https://s3.amazonaws.com/", "s3.amazonaws.com", ""},
+		{"https://s3.amazonaws.com/bucket/object", "s3.amazonaws.com", "bucket"},
+		{"https://bucket.s3.amazonaws.com/object", "s3.amazonaws.com", "bucket"},
+		{"https://bucket.s3.eu-west-1.amazonaws.com/object", "s3.amazonaws.com", "bucket"},
+		{"https://s3.us-west-2.amazonaws.com/bucket", "s3.amazonaws.com", "bucket"},
+		{"https://s3.us-west-2.amazonaws.com/my.bucket/a/b", "s3.us-west-2.amazonaws.com", "my.bucket"},
+		{"http://127.0.0.1:9000/bucket?location", "127.0.0.1", "bucket"},
+		{"https://bucket.minio.example.com/object", "minio.example.com", "bucket"},
+		{"https://minio.example.com/bucket/object", "minio.example.com", "bucket"},
+	}
+	for _, testCase := range testCases {
+		u, e := url.Parse(testCase.target)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if got := requestBucket(u, testCase.endpointHost); got != testCase.want {
+			t.Errorf("%s: expected %q, got %q", testCase.target, testCase.want, got)
+		}
+	}
+}
+
+func TestLookupBucketRegionClientCert(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-amz-bucket-region", "eu-west-1")
+		w.WriteHeader(http.StatusMovedPermanently)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+	cert, e := tls.LoadX509KeyPair(certFile, keyFile)
+	if e != nil {
+		t.Fatal(e)
+	}
+	rootCAs := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	hostName := strings.TrimPrefix(server.URL, "https://")
+
+	testCases := []struct {
+		clientCert *tls.Certificate
+		region     string
+	}{
+		// The server requires the certificate of the alias.
+		{nil, ""},
+		{&cert, "eu-west-1"},
+	}
+	for i, testCase := range testCases {
+		config := &Config{HostURL: server.URL, ClientCert: testCase.clientCert, RootCAs: rootCAs}
+		if region := lookupBucketRegion(config, hostName, "bucket"); region != testCase.region {
+			t.Errorf("Test %d: expected region %q, got %q", i+1, testCase.region, region)
+		}
+	}
+}
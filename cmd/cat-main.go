@@ -75,7 +75,11 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENCRYPT_KEY:   list of comma delimited prefix=secret values
+  MC_AGE_IDENTITY:  file of the age identity decrypting the objects uploaded with --encrypt-with
+
+  The objects uploaded with --compress or --encrypt-with are decompressed
+  and decrypted, --offset and --tail are not supported for them.
 
 EXAMPLES:
   1. Stream an object from Amazon S3 cloud storage to mplayer standard input.
@@ -99,6 +103,9 @@ EXAMPLES:
 
   7. Display the content of a particular object version
      {{.Prompt}} {{.HelpName}} --vid "3ddac055-89a7-40fa-8cd3-530a5581b6b8" play/my-bucket/my-object
+
+  8. Display an object uploaded with 'mc cp --encrypt-with age1...'.
+     {{.Prompt}} MC_AGE_IDENTITY=~/.age/key.txt {{.HelpName}} play/my-bucket/my-object
 `,
 }
 
@@ -208,6 +215,7 @@ func parseCatSyntax(ctx *cli.Context) catOpts {
 // catURL displays contents of a URL to stdout.
 func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixSSEPair, o catOpts) *probe.Error {
 	var reader io.ReadCloser
+	var codec map[string]string
 	size := int64(-1)
 	switch sourceURL {
 	case "-":
@@ -225,6 +233,12 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 			if o.versionID == "" {
 				versionID = content.VersionID
 			}
+			// The offsets of the objects encoded by mc are not the offsets
+			// of the data, whose size is only known once decoded.
+			codec = codecMetadata(content.Metadata)
+			if isCodecEncoded(codec) && (o.startO != 0 || o.tailO != 0) {
+				return probe.NewError(errors.New("--offset and --tail are not supported with objects compressed or encrypted by mc")).Trace(sourceURL)
+			}
 			if o.tailO > 0 && content.Size > 0 {
 				o.startO = content.Size - o.tailO
 				if o.startO < 0 {
@@ -233,7 +247,7 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 				}
 			}
 
-			if client.GetURL().Type == objectStorage {
+			if client.GetURL().Type == objectStorage && !isCodecEncoded(codec) {
 				size = content.Size - o.startO
 				if size < 0 {
 					err := probe.NewError(fmt.Errorf("specified offset (%d) bigger than file (%d)", o.startO, content.Size))
@@ -251,6 +265,14 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 		}); err != nil {
 			return err.Trace(sourceURL)
 		}
+		if isCodecEncoded(codec) {
+			decoded, e := newCodecDecoder(reader, codec)
+			if e != nil {
+				reader.Close()
+				return probe.NewError(e).Trace(sourceURL)
+			}
+			reader = decoded
+		}
 		defer reader.Close()
 	}
 	return catOut(reader, size).Trace(sourceURL)
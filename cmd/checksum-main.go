@@ -0,0 +1,267 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var checksumFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "algo",
+		Value: "sha256",
+		Usage: "checksum algorithm (md5, sha1, sha256, sha512, crc32, crc32c)",
+	},
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "compute the checksums of all the objects of the prefix",
+	},
+}
+
+var checksumCmd = cli.Command{
+	Name:         "checksum",
+	Usage:        "compute or fetch the checksums of objects",
+	Action:       mainChecksum,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(checksumFlags, ioFlags...), globalFlags...),
+	Subcommands: []cli.Command{
+		checksumVerifyCmd,
+	},
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [TARGET...]
+  {{.HelpName}} verify [FLAGS] --manifest FILE TARGET
+
+  The checksums stored by the server are used when available, the objects
+  are streamed to compute them otherwise. The output is in the format of
+  sha256sum and can be verified with '{{.HelpName}} verify'.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Print the SHA-256 checksum of an object.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket/backup.tar
+
+  2. Print the CRC32C checksums of all the objects of a prefix into a manifest.
+     {{.Prompt}} {{.HelpName}} --algo crc32c --recursive myminio/mybucket/dataset/ > sums.txt
+
+  3. Verify a prefix against the manifest.
+     {{.Prompt}} {{.HelpName}} verify --algo crc32c --manifest sums.txt --recursive myminio/mybucket/dataset/
+`,
+}
+
+// checksumMessage container for the checksum of an object
+type checksumMessage struct {
+	Status    string `json:"status"`
+	Key       string `json:"key"`
+	Size      int64  `json:"size"`
+	Algorithm string `json:"algorithm"`
+	Checksum  string `json:"checksum"`
+	// Source is 'server' when the checksum was stored by the server,
+	// 'computed' when the object was streamed.
+	Source string `json:"source"`
+}
+
+// String prints the checksum in the format of sha256sum.
+func (c checksumMessage) String() string {
+	return console.Colorize("Checksum", c.Checksum) + "  " + c.Key
+}
+
+func (c checksumMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checksumHash returns the hash of a checksum algorithm.
+func checksumHash(algorithm string) (hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "md5":
+		return md5.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	case "crc32":
+		return crc32.NewIEEE(), nil
+	case "crc32c":
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	}
+	return nil, fmt.Errorf("unsupported checksum algorithm %s, valid options are [md5, sha1, sha256, sha512, crc32, crc32c]", algorithm)
+}
+
+// serverChecksum returns the checksum stored by the server in hex, the
+// checksums of the multipart objects are checksums of the parts and
+// can't be compared.
+func serverChecksum(content *ClientContent, algorithm string) string {
+	value := content.Checksums[algorithm]
+	if value == "" || strings.Contains(value, "-") {
+		return ""
+	}
+	sum, e := base64.StdEncoding.DecodeString(value)
+	if e != nil {
+		return ""
+	}
+	return hex.EncodeToString(sum)
+}
+
+// objectChecksum fetches the checksum of an object from the server, or
+// computes it. It also returns the stat of the object.
+func objectChecksum(ctx context.Context, alias string, content *ClientContent, algorithm string, encKeyDB map[string][]prefixSSEPair) (sum, source string, st *ClientContent, err *probe.Error) {
+	urlStr := content.URL.String()
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return "", "", nil, err.Trace(urlStr)
+	}
+	sse := getSSE(filepath.Join(alias, content.URL.Path), encKeyDB[alias])
+
+	st, err = clnt.Stat(ctx, StatOptions{sse: sse, versionID: content.VersionID, checksum: true})
+	if err != nil {
+		return "", "", nil, err.Trace(urlStr)
+	}
+	if sum = serverChecksum(st, algorithm); sum != "" {
+		return sum, "server", st, nil
+	}
+
+	h, e := checksumHash(algorithm)
+	if e != nil {
+		return "", "", nil, probe.NewError(e)
+	}
+	reader, err := clnt.Get(ctx, GetOptions{SSE: sse, VersionID: content.VersionID})
+	if err != nil {
+		return "", "", nil, err.Trace(urlStr)
+	}
+	defer reader.Close()
+	if _, e = io.Copy(h, reader); e != nil {
+		return "", "", nil, probe.NewError(e).Trace(urlStr)
+	}
+	return hex.EncodeToString(h.Sum(nil)), "computed", st, nil
+}
+
+// checksumPrefix returns the part of the path of the target which is
+// trimmed from the keys, up to its last separator.
+func checksumPrefix(clnt Client) string {
+	prefix := filepath.ToSlash(clnt.GetURL().Path)
+	return prefix[:strings.LastIndex(prefix, "/")+1]
+}
+
+// listChecksumTargets calls fn with the objects of the target and their
+// keys relative to it.
+func listChecksumTargets(ctx context.Context, targetURL string, recursive bool, fn func(key string, content *ClientContent) *probe.Error) *probe.Error {
+	clnt, err := newClient(targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	prefix := checksumPrefix(clnt)
+
+	if !recursive {
+		content, err := clnt.Stat(ctx, StatOptions{})
+		if err != nil {
+			return err.Trace(targetURL)
+		}
+		if content.Type.IsDir() {
+			return errInvalidArgument().Trace(targetURL)
+		}
+		return fn(strings.TrimPrefix(filepath.ToSlash(content.URL.Path), prefix), content)
+	}
+
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			return content.Err.Trace(targetURL)
+		}
+		if content.Type.IsDir() {
+			continue
+		}
+		if err = fn(strings.TrimPrefix(filepath.ToSlash(content.URL.Path), prefix), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkChecksumSyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if _, e := checksumHash(ctx.String("algo")); e != nil {
+		fatalIf(probe.NewError(e), "Invalid --algo.")
+	}
+}
+
+// mainChecksum is the handle for "mc checksum" command.
+func mainChecksum(cliCtx *cli.Context) error {
+	ctx, cancelChecksum := context.WithCancel(globalContext)
+	defer cancelChecksum()
+
+	checkChecksumSyntax(cliCtx)
+	console.SetColor("Checksum", color.New(color.FgGreen))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	algorithm := strings.ToLower(cliCtx.String("algo"))
+	var cErr error
+	for _, targetURL := range cliCtx.Args() {
+		alias, _, _ := mustExpandAlias(targetURL)
+		err = listChecksumTargets(ctx, targetURL, cliCtx.Bool("recursive"), func(key string, content *ClientContent) *probe.Error {
+			sum, source, _, err := objectChecksum(ctx, alias, content, algorithm, encKeyDB)
+			if err != nil {
+				errorIf(err, "Unable to compute the checksum of `"+key+"`.")
+				cErr = exitStatus(globalErrorExitStatus)
+				return nil
+			}
+			printMsg(checksumMessage{
+				Key:       key,
+				Size:      content.Size,
+				Algorithm: algorithm,
+				Checksum:  sum,
+				Source:    source,
+			})
+			return nil
+		})
+		if err != nil {
+			errorIf(err, "Unable to list `"+targetURL+"`.")
+			cErr = exitStatus(globalErrorExitStatus)
+		}
+	}
+	return cErr
+}
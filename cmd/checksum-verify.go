@@ -0,0 +1,326 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var checksumVerifyFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "manifest",
+		Usage: "file of the expected checksums, in the format of sha256sum, of its --tag option or of cp --write-manifest",
+	},
+	cli.StringFlag{
+		Name:  "algo",
+		Usage: "checksum algorithm of the manifest, guessed from the length of the checksums by default",
+	},
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "verify all the objects of the prefix, reporting the ones not in the manifest",
+	},
+}
+
+var checksumVerifyCmd = cli.Command{
+	Name:         "verify",
+	Usage:        "verify objects against a manifest of checksums",
+	Action:       mainChecksumVerify,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(checksumVerifyFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] --manifest FILE TARGET
+
+  The keys of the manifest are relative to TARGET, up to its last '/'. The
+  keys of the manifests of 'mc cp --write-manifest' start with the bucket,
+  their sizes and versions are verified as well.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Verify the objects listed in a manifest generated by sha256sum.
+     {{.Prompt}} cd dataset && sha256sum * > ../sums.txt
+     {{.Prompt}} {{.HelpName}} --manifest ../sums.txt myminio/mybucket/dataset/
+
+  2. Verify a whole prefix, reporting the objects missing from the manifest.
+     {{.Prompt}} {{.HelpName}} --manifest sums.txt --recursive myminio/mybucket/dataset/
+
+  3. Prove that a bucket still holds the objects uploaded with a manifest.
+     {{.Prompt}} mc cp --recursive --write-manifest manifest.json ./evidence/ myminio/evidence/
+     {{.Prompt}} {{.HelpName}} --manifest manifest.json myminio/evidence
+`,
+}
+
+// verifyCmd is 'mc checksum verify' as a top level command.
+var verifyCmd = func() cli.Command {
+	cmd := checksumVerifyCmd
+	cmd.Name = "verify"
+	return cmd
+}()
+
+// Results of the verification of an object.
+const (
+	checksumOK       = "ok"
+	checksumFailed   = "failed"
+	checksumMissing  = "missing"
+	checksumUnlisted = "unlisted"
+)
+
+// checksumVerifyMessage container for the verification of an object
+type checksumVerifyMessage struct {
+	Status   string `json:"status"`
+	Key      string `json:"key"`
+	Result   string `json:"result"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+func (c checksumVerifyMessage) String() string {
+	switch c.Result {
+	case checksumOK:
+		return c.Key + ": " + console.Colorize("ChecksumOK", "OK")
+	case checksumFailed:
+		return c.Key + ": " + console.Colorize("ChecksumFailed", "FAILED") +
+			fmt.Sprintf(" (expected %s, got %s)", c.Expected, c.Actual)
+	case checksumMissing:
+		return c.Key + ": " + console.Colorize("ChecksumFailed", "MISSING")
+	}
+	return c.Key + ": " + console.Colorize("ChecksumFailed", "NOT IN MANIFEST")
+}
+
+func (c checksumVerifyMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checksumVerifySummaryMessage container for the counts of a verification
+type checksumVerifySummaryMessage struct {
+	Status   string `json:"status"`
+	Verified int    `json:"verified"`
+	Failed   int    `json:"failed"`
+	Missing  int    `json:"missing"`
+	Unlisted int    `json:"unlisted"`
+}
+
+func (c checksumVerifySummaryMessage) String() string {
+	msg := fmt.Sprintf("Verified %d object(s), %d failed, %d missing", c.Verified, c.Failed, c.Missing)
+	if c.Unlisted > 0 {
+		msg += fmt.Sprintf(", %d not in the manifest", c.Unlisted)
+	}
+	if c.Failed+c.Missing+c.Unlisted > 0 {
+		return console.Colorize("ChecksumFailed", msg)
+	}
+	return console.Colorize("ChecksumOK", msg)
+}
+
+func (c checksumVerifySummaryMessage) JSON() string {
+	c.Status = "success"
+	if c.Failed+c.Missing+c.Unlisted > 0 {
+		c.Status = "error"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checksumManifestEntry is an expected checksum of a manifest, the
+// size and the version are only known for the manifests of cp.
+type checksumManifestEntry struct {
+	Key       string
+	Algorithm string
+	Checksum  string
+	Size      *int64
+	VersionID string
+}
+
+// checksumTagRx matches the lines of 'sha256sum --tag'.
+var checksumTagRx = regexp.MustCompile(`^([A-Za-z0-9]+) \((.*)\) = ([0-9a-fA-F]+)$`)
+
+// parseChecksumManifest parses the lines of sha256sum and the like, the
+// algorithm is guessed from the length of the checksums when empty.
+func parseChecksumManifest(r io.Reader, algorithm string) ([]checksumManifestEntry, error) {
+	var entries []checksumManifestEntry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		entry := checksumManifestEntry{Algorithm: algorithm}
+		if m := checksumTagRx.FindStringSubmatch(text); m != nil {
+			entry.Algorithm, entry.Key, entry.Checksum = strings.ToLower(m[1]), m[2], m[3]
+		} else {
+			sum, key, ok := strings.Cut(text, " ")
+			if !ok || len(key) < 2 {
+				return nil, fmt.Errorf("line %d: expected 'CHECKSUM  KEY'", line)
+			}
+			// The second character is ' ' in text mode and '*' in binary mode.
+			entry.Checksum, entry.Key = sum, key[1:]
+		}
+		entry.Checksum = strings.ToLower(entry.Checksum)
+		if entry.Algorithm == "" {
+			switch len(entry.Checksum) {
+			case 32:
+				entry.Algorithm = "md5"
+			case 40:
+				entry.Algorithm = "sha1"
+			case 64:
+				entry.Algorithm = "sha256"
+			case 128:
+				entry.Algorithm = "sha512"
+			default:
+				return nil, fmt.Errorf("line %d: unable to guess the algorithm of the checksum, use --algo", line)
+			}
+		}
+		if _, e := checksumHash(entry.Algorithm); e != nil {
+			return nil, fmt.Errorf("line %d: %w", line, e)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func checkChecksumVerifySyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 || ctx.String("manifest") == "" {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if algorithm := ctx.String("algo"); algorithm != "" {
+		if _, e := checksumHash(algorithm); e != nil {
+			fatalIf(probe.NewError(e), "Invalid --algo.")
+		}
+	}
+}
+
+// mainChecksumVerify is the handle for "mc checksum verify" command.
+func mainChecksumVerify(cliCtx *cli.Context) error {
+	ctx, cancelVerify := context.WithCancel(globalContext)
+	defer cancelVerify()
+
+	checkChecksumVerifySyntax(cliCtx)
+	console.SetColor("ChecksumOK", color.New(color.FgGreen))
+	console.SetColor("ChecksumFailed", color.New(color.FgRed, color.Bold))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	manifestPath := cliCtx.String("manifest")
+	data, e := os.ReadFile(manifestPath)
+	fatalIf(probe.NewError(e), "Unable to open the manifest.")
+	var entries []checksumManifestEntry
+	if isCopyManifest(data) {
+		entries, e = parseCopyManifest(data)
+	} else {
+		entries, e = parseChecksumManifest(bytes.NewReader(data), strings.ToLower(cliCtx.String("algo")))
+	}
+	fatalIf(probe.NewError(e).Trace(manifestPath), "Unable to parse the manifest.")
+
+	targetURL := cliCtx.Args().Get(0)
+	alias, _, _ := mustExpandAlias(targetURL)
+	clnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target.")
+	prefix := checksumPrefix(clnt)
+
+	var summary checksumVerifySummaryMessage
+	report := func(msg checksumVerifyMessage) {
+		switch msg.Result {
+		case checksumOK:
+			summary.Verified++
+		case checksumFailed:
+			summary.Failed++
+		case checksumMissing:
+			summary.Missing++
+		case checksumUnlisted:
+			summary.Unlisted++
+		}
+		printMsg(msg)
+	}
+	verify := func(entry checksumManifestEntry, content *ClientContent) {
+		if entry.VersionID != "" {
+			content.VersionID = entry.VersionID
+		}
+		sum, _, st, err := objectChecksum(ctx, alias, content, entry.Algorithm, encKeyDB)
+		switch {
+		case err != nil && errorExitStatus(err) == exitStatusNotFound:
+			report(checksumVerifyMessage{Key: entry.Key, Result: checksumMissing})
+		case err != nil:
+			fatalIf(err, "Unable to compute the checksum of `"+entry.Key+"`.")
+		case entry.Size != nil && st.Size != *entry.Size:
+			report(checksumVerifyMessage{
+				Key: entry.Key, Result: checksumFailed,
+				Expected: fmt.Sprintf("%d bytes", *entry.Size), Actual: fmt.Sprintf("%d bytes", st.Size),
+			})
+		case sum == entry.Checksum:
+			report(checksumVerifyMessage{Key: entry.Key, Result: checksumOK})
+		default:
+			report(checksumVerifyMessage{Key: entry.Key, Result: checksumFailed, Expected: entry.Checksum, Actual: sum})
+		}
+	}
+
+	if cliCtx.Bool("recursive") {
+		byKey := make(map[string]checksumManifestEntry, len(entries))
+		for _, entry := range entries {
+			byKey[entry.Key] = entry
+		}
+		err = listChecksumTargets(ctx, targetURL, true, func(key string, content *ClientContent) *probe.Error {
+			entry, ok := byKey[key]
+			if !ok {
+				report(checksumVerifyMessage{Key: key, Result: checksumUnlisted})
+				return nil
+			}
+			delete(byKey, key)
+			verify(entry, content)
+			return nil
+		})
+		fatalIf(err, "Unable to list `"+targetURL+"`.")
+		for _, entry := range entries {
+			if _, ok := byKey[entry.Key]; ok {
+				report(checksumVerifyMessage{Key: entry.Key, Result: checksumMissing})
+			}
+		}
+	} else {
+		for _, entry := range entries {
+			u := clnt.GetURL()
+			u.Path = prefix + entry.Key
+			verify(entry, &ClientContent{URL: u})
+		}
+	}
+
+	printMsg(summary)
+	if summary.Failed+summary.Missing+summary.Unlisted > 0 {
+		return exitStatus(exitStatusGeneral)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseChecksumManifest(t *testing.T) {
+	manifest := `# generated by sha256sum
+e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  empty.txt
+d41d8cd98f00b204e9800998ecf8427e *dir/with space.bin
+SHA1 (tagged) = DA39A3EE5E6B4B0D3255BFEF95601890AFD80709
+`
+	entries, e := parseChecksumManifest(strings.NewReader(manifest), "")
+	if e != nil {
+		t.Fatal(e)
+	}
+	expected := []checksumManifestEntry{
+		{Key: "empty.txt", Algorithm: "sha256", Checksum: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{Key: "dir/with space.bin", Algorithm: "md5", Checksum: "d41d8cd98f00b204e9800998ecf8427e"},
+		{Key: "tagged", Algorithm: "sha1", Checksum: "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("entry %d: expected %+v, got %+v", i, expected[i], entries[i])
+		}
+	}
+
+	// CRC32C checksums cannot be told apart from CRC32 ones.
+	if _, e = parseChecksumManifest(strings.NewReader("b8ce48d5  a\n"), ""); e == nil {
+		t.Error("expected an error for a checksum of unknown algorithm")
+	}
+	entries, e = parseChecksumManifest(strings.NewReader("b8ce48d5  a\n"), "crc32c")
+	if e != nil || entries[0].Algorithm != "crc32c" {
+		t.Errorf("unexpected result %+v, %v", entries, e)
+	}
+}
+
+func TestParseCopyManifest(t *testing.T) {
+	manifest := `{
+ "version": "1",
+ "algorithm": "sha256",
+ "objects": [
+  {"key": "evidence/a.txt", "size": 5, "checksum": "2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824", "versionId": "v1"}
+ ]
+}`
+	if !isCopyManifest([]byte(manifest)) || isCopyManifest([]byte("e3b0c442  a.txt\n")) {
+		t.Fatal("unable to tell the manifests of cp from the ones of sha256sum")
+	}
+	entries, e := parseCopyManifest([]byte(manifest))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Key != "evidence/a.txt" || entry.Algorithm != "sha256" || entry.VersionID != "v1" ||
+		entry.Size == nil || *entry.Size != 5 || entry.Checksum != strings.ToLower(entry.Checksum) {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	if _, e = parseCopyManifest([]byte(`{"version": "2", "algorithm": "sha256"}`)); e == nil {
+		t.Error("expected an error for an unsupported manifest version")
+	}
+}
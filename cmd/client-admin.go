@@ -32,7 +32,6 @@ import (
 	"github.com/minio/madmin-go/v2"
 	"github.com/kirolous/mc/pkg/httptracer"
 	"github.com/kirolous/mc/pkg/probe"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
 // NewAdminFactory encloses New function with client cache.
@@ -58,7 +57,10 @@ func NewAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey))
+		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.AuthScheme))
+		if config.Creds != nil {
+			confHash.Write([]byte(fmt.Sprintf("%p", config.Creds)))
+		}
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -67,19 +69,6 @@ func NewAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 		var api *madmin.AdminClient
 		var found bool
 		if api, found = clientCache[confSum]; !found {
-			// Admin API only supports signature v4.
-			creds := credentials.NewStaticV4(config.AccessKey, config.SecretKey, config.SessionToken)
-
-			// Not found. Instantiate a new MinIO
-			var e error
-			api, e = madmin.NewWithOptions(hostName, &madmin.Options{
-				Creds:  creds,
-				Secure: useTLS,
-			})
-			if e != nil {
-				return nil, probe.NewError(e)
-			}
-
 			// Keep TLS config.
 			tlsConfig := &tls.Config{
 				RootCAs: globalRootCAs,
@@ -92,21 +81,41 @@ func NewAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 				tlsConfig.InsecureSkipVerify = true
 			}
 
-			var transport http.RoundTripper = &http.Transport{
+			tr := &http.Transport{
 				Proxy:                 ieproxy.GetProxyFunc(),
 				DialContext:           newCustomDialContext(config),
-				MaxIdleConnsPerHost:   256,
 				IdleConnTimeout:       90 * time.Second,
 				TLSHandshakeTimeout:   10 * time.Second,
 				ExpectContinueTimeout: 10 * time.Second,
+				ResponseHeaderTimeout: config.ResponseHeaderTimeout,
 				TLSClientConfig:       tlsConfig,
 				DisableCompression:    true,
 			}
-			transport = gzhttp.Transport(transport)
+			tuneTransport(tr, config, 256)
+			transport := gzhttp.Transport(tr)
+			transport = withRequestTimeout(transport, config.RequestTimeout)
+			setMaxAttempts(config.MaxAttempts)
+
+			// Admin API only supports signature v4, unless the
+			// alias signs with an auth scheme of its own.
+			creds, transport, e := withAuthScheme(config, configCredentials(config), transport)
+			if e != nil {
+				return nil, probe.NewError(e)
+			}
 
 			if config.Debug {
 				transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
 			}
+			transport = wrapHTTPTrace(transport)
+
+			// Not found. Instantiate a new MinIO
+			api, e = madmin.NewWithOptions(hostName, &madmin.Options{
+				Creds:  creds,
+				Secure: useTLS,
+			})
+			if e != nil {
+				return nil, probe.NewError(e)
+			}
 
 			// Set custom transport.
 			api.SetCustomTransport(transport)
@@ -162,8 +171,14 @@ func newAnonymousClient(aliasedURL string) (*madmin.AnonymousClient, *probe.Erro
 		return nil, probe.NewError(fmt.Errorf("No valid configuration found for '%s' host alias", urlStrFull))
 	}
 
+	return newAnonymousClientFromConfig(NewS3Config(urlStrFull, aliasCfg))
+}
+
+// newAnonymousClientFromConfig returns an anonymous client of the host of
+// config, with its TLS settings, timeouts and proxy.
+func newAnonymousClientFromConfig(config *Config) (*madmin.AnonymousClient, *probe.Error) {
 	// Creates a parsed URL.
-	targetURL, e := url.Parse(urlStrFull)
+	targetURL, e := url.Parse(config.HostURL)
 	if e != nil {
 		return nil, probe.NewError(e)
 	}
@@ -188,20 +203,21 @@ func newAnonymousClient(aliasedURL string) (*madmin.AnonymousClient, *probe.Erro
 		// Can't use TLSv1.1 because of RC4 cipher usage
 		MinVersion: tls.VersionTLS12,
 	}
-	if globalInsecure {
+	if config.Insecure {
 		tlsConfig.InsecureSkipVerify = true
 	}
+
 	// Set custom transport
-	var transport http.RoundTripper = &http.Transport{
+	tr := &http.Transport{
 		Proxy: ieproxy.GetProxyFunc(),
 		DialContext: (&net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 15 * time.Second,
+			Timeout:   config.connectTimeout(),
+			KeepAlive: config.tcpKeepAlive(),
 		}).DialContext,
-		MaxIdleConnsPerHost:   256,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 10 * time.Second,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
 		TLSClientConfig:       tlsConfig,
 		// Set this value so that the underlying transport round-tripper
 		// doesn't try to auto decode the body of objects with
@@ -211,9 +227,13 @@ func newAnonymousClient(aliasedURL string) (*madmin.AnonymousClient, *probe.Erro
 		//    https://golang.org/src/net/http/transport.go?h=roundTrip#L1843
 		DisableCompression: true,
 	}
-	if globalDebug {
+	tuneTransport(tr, config, 256)
+	transport := withRequestTimeout(tr, config.RequestTimeout)
+	setMaxAttempts(config.MaxAttempts)
+	if config.Debug {
 		transport = httptracer.GetNewTraceTransport(newTraceV4(), transport)
 	}
+	transport = wrapHTTPTrace(transport)
 	anonClient.SetCustomTransport(transport)
 
 	return anonClient, nil
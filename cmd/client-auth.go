@@ -0,0 +1,310 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+// Signatures of 'mc alias set --signature'.
+const (
+	signatureV4     = "v4"
+	signatureV2     = "v2"
+	signatureV4A    = "v4a"
+	signatureSPNEGO = "spnego"
+)
+
+// authScheme authenticates the requests of the aliases whose signature is
+// not one of the S3 signatures of the SDK. The SDK sends the requests of
+// these aliases unsigned, the transport returned by wrap signs them.
+type authScheme interface {
+	wrap(config *Config, transport http.RoundTripper) (http.RoundTripper, error)
+}
+
+var authSchemes = map[string]authScheme{
+	signatureV4A:    sigV4AScheme{},
+	signatureSPNEGO: spnegoScheme{},
+}
+
+// validSignatures returns the values of --signature.
+func validSignatures() []string {
+	signatures := []string{signatureV4, signatureV2}
+	for name := range authSchemes {
+		signatures = append(signatures, name)
+	}
+	sort.Strings(signatures[2:])
+	return signatures
+}
+
+// aliasSignature returns the API signature and the auth scheme of an alias
+// set with --signature. Auth schemes sign the requests of the S3 API.
+func aliasSignature(signature string) (api, scheme string) {
+	switch signature {
+	case signatureV2:
+		return "S3v2", ""
+	case signatureV4:
+		return "S3v4", ""
+	}
+	return "S3v4", signature
+}
+
+// withAuthScheme returns the credentials given to the SDK and the transport
+// of config, both unchanged unless the alias uses an auth scheme.
+func withAuthScheme(config *Config, creds *credentials.Credentials, transport http.RoundTripper) (*credentials.Credentials, http.RoundTripper, error) {
+	scheme, ok := authSchemes[config.AuthScheme]
+	if !ok {
+		return creds, transport, nil
+	}
+	transport, e := scheme.wrap(config, transport)
+	if e != nil {
+		return nil, nil, e
+	}
+	return credentials.NewStaticV4("", "", ""), transport, nil
+}
+
+// configCredentials returns the credentials of config.
+func configCredentials(config *Config) *credentials.Credentials {
+	if config.Creds != nil {
+		return config.Creds
+	}
+	return credentials.NewStaticV4(config.AccessKey, config.SecretKey, config.SessionToken)
+}
+
+const (
+	sigV4AAlgorithm   = "AWS4-ECDSA-P256-SHA256"
+	emptySHA256       = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	unsignedPayload   = "UNSIGNED-PAYLOAD"
+	sigV4ATimeFormat  = "20060102T150405Z"
+	sigV4ADateFormat  = "20060102"
+	sigV4AServiceName = "s3"
+)
+
+// sigV4AScheme signs with SigV4A, the asymmetric signature valid in a set
+// of regions required by the multi-region access points of AWS S3.
+type sigV4AScheme struct{}
+
+func (sigV4AScheme) wrap(config *Config, transport http.RoundTripper) (http.RoundTripper, error) {
+	regionSet := os.Getenv("MC_REGION")
+	if regionSet == "" {
+		regionSet = "*"
+	}
+	return &sigV4ATransport{
+		transport: transport,
+		creds:     configCredentials(config),
+		regionSet: regionSet,
+		keys:      map[string]*ecdsa.PrivateKey{},
+	}, nil
+}
+
+type sigV4ATransport struct {
+	transport http.RoundTripper
+	creds     *credentials.Credentials
+	regionSet string
+
+	mu   sync.Mutex
+	keys map[string]*ecdsa.PrivateKey
+}
+
+// key returns the signing key of the access key, it is derived once.
+func (t *sigV4ATransport) key(accessKey, secretKey string) (*ecdsa.PrivateKey, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if key, ok := t.keys[accessKey+"\x00"+secretKey]; ok {
+		return key, nil
+	}
+	key, e := deriveSigV4AKey(accessKey, secretKey)
+	if e != nil {
+		return nil, e
+	}
+	t.keys[accessKey+"\x00"+secretKey] = key
+	return key, nil
+}
+
+func (t *sigV4ATransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	value, e := t.creds.Get()
+	if e != nil {
+		return nil, e
+	}
+	key, e := t.key(value.AccessKeyID, value.SecretAccessKey)
+	if e != nil {
+		return nil, e
+	}
+	req = req.Clone(req.Context())
+	signSigV4A(req, key, value.AccessKeyID, value.SessionToken, t.regionSet, time.Now().UTC())
+	return t.transport.RoundTrip(req)
+}
+
+// deriveSigV4AKey derives the ECDSA P-256 key of an access key pair, with
+// the NIST SP 800-108 HMAC-SHA256 counter mode KDF as AWS does.
+func deriveSigV4AKey(accessKey, secretKey string) (*ecdsa.PrivateKey, error) {
+	curve := elliptic.P256()
+	params := curve.Params()
+	nMinusTwo := new(big.Int).Sub(params.N, big.NewInt(2))
+
+	inputKey := []byte("AWS4A" + secretKey)
+	for counter := 1; counter <= 0xff; counter++ {
+		context := append([]byte(accessKey), byte(counter))
+
+		var fixedInput bytes.Buffer
+		fixedInput.WriteString(sigV4AAlgorithm)
+		fixedInput.WriteByte(0)
+		fixedInput.Write(context)
+		binary.Write(&fixedInput, binary.BigEndian, int32(params.BitSize))
+
+		// A single iteration of the KDF gives the 256 bits of a candidate.
+		h := hmac.New(sha256.New, inputKey)
+		binary.Write(h, binary.BigEndian, int32(1))
+		h.Write(fixedInput.Bytes())
+		candidate := new(big.Int).SetBytes(h.Sum(nil))
+
+		if candidate.Cmp(nMinusTwo) < 0 {
+			d := candidate.Add(candidate, big.NewInt(1))
+			key := &ecdsa.PrivateKey{D: d}
+			key.PublicKey.Curve = curve
+			key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d.FillBytes(make([]byte, 32)))
+			return key, nil
+		}
+	}
+	return nil, errors.New("unable to derive the SigV4A key of the access key")
+}
+
+// signSigV4A adds the SigV4A authorization of req.
+func signSigV4A(req *http.Request, key *ecdsa.PrivateKey, accessKey, sessionToken, regionSet string, now time.Time) {
+	amzDate := now.Format(sigV4ATimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Region-Set", regionSet)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		payloadHash = unsignedPayload
+		if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+			payloadHash = emptySHA256
+		}
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	stringToSign, scope, signedHeaders := sigV4AStringToSign(req, now)
+	digest := sha256.Sum256([]byte(stringToSign))
+	signature, e := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if e != nil {
+		// Only fails when the random source does, the server rejects the request.
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4AAlgorithm, accessKey, scope, signedHeaders, hex.EncodeToString(signature)))
+}
+
+// sigV4AStringToSign returns the string to sign of req, its credential scope
+// and its signed headers. The query of req is canonicalized in place.
+func sigV4AStringToSign(req *http.Request, now time.Time) (stringToSign, scope, signedHeaders string) {
+	canonicalRequest, signedHeaders := sigV4CanonicalRequest(req)
+	scope = now.Format(sigV4ADateFormat) + "/" + sigV4AServiceName + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign = sigV4AAlgorithm + "\n" + now.Format(sigV4ATimeFormat) + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+	return stringToSign, scope, signedHeaders
+}
+
+// sigV4CanonicalRequest returns the canonical request of req and its
+// signed headers, the same for SigV4 and SigV4A. The query of req is
+// canonicalized in place.
+func sigV4CanonicalRequest(req *http.Request) (canonicalRequest, signedHeaders string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" || name == "content-md5" {
+			trimmed := make([]string, len(values))
+			for i, value := range values {
+				trimmed[i] = strings.Join(strings.Fields(value), " ")
+			}
+			headers[name] = strings.Join(trimmed, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders = strings.Join(names, ";")
+
+	req.URL.RawQuery = strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20")
+	canonicalRequest = strings.Join([]string{
+		req.Method,
+		s3utils.EncodePath(req.URL.Path),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	return canonicalRequest, signedHeaders
+}
+
+// spnegoScheme authenticates with Kerberos, for gateways in front of S3
+// requiring SPNEGO. The access key is the principal, the secret key is
+// "kerberos" for the ticket cache or "keytab:PATH".
+type spnegoScheme struct{}
+
+func (spnegoScheme) wrap(config *Config, transport http.RoundTripper) (http.RoundTripper, error) {
+	krbClient, e := kerberosClient(config.AccessKey, strings.TrimSpace(config.SecretKey))
+	if e != nil {
+		return nil, e
+	}
+	return spnegoTransport{transport: transport, client: krbClient}, nil
+}
+
+type spnegoTransport struct {
+	transport http.RoundTripper
+	client    *client.Client
+}
+
+func (t spnegoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if e := spnego.SetSPNEGOHeader(t.client, req, ""); e != nil {
+		return nil, e
+	}
+	return t.transport.RoundTrip(req)
+}
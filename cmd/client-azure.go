@@ -0,0 +1,443 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+const (
+	// azureAPIVersion is the version of the Blob service REST API.
+	azureAPIVersion = "2020-10-02"
+	// azureBlockSize is the size of the blocks of the large uploads.
+	azureBlockSize = 16 << 20
+	// azureSinglePutSize is the largest upload sent in a single request.
+	azureSinglePutSize = 64 << 20
+)
+
+// azureStore is a blobStore of Azure Blob Storage, the buckets are the
+// containers of the account. Requests are signed with the shared key of
+// the account, or carry a SAS token when the secret key starts with '?'.
+type azureStore struct {
+	endpoint *url.URL
+	account  string
+	key      []byte
+	sas      url.Values
+	client   *http.Client
+}
+
+// azureNew returns a client of an Azure Blob Storage alias, the access key
+// is the account name and the secret key the account key or a SAS token.
+func azureNew(config *Config) (Client, *probe.Error) {
+	targetURL := newClientURL(config.HostURL)
+	endpoint, e := url.Parse(targetURL.Scheme + "://" + targetURL.Host)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	store := &azureStore{
+		endpoint: endpoint,
+		account:  config.AccessKey,
+		client:   &http.Client{Transport: newBlobTransport(config)},
+	}
+	if store.account == "" {
+		store.account = strings.Split(targetURL.Host, ".")[0]
+	}
+	if strings.HasPrefix(config.SecretKey, "?") {
+		if store.sas, e = url.ParseQuery(config.SecretKey[1:]); e != nil {
+			return nil, probe.NewError(e)
+		}
+	} else if config.SecretKey != "" {
+		if store.key, e = base64.StdEncoding.DecodeString(config.SecretKey); e != nil {
+			return nil, probe.NewError(fmt.Errorf("invalid account key: %w", e))
+		}
+	}
+	return &blobClient{store: store, targetURL: targetURL}, nil
+}
+
+func (s *azureStore) kind() string {
+	return "azure"
+}
+
+// sign computes the shared key signature of a request.
+func (s *azureStore) sign(req *http.Request) {
+	var headers []string
+	for k := range req.Header {
+		if k = strings.ToLower(k); strings.HasPrefix(k, "x-ms-") {
+			headers = append(headers, k)
+		}
+	}
+	sort.Strings(headers)
+	var canonicalHeaders strings.Builder
+	for _, k := range headers {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(req.Header.Get(k)) + "\n")
+	}
+
+	var resource strings.Builder
+	resource.WriteString("/" + s.account + req.URL.EscapedPath())
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		resource.WriteString("\n" + strings.ToLower(k) + ":" + strings.Join(values, ","))
+	}
+
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is used instead.
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalHeaders.String() + resource.String(),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(stringToSign))
+	req.Header.Set("Authorization", "SharedKey "+s.account+":"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+// do sends a request to a container or a blob, the responses with an
+// unexpected status are returned as errors.
+func (s *azureStore) do(ctx context.Context, method, container, blob string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = "/" + container
+	if blob != "" {
+		u.Path += "/" + blob
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	for k, v := range s.sas {
+		query[k] = v
+	}
+	u.RawQuery = query.Encode()
+
+	req, e := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if e != nil {
+		return nil, e
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	if s.sas == nil {
+		s.sign(req)
+	}
+
+	resp, e := s.client.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	var azErr struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&azErr)
+	if azErr.Code == "" {
+		azErr.Code = resp.Header.Get("x-ms-error-code")
+	}
+	switch {
+	case azErr.Code == "ContainerNotFound":
+		return nil, errBlobBucketNotFound
+	case resp.StatusCode == http.StatusNotFound:
+		// HEAD responses have no body to tell the code.
+		return nil, errBlobNotFound
+	}
+	if azErr.Message == "" {
+		azErr.Message = resp.Status
+	}
+	return nil, fmt.Errorf("%s: %s", azErr.Code, strings.SplitN(azErr.Message, "\n", 2)[0])
+}
+
+func (s *azureStore) listBuckets(ctx context.Context) ([]blobInfo, error) {
+	var buckets []blobInfo
+	marker := ""
+	for {
+		query := url.Values{"comp": {"list"}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, e := s.do(ctx, http.MethodGet, "", "", query, nil, nil, 0)
+		if e != nil {
+			return nil, e
+		}
+		var result struct {
+			Containers []struct {
+				Name         string `xml:"Name"`
+				LastModified string `xml:"Properties>Last-Modified"`
+			} `xml:"Containers>Container"`
+			NextMarker string `xml:"NextMarker"`
+		}
+		e = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if e != nil {
+			return nil, e
+		}
+		for _, c := range result.Containers {
+			t, _ := time.Parse(http.TimeFormat, c.LastModified)
+			buckets = append(buckets, blobInfo{Name: c.Name, ModTime: t, IsDir: true})
+		}
+		if marker = result.NextMarker; marker == "" {
+			return buckets, nil
+		}
+	}
+}
+
+func (s *azureStore) makeBucket(ctx context.Context, bucket string) error {
+	resp, e := s.do(ctx, http.MethodPut, bucket, "", url.Values{"restype": {"container"}}, nil, nil, 0)
+	if e != nil {
+		return e
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *azureStore) removeBucket(ctx context.Context, bucket string) error {
+	resp, e := s.do(ctx, http.MethodDelete, bucket, "", url.Values{"restype": {"container"}}, nil, nil, 0)
+	if e != nil {
+		return e
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *azureStore) list(ctx context.Context, bucket, prefix string, delimited bool, fn func(blobInfo) error) error {
+	marker := ""
+	for {
+		query := url.Values{
+			"restype": {"container"},
+			"comp":    {"list"},
+			"include": {"metadata"},
+		}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if delimited {
+			query.Set("delimiter", "/")
+		}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		resp, e := s.do(ctx, http.MethodGet, bucket, "", query, nil, nil, 0)
+		if e != nil {
+			return e
+		}
+		var result struct {
+			Blobs []struct {
+				Name       string `xml:"Name"`
+				Properties struct {
+					LastModified  string `xml:"Last-Modified"`
+					ContentLength int64  `xml:"Content-Length"`
+					ContentType   string `xml:"Content-Type"`
+					ETag          string `xml:"Etag"`
+				} `xml:"Properties"`
+				Metadata azureMetadata `xml:"Metadata"`
+			} `xml:"Blobs>Blob"`
+			Prefixes []struct {
+				Name string `xml:"Name"`
+			} `xml:"Blobs>BlobPrefix"`
+			NextMarker string `xml:"NextMarker"`
+		}
+		e = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if e != nil {
+			return e
+		}
+		for _, p := range result.Prefixes {
+			if e = fn(blobInfo{Name: p.Name, IsDir: true}); e != nil {
+				return e
+			}
+		}
+		for _, b := range result.Blobs {
+			t, _ := time.Parse(http.TimeFormat, b.Properties.LastModified)
+			info := blobInfo{
+				Name:        b.Name,
+				Size:        b.Properties.ContentLength,
+				ModTime:     t,
+				ETag:        strings.Trim(b.Properties.ETag, `"`),
+				ContentType: b.Properties.ContentType,
+				Metadata:    b.Metadata,
+			}
+			if e = fn(info); e != nil {
+				return e
+			}
+		}
+		if marker = result.NextMarker; marker == "" {
+			return nil
+		}
+	}
+}
+
+// azureMetadata decodes the metadata elements of a blob listing.
+type azureMetadata map[string]string
+
+func (m *azureMetadata) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*m = azureMetadata{}
+	for {
+		tok, e := d.Token()
+		if e != nil {
+			return e
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var value string
+			if e = d.DecodeElement(&value, &t); e != nil {
+				return e
+			}
+			(*m)[strings.ToLower(t.Name.Local)] = value
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (s *azureStore) stat(ctx context.Context, bucket, object string) (blobInfo, error) {
+	resp, e := s.do(ctx, http.MethodHead, bucket, object, nil, nil, nil, 0)
+	if e != nil {
+		return blobInfo{}, e
+	}
+	resp.Body.Close()
+	t, _ := time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified"))
+	info := blobInfo{
+		Name:        object,
+		Size:        resp.ContentLength,
+		ModTime:     t,
+		ETag:        strings.Trim(resp.Header.Get("ETag"), `"`),
+		ContentType: resp.Header.Get("Content-Type"),
+		Metadata:    map[string]string{},
+	}
+	for k := range resp.Header {
+		if lk := strings.ToLower(k); strings.HasPrefix(lk, "x-ms-meta-") {
+			info.Metadata[strings.TrimPrefix(lk, "x-ms-meta-")] = resp.Header.Get(k)
+		}
+	}
+	return info, nil
+}
+
+func (s *azureStore) get(ctx context.Context, bucket, object string, offset int64) (io.ReadCloser, error) {
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("x-ms-range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, e := s.do(ctx, http.MethodGet, bucket, object, nil, header, nil, 0)
+	if e != nil {
+		return nil, e
+	}
+	return resp.Body, nil
+}
+
+// put uploads a blob in a single request when small enough, otherwise
+// as a list of blocks.
+func (s *azureStore) put(ctx context.Context, bucket, object string, reader io.Reader, size int64, contentType string, metadata map[string]string) error {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("x-ms-blob-content-type", contentType)
+	}
+	for k, v := range metadata {
+		header.Set("x-ms-meta-"+k, v)
+	}
+
+	if size >= 0 && size <= azureSinglePutSize {
+		header.Set("x-ms-blob-type", "BlockBlob")
+		resp, e := s.do(ctx, http.MethodPut, bucket, object, nil, header, io.LimitReader(reader, size), size)
+		if e != nil {
+			return e
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	var blockList bytes.Buffer
+	blockList.WriteString(`<?xml version="1.0" encoding="utf-8"?><BlockList>`)
+	buf := make([]byte, azureBlockSize)
+	for i := 0; ; i++ {
+		n, e := io.ReadFull(reader, buf)
+		if n == 0 && i > 0 {
+			break
+		}
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return e
+		}
+		id := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%010d", i)))
+		resp, err := s.do(ctx, http.MethodPut, bucket, object, url.Values{"comp": {"block"}, "blockid": {id}}, nil, bytes.NewReader(buf[:n]), int64(n))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		blockList.WriteString("<Latest>" + id + "</Latest>")
+		if e != nil {
+			break
+		}
+	}
+	blockList.WriteString("</BlockList>")
+	resp, e := s.do(ctx, http.MethodPut, bucket, object, url.Values{"comp": {"blocklist"}}, header, bytes.NewReader(blockList.Bytes()), int64(blockList.Len()))
+	if e != nil {
+		return e
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *azureStore) remove(ctx context.Context, bucket, object string) error {
+	resp, e := s.do(ctx, http.MethodDelete, bucket, object, nil, nil, nil, 0)
+	if e != nil {
+		return e
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,588 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kirolous/mc/pkg/hookreader"
+	"github.com/kirolous/mc/pkg/limiter"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/replication"
+)
+
+// blobPartialSuffix names the files being uploaded to the filesystem like
+// stores, they are renamed once complete so that readers never see
+// partial files.
+const blobPartialSuffix = ".mc-partial"
+
+// Errors of the blob stores, mapped to the errors of mc by blobClient.
+var (
+	errBlobBucketNotFound = errors.New("bucket not found")
+	errBlobNotFound       = errors.New("object not found")
+)
+
+// blobInfo describes a bucket, an object or a common prefix of a blob store.
+type blobInfo struct {
+	// Name is the bucket name, the object key or the prefix ending with '/'.
+	Name        string
+	Size        int64
+	ModTime     time.Time
+	ETag        string
+	ContentType string
+	Metadata    map[string]string
+	IsDir       bool
+}
+
+// blobStore is implemented by the backends which do not speak the S3
+// API, blobClient maps the operations of mc to them.
+type blobStore interface {
+	// kind names the backend in the errors.
+	kind() string
+	listBuckets(ctx context.Context) ([]blobInfo, error)
+	makeBucket(ctx context.Context, bucket string) error
+	removeBucket(ctx context.Context, bucket string) error
+	// list calls fn for the objects with the prefix, or for the objects and
+	// the common prefixes right below it when delimited.
+	list(ctx context.Context, bucket, prefix string, delimited bool, fn func(blobInfo) error) error
+	stat(ctx context.Context, bucket, object string) (blobInfo, error)
+	get(ctx context.Context, bucket, object string, offset int64) (io.ReadCloser, error)
+	put(ctx context.Context, bucket, object string, reader io.Reader, size int64, contentType string, metadata map[string]string) error
+	remove(ctx context.Context, bucket, object string) error
+}
+
+// blobClient implements the Client interface of mc on top of a blob store.
+type blobClient struct {
+	store     blobStore
+	targetURL *ClientURL
+}
+
+// newBlobTransport returns the HTTP transport of the blob stores, it honors
+// the TLS and bandwidth settings of mc.
+func newBlobTransport(config *Config) http.RoundTripper {
+	tr := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           newCustomDialContext(config),
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 10 * time.Second,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		DisableCompression:    true,
+		// Azure and GCS serve HTTP/2, use it as their SDKs do.
+		ForceAttemptHTTP2: true,
+		TLSClientConfig: &tls.Config{
+			RootCAs:            globalRootCAs,
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: config.Insecure,
+		},
+	}
+	tuneTransport(tr, config, 1024)
+	transport := withRequestTimeout(limiter.New(config.UploadLimit, config.DownloadLimit, tr), config.RequestTimeout)
+	return wrapHTTPTrace(transport)
+}
+
+// url2BucketAndObject returns the bucket and the object of the target.
+func (c *blobClient) url2BucketAndObject() (bucket, object string) {
+	return c.splitPath(c.targetURL.Path)
+}
+
+func (c *blobClient) splitPath(p string) (bucket, object string) {
+	p = strings.TrimPrefix(p, "/")
+	if i := strings.Index(p, "/"); i >= 0 {
+		return p[:i], p[i+1:]
+	}
+	return p, ""
+}
+
+// toClientError maps the errors of the store to the errors of mc.
+func (c *blobClient) toClientError(e error, bucket, object string) *probe.Error {
+	switch {
+	case errors.Is(e, errBlobBucketNotFound):
+		return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+	case errors.Is(e, errBlobNotFound):
+		return probe.NewError(ObjectMissing{})
+	}
+	return probe.NewError(e)
+}
+
+func (c *blobClient) notImplemented(api string) *probe.Error {
+	return probe.NewError(APINotImplemented{API: api, APIType: c.store.kind()})
+}
+
+func (c *blobClient) content(bucket string, info blobInfo) *ClientContent {
+	url := c.targetURL.Clone()
+	url.Path = "/" + path.Join(bucket, info.Name)
+	content := &ClientContent{
+		URL:          url,
+		BucketName:   bucket,
+		Time:         info.ModTime,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		Type:         os.FileMode(0o664),
+		Metadata:     map[string]string{},
+		UserMetadata: info.Metadata,
+	}
+	if info.ContentType != "" {
+		content.Metadata["Content-Type"] = info.ContentType
+	}
+	if info.IsDir || info.Name == "" {
+		content.Type = os.ModeDir
+		if !strings.HasSuffix(url.Path, "/") {
+			content.URL.Path += "/"
+		}
+	}
+	return content
+}
+
+// Stat returns the attributes of an object, of a prefix or of a bucket.
+func (c *blobClient) Stat(ctx context.Context, _ StatOptions) (*ClientContent, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return c.content("", blobInfo{IsDir: true}), nil
+	}
+	if object == "" || strings.HasSuffix(object, "/") {
+		found := object == ""
+		if object == "" {
+			if e := c.store.list(ctx, bucket, "", true, func(blobInfo) error { return io.EOF }); e != nil && e != io.EOF {
+				return nil, c.toClientError(e, bucket, object).Trace(c.targetURL.String())
+			}
+		} else {
+			e := c.store.list(ctx, bucket, object, false, func(blobInfo) error {
+				found = true
+				return io.EOF
+			})
+			if e != nil && e != io.EOF {
+				return nil, c.toClientError(e, bucket, object).Trace(c.targetURL.String())
+			}
+		}
+		if !found {
+			return nil, probe.NewError(PathNotFound{Path: c.targetURL.Path})
+		}
+		return c.content(bucket, blobInfo{Name: object, IsDir: true}), nil
+	}
+
+	info, e := c.store.stat(ctx, bucket, object)
+	if e == nil {
+		info.Name = object
+		return c.content(bucket, info), nil
+	}
+	if !errors.Is(e, errBlobNotFound) {
+		return nil, c.toClientError(e, bucket, object).Trace(c.targetURL.String())
+	}
+	// Not an object, maybe a prefix.
+	found := false
+	e = c.store.list(ctx, bucket, object+"/", false, func(blobInfo) error {
+		found = true
+		return io.EOF
+	})
+	if e != nil && e != io.EOF {
+		return nil, c.toClientError(e, bucket, object).Trace(c.targetURL.String())
+	}
+	if !found {
+		return nil, probe.NewError(ObjectMissing{}).Trace(c.targetURL.String())
+	}
+	return c.content(bucket, blobInfo{Name: object + "/", IsDir: true}), nil
+}
+
+// List lists the buckets, or the objects below the target.
+func (c *blobClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		send := func(content *ClientContent) error {
+			select {
+			case contentCh <- content:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		bucket, object := c.url2BucketAndObject()
+		if bucket == "" {
+			buckets, e := c.store.listBuckets(ctx)
+			if e != nil {
+				send(&ClientContent{Err: c.toClientError(e, bucket, object)})
+				return
+			}
+			for _, b := range buckets {
+				if !opts.Recursive {
+					if send(c.content(b.Name, blobInfo{ModTime: b.ModTime, IsDir: true})) != nil {
+						return
+					}
+					continue
+				}
+				c.listObjects(ctx, b.Name, "", opts, send)
+			}
+			return
+		}
+
+		if object != "" && !strings.HasSuffix(object, "/") {
+			// An object is listed as itself, a prefix as a directory
+			// unless the listing is recursive.
+			content, err := c.Stat(ctx, StatOptions{})
+			if err != nil {
+				send(&ClientContent{Err: err})
+				return
+			}
+			if !content.Type.IsDir() || !opts.Recursive {
+				if !content.Type.IsDir() || opts.ShowDir != DirNone {
+					send(content)
+				}
+				return
+			}
+			object += "/"
+		}
+		c.listObjects(ctx, bucket, object, opts, send)
+	}()
+	return contentCh
+}
+
+func (c *blobClient) listObjects(ctx context.Context, bucket, prefix string, opts ListOptions, send func(*ClientContent) error) {
+	count := 0
+	e := c.store.list(ctx, bucket, prefix, !opts.Recursive, func(info blobInfo) error {
+		if info.IsDir && opts.ShowDir == DirNone && opts.Recursive {
+			return nil
+		}
+		if opts.Count > 0 && count >= opts.Count {
+			return io.EOF
+		}
+		count++
+		return send(c.content(bucket, info))
+	})
+	if e != nil && e != io.EOF && !errors.Is(e, context.Canceled) {
+		send(&ClientContent{Err: c.toClientError(e, bucket, prefix)})
+	}
+}
+
+// MakeBucket creates a bucket, or a prefix with an empty marker object.
+func (c *blobClient) MakeBucket(ctx context.Context, _ string, ignoreExisting, _ bool) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if object != "" {
+		if !strings.HasSuffix(object, "/") {
+			object += "/"
+		}
+		if e := c.store.put(ctx, bucket, object, strings.NewReader(""), 0, "", nil); e != nil {
+			return c.toClientError(e, bucket, object)
+		}
+		return nil
+	}
+	if e := c.store.makeBucket(ctx, bucket); e != nil {
+		if ignoreExisting {
+			if _, err := c.Stat(ctx, StatOptions{}); err == nil {
+				return nil
+			}
+		}
+		return c.toClientError(e, bucket, "")
+	}
+	return nil
+}
+
+// RemoveBucket removes a bucket, with its objects when forced.
+func (c *blobClient) RemoveBucket(ctx context.Context, forceRemove bool) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" || object != "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if forceRemove {
+		e := c.store.list(ctx, bucket, "", false, func(info blobInfo) error {
+			return c.store.remove(ctx, bucket, info.Name)
+		})
+		if e != nil {
+			return c.toClientError(e, bucket, "")
+		}
+	}
+	if e := c.store.removeBucket(ctx, bucket); e != nil {
+		return c.toClientError(e, bucket, "")
+	}
+	return nil
+}
+
+// ListBuckets lists the buckets of the store.
+func (c *blobClient) ListBuckets(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	buckets, e := c.store.listBuckets(ctx)
+	if e != nil {
+		return nil, c.toClientError(e, "", "")
+	}
+	contents := make([]*ClientContent, 0, len(buckets))
+	for _, b := range buckets {
+		contents = append(contents, c.content(b.Name, blobInfo{ModTime: b.ModTime, IsDir: true}))
+	}
+	return contents, nil
+}
+
+// Get returns a reader of the object from the given offset.
+func (c *blobClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	reader, e := c.store.get(ctx, bucket, object, opts.RangeStart)
+	if e != nil {
+		return nil, c.toClientError(e, bucket, object).Trace(c.targetURL.String())
+	}
+	return reader, nil
+}
+
+// countReader counts the bytes read.
+type countReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, e := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, e
+}
+
+// Put uploads an object, large and unknown sizes are uploaded in parts
+// by the stores.
+func (c *blobClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return 0, probe.NewError(ObjectNameEmpty{})
+	}
+	contentType := ""
+	metadata := map[string]string{}
+	for k, v := range opts.metadata {
+		switch {
+		case strings.EqualFold(k, "Content-Type"):
+			contentType = v
+		case strings.HasPrefix(strings.ToLower(k), "x-amz-meta-"):
+			metadata[strings.ToLower(k[len("x-amz-meta-"):])] = v
+		}
+	}
+	cr := &countReader{Reader: hookreader.NewHook(reader, progress)}
+	if e := c.store.put(ctx, bucket, object, cr, size, contentType, metadata); e != nil {
+		return cr.n, c.toClientError(e, bucket, object).Trace(c.targetURL.String())
+	}
+	return cr.n, nil
+}
+
+// Copy copies an object of the same alias through mc.
+func (c *blobClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	srcBucket, srcObject := c.splitPath(source)
+	reader, e := c.store.get(ctx, srcBucket, srcObject, 0)
+	if e != nil {
+		return c.toClientError(e, srcBucket, srcObject).Trace(source)
+	}
+	defer reader.Close()
+	_, err := c.Put(ctx, reader, opts.size, progress, PutOptions{metadata: opts.metadata})
+	return err
+}
+
+// Remove removes the objects, or the buckets when requested.
+func (c *blobClient) Remove(ctx context.Context, _, isRemoveBucket, _, _ bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	resultCh := make(chan RemoveResult)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			bucket, object := c.splitPath(content.URL.Path)
+			var e error
+			switch {
+			case object == "" && isRemoveBucket:
+				e = c.store.removeBucket(ctx, bucket)
+			case object == "":
+			default:
+				e = c.store.remove(ctx, bucket, object)
+				if errors.Is(e, errBlobNotFound) && strings.HasSuffix(object, "/") {
+					// Prefixes without a marker object.
+					e = nil
+				}
+			}
+			result := RemoveResult{BucketName: bucket}
+			result.ObjectName = object
+			if e != nil {
+				result.Err = c.toClientError(e, bucket, object).Trace(content.URL.String())
+			}
+			select {
+			case resultCh <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return resultCh
+}
+
+// GetURL returns the target URL.
+func (c *blobClient) GetURL() ClientURL {
+	return c.targetURL.Clone()
+}
+
+// AddUserAgent is not used by the blob stores.
+func (c *blobClient) AddUserAgent(_, _ string) {}
+
+// GetBucketInfo returns the name of the bucket.
+func (c *blobClient) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return BucketInfo{}, probe.NewError(BucketNameEmpty{})
+	}
+	if object != "" {
+		return BucketInfo{}, probe.NewError(InvalidArgument{})
+	}
+	content, err := c.Stat(ctx, StatOptions{})
+	if err != nil {
+		return BucketInfo{}, err
+	}
+	return BucketInfo{URL: content.URL, Key: bucket, Type: os.ModeDir}, nil
+}
+
+// GetPart is not supported by the blob stores.
+func (c *blobClient) GetPart(_ context.Context, _ int) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("GetPart")
+}
+
+// PutPart uploads the whole object.
+func (c *blobClient) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	return c.Put(ctx, reader, size, progress, opts)
+}
+
+// The S3 specific features are not implemented by the blob stores.
+
+func (c *blobClient) SetObjectLockConfig(_ context.Context, _ minio.RetentionMode, _ uint64, _ minio.ValidityUnit) *probe.Error {
+	return c.notImplemented("SetObjectLockConfig")
+}
+
+func (c *blobClient) GetObjectLockConfig(_ context.Context) (string, minio.RetentionMode, uint64, minio.ValidityUnit, *probe.Error) {
+	return "", "", 0, "", c.notImplemented("GetObjectLockConfig")
+}
+
+func (c *blobClient) GetAccess(_ context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetBucketPolicy")
+}
+
+func (c *blobClient) GetAccessRules(_ context.Context) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetBucketPolicy")
+}
+
+func (c *blobClient) SetAccess(_ context.Context, _ string, _ bool) *probe.Error {
+	return c.notImplemented("SetBucketPolicy")
+}
+
+func (c *blobClient) Select(_ context.Context, _ string, _ encrypt.ServerSide, _ SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, c.notImplemented("Select")
+}
+
+func (c *blobClient) PutObjectRetention(_ context.Context, _ string, _ minio.RetentionMode, _ time.Time, _ bool) *probe.Error {
+	return c.notImplemented("PutObjectRetention")
+}
+
+func (c *blobClient) GetObjectRetention(_ context.Context, _ string) (minio.RetentionMode, time.Time, *probe.Error) {
+	return "", time.Time{}, c.notImplemented("GetObjectRetention")
+}
+
+func (c *blobClient) PutObjectLegalHold(_ context.Context, _ string, _ minio.LegalHoldStatus) *probe.Error {
+	return c.notImplemented("PutObjectLegalHold")
+}
+
+func (c *blobClient) GetObjectLegalHold(_ context.Context, _ string) (minio.LegalHoldStatus, *probe.Error) {
+	return "", c.notImplemented("GetObjectLegalHold")
+}
+
+func (c *blobClient) ShareDownload(_ context.Context, _ string, _ time.Duration, _ map[string]string) (string, *probe.Error) {
+	return "", c.notImplemented("ShareDownload")
+}
+
+func (c *blobClient) ShareUpload(_ context.Context, _ bool, _ time.Duration, _ string) (string, map[string]string, *probe.Error) {
+	return "", nil, c.notImplemented("ShareUpload")
+}
+
+func (c *blobClient) Watch(_ context.Context, _ WatchOptions) (*WatchObject, *probe.Error) {
+	return nil, c.notImplemented("Watch")
+}
+
+func (c *blobClient) GetTags(_ context.Context, _ string) (map[string]string, *probe.Error) {
+	return nil, c.notImplemented("GetObjectTagging")
+}
+
+func (c *blobClient) SetTags(_ context.Context, _, _ string) *probe.Error {
+	return c.notImplemented("PutObjectTagging")
+}
+
+func (c *blobClient) DeleteTags(_ context.Context, _ string) *probe.Error {
+	return c.notImplemented("DeleteObjectTagging")
+}
+
+func (c *blobClient) GetLifecycle(_ context.Context) (*lifecycle.Configuration, *probe.Error) {
+	return nil, c.notImplemented("GetBucketLifecycle")
+}
+
+func (c *blobClient) SetLifecycle(_ context.Context, _ *lifecycle.Configuration) *probe.Error {
+	return c.notImplemented("SetBucketLifecycle")
+}
+
+func (c *blobClient) GetVersion(_ context.Context) (minio.BucketVersioningConfiguration, *probe.Error) {
+	return minio.BucketVersioningConfiguration{}, c.notImplemented("GetBucketVersioning")
+}
+
+func (c *blobClient) SetVersion(_ context.Context, _ string, _ []string, _ bool) *probe.Error {
+	return c.notImplemented("SetBucketVersioning")
+}
+
+func (c *blobClient) GetReplication(_ context.Context) (replication.Config, *probe.Error) {
+	return replication.Config{}, c.notImplemented("GetReplication")
+}
+
+func (c *blobClient) SetReplication(_ context.Context, _ *replication.Config, _ replication.Options) *probe.Error {
+	return c.notImplemented("SetReplication")
+}
+
+func (c *blobClient) RemoveReplication(_ context.Context) *probe.Error {
+	return c.notImplemented("RemoveReplication")
+}
+
+func (c *blobClient) GetReplicationMetrics(_ context.Context) (replication.Metrics, *probe.Error) {
+	return replication.Metrics{}, c.notImplemented("GetReplicationMetrics")
+}
+
+func (c *blobClient) ResetReplication(_ context.Context, _ time.Duration, _ string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ResetReplication")
+}
+
+func (c *blobClient) ReplicationResyncStatus(_ context.Context, _ string) (replication.ResyncTargetsInfo, *probe.Error) {
+	return replication.ResyncTargetsInfo{}, c.notImplemented("ReplicationResyncStatus")
+}
+
+func (c *blobClient) GetEncryption(_ context.Context) (string, string, *probe.Error) {
+	return "", "", c.notImplemented("GetEncryption")
+}
+
+func (c *blobClient) SetEncryption(_ context.Context, _, _ string) *probe.Error {
+	return c.notImplemented("SetEncryption")
+}
+
+func (c *blobClient) DeleteEncryption(_ context.Context) *probe.Error {
+	return c.notImplemented("DeleteEncryption")
+}
+
+func (c *blobClient) Restore(_ context.Context, _ string, _ int, _ minio.TierType) *probe.Error {
+	return c.notImplemented("Restore")
+}
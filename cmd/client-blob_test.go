@@ -0,0 +1,188 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memBlobStore is an in-memory blobStore.
+type memBlobStore struct {
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+}
+
+func (s *memBlobStore) kind() string { return "memory" }
+
+func (s *memBlobStore) listBuckets(_ context.Context) ([]blobInfo, error) {
+	var buckets []blobInfo
+	for name := range s.buckets {
+		buckets = append(buckets, blobInfo{Name: name, IsDir: true})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Name < buckets[j].Name })
+	return buckets, nil
+}
+
+func (s *memBlobStore) makeBucket(_ context.Context, bucket string) error {
+	s.buckets[bucket] = map[string][]byte{}
+	return nil
+}
+
+func (s *memBlobStore) removeBucket(_ context.Context, bucket string) error {
+	delete(s.buckets, bucket)
+	return nil
+}
+
+func (s *memBlobStore) list(_ context.Context, bucket, prefix string, delimited bool, fn func(blobInfo) error) error {
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		return errBlobBucketNotFound
+	}
+	var names []string
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := map[string]bool{}
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if i := strings.Index(name[len(prefix):], "/"); delimited && i >= 0 {
+			dir := name[:len(prefix)+i+1]
+			if !seen[dir] {
+				seen[dir] = true
+				if e := fn(blobInfo{Name: dir, IsDir: true}); e != nil {
+					return e
+				}
+			}
+			continue
+		}
+		if e := fn(blobInfo{Name: name, Size: int64(len(objects[name]))}); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *memBlobStore) stat(_ context.Context, bucket, object string) (blobInfo, error) {
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		return blobInfo{}, errBlobBucketNotFound
+	}
+	data, ok := objects[object]
+	if !ok {
+		return blobInfo{}, errBlobNotFound
+	}
+	return blobInfo{Name: object, Size: int64(len(data)), ModTime: time.Now()}, nil
+}
+
+func (s *memBlobStore) get(_ context.Context, bucket, object string, offset int64) (io.ReadCloser, error) {
+	data, ok := s.buckets[bucket][object]
+	if !ok {
+		return nil, errBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data[offset:])), nil
+}
+
+func (s *memBlobStore) put(_ context.Context, bucket, object string, reader io.Reader, _ int64, _ string, _ map[string]string) error {
+	data, e := io.ReadAll(reader)
+	if e != nil {
+		return e
+	}
+	s.buckets[bucket][object] = data
+	return nil
+}
+
+func (s *memBlobStore) remove(_ context.Context, bucket, object string) error {
+	if _, ok := s.buckets[bucket][object]; !ok {
+		return errBlobNotFound
+	}
+	delete(s.buckets[bucket], object)
+	return nil
+}
+
+func TestBlobClient(t *testing.T) {
+	store := &memBlobStore{buckets: map[string]map[string][]byte{}}
+	clnt := func(path string) Client {
+		return &blobClient{store: store, targetURL: newClientURL("https://blob.example.com" + path)}
+	}
+	ctx := context.Background()
+
+	if err := clnt("/photos").MakeBucket(ctx, "", false, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.jpg", "2023/b.jpg", "2023/c.jpg"} {
+		if _, err := clnt("/photos/"+name).Put(ctx, strings.NewReader(name), int64(len(name)), nil, PutOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, err := clnt("/photos/2023").Stat(ctx, StatOptions{})
+	if err != nil || !content.Type.IsDir() {
+		t.Fatalf("expected a directory, got %v %v", content, err)
+	}
+	if _, err = clnt("/photos/missing").Stat(ctx, StatOptions{}); err == nil {
+		t.Fatal("expected an error for a missing object")
+	}
+
+	list := func(path string, recursive bool) (names []string) {
+		for content := range clnt(path).List(ctx, ListOptions{Recursive: recursive, ShowDir: DirFirst}) {
+			if content.Err != nil {
+				t.Fatal(content.Err)
+			}
+			names = append(names, content.URL.Path)
+		}
+		return names
+	}
+	if got := strings.Join(list("/photos/", false), ","); got != "/photos/2023/,/photos/a.jpg" {
+		t.Fatalf("unexpected listing %s", got)
+	}
+	if got := strings.Join(list("/photos/2023", true), ","); got != "/photos/2023/b.jpg,/photos/2023/c.jpg" {
+		t.Fatalf("unexpected recursive listing %s", got)
+	}
+	if got := strings.Join(list("/", false), ","); got != "/photos/" {
+		t.Fatalf("unexpected bucket listing %s", got)
+	}
+
+	reader, err := clnt("/photos/2023/b.jpg").Get(ctx, GetOptions{RangeStart: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(reader); string(data) != "b.jpg" {
+		t.Fatalf("unexpected content %q", data)
+	}
+
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: *newClientURL("https://blob.example.com/photos/a.jpg")}
+	close(contentCh)
+	for result := range clnt("/photos").Remove(ctx, false, false, false, false, contentCh) {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+	}
+	if _, ok := store.buckets["photos"]["a.jpg"]; ok {
+		t.Fatal("object was not removed")
+	}
+}
@@ -503,7 +503,7 @@ func (f *fsClient) PutPart(ctx context.Context, reader io.Reader, size int64, pr
 }
 
 // ShareDownload - share download not implemented for filesystem.
-func (f *fsClient) ShareDownload(_ context.Context, _ string, _ time.Duration) (string, *probe.Error) {
+func (f *fsClient) ShareDownload(_ context.Context, _ string, _ time.Duration, _ map[string]string) (string, *probe.Error) {
 	return "", probe.NewError(APINotImplemented{
 		API:     "ShareDownload",
 		APIType: "filesystem",
@@ -1461,7 +1461,7 @@ func (f *fsClient) GetBucketInfo(_ context.Context) (BucketInfo, *probe.Error) {
 }
 
 // Restore object - not implemented
-func (f *fsClient) Restore(_ context.Context, _ string, _ int) *probe.Error {
+func (f *fsClient) Restore(_ context.Context, _ string, _ int, _ minio.TierType) *probe.Error {
 	return probe.NewError(APINotImplemented{
 		API:     "Restore",
 		APIType: "filesystem",
@@ -0,0 +1,418 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v4"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+const (
+	// gcsScope is the OAuth scope requested for the service accounts.
+	gcsScope = "https://www.googleapis.com/auth/devstorage.full_control"
+	// gcsMetadataTokenURL serves the tokens of the instance service account.
+	gcsMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	// gcsChunkSize is the size of the chunks of the resumable uploads, a
+	// multiple of 256KiB.
+	gcsChunkSize = 16 << 20
+)
+
+// gcsStore is a blobStore of Google Cloud Storage using the JSON API,
+// authenticated with OAuth tokens of a service account.
+type gcsStore struct {
+	endpoint string
+	project  string
+	client   *http.Client
+
+	// Service account key, the metadata server is used without it.
+	clientEmail string
+	privateKey  []byte
+	tokenURI    string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// gcsNew returns a client of a Google Cloud Storage alias, the access key
+// is the project and the secret key the JSON key of a service account or
+// its path. Without a key, the service account of the instance is used.
+func gcsNew(config *Config) (Client, *probe.Error) {
+	targetURL := newClientURL(config.HostURL)
+	store := &gcsStore{
+		endpoint: targetURL.Scheme + "://" + targetURL.Host,
+		project:  config.AccessKey,
+		client:   &http.Client{Transport: newBlobTransport(config)},
+	}
+
+	if key := strings.TrimSpace(config.SecretKey); key != "" {
+		data := []byte(key)
+		if !strings.HasPrefix(key, "{") {
+			var e error
+			if data, e = os.ReadFile(key); e != nil {
+				return nil, probe.NewError(e).Trace(key)
+			}
+		}
+		var sa struct {
+			ProjectID   string `json:"project_id"`
+			ClientEmail string `json:"client_email"`
+			PrivateKey  string `json:"private_key"`
+			TokenURI    string `json:"token_uri"`
+		}
+		if e := json.Unmarshal(data, &sa); e != nil {
+			return nil, probe.NewError(fmt.Errorf("invalid service account key: %w", e))
+		}
+		store.clientEmail, store.privateKey, store.tokenURI = sa.ClientEmail, []byte(sa.PrivateKey), sa.TokenURI
+		if store.tokenURI == "" {
+			store.tokenURI = "https://oauth2.googleapis.com/token"
+		}
+		if store.project == "" {
+			store.project = sa.ProjectID
+		}
+	}
+	return &blobClient{store: store, targetURL: targetURL}, nil
+}
+
+func (s *gcsStore) kind() string {
+	return "gcs"
+}
+
+// accessToken returns a valid OAuth token, renewed a minute before it expires.
+func (s *gcsStore) accessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Add(time.Minute).Before(s.expires) {
+		return s.token, nil
+	}
+
+	var req *http.Request
+	var e error
+	if s.clientEmail == "" {
+		req, e = http.NewRequestWithContext(ctx, http.MethodGet, gcsMetadataTokenURL, nil)
+		if e != nil {
+			return "", e
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+	} else {
+		key, e := jwtgo.ParseRSAPrivateKeyFromPEM(s.privateKey)
+		if e != nil {
+			return "", e
+		}
+		now := time.Now()
+		assertion, e := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, jwtgo.MapClaims{
+			"iss":   s.clientEmail,
+			"scope": gcsScope,
+			"aud":   s.tokenURI,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		}).SignedString(key)
+		if e != nil {
+			return "", e
+		}
+		form := url.Values{
+			"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			"assertion":  {assertion},
+		}
+		req, e = http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURI, strings.NewReader(form.Encode()))
+		if e != nil {
+			return "", e
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, e := s.client.Do(req)
+	if e != nil {
+		return "", e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("unable to get an access token: %s %s", resp.Status, bytes.TrimSpace(body))
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if e = json.NewDecoder(resp.Body).Decode(&token); e != nil {
+		return "", e
+	}
+	s.token, s.expires = token.AccessToken, time.Now().Add(time.Duration(token.ExpiresIn)*time.Second)
+	return s.token, nil
+}
+
+// do sends an authenticated request, the responses with an unexpected
+// status are returned as errors.
+func (s *gcsStore) do(ctx context.Context, method, u string, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	token, e := s.accessToken(ctx)
+	if e != nil {
+		return nil, e
+	}
+	req, e := http.NewRequestWithContext(ctx, method, u, body)
+	if e != nil {
+		return nil, e
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, e := s.client.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	// 308 is returned while a resumable upload is incomplete.
+	if resp.StatusCode < 300 || resp.StatusCode == http.StatusPermanentRedirect {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	var gcsErr struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&gcsErr)
+	if resp.StatusCode == http.StatusNotFound {
+		if strings.Contains(gcsErr.Error.Message, "bucket") {
+			return nil, errBlobBucketNotFound
+		}
+		return nil, errBlobNotFound
+	}
+	if gcsErr.Error.Message == "" {
+		gcsErr.Error.Message = resp.Status
+	}
+	return nil, errors.New(gcsErr.Error.Message)
+}
+
+func (s *gcsStore) bucketURL(bucket string) string {
+	return s.endpoint + "/storage/v1/b/" + url.PathEscape(bucket)
+}
+
+func (s *gcsStore) objectURL(bucket, object string) string {
+	return s.bucketURL(bucket) + "/o/" + url.PathEscape(object)
+}
+
+func (s *gcsStore) decode(ctx context.Context, method, u string, body io.Reader, size int64, v interface{}) error {
+	header := http.Header{}
+	if body != nil {
+		header.Set("Content-Type", "application/json")
+	}
+	resp, e := s.do(ctx, method, u, header, body, size)
+	if e != nil {
+		return e
+	}
+	defer resp.Body.Close()
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (s *gcsStore) listBuckets(ctx context.Context) ([]blobInfo, error) {
+	if s.project == "" {
+		return nil, errors.New("the project is required to list the buckets, set it as the access key")
+	}
+	var buckets []blobInfo
+	pageToken := ""
+	for {
+		query := url.Values{"project": {s.project}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var result struct {
+			Items []struct {
+				Name        string    `json:"name"`
+				TimeCreated time.Time `json:"timeCreated"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if e := s.decode(ctx, http.MethodGet, s.endpoint+"/storage/v1/b?"+query.Encode(), nil, 0, &result); e != nil {
+			return nil, e
+		}
+		for _, b := range result.Items {
+			buckets = append(buckets, blobInfo{Name: b.Name, ModTime: b.TimeCreated, IsDir: true})
+		}
+		if pageToken = result.NextPageToken; pageToken == "" {
+			return buckets, nil
+		}
+	}
+}
+
+func (s *gcsStore) makeBucket(ctx context.Context, bucket string) error {
+	if s.project == "" {
+		return errors.New("the project is required to create a bucket, set it as the access key")
+	}
+	body, _ := json.Marshal(map[string]string{"name": bucket})
+	u := s.endpoint + "/storage/v1/b?" + url.Values{"project": {s.project}}.Encode()
+	return s.decode(ctx, http.MethodPost, u, bytes.NewReader(body), int64(len(body)), nil)
+}
+
+func (s *gcsStore) removeBucket(ctx context.Context, bucket string) error {
+	return s.decode(ctx, http.MethodDelete, s.bucketURL(bucket), nil, 0, nil)
+}
+
+// gcsObject is the resource of an object in the JSON API.
+type gcsObject struct {
+	Name        string            `json:"name"`
+	Size        string            `json:"size"`
+	Updated     time.Time         `json:"updated"`
+	ETag        string            `json:"etag"`
+	ContentType string            `json:"contentType"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+func (o gcsObject) info() blobInfo {
+	size, _ := strconv.ParseInt(o.Size, 10, 64)
+	return blobInfo{
+		Name:        o.Name,
+		Size:        size,
+		ModTime:     o.Updated,
+		ETag:        o.ETag,
+		ContentType: o.ContentType,
+		Metadata:    o.Metadata,
+	}
+}
+
+func (s *gcsStore) list(ctx context.Context, bucket, prefix string, delimited bool, fn func(blobInfo) error) error {
+	pageToken := ""
+	for {
+		query := url.Values{}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if delimited {
+			query.Set("delimiter", "/")
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var result struct {
+			Items         []gcsObject `json:"items"`
+			Prefixes      []string    `json:"prefixes"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+		if e := s.decode(ctx, http.MethodGet, s.bucketURL(bucket)+"/o?"+query.Encode(), nil, 0, &result); e != nil {
+			return e
+		}
+		for _, p := range result.Prefixes {
+			if e := fn(blobInfo{Name: p, IsDir: true}); e != nil {
+				return e
+			}
+		}
+		for _, o := range result.Items {
+			if e := fn(o.info()); e != nil {
+				return e
+			}
+		}
+		if pageToken = result.NextPageToken; pageToken == "" {
+			return nil
+		}
+	}
+}
+
+func (s *gcsStore) stat(ctx context.Context, bucket, object string) (blobInfo, error) {
+	var o gcsObject
+	if e := s.decode(ctx, http.MethodGet, s.objectURL(bucket, object), nil, 0, &o); e != nil {
+		return blobInfo{}, e
+	}
+	return o.info(), nil
+}
+
+func (s *gcsStore) get(ctx context.Context, bucket, object string, offset int64) (io.ReadCloser, error) {
+	header := http.Header{}
+	if offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, e := s.do(ctx, http.MethodGet, s.objectURL(bucket, object)+"?alt=media", header, nil, 0)
+	if e != nil {
+		return nil, e
+	}
+	return resp.Body, nil
+}
+
+// put uploads an object with a resumable upload, sent in chunks so the
+// size does not need to be known.
+func (s *gcsStore) put(ctx context.Context, bucket, object string, reader io.Reader, _ int64, contentType string, metadata map[string]string) error {
+	resource, _ := json.Marshal(map[string]interface{}{
+		"name":        object,
+		"contentType": contentType,
+		"metadata":    metadata,
+	})
+	u := s.endpoint + "/upload/storage/v1/b/" + url.PathEscape(bucket) + "/o?uploadType=resumable"
+	header := http.Header{"Content-Type": {"application/json"}}
+	resp, e := s.do(ctx, http.MethodPost, u, header, bytes.NewReader(resource), int64(len(resource)))
+	if e != nil {
+		return e
+	}
+	resp.Body.Close()
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return errors.New("no resumable upload session returned")
+	}
+
+	buf := make([]byte, gcsChunkSize)
+	var offset int64
+	for {
+		n, e := io.ReadFull(reader, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return e
+		}
+		last := e != nil
+		total := "*"
+		if last {
+			total = strconv.FormatInt(offset+int64(n), 10)
+		}
+		header := http.Header{}
+		if n > 0 {
+			header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%s", offset, offset+int64(n)-1, total))
+		} else {
+			header.Set("Content-Range", "bytes */"+total)
+		}
+		resp, err := s.do(ctx, http.MethodPut, session, header, bytes.NewReader(buf[:n]), int64(n))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		offset += int64(n)
+		if last {
+			return nil
+		}
+	}
+}
+
+func (s *gcsStore) remove(ctx context.Context, bucket, object string) error {
+	return s.decode(ctx, http.MethodDelete, s.objectURL(bucket, object), nil, 0, nil)
+}
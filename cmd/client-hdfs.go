@@ -0,0 +1,472 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+const (
+	// hdfsDefaultPort is the WebHDFS port of the name nodes, used when an
+	// hdfs:// alias URL has no port.
+	hdfsDefaultPort = "9870"
+	// hdfsKerberos selects the Kerberos ticket cache as the secret key.
+	hdfsKerberos = "kerberos"
+	// hdfsKeytabPrefix prefixes the path of a Kerberos keytab as the
+	// secret key.
+	hdfsKeytabPrefix = "keytab:"
+)
+
+// hdfsDoer sends the requests to the name node, with SPNEGO when the
+// alias uses Kerberos.
+type hdfsDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// hdfsStore is a blobStore of HDFS using the WebHDFS REST API, the
+// directories right below the root are the buckets.
+type hdfsStore struct {
+	endpoint string
+	// auth are the query parameters authenticating the requests, the user
+	// name or a delegation token.
+	auth url.Values
+	// nameNode serves the metadata, dataNode the redirects of the name
+	// node to read and write the files.
+	nameNode hdfsDoer
+	dataNode *http.Client
+}
+
+// hdfsFileStatus is a file or a directory of WebHDFS.
+type hdfsFileStatus struct {
+	PathSuffix       string `json:"pathSuffix"`
+	Type             string `json:"type"`
+	Length           int64  `json:"length"`
+	ModificationTime int64  `json:"modificationTime"`
+}
+
+func (fs hdfsFileStatus) isDir() bool {
+	return fs.Type == "DIRECTORY"
+}
+
+func (fs hdfsFileStatus) modTime() time.Time {
+	return time.UnixMilli(fs.ModificationTime).UTC()
+}
+
+// hdfsError is the RemoteException of WebHDFS.
+type hdfsError struct {
+	Exception string `json:"exception"`
+	Message   string `json:"message"`
+}
+
+func (e hdfsError) Error() string {
+	return e.Exception + ": " + e.Message
+}
+
+// hdfsNew returns a client of the WebHDFS API of a name node. The access
+// key is the user, the secret key is either empty for the simple
+// authentication, "kerberos" for the Kerberos ticket cache,
+// "keytab:PATH" for a Kerberos keytab of the access key principal, or a
+// delegation token.
+func hdfsNew(config *Config) (Client, *probe.Error) {
+	targetURL := newClientURL(config.HostURL)
+	endpoint := targetURL.Scheme + "://" + targetURL.Host
+	if targetURL.Scheme == "hdfs" {
+		host := targetURL.Host
+		if _, _, e := net.SplitHostPort(host); e != nil {
+			host = net.JoinHostPort(host, hdfsDefaultPort)
+		}
+		endpoint = "http://" + host
+	}
+
+	noRedirect := func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	store := &hdfsStore{
+		endpoint: endpoint,
+		auth:     url.Values{},
+		dataNode: &http.Client{Transport: newBlobTransport(config)},
+	}
+	nameNode := &http.Client{Transport: newBlobTransport(config), CheckRedirect: noRedirect}
+	store.nameNode = nameNode
+
+	secret := strings.TrimSpace(config.SecretKey)
+	switch {
+	case secret == hdfsKerberos || strings.HasPrefix(secret, hdfsKeytabPrefix):
+		krbClient, e := kerberosClient(config.AccessKey, secret)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		store.nameNode = spnego.NewClient(krbClient, nameNode, "")
+	case secret != "":
+		store.auth.Set("delegation", secret)
+	case config.AccessKey != "":
+		store.auth.Set("user.name", config.AccessKey)
+	}
+	return &blobClient{store: store, targetURL: targetURL}, nil
+}
+
+// kerberosClient logs in with the ticket cache of the user, or with
+// the keytab of the principal.
+func kerberosClient(principal, secret string) (*client.Client, error) {
+	confPath := os.Getenv("KRB5_CONFIG")
+	if confPath == "" {
+		confPath = "/etc/krb5.conf"
+	}
+	conf, e := krbconfig.Load(confPath)
+	if e != nil {
+		return nil, fmt.Errorf("unable to load the Kerberos configuration: %w", e)
+	}
+
+	if strings.HasPrefix(secret, hdfsKeytabPrefix) {
+		kt, e := keytab.Load(strings.TrimPrefix(secret, hdfsKeytabPrefix))
+		if e != nil {
+			return nil, fmt.Errorf("unable to load the keytab: %w", e)
+		}
+		name, realm := principal, conf.LibDefaults.DefaultRealm
+		if i := strings.LastIndex(principal, "@"); i >= 0 {
+			name, realm = principal[:i], principal[i+1:]
+		}
+		krbClient := client.NewWithKeytab(name, realm, kt, conf, client.DisablePAFXFAST(true))
+		if e = krbClient.Login(); e != nil {
+			return nil, e
+		}
+		return krbClient, nil
+	}
+
+	ccachePath := strings.TrimPrefix(os.Getenv("KRB5CCNAME"), "FILE:")
+	if ccachePath == "" {
+		u, e := user.Current()
+		if e != nil {
+			return nil, e
+		}
+		ccachePath = "/tmp/krb5cc_" + u.Uid
+	}
+	ccache, e := credentials.LoadCCache(ccachePath)
+	if e != nil {
+		return nil, fmt.Errorf("unable to load the Kerberos ticket cache, run kinit first: %w", e)
+	}
+	return client.NewFromCCache(ccache, conf, client.DisablePAFXFAST(true))
+}
+
+func (s *hdfsStore) kind() string {
+	return "hdfs"
+}
+
+// call sends an operation on a path to the name node.
+func (s *hdfsStore) call(ctx context.Context, method, p, op string, params url.Values) (*http.Response, error) {
+	query := url.Values{"op": {op}}
+	for k, v := range params {
+		query[k] = v
+	}
+	for k, v := range s.auth {
+		query[k] = v
+	}
+	u := s.endpoint + "/webhdfs/v1" + (&url.URL{Path: p}).EscapedPath() + "?" + query.Encode()
+	req, e := http.NewRequestWithContext(ctx, method, u, nil)
+	if e != nil {
+		return nil, e
+	}
+	resp, e := s.nameNode.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	if e = hdfsResponseError(resp); e != nil {
+		return nil, e
+	}
+	return resp, nil
+}
+
+// hdfsResponseError returns the error of a failed response, and closes
+// its body.
+func hdfsResponseError(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	defer resp.Body.Close()
+	var remote struct {
+		RemoteException hdfsError `json:"RemoteException"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&remote) != nil || remote.RemoteException.Exception == "" {
+		return fmt.Errorf("webhdfs: %s", resp.Status)
+	}
+	return remote.RemoteException
+}
+
+// callJSON sends an operation and decodes its JSON response.
+func (s *hdfsStore) callJSON(ctx context.Context, method, p, op string, params url.Values, v interface{}) error {
+	resp, e := s.call(ctx, method, p, op, params)
+	if e != nil {
+		return e
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// redirect follows the redirection of the name node to a data node.
+func (s *hdfsStore) redirect(ctx context.Context, method string, resp *http.Response, body io.Reader, size int64) (*http.Response, error) {
+	resp.Body.Close()
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, fmt.Errorf("webhdfs: no data node location in %s response", resp.Status)
+	}
+	req, e := http.NewRequestWithContext(ctx, method, location, body)
+	if e != nil {
+		return nil, e
+	}
+	if body != nil {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	if resp, e = s.dataNode.Do(req); e != nil {
+		return nil, e
+	}
+	if e = hdfsResponseError(resp); e != nil {
+		return nil, e
+	}
+	return resp, nil
+}
+
+// toStoreError maps the errors of the name node to the errors of the
+// stores.
+func (s *hdfsStore) toStoreError(e error, notFound error) error {
+	var remote hdfsError
+	if errors.As(e, &remote) && remote.Exception == "FileNotFoundException" {
+		return notFound
+	}
+	return e
+}
+
+func (s *hdfsStore) getFileStatus(ctx context.Context, p string) (hdfsFileStatus, error) {
+	var status struct {
+		FileStatus hdfsFileStatus `json:"FileStatus"`
+	}
+	e := s.callJSON(ctx, http.MethodGet, p, "GETFILESTATUS", nil, &status)
+	return status.FileStatus, e
+}
+
+func (s *hdfsStore) listStatus(ctx context.Context, p string) ([]hdfsFileStatus, error) {
+	var status struct {
+		FileStatuses struct {
+			FileStatus []hdfsFileStatus `json:"FileStatus"`
+		} `json:"FileStatuses"`
+	}
+	if e := s.callJSON(ctx, http.MethodGet, p, "LISTSTATUS", nil, &status); e != nil {
+		return nil, e
+	}
+	return status.FileStatuses.FileStatus, nil
+}
+
+// boolOp sends an operation answering with a boolean, false when the
+// path does not exist.
+func (s *hdfsStore) boolOp(ctx context.Context, method, p, op string, params url.Values) (bool, error) {
+	var result struct {
+		Boolean bool `json:"boolean"`
+	}
+	e := s.callJSON(ctx, method, p, op, params, &result)
+	return result.Boolean, e
+}
+
+func (s *hdfsStore) listBuckets(ctx context.Context) ([]blobInfo, error) {
+	entries, e := s.listStatus(ctx, "/")
+	if e != nil {
+		return nil, e
+	}
+	var buckets []blobInfo
+	for _, entry := range entries {
+		if entry.isDir() {
+			buckets = append(buckets, blobInfo{Name: entry.PathSuffix, ModTime: entry.modTime(), IsDir: true})
+		}
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Name < buckets[j].Name })
+	return buckets, nil
+}
+
+func (s *hdfsStore) makeBucket(ctx context.Context, bucket string) error {
+	if _, e := s.getFileStatus(ctx, "/"+bucket); e == nil {
+		return fmt.Errorf("directory %s already exists", bucket)
+	}
+	_, e := s.boolOp(ctx, http.MethodPut, "/"+bucket, "MKDIRS", nil)
+	return e
+}
+
+func (s *hdfsStore) removeBucket(ctx context.Context, bucket string) error {
+	ok, e := s.boolOp(ctx, http.MethodDelete, "/"+bucket, "DELETE", url.Values{"recursive": {"false"}})
+	if e == nil && !ok {
+		return errBlobBucketNotFound
+	}
+	return e
+}
+
+// list walks the directories of the prefix, the directory names end with
+// '/' so that the keys are listed in the same order as by S3.
+func (s *hdfsStore) list(ctx context.Context, bucket, prefix string, delimited bool, fn func(blobInfo) error) error {
+	if _, e := s.getFileStatus(ctx, "/"+bucket); e != nil {
+		return s.toStoreError(e, errBlobBucketNotFound)
+	}
+	dir, base := "", prefix
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir, base = prefix[:i+1], prefix[i+1:]
+	}
+	e := s.walk(ctx, bucket, dir, base, delimited, fn)
+	if errors.Is(s.toStoreError(e, errBlobNotFound), errBlobNotFound) {
+		return nil
+	}
+	return e
+}
+
+// walk lists the entries of the directory starting with base.
+func (s *hdfsStore) walk(ctx context.Context, bucket, dir, base string, delimited bool, fn func(blobInfo) error) error {
+	entries, e := s.listStatus(ctx, path.Join("/", bucket, dir))
+	if e != nil {
+		return e
+	}
+	names := make(map[string]hdfsFileStatus, len(entries))
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.PathSuffix
+		if !strings.HasPrefix(name, base) || strings.HasSuffix(name, blobPartialSuffix) {
+			continue
+		}
+		if entry.isDir() {
+			name += "/"
+		}
+		names[name] = entry
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	for _, name := range keys {
+		entry := names[name]
+		switch {
+		case entry.isDir() && delimited:
+			e = fn(blobInfo{Name: dir + name, ModTime: entry.modTime(), IsDir: true})
+		case entry.isDir():
+			e = s.walk(ctx, bucket, dir+name, "", false, fn)
+		default:
+			e = fn(blobInfo{Name: dir + name, Size: entry.Length, ModTime: entry.modTime()})
+		}
+		if e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *hdfsStore) stat(ctx context.Context, bucket, object string) (blobInfo, error) {
+	status, e := s.getFileStatus(ctx, path.Join("/", bucket, object))
+	if e != nil {
+		return blobInfo{}, s.toStoreError(e, errBlobNotFound)
+	}
+	if status.isDir() {
+		return blobInfo{}, errBlobNotFound
+	}
+	return blobInfo{Name: object, Size: status.Length, ModTime: status.modTime()}, nil
+}
+
+// get opens the file at the offset, which resumes interrupted copies.
+func (s *hdfsStore) get(ctx context.Context, bucket, object string, offset int64) (io.ReadCloser, error) {
+	params := url.Values{}
+	if offset > 0 {
+		params.Set("offset", strconv.FormatInt(offset, 10))
+	}
+	resp, e := s.call(ctx, http.MethodGet, path.Join("/", bucket, object), "OPEN", params)
+	if e != nil {
+		return nil, s.toStoreError(e, errBlobNotFound)
+	}
+	if resp.StatusCode/100 == 3 {
+		if resp, e = s.redirect(ctx, http.MethodGet, resp, nil, 0); e != nil {
+			return nil, e
+		}
+	}
+	return resp.Body, nil
+}
+
+// put writes to a partial file renamed once complete, the name node
+// creates the parent directories.
+func (s *hdfsStore) put(ctx context.Context, bucket, object string, reader io.Reader, size int64, _ string, _ map[string]string) error {
+	if _, e := s.getFileStatus(ctx, "/"+bucket); e != nil {
+		return s.toStoreError(e, errBlobBucketNotFound)
+	}
+	name := path.Join("/", bucket, object)
+	partial := name + blobPartialSuffix
+	resp, e := s.call(ctx, http.MethodPut, partial, "CREATE", url.Values{"overwrite": {"true"}})
+	if e != nil {
+		return e
+	}
+	if resp, e = s.redirect(ctx, http.MethodPut, resp, reader, size); e != nil {
+		s.boolOp(ctx, http.MethodDelete, partial, "DELETE", nil)
+		return e
+	}
+	resp.Body.Close()
+
+	// Renames do not replace the existing files.
+	if _, e = s.boolOp(ctx, http.MethodDelete, name, "DELETE", nil); e != nil {
+		return e
+	}
+	ok, e := s.boolOp(ctx, http.MethodPut, partial, "RENAME", url.Values{"destination": {name}})
+	if e == nil && !ok {
+		e = fmt.Errorf("unable to rename %s to %s", partial, name)
+	}
+	return e
+}
+
+// remove deletes a file, or an empty directory when object ends with '/',
+// and then the parent directories left empty like the filesystem client.
+func (s *hdfsStore) remove(ctx context.Context, bucket, object string) error {
+	name := path.Join("/", bucket, object)
+	ok, e := s.boolOp(ctx, http.MethodDelete, name, "DELETE", url.Values{"recursive": {"false"}})
+	if e != nil {
+		return e
+	}
+	if !ok {
+		return errBlobNotFound
+	}
+	for dir := path.Dir(name); dir != path.Join("/", bucket); dir = path.Dir(dir) {
+		entries, e := s.listStatus(ctx, dir)
+		if e != nil || len(entries) > 0 {
+			break
+		}
+		if ok, e = s.boolOp(ctx, http.MethodDelete, dir, "DELETE", url.Values{"recursive": {"false"}}); e != nil || !ok {
+			break
+		}
+	}
+	return nil
+}
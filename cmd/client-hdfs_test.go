@@ -0,0 +1,167 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newFakeWebHDFS serves the WebHDFS operations used by hdfsStore on top
+// of a map of files, the directories are implied by the file names.
+func newFakeWebHDFS(t *testing.T, files map[string]string) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/data/") {
+			p := strings.TrimPrefix(r.URL.Path, "/data")
+			if r.Method == http.MethodPut {
+				data, _ := io.ReadAll(r.Body)
+				files[p] = string(data)
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			io.WriteString(w, files[p])
+			return
+		}
+		if user := r.URL.Query().Get("user.name"); user != "hdfs" {
+			t.Errorf("unexpected user %q", user)
+		}
+		p := strings.TrimPrefix(r.URL.Path, "/webhdfs/v1")
+		isDir := func(dir string) bool {
+			for name := range files {
+				if strings.HasPrefix(name, strings.TrimSuffix(dir, "/")+"/") {
+					return true
+				}
+			}
+			return false
+		}
+		notFound := func() {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"RemoteException": hdfsError{Exception: "FileNotFoundException", Message: p}})
+		}
+		switch op := r.URL.Query().Get("op"); op {
+		case "GETFILESTATUS":
+			if data, ok := files[p]; ok {
+				json.NewEncoder(w).Encode(map[string]interface{}{"FileStatus": hdfsFileStatus{Type: "FILE", Length: int64(len(data))}})
+			} else if isDir(p) {
+				json.NewEncoder(w).Encode(map[string]interface{}{"FileStatus": hdfsFileStatus{Type: "DIRECTORY"}})
+			} else {
+				notFound()
+			}
+		case "LISTSTATUS":
+			if !isDir(p) {
+				notFound()
+				return
+			}
+			seen := map[string]bool{}
+			var statuses []hdfsFileStatus
+			for name, data := range files {
+				rest := strings.TrimPrefix(name, strings.TrimSuffix(p, "/")+"/")
+				if rest == name {
+					continue
+				}
+				if i := strings.Index(rest, "/"); i >= 0 {
+					if !seen[rest[:i]] {
+						seen[rest[:i]] = true
+						statuses = append(statuses, hdfsFileStatus{PathSuffix: rest[:i], Type: "DIRECTORY"})
+					}
+					continue
+				}
+				statuses = append(statuses, hdfsFileStatus{PathSuffix: rest, Type: "FILE", Length: int64(len(data))})
+			}
+			sort.Slice(statuses, func(i, j int) bool { return statuses[i].PathSuffix > statuses[j].PathSuffix })
+			json.NewEncoder(w).Encode(map[string]interface{}{"FileStatuses": map[string]interface{}{"FileStatus": statuses}})
+		case "OPEN", "CREATE":
+			if _, ok := files[p]; op == "OPEN" && !ok {
+				notFound()
+				return
+			}
+			w.Header().Set("Location", srv.URL+"/data"+p)
+			w.WriteHeader(http.StatusTemporaryRedirect)
+		case "RENAME":
+			dst := r.URL.Query().Get("destination")
+			files[dst] = files[p]
+			delete(files, p)
+			json.NewEncoder(w).Encode(map[string]bool{"boolean": true})
+		case "DELETE":
+			_, ok := files[p]
+			delete(files, p)
+			json.NewEncoder(w).Encode(map[string]bool{"boolean": ok})
+		default:
+			t.Errorf("unexpected operation %s", op)
+		}
+	}))
+	return srv
+}
+
+func TestHDFSClient(t *testing.T) {
+	files := map[string]string{
+		"/warehouse/sales/2023/part-0": "alpha",
+		"/warehouse/sales/2023/part-1": "beta",
+		"/warehouse/readme":            "gamma",
+	}
+	srv := newFakeWebHDFS(t, files)
+	defer srv.Close()
+
+	clnt := func(p string) Client {
+		c, err := hdfsNew(&Config{HostURL: srv.URL + p, AccessKey: "hdfs"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	ctx := context.Background()
+
+	var names []string
+	for content := range clnt("/warehouse/sales/").List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			t.Fatal(content.Err)
+		}
+		names = append(names, path.Base(content.URL.Path))
+	}
+	if strings.Join(names, ",") != "part-0,part-1" {
+		t.Fatalf("unexpected listing %v", names)
+	}
+
+	reader, err := clnt("/warehouse/sales/2023/part-1").Get(ctx, GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "beta" {
+		t.Fatalf("unexpected content %q", data)
+	}
+
+	if _, err = clnt("/warehouse/copy/part-1").Put(ctx, strings.NewReader("delta"), 5, nil, PutOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if files["/warehouse/copy/part-1"] != "delta" || len(files) != 4 {
+		t.Fatalf("unexpected files after upload %v", files)
+	}
+
+	if _, err = clnt("/warehouse/missing").Stat(ctx, StatOptions{}); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+// httpSourceRetries is the number of times a download is resumed after
+// a failure.
+const httpSourceRetries = 5
+
+// errHTTPSourceReadOnly is returned by the operations which would change
+// an HTTP(S) source.
+var errHTTPSourceReadOnly = errors.New("HTTP(S) URLs without an alias can only be copied from")
+
+// isHTTPSourceURL returns true for the HTTP(S) URLs read by the HTTP
+// source client.
+func isHTTPSourceURL(urlStr string) bool {
+	return strings.HasPrefix(urlStr, "http://") || strings.HasPrefix(urlStr, "https://")
+}
+
+// isHTTPSource returns true for the sources read from a web server, the
+// expanded URLs of the objects of an alias are HTTP(S) URLs as well.
+func isHTTPSource(alias, urlStr string) bool {
+	return alias == "" && isHTTPSourceURL(urlStr)
+}
+
+// httpStore is a read-only blobStore of the files served by a web
+// server, the bucket is the first element of their path.
+type httpStore struct {
+	endpoint string
+	client   *http.Client
+}
+
+// httpSourceClient reads a single file of a web server, the URL is not
+// split in a bucket and an object since most files are at the root.
+type httpSourceClient struct {
+	*blobClient
+	store *httpStore
+}
+
+// httpSourceNew returns a client reading a plain HTTP(S) URL.
+func httpSourceNew(urlStr string) (Client, *probe.Error) {
+	targetURL := newClientURL(urlStr)
+	store := &httpStore{
+		endpoint: targetURL.Scheme + "://" + targetURL.Host,
+		client:   &http.Client{Transport: newBlobTransport(NewS3Config(urlStr, nil))},
+	}
+	return &httpSourceClient{
+		blobClient: &blobClient{store: store, targetURL: targetURL},
+		store:      store,
+	}, nil
+}
+
+// name returns the path of the file, without its leading '/'.
+func (c *httpSourceClient) name() string {
+	return strings.TrimPrefix(c.targetURL.Path, "/")
+}
+
+// Stat returns the attributes of the file.
+func (c *httpSourceClient) Stat(ctx context.Context, _ StatOptions) (*ClientContent, *probe.Error) {
+	if c.name() == "" || strings.HasSuffix(c.name(), "/") {
+		return nil, probe.NewError(errors.New("HTTP(S) URLs must name a file, directories are not listed"))
+	}
+	info, e := c.store.statURL(ctx, c.targetURL.String())
+	if e != nil {
+		return nil, c.toClientError(e, "", c.name()).Trace(c.targetURL.String())
+	}
+	info.Name = c.name()
+	return c.content("", info), nil
+}
+
+// GetBucketInfo is not supported, web servers have no buckets.
+func (c *httpSourceClient) GetBucketInfo(_ context.Context) (BucketInfo, *probe.Error) {
+	return BucketInfo{}, c.notImplemented("GetBucketInfo")
+}
+
+// List returns the file itself.
+func (c *httpSourceClient) List(ctx context.Context, _ ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 1)
+	content, err := c.Stat(ctx, StatOptions{})
+	if err != nil {
+		content = &ClientContent{Err: err}
+	}
+	contentCh <- content
+	close(contentCh)
+	return contentCh
+}
+
+// Get downloads the file from the start of the range.
+func (c *httpSourceClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *probe.Error) {
+	reader, e := c.store.getURL(ctx, c.targetURL.String(), opts.RangeStart)
+	if e != nil {
+		return nil, c.toClientError(e, "", c.name()).Trace(c.targetURL.String())
+	}
+	return reader, nil
+}
+
+func (s *httpStore) kind() string {
+	return "http"
+}
+
+// fileURL returns the URL of the file, as given by the user.
+func (s *httpStore) fileURL(bucket, object string) string {
+	u := s.endpoint + "/" + bucket
+	if object != "" {
+		u += "/" + object
+	}
+	return u
+}
+
+// do sends a request for the file with the headers.
+func (s *httpStore) do(ctx context.Context, method, u string, header http.Header) (*http.Response, error) {
+	req, e := http.NewRequestWithContext(ctx, method, u, nil)
+	if e != nil {
+		return nil, e
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, e := s.client.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, errBlobNotFound
+	}
+	return nil, fmt.Errorf("%s %s: %s", method, u, resp.Status)
+}
+
+func (s *httpStore) listBuckets(_ context.Context) ([]blobInfo, error) {
+	return nil, errors.New("HTTP(S) URLs without an alias can only be copied from, add an alias with 'mc alias set' to list a server")
+}
+
+func (s *httpStore) makeBucket(_ context.Context, _ string) error {
+	return errHTTPSourceReadOnly
+}
+
+func (s *httpStore) removeBucket(_ context.Context, _ string) error {
+	return errHTTPSourceReadOnly
+}
+
+// list returns the file named by the prefix, the web servers are not
+// listed.
+func (s *httpStore) list(ctx context.Context, bucket, prefix string, _ bool, fn func(blobInfo) error) error {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return nil
+	}
+	info, e := s.stat(ctx, bucket, prefix)
+	if errors.Is(e, errBlobNotFound) {
+		return nil
+	}
+	if e != nil {
+		return e
+	}
+	return fn(info)
+}
+
+func (s *httpStore) stat(ctx context.Context, bucket, object string) (blobInfo, error) {
+	info, e := s.statURL(ctx, s.fileURL(bucket, object))
+	info.Name = object
+	return info, e
+}
+
+// statURL sends a HEAD request, or a GET of the first byte for the
+// servers and presigned URLs refusing HEAD requests.
+func (s *httpStore) statURL(ctx context.Context, u string) (blobInfo, error) {
+	resp, e := s.do(ctx, http.MethodHead, u, nil)
+	size := int64(-1)
+	if e == nil {
+		resp.Body.Close()
+		size = resp.ContentLength
+	} else if !errors.Is(e, errBlobNotFound) {
+		resp, e = s.do(ctx, http.MethodGet, u, http.Header{"Range": {"bytes=0-0"}})
+		if e != nil {
+			return blobInfo{}, e
+		}
+		resp.Body.Close()
+		size = resp.ContentLength
+		if cr := resp.Header.Get("Content-Range"); resp.StatusCode == http.StatusPartialContent && strings.Contains(cr, "/") {
+			size, _ = strconv.ParseInt(cr[strings.LastIndex(cr, "/")+1:], 10, 64)
+		}
+	} else {
+		return blobInfo{}, e
+	}
+
+	info := blobInfo{
+		Size:        size,
+		ETag:        strings.Trim(resp.Header.Get("ETag"), "\""),
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	info.ModTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return info, nil
+}
+
+func (s *httpStore) get(ctx context.Context, bucket, object string, offset int64) (io.ReadCloser, error) {
+	return s.getURL(ctx, s.fileURL(bucket, object), offset)
+}
+
+// getURL returns a reader resuming the download with range requests when
+// the connection fails.
+func (s *httpStore) getURL(ctx context.Context, u string, offset int64) (io.ReadCloser, error) {
+	r := &httpSourceReader{ctx: ctx, store: s, url: u, offset: offset}
+	if e := r.open(); e != nil {
+		return nil, e
+	}
+	return r, nil
+}
+
+func (s *httpStore) put(_ context.Context, _, _ string, _ io.Reader, _ int64, _ string, _ map[string]string) error {
+	return errHTTPSourceReadOnly
+}
+
+func (s *httpStore) remove(_ context.Context, _, _ string) error {
+	return errHTTPSourceReadOnly
+}
+
+// httpSourceReader reads a file from its offset, the downloads are
+// resumed with a range request conditional on the validator of the first
+// response, so that a changed file is not stitched together.
+type httpSourceReader struct {
+	ctx       context.Context
+	store     *httpStore
+	url       string
+	offset    int64
+	validator string
+	body      io.ReadCloser
+	retries   int
+}
+
+// open starts the download at the offset.
+func (r *httpSourceReader) open() error {
+	header := http.Header{}
+	if r.offset > 0 {
+		header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-")
+		if r.validator != "" {
+			header.Set("If-Range", r.validator)
+		}
+	}
+	resp, e := r.store.do(r.ctx, http.MethodGet, r.url, header)
+	if e != nil {
+		return e
+	}
+	if r.offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return fmt.Errorf("unable to resume %s at offset %d, the file changed or the server does not support range requests", r.url, r.offset)
+	}
+	if r.validator == "" {
+		if r.validator = resp.Header.Get("ETag"); r.validator == "" || strings.HasPrefix(r.validator, "W/") {
+			r.validator = resp.Header.Get("Last-Modified")
+		}
+	}
+	r.body = resp.Body
+	return nil
+}
+
+func (r *httpSourceReader) Read(p []byte) (int, error) {
+	for {
+		n, e := r.body.Read(p)
+		r.offset += int64(n)
+		if e == nil || e == io.EOF || r.ctx.Err() != nil {
+			return n, e
+		}
+		if n > 0 {
+			// Deliver the data read, the next read fails again and resumes.
+			return n, nil
+		}
+		r.body.Close()
+		if e = r.resume(e); e != nil {
+			return 0, e
+		}
+	}
+}
+
+// resume reopens the download after the failure with a backoff, until
+// the retries are exhausted.
+func (r *httpSourceReader) resume(failure error) error {
+	for r.retries < httpSourceRetries {
+		r.retries++
+		select {
+		case <-time.After(time.Duration(1<<r.retries) * time.Second):
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		}
+		e := r.open()
+		if e == nil {
+			return nil
+		}
+		failure = e
+	}
+	return failure
+}
+
+func (r *httpSourceReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestHTTPSourceResume(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100000)
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start := 0
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(data)-1, len(data)))
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)-start))
+			w.WriteHeader(http.StatusPartialContent)
+		} else {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		}
+		if requests == 1 {
+			// Drop the connection in the middle of the first download.
+			w.Write(data[:len(data)/3])
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write(data[start:])
+	}))
+	defer srv.Close()
+
+	clnt, err := httpSourceNew(srv.URL + "/file.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := clnt.Get(context.Background(), GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	sum := sha256.Sum256(data)
+	verified, e := newDigestReader(reader, fmt.Sprintf("sha256:%x", sum), int64(len(data)))
+	if e != nil {
+		t.Fatal(e)
+	}
+	got, e := io.ReadAll(verified)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(got, data) || requests != 2 {
+		t.Fatalf("unexpected download of %d bytes in %d requests", len(got), requests)
+	}
+
+	mismatch, _ := newDigestReader(io.NopCloser(bytes.NewReader(data[1:])), fmt.Sprintf("sha256:%x", sum), int64(len(data)-1))
+	if _, e = io.ReadAll(mismatch); e == nil {
+		t.Fatal("expected a digest mismatch")
+	}
+}
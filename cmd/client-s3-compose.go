@@ -0,0 +1,95 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+// directoryBucketSuffix ends the names of the S3 Express One Zone
+// directory buckets.
+const directoryBucketSuffix = "--x-s3"
+
+// isDirectoryBucket returns true for the S3 Express One Zone directory
+// buckets, the only ones which support appending to objects.
+func isDirectoryBucket(bucket string) bool {
+	return strings.HasSuffix(bucket, directoryBucketSuffix)
+}
+
+// AppendObject appends the content of reader to the object, which must be
+// offset bytes long. Servers which don't support appending ignore the
+// offset and overwrite the object, it is only supported by directory
+// buckets.
+func (c *S3Client) AppendObject(ctx context.Context, reader io.Reader, size, offset int64) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return probe.NewError(ObjectNameEmpty{})
+	}
+	if !isDirectoryBucket(bucket) {
+		return probe.NewError(APINotImplemented{API: "Append", APIType: c.GetURL().String()})
+	}
+
+	header := http.Header{}
+	header.Set("X-Amz-Write-Offset-Bytes", strconv.FormatInt(offset, 10))
+	var reqURL string
+	if c.authScheme != "" {
+		u := c.api.EndpointURL()
+		u.Path = "/" + bucket + "/" + object
+		reqURL = u.String()
+	} else {
+		u, e := c.api.PresignHeader(ctx, http.MethodPut, bucket, object, 15*time.Minute, nil, header)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		reqURL = u.String()
+	}
+	req, e := http.NewRequestWithContext(ctx, http.MethodPut, reqURL, reader)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	req.Header = header
+	req.ContentLength = size
+	_, err := c.sendRequest(req, bucket)
+	return err
+}
+
+// Compose concatenates the sources server side into the object. All the
+// sources but the last must be at least 5 MiB.
+func (c *S3Client) Compose(ctx context.Context, sse encrypt.ServerSide, sources ...minio.CopySrcOptions) (minio.UploadInfo, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if object == "" {
+		return minio.UploadInfo{}, probe.NewError(ObjectNameEmpty{})
+	}
+	info, e := c.api.ComposeObject(ctx, minio.CopyDestOptions{
+		Bucket:     bucket,
+		Object:     object,
+		Encryption: sse,
+	}, sources...)
+	if e != nil {
+		return info, probe.NewError(e)
+	}
+	return info, nil
+}
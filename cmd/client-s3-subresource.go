@@ -0,0 +1,188 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+// subresourceRequest sends a request on a subresource of a bucket, like
+// ?cors, which the S3 SDK has no API for. The request is presigned by the
+// SDK, so it is authenticated like all the others, and sent with the
+// transport of the alias. Aliases with an auth scheme are signed by their
+// transport instead, so the request is left unsigned.
+func (c *S3Client) subresourceRequest(ctx context.Context, method, bucket, subresource string, body []byte) ([]byte, *probe.Error) {
+	return c.objectSubresourceRequest(ctx, method, bucket, "", url.Values{subresource: []string{""}}, body)
+}
+
+// objectSubresourceRequest is subresourceRequest for the subresources of
+// objects, like ?acl, the query holds the subresource and its parameters.
+func (c *S3Client) objectSubresourceRequest(ctx context.Context, method, bucket, object string, query url.Values, body []byte) ([]byte, *probe.Error) {
+	if bucket == "" {
+		return nil, probe.NewError(BucketNameEmpty{})
+	}
+	var u *url.URL
+	if c.authScheme != "" {
+		u = c.api.EndpointURL()
+		u.Path = "/" + bucket + "/" + object
+		u.RawQuery = query.Encode()
+	} else {
+		var e error
+		u, e = c.api.Presign(ctx, method, bucket, object, 15*time.Minute, query)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+	}
+	req, e := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if body != nil {
+		sum := md5.Sum(body)
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		req.Header.Set("Content-Type", "application/xml")
+		req.ContentLength = int64(len(body))
+	}
+	return c.sendRequest(req, bucket)
+}
+
+// sendRequest sends a request built outside of the SDK and returns the
+// body of the response, errors are converted to S3 error responses.
+func (c *S3Client) sendRequest(req *http.Request, bucket string) ([]byte, *probe.Error) {
+	transport := c.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, e := (&http.Client{Transport: transport}).Do(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	data, e := io.ReadAll(resp.Body)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		var errResp minio.ErrorResponse
+		if len(data) == 0 || xml.Unmarshal(data, &errResp) != nil || errResp.Code == "" {
+			errResp = minio.ErrorResponse{
+				Code:       http.StatusText(resp.StatusCode),
+				Message:    http.StatusText(resp.StatusCode),
+				BucketName: bucket,
+			}
+		}
+		errResp.StatusCode = resp.StatusCode
+		if errResp.Code == "NoSuchBucket" {
+			return nil, probe.NewError(BucketDoesNotExist{Bucket: bucket})
+		}
+		return nil, probe.NewError(errResp)
+	}
+	return data, nil
+}
+
+// GetCors returns the CORS configuration of the bucket, nil if it has none.
+func (c *S3Client) GetCors(ctx context.Context) (*corsConfig, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	data, err := c.subresourceRequest(ctx, http.MethodGet, bucket, "cors", nil)
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchCORSConfiguration" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	config := &corsConfig{}
+	if e := xml.Unmarshal(data, config); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return config, nil
+}
+
+// SetCors replaces the CORS configuration of the bucket.
+func (c *S3Client) SetCors(ctx context.Context, config *corsConfig) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	data, e := xml.Marshal(config)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	_, err := c.subresourceRequest(ctx, http.MethodPut, bucket, "cors", data)
+	return err
+}
+
+// DeleteCors removes the CORS configuration of the bucket.
+func (c *S3Client) DeleteCors(ctx context.Context) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	_, err := c.subresourceRequest(ctx, http.MethodDelete, bucket, "cors", nil)
+	return err
+}
+
+// GetWebsite returns the website configuration of the bucket, nil if it has none.
+func (c *S3Client) GetWebsite(ctx context.Context) (*websiteConfig, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	data, err := c.subresourceRequest(ctx, http.MethodGet, bucket, "website", nil)
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchWebsiteConfiguration" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	config := &websiteConfig{}
+	if e := xml.Unmarshal(data, config); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return config, nil
+}
+
+// SetWebsite replaces the website configuration of the bucket.
+func (c *S3Client) SetWebsite(ctx context.Context, config *websiteConfig) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	data, e := xml.Marshal(config)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	_, err := c.subresourceRequest(ctx, http.MethodPut, bucket, "website", data)
+	return err
+}
+
+// GetACL returns the access control list of the bucket, or of the object
+// when the URL has one. The version is optional.
+func (c *S3Client) GetACL(ctx context.Context, versionID string) (*accessControlPolicy, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	query := url.Values{"acl": []string{""}}
+	if object != "" && versionID != "" {
+		query.Set("versionId", versionID)
+	}
+	data, err := c.objectSubresourceRequest(ctx, http.MethodGet, bucket, object, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	acl := &accessControlPolicy{}
+	if e := xml.Unmarshal(data, acl); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return acl, nil
+}
@@ -61,7 +61,17 @@ type S3Client struct {
 	sync.Mutex
 	targetURL    *ClientURL
 	api          *minio.Client
+	transport    http.RoundTripper
 	virtualStyle bool
+	// authScheme is set when the SDK doesn't sign the requests, see withAuthScheme.
+	authScheme string
+	// accessPoint is set when the target is an access point, see withAccessPoint.
+	accessPoint *accessPoint
+	// directoryBucket is set when the target is a directory bucket of
+	// S3 Express One Zone, see withDirectoryBucket.
+	directoryBucket string
+	// requestPayer is the payer of the requests, see withRequestPayer.
+	requestPayer string
 }
 
 const (
@@ -96,8 +106,8 @@ type dialContext func(ctx context.Context, network, addr string) (net.Conn, erro
 func newCustomDialContext(c *Config) dialContext {
 	return func(ctx context.Context, network, addr string) (net.Conn, error) {
 		dialer := &net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 15 * time.Second,
+			Timeout:   c.connectTimeout(),
+			KeepAlive: c.tcpKeepAlive(),
 		}
 
 		conn, err := dialer.DialContext(ctx, network, addr)
@@ -118,6 +128,7 @@ var timeSentinel = time.Unix(0, 0).UTC()
 // newFactory encloses New function with client cache.
 func newFactory() func(config *Config) (Client, *probe.Error) {
 	clientCache := make(map[uint32]*minio.Client)
+	transportCache := make(map[uint32]http.RoundTripper)
 	var mutex sync.Mutex
 
 	// Return New function.
@@ -138,6 +149,12 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 		// Save if target supports virtual host style.
 		hostName := targetURL.Host
 		s3Clnt.virtualStyle = isVirtualHostStyle(hostName, config.Lookup)
+		if bucket, object := url2BucketAndObject(targetURL); bucket != "" {
+			s3Clnt.accessPoint, _ = parseAccessPoint(bucket, object, isAmazon(hostName))
+			if isDirectoryBucket(bucket) && isAmazon(hostName) {
+				s3Clnt.directoryBucket = bucket
+			}
+		}
 		isS3AcceleratedEndpoint := isAmazonAccelerated(hostName)
 
 		if s3Clnt.virtualStyle {
@@ -149,7 +166,45 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.SessionToken))
+		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.SessionToken + config.AuthScheme))
+		if config.Creds != nil {
+			confHash.Write([]byte(fmt.Sprintf("%p", config.Creds)))
+		}
+		if config.TrailingHeaders {
+			confHash.Write([]byte("trailing-headers"))
+		}
+		region := os.Getenv("MC_REGION")
+		if region == "" {
+			region = config.Region
+		}
+		// Buckets of other regions are addressed in their region, access
+		// points in the region of their ARN and directory buckets in the
+		// region of their zone. The SDK doesn't sign the requests of the
+		// multi-region access points, any region does.
+		if ap := s3Clnt.accessPoint; ap != nil {
+			confHash.Write([]byte(ap.host))
+			region = ap.region
+			if region == "" {
+				region = "us-east-1"
+			}
+		} else if s3Clnt.directoryBucket != "" {
+			confHash.Write([]byte(s3Clnt.directoryBucket))
+			if zoneRegion := availabilityZoneRegion(directoryBucketZone(s3Clnt.directoryBucket)); zoneRegion != "" {
+				region = zoneRegion
+			}
+			if region == "" {
+				region = s3utils.GetRegionFromURL(url.URL{Host: hostName})
+			}
+			if region == "" {
+				region = "us-east-1"
+			}
+		} else {
+			bucket, _ := s3Clnt.url2BucketAndObject()
+			if bucketRegion := getBucketRegion(config, hostName, bucket); bucketRegion != "" {
+				region = bucketRegion
+			}
+		}
+		confHash.Write([]byte(fmt.Sprintf("lookup=%d region=%s alias=%s", config.Lookup, region, config.Alias)))
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -164,6 +219,9 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 			if strings.ToUpper(config.Signature) == "S3V2" {
 				creds = credentials.NewStaticV2(config.AccessKey, config.SecretKey, "")
 			}
+			if config.Creds != nil {
+				creds = config.Creds
+			}
 
 			var transport http.RoundTripper
 
@@ -173,12 +231,12 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				tr := &http.Transport{
 					Proxy:                 http.ProxyFromEnvironment,
 					DialContext:           newCustomDialContext(config),
-					MaxIdleConnsPerHost:   1024,
 					WriteBufferSize:       32 << 10, // 32KiB moving up from 4KiB default
 					ReadBufferSize:        32 << 10, // 32KiB moving up from 4KiB default
 					IdleConnTimeout:       90 * time.Second,
 					TLSHandshakeTimeout:   10 * time.Second,
 					ExpectContinueTimeout: 10 * time.Second,
+					ResponseHeaderTimeout: config.ResponseHeaderTimeout,
 					// Set this value so that the underlying transport round-tripper
 					// doesn't try to auto decode the body of objects with
 					// content-encoding set to `gzip`.
@@ -210,10 +268,30 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 					// 	return nil, probe.NewError(e)
 					// }
 				}
+				tuneTransport(tr, config, 1024)
 				transport = tr
 			}
 
 			transport = limiter.New(config.UploadLimit, config.DownloadLimit, transport)
+			transport = withRequestTimeout(transport, config.RequestTimeout)
+			setMaxAttempts(config.MaxAttempts)
+
+			var e error
+			if s3Clnt.accessPoint != nil {
+				creds, transport, e = withAccessPoint(config, s3Clnt.accessPoint, creds, transport)
+			} else if s3Clnt.directoryBucket != "" {
+				creds, transport = withDirectoryBucket(s3Clnt.directoryBucket, region, creds, transport)
+			} else {
+				// The custom schemes sign the requests themselves.
+				if config.AuthScheme == "" {
+					transport = withBucketRegion(transport, config.Alias, hostName, creds)
+				}
+				creds, transport, e = withAuthScheme(config, creds, transport)
+			}
+			if e != nil {
+				return nil, probe.NewError(e)
+			}
+			transport = withRequestPayer(transport, config.RequestPayer, creds)
 
 			if config.Debug {
 				if strings.EqualFold(config.Signature, "S3v4") {
@@ -222,16 +300,24 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 					transport = httptracer.GetNewTraceTransport(newTraceV2(), transport)
 				}
 			}
+			transport = wrapHTTPTrace(transport)
 
-			// Not found. Instantiate a new MinIO
-			var e error
+			// The access points and the directory buckets are given to the
+			// SDK as a bucket in the path.
+			lookup := config.Lookup
+			if s3Clnt.accessPoint != nil || s3Clnt.directoryBucket != "" {
+				lookup = minio.BucketLookupPath
+			}
 
+			// Not found. Instantiate a new MinIO
 			options := minio.Options{
 				Creds:        creds,
 				Secure:       useTLS,
-				Region:       os.Getenv("MC_REGION"),
-				BucketLookup: config.Lookup,
+				Region:       region,
+				BucketLookup: lookup,
 				Transport:    transport,
+
+				TrailingHeaders: config.TrailingHeaders,
 			}
 
 			api, e = minio.New(hostName, &options)
@@ -249,10 +335,22 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 
 			// Cache the new MinIO Client with hash of config as key.
 			clientCache[confSum] = api
+			transportCache[confSum] = transport
 		}
 
 		// Store the new api object.
 		s3Clnt.api = api
+		s3Clnt.transport = transportCache[confSum]
+		s3Clnt.authScheme = config.AuthScheme
+		s3Clnt.requestPayer = config.RequestPayer
+		if ap := s3Clnt.accessPoint; ap != nil {
+			s3Clnt.authScheme = signatureV4
+			if ap.region == "" {
+				s3Clnt.authScheme = signatureV4A
+			}
+		} else if s3Clnt.directoryBucket != "" {
+			s3Clnt.authScheme = s3ExpressServiceName
+		}
 
 		return s3Clnt, nil
 	}
@@ -750,6 +848,7 @@ func (c *S3Client) notificationToEventsInfo(ninfo notification.Info) []EventInfo
 				UserAgent:    record.Source.UserAgent,
 			}
 		}
+		eventsInfo[i].ETag = record.S3.Object.ETag
 	}
 	return eventsInfo
 }
@@ -853,6 +952,9 @@ func (c *S3Client) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *pr
 	if opts.Zip {
 		o.Set("x-minio-extract", "true")
 	}
+	if opts.PartNumber > 0 {
+		o.PartNumber = opts.PartNumber
+	}
 	if opts.RangeStart != 0 {
 		err := o.SetRange(opts.RangeStart, 0)
 		if err != nil {
@@ -937,7 +1039,7 @@ func (c *S3Client) Copy(ctx context.Context, source string, opts CopyOptions, pr
 
 	// Assign metadata after irrelevant parts are delete above
 	destOpts.UserMetadata = metadata
-	destOpts.ReplaceMetadata = len(metadata) > 0
+	destOpts.ReplaceMetadata = opts.replaceMetadata || len(metadata) > 0
 
 	var e error
 	if opts.disableMultipart || opts.size < 64*1024*1024 {
@@ -977,6 +1079,11 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 	if bucket == "" {
 		return 0, probe.NewError(BucketNameEmpty{})
 	}
+	if c.directoryBucket != "" {
+		if err := checkDirectoryBucketPut(putOpts); err != nil {
+			return 0, err
+		}
+	}
 
 	metadata := make(map[string]string, len(putOpts.metadata))
 	for k, v := range putOpts.metadata {
@@ -1063,6 +1170,10 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		opts.RetainUntilDate = retainUntilDate
 	}
 
+	// The chunk signatures of streaming uploads cannot be computed again
+	// with the payer, see withRequestPayer.
+	opts.DisableContentSha256 = c.requestPayer != ""
+
 	if lockModeStr != "" {
 		opts.Mode = lockMode
 		opts.SendContentMd5 = true
@@ -1074,6 +1185,13 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		opts.SendContentMd5 = true
 	}
 
+	if putOpts.checksum != "" {
+		var e error
+		if size, e = setUploadChecksum(reader, size, putOpts.checksum, &opts); e != nil {
+			return 0, probe.NewError(e)
+		}
+	}
+
 	ui, e := c.api.PutObject(ctx, bucket, object, reader, size, opts)
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
@@ -1113,6 +1231,9 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		}
 		return ui.Size, probe.NewError(e)
 	}
+	if putOpts.versionID != nil {
+		*putOpts.versionID = ui.VersionID
+	}
 	return ui.Size, nil
 }
 
@@ -1513,6 +1634,10 @@ func (c *S3Client) listObjectWrapper(ctx context.Context, bucket, object string,
 		return c.listVersions(ctx, bucket, object, ListOptions{Recursive: isRecursive, TimeRef: timeRef, WithOlderVersions: withVersions, WithDeleteMarkers: withDeleteMarkers})
 	}
 
+	if c.directoryBucket != "" {
+		return c.listDirectoryBucket(ctx, bucket, object, isRecursive, maxKeys)
+	}
+
 	if isGoogle(c.targetURL.Host) {
 		// Google Cloud S3 layer doesn't implement ListObjectsV2 implementation
 		// https://github.com/kirolous/mc/issues/3073
@@ -1604,7 +1729,7 @@ func (c *S3Client) Stat(ctx context.Context, opts StatOptions) (*ClientContent,
 	// Start with a HEAD request first to return object metadata information.
 	// If the object is not found, continue to look for a directory marker or a prefix
 	if !strings.HasSuffix(path, string(c.targetURL.Separator)) && opts.timeRef.IsZero() {
-		o := minio.StatObjectOptions{ServerSideEncryption: opts.sse, VersionID: opts.versionID}
+		o := minio.StatObjectOptions{ServerSideEncryption: opts.sse, VersionID: opts.versionID, Checksum: opts.checksum}
 		if opts.isZip {
 			o.Set("x-minio-extract", "true")
 		}
@@ -1713,7 +1838,12 @@ func url2BucketAndObject(u *ClientURL) (bucketName, objectName string) {
 
 // url2BucketAndObject gives bucketName and objectName from URL path.
 func (c *S3Client) url2BucketAndObject() (bucketName, objectName string) {
-	return url2BucketAndObject(c.targetURL)
+	bucketName, objectName = url2BucketAndObject(c.targetURL)
+	if c.accessPoint != nil {
+		_, objectName = parseAccessPoint(bucketName, objectName, true)
+		bucketName = c.accessPoint.name
+	}
+	return bucketName, objectName
 }
 
 // splitPath split path into bucket and object.
@@ -1737,6 +1867,11 @@ func (c *S3Client) splitPath(path string) (bucketName, objectName string) {
 	}
 
 	tokens := splitStr(path, string(c.targetURL.Separator), 2)
+	if c.accessPoint != nil {
+		if ap, objectName := parseAccessPoint(tokens[0], tokens[1], true); ap != nil && ap.path == c.accessPoint.path {
+			return c.accessPoint.name, objectName
+		}
+	}
 	return tokens[0], tokens[1]
 }
 
@@ -1746,6 +1881,18 @@ func (c *S3Client) listVersions(ctx context.Context, b, o string, opts ListOptio
 	objectInfoCh := make(chan minio.ObjectInfo)
 	go func() {
 		defer close(objectInfoCh)
+		if c.directoryBucket != "" {
+			// Directory buckets are not versioned, versionedList lists the objects.
+			select {
+			case <-ctx.Done():
+			case objectInfoCh <- minio.ObjectInfo{Err: minio.ErrorResponse{
+				Code:       "NotImplemented",
+				Message:    "Versions are not supported for " + directoryBucketUnsupported + ".",
+				BucketName: b,
+			}}:
+			}
+			return
+		}
 		c.listVersionsRoutine(ctx, b, o, opts, objectInfoCh)
 	}()
 	return objectInfoCh
@@ -2133,6 +2280,9 @@ func (c *S3Client) joinPath(bucket string, objects ...string) string {
 
 // Build new absolute URL path by joining path segments with URL path separator.
 func (c *S3Client) buildAbsPath(bucket string, objects ...string) string {
+	if c.accessPoint != nil && bucket == c.accessPoint.name {
+		bucket = c.accessPoint.path
+	}
 	return string(c.targetURL.Separator) + c.joinPath(bucket, objects...)
 }
 
@@ -2192,6 +2342,17 @@ func (c *S3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInf
 	content.Tags = entry.UserTags
 
 	content.ReplicationStatus = entry.ReplicationStatus
+	content.Checksums = map[string]string{}
+	for algorithm, sum := range map[string]string{
+		"crc32":  entry.ChecksumCRC32,
+		"crc32c": entry.ChecksumCRC32C,
+		"sha1":   entry.ChecksumSHA1,
+		"sha256": entry.ChecksumSHA256,
+	} {
+		if sum != "" {
+			content.Checksums[algorithm] = sum
+		}
+	}
 	for k, v := range entry.UserMetadata {
 		content.UserMetadata[k] = v
 	}
@@ -2351,13 +2512,16 @@ func (c *S3Client) listRecursiveInRoutine(ctx context.Context, contentCh chan *C
 }
 
 // ShareDownload - get a usable presigned object url to share.
-func (c *S3Client) ShareDownload(ctx context.Context, versionID string, expires time.Duration) (string, *probe.Error) {
+func (c *S3Client) ShareDownload(ctx context.Context, versionID string, expires time.Duration, responseHeaders map[string]string) (string, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
-	// No additional request parameters are set for the time being.
 	reqParams := make(url.Values)
 	if versionID != "" {
 		reqParams.Set("versionId", versionID)
 	}
+	// Override the headers of the response, e.g. response-content-disposition.
+	for k, v := range responseHeaders {
+		reqParams.Set("response-"+strings.ToLower(k), v)
+	}
 	presignedURL, e := c.api.PresignedGetObject(ctx, bucket, object, expires, reqParams)
 	if e != nil {
 		return "", probe.NewError(e)
@@ -2397,6 +2561,9 @@ func (c *S3Client) ShareUpload(ctx context.Context, isRecursive bool, expires ti
 
 // SetObjectLockConfig - Set object lock configurataion of bucket.
 func (c *S3Client) SetObjectLockConfig(ctx context.Context, mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit) *probe.Error {
+	if err := c.checkDirectoryBucket("Object lock"); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 
 	if bucket == "" || object != "" {
@@ -2424,6 +2591,9 @@ func (c *S3Client) SetObjectLockConfig(ctx context.Context, mode minio.Retention
 
 // PutObjectRetention - Set object retention for a given object.
 func (c *S3Client) PutObjectRetention(ctx context.Context, versionID string, mode minio.RetentionMode, retainUntilDate time.Time, bypassGovernance bool) *probe.Error {
+	if err := c.checkDirectoryBucket("Retention"); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 
 	var (
@@ -2455,6 +2625,9 @@ func (c *S3Client) PutObjectRetention(ctx context.Context, versionID string, mod
 
 // GetObjectRetention - Get object retention for a given object.
 func (c *S3Client) GetObjectRetention(ctx context.Context, versionID string) (minio.RetentionMode, time.Time, *probe.Error) {
+	if err := c.checkDirectoryBucket("Retention"); err != nil {
+		return "", time.Time{}, err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if object == "" {
 		return "", time.Time{}, probe.NewError(ObjectNameEmpty{}).Trace(c.GetURL().String())
@@ -2478,6 +2651,9 @@ func (c *S3Client) GetObjectRetention(ctx context.Context, versionID string) (mi
 
 // PutObjectLegalHold - Set object legal hold for a given object.
 func (c *S3Client) PutObjectLegalHold(ctx context.Context, versionID string, lhold minio.LegalHoldStatus) *probe.Error {
+	if err := c.checkDirectoryBucket("Legal hold"); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if lhold.IsValid() {
 		opts := minio.PutObjectLegalHoldOptions{
@@ -2495,6 +2671,9 @@ func (c *S3Client) PutObjectLegalHold(ctx context.Context, versionID string, lho
 
 // GetObjectLegalHold - Get object legal hold for a given object.
 func (c *S3Client) GetObjectLegalHold(ctx context.Context, versionID string) (minio.LegalHoldStatus, *probe.Error) {
+	if err := c.checkDirectoryBucket("Legal hold"); err != nil {
+		return "", err
+	}
 	var lhold minio.LegalHoldStatus
 	bucket, object := c.url2BucketAndObject()
 	opts := minio.GetObjectLegalHoldOptions{
@@ -2517,6 +2696,9 @@ func (c *S3Client) GetObjectLegalHold(ctx context.Context, versionID string) (mi
 
 // GetObjectLockConfig - Get object lock configuration of bucket.
 func (c *S3Client) GetObjectLockConfig(ctx context.Context) (string, minio.RetentionMode, uint64, minio.ValidityUnit, *probe.Error) {
+	if err := c.checkDirectoryBucket("Object lock"); err != nil {
+		return "", "", 0, "", err
+	}
 	bucket, object := c.url2BucketAndObject()
 
 	if bucket == "" || object != "" {
@@ -2539,6 +2721,9 @@ func (c *S3Client) GetObjectLockConfig(ctx context.Context) (string, minio.Reten
 
 // GetTags - Get tags of bucket or object.
 func (c *S3Client) GetTags(ctx context.Context, versionID string) (map[string]string, *probe.Error) {
+	if err := c.checkDirectoryBucket("Tags"); err != nil {
+		return nil, err
+	}
 	bucketName, objectName := c.url2BucketAndObject()
 	if bucketName == "" {
 		return nil, probe.NewError(BucketNameEmpty{})
@@ -2567,6 +2752,9 @@ func (c *S3Client) GetTags(ctx context.Context, versionID string) (map[string]st
 
 // SetTags - Set tags of bucket or object.
 func (c *S3Client) SetTags(ctx context.Context, versionID, tagString string) *probe.Error {
+	if err := c.checkDirectoryBucket("Tags"); err != nil {
+		return err
+	}
 	bucketName, objectName := c.url2BucketAndObject()
 	if bucketName == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2595,6 +2783,9 @@ func (c *S3Client) SetTags(ctx context.Context, versionID, tagString string) *pr
 
 // DeleteTags - Delete tags of bucket or object
 func (c *S3Client) DeleteTags(ctx context.Context, versionID string) *probe.Error {
+	if err := c.checkDirectoryBucket("Tags"); err != nil {
+		return err
+	}
 	bucketName, objectName := c.url2BucketAndObject()
 	if bucketName == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2648,6 +2839,9 @@ func (c *S3Client) SetLifecycle(ctx context.Context, config *lifecycle.Configura
 
 // GetVersion - gets bucket version info.
 func (c *S3Client) GetVersion(ctx context.Context) (config minio.BucketVersioningConfiguration, err *probe.Error) {
+	if err = c.checkDirectoryBucket("Versioning"); err != nil {
+		return config, err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return config, probe.NewError(BucketNameEmpty{})
@@ -2663,6 +2857,9 @@ func (c *S3Client) GetVersion(ctx context.Context) (config minio.BucketVersionin
 
 // SetVersion - Set version configuration on a bucket
 func (c *S3Client) SetVersion(ctx context.Context, status string, prefixes []string, excludeFolders bool) *probe.Error {
+	if err := c.checkDirectoryBucket("Versioning"); err != nil {
+		return err
+	}
 	bucket, _ := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2913,7 +3110,10 @@ func (c *S3Client) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error)
 }
 
 // Restore gets a copy of an archived object
-func (c *S3Client) Restore(ctx context.Context, versionID string, days int) *probe.Error {
+func (c *S3Client) Restore(ctx context.Context, versionID string, days int, tier minio.TierType) *probe.Error {
+	if err := c.checkDirectoryBucket("Restore"); err != nil {
+		return err
+	}
 	bucket, object := c.url2BucketAndObject()
 	if bucket == "" {
 		return probe.NewError(BucketNameEmpty{})
@@ -2924,7 +3124,7 @@ func (c *S3Client) Restore(ctx context.Context, versionID string, days int) *pro
 
 	req := minio.RestoreRequest{}
 	req.SetDays(days)
-	req.SetGlacierJobParameters(minio.GlacierJobParameters{Tier: minio.TierExpedited})
+	req.SetGlacierJobParameters(minio.GlacierJobParameters{Tier: tier})
 	if err := c.api.RestoreObject(ctx, bucket, object, versionID, req); err != nil {
 		return probe.NewError(err)
 	}
@@ -264,3 +264,69 @@ func (s *TestSuite) TestSelectCompressionType(c *C) {
 		c.Assert(cType, DeepEquals, test.compressionType)
 	}
 }
+
+// corsHandler serves the ?cors subresource of a bucket.
+type corsHandler struct {
+	config *[]byte
+}
+
+func (h corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		return
+	}
+	if _, ok := r.URL.Query()["cors"]; !ok || r.URL.Query().Get("X-Amz-Signature") == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case "GET":
+		if *h.config == nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchCORSConfiguration</Code><Message>The CORS configuration does not exist</Message></Error>`))
+			return
+		}
+		w.Write(*h.config)
+	case "PUT":
+		if r.Header.Get("Content-MD5") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*h.config, _ = io.ReadAll(r.Body)
+	case "DELETE":
+		*h.config = nil
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Test the CORS configuration of a bucket.
+func (s *TestSuite) TestCors(c *C) {
+	var stored []byte
+	server := httptest.NewServer(corsHandler{config: &stored})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	clnt, err := S3New(conf)
+	c.Assert(err, IsNil)
+	s3c := clnt.(*S3Client)
+
+	config, err := s3c.GetCors(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(config, IsNil)
+
+	rule := corsRule{ID: "web", AllowedOrigins: []string{"https://*.example.com"}, AllowedMethods: []string{"GET"}}
+	err = s3c.SetCors(context.Background(), &corsConfig{Rules: []corsRule{rule}})
+	c.Assert(err, IsNil)
+
+	config, err = s3c.GetCors(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(config.Rules, DeepEquals, []corsRule{rule})
+
+	err = s3c.DeleteCors(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(stored, IsNil)
+}
@@ -0,0 +1,355 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sftpDefaultPort is used when the alias URL has no port.
+const sftpDefaultPort = "22"
+
+// sftpConns caches the SFTP sessions by server and user, the session is
+// shared by the parallel transfers and dropped when the connection closes.
+var (
+	sftpConnsMu sync.Mutex
+	sftpConns   = map[string]*sftp.Client{}
+)
+
+// sftpStore is a blobStore of an SFTP server, the directories right
+// below the root are the buckets.
+type sftpStore struct {
+	client *sftp.Client
+}
+
+// sftpNew returns a client of an SFTP server, the access key is the user
+// and the secret key a password, a private key or the path to a private
+// key. The SSH agent is used without a secret key.
+func sftpNew(config *Config) (Client, *probe.Error) {
+	targetURL := newClientURL(config.HostURL)
+	address := targetURL.Host
+	if _, _, e := net.SplitHostPort(address); e != nil {
+		address = net.JoinHostPort(address, sftpDefaultPort)
+	}
+	dial, e := config.proxyDialer("sftp", address)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(address)
+	}
+	client, e := sftpConnect(dial, address, config.AccessKey, config.SecretKey)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(address)
+	}
+	return &blobClient{store: &sftpStore{client: client}, targetURL: targetURL}, nil
+}
+
+// sftpConnect returns the cached session of the user on the server, or
+// opens a new one.
+func sftpConnect(dial dialContext, address, user, secret string) (*sftp.Client, error) {
+	key := address + "\x00" + user + "\x00" + secret
+
+	sftpConnsMu.Lock()
+	defer sftpConnsMu.Unlock()
+	if client, ok := sftpConns[key]; ok {
+		return client, nil
+	}
+
+	auth, e := sftpAuthMethods(secret)
+	if e != nil {
+		return nil, e
+	}
+	hostKeyCallback, e := sftpHostKeyCallback()
+	if e != nil {
+		return nil, e
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	netConn, e := dial(ctx, "tcp", address)
+	if e != nil {
+		return nil, e
+	}
+	// Bound the SSH handshake as ssh.Dial does.
+	netConn.SetDeadline(time.Now().Add(30 * time.Second))
+	sshConn, chans, reqs, e := ssh.NewClientConn(netConn, address, &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	})
+	if e != nil {
+		netConn.Close()
+		return nil, e
+	}
+	netConn.SetDeadline(time.Time{})
+	conn := ssh.NewClient(sshConn, chans, reqs)
+	client, e := sftp.NewClient(conn, sftp.UseConcurrentReads(true), sftp.UseConcurrentWrites(true))
+	if e != nil {
+		conn.Close()
+		return nil, e
+	}
+	sftpConns[key] = client
+	go func() {
+		client.Wait()
+		sftpConnsMu.Lock()
+		delete(sftpConns, key)
+		sftpConnsMu.Unlock()
+	}()
+	return client, nil
+}
+
+// sftpAuthMethods returns the SSH authentication of the secret key.
+func sftpAuthMethods(secret string) ([]ssh.AuthMethod, error) {
+	if secret == "" {
+		socket := os.Getenv("SSH_AUTH_SOCK")
+		if socket == "" {
+			return nil, errors.New("no password or private key, and no SSH agent is running")
+		}
+		conn, e := net.Dial("unix", socket)
+		if e != nil {
+			return nil, fmt.Errorf("unable to connect to the SSH agent: %w", e)
+		}
+		return []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(conn).Signers)}, nil
+	}
+
+	pemBytes := []byte(secret)
+	if !strings.Contains(secret, "PRIVATE KEY") {
+		pemBytes, _ = os.ReadFile(secret)
+	}
+	if len(pemBytes) > 0 {
+		signer, e := ssh.ParsePrivateKey(pemBytes)
+		if e != nil {
+			return nil, fmt.Errorf("invalid private key: %w", e)
+		}
+		return []ssh.AuthMethod{ssh.PublicKeys(signer)}, nil
+	}
+
+	return []ssh.AuthMethod{
+		ssh.Password(secret),
+		ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+			answers := make([]string, len(questions))
+			for i := range answers {
+				answers[i] = secret
+			}
+			return answers, nil
+		}),
+	}, nil
+}
+
+// sftpHostKeyCallback verifies the host keys with the known hosts of the
+// user, unless --insecure is set.
+func sftpHostKeyCallback() (ssh.HostKeyCallback, error) {
+	if globalInsecure {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	home, e := os.UserHomeDir()
+	if e != nil {
+		return nil, e
+	}
+	callback, e := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if e != nil {
+		return nil, fmt.Errorf("unable to verify the host key: %w, add the server to the known hosts or use --insecure", e)
+	}
+	return callback, nil
+}
+
+func (s *sftpStore) kind() string {
+	return "sftp"
+}
+
+// toStoreError maps the errors of the server to the errors of the stores.
+func (s *sftpStore) toStoreError(e error, notFound error) error {
+	if errors.Is(e, os.ErrNotExist) {
+		return notFound
+	}
+	return e
+}
+
+func (s *sftpStore) listBuckets(_ context.Context) ([]blobInfo, error) {
+	entries, e := s.client.ReadDir("/")
+	if e != nil {
+		return nil, e
+	}
+	var buckets []blobInfo
+	for _, entry := range entries {
+		if entry.IsDir() {
+			buckets = append(buckets, blobInfo{Name: entry.Name(), ModTime: entry.ModTime(), IsDir: true})
+		}
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Name < buckets[j].Name })
+	return buckets, nil
+}
+
+func (s *sftpStore) makeBucket(_ context.Context, bucket string) error {
+	return s.client.Mkdir("/" + bucket)
+}
+
+func (s *sftpStore) removeBucket(_ context.Context, bucket string) error {
+	return s.toStoreError(s.client.RemoveDirectory("/"+bucket), errBlobBucketNotFound)
+}
+
+// list walks the directories of the prefix, the directory names end with
+// '/' so that the keys are listed in the same order as by S3.
+func (s *sftpStore) list(ctx context.Context, bucket, prefix string, delimited bool, fn func(blobInfo) error) error {
+	if _, e := s.client.Stat("/" + bucket); e != nil {
+		return s.toStoreError(e, errBlobBucketNotFound)
+	}
+	dir, base := "", prefix
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir, base = prefix[:i+1], prefix[i+1:]
+	}
+	e := s.walk(ctx, bucket, dir, base, delimited, fn)
+	if errors.Is(e, os.ErrNotExist) {
+		return nil
+	}
+	return e
+}
+
+// walk lists the entries of the directory starting with base.
+func (s *sftpStore) walk(ctx context.Context, bucket, dir, base string, delimited bool, fn func(blobInfo) error) error {
+	entries, e := s.client.ReadDir(path.Join("/", bucket, dir))
+	if e != nil {
+		return e
+	}
+	names := make(map[string]os.FileInfo, len(entries))
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || strings.HasSuffix(name, blobPartialSuffix) {
+			continue
+		}
+		if entry.IsDir() {
+			name += "/"
+		}
+		names[name] = entry
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	for _, name := range keys {
+		if e := ctx.Err(); e != nil {
+			return e
+		}
+		entry := names[name]
+		switch {
+		case entry.IsDir() && delimited:
+			e = fn(blobInfo{Name: dir + name, ModTime: entry.ModTime(), IsDir: true})
+		case entry.IsDir():
+			e = s.walk(ctx, bucket, dir+name, "", false, fn)
+		case entry.Mode().IsRegular():
+			e = fn(blobInfo{Name: dir + name, Size: entry.Size(), ModTime: entry.ModTime()})
+		}
+		if e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *sftpStore) stat(_ context.Context, bucket, object string) (blobInfo, error) {
+	fi, e := s.client.Stat(path.Join("/", bucket, object))
+	if e != nil {
+		return blobInfo{}, s.toStoreError(e, errBlobNotFound)
+	}
+	if !fi.Mode().IsRegular() {
+		return blobInfo{}, errBlobNotFound
+	}
+	return blobInfo{Name: object, Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
+
+// get opens the file at the offset, which resumes interrupted copies.
+func (s *sftpStore) get(_ context.Context, bucket, object string, offset int64) (io.ReadCloser, error) {
+	f, e := s.client.Open(path.Join("/", bucket, object))
+	if e != nil {
+		return nil, s.toStoreError(e, errBlobNotFound)
+	}
+	if offset > 0 {
+		if _, e = f.Seek(offset, io.SeekStart); e != nil {
+			f.Close()
+			return nil, e
+		}
+	}
+	return f, nil
+}
+
+// put uploads to a partial file renamed once complete, the parent
+// directories are created as needed.
+func (s *sftpStore) put(_ context.Context, bucket, object string, reader io.Reader, _ int64, _ string, _ map[string]string) error {
+	name := path.Join("/", bucket, object)
+	if _, e := s.client.Stat("/" + bucket); e != nil {
+		return s.toStoreError(e, errBlobBucketNotFound)
+	}
+	if e := s.client.MkdirAll(path.Dir(name)); e != nil {
+		return e
+	}
+	partial := name + blobPartialSuffix
+	f, e := s.client.Create(partial)
+	if e != nil {
+		return e
+	}
+	if _, e = f.ReadFrom(reader); e != nil {
+		f.Close()
+		s.client.Remove(partial)
+		return e
+	}
+	if e = f.Close(); e != nil {
+		s.client.Remove(partial)
+		return e
+	}
+	if e = s.client.PosixRename(partial, name); e != nil {
+		// Servers without the posix-rename extension do not replace
+		// existing files.
+		s.client.Remove(name)
+		e = s.client.Rename(partial, name)
+	}
+	return e
+}
+
+// remove deletes a file, or an empty directory when object ends with '/',
+// and then the parent directories left empty like the filesystem client.
+func (s *sftpStore) remove(_ context.Context, bucket, object string) error {
+	name := path.Join("/", bucket, object)
+	var e error
+	if strings.HasSuffix(object, "/") {
+		e = s.client.RemoveDirectory(name)
+	} else {
+		e = s.client.Remove(name)
+	}
+	if e != nil {
+		return s.toStoreError(e, errBlobNotFound)
+	}
+	for dir := path.Dir(name); dir != path.Join("/", bucket); dir = path.Dir(dir) {
+		if s.client.RemoveDirectory(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
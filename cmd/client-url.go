@@ -104,7 +104,7 @@ func newClientURL(urlStr string) *ClientURL {
 			rest = "/"
 		}
 		host := getHost(authority)
-		if host != "" && (scheme == "http" || scheme == "https") {
+		if host != "" && (scheme == "http" || scheme == "https" || scheme == "sftp" || scheme == "hdfs") {
 			return &ClientURL{
 				Scheme:          scheme,
 				Type:            objectStorage,
@@ -19,6 +19,8 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"io"
 	"net/http"
 	"os"
@@ -26,6 +28,7 @@ import (
 
 	"github.com/kirolous/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/encrypt"
 	"github.com/minio/minio-go/v7/pkg/lifecycle"
 	"github.com/minio/minio-go/v7/pkg/replication"
@@ -49,6 +52,8 @@ type GetOptions struct {
 	VersionID  string
 	Zip        bool
 	RangeStart int64
+	// PartNumber selects a part of a multipart object, 0 for the whole object.
+	PartNumber int
 }
 
 // PutOptions holds options for PUT operation
@@ -61,6 +66,10 @@ type PutOptions struct {
 	multipartSize         uint64
 	multipartThreads      uint
 	concurrentStream      bool
+	// checksum is the algorithm of the additional checksum sent, see setUploadChecksum.
+	checksum string
+	// versionID receives the version created by the upload.
+	versionID *string
 }
 
 // StatOptions holds options of the HEAD operation
@@ -71,6 +80,8 @@ type StatOptions struct {
 	timeRef    time.Time
 	versionID  string
 	isZip      bool
+	// checksum requests the checksums stored by the server.
+	checksum bool
 }
 
 // ListOptions holds options for listing operation
@@ -95,6 +106,7 @@ type CopyOptions struct {
 	disableMultipart bool
 	isPreserve       bool
 	storageClass     string
+	replaceMetadata  bool
 }
 
 // Client - client interface
@@ -134,7 +146,7 @@ type Client interface {
 	GetObjectLegalHold(ctx context.Context, versionID string) (minio.LegalHoldStatus, *probe.Error)
 
 	// I/O operations with expiration
-	ShareDownload(ctx context.Context, versionID string, expires time.Duration) (string, *probe.Error)
+	ShareDownload(ctx context.Context, versionID string, expires time.Duration, responseHeaders map[string]string) (string, *probe.Error)
 	ShareUpload(context.Context, bool, time.Duration, string) (string, map[string]string, *probe.Error)
 
 	// Watch events
@@ -174,7 +186,7 @@ type Client interface {
 	GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error)
 
 	// Restore an object
-	Restore(ctx context.Context, versionID string, days int) *probe.Error
+	Restore(ctx context.Context, versionID string, days int, tier minio.TierType) *probe.Error
 
 	// OD operations
 	GetPart(ctx context.Context, part int) (io.ReadCloser, *probe.Error)
@@ -211,6 +223,9 @@ type ClientContent struct {
 
 	Restore *minio.RestoreInfo
 
+	// Checksums stored by the server by algorithm, base64 encoded.
+	Checksums map[string]string
+
 	Err *probe.Error
 }
 
@@ -231,6 +246,35 @@ type Config struct {
 	UploadLimit       int64
 	DownloadLimit     int64
 	Transport         *http.Transport
+	// Timeouts of the HTTP requests, zero uses the defaults.
+	RequestTimeout        time.Duration
+	ConnectTimeout        time.Duration
+	ResponseHeaderTimeout time.Duration
+	// MaxAttempts is the number of attempts of failed requests, zero uses the default.
+	MaxAttempts int
+	// Connection pool and protocol settings, zero uses the defaults.
+	MaxIdleConnsPerHost int
+	DisableHTTP2        bool
+	TCPKeepAlive        time.Duration
+	// Proxy overrides the proxy environment variables, see 'mc alias set --proxy'.
+	Proxy   string
+	NoProxy string
+	// ClientCert and RootCAs replace the mTLS certificate and the trusted
+	// CAs of mc, see 'mc alias set --client-cert'.
+	ClientCert *tls.Certificate
+	RootCAs    *x509.CertPool
+	// AuthScheme signs the requests instead of the SDK, see authSchemes.
+	AuthScheme string
+	// Creds overrides the static keys when set.
+	Creds *credentials.Credentials
+	// TrailingHeaders sends the checksums of the parts as trailers.
+	TrailingHeaders bool
+	// Region is the region of the alias, looked up per bucket when empty.
+	Region string
+	// Alias of the config, used to cache the regions of its buckets.
+	Alias string
+	// RequestPayer is sent as x-amz-request-payer, e.g. "requester".
+	RequestPayer string
 }
 
 // SelectObjectOpts - opts entered for select API
@@ -0,0 +1,306 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The metadata recording the client side compression and encryption of
+// an object, reversed when it is downloaded.
+const (
+	codecCompressionMeta = "X-Amz-Meta-Mc-Compression"
+	codecEncryptionMeta  = "X-Amz-Meta-Mc-Encryption"
+)
+
+// mcAgeIdentity is the file of the age identities decrypting the objects.
+const mcAgeIdentity = "MC_AGE_IDENTITY"
+
+// checkCodecOptions validates the values of --compress and --encrypt-with.
+func checkCodecOptions(compress, encryptWith string) error {
+	switch compress {
+	case "", "gzip", "zstd":
+	default:
+		return fmt.Errorf("unsupported compression %s, valid options are [gzip, zstd]", compress)
+	}
+	switch {
+	case encryptWith == "":
+	case strings.HasPrefix(encryptWith, "age1"):
+		if _, e := age.ParseX25519Recipient(encryptWith); e != nil {
+			return e
+		}
+	default:
+		if _, e := exec.LookPath("gpg"); e != nil {
+			return fmt.Errorf("gpg is required to encrypt for %s: %w", encryptWith, e)
+		}
+	}
+	return nil
+}
+
+// codecWriter closes the writers of the encoding pipeline in order.
+type codecWriter struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (w *codecWriter) Close() error {
+	for _, c := range w.closers {
+		if e := c.Close(); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// newCodecEncoder compresses and then encrypts the reader, an age
+// recipient starts with 'age1' and any other recipient is encrypted for
+// by gpg. It returns the metadata to set on the object.
+func newCodecEncoder(reader io.Reader, compress, encryptWith string) (io.ReadCloser, map[string]string) {
+	metadata := map[string]string{}
+	pr, pw := io.Pipe()
+
+	go func() {
+		w := &codecWriter{Writer: pw}
+		fail := func(e error) {
+			pw.CloseWithError(e)
+		}
+
+		switch {
+		case encryptWith == "":
+		case strings.HasPrefix(encryptWith, "age1"):
+			recipient, e := age.ParseX25519Recipient(encryptWith)
+			if e != nil {
+				fail(e)
+				return
+			}
+			encrypter, e := age.Encrypt(w.Writer, recipient)
+			if e != nil {
+				fail(e)
+				return
+			}
+			w.Writer, w.closers = encrypter, append([]io.Closer{encrypter}, w.closers...)
+		default:
+			encrypter, e := startGPG(nil, w.Writer, "--encrypt", "--trust-model", "always", "--recipient", encryptWith)
+			if e != nil {
+				fail(e)
+				return
+			}
+			w.Writer, w.closers = encrypter, append([]io.Closer{encrypter}, w.closers...)
+		}
+
+		switch compress {
+		case "gzip":
+			compressor := gzip.NewWriter(w.Writer)
+			w.Writer, w.closers = compressor, append([]io.Closer{compressor}, w.closers...)
+		case "zstd":
+			compressor, e := zstd.NewWriter(w.Writer)
+			if e != nil {
+				fail(e)
+				return
+			}
+			w.Writer, w.closers = compressor, append([]io.Closer{compressor}, w.closers...)
+		}
+
+		_, e := io.Copy(w, reader)
+		// The errors of gpg are only known once it exits.
+		if ce := w.Close(); ce != nil {
+			e = ce
+		}
+		pw.CloseWithError(e)
+	}()
+
+	if compress != "" {
+		metadata[codecCompressionMeta] = compress
+	}
+	switch {
+	case encryptWith == "":
+	case strings.HasPrefix(encryptWith, "age1"):
+		metadata[codecEncryptionMeta] = "age"
+	default:
+		metadata[codecEncryptionMeta] = "gpg"
+	}
+	return pr, metadata
+}
+
+// isCodecEncoded returns true when the metadata records a client side
+// compression or encryption.
+func isCodecEncoded(metadata map[string]string) bool {
+	return metadata[codecCompressionMeta] != "" || metadata[codecEncryptionMeta] != ""
+}
+
+// codecReader closes the readers of the decoding pipeline in order.
+type codecReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *codecReader) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// newCodecDecoder reverses the compression and the encryption recorded in
+// the metadata of the object.
+func newCodecDecoder(reader io.ReadCloser, metadata map[string]string) (io.ReadCloser, error) {
+	r := &codecReader{Reader: reader, closers: []io.Closer{reader}}
+
+	switch encryption := metadata[codecEncryptionMeta]; encryption {
+	case "":
+	case "age":
+		identityFile := os.Getenv(mcAgeIdentity)
+		if identityFile == "" {
+			return nil, errors.New("the object is encrypted with age, set " + mcAgeIdentity + " to the file of the identity")
+		}
+		f, e := os.Open(identityFile)
+		if e != nil {
+			return nil, e
+		}
+		identities, e := age.ParseIdentities(f)
+		f.Close()
+		if e != nil {
+			return nil, e
+		}
+		if r.Reader, e = age.Decrypt(r.Reader, identities...); e != nil {
+			return nil, e
+		}
+	case "gpg":
+		decrypter, e := startGPG(r.Reader, nil, "--decrypt")
+		if e != nil {
+			return nil, e
+		}
+		// The source is closed first so that gpg stops reading it.
+		r.Reader, r.closers = decrypter, append(r.closers, decrypter)
+	default:
+		return nil, fmt.Errorf("unsupported client side encryption %s", encryption)
+	}
+
+	switch compression := metadata[codecCompressionMeta]; compression {
+	case "":
+	case "gzip":
+		decompressor, e := gzip.NewReader(r.Reader)
+		if e != nil {
+			return nil, e
+		}
+		r.Reader, r.closers = decompressor, append([]io.Closer{decompressor}, r.closers...)
+	case "zstd":
+		decompressor, e := zstd.NewReader(r.Reader)
+		if e != nil {
+			return nil, e
+		}
+		r.Reader, r.closers = decompressor, append([]io.Closer{decompressor.IOReadCloser()}, r.closers...)
+	default:
+		return nil, fmt.Errorf("unsupported client side compression %s", compression)
+	}
+	return r, nil
+}
+
+// gpgProcess streams data through gpg, writing to its input encrypts and
+// reading from its output decrypts.
+type gpgProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr bytes.Buffer
+	done   bool
+	err    error
+}
+
+// startGPG starts gpg in batch mode, with the input or the output piped
+// when nil.
+func startGPG(stdin io.Reader, stdout io.Writer, args ...string) (*gpgProcess, error) {
+	p := &gpgProcess{cmd: exec.Command("gpg", append([]string{"--batch", "--quiet", "--yes", "--output", "-"}, args...)...)}
+	p.cmd.Stderr = &p.stderr
+	var e error
+	if stdin != nil {
+		p.cmd.Stdin = stdin
+	} else if p.stdin, e = p.cmd.StdinPipe(); e != nil {
+		return nil, e
+	}
+	if stdout != nil {
+		p.cmd.Stdout = stdout
+	} else if p.stdout, e = p.cmd.StdoutPipe(); e != nil {
+		return nil, e
+	}
+	if e = p.cmd.Start(); e != nil {
+		return nil, e
+	}
+	return p, nil
+}
+
+// wait returns the error of gpg once it exits.
+func (p *gpgProcess) wait() error {
+	if !p.done {
+		p.done = true
+		if e := p.cmd.Wait(); e != nil {
+			p.err = fmt.Errorf("gpg: %v: %s", e, strings.TrimSpace(p.stderr.String()))
+		}
+	}
+	return p.err
+}
+
+func (p *gpgProcess) Write(b []byte) (int, error) {
+	return p.stdin.Write(b)
+}
+
+func (p *gpgProcess) Read(b []byte) (int, error) {
+	n, e := p.stdout.Read(b)
+	if e == io.EOF {
+		if we := p.wait(); we != nil {
+			return n, we
+		}
+	}
+	return n, e
+}
+
+// Close ends the input of gpg, or discards its output, and waits for it.
+func (p *gpgProcess) Close() error {
+	if p.stdin != nil {
+		p.stdin.Close()
+	}
+	if p.stdout != nil && !p.done {
+		io.Copy(io.Discard, p.stdout)
+	}
+	return p.wait()
+}
+
+// codecMetadata returns the metadata of the codecs with canonical keys.
+func codecMetadata(metadata map[string]string) map[string]string {
+	codec := map[string]string{}
+	for k, v := range metadata {
+		switch k = http.CanonicalHeaderKey(k); k {
+		case codecCompressionMeta, codecEncryptionMeta:
+			codec[k] = v
+		}
+	}
+	return codec
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	identity, e := age.GenerateX25519Identity()
+	if e != nil {
+		t.Fatal(e)
+	}
+	identityFile := filepath.Join(t.TempDir(), "key.txt")
+	if e = os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	t.Setenv(mcAgeIdentity, identityFile)
+
+	data := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog\n", 1000))
+	testCases := []struct {
+		compress, encryptWith string
+	}{
+		{compress: "gzip"},
+		{compress: "zstd"},
+		{encryptWith: identity.Recipient().String()},
+		{compress: "zstd", encryptWith: identity.Recipient().String()},
+	}
+	for i, testCase := range testCases {
+		encoded, metadata := newCodecEncoder(bytes.NewReader(data), testCase.compress, testCase.encryptWith)
+		stored, e := io.ReadAll(encoded)
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if bytes.Equal(stored, data) || !isCodecEncoded(metadata) {
+			t.Fatalf("Test %d: the data was not encoded", i+1)
+		}
+
+		decoded, e := newCodecDecoder(io.NopCloser(bytes.NewReader(stored)), codecMetadata(metadata))
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		got, e := io.ReadAll(decoded)
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if e = decoded.Close(); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("Test %d: the decoded data differs", i+1)
+		}
+	}
+}
+
+func TestCatDecodesCodec(t *testing.T) {
+	data := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog\n", 100))
+	encoded, metadata := newCodecEncoder(bytes.NewReader(data), "gzip", "")
+	stored, e := io.ReadAll(encoded)
+	if e != nil {
+		t.Fatal(e)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		if r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for k, v := range metadata {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(stored)))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("ETag", `"etag"`)
+		if r.Method == http.MethodGet {
+			w.Write(stored)
+		}
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+	f, e := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer f.Close()
+	os.Stdout = f
+
+	if err := catURL(context.Background(), "myminio/bucket/object", nil, catOpts{}); err != nil {
+		t.Fatal(err)
+	}
+	got, e := os.ReadFile(f.Name())
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("expected the decompressed data, got %d bytes", len(got))
+	}
+
+	// Offsets in the compressed data are rejected.
+	if err := catURL(context.Background(), "myminio/bucket/object", nil, catOpts{tailO: 10}); err == nil {
+		t.Error("expected --tail to fail")
+	}
+}
@@ -20,7 +20,9 @@ package cmd
 import (
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"hash"
 	"io"
 	"net/http"
 	"os"
@@ -35,6 +37,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/hookreader"
 	"github.com/kirolous/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/encrypt"
@@ -474,23 +477,33 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 		legalHold = urls.TargetContent.LegalHold
 	}
 
-	for k, v := range urls.SourceContent.UserMetadata {
-		metadata[http.CanonicalHeaderKey(k)] = v
-	}
-	for k, v := range urls.SourceContent.Metadata {
-		metadata[http.CanonicalHeaderKey(k)] = v
+	if !urls.ReplaceMetadata {
+		for k, v := range urls.SourceContent.UserMetadata {
+			metadata[http.CanonicalHeaderKey(k)] = v
+		}
+		for k, v := range urls.SourceContent.Metadata {
+			metadata[http.CanonicalHeaderKey(k)] = v
+		}
 	}
 
-	// Optimize for server side copy if the host is same.
-	if sourceAlias == targetAlias && !isZip {
+	// Optimize for server side copy if the host is same, unless the data
+	// is verified or transformed by the client.
+	clientSide := urls.SourceDigest != "" || urls.Compress != "" || urls.EncryptWith != "" || urls.Checksum != "" || urls.Transform != ""
+	if sourceAlias == targetAlias && !isZip && !clientSide && !isHTTPSource(sourceAlias, sourceURL.String()) {
+		// A copy with new metadata replaces all the metadata of the object,
+		// with the COPY directive the one of the source is kept.
+		mergeSource := !urls.ReplaceMetadata && (len(urls.TargetContent.Metadata) > 0 || len(urls.TargetContent.UserMetadata) > 0)
+
 		// preserve new metadata and save existing ones.
-		if preserve {
+		if preserve || mergeSource {
 			currentMetadata, err := getAllMetadata(ctx, sourceAlias, sourceURL.String(), srcSSE, urls)
 			if err != nil {
 				return urls.WithError(err.Trace(sourceURL.String()))
 			}
 			for k, v := range currentMetadata {
-				metadata[k] = v
+				if preserve || metaKey(k) == k {
+					metadata[k] = v
+				}
 			}
 		}
 
@@ -517,6 +530,7 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			disableMultipart: urls.DisableMultipart,
 			isPreserve:       preserve,
 			storageClass:     urls.TargetContent.StorageClass,
+			replaceMetadata:  urls.ReplaceMetadata,
 		}
 
 		err = copySourceToTargetURL(ctx, targetAlias, targetURL.String(), sourcePath, sourceVersion, mode, until,
@@ -563,6 +577,16 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			return urls.WithError(err.Trace(sourceURL.String()))
 		}
 		defer reader.Close()
+		if urls.ReplaceMetadata && targetURL.Type != fileSystem {
+			metadata = map[string]string{}
+		}
+
+		var e error
+		if urls.SourceDigest != "" {
+			if reader, e = newDigestReader(reader, urls.SourceDigest, length); e != nil {
+				return urls.WithError(probe.NewError(e).Trace(sourceURL.String()))
+			}
+		}
 
 		// Get metadata from target content as well
 		for k, v := range urls.TargetContent.Metadata {
@@ -574,7 +598,41 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			metadata[http.CanonicalHeaderKey(k)] = v
 		}
 
-		var e error
+		// Pipe the objects through --transform, the size of the result
+		// is unknown. The progress is of the source.
+		if urls.Transform != "" {
+			transformed, e := newTransformReader(ctx, hookreader.NewHook(reader, progress), urls.Transform)
+			if e != nil {
+				return urls.WithError(probe.NewError(e).Trace(sourceURL.String()))
+			}
+			defer transformed.Close()
+			if urls.Transform == transformGunzip {
+				delete(metadata, "Content-Encoding")
+			}
+			reader, length, progress = transformed, -1, nil
+		}
+
+		// Compress and encrypt the uploads, or reverse it for the objects
+		// downloaded to the filesystem. The progress is of the source.
+		if urls.Compress != "" || urls.EncryptWith != "" {
+			encoded, codecMeta := newCodecEncoder(hookreader.NewHook(reader, progress), urls.Compress, urls.EncryptWith)
+			defer encoded.Close()
+			for k, v := range codecMeta {
+				metadata[k] = v
+			}
+			reader, length, progress = encoded, -1, nil
+		} else if codec := codecMetadata(metadata); isCodecEncoded(codec) && targetURL.Type == fileSystem {
+			decoded, e := newCodecDecoder(io.NopCloser(hookreader.NewHook(reader, progress)), codec)
+			if e != nil {
+				return urls.WithError(probe.NewError(e).Trace(sourceURL.String()))
+			}
+			defer decoded.Close()
+			for k := range codec {
+				delete(metadata, k)
+			}
+			reader, length, progress = decoded, -1, nil
+		}
+
 		var multipartSize uint64
 		if v := env.Get("MC_UPLOAD_MULTIPART_SIZE", ""); v != "" {
 			multipartSize, e = humanize.ParseBytes(v)
@@ -598,13 +656,32 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			multipartSize:    multipartSize,
 			multipartThreads: uint(multipartThreads),
 		}
+		if targetURL.Type != fileSystem {
+			putOpts.checksum = urls.Checksum
+		}
 
-		if isReadAt(reader) {
-			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
-				legalHold, reader, length, progress, putOpts)
+		// The manifest of cp records the checksum of the uploaded content
+		// and the version created by the upload.
+		var body io.Reader = reader
+		var manifestHash hash.Hash
+		if urls.manifest != nil {
+			if body, manifestHash, e = urls.manifest.hashUpload(reader, length); e != nil {
+				return urls.WithError(probe.NewError(e).Trace(sourceURL.String()))
+			}
+			putOpts.versionID = &urls.TargetContent.VersionID
+		}
+
+		var n int64
+		if isReadAt(body) || length < 0 {
+			n, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
+				legalHold, body, length, progress, putOpts)
 		} else {
-			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
-				legalHold, io.LimitReader(reader, length), length, progress, putOpts)
+			n, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
+				legalHold, io.LimitReader(body, length), length, progress, putOpts)
+		}
+		if err == nil && manifestHash != nil {
+			urls.TargetContent.Size = n
+			urls.manifestChecksum = hex.EncodeToString(manifestHash.Sum(nil))
 		}
 	}
 	if err != nil {
@@ -624,6 +701,10 @@ func newClientFromAlias(alias, urlStr string) (Client, *probe.Error) {
 	}
 
 	if hostCfg == nil {
+		// Plain HTTP(S) URLs are read from the web server.
+		if isHTTPSourceURL(urlStr) {
+			return httpSourceNew(urlStr)
+		}
 		// No matching host config. So we treat it like a
 		// filesystem.
 		fsClient, fsErr := fsNew(urlStr)
@@ -634,6 +715,22 @@ func newClientFromAlias(alias, urlStr string) (Client, *probe.Error) {
 	}
 
 	s3Config := NewS3Config(urlStr, hostCfg)
+	s3Config.Alias = alias
+
+	api := strings.ToLower(hostCfg.API)
+	if scheme := newClientURL(hostCfg.URL).Scheme; scheme == "sftp" || scheme == "hdfs" {
+		api = scheme
+	}
+	switch api {
+	case "azure":
+		return azureNew(s3Config)
+	case "gcs":
+		return gcsNew(s3Config)
+	case "sftp":
+		return sftpNew(s3Config)
+	case "hdfs":
+		return hdfsNew(s3Config)
+	}
 
 	s3Client, err := S3New(s3Config)
 	if err != nil {
@@ -643,7 +740,7 @@ func newClientFromAlias(alias, urlStr string) (Client, *probe.Error) {
 }
 
 // urlRgx - verify if aliased url is real URL.
-var urlRgx = regexp.MustCompile("^https?://")
+var urlRgx = regexp.MustCompile("^(https?|sftp|hdfs)://")
 
 // newClient gives a new client interface
 func newClient(aliasedURL string) (Client, *probe.Error) {
@@ -652,8 +749,9 @@ func newClient(aliasedURL string) (Client, *probe.Error) {
 		return nil, err.Trace(aliasedURL)
 	}
 	// Verify if the aliasedURL is a real URL, fail in those cases
-	// indicating the user to add alias.
-	if hostCfg == nil && urlRgx.MatchString(aliasedURL) {
+	// indicating the user to add alias. Plain HTTP(S) URLs are only
+	// allowed as read-only sources.
+	if hostCfg == nil && urlRgx.MatchString(aliasedURL) && !isHTTPSourceURL(aliasedURL) {
 		return nil, errInvalidAliasedURL(aliasedURL).Trace(aliasedURL)
 	}
 	return newClientFromAlias(alias, urlStrFull)
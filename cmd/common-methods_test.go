@@ -18,8 +18,12 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -69,3 +73,77 @@ func TestGetDecodedKey(t *testing.T) {
 		}
 	}
 }
+
+func TestUploadSourceToTargetURLServerSideMetadata(t *testing.T) {
+	var copyHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		switch r.Method {
+		case http.MethodHead:
+			w.Header().Set("Cache-Control", "max-age=3600")
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("X-Amz-Meta-Owner", "web")
+			w.Header().Set("Last-Modified", "Sat, 01 Jan 2022 00:00:00 GMT")
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Content-Length", "4")
+		case http.MethodPut:
+			copyHeader = r.Header.Clone()
+			w.Write([]byte(`<CopyObjectResult><ETag>"etag"</ETag><LastModified>2022-01-01T00:00:00.000Z</LastModified></CopyObjectResult>`))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	testCases := []struct {
+		metadata     map[string]string
+		userMetadata map[string]string
+		replace      bool
+		expected     map[string]string
+	}{
+		// The metadata of the source is kept with the COPY directive.
+		{
+			metadata: map[string]string{"Content-Type": "text/html"},
+			expected: map[string]string{"Cache-Control": "max-age=3600", "Content-Type": "text/html", "X-Amz-Meta-Owner": "web", "X-Amz-Meta-Last-Modified": ""},
+		},
+		{
+			userMetadata: map[string]string{"team": "site"},
+			expected:     map[string]string{"Cache-Control": "max-age=3600", "X-Amz-Meta-Owner": "web", "X-Amz-Meta-Team": "site"},
+		},
+		// Only the new metadata is set with the REPLACE directive.
+		{
+			userMetadata: map[string]string{"team": "site"},
+			replace:      true,
+			expected:     map[string]string{"Cache-Control": "", "X-Amz-Meta-Owner": "", "X-Amz-Meta-Team": "site"},
+		},
+		// Without new metadata the server copies it.
+		{
+			expected: map[string]string{"Cache-Control": "", "X-Amz-Metadata-Directive": ""},
+		},
+	}
+	for i, testCase := range testCases {
+		copyHeader = nil
+		urls := URLs{
+			SourceAlias:     "myminio",
+			SourceContent:   &ClientContent{URL: *newClientURL(server.URL + "/bucket/index.html"), Size: 4},
+			TargetAlias:     "myminio",
+			TargetContent:   &ClientContent{URL: *newClientURL(server.URL + "/bucket/index.html"), Metadata: testCase.metadata, UserMetadata: testCase.userMetadata},
+			ReplaceMetadata: testCase.replace,
+		}
+		if urls = uploadSourceToTargetURL(context.Background(), urls, nil, nil, false, false); urls.Error != nil {
+			t.Fatalf("Test %d: %v", i+1, urls.Error)
+		}
+		if copyHeader == nil {
+			t.Fatalf("Test %d: expected a server side copy", i+1)
+		}
+		for k, v := range testCase.expected {
+			if got := copyHeader.Get(k); got != v {
+				t.Errorf("Test %d: expected %s=%q, got %q", i+1, k, v, got)
+			}
+		}
+	}
+}
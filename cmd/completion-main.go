@@ -0,0 +1,200 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/env"
+)
+
+// mcEnvCompletionCache enables caching of bucket names for completion.
+const mcEnvCompletionCache = "MC_COMPLETION_CACHE"
+
+var completionFlags = []cli.Flag{
+	cli.DurationFlag{
+		Name:  "cache-buckets",
+		Usage: "cache the bucket names completed for an alias for the duration, e.g. 5m",
+	},
+}
+
+var completionCmd = cli.Command{
+	Name:         "completion",
+	Usage:        "generate shell completion scripts",
+	Action:       mainCompletion,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(completionFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] bash|zsh|fish|powershell
+
+  The scripts ask mc for the completions, aliases, buckets and object
+  names are completed from the configured aliases.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Enable completion in the current bash shell.
+     {{.Prompt}} source <({{.HelpName}} bash)
+
+  2. Install completion for zsh, caching bucket names for 5 minutes.
+     {{.Prompt}} {{.HelpName}} --cache-buckets 5m zsh > "${fpath[1]}/_mc"
+
+  3. Install completion for fish.
+     {{.Prompt}} {{.HelpName}} fish > ~/.config/fish/completions/mc.fish
+
+  4. Enable completion in PowerShell.
+     PS> {{.HelpName}} powershell | Out-String | Invoke-Expression
+`,
+}
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for {{.Cmd}}
+complete -o nospace -C '{{.Env}}{{.Cmd}}' {{.Cmd}}
+`,
+	"zsh": `#compdef {{.Cmd}}
+# zsh completion for {{.Cmd}}
+autoload -U +X bashcompinit && bashcompinit
+complete -o nospace -C '{{.Env}}{{.Cmd}}' {{.Cmd}}
+`,
+	"fish": `# fish completion for {{.Cmd}}
+function __complete_{{.Cmd}}
+    set -lx COMP_LINE (commandline -cp)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    {{.Env}}{{.Cmd}} {{.Cmd}}
+end
+complete -f -c {{.Cmd}} -a "(__complete_{{.Cmd}})"
+`,
+	"powershell": `# PowerShell completion for {{.Cmd}}
+Register-ArgumentCompleter -Native -CommandName '{{.Cmd}}' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $line = $commandAst.ToString()
+    $point = $cursorPosition - $commandAst.Extent.StartOffset
+    if ($point -lt $line.Length) { $line = $line.Substring(0, $point) }
+    if ($wordToComplete -eq '') { $line += ' ' }
+    $env:COMP_LINE = $line
+    {{if .CacheBuckets}}$env:{{.CacheEnv}} = '{{.CacheBuckets}}'
+    {{end}}& '{{.Cmd}}' '{{.Cmd}}' | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+    Remove-Item Env:COMP_LINE{{if .CacheBuckets}}, Env:{{.CacheEnv}}{{end}}
+}
+`,
+}
+
+// mainCompletion is the handle for "mc completion" command.
+func mainCompletion(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	script, err := completionScript(ctx.Args().First(), os.Args[0], ctx.Duration("cache-buckets"))
+	fatalIf(err, "Unsupported shell, must be one of bash, zsh, fish or powershell.")
+	fmt.Print(script)
+	return nil
+}
+
+// completionScript returns the completion script of the shell for the
+// program, caching the bucket names for cacheBuckets when positive.
+func completionScript(shell, program string, cacheBuckets time.Duration) (string, *probe.Error) {
+	shell = strings.ToLower(shell)
+	script, ok := completionScripts[shell]
+	if !ok {
+		return "", errInvalidArgument().Trace(shell)
+	}
+
+	params := struct {
+		Cmd, Env, CacheEnv, CacheBuckets string
+	}{
+		Cmd:      filepath.Base(program),
+		CacheEnv: mcEnvCompletionCache,
+	}
+	if strings.HasSuffix(strings.ToLower(params.Cmd), ".exe") {
+		params.Cmd = params.Cmd[:len(params.Cmd)-len(".exe")]
+	}
+	if cacheBuckets > 0 {
+		params.CacheBuckets = cacheBuckets.String()
+		params.Env = "env " + mcEnvCompletionCache + "=" + cacheBuckets.String() + " "
+		if shell == "fish" {
+			params.Env = mcEnvCompletionCache + "=" + cacheBuckets.String() + " "
+		}
+	}
+
+	tmpl := template.Must(template.New(shell).Parse(script))
+	var buf strings.Builder
+	if e := tmpl.Execute(&buf, params); e != nil {
+		return "", probe.NewError(e)
+	}
+	return buf.String(), nil
+}
+
+// completionCache is the list of buckets of an alias cached for completion.
+type completionCache struct {
+	Updated time.Time `json:"updated"`
+	Buckets []string  `json:"buckets"`
+}
+
+func getCompletionCachePath(alias string) string {
+	return filepath.Join(mustGetMcConfigDir(), "completion-cache", alias+".json")
+}
+
+// loadCompletionCache returns the cached buckets of the alias, when
+// caching is enabled and the cache did not expire.
+func loadCompletionCache(alias string) ([]string, bool) {
+	ttl, e := time.ParseDuration(env.Get(mcEnvCompletionCache, ""))
+	if e != nil || ttl <= 0 {
+		return nil, false
+	}
+	buf, e := os.ReadFile(getCompletionCachePath(alias))
+	if e != nil {
+		return nil, false
+	}
+	var cache completionCache
+	if e = json.Unmarshal(buf, &cache); e != nil || time.Since(cache.Updated) > ttl {
+		return nil, false
+	}
+	return cache.Buckets, true
+}
+
+// saveCompletionCache caches the buckets of the alias, when caching is enabled.
+func saveCompletionCache(alias string, buckets []string) {
+	if env.Get(mcEnvCompletionCache, "") == "" {
+		return
+	}
+	buf, e := json.Marshal(completionCache{Updated: time.Now(), Buckets: buckets})
+	if e != nil {
+		return
+	}
+	path := getCompletionCachePath(alias)
+	if e = os.MkdirAll(filepath.Dir(path), 0o700); e != nil {
+		return
+	}
+	os.WriteFile(path, buf, 0o600)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompletionScript(t *testing.T) {
+	testCases := []struct {
+		shell        string
+		program      string
+		cacheBuckets time.Duration
+		expected     []string
+		err          bool
+	}{
+		{"bash", "/usr/local/bin/mc", 0, []string{"complete -o nospace -C 'mc' mc"}, false},
+		{"BASH", "mc", 5 * time.Minute, []string{"complete -o nospace -C 'env MC_COMPLETION_CACHE=5m0s mc' mc"}, false},
+		{"zsh", "mcli", 0, []string{"#compdef mcli", "complete -o nospace -C 'mcli' mcli"}, false},
+		{"fish", "mc", time.Hour, []string{"MC_COMPLETION_CACHE=1h0m0s mc mc", "complete -f -c mc"}, false},
+		// The extension of the program is not part of the command on windows.
+		{"powershell", `mc.EXE`, 0, []string{"-CommandName 'mc'", "Remove-Item Env:COMP_LINE\n"}, false},
+		{"powershell", "mc", time.Minute, []string{"$env:MC_COMPLETION_CACHE = '1m0s'", "Remove-Item Env:COMP_LINE, Env:MC_COMPLETION_CACHE"}, false},
+		{"tcsh", "mc", 0, nil, true},
+	}
+	for i, testCase := range testCases {
+		script, err := completionScript(testCase.shell, testCase.program, testCase.cacheBuckets)
+		if (err != nil) != testCase.err {
+			t.Fatalf("Test %d: expected error %t, got %v", i+1, testCase.err, err)
+		}
+		for _, expected := range testCase.expected {
+			if !strings.Contains(script, expected) {
+				t.Errorf("Test %d: expected %q in %q", i+1, expected, script)
+			}
+		}
+		if testCase.cacheBuckets == 0 && strings.Contains(script, mcEnvCompletionCache) {
+			t.Errorf("Test %d: expected no bucket cache in %q", i+1, script)
+		}
+	}
+}
+
+func TestCompletionCache(t *testing.T) {
+	defer func(dir string) { setMcConfigDir(dir) }(mcCustomConfigDir)
+	setMcConfigDir(t.TempDir())
+
+	// The buckets are not cached without MC_COMPLETION_CACHE.
+	t.Setenv(mcEnvCompletionCache, "")
+	saveCompletionCache("myminio", []string{"myminio/a/"})
+	if _, e := os.Stat(getCompletionCachePath("myminio")); e == nil {
+		t.Error("expected no completion cache")
+	}
+
+	t.Setenv(mcEnvCompletionCache, "1h")
+	saveCompletionCache("myminio", []string{"myminio/a/", "myminio/b/"})
+	if buckets, ok := loadCompletionCache("myminio"); !ok || !reflect.DeepEqual(buckets, []string{"myminio/a/", "myminio/b/"}) {
+		t.Errorf("expected the cached buckets, got %v %t", buckets, ok)
+	}
+	if _, ok := loadCompletionCache("other"); ok {
+		t.Error("expected no cached buckets for another alias")
+	}
+
+	// The cache expires after the duration.
+	buf, e := json.Marshal(completionCache{Updated: time.Now().Add(-2 * time.Hour), Buckets: []string{"myminio/a/"}})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e = os.WriteFile(getCompletionCachePath("myminio"), buf, 0o600); e != nil {
+		t.Fatal(e)
+	}
+	if _, ok := loadCompletionCache("myminio"); ok {
+		t.Error("expected the cache to expire")
+	}
+	t.Setenv(mcEnvCompletionCache, "3h")
+	if _, ok := loadCompletionCache("myminio"); !ok {
+		t.Error("expected the cache to be valid for 3h")
+	}
+	t.Setenv(mcEnvCompletionCache, "")
+	if _, ok := loadCompletionCache("myminio"); ok {
+		t.Error("expected the cache not to be used without MC_COMPLETION_CACHE")
+	}
+}
+
+func TestCompleteS3PathCache(t *testing.T) {
+	defer func(dir string) { setMcConfigDir(dir) }(mcCustomConfigDir)
+	setMcConfigDir(t.TempDir())
+	t.Setenv(mcEnvCompletionCache, "1h")
+
+	var listed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		if r.URL.Path != "/" {
+			// The objects of a single bucket completed.
+			w.Write([]byte(`<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Name>videos</Name><IsTruncated>false</IsTruncated>` +
+				`<Contents><Key>intro.mp4</Key><Size>1</Size><LastModified>2023-01-01T00:00:00.000Z</LastModified></Contents></ListBucketResult>`))
+			return
+		}
+		listed++
+		w.Write([]byte(`<ListAllMyBucketsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Buckets>` +
+			`<Bucket><Name>photos</Name><CreationDate>2023-01-01T00:00:00.000Z</CreationDate></Bucket>` +
+			`<Bucket><Name>podcasts</Name><CreationDate>2023-01-01T00:00:00.000Z</CreationDate></Bucket>` +
+			`<Bucket><Name>videos</Name><CreationDate>2023-01-01T00:00:00.000Z</CreationDate></Bucket>` +
+			`</Buckets></ListAllMyBucketsResult>`))
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	testCases := []struct {
+		path     string
+		expected []string
+		listed   int
+	}{
+		{"myminio/p", []string{"myminio/photos/", "myminio/podcasts/"}, 1},
+		// The bucket names are completed from the cache.
+		{"myminio/v", []string{"myminio/videos/", "myminio/videos/intro.mp4"}, 1},
+		{"myminio/", []string{"myminio/photos/", "myminio/podcasts/", "myminio/videos/"}, 1},
+	}
+	for i, testCase := range testCases {
+		if prediction := completeS3Path(testCase.path); !reflect.DeepEqual(prediction, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, prediction)
+		}
+		if listed != testCase.listed {
+			t.Errorf("Test %d: expected %d listing(s), got %d", i+1, testCase.listed, listed)
+		}
+	}
+}
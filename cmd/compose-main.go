@@ -0,0 +1,157 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
+)
+
+var composeCmd = cli.Command{
+	Name:         "compose",
+	Usage:        "concatenate objects server side",
+	Action:       mainCompose,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(ioFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET SOURCE [SOURCE...]
+
+  Concatenate the sources in the given order into the target, with multipart
+  copies on the server without downloading them. The sources must be on the
+  alias of the target, all of them but the last must be at least 5 MiB. The
+  target may be one of the sources.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Concatenate the logs of a day into a single object.
+     {{.Prompt}} {{.HelpName}} myminio/logs/2023-05-01.log myminio/logs/2023-05-01/00.log myminio/logs/2023-05-01/01.log myminio/logs/2023-05-01/02.log
+`,
+}
+
+// composeMessage container for a composed object
+type composeMessage struct {
+	Status  string   `json:"status"`
+	Target  string   `json:"target"`
+	Sources []string `json:"sources"`
+	Size    int64    `json:"size"`
+	ETag    string   `json:"etag"`
+}
+
+func (c composeMessage) String() string {
+	return console.Colorize("Compose", fmt.Sprintf("Composed `%s` from %d object(s)", c.Target, len(c.Sources))) +
+		fmt.Sprintf(" (%s)", humanize.IBytes(uint64(c.Size)))
+}
+
+func (c composeMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// newComposeClient returns the S3 client of an object, appending and
+// composing are S3 only features.
+func newComposeClient(aliasedURL string) *S3Client {
+	client, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize connection.")
+
+	s3Client, ok := client.(*S3Client)
+	if !ok {
+		fatalIf(errDummy().Trace(aliasedURL), "The provided url doesn't point to a S3 server.")
+	}
+	return s3Client
+}
+
+// composeSource returns the source of a composition for an object, with
+// the ETag it must match when not empty.
+func composeSource(aliasedURL, etag string, encKeyDB map[string][]prefixSSEPair) minio.CopySrcOptions {
+	alias, _, _ := mustExpandAlias(aliasedURL)
+	clnt := newComposeClient(aliasedURL)
+	bucket, object := clnt.url2BucketAndObject()
+	return minio.CopySrcOptions{
+		Bucket:     bucket,
+		Object:     object,
+		MatchETag:  etag,
+		Encryption: getSSE(filepath.Join(alias, clnt.GetURL().Path), encKeyDB[alias]),
+	}
+}
+
+func checkComposeSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+	targetAlias, _, _ := mustExpandAlias(ctx.Args().Get(0))
+	for _, arg := range ctx.Args() {
+		alias, _, _ := mustExpandAlias(arg)
+		if alias == "" || alias != targetAlias {
+			fatalIf(errInvalidArgument().Trace(arg), "The target and the sources must be objects on the same alias.")
+		}
+		if strings.HasSuffix(arg, "/") {
+			fatalIf(errInvalidArgument().Trace(arg), "The target and the sources must be objects.")
+		}
+	}
+}
+
+// mainCompose is the handle for "mc compose" command.
+func mainCompose(cliCtx *cli.Context) error {
+	ctx, cancelCompose := context.WithCancel(globalContext)
+	defer cancelCompose()
+
+	checkComposeSyntax(cliCtx)
+	console.SetColor("Compose", color.New(color.FgGreen))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	targetURL := cliCtx.Args().Get(0)
+	sourceURLs := cliCtx.Args().Tail()
+	sources := make([]minio.CopySrcOptions, 0, len(sourceURLs))
+	for _, sourceURL := range sourceURLs {
+		sources = append(sources, composeSource(sourceURL, "", encKeyDB))
+	}
+
+	target := composeSource(targetURL, "", encKeyDB)
+	clnt := newComposeClient(targetURL)
+	_, err = clnt.Compose(ctx, target.Encryption, sources...)
+	fatalIf(err.Trace(targetURL), "Unable to compose `"+targetURL+"`.")
+
+	st, err := clnt.Stat(ctx, StatOptions{sse: target.Encryption})
+	fatalIf(err.Trace(targetURL), "Unable to stat `"+targetURL+"`.")
+	printMsg(composeMessage{
+		Target:  targetURL,
+		Sources: sourceURLs,
+		Size:    st.Size,
+		ETag:    strings.Trim(st.ETag, "\""),
+	})
+	return nil
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+// +build !windows
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"golang.org/x/sys/unix"
+)
+
+// lockConfigFile takes an exclusive advisory lock on '<config>.lock', the
+// lock is held until the returned function is called.
+func lockConfigFile(configPath string) (func(), *probe.Error) {
+	f, e := os.OpenFile(configPath+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	lock := unix.Flock_t{Type: unix.F_WRLCK}
+	for {
+		e = unix.FcntlFlock(f.Fd(), unix.F_SETLKW, &lock)
+		if e != unix.EINTR {
+			break
+		}
+	}
+	if e != nil {
+		f.Close()
+		return nil, probe.NewError(e)
+	}
+	return func() {
+		lock.Type = unix.F_UNLCK
+		unix.FcntlFlock(f.Fd(), unix.F_SETLK, &lock)
+		f.Close()
+	}, nil
+}
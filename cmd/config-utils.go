@@ -52,7 +52,7 @@ func trimTrailingSeparator(hostURL string) string {
 func isValidHostURL(hostURL string) (ok bool) {
 	if strings.TrimSpace(hostURL) != "" {
 		url := newClientURL(hostURL)
-		if url.Scheme == "https" || url.Scheme == "http" {
+		if url.Scheme == "https" || url.Scheme == "http" || url.Scheme == "sftp" || url.Scheme == "hdfs" {
 			if url.Path == "/" {
 				ok = true
 			}
@@ -64,12 +64,22 @@ func isValidHostURL(hostURL string) (ok bool) {
 // isValidAPI - Validates if API signature string of supported type.
 func isValidAPI(api string) (ok bool) {
 	switch strings.ToLower(api) {
-	case "s3v2", "s3v4":
+	case "s3v2", "s3v4", "azure", "gcs", "sftp", "hdfs":
 		ok = true
 	}
 	return ok
 }
 
+// isValidSignature - validates the signature of 'mc alias set --signature'.
+func isValidSignature(signature string) bool {
+	for _, v := range validSignatures() {
+		if strings.EqualFold(signature, v) {
+			return true
+		}
+	}
+	return false
+}
+
 // isValidLookup - validates if bucket lookup is of valid type
 func isValidLookup(lookup string) (ok bool) {
 	l := strings.ToLower(strings.TrimSpace(lookup))
@@ -33,6 +33,10 @@ func TestValidHostURL(t *testing.T) {
 			hostURL: "/",
 			isHost:  false,
 		},
+		{
+			hostURL: "sftp://ftp.example.com:2222",
+			isHost:  true,
+		},
 	}
 
 	for _, testCase := range testCases {
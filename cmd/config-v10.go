@@ -18,6 +18,9 @@
 package cmd
 
 import (
+	"encoding/json"
+	"os"
+	"reflect"
 	"sync"
 
 	"github.com/kirolous/mc/pkg/probe"
@@ -32,6 +35,9 @@ const (
 var (
 	// set once during first load.
 	cacheCfgV10 *configV10
+	// copy of the config as loaded, the changes made since are merged
+	// with the changes of other processes when saving.
+	baseCfgV10 *configV10
 	// All access to mc config file should be synchronized.
 	cfgMutex = &sync.RWMutex{}
 )
@@ -41,17 +47,73 @@ type aliasConfigV10 struct {
 	URL          string `json:"url"`
 	AccessKey    string `json:"accessKey"`
 	SecretKey    string `json:"secretKey"`
+	SecretStore  string `json:"secretStore,omitempty"`
 	SessionToken string `json:"sessionToken,omitempty"`
 	API          string `json:"api"`
 	Path         string `json:"path"`
 	License      string `json:"license,omitempty"`
 	APIKey       string `json:"apiKey,omitempty"`
+	SubnetProxy  string `json:"subnetProxy,omitempty"`
+	// CredentialSource replaces the static keys when set.
+	CredentialSource *aliasCredentialSource `json:"credentialSource,omitempty"`
+	// Protection is either "readonly" or "guard", see 'mc alias protect'.
+	Protection string `json:"protection,omitempty"`
+	// Defaults maps flag names to the values used when not given on the command line.
+	Defaults map[string]string `json:"defaults,omitempty"`
+	// Timeouts and retries of the HTTP requests, see 'mc alias set --request-timeout'.
+	RequestTimeout        string `json:"requestTimeout,omitempty"`
+	ConnectTimeout        string `json:"connectTimeout,omitempty"`
+	ResponseHeaderTimeout string `json:"responseHeaderTimeout,omitempty"`
+	Retries               *int   `json:"retries,omitempty"`
+	// Transport settings, see 'mc alias set --max-idle-conns-per-host'.
+	MaxIdleConnsPerHost int    `json:"maxIdleConnsPerHost,omitempty"`
+	DisableHTTP2        *bool  `json:"disableHTTP2,omitempty"`
+	TCPKeepAlive        string `json:"tcpKeepAlive,omitempty"`
+	// Proxy is a proxy URL or "none", it overrides the proxy environment
+	// variables for the hosts not matched by NoProxy.
+	Proxy   string `json:"proxy,omitempty"`
+	NoProxy string `json:"noProxy,omitempty"`
+	// PEM files of the mTLS certificate and of the CAs trusted for the alias.
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
+	CABundle   string `json:"caBundle,omitempty"`
+	// Signature is the auth scheme of the alias when not an S3 signature
+	// of the SDK, "v4a" or "spnego".
+	Signature string `json:"signature,omitempty"`
+	// Region is the region of the requests, MC_REGION takes precedence.
+	Region string `json:"region,omitempty"`
+	// RequestPayer is sent as x-amz-request-payer, see 'mc alias set --request-payer'.
+	RequestPayer string `json:"requestPayer,omitempty"`
 }
 
 // configV10 config version.
 type configV10 struct {
 	Version string                    `json:"version"`
 	Aliases map[string]aliasConfigV10 `json:"aliases"`
+	// AuditLog is the client side audit log used when --audit-log is not given.
+	AuditLog string `json:"auditLog,omitempty"`
+	// CurrentAlias is used when the arguments of a command omit the alias.
+	CurrentAlias string `json:"currentAlias,omitempty"`
+	// Groups maps a group name to a list of aliases.
+	Groups map[string][]string `json:"groups,omitempty"`
+	// Notify maps a profile name to the destination of --notify-on-completion.
+	Notify map[string]notifyProfileV10 `json:"notify,omitempty"`
+}
+
+// notifyProfileV10 is a destination of the notifications sent when a
+// command finishes, see 'mc cp --notify-on-completion'.
+type notifyProfileV10 struct {
+	// Type is either "desktop", "webhook" or "smtp".
+	Type string `json:"type"`
+	// Endpoint and AuthToken of a webhook.
+	Endpoint  string `json:"endpoint,omitempty"`
+	AuthToken string `json:"authToken,omitempty"`
+	// Host is the host:port of the SMTP server.
+	Host     string   `json:"host,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
 }
 
 // newConfigV10 - new config version.
@@ -138,16 +200,113 @@ func loadConfigV10() (*configV10, *probe.Error) {
 
 	// Cache config.
 	cacheCfgV10 = cfgV10
+	baseCfgV10 = cloneConfigV10(cfgV10)
 
 	// Success.
 	return cfgV10, nil
 }
 
-// saveConfigV10 - saves an updated config.
+// cloneConfigV10 - deep copy of a config.
+func cloneConfigV10(cfgV10 *configV10) *configV10 {
+	data, e := json.Marshal(cfgV10)
+	if e != nil {
+		return nil
+	}
+	clone := newConfigV10()
+	if e = json.Unmarshal(data, clone); e != nil {
+		return nil
+	}
+	return clone
+}
+
+// mergeConfigV10 - three-way merge of the config saved by this process
+// with the config saved by another process since base was loaded. The
+// aliases, groups and settings changed by this process win, the others
+// are taken from disk.
+func mergeConfigV10(base, ours, disk *configV10) *configV10 {
+	if base == nil {
+		base = newConfigV10()
+	}
+	merged := cloneConfigV10(ours)
+	if merged == nil {
+		return ours
+	}
+
+	aliases := map[string]bool{}
+	for _, m := range []map[string]aliasConfigV10{base.Aliases, ours.Aliases, disk.Aliases} {
+		for alias := range m {
+			aliases[alias] = true
+		}
+	}
+	for alias := range aliases {
+		b, inBase := base.Aliases[alias]
+		o, inOurs := ours.Aliases[alias]
+		if inBase == inOurs && reflect.DeepEqual(b, o) {
+			if d, ok := disk.Aliases[alias]; ok {
+				merged.Aliases[alias] = d
+			} else {
+				delete(merged.Aliases, alias)
+			}
+		}
+	}
+
+	groups := map[string]bool{}
+	for _, m := range []map[string][]string{base.Groups, ours.Groups, disk.Groups} {
+		for group := range m {
+			groups[group] = true
+		}
+	}
+	for group := range groups {
+		b, inBase := base.Groups[group]
+		o, inOurs := ours.Groups[group]
+		if inBase == inOurs && reflect.DeepEqual(b, o) {
+			if d, ok := disk.Groups[group]; ok {
+				if merged.Groups == nil {
+					merged.Groups = map[string][]string{}
+				}
+				merged.Groups[group] = d
+			} else {
+				delete(merged.Groups, group)
+			}
+		}
+	}
+
+	if ours.AuditLog == base.AuditLog {
+		merged.AuditLog = disk.AuditLog
+	}
+	if reflect.DeepEqual(ours.Notify, base.Notify) {
+		merged.Notify = disk.Notify
+	}
+	if ours.CurrentAlias == base.CurrentAlias {
+		merged.CurrentAlias = disk.CurrentAlias
+	}
+	return merged
+}
+
+// saveConfigV10 - saves an updated config. The config file is locked while
+// the changes made by other processes are merged and the file is replaced.
 func saveConfigV10(cfgV10 *configV10) *probe.Error {
 	cfgMutex.Lock()
 	defer cfgMutex.Unlock()
 
+	configPath := mustGetMcConfigPath()
+	unlock, err := lockConfigFile(configPath)
+	if err != nil {
+		return err.Trace(configPath)
+	}
+	defer unlock()
+
+	if _, e := os.Stat(configPath); e == nil {
+		qd, e := quick.NewConfig(newConfigV10(), nil)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		if e = qd.Load(configPath); e != nil {
+			return probe.NewError(e).Trace(configPath)
+		}
+		cfgV10 = mergeConfigV10(baseCfgV10, cfgV10, qd.Data().(*configV10))
+	}
+
 	qs, e := quick.NewConfig(cfgV10, nil)
 	if e != nil {
 		return probe.NewError(e)
@@ -155,10 +314,11 @@ func saveConfigV10(cfgV10 *configV10) *probe.Error {
 
 	// update the cache.
 	cacheCfgV10 = cfgV10
+	baseCfgV10 = cloneConfigV10(cfgV10)
 
-	e = qs.Save(mustGetMcConfigPath())
+	e = qs.Save(configPath)
 	if e != nil {
-		return probe.NewError(e).Trace(mustGetMcConfigPath())
+		return probe.NewError(e).Trace(configPath)
 	}
 	return nil
 }
@@ -36,16 +36,64 @@ import (
 // mcCustomConfigDir contains the whole path to config dir. Only access via get/set functions.
 var mcCustomConfigDir string
 
+// mcConfigProfile is the name of the config profile in use, see --profile.
+var mcConfigProfile = os.Getenv(mcEnvConfigProfile)
+
+// mcEnvConfigProfile selects a config profile like --profile.
+const mcEnvConfigProfile = "MC_CONFIG_PROFILE"
+
 // setMcConfigDir - set a custom MinIO Client config folder.
 func setMcConfigDir(configDir string) {
 	mcCustomConfigDir = configDir
 }
 
-// getMcConfigDir - construct MinIO Client config folder.
+// setMcConfigProfile - select a named config profile.
+func setMcConfigProfile(profile string) {
+	mcConfigProfile = profile
+}
+
+// isValidConfigProfile - profile names are used as folder names.
+func isValidConfigProfile(profile string) bool {
+	return isValidAlias(profile)
+}
+
+// xdgMcConfigDir - MinIO Client folder under the XDG config folder,
+// '$XDG_CONFIG_HOME/mc' or '~/.config/mc'.
+func xdgMcConfigDir() (string, *probe.Error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "mc"), nil
+	}
+	if runtime.GOOS == "windows" {
+		dir, e := os.UserConfigDir()
+		if e != nil {
+			return "", probe.NewError(e)
+		}
+		return filepath.Join(dir, "mc"), nil
+	}
+	homeDir, e := homedir.Dir()
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	return filepath.Join(homeDir, ".config", "mc"), nil
+}
+
+// getMcConfigDir - construct MinIO Client config folder. Profiles live in
+// 'mc-profiles/NAME' next to the XDG folder, the XDG folder is otherwise
+// used when it holds a config file, instead of the legacy '~/.mc'.
 func getMcConfigDir() (string, *probe.Error) {
 	if mcCustomConfigDir != "" {
 		return mcCustomConfigDir, nil
 	}
+	xdgDir, err := xdgMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	if mcConfigProfile != "" {
+		return filepath.Join(filepath.Dir(xdgDir), "mc-profiles", mcConfigProfile), nil
+	}
+	if st, e := os.Stat(filepath.Join(xdgDir, globalMCConfigFile)); e == nil && st.Mode().IsRegular() {
+		return xdgDir, nil
+	}
 	homeDir, e := homedir.Dir()
 	if e != nil {
 		return "", probe.NewError(e)
@@ -185,6 +233,9 @@ func getAliasConfig(alias string) (*aliasConfigV10, *probe.Error) {
 	// if host is exact return quickly.
 	if _, ok := mcCfg.Aliases[alias]; ok {
 		hostCfg := mcCfg.Aliases[alias]
+		// A wrong password or a missing keychain entry must not be
+		// mistaken for an unknown alias, i.e a local path.
+		fatalIf(loadAliasSecret(alias, &hostCfg), "Unable to load the secret key of alias `"+alias+"`.")
 		return &hostCfg, nil
 	}
 
@@ -332,6 +383,14 @@ func expandAlias(aliasedURL string) (alias string, urlStr string, aliasCfg *alia
 		return alias, urlJoinPath(aliasCfg.URL, path), aliasCfg, nil
 	}
 
+	// Resolve the URL relative to the current alias, if any.
+	if current, _ := getCurrentAlias(); current != "" && current != alias && !isExplicitLocalPath(aliasedURL) {
+		if alias, urlStr, aliasCfg, err = expandAlias(urlJoinPath(current, aliasedURL)); aliasCfg != nil {
+			globalCurrentAlias = current
+			return alias, urlStr, aliasCfg, err
+		}
+	}
+
 	return "", aliasedURL, nil, nil // No matching entry found. Return original URL as is.
 }
 
@@ -17,7 +17,11 @@
 
 package cmd
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 // Tests valid host URL functionality.
 func TestParseEnvURLStr(t *testing.T) {
@@ -142,3 +146,74 @@ func TestParseEnvURLStrInvalid(t *testing.T) {
 		t.Fatalf("Expected failure")
 	}
 }
+
+func TestGetMcConfigDirProfile(t *testing.T) {
+	defer func(dir, profile string) {
+		setMcConfigDir(dir)
+		setMcConfigProfile(profile)
+	}(mcCustomConfigDir, mcConfigProfile)
+
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	setMcConfigDir("")
+
+	setMcConfigProfile("acme")
+	if dir, _ := getMcConfigDir(); dir != filepath.Join(xdg, "mc-profiles", "acme") {
+		t.Errorf("unexpected profile config dir %s", dir)
+	}
+
+	setMcConfigProfile("")
+	if dir, _ := getMcConfigDir(); dir == filepath.Join(xdg, "mc") {
+		t.Errorf("expected the legacy config dir when the XDG dir is missing")
+	}
+	if e := os.MkdirAll(filepath.Join(xdg, "mc"), 0o700); e != nil {
+		t.Fatal(e)
+	}
+	if dir, _ := getMcConfigDir(); dir == filepath.Join(xdg, "mc") {
+		t.Errorf("expected the legacy config dir when the XDG dir has no config file")
+	}
+	if e := os.WriteFile(filepath.Join(xdg, "mc", globalMCConfigFile), []byte("{}"), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	if dir, _ := getMcConfigDir(); dir != filepath.Join(xdg, "mc") {
+		t.Errorf("expected the XDG config dir, got %s", dir)
+	}
+
+	setMcConfigDir("/custom")
+	setMcConfigProfile("acme")
+	if dir, _ := getMcConfigDir(); dir != "/custom" {
+		t.Errorf("expected --config-dir to take precedence, got %s", dir)
+	}
+}
+
+func TestMergeConfigV10(t *testing.T) {
+	base := newConfigV10()
+	base.Aliases["a"] = aliasConfigV10{URL: "https://a"}
+	base.Aliases["b"] = aliasConfigV10{URL: "https://b"}
+
+	// This process changes a and adds c.
+	ours := cloneConfigV10(base)
+	ours.Aliases["a"] = aliasConfigV10{URL: "https://a2"}
+	ours.Aliases["c"] = aliasConfigV10{URL: "https://c"}
+	ours.CurrentAlias = "c"
+
+	// Another process removed b and added d.
+	disk := cloneConfigV10(base)
+	delete(disk.Aliases, "b")
+	disk.Aliases["d"] = aliasConfigV10{URL: "https://d"}
+	disk.Groups = map[string][]string{"prod": {"a", "d"}}
+
+	merged := mergeConfigV10(base, ours, disk)
+	expected := map[string]string{"a": "https://a2", "c": "https://c", "d": "https://d"}
+	if len(merged.Aliases) != len(expected) {
+		t.Fatalf("unexpected merged aliases %v", merged.Aliases)
+	}
+	for alias, url := range expected {
+		if merged.Aliases[alias].URL != url {
+			t.Errorf("%s: expected %s, got %s", alias, url, merged.Aliases[alias].URL)
+		}
+	}
+	if len(merged.Groups["prod"]) != 2 || merged.CurrentAlias != "c" {
+		t.Errorf("unexpected merged groups %v and current alias %s", merged.Groups, merged.CurrentAlias)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+// copyManifestVersion is the version of the manifests of cp --write-manifest.
+const copyManifestVersion = "1"
+
+// copyManifestSaveInterval is the interval between the writes of a
+// manifest during a copy.
+const copyManifestSaveInterval = time.Second
+
+// copyManifest records the objects written by cp --write-manifest, it
+// is verified with 'mc verify --manifest'.
+type copyManifest struct {
+	mu    sync.Mutex
+	path  string
+	saved time.Time
+
+	Version   string               `json:"version"`
+	Created   time.Time            `json:"created"`
+	Algorithm string               `json:"algorithm"`
+	Objects   []copyManifestObject `json:"objects"`
+}
+
+// copyManifestObject is an object of a manifest, the key is its path
+// after the alias, i.e. 'bucket/object'.
+type copyManifestObject struct {
+	Key       string `json:"key"`
+	Size      int64  `json:"size"`
+	Checksum  string `json:"checksum"`
+	VersionID string `json:"versionId,omitempty"`
+}
+
+func newCopyManifest(path, algorithm string) *copyManifest {
+	return &copyManifest{path: path, Version: copyManifestVersion, Created: UTCNow(), Algorithm: algorithm}
+}
+
+// hashUpload returns the reader to upload and the hash of its content.
+// Seekable content is hashed before the upload, the reader is kept for
+// the uploads reading it at offsets. Streams are hashed as uploaded.
+func (m *copyManifest) hashUpload(reader io.Reader, size int64) (io.Reader, hash.Hash, error) {
+	h, e := checksumHash(m.Algorithm)
+	if e != nil {
+		return nil, nil, e
+	}
+	if rs, ok := reader.(io.ReadSeeker); ok && size >= 0 {
+		if offset, e := rs.Seek(0, io.SeekCurrent); e == nil {
+			if _, e = io.CopyN(h, rs, size); e != nil {
+				return nil, nil, e
+			}
+			if _, e = rs.Seek(offset, io.SeekStart); e != nil {
+				return nil, nil, e
+			}
+			return reader, h, nil
+		}
+	}
+	return io.TeeReader(reader, h), h, nil
+}
+
+// record adds a written object to the manifest. The checksum is the one
+// of the uploaded stream and the version the one created by the upload,
+// the objects copied by the server are read back instead. The manifest is
+// saved regularly to keep the objects written before an interruption.
+func (m *copyManifest) record(ctx context.Context, urls URLs, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+	targetURL := urls.TargetContent.URL
+	object := copyManifestObject{
+		Key:       strings.TrimPrefix(targetURL.Path, string(targetURL.Separator)),
+		Size:      urls.TargetContent.Size,
+		Checksum:  urls.manifestChecksum,
+		VersionID: urls.TargetContent.VersionID,
+	}
+	if object.Checksum == "" {
+		sum, _, st, err := objectChecksum(ctx, urls.TargetAlias, &ClientContent{URL: targetURL}, m.Algorithm, encKeyDB)
+		if err != nil {
+			return err.Trace(targetURL.String())
+		}
+		object.Checksum, object.Size, object.VersionID = sum, st.Size, st.VersionID
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Objects = append(m.Objects, object)
+	if time.Since(m.saved) < copyManifestSaveInterval {
+		return nil
+	}
+	return m.saveLocked()
+}
+
+// save writes the manifest with the objects sorted by key.
+func (m *copyManifest) save() *probe.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveLocked()
+}
+
+// saveLocked writes the manifest, the file is replaced atomically to
+// survive a crash.
+func (m *copyManifest) saveLocked() *probe.Error {
+	sort.Slice(m.Objects, func(i, j int) bool {
+		return m.Objects[i].Key < m.Objects[j].Key
+	})
+	data, e := json.MarshalIndent(m, "", " ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	tmp, e := os.CreateTemp(filepath.Dir(m.path), "."+filepath.Base(m.path)+".*")
+	if e != nil {
+		return probe.NewError(e).Trace(m.path)
+	}
+	if _, e = tmp.Write(append(data, '\n')); e == nil {
+		e = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if e == nil {
+		e = os.Chmod(tmp.Name(), 0o644)
+	}
+	if e == nil {
+		e = os.Rename(tmp.Name(), m.path)
+	}
+	if e != nil {
+		os.Remove(tmp.Name())
+		return probe.NewError(e).Trace(m.path)
+	}
+	m.saved = time.Now()
+	return nil
+}
+
+// isCopyManifest tells the manifests of cp --write-manifest from the
+// ones of sha256sum.
+func isCopyManifest(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// parseCopyManifest returns the entries of a manifest of cp --write-manifest.
+func parseCopyManifest(data []byte) ([]checksumManifestEntry, error) {
+	var m copyManifest
+	if e := json.Unmarshal(data, &m); e != nil {
+		return nil, e
+	}
+	if m.Version != copyManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version '%s'", m.Version)
+	}
+	if _, e := checksumHash(m.Algorithm); e != nil {
+		return nil, e
+	}
+	entries := make([]checksumManifestEntry, 0, len(m.Objects))
+	for _, object := range m.Objects {
+		size := object.Size
+		entries = append(entries, checksumManifestEntry{
+			Key:       object.Key,
+			Algorithm: m.Algorithm,
+			Checksum:  strings.ToLower(object.Checksum),
+			Size:      &size,
+			VersionID: object.VersionID,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopyManifestHashUpload(t *testing.T) {
+	const sha256Hello = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	m := newCopyManifest("", "sha256")
+
+	// Seekable content is hashed before the upload and rewound.
+	seekable := bytes.NewReader([]byte("hello"))
+	body, h, e := m.hashUpload(seekable, 5)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if body != io.Reader(seekable) {
+		t.Error("expected the seekable reader to be uploaded as is")
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != sha256Hello {
+		t.Errorf("unexpected checksum %s", sum)
+	}
+	if data, _ := io.ReadAll(body); string(data) != "hello" {
+		t.Errorf("expected the reader to be rewound, got %q", data)
+	}
+
+	// Streams are hashed as they are uploaded.
+	body, h, e = m.hashUpload(io.MultiReader(strings.NewReader("hello")), -1)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if data, _ := io.ReadAll(body); string(data) != "hello" {
+		t.Errorf("unexpected content %q", data)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != sha256Hello {
+		t.Errorf("unexpected checksum %s", sum)
+	}
+}
+
+func TestCopyManifestRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	m := newCopyManifest(path, "sha256")
+
+	upload := func(key, checksum, versionID string) URLs {
+		return URLs{
+			TargetAlias: "myminio",
+			TargetContent: &ClientContent{
+				URL:       *newClientURL("http://localhost:9000/" + key),
+				Size:      5,
+				VersionID: versionID,
+			},
+			manifestChecksum: checksum,
+		}
+	}
+	if err := m.record(context.Background(), upload("bucket/b", "bb", "v2"), nil); err != nil {
+		t.Fatal(err)
+	}
+	// The first object is saved at once, the next ones once per interval.
+	data, e := os.ReadFile(path)
+	if e != nil {
+		t.Fatal(e)
+	}
+	entries, e := parseCopyManifest(data)
+	if e != nil || len(entries) != 1 || entries[0].Key != "bucket/b" || entries[0].VersionID != "v2" {
+		t.Fatalf("unexpected manifest %v, %v", entries, e)
+	}
+
+	if err := m.record(context.Background(), upload("bucket/a", "aa", "v1"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ = os.ReadFile(path); bytes.Contains(data, []byte("bucket/a")) {
+		t.Error("expected the manifest to be saved once per interval")
+	}
+	m.saved = time.Now().Add(-copyManifestSaveInterval)
+	if err := m.record(context.Background(), upload("bucket/c", "cc", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if entries, e = parseCopyManifest(data); e != nil || len(entries) != 3 {
+		t.Fatalf("unexpected manifest %v, %v", entries, e)
+	}
+	for i, expected := range []checksumManifestEntry{
+		{Key: "bucket/a", Checksum: "aa", VersionID: "v1"},
+		{Key: "bucket/b", Checksum: "bb", VersionID: "v2"},
+		{Key: "bucket/c", Checksum: "cc"},
+	} {
+		if entries[i].Key != expected.Key || entries[i].Checksum != expected.Checksum ||
+			entries[i].VersionID != expected.VersionID || *entries[i].Size != 5 {
+			t.Errorf("Test %d: expected %v, got %v", i+1, expected, entries[i])
+		}
+	}
+}
+
+func TestPutVersionID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+		w.Header().Set("x-amz-version-id", "v1")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := S3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versionID string
+	if _, err = s3c.Put(context.Background(), strings.NewReader("hello"), 5, nil, PutOptions{
+		metadata:  map[string]string{},
+		versionID: &versionID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if versionID != "v1" {
+		t.Errorf("expected the version of the upload, got %q", versionID)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// corsMaxRules is the maximum number of rules of a CORS configuration.
+const corsMaxRules = 100
+
+// corsMethods are the methods a CORS rule can allow.
+var corsMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+
+// corsConfig is the CORS configuration of a bucket.
+type corsConfig struct {
+	XMLName xml.Name   `xml:"CORSConfiguration" json:"-"`
+	Rules   []corsRule `xml:"CORSRule" json:"rules"`
+}
+
+// corsRule is a rule of a CORS configuration.
+type corsRule struct {
+	ID             string   `xml:"ID,omitempty" json:"id,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin" json:"allowedOrigins"`
+	AllowedMethods []string `xml:"AllowedMethod" json:"allowedMethods"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty" json:"allowedHeaders,omitempty"`
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty" json:"exposeHeaders,omitempty"`
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty" json:"maxAgeSeconds,omitempty"`
+}
+
+// isHeaderToken reports whether s is a valid HTTP header name, wildcards
+// are checked by the callers.
+func isHeaderToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r > 0x7e || r <= 0x20 || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateCorsOrigin checks an allowed origin, '*' or scheme://host[:port]
+// with at most one '*' wildcard.
+func validateCorsOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	if strings.Count(origin, "*") > 1 {
+		return fmt.Errorf("origin %q has more than one wildcard", origin)
+	}
+	u, e := url.Parse(strings.Replace(origin, "*", "wildcard", 1))
+	if e != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("origin %q must be '*' or of the form scheme://host[:port]", origin)
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("origin %q must not have a path, a query or credentials", origin)
+	}
+	return nil
+}
+
+// Validate checks the rule against the CORS specification of S3.
+func (r corsRule) Validate() error {
+	if len(r.ID) > 255 {
+		return fmt.Errorf("rule ID %q is longer than 255 characters", r.ID)
+	}
+	if len(r.AllowedOrigins) == 0 {
+		return fmt.Errorf("rule %q has no allowed origin", r.ID)
+	}
+	for _, origin := range r.AllowedOrigins {
+		if e := validateCorsOrigin(origin); e != nil {
+			return e
+		}
+	}
+	if len(r.AllowedMethods) == 0 {
+		return fmt.Errorf("rule %q has no allowed method", r.ID)
+	}
+	for _, method := range r.AllowedMethods {
+		valid := false
+		for _, m := range corsMethods {
+			valid = valid || method == m
+		}
+		if !valid {
+			return fmt.Errorf("method %q must be one of %s", method, strings.Join(corsMethods, ", "))
+		}
+	}
+	for _, header := range r.AllowedHeaders {
+		if strings.Count(header, "*") > 1 || !isHeaderToken(strings.Replace(header, "*", "x", 1)) {
+			return fmt.Errorf("allowed header %q must be a header name with at most one wildcard", header)
+		}
+	}
+	for _, header := range r.ExposeHeaders {
+		if strings.Contains(header, "*") || !isHeaderToken(header) {
+			return fmt.Errorf("expose header %q must be a header name without wildcard", header)
+		}
+	}
+	if r.MaxAgeSeconds < 0 {
+		return fmt.Errorf("rule %q has a negative max age", r.ID)
+	}
+	return nil
+}
+
+// Validate checks all the rules of the configuration.
+func (c corsConfig) Validate() error {
+	if len(c.Rules) == 0 {
+		return fmt.Errorf("CORS configuration has no rule")
+	}
+	if len(c.Rules) > corsMaxRules {
+		return fmt.Errorf("CORS configuration has more than %d rules", corsMaxRules)
+	}
+	ids := make(map[string]bool, len(c.Rules))
+	for _, rule := range c.Rules {
+		if e := rule.Validate(); e != nil {
+			return e
+		}
+		if rule.ID != "" && ids[rule.ID] {
+			return fmt.Errorf("rule ID %q is not unique", rule.ID)
+		}
+		ids[rule.ID] = true
+	}
+	return nil
+}
+
+// corsWildcardMatch matches s against a pattern with at most one '*',
+// case insensitively.
+func corsWildcardMatch(pattern, s string) bool {
+	pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+	prefix, suffix, found := strings.Cut(pattern, "*")
+	if !found {
+		return pattern == s
+	}
+	return len(s) >= len(prefix)+len(suffix) && strings.HasPrefix(s, prefix) && strings.HasSuffix(s, suffix)
+}
+
+// corsTestResult is the outcome of a simulated browser request.
+type corsTestResult struct {
+	Allowed   bool
+	RuleIndex int
+	Rule      corsRule
+	// Response headers of the request, or of its preflight.
+	Headers map[string]string
+}
+
+// Test simulates the evaluation of a cross-origin request by S3, the first
+// rule matching the origin, the method and all the request headers applies.
+func (c corsConfig) Test(origin, method string, headers []string) corsTestResult {
+	for i, rule := range c.Rules {
+		if !corsMatchAny(rule.AllowedOrigins, origin) || !corsMatchAny(rule.AllowedMethods, method) {
+			continue
+		}
+		allHeaders := true
+		for _, header := range headers {
+			allHeaders = allHeaders && corsMatchAny(rule.AllowedHeaders, header)
+		}
+		if !allHeaders {
+			continue
+		}
+
+		allowOrigin := origin
+		for _, o := range rule.AllowedOrigins {
+			if o == "*" {
+				allowOrigin = "*"
+			}
+		}
+		result := corsTestResult{
+			Allowed:   true,
+			RuleIndex: i,
+			Rule:      rule,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  allowOrigin,
+				"Access-Control-Allow-Methods": strings.Join(rule.AllowedMethods, ", "),
+			},
+		}
+		if len(headers) > 0 {
+			result.Headers["Access-Control-Allow-Headers"] = strings.Join(headers, ", ")
+		}
+		if len(rule.ExposeHeaders) > 0 {
+			result.Headers["Access-Control-Expose-Headers"] = strings.Join(rule.ExposeHeaders, ", ")
+		}
+		if rule.MaxAgeSeconds > 0 {
+			result.Headers["Access-Control-Max-Age"] = fmt.Sprint(rule.MaxAgeSeconds)
+		}
+		if allowOrigin != "*" {
+			result.Headers["Access-Control-Allow-Credentials"] = "true"
+			result.Headers["Vary"] = "Origin, Access-Control-Request-Headers, Access-Control-Request-Method"
+		}
+		return result
+	}
+	return corsTestResult{RuleIndex: -1}
+}
+
+func corsMatchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if corsWildcardMatch(pattern, s) {
+			return true
+		}
+	}
+	return false
+}
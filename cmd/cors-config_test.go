@@ -0,0 +1,69 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import "testing"
+
+func TestCorsRuleValidate(t *testing.T) {
+	testCases := []struct {
+		rule  corsRule
+		valid bool
+	}{
+		{corsRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}, true},
+		{corsRule{AllowedOrigins: []string{"https://*.example.com:8443"}, AllowedMethods: []string{"PUT", "POST"}, AllowedHeaders: []string{"x-amz-*"}, ExposeHeaders: []string{"ETag"}}, true},
+		{corsRule{AllowedMethods: []string{"GET"}}, false},
+		{corsRule{AllowedOrigins: []string{"example.com"}, AllowedMethods: []string{"GET"}}, false},
+		{corsRule{AllowedOrigins: []string{"https://*.*.example.com"}, AllowedMethods: []string{"GET"}}, false},
+		{corsRule{AllowedOrigins: []string{"https://example.com/app"}, AllowedMethods: []string{"GET"}}, false},
+		{corsRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"PATCH"}}, false},
+		{corsRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{"x-*-*"}}, false},
+		{corsRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, ExposeHeaders: []string{"x-amz-*"}}, false},
+		{corsRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowedHeaders: []string{"bad header"}}, false},
+	}
+	for i, testCase := range testCases {
+		if e := testCase.rule.Validate(); (e == nil) != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %v", i+1, testCase.valid, e)
+		}
+	}
+}
+
+func TestCorsConfigTest(t *testing.T) {
+	config := corsConfig{Rules: []corsRule{
+		{ID: "read", AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET", "HEAD"}},
+		{ID: "upload", AllowedOrigins: []string{"https://*.example.com"}, AllowedMethods: []string{"PUT"}, AllowedHeaders: []string{"x-amz-meta-*", "content-type"}, MaxAgeSeconds: 600},
+	}}
+	testCases := []struct {
+		origin, method string
+		headers        []string
+		rule           int
+	}{
+		{"https://anything.org", "GET", nil, 0},
+		{"https://app.example.com", "PUT", []string{"Content-Type", "X-Amz-Meta-Owner"}, 1},
+		{"https://app.example.com", "PUT", []string{"Authorization"}, -1},
+		{"https://example.com", "PUT", nil, -1},
+		{"https://app.example.com", "DELETE", nil, -1},
+	}
+	for i, testCase := range testCases {
+		result := config.Test(testCase.origin, testCase.method, testCase.headers)
+		if result.RuleIndex != testCase.rule || result.Allowed != (testCase.rule >= 0) {
+			t.Errorf("Test %d: expected rule %d, got %d", i+1, testCase.rule, result.RuleIndex)
+		}
+	}
+	if result := config.Test("https://app.example.com", "PUT", nil); result.Headers["Access-Control-Max-Age"] != "600" || result.Headers["Access-Control-Allow-Origin"] != "https://app.example.com" {
+		t.Errorf("unexpected response headers %v", result.Headers)
+	}
+}
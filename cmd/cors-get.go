@@ -0,0 +1,114 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var corsGetCmd = cli.Command{
+	Name:         "get",
+	Usage:        "show the CORS configuration of a bucket",
+	Action:       mainCorsGet,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the CORS rules of bucket "mybucket".
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+`,
+}
+
+type corsGetMessage struct {
+	Op     string      `json:"op"`
+	Status string      `json:"status"`
+	URL    string      `json:"url"`
+	Config *corsConfig `json:"config,omitempty"`
+}
+
+func (c corsGetMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (c corsGetMessage) String() string {
+	if c.Config == nil {
+		return console.Colorize("corsMessage", fmt.Sprintf("No CORS configuration is set for %s", c.URL))
+	}
+	var b strings.Builder
+	for i, rule := range c.Config.Rules {
+		title := fmt.Sprintf("Rule %d", i+1)
+		if rule.ID != "" {
+			title += fmt.Sprintf(" (%s)", rule.ID)
+		}
+		fmt.Fprintln(&b, console.Colorize("corsRule", title+":"))
+		fmt.Fprintf(&b, "  %-15s: %s\n", "Origins", strings.Join(rule.AllowedOrigins, ", "))
+		fmt.Fprintf(&b, "  %-15s: %s\n", "Methods", strings.Join(rule.AllowedMethods, ", "))
+		if len(rule.AllowedHeaders) > 0 {
+			fmt.Fprintf(&b, "  %-15s: %s\n", "Headers", strings.Join(rule.AllowedHeaders, ", "))
+		}
+		if len(rule.ExposeHeaders) > 0 {
+			fmt.Fprintf(&b, "  %-15s: %s\n", "Expose Headers", strings.Join(rule.ExposeHeaders, ", "))
+		}
+		if rule.MaxAgeSeconds > 0 {
+			fmt.Fprintf(&b, "  %-15s: %ds\n", "Max Age", rule.MaxAgeSeconds)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func mainCorsGet(cliCtx *cli.Context) error {
+	ctx, cancelCorsGet := context.WithCancel(globalContext)
+	defer cancelCorsGet()
+
+	console.SetColor("corsMessage", color.New(color.FgGreen))
+	console.SetColor("corsRule", color.New(color.Bold))
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	aliasedURL := cliCtx.Args().Get(0)
+
+	config, err := newCorsClient(aliasedURL).GetCors(ctx)
+	fatalIf(err.Trace(aliasedURL), "Unable to get the CORS configuration.")
+
+	printMsg(corsGetMessage{
+		Op:     cliCtx.Command.Name,
+		URL:    aliasedURL,
+		Config: config,
+	})
+	return nil
+}
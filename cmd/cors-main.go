@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var corsSubcommands = []cli.Command{
+	corsSetCmd,
+	corsGetCmd,
+	corsRemoveCmd,
+	corsAddRuleCmd,
+	corsRemoveRuleCmd,
+	corsTestCmd,
+}
+
+var corsCmd = cli.Command{
+	Name:            "cors",
+	Usage:           "manage bucket CORS configuration",
+	HideHelpCommand: true,
+	Action:          mainCors,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     corsSubcommands,
+}
+
+// mainCors is the handle for "mc cors" command.
+func mainCors(ctx *cli.Context) error {
+	commandNotFound(ctx, corsSubcommands)
+	return nil
+	// Sub-commands like "get", "set", "test" have their own main.
+}
+
+// newCorsClient returns the S3 client of a bucket, CORS is an S3 only feature.
+func newCorsClient(aliasedURL string) *S3Client {
+	client, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize connection.")
+
+	s3Client, ok := client.(*S3Client)
+	if !ok {
+		fatalIf(errDummy().Trace(aliasedURL), "The provided url doesn't point to a S3 server.")
+	}
+	return s3Client
+}
+
+// corsMessage reports a change of the CORS configuration of a bucket.
+type corsMessage struct {
+	Op     string `json:"op"`
+	Status string `json:"status"`
+	URL    string `json:"url"`
+	Rules  int    `json:"rules"`
+}
+
+func (c corsMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (c corsMessage) String() string {
+	var msg string
+	switch c.Op {
+	case "set":
+		msg = fmt.Sprintf("CORS configuration of %d rule(s) has been set successfully for %s", c.Rules, c.URL)
+	case "remove":
+		msg = fmt.Sprintf("CORS configuration has been removed successfully for %s", c.URL)
+	case "add-rule":
+		msg = fmt.Sprintf("CORS rule has been added successfully for %s, %d rule(s) in total", c.URL, c.Rules)
+	case "remove-rule":
+		msg = fmt.Sprintf("CORS rule has been removed successfully for %s, %d rule(s) left", c.URL, c.Rules)
+	}
+	return console.Colorize("corsMessage", msg)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var corsRemoveCmd = cli.Command{
+	Name:         "remove",
+	Usage:        "remove the CORS configuration of a bucket",
+	Action:       mainCorsRemove,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove all the CORS rules of bucket "mybucket".
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+`,
+}
+
+func mainCorsRemove(cliCtx *cli.Context) error {
+	ctx, cancelCorsRemove := context.WithCancel(globalContext)
+	defer cancelCorsRemove()
+
+	console.SetColor("corsMessage", color.New(color.FgGreen))
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	aliasedURL := cliCtx.Args().Get(0)
+
+	fatalIf(newCorsClient(aliasedURL).DeleteCors(ctx).Trace(aliasedURL), "Unable to remove the CORS configuration.")
+
+	printMsg(corsMessage{
+		Op:  cliCtx.Command.Name,
+		URL: aliasedURL,
+	})
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var corsAddRuleFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "id",
+		Usage: "identifier of the rule",
+	},
+	cli.StringSliceFlag{
+		Name:  "origin",
+		Usage: "origin allowed by the rule, '*' or scheme://host[:port] with at most one '*'",
+	},
+	cli.StringSliceFlag{
+		Name:  "method",
+		Usage: "method allowed by the rule, one of GET, PUT, POST, DELETE or HEAD",
+	},
+	cli.StringSliceFlag{
+		Name:  "header",
+		Usage: "request header allowed by the rule, with at most one '*'",
+	},
+	cli.StringSliceFlag{
+		Name:  "expose-header",
+		Usage: "response header exposed to the browser scripts",
+	},
+	cli.IntFlag{
+		Name:  "max-age",
+		Usage: "seconds the browsers may cache the preflight response",
+	},
+}
+
+var corsAddRuleCmd = cli.Command{
+	Name:         "add-rule",
+	Usage:        "add a rule to the CORS configuration of a bucket",
+	Action:       mainCorsAddRule,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(corsAddRuleFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} --origin ORIGIN --method METHOD [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Allow a web application to download the objects of bucket "mybucket".
+     {{.Prompt}} {{.HelpName}} --id webapp --origin https://app.example.com --method GET --method HEAD myminio/mybucket
+
+  2. Allow the subdomains of example.com to upload with any header, caching the preflight for an hour.
+     {{.Prompt}} {{.HelpName}} --origin 'https://*.example.com' --method PUT --method POST --header '*' \
+           --expose-header ETag --max-age 3600 myminio/mybucket
+`,
+}
+
+var corsRemoveRuleFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "id",
+		Usage: "identifier of the rule to remove",
+	},
+	cli.IntFlag{
+		Name:  "index",
+		Usage: "number of the rule to remove, as displayed by 'mc cors get'",
+	},
+}
+
+var corsRemoveRuleCmd = cli.Command{
+	Name:         "remove-rule",
+	Usage:        "remove a rule from the CORS configuration of a bucket",
+	Action:       mainCorsRemoveRule,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(corsRemoveRuleFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} --id ID | --index N TARGET
+
+  Removing the last rule removes the CORS configuration.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove the rule "webapp" of bucket "mybucket".
+     {{.Prompt}} {{.HelpName}} --id webapp myminio/mybucket
+
+  2. Remove the second rule of bucket "mybucket".
+     {{.Prompt}} {{.HelpName}} --index 2 myminio/mybucket
+`,
+}
+
+func mainCorsAddRule(cliCtx *cli.Context) error {
+	ctx, cancelCorsAddRule := context.WithCancel(globalContext)
+	defer cancelCorsAddRule()
+
+	console.SetColor("corsMessage", color.New(color.FgGreen))
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	aliasedURL := cliCtx.Args().Get(0)
+
+	methods := cliCtx.StringSlice("method")
+	for i := range methods {
+		methods[i] = strings.ToUpper(methods[i])
+	}
+	rule := corsRule{
+		ID:             cliCtx.String("id"),
+		AllowedOrigins: cliCtx.StringSlice("origin"),
+		AllowedMethods: methods,
+		AllowedHeaders: cliCtx.StringSlice("header"),
+		ExposeHeaders:  cliCtx.StringSlice("expose-header"),
+		MaxAgeSeconds:  cliCtx.Int("max-age"),
+	}
+	fatalIf(probe.NewError(rule.Validate()), "Invalid CORS rule.")
+
+	client := newCorsClient(aliasedURL)
+	config, err := client.GetCors(ctx)
+	fatalIf(err.Trace(aliasedURL), "Unable to get the CORS configuration.")
+	if config == nil {
+		config = &corsConfig{}
+	}
+	config.Rules = append(config.Rules, rule)
+	fatalIf(probe.NewError(config.Validate()), "Invalid CORS configuration.")
+	fatalIf(client.SetCors(ctx, config).Trace(aliasedURL), "Unable to set the CORS configuration.")
+
+	printMsg(corsMessage{
+		Op:    cliCtx.Command.Name,
+		URL:   aliasedURL,
+		Rules: len(config.Rules),
+	})
+	return nil
+}
+
+func mainCorsRemoveRule(cliCtx *cli.Context) error {
+	ctx, cancelCorsRemoveRule := context.WithCancel(globalContext)
+	defer cancelCorsRemoveRule()
+
+	console.SetColor("corsMessage", color.New(color.FgGreen))
+
+	if len(cliCtx.Args()) != 1 || cliCtx.IsSet("id") == cliCtx.IsSet("index") {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	aliasedURL := cliCtx.Args().Get(0)
+
+	client := newCorsClient(aliasedURL)
+	config, err := client.GetCors(ctx)
+	fatalIf(err.Trace(aliasedURL), "Unable to get the CORS configuration.")
+	if config == nil {
+		fatalIf(errDummy().Trace(aliasedURL), "No CORS configuration is set.")
+	}
+
+	index := cliCtx.Int("index") - 1
+	if id := cliCtx.String("id"); id != "" {
+		index = -1
+		for i, rule := range config.Rules {
+			if rule.ID == id {
+				index = i
+			}
+		}
+	}
+	if index < 0 || index >= len(config.Rules) {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), fmt.Sprintf("No such CORS rule, the configuration has %d rule(s).", len(config.Rules)))
+	}
+	config.Rules = append(config.Rules[:index], config.Rules[index+1:]...)
+
+	if len(config.Rules) == 0 {
+		err = client.DeleteCors(ctx)
+	} else {
+		err = client.SetCors(ctx, config)
+	}
+	fatalIf(err.Trace(aliasedURL), "Unable to set the CORS configuration.")
+
+	printMsg(corsMessage{
+		Op:    cliCtx.Command.Name,
+		URL:   aliasedURL,
+		Rules: len(config.Rules),
+	})
+	return nil
+}
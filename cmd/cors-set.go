@@ -0,0 +1,112 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var corsSetCmd = cli.Command{
+	Name:         "set",
+	Usage:        "set the CORS configuration of a bucket",
+	Action:       mainCorsSet,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET FILE
+
+  FILE is an S3 CORSConfiguration XML document, or the JSON output of
+  'mc cors get --json'. Use '-' to read it from STDIN.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Set the CORS configuration of bucket "mybucket" from an XML document.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket cors.xml
+
+  2. Copy the CORS configuration of a bucket to another one.
+     {{.Prompt}} mc cors get --json myminio/mybucket | {{.HelpName}} myminio/otherbucket -
+`,
+}
+
+// parseCorsConfig parses a CORS configuration in XML or in JSON.
+func parseCorsConfig(data []byte) (*corsConfig, error) {
+	config := &corsConfig{}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		// Accept the output of 'mc cors get --json' as well as bare rules.
+		var msg corsGetMessage
+		if e := json.Unmarshal(data, &msg); e != nil {
+			return nil, e
+		}
+		if msg.Config != nil {
+			return msg.Config, nil
+		}
+		return config, json.Unmarshal(data, config)
+	}
+	return config, xml.Unmarshal(data, config)
+}
+
+func mainCorsSet(cliCtx *cli.Context) error {
+	ctx, cancelCorsSet := context.WithCancel(globalContext)
+	defer cancelCorsSet()
+
+	console.SetColor("corsMessage", color.New(color.FgGreen))
+
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	aliasedURL, file := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+
+	var data []byte
+	var e error
+	if file == "-" {
+		data, e = io.ReadAll(os.Stdin)
+	} else {
+		data, e = os.ReadFile(file)
+	}
+	fatalIf(probe.NewError(e).Trace(file), "Unable to read the CORS configuration.")
+
+	config, e := parseCorsConfig(data)
+	fatalIf(probe.NewError(e).Trace(file), "Unable to parse the CORS configuration.")
+	fatalIf(probe.NewError(config.Validate()).Trace(file), "Invalid CORS configuration.")
+
+	client := newCorsClient(aliasedURL)
+	fatalIf(client.SetCors(ctx, config).Trace(aliasedURL), "Unable to set the CORS configuration.")
+
+	printMsg(corsMessage{
+		Op:    cliCtx.Command.Name,
+		URL:   aliasedURL,
+		Rules: len(config.Rules),
+	})
+	return nil
+}
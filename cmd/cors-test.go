@@ -0,0 +1,147 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var corsTestFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name:  "header",
+		Usage: "header the request sends, making the browser send a preflight request",
+	},
+}
+
+var corsTestCmd = cli.Command{
+	Name:         "test",
+	Usage:        "simulate whether a browser request is allowed by the CORS configuration of a bucket",
+	Action:       mainCorsTest,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(corsTestFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET ORIGIN METHOD
+
+  The request is evaluated locally against the rules, nothing is sent to the
+  bucket besides reading its configuration. The exit status is 1 when the
+  request is denied.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Check whether https://app.example.com may download from bucket "mybucket".
+     {{.Prompt}} {{.HelpName}} myminio/mybucket https://app.example.com GET
+
+  2. Check whether an upload with a custom header is allowed.
+     {{.Prompt}} {{.HelpName}} --header x-amz-meta-owner myminio/mybucket https://app.example.com PUT
+`,
+}
+
+type corsTestMessage struct {
+	Op              string            `json:"op"`
+	Status          string            `json:"status"`
+	URL             string            `json:"url"`
+	Origin          string            `json:"origin"`
+	Method          string            `json:"method"`
+	Headers         []string          `json:"headers,omitempty"`
+	Allowed         bool              `json:"allowed"`
+	Rule            int               `json:"rule,omitempty"`
+	RuleID          string            `json:"ruleId,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+}
+
+func (c corsTestMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (c corsTestMessage) String() string {
+	request := fmt.Sprintf("%s from %s", c.Method, c.Origin)
+	if !c.Allowed {
+		return console.Colorize("corsDenied", "Denied: ") + request + ", no CORS rule matches"
+	}
+	rule := fmt.Sprintf("rule %d", c.Rule)
+	if c.RuleID != "" {
+		rule += fmt.Sprintf(" (%s)", c.RuleID)
+	}
+	msg := console.Colorize("corsAllowed", "Allowed: ") + request + " by " + rule
+	keys := make([]string, 0, len(c.ResponseHeaders))
+	for k := range c.ResponseHeaders {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		msg += fmt.Sprintf("\n  %s: %s", k, c.ResponseHeaders[k])
+	}
+	return msg
+}
+
+func mainCorsTest(cliCtx *cli.Context) error {
+	ctx, cancelCorsTest := context.WithCancel(globalContext)
+	defer cancelCorsTest()
+
+	console.SetColor("corsAllowed", color.New(color.FgGreen, color.Bold))
+	console.SetColor("corsDenied", color.New(color.FgRed, color.Bold))
+
+	if len(cliCtx.Args()) != 3 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	aliasedURL, origin, method := cliCtx.Args().Get(0), cliCtx.Args().Get(1), strings.ToUpper(cliCtx.Args().Get(2))
+	headers := cliCtx.StringSlice("header")
+
+	config, err := newCorsClient(aliasedURL).GetCors(ctx)
+	fatalIf(err.Trace(aliasedURL), "Unable to get the CORS configuration.")
+	if config == nil {
+		config = &corsConfig{}
+	}
+
+	result := config.Test(origin, method, headers)
+	msg := corsTestMessage{
+		Op:      "test",
+		URL:     aliasedURL,
+		Origin:  origin,
+		Method:  method,
+		Headers: headers,
+		Allowed: result.Allowed,
+	}
+	if result.Allowed {
+		msg.Rule = result.RuleIndex + 1
+		msg.RuleID = result.Rule.ID
+		msg.ResponseHeaders = result.Headers
+	}
+	printMsg(msg)
+	if !result.Allowed {
+		return exitStatus(exitStatusGeneral)
+	}
+	return nil
+}
@@ -34,6 +34,7 @@ import (
 	json "github.com/minio/colorjson"
 	"github.com/kirolous/mc/pkg/probe"
 	"github.com/minio/pkg/console"
+	"github.com/minio/pkg/mimedb"
 )
 
 // cp command flags.
@@ -63,6 +64,10 @@ var (
 			Name:  "storage-class, sc",
 			Usage: "set storage class for new object(s) on target",
 		},
+		cli.StringFlag{
+			Name:  "storage-class-map",
+			Usage: "set the storage class of the objects matching patterns, e.g. '*.log=REDUCED_REDUNDANCY,*.parquet=STANDARD'",
+		},
 		cli.StringFlag{
 			Name:  "encrypt",
 			Usage: "encrypt/decrypt objects (using server-side encryption with server managed keys)",
@@ -71,6 +76,15 @@ var (
 			Name:  "attr",
 			Usage: "add custom metadata for the object",
 		},
+		cli.StringFlag{
+			Name:  "metadata-directive",
+			Value: "COPY",
+			Usage: "copy the metadata of the source and add --attr (COPY), or only set --attr (REPLACE)",
+		},
+		cli.BoolFlag{
+			Name:  "content-type-by-extension",
+			Usage: "set the Content-Type of the objects from the extension of their names",
+		},
 		cli.BoolFlag{
 			Name:  "continue, c",
 			Usage: "create or resume copy session",
@@ -107,6 +121,24 @@ var (
 			Name:  "zip",
 			Usage: "Extract from remote zip file (MinIO server source only)",
 		},
+		cli.StringFlag{
+			Name:  "source-digest",
+			Usage: "verify the source against a digest as ALGORITHM:HEX (md5, sha1, sha256, sha512)",
+		},
+		uploadChecksumFlag,
+		cli.StringFlag{
+			Name:  "write-manifest",
+			Usage: "write the key, size, checksum and version of the copied objects to a JSON manifest",
+		},
+		cli.BoolFlag{
+			Name:  "map-acl-to-policy",
+			Usage: "allow the anonymous reads granted by the ACLs of the source in the policies of the target buckets",
+		},
+		transformFlag,
+		parallelFlag,
+		maxCopyBytesFlag,
+		noLimitFlag,
+		notifyOnCompletionFlag,
 	}
 )
 
@@ -126,7 +158,7 @@ var cpCmd = cli.Command{
 	Action:       mainCopy,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(cpFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(cpFlags, codecFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -137,8 +169,10 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-  MC_ENCRYPT:      list of comma delimited prefixes
-  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENCRYPT:         list of comma delimited prefixes
+  MC_ENCRYPT_KEY:     list of comma delimited prefix=secret values
+  MC_AGE_IDENTITY:    file of the age identities decrypting the objects encrypted with --encrypt-with
+  MC_MAX_COPY_BYTES:  default of --max-copy-bytes, lifted with --no-limit
 
 EXAMPLES:
   01. Copy a list of objects from local file system to Amazon S3 cloud storage.
@@ -202,6 +236,56 @@ EXAMPLES:
   20. Set tags to the uploaded objects
       {{.Prompt}} {{.HelpName}} -r --tags "category=prod&type=backup" ./data/ play/another-bucket/
 
+  21. Stream a file from a web server to an object storage, verifying its SHA-256 digest. Interrupted
+      downloads are resumed with range requests.
+      {{.Prompt}} {{.HelpName}} --source-digest sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 https://example.com/file.iso play/mybucket/
+
+  22. Compress and encrypt objects on the client for an age recipient. Downloading them to the filesystem
+      decrypts them with the identity file set in MC_AGE_IDENTITY and decompresses them.
+      {{.Prompt}} {{.HelpName}} --recursive --compress zstd --encrypt-with age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p ./reports/ play/mybucket/
+      {{.Prompt}} {{.HelpName}} --recursive play/mybucket/ ./reports/
+
+  23. Copy a folder of small files with 128 concurrent transfers, the connection pool grows to match.
+      {{.Prompt}} {{.HelpName}} --recursive --parallel 128 ./thumbnails/ play/mybucket/thumbnails/
+
+  24. Fix the Content-Type of the objects of a prefix in place, from the extension of their names.
+      {{.Prompt}} {{.HelpName}} --recursive --content-type-by-extension play/mybucket/site/ play/mybucket/site/
+
+  25. Copy objects replacing their metadata with a Cache-Control header and a custom key.
+      {{.Prompt}} {{.HelpName}} --recursive --metadata-directive REPLACE --attr "Cache-Control=max-age=3600;owner=web" play/mybucket/assets/ play/cdn/assets/
+
+  26. Copy logs to the REDUCED_REDUNDANCY storage class and the other objects to STANDARD_IA. Patterns
+      without '/' match the names of the objects, the others their keys in the bucket.
+      {{.Prompt}} {{.HelpName}} --recursive --storage-class STANDARD_IA --storage-class-map '*.log=REDUCED_REDUNDANCY,tmp/*=STANDARD' ./data/ s3/mybucket/
+
+  27. Upload a file with a SHA-256 checksum verified by the server. Files larger than 5 GiB and streams
+      only support crc32c, which is sent for every part of the multipart upload.
+      {{.Prompt}} {{.HelpName}} --checksum sha256 backup.tar s3/mybucket/
+
+  28. Decompress gzipped logs while migrating them, and keep only the records of JSON exports with jq.
+      {{.Prompt}} {{.HelpName}} --recursive --transform gunzip old/logs/ s3/logs/
+      {{.Prompt}} {{.HelpName}} --recursive --transform "jq -c '.records[]'" old/exports/ s3/exports/
+
+  29. Upload evidence with a manifest of the keys, sizes, checksums and versions of the objects, and
+      verify later that the bucket still matches it. The objects are read back to compute their SHA-256
+      unless the server stores their checksum, e.g. with --checksum sha256.
+      {{.Prompt}} {{.HelpName}} --recursive --checksum sha256 --write-manifest manifest.json ./evidence/ s3/evidence/
+      {{.Prompt}} mc verify --manifest manifest.json s3/evidence
+
+  30. Download the objects of an access point of AWS S3, given by its ARN in place of a bucket.
+      {{.Prompt}} {{.HelpName}} --recursive s3/arn:aws:s3:us-west-2:123456789012:accesspoint/logs/2023/ ./logs/
+
+  31. Migrate a bucket from AWS S3 to MinIO, the public-read ACLs of the bucket and of the objects become
+      statements of the bucket policy. The objects are allowed by prefix when all the objects of the target
+      under the prefix are public, the grants which cannot be mapped are reported.
+      {{.Prompt}} {{.HelpName}} --recursive --map-acl-to-policy s3/website/ myminio/website/
+
+  32. Copy a prefix, aborting if more than 50 GiB would be copied.
+      {{.Prompt}} {{.HelpName}} --recursive --max-copy-bytes 50GiB s3/mybucket/exports/ myminio/exports/
+
+  33. Copy a large prefix and post to a webhook when the copy finishes or fails.
+      {{.Prompt}} {{.HelpName}} --recursive --notify-on-completion https://hooks.example.com/mc s3/mybucket/ myminio/mybucket/
+
 `,
 }
 
@@ -256,6 +340,9 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 	targetURL := cpURLs.TargetContent.URL
 	length := cpURLs.SourceContent.Size
 	sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
+	if isHTTPSource(sourceAlias, sourceURL.String()) {
+		sourcePath = sourceURL.String()
+	}
 
 	if progressReader, ok := pg.(*progressBar); ok {
 		progressReader.SetCaption(cpURLs.SourceContent.URL.String() + ":")
@@ -271,6 +358,26 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 	}
 
 	urls := uploadSourceToTargetURL(ctx, cpURLs, pg, encKeyDB, preserve, isZip)
+	if urls.Error == nil && cpURLs.Checksum != "" && targetURL.Type != fileSystem {
+		tgtSSE := getSSE(filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path)), encKeyDB[targetAlias])
+		msg, err := verifyUploadChecksum(ctx, targetAlias, targetURL.String(), cpURLs.Checksum, tgtSSE)
+		if err != nil {
+			return urls.WithError(err.Trace(targetURL.String()))
+		}
+		if _, ok := pg.(*progressBar); !ok {
+			printMsg(msg)
+		}
+	}
+	if urls.Error == nil && cpURLs.manifest != nil {
+		if err := cpURLs.manifest.record(ctx, urls, encKeyDB); err != nil {
+			return urls.WithError(err.Trace(targetURL.String()))
+		}
+	}
+	if urls.Error == nil && cpURLs.aclMapper != nil {
+		if err := cpURLs.aclMapper.record(ctx, cpURLs); err != nil {
+			return urls.WithError(err.Trace(sourceURL.String()))
+		}
+	}
 	if isMvCmd && urls.Error == nil {
 		rmManager.add(ctx, sourceAlias, sourceURL.String())
 	}
@@ -367,7 +474,7 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 				console.Eraseline()
 			}
 			session.Delete() // If we are interrupted during the URL scanning, we drop the session.
-			os.Exit(0)
+			exitProcess(0)
 		}
 	}
 
@@ -383,6 +490,27 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 
 	cpURLsCh := make(chan URLs, 10000)
 
+	// Validated by checkCopySyntax.
+	storageClassMap, _ := parseStorageClassMap(cli.String("storage-class-map"))
+
+	// The checksums of the manifest use the algorithm of --checksum, they
+	// are computed from the uploaded content.
+	var manifest *copyManifest
+	if cli.String("write-manifest") != "" {
+		algorithm := strings.ToLower(cli.String("checksum"))
+		if algorithm == "" {
+			algorithm = "sha256"
+		}
+		manifest = newCopyManifest(cli.String("write-manifest"), algorithm)
+	}
+
+	budget := mustGetOpBudget(cli)
+
+	var aclMapper *aclPolicyMapper
+	if cli.Bool("map-acl-to-policy") {
+		aclMapper = newACLPolicyMapper()
+	}
+
 	// Store a progress bar or an accounter
 	var pg ProgressReader
 
@@ -481,7 +609,11 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 	quitCh := make(chan struct{})
 	statusCh := make(chan URLs)
 
-	parallel := newParallelManager(statusCh)
+	workers := cli.Int("parallel")
+	if session != nil {
+		workers = session.Header.CommandIntFlags["parallel"]
+	}
+	parallel := newParallelManager(statusCh, workers)
 
 	go func() {
 		gracefulStop := func() {
@@ -517,6 +649,9 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 				if storageClass := cli.String("storage-class"); storageClass != "" {
 					cpURLs.TargetContent.StorageClass = storageClass
 				}
+				if storageClass := storageClassMap.lookup(cpURLs.TargetContent.URL); storageClass != "" {
+					cpURLs.TargetContent.StorageClass = storageClass
+				}
 
 				if rm := cli.String(rmFlag); rm != "" {
 					cpURLs.TargetContent.RetentionMode = rm
@@ -534,6 +669,11 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 					cpURLs.TargetContent.Metadata["X-Amz-Tagging"] = tags
 				}
 
+				// --attr overrides the detected Content-Type.
+				if cli.Bool("content-type-by-extension") {
+					cpURLs.TargetContent.Metadata["Content-Type"] = mimedb.TypeByExtension(filepath.Ext(cpURLs.TargetContent.URL.Path))
+				}
+
 				preserve := cli.Bool("preserve")
 				isZip := cli.Bool("zip")
 				if cli.String("attr") != "" {
@@ -545,6 +685,14 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 
 				cpURLs.MD5 = cli.Bool("md5") || withLock
 				cpURLs.DisableMultipart = cli.Bool("disable-multipart")
+				cpURLs.SourceDigest = cli.String("source-digest")
+				cpURLs.Checksum = strings.ToLower(cli.String("checksum"))
+				cpURLs.Compress = cli.String("compress")
+				cpURLs.Transform = cli.String("transform")
+				cpURLs.manifest = manifest
+				cpURLs.aclMapper = aclMapper
+				cpURLs.EncryptWith = cli.String("encrypt-with")
+				cpURLs.ReplaceMetadata = strings.EqualFold(cli.String("metadata-directive"), "REPLACE")
 
 				// Verify if previously copied, notify progress bar.
 				if isCopied != nil && isCopied(cpURLs.SourceContent.URL.String()) {
@@ -559,6 +707,7 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 						console.Println("Resuming copy from ", startSize, " / ", totalSize)
 						startContinue = false
 					}
+					fatalIf(budget.copy(cpURLs.SourceContent.Size).Trace(cpURLs.SourceContent.URL.String()), "Aborting the copy.")
 					parallel.queueTask(func() URLs {
 						return doCopy(ctx, cpURLs, pg, encKeyDB, isMvCmd, preserve, isZip)
 					}, cpURLs.SourceContent.Size)
@@ -591,15 +740,17 @@ loop:
 				break loop
 			}
 			if cpURLs.Error == nil {
+				recordObjectDone(cpURLs.SourceContent.URL.String(), cpURLs.SourceContent.Size)
 				if session != nil {
 					session.Header.LastCopied = cpURLs.SourceContent.URL.String()
+					session.Header.ObjectsDone++
 					session.Save()
 				}
 				cpAllFilesErr = false
 			} else {
 
 				// Set exit status for any copy error
-				retErr = exitStatus(globalErrorExitStatus)
+				retErr = exitStatus(porcelainExitStatus(errorExitStatus(cpURLs.Error)))
 
 				// Print in new line and adjust to top so that we
 				// don't print over the ongoing progress bar.
@@ -648,6 +799,29 @@ loop:
 		}
 	}
 
+	if manifest != nil {
+		manifestPath := cli.String("write-manifest")
+		fatalIf(manifest.save(), "Unable to write the manifest `"+manifestPath+"`.")
+	}
+
+	if aclMapper != nil {
+		msgs, err := aclMapper.apply(ctx)
+		for _, msg := range msgs {
+			printMsg(msg)
+		}
+		for _, msg := range aclMapper.unmapped {
+			printMsg(msg)
+		}
+		if err != nil {
+			errorIf(err, "Unable to set the policies mapped from the ACLs.")
+			retErr = exitStatus(globalErrorExitStatus)
+		}
+	}
+
+	if retErr != nil && objectsDone() > 0 {
+		// Some objects were copied before the errors.
+		retErr = exitStatus(porcelainExitStatus(exitStatusPartial))
+	}
 	return retErr
 }
 
@@ -669,8 +843,12 @@ func mainCopy(cliCtx *cli.Context) error {
 
 	// check 'copy' cli arguments.
 	checkCopySyntax(ctx, cliCtx, encKeyDB, false)
+	checkUploadChecksum(cliCtx)
+	enableCompletionNotify(cliCtx)
 	// Additional command specific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+	console.SetColor("ACLPolicy", color.New(color.FgGreen))
+	console.SetColor("ACLUnmapped", color.New(color.FgYellow))
 
 	recursive := cliCtx.Bool("recursive")
 	rewind := cliCtx.String("rewind")
@@ -723,6 +901,7 @@ func mainCopy(cliCtx *cli.Context) error {
 			session.Header.UserMetaData = userMetaMap
 			session.Header.CommandBoolFlags["md5"] = cliCtx.Bool("md5")
 			session.Header.CommandBoolFlags["disable-multipart"] = cliCtx.Bool("disable-multipart")
+			session.Header.CommandIntFlags["parallel"] = cliCtx.Int("parallel")
 
 			var e error
 			if session.Header.RootPath, e = os.Getwd(); e != nil {
@@ -732,6 +911,7 @@ func mainCopy(cliCtx *cli.Context) error {
 
 			// extract URLs.
 			session.Header.CommandArgs = cliCtx.Args()
+			session.Header.CommandLine = os.Args[1:]
 		}
 	}
 
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/minio/cli"
@@ -57,6 +58,41 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		fatalIf(errDummy().Trace(cliCtx.Args()...), "--zip and --rewind cannot be used together")
 	}
 
+	if e := checkCodecOptions(cliCtx.String("compress"), cliCtx.String("encrypt-with")); e != nil {
+		fatalIf(probe.NewError(e), "Invalid client side compression or encryption.")
+	}
+
+	if _, e := parseStorageClassMap(cliCtx.String("storage-class-map")); e != nil {
+		fatalIf(probe.NewError(e), "Invalid --storage-class-map.")
+	}
+
+	switch directive := strings.ToUpper(cliCtx.String("metadata-directive")); directive {
+	case "", "COPY":
+	case "REPLACE":
+		if cliCtx.Bool("preserve") {
+			fatalIf(errDummy().Trace(cliCtx.Args()...), "--metadata-directive REPLACE and --preserve cannot be used together.")
+		}
+	default:
+		fatalIf(errInvalidArgument().Trace(directive), "--metadata-directive must be COPY or REPLACE.")
+	}
+
+	if digest := cliCtx.String("source-digest"); digest != "" {
+		if len(srcURLs) > 1 || isRecursive {
+			fatalIf(errDummy().Trace(cliCtx.Args()...), "--source-digest can only verify a single source file.")
+		}
+		if _, _, e := parseSourceDigest(digest); e != nil {
+			fatalIf(probe.NewError(e).Trace(digest), "Invalid --source-digest.")
+		}
+	}
+
+	if cliCtx.Bool("map-acl-to-policy") {
+		clnt, err := newClient(tgtURL)
+		fatalIf(err.Trace(tgtURL), "Unable to initialize target `"+tgtURL+"`.")
+		if _, ok := clnt.(*S3Client); !ok {
+			fatalIf(errInvalidArgument().Trace(tgtURL), "--map-acl-to-policy requires a target on an object storage.")
+		}
+	}
+
 	// Verify if source(s) exists.
 	for _, srcURL := range srcURLs {
 		var err *probe.Error
@@ -127,7 +163,10 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		}
 		checkCopySyntaxTypeB(ctx, srcURLs[0], versionID, tgtURL, encKeyDB, isZip, timeRef)
 	case copyURLsTypeC: // Folder... -> Folder.
-		checkCopySyntaxTypeC(ctx, srcURLs, tgtURL, isRecursive, isZip, encKeyDB, isMvCmd, timeRef)
+		// Objects can be copied onto themselves to rewrite their metadata.
+		inPlace := !isMvCmd && (cliCtx.String("attr") != "" || cliCtx.Bool("content-type-by-extension") ||
+			strings.EqualFold(cliCtx.String("metadata-directive"), "REPLACE"))
+		checkCopySyntaxTypeC(ctx, srcURLs, tgtURL, isRecursive, isZip, encKeyDB, isMvCmd, inPlace, timeRef)
 	case copyURLsTypeD: // File1...FileN -> Folder.
 		checkCopySyntaxTypeD(ctx, tgtURL, encKeyDB, timeRef)
 	default:
@@ -168,7 +207,7 @@ func checkCopySyntaxTypeB(ctx context.Context, srcURL, versionID string, tgtURL
 }
 
 // checkCopySyntaxTypeC verifies if the source is a valid recursive dir and target is a valid folder.
-func checkCopySyntaxTypeC(ctx context.Context, srcURLs []string, tgtURL string, isRecursive, isZip bool, keys map[string][]prefixSSEPair, isMvCmd bool, timeRef time.Time) {
+func checkCopySyntaxTypeC(ctx context.Context, srcURLs []string, tgtURL string, isRecursive, isZip bool, keys map[string][]prefixSSEPair, isMvCmd, inPlace bool, timeRef time.Time) {
 	// Check source.
 	if len(srcURLs) != 1 {
 		fatalIf(errInvalidArgument().Trace(), "Invalid number of source arguments.")
@@ -196,7 +235,11 @@ func checkCopySyntaxTypeC(ctx context.Context, srcURLs []string, tgtURL string,
 			}
 
 			// Check if we are going to copy a directory into itself
-			if isURLContains(srcURL, tgtURL, string(c.GetURL().Separator)) {
+			sep := string(c.GetURL().Separator)
+			if inPlace && strings.TrimSuffix(srcURL, sep) == strings.TrimSuffix(tgtURL, sep) {
+				continue
+			}
+			if isURLContains(srcURL, tgtURL, sep) {
 				operation := "Copying"
 				if isMvCmd {
 					operation = "Moving"
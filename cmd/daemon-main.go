@@ -0,0 +1,462 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bufio"
+	"crypto/rand"
+	csubtle "crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// mcEnvDaemonToken is the token authenticating the requests to `mc daemon`.
+const mcEnvDaemonToken = "MC_DAEMON_TOKEN"
+
+// daemonMaxEvents is the number of output lines kept for each job.
+const daemonMaxEvents = 1000
+
+var daemonFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "listen",
+		Value: "127.0.0.1:9990",
+		Usage: "address the HTTP API listens on",
+	},
+	cli.StringFlag{
+		Name:   "token",
+		Usage:  "bearer token of the HTTP API, generated when not set",
+		EnvVar: mcEnvDaemonToken,
+	},
+}
+
+var daemonCmd = cli.Command{
+	Name:         "daemon",
+	Usage:        "serve an HTTP API to run and monitor transfer jobs",
+	Action:       mainDaemon,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(daemonFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+
+  Jobs run 'mc cp', 'mc mirror' or 'mc rm' with the configuration of the
+  daemon. Every request must carry 'Authorization: Bearer TOKEN'.
+
+API:
+  POST   /v1/jobs               start a job, body {"command":"mirror","args":["SOURCE","TARGET"]}
+  GET    /v1/jobs               list the jobs
+  GET    /v1/jobs/ID            show a job
+  GET    /v1/jobs/ID/events     JSON output of a job, from the line ?since=N
+  POST   /v1/jobs/ID/resume     resume an interrupted job from its session
+  DELETE /v1/jobs/ID            cancel a job, its session is kept
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_DAEMON_TOKEN:  bearer token of the HTTP API
+
+EXAMPLES:
+  1. Start the daemon on the default address with a generated token.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Start a mirror job and follow its progress.
+     {{.Prompt}} curl -H "Authorization: Bearer $TOKEN" -d '{"command":"mirror","args":["data/","myminio/backup"]}' http://127.0.0.1:9990/v1/jobs
+     {{.Prompt}} curl -H "Authorization: Bearer $TOKEN" http://127.0.0.1:9990/v1/jobs/1
+`,
+}
+
+// daemonCommands are the commands run by daemon jobs.
+var daemonCommands = map[string]bool{
+	"cp":     true,
+	"mirror": true,
+	"rm":     true,
+}
+
+// Job states.
+const (
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+	jobCanceled  = "canceled"
+)
+
+type daemonJob struct {
+	ID         string     `json:"id"`
+	Command    string     `json:"command"`
+	Args       []string   `json:"args"`
+	State      string     `json:"state"`
+	ExitStatus int        `json:"exitStatus"`
+	SessionID  string     `json:"sessionId,omitempty"`
+	Started    time.Time  `json:"started"`
+	Finished   *time.Time `json:"finished,omitempty"`
+	Objects    int64      `json:"objects"`
+	Bytes      int64      `json:"bytes"`
+	Errors     int64      `json:"errors"`
+	LastError  string     `json:"lastError,omitempty"`
+
+	childArgs []string
+	events    []string
+	dropped   int
+	canceled  bool
+	cmd       *exec.Cmd
+}
+
+type jobRequest struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+type mcDaemon struct {
+	mu     sync.Mutex
+	token  string
+	nextID int
+	jobs   map[string]*daemonJob
+}
+
+// daemonMessage container for the daemon start message
+type daemonMessage struct {
+	Status string `json:"status"`
+	Listen string `json:"listen"`
+	Token  string `json:"token,omitempty"`
+}
+
+func (m daemonMessage) String() string {
+	msg := console.Colorize("DaemonMessage", "Listening on http://"+m.Listen)
+	if m.Token != "" {
+		msg += "\n" + console.Colorize("DaemonMessage", "Token: ") + m.Token
+	}
+	return msg
+}
+
+func (m daemonMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, msg string) {
+	writeJSONResponse(w, status, map[string]string{"error": msg})
+}
+
+// snapshot returns a copy of the job safe to encode.
+func (d *mcDaemon) snapshot(job *daemonJob) daemonJob {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c := *job
+	c.events, c.cmd = nil, nil
+	if !isSessionExists(c.SessionID) {
+		c.SessionID = ""
+	}
+	return c
+}
+
+// start runs the job in a child mc process, its JSON output is parsed
+// to follow the progress.
+func (d *mcDaemon) start(job *daemonJob) *probe.Error {
+	executable, e := os.Executable()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	cmd := exec.Command(executable, job.childArgs...)
+	cmd.Env = os.Environ()
+	stdout, e := cmd.StdoutPipe()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	cmd.Stderr = cmd.Stdout
+	if e = cmd.Start(); e != nil {
+		return probe.NewError(e)
+	}
+
+	d.mu.Lock()
+	job.cmd, job.State, job.canceled = cmd, jobRunning, false
+	job.Started, job.Finished = time.Now().UTC(), nil
+	d.mu.Unlock()
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64<<10), 1<<20)
+		for scanner.Scan() {
+			d.record(job, scanner.Text())
+		}
+		e := cmd.Wait()
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		now := time.Now().UTC()
+		job.Finished = &now
+		job.ExitStatus = getExitStatus(e)
+		switch {
+		case job.canceled:
+			job.State = jobCanceled
+		case e != nil:
+			job.State = jobFailed
+		default:
+			job.State = jobSucceeded
+		}
+	}()
+	return nil
+}
+
+// record accounts for an output line of a job.
+func (d *mcDaemon) record(job *daemonJob, line string) {
+	var msg struct {
+		Status string `json:"status"`
+		Source string `json:"source"`
+		Key    string `json:"key"`
+		Size   int64  `json:"size"`
+		Error  struct {
+			Message string `json:"message"`
+			Cause   struct {
+				Message string `json:"message"`
+			} `json:"cause"`
+		} `json:"error"`
+	}
+	json.Unmarshal([]byte(line), &msg)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch msg.Status {
+	case "success":
+		if msg.Source == "" && msg.Key == "" {
+			// Summary of the transfer.
+			break
+		}
+		job.Objects++
+		job.Bytes += msg.Size
+	case "error":
+		job.Errors++
+		job.LastError = strings.TrimSpace(msg.Error.Message + " " + msg.Error.Cause.Message)
+	}
+	job.events = append(job.events, line)
+	if len(job.events) > daemonMaxEvents {
+		job.events = job.events[1:]
+		job.dropped++
+	}
+}
+
+func (d *mcDaemon) getJob(id string) (*daemonJob, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	job, ok := d.jobs[id]
+	return job, ok
+}
+
+func (d *mcDaemon) handleJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		d.mu.Lock()
+		ids := make([]string, 0, len(d.jobs))
+		for id := range d.jobs {
+			ids = append(ids, id)
+		}
+		d.mu.Unlock()
+		sort.Slice(ids, func(i, j int) bool {
+			a, _ := strconv.Atoi(ids[i])
+			b, _ := strconv.Atoi(ids[j])
+			return a < b
+		})
+		jobs := make([]daemonJob, 0, len(ids))
+		for _, id := range ids {
+			job, _ := d.getJob(id)
+			jobs = append(jobs, d.snapshot(job))
+		}
+		writeJSONResponse(w, http.StatusOK, jobs)
+	case http.MethodPost:
+		var req jobRequest
+		if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "invalid job: "+e.Error())
+			return
+		}
+		if !daemonCommands[req.Command] {
+			writeErrorResponse(w, http.StatusBadRequest, "unsupported command `"+req.Command+"`, must be one of cp, mirror or rm")
+			return
+		}
+		for _, arg := range req.Args {
+			if arg == "-C" || strings.HasPrefix(arg, "--config-dir") {
+				writeErrorResponse(w, http.StatusBadRequest, "jobs use the configuration of the daemon")
+				return
+			}
+		}
+
+		childArgs := []string{req.Command, "--json", "--config-dir", mustGetMcConfigDir()}
+		if req.Command == "cp" {
+			// Sessions of cp are only created with --continue.
+			childArgs = append(childArgs, "--continue")
+		}
+		childArgs = append(childArgs, req.Args...)
+
+		d.mu.Lock()
+		d.nextID++
+		job := &daemonJob{
+			ID:        strconv.Itoa(d.nextID),
+			Command:   req.Command,
+			Args:      req.Args,
+			SessionID: getHash(req.Command, childArgs),
+			childArgs: childArgs,
+		}
+		d.jobs[job.ID] = job
+		d.mu.Unlock()
+
+		if err := d.start(job); err != nil {
+			d.mu.Lock()
+			job.State, job.LastError = jobFailed, err.ToGoError().Error()
+			d.mu.Unlock()
+		}
+		writeJSONResponse(w, http.StatusCreated, d.snapshot(job))
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (d *mcDaemon) handleJob(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/jobs/"), "/"), "/")
+	job, ok := d.getJob(parts[0])
+	if !ok {
+		writeErrorResponse(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSONResponse(w, http.StatusOK, d.snapshot(job))
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		d.mu.Lock()
+		if job.State == jobRunning && job.cmd != nil && job.cmd.Process != nil {
+			job.canceled = true
+			// Interrupted commands keep their session to be resumed.
+			job.cmd.Process.Signal(syscall.SIGINT)
+		}
+		d.mu.Unlock()
+		writeJSONResponse(w, http.StatusAccepted, d.snapshot(job))
+	case len(parts) == 2 && parts[1] == "events" && r.Method == http.MethodGet:
+		since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+		d.mu.Lock()
+		start := since - job.dropped
+		if start < 0 {
+			start = 0
+		}
+		if start > len(job.events) {
+			start = len(job.events)
+		}
+		var events []json.RawMessage
+		for _, line := range job.events[start:] {
+			if json.Valid([]byte(line)) {
+				events = append(events, json.RawMessage(line))
+			} else {
+				buf, _ := json.Marshal(map[string]string{"status": "output", "message": line})
+				events = append(events, buf)
+			}
+		}
+		next := job.dropped + len(job.events)
+		d.mu.Unlock()
+		writeJSONResponse(w, http.StatusOK, map[string]interface{}{"events": events, "next": next})
+	case len(parts) == 2 && parts[1] == "resume" && r.Method == http.MethodPost:
+		d.mu.Lock()
+		running := job.State == jobRunning
+		d.mu.Unlock()
+		if running {
+			writeErrorResponse(w, http.StatusConflict, "job is running")
+			return
+		}
+		if err := d.start(job); err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err.ToGoError().Error())
+			return
+		}
+		writeJSONResponse(w, http.StatusAccepted, d.snapshot(job))
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// authenticate checks the bearer token of the requests.
+func (d *mcDaemon) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if scheme != "Bearer" || csubtle.ConstantTimeCompare([]byte(token), []byte(d.token)) != 1 {
+			writeErrorResponse(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// mainDaemon is the handle for "mc daemon" command.
+func mainDaemon(ctx *cli.Context) error {
+	if ctx.Args().Present() {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	console.SetColor("DaemonMessage", color.New(color.FgGreen, color.Bold))
+
+	d := &mcDaemon{token: ctx.String("token"), jobs: map[string]*daemonJob{}}
+	generated := d.token == ""
+	if generated {
+		buf := make([]byte, 24)
+		_, e := rand.Read(buf)
+		fatalIf(probe.NewError(e), "Unable to generate a token.")
+		d.token = hex.EncodeToString(buf)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/jobs", d.handleJobs)
+	mux.HandleFunc("/v1/jobs/", d.handleJob)
+
+	listen := ctx.String("listen")
+	msg := daemonMessage{Listen: listen}
+	if generated {
+		msg.Token = d.token
+	}
+	printMsg(msg)
+
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           d.authenticate(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-globalContext.Done()
+		server.Close()
+	}()
+	if e := server.ListenAndServe(); e != nil && !errors.Is(e, http.ErrServerClosed) {
+		fatalIf(probe.NewError(e).Trace(listen), "Unable to serve the HTTP API.")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDaemonAuthenticate(t *testing.T) {
+	d := &mcDaemon{token: "secret", jobs: map[string]*daemonJob{}}
+	handler := d.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	testCases := []struct {
+		auth   string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusNoContent},
+	}
+	for i, testCase := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+		if testCase.auth != "" {
+			r.Header.Set("Authorization", testCase.auth)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != testCase.status {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.status, w.Code)
+		}
+	}
+}
+
+func TestDaemonRejectedJobs(t *testing.T) {
+	d := &mcDaemon{jobs: map[string]*daemonJob{}}
+	testCases := []struct {
+		method string
+		body   string
+		status int
+	}{
+		{http.MethodPost, `{"command":"admin","args":["service","stop","myminio"]}`, http.StatusBadRequest},
+		{http.MethodPost, `{"command":"cp","args":["--config-dir=/tmp","a","myminio/bucket"]}`, http.StatusBadRequest},
+		{http.MethodPost, `{"command":"rm","args":["-C","/tmp","myminio/bucket/object"]}`, http.StatusBadRequest},
+		{http.MethodPost, `not json`, http.StatusBadRequest},
+		{http.MethodPut, `{}`, http.StatusMethodNotAllowed},
+		{http.MethodGet, ``, http.StatusOK},
+	}
+	for i, testCase := range testCases {
+		w := httptest.NewRecorder()
+		d.handleJobs(w, httptest.NewRequest(testCase.method, "/v1/jobs", strings.NewReader(testCase.body)))
+		if w.Code != testCase.status {
+			t.Errorf("Test %d: expected %d, got %d: %s", i+1, testCase.status, w.Code, w.Body.String())
+		}
+	}
+	if len(d.jobs) != 0 {
+		t.Errorf("expected no job, got %d", len(d.jobs))
+	}
+
+	w := httptest.NewRecorder()
+	d.handleJob(w, httptest.NewRequest(http.MethodGet, "/v1/jobs/42", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected %d for an unknown job, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestDaemonJobEvents(t *testing.T) {
+	d := &mcDaemon{jobs: map[string]*daemonJob{}}
+	job := &daemonJob{ID: "1", Command: "cp", State: jobSucceeded}
+	d.jobs[job.ID] = job
+
+	d.record(job, `{"status":"success","source":"a","target":"myminio/bucket/a","size":10}`)
+	d.record(job, `{"status":"success","source":"b","target":"myminio/bucket/b","size":5}`)
+	d.record(job, `{"status":"error","error":{"message":"Failed to copy `+"`c`"+`.","cause":{"message":"Access Denied."}}}`)
+	d.record(job, `{"status":"success","total":15}`)
+	d.record(job, "plain output")
+	if job.Objects != 2 || job.Bytes != 15 || job.Errors != 1 || job.LastError != "Failed to copy `c`. Access Denied." {
+		t.Fatalf("unexpected job %+v", *job)
+	}
+
+	// Old events are dropped, the indexes given to 'since' are kept.
+	for i := len(job.events); i < daemonMaxEvents+3; i++ {
+		d.record(job, fmt.Sprintf(`{"status":"success","key":"k%d"}`, i))
+	}
+	if job.dropped != 3 {
+		t.Fatalf("expected 3 events dropped, got %d", job.dropped)
+	}
+
+	testCases := []struct {
+		since, events, next int
+	}{
+		{0, daemonMaxEvents, daemonMaxEvents + 3},
+		{daemonMaxEvents + 1, 2, daemonMaxEvents + 3},
+		{daemonMaxEvents + 10, 0, daemonMaxEvents + 3},
+	}
+	for i, testCase := range testCases {
+		w := httptest.NewRecorder()
+		d.handleJob(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/jobs/1/events?since=%d", testCase.since), nil))
+		var resp struct {
+			Events []json.RawMessage `json:"events"`
+			Next   int               `json:"next"`
+		}
+		if e := json.Unmarshal(w.Body.Bytes(), &resp); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if len(resp.Events) != testCase.events || resp.Next != testCase.next {
+			t.Errorf("Test %d: expected %d events and next %d, got %d and %d",
+				i+1, testCase.events, testCase.next, len(resp.Events), resp.Next)
+		}
+	}
+
+	// Only finished jobs are resumed.
+	job.State = jobRunning
+	w := httptest.NewRecorder()
+	d.handleJob(w, httptest.NewRequest(http.MethodPost, "/v1/jobs/1/resume", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, w.Code)
+	}
+}
@@ -344,6 +344,17 @@ func differenceInternal(sourceURL string, srcCh <-chan *ClientContent, targetURL
 					firstContent:  srcCtnt,
 					secondContent: tgtCtnt,
 				}
+			} else if cmpMetadata && srcCtnt.URL.Type == objectStorage && tgtCtnt.URL.Type == objectStorage &&
+				!metadataEqual(srcCtnt.Tags, tgtCtnt.Tags) {
+
+				// Objects differing in tags only, they are propagated to the target.
+				diffCh <- diffMessage{
+					FirstURL:      srcCtnt.URL.String(),
+					SecondURL:     tgtCtnt.URL.String(),
+					Diff:          differInMetadata,
+					firstContent:  srcCtnt,
+					secondContent: tgtCtnt,
+				}
 			}
 
 			// No differ
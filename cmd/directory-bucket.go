@@ -0,0 +1,290 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+// The directory buckets of S3 Express One Zone are reached on the zonal
+// endpoint of their availability zone, e.g. the bucket
+// 'logs--usw2-az1--x-s3' on 'logs--usw2-az1--x-s3.s3express-usw2-az1.us-west-2.amazonaws.com'.
+// Their requests are signed for the s3express service with the
+// credentials of a session, created with CreateSession and valid for
+// five minutes.
+const (
+	s3ExpressServiceName       = "s3express"
+	s3ExpressStorageClass      = "EXPRESS_ONEZONE"
+	s3ExpressSessionTokenName  = "X-Amz-S3session-Token"
+	s3ExpressSessionRenewal    = time.Minute
+	sigV4Algorithm             = "AWS4-HMAC-SHA256"
+	directoryBucketZoneMarker  = "--"
+	directoryBucketUnsupported = "directory buckets of S3 Express One Zone"
+)
+
+// directoryBucketZone returns the availability zone ID of a directory
+// bucket, e.g. 'usw2-az1' for 'logs--usw2-az1--x-s3'.
+func directoryBucketZone(bucket string) string {
+	name := strings.TrimSuffix(bucket, directoryBucketSuffix)
+	if i := strings.LastIndex(name, directoryBucketZoneMarker); i >= 0 {
+		return name[i+len(directoryBucketZoneMarker):]
+	}
+	return ""
+}
+
+// availabilityZoneRegion returns the region of an availability zone ID,
+// e.g. 'us-west-2' for 'usw2-az1' or 'ap-northeast-1' for 'apne1-az4'.
+func availabilityZoneRegion(zone string) string {
+	code, _, ok := strings.Cut(zone, "-az")
+	if !ok || len(code) < 4 {
+		return ""
+	}
+	region := code[:2]
+	code = code[2:]
+	if strings.HasPrefix(code, "g") {
+		region += "-gov"
+		code = code[1:]
+	}
+	i := strings.IndexAny(code, "0123456789")
+	if i <= 0 {
+		return ""
+	}
+	directions := map[string]string{
+		"n": "north", "s": "south", "e": "east", "w": "west", "c": "central",
+		"ne": "northeast", "nw": "northwest", "se": "southeast", "sw": "southwest",
+	}
+	direction, ok := directions[code[:i]]
+	if !ok {
+		return ""
+	}
+	return region + "-" + direction + "-" + code[i:]
+}
+
+// directoryBucketHost returns the zonal endpoint of a directory bucket in region.
+func directoryBucketHost(bucket, region string) string {
+	domain := "amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		domain += ".cn"
+	}
+	return bucket + ".s3express-" + directoryBucketZone(bucket) + "." + region + "." + domain
+}
+
+// withDirectoryBucket returns the credentials given to the SDK and the
+// transport sending its requests to the zonal endpoint of the directory
+// bucket, signed with the credentials of a session.
+func withDirectoryBucket(bucket, region string, creds *credentials.Credentials, transport http.RoundTripper) (*credentials.Credentials, http.RoundTripper) {
+	return credentials.NewStaticV4("", "", ""), &directoryBucketTransport{
+		transport: transport,
+		bucket:    bucket,
+		host:      directoryBucketHost(bucket, region),
+		region:    region,
+		creds:     creds,
+	}
+}
+
+type directoryBucketTransport struct {
+	transport http.RoundTripper
+	bucket    string
+	host      string
+	region    string
+	creds     *credentials.Credentials
+
+	mu      sync.Mutex
+	session credentials.Value
+	expires time.Time
+}
+
+func (t *directoryBucketTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	session, resp, e := t.getSession(req)
+	if e != nil || resp != nil {
+		// The error response of CreateSession is reported by the SDK.
+		return resp, e
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "https"
+	req.URL.Host, req.Host = t.host, t.host
+	req.URL.Path = accessPointRequestPath(req.URL.Path, t.bucket)
+	if req.URL.RawPath != "" {
+		req.URL.RawPath = accessPointRequestPath(req.URL.RawPath, t.bucket)
+	}
+	req.Header.Del("X-Amz-Security-Token")
+	req.Header.Set(s3ExpressSessionTokenName, session.SessionToken)
+	signS3Express(req, session.AccessKeyID, session.SecretAccessKey, t.region, time.Now().UTC())
+	return t.transport.RoundTrip(req)
+}
+
+// getSession returns the credentials of the session, created again when
+// they are about to expire. A response is returned instead when the server
+// refuses to create the session.
+func (t *directoryBucketTransport) getSession(req *http.Request) (credentials.Value, *http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if time.Until(t.expires) > s3ExpressSessionRenewal {
+		return t.session, nil, nil
+	}
+
+	value, e := t.creds.Get()
+	if e != nil {
+		return credentials.Value{}, nil, e
+	}
+	sessionReq, e := http.NewRequestWithContext(req.Context(), http.MethodGet, "https://"+t.host+"/?session", nil)
+	if e != nil {
+		return credentials.Value{}, nil, e
+	}
+	if value.SessionToken != "" {
+		sessionReq.Header.Set("X-Amz-Security-Token", value.SessionToken)
+	}
+	signS3Express(sessionReq, value.AccessKeyID, value.SecretAccessKey, t.region, time.Now().UTC())
+	resp, e := t.transport.RoundTrip(sessionReq)
+	if e != nil {
+		return credentials.Value{}, nil, e
+	}
+	if resp.StatusCode != http.StatusOK {
+		return credentials.Value{}, resp, nil
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string
+			SessionToken    string
+			Expiration      time.Time
+		}
+	}
+	if e = xml.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); e != nil {
+		return credentials.Value{}, nil, e
+	}
+	t.session = credentials.Value{
+		AccessKeyID:     result.Credentials.AccessKeyID,
+		SecretAccessKey: result.Credentials.SecretAccessKey,
+		SessionToken:    result.Credentials.SessionToken,
+	}
+	t.expires = result.Credentials.Expiration
+	return t.session, nil, nil
+}
+
+// signS3Express signs req with SigV4 for the s3express service.
+func signS3Express(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.Format(sigV4ATimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if req.Header.Get("X-Amz-Content-Sha256") == "" {
+		payloadHash := unsignedPayload
+		if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+			payloadHash = emptySHA256
+		}
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	canonicalRequest, signedHeaders := sigV4CanonicalRequest(req)
+	date := now.Format(sigV4ADateFormat)
+	scope := date + "/" + region + "/" + s3ExpressServiceName + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := sigV4Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, data := range []string{date, region, s3ExpressServiceName, "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(data))
+		key = mac.Sum(nil)
+	}
+	req.Header.Set("Authorization", sigV4Algorithm+" Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+hex.EncodeToString(key))
+}
+
+// checkDirectoryBucketPut returns an error when the options of an upload
+// to a directory bucket use features they don't support, rather than the
+// error of the server.
+func checkDirectoryBucketPut(putOpts PutOptions) *probe.Error {
+	if putOpts.sse != nil && putOpts.sse.Type() == encrypt.SSEC {
+		return probe.NewError(APINotImplemented{API: "SSE-C", APIType: directoryBucketUnsupported})
+	}
+	if class := strings.ToUpper(putOpts.storageClass); class != "" && class != s3ExpressStorageClass {
+		return probe.NewError(APINotImplemented{API: "Storage class " + class, APIType: directoryBucketUnsupported})
+	}
+	for name := range putOpts.metadata {
+		switch http.CanonicalHeaderKey(name) {
+		case "X-Amz-Tagging":
+			return probe.NewError(APINotImplemented{API: "Tags", APIType: directoryBucketUnsupported})
+		case AmzObjectLockMode, AmzObjectLockRetainUntilDate, AmzObjectLockLegalHold:
+			return probe.NewError(APINotImplemented{API: "Object lock", APIType: directoryBucketUnsupported})
+		}
+	}
+	return nil
+}
+
+// checkDirectoryBucket returns an error when the target is a directory
+// bucket, which doesn't support api.
+func (c *S3Client) checkDirectoryBucket(api string) *probe.Error {
+	if c.directoryBucket == "" {
+		return nil
+	}
+	return probe.NewError(APINotImplemented{API: api, APIType: directoryBucketUnsupported})
+}
+
+// listDirectoryBucket lists the objects of a directory bucket. Their
+// listings only accept prefixes ending with '/' when not recursive, so
+// the parent prefix is listed and filtered. They are also not sorted, as
+// mc diff and mirror expect, so the objects are sorted once listed.
+func (c *S3Client) listDirectoryBucket(ctx context.Context, bucket, prefix string, isRecursive bool, maxKeys int) <-chan minio.ObjectInfo {
+	listPrefix := prefix
+	if !isRecursive {
+		listPrefix = prefix[:strings.LastIndex(prefix, "/")+1]
+	}
+	objectCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectCh)
+		var objects []minio.ObjectInfo
+		for object := range c.api.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: listPrefix, Recursive: isRecursive, MaxKeys: maxKeys}) {
+			if object.Err != nil {
+				objects = append(objects, object)
+				break
+			}
+			if strings.HasPrefix(object.Key, prefix) {
+				objects = append(objects, object)
+			}
+		}
+		sort.SliceStable(objects, func(i, j int) bool {
+			return objects[i].Err == nil && objects[j].Err == nil && objects[i].Key < objects[j].Key
+		})
+		for _, object := range objects {
+			select {
+			case <-ctx.Done():
+				return
+			case objectCh <- object:
+			}
+		}
+	}()
+	return objectCh
+}
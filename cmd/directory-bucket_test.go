@@ -0,0 +1,92 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+func TestAvailabilityZoneRegion(t *testing.T) {
+	testCases := []struct {
+		zone, want string
+	}{
+		{"usw2-az1", "us-west-2"},
+		{"use1-az4", "us-east-1"},
+		{"apne1-az4", "ap-northeast-1"},
+		{"euc1-az2", "eu-central-1"},
+		{"aps1-az1", "ap-south-1"},
+		{"usgw1-az1", "us-gov-west-1"},
+		{"usx2-az1", ""},
+		{"usw2", ""},
+		{"", ""},
+	}
+	for _, testCase := range testCases {
+		if got := availabilityZoneRegion(testCase.zone); got != testCase.want {
+			t.Errorf("%s: expected %q, got %q", testCase.zone, testCase.want, got)
+		}
+	}
+	if got, want := directoryBucketHost("logs--usw2-az1--x-s3", "us-west-2"), "logs--usw2-az1--x-s3.s3express-usw2-az1.us-west-2.amazonaws.com"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestDirectoryBucketTransport(t *testing.T) {
+	var sessions int
+	var sent *http.Request
+	_, transport := withDirectoryBucket("logs--usw2-az1--x-s3", "us-west-2", credentials.NewStaticV4("access", "secret", ""),
+		roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.RawQuery == "session=" {
+				sessions++
+				body := `<CreateSessionResult><Credentials><SessionToken>token</SessionToken>` +
+					`<SecretAccessKey>session-secret</SecretAccessKey><AccessKeyId>session-access</AccessKeyId>` +
+					`<Expiration>` + time.Now().Add(5*time.Minute).UTC().Format(time.RFC3339) + `</Expiration></Credentials></CreateSessionResult>`
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+			}
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}))
+
+	for i := 0; i < 2; i++ {
+		req, e := http.NewRequest(http.MethodGet, "http://s3.us-west-2.amazonaws.com/logs--usw2-az1--x-s3/2023/a.log", nil)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if _, e = transport.RoundTrip(req); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if sessions != 1 {
+		t.Fatalf("expected a single session, got %d", sessions)
+	}
+	if got, want := sent.URL.String(), "https://logs--usw2-az1--x-s3.s3express-usw2-az1.us-west-2.amazonaws.com/2023/a.log"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := sent.Header.Get(s3ExpressSessionTokenName); got != "token" {
+		t.Fatalf("expected the session token, got %q", got)
+	}
+	if authorization := sent.Header.Get("Authorization"); !strings.Contains(authorization, "Credential=session-access/") ||
+		!strings.Contains(authorization, "/us-west-2/s3express/aws4_request") {
+		t.Fatalf("expected a signature with the session credentials, got %q", authorization)
+	}
+}
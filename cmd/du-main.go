@@ -210,6 +210,10 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 
 // main for du command.
 func mainDu(cliCtx *cli.Context) error {
+	if runOnAliasGroup(cliCtx) {
+		return nil
+	}
+
 	if !cliCtx.Args().Present() {
 		showCommandHelpAndExit(cliCtx, 1)
 	}
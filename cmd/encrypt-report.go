@@ -0,0 +1,305 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
+)
+
+var encryptReportFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "report on all the objects under the prefix",
+	},
+	cli.Float64Flag{
+		Name:  "sample",
+		Usage: "check only a random sample of the given percentage of the objects",
+		Value: 100,
+	},
+	cli.IntFlag{
+		Name:  "depth",
+		Usage: "number of path components of the prefixes the objects are grouped by",
+		Value: 1,
+	},
+}
+
+var encryptReportCmd = cli.Command{
+	Name:         "report",
+	Usage:        "report how the objects of a bucket are encrypted",
+	Action:       mainEncryptReport,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(encryptReportFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+  Objects are counted as SSE-S3, SSE-KMS, SSE-C or unencrypted, grouped by
+  their prefix. Objects whose encryption is not returned by the listing are
+  checked with a HEAD request each, use --sample on large buckets.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Report the encryption of all the objects of "mybucket", by top level prefix.
+     {{.Prompt}} {{.HelpName}} --recursive myminio/mybucket
+
+  2. Report the encryption of a random 5% of the objects under "mybucket/logs/", by day.
+     {{.Prompt}} {{.HelpName}} --recursive --sample 5 --depth 3 myminio/mybucket/logs/
+
+  3. Report the encryption of the objects of "mybucket" in JSON.
+     {{.Prompt}} {{.HelpName}} --recursive --json myminio/mybucket
+`,
+}
+
+// Encryption types of the objects, as reported.
+const (
+	encryptionSSES3  = "sse-s3"
+	encryptionSSEKMS = "sse-kms"
+	encryptionSSEC   = "sse-c"
+	encryptionNone   = "none"
+)
+
+// encryptReportMessage container for the encryption of the objects of a prefix.
+type encryptReportMessage struct {
+	Status      string `json:"status"`
+	Prefix      string `json:"prefix"`
+	Total       bool   `json:"total,omitempty"`
+	Objects     int64  `json:"objects"`
+	SSES3       int64  `json:"sseS3"`
+	SSEKMS      int64  `json:"sseKMS"`
+	SSEC        int64  `json:"sseC"`
+	Unencrypted int64  `json:"unencrypted"`
+	Errors      int64  `json:"errors,omitempty"`
+}
+
+func (r encryptReportMessage) String() string {
+	prefix := r.Prefix
+	switch {
+	case r.Total:
+		prefix = "Total"
+	case prefix == "":
+		prefix = "/"
+	}
+	unencrypted := fmt.Sprintf("%11d", r.Unencrypted)
+	if r.Unencrypted > 0 {
+		unencrypted = console.Colorize("Unencrypted", unencrypted)
+	}
+	row := fmt.Sprintf("%-40s %9d %9d %9d %9d %s", prefix, r.Objects, r.SSES3, r.SSEKMS, r.SSEC, unencrypted)
+	if r.Errors > 0 {
+		row += console.Colorize("Unencrypted", fmt.Sprintf(" (%d not checked)", r.Errors))
+	}
+	if r.Total {
+		return console.Colorize("Total", row)
+	}
+	return row
+}
+
+func (r encryptReportMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (r *encryptReportMessage) add(encryption string) {
+	switch encryption {
+	case "":
+		// The encryption of the object could not be checked.
+		r.Errors++
+		return
+	case encryptionSSES3:
+		r.SSES3++
+	case encryptionSSEKMS:
+		r.SSEKMS++
+	case encryptionSSEC:
+		r.SSEC++
+	default:
+		r.Unencrypted++
+	}
+	r.Objects++
+}
+
+// encryptionFromMetadata returns the encryption type named by the
+// headers of an object, or "" if they do not tell.
+func encryptionFromMetadata(metadata ...map[string]string) string {
+	for _, m := range metadata {
+		for k, v := range m {
+			switch strings.ToLower(k) {
+			case "x-amz-server-side-encryption-customer-algorithm":
+				return encryptionSSEC
+			case "x-amz-server-side-encryption":
+				if strings.HasPrefix(strings.ToLower(v), "aws:kms") {
+					return encryptionSSEKMS
+				}
+				return encryptionSSES3
+			}
+		}
+	}
+	return ""
+}
+
+// objectEncryption returns the encryption type of a listed object.
+func objectEncryption(ctx context.Context, alias string, content *ClientContent) (string, *probe.Error) {
+	if encryption := encryptionFromMetadata(content.Metadata, content.UserMetadata); encryption != "" {
+		return encryption, nil
+	}
+	urlStr := content.URL.String()
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return "", err.Trace(urlStr)
+	}
+	st, err := clnt.Stat(ctx, StatOptions{versionID: content.VersionID})
+	if err != nil {
+		// HEAD on an SSE-C object fails without the customer key, with
+		// a 400 whose error code is only returned by a GET.
+		if minio.ToErrorResponse(err.ToGoError()).StatusCode == http.StatusBadRequest && isSSECObject(ctx, clnt, content.VersionID) {
+			return encryptionSSEC, nil
+		}
+		return "", err.Trace(urlStr)
+	}
+	if encryption := encryptionFromMetadata(st.Metadata, st.UserMetadata); encryption != "" {
+		return encryption, nil
+	}
+	return encryptionNone, nil
+}
+
+// isSSECObject reads the first byte of an object to tell whether it
+// fails for the lack of an SSE-C key.
+func isSSECObject(ctx context.Context, clnt Client, versionID string) bool {
+	reader, err := clnt.Get(ctx, GetOptions{VersionID: versionID})
+	if err != nil {
+		return isSSECRequiredError(err.ToGoError())
+	}
+	defer reader.Close()
+	_, e := reader.Read(make([]byte, 1))
+	return e != nil && isSSECRequiredError(e)
+}
+
+// isSSECRequiredError tells whether an error is the one of reading an
+// SSE-C object without its key.
+func isSSECRequiredError(e error) bool {
+	errResp := minio.ToErrorResponse(e)
+	return errResp.Code == "InvalidRequest" && strings.Contains(errResp.Message, "Server Side Encryption")
+}
+
+// encryptReportPrefix returns the prefix an object is grouped by, the
+// first depth path components after the target.
+func encryptReportPrefix(base, objectPath string, depth int) string {
+	prefix := ""
+	components := strings.Split(strings.TrimPrefix(objectPath, base), "/")
+	for i := 0; i < depth && i < len(components)-1; i++ {
+		prefix += components[i] + "/"
+	}
+	return prefix
+}
+
+// checkEncryptReportSyntax - validate all the passed arguments
+func checkEncryptReportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if sample := ctx.Float64("sample"); sample <= 0 || sample > 100 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("sample")), "--sample must be a percentage greater than 0.")
+	}
+	if ctx.Int("depth") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("depth")), "--depth must not be negative.")
+	}
+}
+
+func mainEncryptReport(cliCtx *cli.Context) error {
+	ctx, cancelEncryptReport := context.WithCancel(globalContext)
+	defer cancelEncryptReport()
+
+	checkEncryptReportSyntax(cliCtx)
+
+	console.SetColor("Header", color.New(color.Bold))
+	console.SetColor("Total", color.New(color.Bold))
+	console.SetColor("Unencrypted", color.New(color.FgRed, color.Bold))
+
+	aliasedURL := cliCtx.Args().Get(0)
+	alias, _, _ := mustExpandAlias(aliasedURL)
+	client, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize connection.")
+
+	sample := cliCtx.Float64("sample") / 100
+	depth := cliCtx.Int("depth")
+	// Prefixes are relative to the target, as a directory.
+	base := filepath.ToSlash(client.GetURL().Path)
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	reports := map[string]*encryptReportMessage{}
+	total := encryptReportMessage{Total: true}
+	for content := range client.List(ctx, ListOptions{Recursive: cliCtx.Bool("recursive"), WithMetadata: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			fatalIf(content.Err.Trace(aliasedURL), "Unable to list the objects.")
+		}
+		if content.Type.IsDir() || (sample < 1 && rand.Float64() >= sample) {
+			continue
+		}
+		// The objects which cannot be checked are reported and counted
+		// apart, the report goes on.
+		encryption, err := objectEncryption(ctx, alias, content)
+		errorIf(err, "Unable to get the encryption of `"+content.URL.String()+"`.")
+
+		prefix := encryptReportPrefix(base, filepath.ToSlash(content.URL.Path), depth)
+		report, ok := reports[prefix]
+		if !ok {
+			report = &encryptReportMessage{Prefix: prefix}
+			reports[prefix] = report
+		}
+		report.add(encryption)
+		total.add(encryption)
+	}
+
+	prefixes := make([]string, 0, len(reports))
+	for prefix := range reports {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	if !globalJSON {
+		console.Println(console.Colorize("Header", fmt.Sprintf("%-40s %9s %9s %9s %9s %11s", "PREFIX", "OBJECTS", "SSE-S3", "SSE-KMS", "SSE-C", "UNENCRYPTED")))
+	}
+	for _, prefix := range prefixes {
+		printMsg(*reports[prefix])
+	}
+	printMsg(total)
+	if total.Errors > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
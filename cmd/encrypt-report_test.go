@@ -0,0 +1,116 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEncryptionFromMetadata(t *testing.T) {
+	testCases := []struct {
+		metadata   map[string]string
+		encryption string
+	}{
+		{map[string]string{"X-Amz-Server-Side-Encryption": "AES256"}, encryptionSSES3},
+		{map[string]string{"x-amz-server-side-encryption": "aws:kms"}, encryptionSSEKMS},
+		{map[string]string{"X-Amz-Server-Side-Encryption": "aws:kms:dsse"}, encryptionSSEKMS},
+		{map[string]string{"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256"}, encryptionSSEC},
+		{map[string]string{"Content-Type": "text/plain"}, ""},
+		{nil, ""},
+	}
+	for i, testCase := range testCases {
+		if encryption := encryptionFromMetadata(testCase.metadata); encryption != testCase.encryption {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.encryption, encryption)
+		}
+	}
+}
+
+func TestEncryptReportPrefix(t *testing.T) {
+	testCases := []struct {
+		path   string
+		depth  int
+		prefix string
+	}{
+		{"/bucket/logs/2023/10/15/a.log", 1, "logs/"},
+		{"/bucket/logs/2023/10/15/a.log", 3, "logs/2023/10/"},
+		{"/bucket/logs/a.log", 3, "logs/"},
+		{"/bucket/a.log", 1, ""},
+		{"/bucket/logs/a.log", 0, ""},
+	}
+	for i, testCase := range testCases {
+		if prefix := encryptReportPrefix("/bucket/", testCase.path, testCase.depth); prefix != testCase.prefix {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.prefix, prefix)
+		}
+	}
+
+	var report encryptReportMessage
+	for _, encryption := range []string{encryptionSSES3, encryptionSSEC, encryptionNone, ""} {
+		report.add(encryption)
+	}
+	if report.Objects != 3 || report.SSES3 != 1 || report.SSEC != 1 || report.Unencrypted != 1 || report.Errors != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+// Only the error of a missing SSE-C key counts an object as SSE-C, HEAD
+// returns a 400 without error code for any bad request.
+func TestObjectEncryptionSSEC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusBadRequest)
+		if r.Method != http.MethodGet {
+			return
+		}
+		switch r.URL.Path {
+		case "/bucket/ssec":
+			w.Write([]byte(`<Error><Code>InvalidRequest</Code><Message>The object was stored using a form of Server Side Encryption. ` +
+				`The correct parameters must be provided to retrieve the object.</Message></Error>`))
+		default:
+			w.Write([]byte(`<Error><Code>InvalidArgument</Code><Message>Invalid argument.</Message></Error>`))
+		}
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	testCases := []struct {
+		object     string
+		encryption string
+		invalid    bool
+	}{
+		{"ssec", encryptionSSEC, false},
+		{"other", "", true},
+	}
+	for i, testCase := range testCases {
+		content := &ClientContent{URL: *newClientURL(server.URL + "/bucket/" + testCase.object)}
+		encryption, err := objectEncryption(context.Background(), "myminio", content)
+		if testCase.invalid != (err != nil) {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if encryption != testCase.encryption {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.encryption, encryption)
+		}
+	}
+}
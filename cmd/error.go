@@ -54,6 +54,7 @@ func fatalIf(err *probe.Error, msg string, data ...interface{}) {
 }
 
 func fatal(err *probe.Error, msg string, data ...interface{}) {
+	status := porcelainExitStatus(errorExitStatus(err))
 	if globalJSON {
 		errorMsg := errorMessage{
 			Message: msg,
@@ -68,17 +69,19 @@ func fatal(err *probe.Error, msg string, data ...interface{}) {
 			errorMsg.SysInfo = err.SysInfo
 		}
 		json, e := json.MarshalIndent(struct {
-			Status string       `json:"status"`
-			Error  errorMessage `json:"error"`
+			Status     string       `json:"status"`
+			Error      errorMessage `json:"error"`
+			ExitStatus int          `json:"exitStatus"`
 		}{
-			Status: "error",
-			Error:  errorMsg,
+			Status:     "error",
+			Error:      errorMsg,
+			ExitStatus: status,
 		}, "", " ")
 		if e != nil {
 			console.Fatalln(probe.NewError(e))
 		}
-		console.Println(string(json))
-		console.Fatalln()
+		console.Println(withAPIVersion(string(json)))
+		fatalExit(status)
 	}
 
 	msg = fmt.Sprintf(msg, data...)
@@ -115,7 +118,11 @@ func fatal(err *probe.Error, msg string, data ...interface{}) {
 		}
 	}
 
-	console.Fatalln(fmt.Sprintf("%s %s", msg, errmsg))
+	if globalCurrentAlias != "" {
+		msg = "[" + globalCurrentAlias + "] " + msg
+	}
+
+	fatalExit(status, fmt.Sprintf("%s %s", msg, errmsg))
 }
 
 // Exit coder wraps cli new exit error with a
@@ -155,10 +162,13 @@ func errorIf(err *probe.Error, msg string, data ...interface{}) {
 		if e != nil {
 			console.Fatalln(probe.NewError(e))
 		}
-		console.Println(string(json))
+		console.Println(withAPIVersion(string(json)))
 		return
 	}
 	msg = fmt.Sprintf(msg, data...)
+	if globalCurrentAlias != "" {
+		msg = "[" + globalCurrentAlias + "] " + msg
+	}
 	if !globalDebug {
 		var e error
 		if errors.Is(globalContext.Err(), context.Canceled) {
@@ -0,0 +1,112 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/minio/madmin-go/v2"
+	"github.com/minio/minio-go/v7"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// Exit statuses of mc, these are part of the --porcelain stability
+// guarantees and must not be renumbered. Without --porcelain, mc exits
+// with 1 on all errors like older releases did, see porcelainExitStatus().
+const (
+	exitStatusSuccess  = 0
+	exitStatusGeneral  = 1 // same as globalErrorExitStatus
+	exitStatusUsage    = 2
+	exitStatusAuth     = 3
+	exitStatusNotFound = 4
+	exitStatusPartial  = 5
+	exitStatusQuota    = 6
+	exitStatusDrift    = 7 // live state deviates, see `mc apply --check`
+)
+
+// porcelainExitStatus returns the detailed exit status with --porcelain,
+// the general error status otherwise, to not break the scripts testing
+// for 1.
+func porcelainExitStatus(status int) int {
+	if !globalPorcelain && status >= exitStatusUsage && status <= exitStatusQuota {
+		return exitStatusGeneral
+	}
+	return status
+}
+
+// errorExitStatus classifies an error to the exit status of mc.
+func errorExitStatus(err *probe.Error) int {
+	if err == nil {
+		return exitStatusSuccess
+	}
+	if errors.Is(globalContext.Err(), context.Canceled) {
+		return globalCancelExitStatus
+	}
+
+	e := err.ToGoError()
+	switch e.(type) {
+	case BucketDoesNotExist, PathNotFound, ObjectMissing:
+		return exitStatusNotFound
+	case PathInsufficientPermission:
+		return exitStatusAuth
+	case InvalidArgument, BucketNameEmpty, ObjectNameEmpty, BucketInvalid:
+		return exitStatusUsage
+	}
+	if e.Error() == errInvalidArgument().ToGoError().Error() {
+		return exitStatusUsage
+	}
+
+	code := minio.ToErrorResponse(e).Code
+	if code == "" {
+		code = madmin.ToErrorResponse(e).Code
+	}
+	switch code {
+	case "AccessDenied", "InvalidAccessKeyId", "SignatureDoesNotMatch",
+		"ExpiredToken", "InvalidToken", "InvalidClientTokenId", "XMinioAdminInvalidAccessKey":
+		return exitStatusAuth
+	case "NoSuchBucket", "NoSuchKey", "NoSuchVersion", "NoSuchUpload",
+		"XMinioAdminNoSuchUser", "XMinioAdminNoSuchGroup", "XMinioAdminNoSuchPolicy":
+		return exitStatusNotFound
+	case "XMinioAdminBucketQuotaExceeded", "QuotaExceeded":
+		return exitStatusQuota
+	}
+	return exitStatusGeneral
+}
+
+// transferExitStatus returns the exit status of a transfer which saw
+// errors, objects copied or removed before failing make it partial.
+func transferExitStatus(err *probe.Error) error {
+	if objectsDone() > 0 {
+		return exitStatus(porcelainExitStatus(exitStatusPartial))
+	}
+	if err == nil {
+		return exitStatus(exitStatusGeneral)
+	}
+	return exitStatus(porcelainExitStatus(errorExitStatus(err)))
+}
+
+// fatalExit prints an error message and exits with the status.
+func fatalExit(status int, data ...interface{}) {
+	if status == globalErrorExitStatus {
+		console.Fatalln(data...)
+	}
+	runExitHooks(status)
+	console.Errorln(data...)
+	exitProcess(status)
+}
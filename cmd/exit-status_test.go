@@ -0,0 +1,109 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/minio/minio-go/v7"
+)
+
+func TestErrorExitStatus(t *testing.T) {
+	testCases := []struct {
+		err    *probe.Error
+		status int
+	}{
+		{nil, exitStatusSuccess},
+		{probe.NewError(errors.New("connection refused")), exitStatusGeneral},
+		{probe.NewError(BucketDoesNotExist{Bucket: "bucket"}), exitStatusNotFound},
+		{probe.NewError(PathNotFound{Path: "file"}), exitStatusNotFound},
+		{probe.NewError(ObjectMissing{}), exitStatusNotFound},
+		{probe.NewError(PathInsufficientPermission{Path: "file"}), exitStatusAuth},
+		{errInvalidArgument(), exitStatusUsage},
+		{probe.NewError(BucketNameEmpty{}), exitStatusUsage},
+		{probe.NewError(minio.ErrorResponse{Code: "AccessDenied"}), exitStatusAuth},
+		{probe.NewError(minio.ErrorResponse{Code: "SignatureDoesNotMatch"}), exitStatusAuth},
+		{probe.NewError(minio.ErrorResponse{Code: "NoSuchKey"}), exitStatusNotFound},
+		{probe.NewError(minio.ErrorResponse{Code: "NoSuchBucket"}), exitStatusNotFound},
+		{probe.NewError(minio.ErrorResponse{Code: "QuotaExceeded"}), exitStatusQuota},
+		{probe.NewError(madmin.ErrorResponse{Code: "XMinioAdminNoSuchUser"}), exitStatusNotFound},
+		{probe.NewError(madmin.ErrorResponse{Code: "XMinioAdminBucketQuotaExceeded"}), exitStatusQuota},
+		{probe.NewError(minio.ErrorResponse{Code: "InternalError"}), exitStatusGeneral},
+	}
+	for i, testCase := range testCases {
+		if status := errorExitStatus(testCase.err); status != testCase.status {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.status, status)
+		}
+	}
+}
+
+func TestPorcelainExitStatus(t *testing.T) {
+	defer func(porcelain bool) { globalPorcelain = porcelain }(globalPorcelain)
+	defer atomic.StoreInt64(&auditObjects, atomic.LoadInt64(&auditObjects))
+
+	testCases := []struct {
+		status    int
+		porcelain int
+		plain     int
+	}{
+		{exitStatusSuccess, exitStatusSuccess, exitStatusSuccess},
+		{exitStatusGeneral, exitStatusGeneral, exitStatusGeneral},
+		{exitStatusUsage, exitStatusUsage, exitStatusGeneral},
+		{exitStatusAuth, exitStatusAuth, exitStatusGeneral},
+		{exitStatusNotFound, exitStatusNotFound, exitStatusGeneral},
+		{exitStatusPartial, exitStatusPartial, exitStatusGeneral},
+		{exitStatusQuota, exitStatusQuota, exitStatusGeneral},
+		{exitStatusDrift, exitStatusDrift, exitStatusDrift},
+		{globalCancelExitStatus, globalCancelExitStatus, globalCancelExitStatus},
+	}
+	for i, testCase := range testCases {
+		globalPorcelain = true
+		if status := porcelainExitStatus(testCase.status); status != testCase.porcelain {
+			t.Errorf("Test %d: expected %d with --porcelain, got %d", i+1, testCase.porcelain, status)
+		}
+		globalPorcelain = false
+		if status := porcelainExitStatus(testCase.status); status != testCase.plain {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.plain, status)
+		}
+	}
+
+	// A transfer failing after some objects is partial.
+	notFound := probe.NewError(minio.ErrorResponse{Code: "NoSuchKey"})
+	for i, testCase := range []struct {
+		porcelain bool
+		objects   int64
+		status    int
+	}{
+		{true, 0, exitStatusNotFound},
+		{true, 2, exitStatusPartial},
+		{false, 0, exitStatusGeneral},
+		{false, 2, exitStatusGeneral},
+	} {
+		globalPorcelain = testCase.porcelain
+		atomic.StoreInt64(&auditObjects, testCase.objects)
+		var exitErr cli.ExitCoder
+		if !errors.As(transferExitStatus(notFound), &exitErr) || exitErr.ExitCode() != testCase.status {
+			t.Errorf("Test %d: expected exit status %d", i+1, testCase.status)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"os"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+// exitProcess exits mc, it is replaced by `mc shell` to keep running
+// after a command failed.
+var exitProcess = os.Exit
+
+var (
+	exitHooksMu   sync.Mutex
+	exitHooks     []func(exitStatus int)
+	exitHooksDone bool
+)
+
+// onExit registers a function to run once before mc exits.
+func onExit(fn func(exitStatus int)) {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+	exitHooks = append(exitHooks, fn)
+}
+
+// runExitHooks runs the registered exit hooks, only the first call has an effect.
+func runExitHooks(exitStatus int) {
+	exitHooksMu.Lock()
+	if exitHooksDone {
+		exitHooksMu.Unlock()
+		return
+	}
+	exitHooksDone = true
+	hooks := exitHooks
+	exitHooksMu.Unlock()
+
+	for _, fn := range hooks {
+		fn(exitStatus)
+	}
+}
+
+// resetExitHooks allows the exit hooks to run again, for the next
+// command run by `mc shell`.
+func resetExitHooks() {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+	exitHooksDone = false
+}
+
+// hookExit runs the exit hooks before mc exits on a fatal error or
+// with the exit status returned by a command, the exit itself goes
+// through exitProcess.
+func hookExit() {
+	console.Fatal = func(data ...interface{}) {
+		runExitHooks(globalErrorExitStatus)
+		console.Error(data...)
+		exitProcess(globalErrorExitStatus)
+	}
+	console.Fatalf = func(format string, data ...interface{}) {
+		runExitHooks(globalErrorExitStatus)
+		console.Errorf(format, data...)
+		exitProcess(globalErrorExitStatus)
+	}
+	console.Fatalln = func(data ...interface{}) {
+		runExitHooks(globalErrorExitStatus)
+		console.Errorln(data...)
+		exitProcess(globalErrorExitStatus)
+	}
+	cli.OsExiter = func(code int) {
+		runExitHooks(code)
+		exitProcess(code)
+	}
+}
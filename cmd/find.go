@@ -21,7 +21,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -129,7 +128,7 @@ func execFind(ctx context.Context, args string, fileContent contentMessage) {
 	split, err := shlex.Split(args)
 	if err != nil {
 		console.Println(console.Colorize("FindExecErr", "Unable to parse --exec: "+err.Error()))
-		os.Exit(getExitStatus(err))
+		exitProcess(getExitStatus(err))
 	}
 	if len(split) == 0 {
 		return
@@ -148,7 +147,7 @@ func execFind(ctx context.Context, args string, fileContent contentMessage) {
 		}
 		console.Println(console.Colorize("FindExecErr", err.Error()))
 		// Return exit status of the command run
-		os.Exit(getExitStatus(err))
+		exitProcess(getExitStatus(err))
 	}
 	console.PrintC(out.String())
 }
@@ -459,7 +458,7 @@ func getShareURL(ctx context.Context, path string) string {
 	fatalIf(err.Trace(targetAlias, objectURL), "Unable to initialize new client from alias.")
 
 	// Set default expiry for each url (point of no longer valid), to be 7 days
-	shareURL, err := newClnt.ShareDownload(ctx, "", defaultSevenDays)
+	shareURL, err := newClnt.ShareDownload(ctx, "", defaultSevenDays, nil)
 	fatalIf(err.Trace(targetAlias, objectURL), "Unable to generate share url.")
 
 	return shareURL
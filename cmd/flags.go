@@ -26,29 +26,104 @@ import (
 // Collection of mc flags currently supported
 var globalFlags = []cli.Flag{
 	cli.StringFlag{
-		Name:  "config-dir, C",
-		Value: mustGetMcConfigDir(),
-		Usage: "path to configuration folder",
+		Name:   "config-dir, C",
+		Value:  mustGetMcConfigDir(),
+		Usage:  "path to configuration folder",
+		EnvVar: "MC_CONFIG_DIR",
+	},
+	cli.StringFlag{
+		Name:   "profile",
+		Usage:  "use the configuration folder of a named profile, --config-dir takes precedence",
+		EnvVar: mcEnvConfigProfile,
 	},
 	cli.BoolFlag{
-		Name:  "quiet, q",
-		Usage: "disable progress bar display",
+		Name:   "quiet, q",
+		Usage:  "disable progress bar display",
+		EnvVar: "MC_QUIET",
 	},
 	cli.BoolFlag{
-		Name:  "no-color",
-		Usage: "disable color theme",
+		Name:   "no-color",
+		Usage:  "disable color theme",
+		EnvVar: "MC_NO_COLOR",
 	},
 	cli.BoolFlag{
-		Name:  "json",
-		Usage: "enable JSON lines formatted output",
+		Name:   "json",
+		Usage:  "enable JSON lines formatted output",
+		EnvVar: "MC_JSON",
 	},
 	cli.BoolFlag{
-		Name:  "debug",
-		Usage: "enable debug output",
+		Name:  "porcelain",
+		Usage: "enable stable, script friendly JSON lines output and exit statuses",
+	},
+	cli.IntFlag{
+		Name:   "api-version",
+		Usage:  "version of the schema of the JSON output, 1 or 2",
+		Value:  jsonAPIVersion1,
+		EnvVar: "MC_API_VERSION",
+	},
+	cli.StringFlag{
+		Name:   "output",
+		Usage:  "output format, one of text, json, yaml, table or csv",
+		EnvVar: "MC_OUTPUT",
+	},
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "format the output using a Go template, e.g. '{{.Key}}\\t{{.Size}}'",
 	},
 	cli.BoolFlag{
-		Name:  "insecure",
-		Usage: "disable SSL certificate verification",
+		Name:   "debug",
+		Usage:  "enable debug output",
+		EnvVar: "MC_DEBUG",
+	},
+	cli.StringFlag{
+		Name:   "trace-file",
+		Usage:  "write redacted HTTP request and response traces to a rotating file",
+		EnvVar: "MC_TRACE_FILE",
+	},
+	cli.BoolFlag{
+		Name:  "curl",
+		Usage: "print a curl command reproducing the last failed request",
+	},
+	cli.DurationFlag{
+		Name:  "request-timeout",
+		Usage: "abort HTTP requests, including the transfer of their body, taking longer than this, 0 for no limit",
+	},
+	cli.DurationFlag{
+		Name:  "connect-timeout",
+		Usage: "abort connecting to the server after this long",
+		Value: 10 * time.Second,
+	},
+	cli.DurationFlag{
+		Name:  "response-header-timeout",
+		Usage: "abort HTTP requests when the server doesn't respond within this long, 0 for no limit",
+	},
+	cli.IntFlag{
+		Name:  "retries",
+		Usage: "number of times failed HTTP requests are retried",
+		Value: 10,
+	},
+	cli.IntFlag{
+		Name:  "max-idle-conns-per-host",
+		Usage: "number of idle connections kept open to a server, more with --parallel",
+		Value: 1024,
+	},
+	cli.BoolFlag{
+		Name:  "disable-http2",
+		Usage: "use HTTP/1.1 also when the server supports HTTP/2",
+	},
+	cli.DurationFlag{
+		Name:  "tcp-keepalive",
+		Usage: "period of the TCP keep-alive probes, negative disables them",
+		Value: 15 * time.Second,
+	},
+	cli.BoolFlag{
+		Name:   "insecure",
+		Usage:  "disable SSL certificate verification",
+		EnvVar: "MC_INSECURE",
+	},
+	cli.StringFlag{
+		Name:  "request-payer",
+		Usage: "pay the requests to requester pays buckets, only 'requester' is accepted",
 	},
 	cli.StringFlag{
 		Name:  "limit-upload",
@@ -58,6 +133,11 @@ var globalFlags = []cli.Flag{
 		Name:  "limit-download",
 		Usage: "limits downloads to a maximum rate in KiB/s, MiB/s, GiB/s. (default: unlimited)",
 	},
+	cli.StringFlag{
+		Name:   "audit-log",
+		Usage:  "append a JSON record of the command to a client side audit log",
+		EnvVar: "MC_AUDIT_LOG",
+	},
 	cli.DurationFlag{
 		Name:   "conn-read-deadline",
 		Usage:  "custom connection READ deadline",
@@ -72,6 +152,25 @@ var globalFlags = []cli.Flag{
 	},
 }
 
+// Flags of the commands uploading with client side compression and
+// encryption, cp and pipe.
+// transformFlag pipes the objects through a command during cp and mirror.
+var transformFlag = cli.StringFlag{
+	Name:  "transform",
+	Usage: "pipe each object through 'gzip', 'gunzip' or a shell command before writing the target",
+}
+
+var codecFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "compress",
+		Usage: "compress objects on the client before upload (gzip, zstd)",
+	},
+	cli.StringFlag{
+		Name:  "encrypt-with",
+		Usage: "encrypt objects on the client before upload for an age recipient (age1...) or a gpg recipient",
+	},
+}
+
 // Flags common across all I/O commands such as cp, mirror, stat, pipe etc.
 var ioFlags = []cli.Flag{
 	cli.StringFlag{
@@ -0,0 +1,281 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var getFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "out, o",
+		Usage: "write the object to a file, '-' for stdout (default: the object name in the current directory)",
+	},
+	cli.StringFlag{
+		Name:  "version-id, vid",
+		Usage: "download a specific version of the object",
+	},
+}
+
+var getCmd = cli.Command{
+	Name:         "get",
+	Usage:        "download an object and verify its checksum",
+	Action:       mainGet,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(getFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS/BUCKET/OBJECT
+
+  Download a single object. The content is always verified against the
+  checksum stored by the server or the ETag of the object, the command
+  fails when the object has none which can be verified. Files are only
+  created once the content is verified, data written to stdout can't be
+  taken back and the command exits with an error on a mismatch.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Download an object to the current directory.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket/backup.tar
+
+  2. Download an object to a file.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket/backup.tar -o /mnt/restore/backup.tar
+
+  3. Stream a version of an object to stdout.
+     {{.Prompt}} {{.HelpName}} --version-id "3ddac055-89a7-40fa-8cd3-530a5581b6b8" myminio/mybucket/dump.sql -o - | psql
+`,
+}
+
+// getMessage container for a downloaded object
+type getMessage struct {
+	Status    string `json:"status"`
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+	Size      int64  `json:"size"`
+	VersionID string `json:"versionId,omitempty"`
+	Algorithm string `json:"algorithm"`
+	Checksum  string `json:"checksum"`
+}
+
+func (g getMessage) String() string {
+	return console.Colorize("Get", fmt.Sprintf("`%s` -> `%s`", g.Source, g.Target)) +
+		fmt.Sprintf(" (%s, %s verified)", humanize.IBytes(uint64(g.Size)), g.Algorithm)
+}
+
+func (g getMessage) JSON() string {
+	g.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(g, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// getVerifier is the checksum an object is verified against.
+type getVerifier struct {
+	algorithm string
+	// checksum is in hex, with the number of parts for multipart ETags.
+	checksum string
+	// parts is the number of parts of a multipart ETag, the object is then
+	// downloaded part by part to compute it.
+	parts int
+}
+
+var multipartETagRx = regexp.MustCompile(`^([0-9a-f]{32})-([0-9]+)$`)
+
+// md5Rx matches the ETags of single part objects.
+var md5Rx = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// newGetVerifier returns the checksum to verify an object against. The
+// checksums stored by the server are preferred, the ETag is the MD5 of the
+// content unless the object is encrypted with SSE-C or SSE-KMS.
+func newGetVerifier(st *ClientContent) (getVerifier, *probe.Error) {
+	for _, algorithm := range []string{"sha256", "sha1", "crc32c", "crc32"} {
+		if sum := serverChecksum(st, algorithm); sum != "" {
+			return getVerifier{algorithm: algorithm, checksum: sum}, nil
+		}
+	}
+
+	encrypted := st.Metadata["X-Amz-Server-Side-Encryption-Customer-Algorithm"] != "" ||
+		strings.HasPrefix(st.Metadata["X-Amz-Server-Side-Encryption"], "aws:kms")
+	etag := strings.ToLower(strings.Trim(st.ETag, "\""))
+	if !encrypted {
+		if md5Rx.MatchString(etag) {
+			return getVerifier{algorithm: "md5", checksum: etag}, nil
+		}
+		if m := multipartETagRx.FindStringSubmatch(etag); m != nil {
+			parts, e := strconv.Atoi(m[2])
+			if e == nil && parts > 0 {
+				return getVerifier{algorithm: "md5", checksum: etag, parts: parts}, nil
+			}
+		}
+	}
+	return getVerifier{}, probe.NewError(fmt.Errorf("the object has no checksum which can be verified, use 'mc cp' to download it without verification"))
+}
+
+// download writes the object to w and returns the checksum of the content,
+// computed in the format of the verifier.
+func (v getVerifier) download(ctx context.Context, clnt Client, opts GetOptions, w io.Writer) (size int64, sum string, err *probe.Error) {
+	if v.parts == 0 {
+		h, e := checksumHash(v.algorithm)
+		if e != nil {
+			return 0, "", probe.NewError(e)
+		}
+		size, err = getPart(ctx, clnt, opts, io.MultiWriter(w, h))
+		return size, hex.EncodeToString(h.Sum(nil)), err
+	}
+
+	// The ETag of a multipart object is the MD5 of the MD5s of its parts.
+	var partSums []byte
+	var h hash.Hash
+	for part := 1; part <= v.parts; part++ {
+		h = md5.New()
+		opts.PartNumber = part
+		n, err := getPart(ctx, clnt, opts, io.MultiWriter(w, h))
+		if err != nil {
+			return size, "", err
+		}
+		size += n
+		partSums = h.Sum(partSums)
+	}
+	h = md5.New()
+	h.Write(partSums)
+	return size, fmt.Sprintf("%s-%d", hex.EncodeToString(h.Sum(nil)), v.parts), nil
+}
+
+func getPart(ctx context.Context, clnt Client, opts GetOptions, w io.Writer) (int64, *probe.Error) {
+	reader, err := clnt.Get(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	n, e := io.Copy(w, reader)
+	return n, probe.NewError(e)
+}
+
+func checkGetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+	alias, _, _ := mustExpandAlias(ctx.Args().Get(0))
+	if alias == "" {
+		fatalIf(errInvalidArgument().Trace(ctx.Args().Get(0)), "The source must be an object on an alias, use 'mc cp' to copy local files.")
+	}
+}
+
+// mainGet is the handle for "mc get" command.
+func mainGet(cliCtx *cli.Context) error {
+	ctx, cancelGet := context.WithCancel(globalContext)
+	defer cancelGet()
+
+	checkGetSyntax(cliCtx)
+	console.SetColor("Get", color.New(color.FgGreen))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	sourceURL := cliCtx.Args().Get(0)
+	alias, _, _ := mustExpandAlias(sourceURL)
+	clnt, err := newClient(sourceURL)
+	fatalIf(err.Trace(sourceURL), "Unable to initialize the client.")
+
+	opts := GetOptions{
+		SSE:       getSSE(filepath.Join(alias, clnt.GetURL().Path), encKeyDB[alias]),
+		VersionID: cliCtx.String("version-id"),
+	}
+	st, err := clnt.Stat(ctx, StatOptions{sse: opts.SSE, versionID: opts.VersionID, checksum: true})
+	fatalIf(err.Trace(sourceURL), "Unable to get the object.")
+	if st.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(sourceURL), "The source must be an object, use 'mc cp --recursive' to download prefixes.")
+	}
+	verifier, err := newGetVerifier(st)
+	fatalIf(err.Trace(sourceURL), "Unable to verify `"+sourceURL+"`.")
+	// Download the version which was stat'ed even if a new one is written.
+	if opts.VersionID == "" {
+		opts.VersionID = st.VersionID
+	}
+
+	target := cliCtx.String("out")
+	if target == "" {
+		target = path.Base(filepath.ToSlash(clnt.GetURL().Path))
+	}
+
+	var size int64
+	var sum string
+	if target == "-" {
+		size, sum, err = verifier.download(ctx, clnt, opts, os.Stdout)
+		fatalIf(err.Trace(sourceURL), "Unable to download `"+sourceURL+"`.")
+	} else {
+		// Write to a temporary file next to the target, which is renamed
+		// once the content is verified.
+		f, e := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*.mcget")
+		fatalIf(probe.NewError(e), "Unable to create `"+target+"`.")
+		size, sum, err = verifier.download(ctx, clnt, opts, f)
+		if e = f.Close(); e != nil && err == nil {
+			err = probe.NewError(e)
+		}
+		if err == nil && sum == verifier.checksum {
+			err = probe.NewError(os.Rename(f.Name(), target))
+		}
+		if err != nil || sum != verifier.checksum {
+			os.Remove(f.Name())
+		}
+		fatalIf(err.Trace(sourceURL), "Unable to download `"+sourceURL+"`.")
+	}
+	if sum != verifier.checksum {
+		fatalIf(errDummy().Trace(sourceURL), fmt.Sprintf("The %s checksum of `%s` is %s, expected %s.", verifier.algorithm, sourceURL, sum, verifier.checksum))
+	}
+
+	// The content is on stdout, only report it in JSON on stderr.
+	if target == "-" {
+		if globalJSON {
+			msg := getMessage{Source: sourceURL, Target: target, Size: size, VersionID: st.VersionID, Algorithm: verifier.algorithm, Checksum: sum}
+			fmt.Fprintln(os.Stderr, msg.JSON())
+		}
+		return nil
+	}
+	printMsg(getMessage{
+		Source:    sourceURL,
+		Target:    target,
+		Size:      size,
+		VersionID: st.VersionID,
+		Algorithm: verifier.algorithm,
+		Checksum:  sum,
+	})
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import "testing"
+
+func TestNewGetVerifier(t *testing.T) {
+	testCases := []struct {
+		content   ClientContent
+		algorithm string
+		checksum  string
+		parts     int
+		fail      bool
+	}{
+		{
+			content:   ClientContent{ETag: "\"D41D8CD98F00B204E9800998ECF8427E\""},
+			algorithm: "md5",
+			checksum:  "d41d8cd98f00b204e9800998ecf8427e",
+		},
+		{
+			content:   ClientContent{ETag: "d41d8cd98f00b204e9800998ecf8427e-12"},
+			algorithm: "md5",
+			checksum:  "d41d8cd98f00b204e9800998ecf8427e-12",
+			parts:     12,
+		},
+		{
+			// The stored checksum is preferred to the ETag.
+			content: ClientContent{
+				ETag:      "d41d8cd98f00b204e9800998ecf8427e",
+				Checksums: map[string]string{"crc32c": "AAAAAA=="},
+			},
+			algorithm: "crc32c",
+			checksum:  "00000000",
+		},
+		{
+			content: ClientContent{
+				ETag:     "d41d8cd98f00b204e9800998ecf8427e",
+				Metadata: map[string]string{"X-Amz-Server-Side-Encryption": "aws:kms"},
+			},
+			fail: true,
+		},
+		{
+			// Composite checksums of multipart objects can't be verified.
+			content: ClientContent{
+				ETag:      "opaque",
+				Checksums: map[string]string{"sha256": "AAAAAA==-3"},
+			},
+			fail: true,
+		},
+	}
+	for i, testCase := range testCases {
+		v, err := newGetVerifier(&testCase.content)
+		if testCase.fail {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got %+v", i+1, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if v.algorithm != testCase.algorithm || v.checksum != testCase.checksum || v.parts != testCase.parts {
+			t.Errorf("Test %d: expected %s %s %d, got %+v", i+1, testCase.algorithm, testCase.checksum, testCase.parts, v)
+		}
+	}
+}
@@ -22,9 +22,12 @@ import (
 	"context"
 	"crypto/x509"
 	"net/url"
+	"strconv"
+	"text/template"
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/kirolous/mc/pkg/probe"
 	"github.com/minio/cli"
 	"github.com/minio/madmin-go/v2"
 	"github.com/minio/pkg/console"
@@ -61,6 +64,10 @@ const (
 var (
 	globalQuiet          = false               // Quiet flag set via command line
 	globalJSON           = false               // Json flag set via command line
+	globalPorcelain      = false               // Porcelain flag set via command line
+	globalOutput         = ""                  // Output format set via --output, when other than text or json
+	globalFormat         *template.Template    // Output template set via --format
+	globalAPIVersion     = jsonAPIVersion1     // Version of the JSON schema set via --api-version
 	globalJSONLine       = false               // Print json as single line.
 	globalDebug          = false               // Debug flag set via command line
 	globalNoColor        = false               // No Color flag set via command line
@@ -91,17 +98,76 @@ var (
 
 // Set global states. NOTE: It is deliberately kept monolithic to ensure we dont miss out any flags.
 func setGlobalsFromContext(ctx *cli.Context) error {
-	quiet := ctx.IsSet("quiet") || ctx.GlobalIsSet("quiet")
-	debug := ctx.IsSet("debug") || ctx.GlobalIsSet("debug")
-	json := ctx.IsSet("json") || ctx.GlobalIsSet("json")
-	noColor := ctx.IsSet("no-color") || ctx.GlobalIsSet("no-color")
-	insecure := ctx.IsSet("insecure") || ctx.GlobalIsSet("insecure")
+	applyAliasDefaults(ctx)
+	checkAliasProtection(ctx)
+	enableAuditLog(ctx)
+	enableHTTPTrace(ctx)
+	setHTTPTimeoutsFromContext(ctx)
+	setHTTPTransportFromContext(ctx)
+	setRequestPayerFromContext(ctx)
+
+	quiet := ctx.Bool("quiet") || ctx.GlobalBool("quiet")
+	debug := ctx.Bool("debug") || ctx.GlobalBool("debug")
+	json := ctx.Bool("json") || ctx.GlobalBool("json")
+	noColor := ctx.Bool("no-color") || ctx.GlobalBool("no-color")
+
+	output := ctx.String("output")
+	if output == "" {
+		output = ctx.GlobalString("output")
+	}
+	switch output {
+	case "", "text":
+	case "json":
+		json = true
+	case outputYAML, outputTable, outputCSV:
+		// Machine friendly formats are rendered from the JSON messages.
+		json = true
+		noColor = true
+		if globalOutput == "" && output == outputTable {
+			onExit(flushTableOutput)
+		}
+		globalOutput = output
+	default:
+		fatalIf(errInvalidArgument().Trace(output), "Unsupported output format, must be one of text, json, yaml, table or csv.")
+	}
+
+	format := ctx.String("format")
+	if format == "" {
+		format = ctx.GlobalString("format")
+	}
+	if format != "" {
+		var err *probe.Error
+		globalFormat, err = parseFormat(format)
+		fatalIf(err.Trace(format), "Unable to parse the format template.")
+		// Templates are applied to the messages printed in JSON mode.
+		json = true
+		noColor = true
+	}
+	insecure := ctx.Bool("insecure") || ctx.GlobalBool("insecure") || isAliasDefaultTrue(ctx, "insecure")
 	devMode := ctx.IsSet("dev") || ctx.GlobalIsSet("dev")
 	airgapped := ctx.IsSet("airgap") || ctx.GlobalIsSet("airgap")
+	porcelain := ctx.IsSet("porcelain") || ctx.GlobalIsSet("porcelain")
+
+	if porcelain {
+		// Porcelain output is JSON lines without progress or colors,
+		// also when printed to a terminal.
+		json, quiet, noColor = true, true, true
+		globalPorcelain = true
+	}
+
+	switch {
+	case ctx.IsSet("api-version"):
+		globalAPIVersion = ctx.Int("api-version")
+	case ctx.GlobalIsSet("api-version"):
+		globalAPIVersion = ctx.GlobalInt("api-version")
+	}
+	if globalAPIVersion < jsonAPIVersion1 || globalAPIVersion > jsonAPIVersionLatest {
+		fatalIf(errInvalidArgument().Trace(strconv.Itoa(globalAPIVersion)), "Unsupported JSON API version, must be between %d and %d.", jsonAPIVersion1, jsonAPIVersionLatest)
+	}
 
 	globalQuiet = globalQuiet || quiet
 	globalDebug = globalDebug || debug
-	globalJSONLine = !isTerminal() && json
+	globalJSONLine = (!isTerminal() || globalPorcelain) && json
 	globalJSON = globalJSON || json
 	globalNoColor = globalNoColor || noColor || globalJSONLine
 	globalInsecure = globalInsecure || insecure
@@ -0,0 +1,134 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	// The alias connects directly, ignoring the proxy environment variables.
+	proxyNone = "none"
+	// The alias uses the proxy environment variables, the default.
+	proxyEnv = "env"
+)
+
+// validateProxy checks the --proxy value of 'mc alias set'.
+func validateProxy(value string) *probe.Error {
+	switch value {
+	case "", proxyNone, proxyEnv:
+		return nil
+	}
+	u, e := url.Parse(value)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return probe.NewError(fmt.Errorf("unsupported proxy scheme %q, must be one of http, https or socks5", u.Scheme))
+	}
+	if u.Host == "" {
+		return probe.NewError(fmt.Errorf("missing proxy host in %q", value))
+	}
+	return nil
+}
+
+// keepAliasProxy keeps the proxy of the previous definition of an alias
+// when --proxy is not given.
+func keepAliasProxy(aliasCfg *aliasConfigV10, prev aliasConfigV10) {
+	if aliasCfg.Proxy == "" {
+		aliasCfg.Proxy = prev.Proxy
+		if aliasCfg.NoProxy == "" {
+			aliasCfg.NoProxy = prev.NoProxy
+		}
+	}
+	if aliasCfg.Proxy == proxyEnv {
+		aliasCfg.Proxy = ""
+	}
+}
+
+// proxyFor returns the proxy of the alias for u, nil when u is reached
+// directly. ok is false when the alias uses the environment variables.
+func (config *Config) proxyFor(u *url.URL) (proxyURL *url.URL, ok bool, e error) {
+	switch config.Proxy {
+	case "", proxyEnv:
+		return nil, false, nil
+	case proxyNone:
+		return nil, true, nil
+	}
+	// Same rules as the HTTP_PROXY, HTTPS_PROXY and NO_PROXY variables,
+	// localhost is never proxied.
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  config.Proxy,
+		HTTPSProxy: config.Proxy,
+		NoProxy:    config.NoProxy,
+	}).ProxyFunc()
+	proxyURL, e = proxyFunc(u)
+	return proxyURL, true, e
+}
+
+// proxyFunc returns the proxy selection of the HTTP transports, fallback
+// is used when the alias doesn't override the environment variables.
+func (config *Config) proxyFunc(fallback func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	if config.Proxy == "" || config.Proxy == proxyEnv {
+		return fallback
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, _, e := config.proxyFor(req.URL)
+		return proxyURL, e
+	}
+}
+
+// proxyDialer returns the dialer of the connections not made by the HTTP
+// transports, such as SFTP, through the SOCKS5 proxy of the alias.
+func (config *Config) proxyDialer(scheme, address string) (dialContext, error) {
+	direct := &net.Dialer{Timeout: config.connectTimeout(), KeepAlive: config.tcpKeepAlive()}
+	proxyURL, ok, e := config.proxyFor(&url.URL{Scheme: "https", Host: address})
+	if e != nil {
+		return nil, e
+	}
+	if !ok {
+		// The environment variables only apply to HTTP.
+		return direct.DialContext, nil
+	}
+	if proxyURL == nil {
+		return direct.DialContext, nil
+	}
+	if !strings.EqualFold(proxyURL.Scheme, "socks5") {
+		return nil, fmt.Errorf("%s connections require a socks5 proxy, not %s", scheme, proxyURL.Scheme)
+	}
+	dialer, e := proxy.FromURL(proxyURL, direct)
+	if e != nil {
+		return nil, e
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if d, ok := dialer.(proxy.ContextDialer); ok {
+			return d.DialContext(ctx, network, addr)
+		}
+		return dialer.Dial(network, addr)
+	}, nil
+}
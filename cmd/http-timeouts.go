@@ -0,0 +1,199 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+// Connect timeout used when neither the alias nor the command line set it.
+const defaultConnectTimeout = 10 * time.Second
+
+// notSetOnCommandLine is the value of the timeouts and the retries not
+// given on the command line, 0 is a valid value meaning no limit.
+const notSetOnCommandLine = -1
+
+var (
+	globalRequestTimeout        time.Duration = notSetOnCommandLine
+	globalConnectTimeout        time.Duration = notSetOnCommandLine
+	globalResponseHeaderTimeout time.Duration = notSetOnCommandLine
+	globalRetries                             = notSetOnCommandLine
+)
+
+// setHTTPTimeoutsFromContext sets the timeouts and the retries given on the command line.
+func setHTTPTimeoutsFromContext(ctx *cli.Context) {
+	duration := func(name string) time.Duration {
+		var d time.Duration
+		switch {
+		case ctx.IsSet(name):
+			d = ctx.Duration(name)
+		case ctx.GlobalIsSet(name):
+			d = ctx.GlobalDuration(name)
+		default:
+			return notSetOnCommandLine
+		}
+		if d < 0 {
+			fatalIf(errInvalidArgument().Trace(d.String()), "--"+name+" cannot be negative.")
+		}
+		return d
+	}
+	globalRequestTimeout = duration("request-timeout")
+	globalConnectTimeout = duration("connect-timeout")
+	globalResponseHeaderTimeout = duration("response-header-timeout")
+
+	globalRetries = notSetOnCommandLine
+	switch {
+	case ctx.IsSet("retries"):
+		globalRetries = ctx.Int("retries")
+	case ctx.GlobalIsSet("retries"):
+		globalRetries = ctx.GlobalInt("retries")
+	default:
+		return
+	}
+	if globalRetries < 0 {
+		fatalIf(errInvalidArgument().Trace(strconv.Itoa(globalRetries)), "The number of retries cannot be negative.")
+	}
+}
+
+// setAliasTimeouts stores the timeouts and the retries given on the
+// command line in the alias config.
+func setAliasTimeouts(aliasCfg *aliasConfigV10) {
+	if globalRequestTimeout >= 0 {
+		aliasCfg.RequestTimeout = globalRequestTimeout.String()
+	}
+	if globalConnectTimeout >= 0 {
+		aliasCfg.ConnectTimeout = globalConnectTimeout.String()
+	}
+	if globalResponseHeaderTimeout >= 0 {
+		aliasCfg.ResponseHeaderTimeout = globalResponseHeaderTimeout.String()
+	}
+	if globalRetries >= 0 {
+		retries := globalRetries
+		aliasCfg.Retries = &retries
+	}
+}
+
+// applyHTTPTimeouts sets the timeouts and the retries of the alias in
+// config, the command line takes precedence over the alias.
+func applyHTTPTimeouts(config *Config, aliasCfg *aliasConfigV10) {
+	parse := func(value string) time.Duration {
+		if value == "" {
+			return 0
+		}
+		d, e := time.ParseDuration(value)
+		fatalIf(probe.NewError(e).Trace(value), "Unable to parse the timeout of the alias.")
+		return d
+	}
+	if aliasCfg != nil {
+		config.RequestTimeout = parse(aliasCfg.RequestTimeout)
+		config.ConnectTimeout = parse(aliasCfg.ConnectTimeout)
+		config.ResponseHeaderTimeout = parse(aliasCfg.ResponseHeaderTimeout)
+		if aliasCfg.Retries != nil {
+			config.MaxAttempts = *aliasCfg.Retries + 1
+		}
+	}
+	if globalRequestTimeout >= 0 {
+		config.RequestTimeout = globalRequestTimeout
+	}
+	if globalConnectTimeout >= 0 {
+		config.ConnectTimeout = globalConnectTimeout
+	}
+	if globalResponseHeaderTimeout >= 0 {
+		config.ResponseHeaderTimeout = globalResponseHeaderTimeout
+	}
+	if globalRetries >= 0 {
+		config.MaxAttempts = globalRetries + 1
+	}
+}
+
+// connectTimeout returns the connect timeout of config.
+func (config *Config) connectTimeout() time.Duration {
+	if config.ConnectTimeout > 0 {
+		return config.ConnectTimeout
+	}
+	return defaultConnectTimeout
+}
+
+var (
+	maxAttemptsMu  sync.Mutex
+	maxAttemptsSet bool
+)
+
+// setMaxAttempts sets the number of attempts of the S3 and admin requests.
+// The SDKs share it between all the clients, when the aliases of a command
+// disagree the lowest number of attempts is used.
+func setMaxAttempts(attempts int) {
+	if attempts <= 0 {
+		return
+	}
+	maxAttemptsMu.Lock()
+	defer maxAttemptsMu.Unlock()
+	if maxAttemptsSet && attempts >= minio.MaxRetry {
+		return
+	}
+	maxAttemptsSet = true
+	minio.MaxRetry = attempts
+	madmin.MaxRetry = attempts
+}
+
+// withRequestTimeout returns transport aborting the requests not done
+// within timeout, the timeout covers reading the response body.
+func withRequestTimeout(transport http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout <= 0 {
+		return transport
+	}
+	return requestTimeoutTransport{transport: transport, timeout: timeout}
+}
+
+type requestTimeoutTransport struct {
+	transport http.RoundTripper
+	timeout   time.Duration
+}
+
+func (t requestTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, e := t.transport.RoundTrip(req.WithContext(ctx))
+	if e != nil {
+		cancel()
+		return nil, e
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of a request once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	e := b.ReadCloser.Close()
+	b.cancel()
+	return e
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestSetHTTPTimeoutsFromContext(t *testing.T) {
+	defer func() {
+		globalRequestTimeout, globalConnectTimeout, globalResponseHeaderTimeout = notSetOnCommandLine, notSetOnCommandLine, notSetOnCommandLine
+		globalRetries = notSetOnCommandLine
+	}()
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{{
+		Name:   "ls",
+		Flags:  globalFlags,
+		Action: func(ctx *cli.Context) error { setHTTPTimeoutsFromContext(ctx); return nil },
+	}}
+	testCases := []struct {
+		args                             []string
+		request, connect, responseHeader time.Duration
+		retries                          int
+	}{
+		// The defaults of the flags do not override the alias.
+		{[]string{"mc", "ls"}, notSetOnCommandLine, notSetOnCommandLine, notSetOnCommandLine, notSetOnCommandLine},
+		// 0 is given to remove the limit of the alias.
+		{[]string{"mc", "ls", "--request-timeout", "0", "--retries", "0"}, 0, notSetOnCommandLine, notSetOnCommandLine, 0},
+		{[]string{"mc", "ls", "--connect-timeout", "3s", "--response-header-timeout", "1m", "--retries", "2"}, notSetOnCommandLine, 3 * time.Second, time.Minute, 2},
+	}
+	for i, testCase := range testCases {
+		if e := app.Run(testCase.args); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if globalRequestTimeout != testCase.request || globalConnectTimeout != testCase.connect ||
+			globalResponseHeaderTimeout != testCase.responseHeader || globalRetries != testCase.retries {
+			t.Errorf("Test %d: unexpected timeouts %s %s %s and retries %d", i+1,
+				globalRequestTimeout, globalConnectTimeout, globalResponseHeaderTimeout, globalRetries)
+		}
+	}
+}
+
+func TestApplyHTTPTimeouts(t *testing.T) {
+	defer func() {
+		globalRequestTimeout, globalConnectTimeout, globalResponseHeaderTimeout = notSetOnCommandLine, notSetOnCommandLine, notSetOnCommandLine
+		globalRetries = notSetOnCommandLine
+	}()
+
+	three := 3
+	alias := &aliasConfigV10{RequestTimeout: "1m", ConnectTimeout: "5s", ResponseHeaderTimeout: "30s", Retries: &three}
+	testCases := []struct {
+		alias                            *aliasConfigV10
+		request, connect, responseHeader time.Duration
+		retries                          int
+		expected                         Config
+	}{
+		{
+			nil, notSetOnCommandLine, notSetOnCommandLine, notSetOnCommandLine, notSetOnCommandLine,
+			Config{},
+		},
+		{
+			alias, notSetOnCommandLine, notSetOnCommandLine, notSetOnCommandLine, notSetOnCommandLine,
+			Config{RequestTimeout: time.Minute, ConnectTimeout: 5 * time.Second, ResponseHeaderTimeout: 30 * time.Second, MaxAttempts: 4},
+		},
+		// The command line overrides the alias, also with 0.
+		{
+			alias, 0, time.Second, 0, 0,
+			Config{RequestTimeout: 0, ConnectTimeout: time.Second, ResponseHeaderTimeout: 0, MaxAttempts: 1},
+		},
+		{
+			nil, 2 * time.Minute, notSetOnCommandLine, notSetOnCommandLine, 5,
+			Config{RequestTimeout: 2 * time.Minute, MaxAttempts: 6},
+		},
+	}
+	for i, testCase := range testCases {
+		globalRequestTimeout, globalConnectTimeout, globalResponseHeaderTimeout = testCase.request, testCase.connect, testCase.responseHeader
+		globalRetries = testCase.retries
+		var config Config
+		applyHTTPTimeouts(&config, testCase.alias)
+		if config.RequestTimeout != testCase.expected.RequestTimeout || config.ConnectTimeout != testCase.expected.ConnectTimeout ||
+			config.ResponseHeaderTimeout != testCase.expected.ResponseHeaderTimeout || config.MaxAttempts != testCase.expected.MaxAttempts {
+			t.Errorf("Test %d: expected %s %s %s %d, got %s %s %s %d", i+1,
+				testCase.expected.RequestTimeout, testCase.expected.ConnectTimeout, testCase.expected.ResponseHeaderTimeout, testCase.expected.MaxAttempts,
+				config.RequestTimeout, config.ConnectTimeout, config.ResponseHeaderTimeout, config.MaxAttempts)
+		}
+
+		// The command line is also what alias set stores.
+		var stored aliasConfigV10
+		setAliasTimeouts(&stored)
+		if (testCase.request >= 0) != (stored.RequestTimeout != "") || (testCase.retries >= 0) != (stored.Retries != nil) {
+			t.Errorf("Test %d: unexpected stored alias %+v", i+1, stored)
+		}
+	}
+}
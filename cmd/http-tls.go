@@ -0,0 +1,125 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+// aliasTLSFlags are the mTLS and trust settings of 'mc alias set'.
+var aliasTLSFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "client-cert",
+		Usage: "PEM certificate presented to the server for mTLS, used with --client-key",
+	},
+	cli.StringFlag{
+		Name:  "client-key",
+		Usage: "PEM private key of --client-cert",
+	},
+	cli.StringFlag{
+		Name:  "ca-bundle",
+		Usage: "PEM bundle of the CAs trusted for the alias, instead of the system and certs dir CAs",
+	},
+}
+
+// setAliasTLS stores the absolute paths of the mTLS and trust files given
+// on the command line in the alias config.
+func setAliasTLS(ctx *cli.Context, aliasCfg *aliasConfigV10) *probe.Error {
+	abs := func(name string) (string, *probe.Error) {
+		value := ctx.String(name)
+		if value == "" {
+			return "", nil
+		}
+		p, e := filepath.Abs(value)
+		if e != nil {
+			return "", probe.NewError(e).Trace(value)
+		}
+		return p, nil
+	}
+	var err *probe.Error
+	if aliasCfg.ClientCert, err = abs("client-cert"); err != nil {
+		return err
+	}
+	if aliasCfg.ClientKey, err = abs("client-key"); err != nil {
+		return err
+	}
+	if aliasCfg.CABundle, err = abs("ca-bundle"); err != nil {
+		return err
+	}
+	if (aliasCfg.ClientCert == "") != (aliasCfg.ClientKey == "") {
+		return probe.NewError(errors.New("--client-cert and --client-key must be given together"))
+	}
+	return nil
+}
+
+// keepAliasTLS keeps the mTLS and trust files of the previous definition
+// of an alias when they are not given.
+func keepAliasTLS(aliasCfg *aliasConfigV10, prev aliasConfigV10) {
+	if aliasCfg.ClientCert == "" {
+		aliasCfg.ClientCert, aliasCfg.ClientKey = prev.ClientCert, prev.ClientKey
+	}
+	if aliasCfg.CABundle == "" {
+		aliasCfg.CABundle = prev.CABundle
+	}
+}
+
+// loadAliasTLS loads the client certificate and the CA bundle of the alias in config.
+func loadAliasTLS(config *Config, aliasCfg *aliasConfigV10) *probe.Error {
+	if aliasCfg.ClientCert != "" {
+		cert, e := tls.LoadX509KeyPair(aliasCfg.ClientCert, aliasCfg.ClientKey)
+		if e != nil {
+			return probe.NewError(e).Trace(aliasCfg.ClientCert, aliasCfg.ClientKey)
+		}
+		config.ClientCert = &cert
+	}
+	if aliasCfg.CABundle != "" {
+		pem, e := os.ReadFile(aliasCfg.CABundle)
+		if e != nil {
+			return probe.NewError(e).Trace(aliasCfg.CABundle)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return probe.NewError(fmt.Errorf("no PEM certificate found in %s", aliasCfg.CABundle))
+		}
+		config.RootCAs = pool
+	}
+	return nil
+}
+
+// tlsConfig returns tlsConfig with the client certificate and the trusted
+// CAs of config.
+func (config *Config) tlsConfig(tlsConfig *tls.Config) *tls.Config {
+	if tlsConfig == nil || (config.ClientCert == nil && config.RootCAs == nil) {
+		return tlsConfig
+	}
+	tlsConfig = tlsConfig.Clone()
+	if config.ClientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*config.ClientCert}
+	}
+	if config.RootCAs != nil {
+		tlsConfig.RootCAs = config.RootCAs
+	}
+	return tlsConfig
+}
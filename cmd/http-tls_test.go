@@ -0,0 +1,195 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+// writeTestCertificate writes a self-signed certificate and its key in dir.
+func writeTestCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mc"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, e := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	keyDER, e := x509.MarshalECPrivateKey(key)
+	if e != nil {
+		t.Fatal(e)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	if e = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	if e = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	return certFile, keyFile
+}
+
+func TestSetAliasTLS(t *testing.T) {
+	newContext := func(args ...string) *cli.Context {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		for _, f := range aliasTLSFlags {
+			f.Apply(set)
+		}
+		if e := set.Parse(args); e != nil {
+			t.Fatal(e)
+		}
+		return cli.NewContext(nil, set, nil)
+	}
+	wd, e := os.Getwd()
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected aliasConfigV10
+		success  bool
+	}{
+		{nil, aliasConfigV10{}, true},
+		// Relative paths are stored absolute.
+		{
+			[]string{"--client-cert", "client.crt", "--client-key", "/etc/mc/client.key", "--ca-bundle", "certs/ca.pem"},
+			aliasConfigV10{ClientCert: filepath.Join(wd, "client.crt"), ClientKey: "/etc/mc/client.key", CABundle: filepath.Join(wd, "certs/ca.pem")},
+			true,
+		},
+		{[]string{"--client-cert", "client.crt"}, aliasConfigV10{}, false},
+		{[]string{"--client-key", "client.key"}, aliasConfigV10{}, false},
+	}
+	for i, testCase := range testCases {
+		var aliasCfg aliasConfigV10
+		err := setAliasTLS(newContext(testCase.args...), &aliasCfg)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, err)
+		}
+		if testCase.success && !reflect.DeepEqual(aliasCfg, testCase.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, aliasCfg)
+		}
+	}
+}
+
+func TestKeepAliasTLS(t *testing.T) {
+	prev := aliasConfigV10{ClientCert: "/old.crt", ClientKey: "/old.key", CABundle: "/old-ca.pem"}
+	testCases := []struct {
+		aliasCfg aliasConfigV10
+		expected aliasConfigV10
+	}{
+		{aliasConfigV10{}, prev},
+		{
+			aliasConfigV10{ClientCert: "/new.crt", ClientKey: "/new.key"},
+			aliasConfigV10{ClientCert: "/new.crt", ClientKey: "/new.key", CABundle: "/old-ca.pem"},
+		},
+		{
+			aliasConfigV10{CABundle: "/new-ca.pem"},
+			aliasConfigV10{ClientCert: "/old.crt", ClientKey: "/old.key", CABundle: "/new-ca.pem"},
+		},
+	}
+	for i, testCase := range testCases {
+		aliasCfg := testCase.aliasCfg
+		keepAliasTLS(&aliasCfg, prev)
+		if !reflect.DeepEqual(aliasCfg, testCase.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, aliasCfg)
+		}
+	}
+}
+
+func TestLoadAliasTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
+	badPEM := filepath.Join(dir, "bad.pem")
+	if e := os.WriteFile(badPEM, []byte("not a certificate"), 0o600); e != nil {
+		t.Fatal(e)
+	}
+
+	testCases := []struct {
+		aliasCfg   aliasConfigV10
+		clientCert bool
+		rootCAs    bool
+		success    bool
+	}{
+		{aliasConfigV10{}, false, false, true},
+		{aliasConfigV10{ClientCert: certFile, ClientKey: keyFile, CABundle: certFile}, true, true, true},
+		{aliasConfigV10{CABundle: badPEM}, false, false, false},
+		{aliasConfigV10{CABundle: filepath.Join(dir, "missing.pem")}, false, false, false},
+		{aliasConfigV10{ClientCert: certFile, ClientKey: badPEM}, false, false, false},
+		// A certificate file without certificate.
+		{aliasConfigV10{ClientCert: badPEM, ClientKey: keyFile}, false, false, false},
+	}
+	for i, testCase := range testCases {
+		config := new(Config)
+		err := loadAliasTLS(config, &testCase.aliasCfg)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, err)
+		}
+		if (config.ClientCert != nil) != testCase.clientCert || (config.RootCAs != nil) != testCase.rootCAs {
+			t.Errorf("Test %d: expected client cert %t and root CAs %t, got %+v", i+1, testCase.clientCert, testCase.rootCAs, config)
+		}
+	}
+}
+
+func TestConfigTLSConfig(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+	cert, e := tls.LoadX509KeyPair(certFile, keyFile)
+	if e != nil {
+		t.Fatal(e)
+	}
+	rootCAs := x509.NewCertPool()
+	base := &tls.Config{RootCAs: x509.NewCertPool(), InsecureSkipVerify: true}
+
+	if got := (&Config{ClientCert: &cert}).tlsConfig(nil); got != nil {
+		t.Errorf("expected no TLS config, got %+v", got)
+	}
+	if got := (&Config{}).tlsConfig(base); got != base {
+		t.Error("expected the TLS config to be unchanged")
+	}
+
+	got := (&Config{ClientCert: &cert, RootCAs: rootCAs}).tlsConfig(base)
+	if got == base || len(got.Certificates) != 1 || got.RootCAs != rootCAs || !got.InsecureSkipVerify {
+		t.Errorf("expected a copy with the client certificate and the root CAs, got %+v", got)
+	}
+	if len(base.Certificates) != 0 || base.RootCAs == rootCAs {
+		t.Error("expected the TLS config not to be modified")
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+const (
+	// Size at which the trace file is rotated.
+	traceFileMaxSize = 64 << 20
+	// Number of rotated trace files kept next to the trace file.
+	traceFileBackups = 4
+)
+
+var (
+	authCredentialRegex = regexp.MustCompile("Credential=([^/]+)/")
+	authSignatureRegex  = regexp.MustCompile("Signature=([0-9a-f]+)")
+	authScopeRegex      = regexp.MustCompile("Credential=[^/]*/[^/]*/([^/]*)/([^/]*)/aws4_request")
+)
+
+// Query parameters of presigned requests carrying credentials.
+var redactedQueryParams = []string{"X-Amz-Credential", "X-Amz-Signature", "X-Amz-Security-Token"}
+
+// Headers carrying credentials or encryption keys, their values are
+// replaced in the traces and the curl commands.
+var redactedHeaders = []string{
+	"X-Amz-Security-Token",
+	"X-Amz-Server-Side-Encryption-Customer-Key",
+	"X-Amz-Server-Side-Encryption-Customer-Key-Md5",
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key",
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key-Md5",
+}
+
+// Headers left out of the generated curl command, curl signs the request itself.
+var curlSkippedHeaders = []string{"Authorization", "X-Amz-Date", "X-Amz-Content-Sha256", "X-Amz-Security-Token", "Content-Length", "Host", "User-Agent"}
+
+var (
+	traceMu         sync.Mutex
+	traceFile       *rotatingFile
+	traceCurl       bool
+	traceLastFailed *http.Request
+)
+
+// rotatingFile is an append only file, it is renamed to PATH.1 (PATH.1 to
+// PATH.2 and so on) once it grows beyond its maximum size.
+type rotatingFile struct {
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+func openRotatingFile(path string, maxSize int64, backups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if e := r.open(); e != nil {
+		return nil, e
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, e := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if e != nil {
+		return e
+	}
+	st, e := f.Stat()
+	if e != nil {
+		f.Close()
+		return e
+	}
+	r.file, r.size = f, st.Size()
+	return nil
+}
+
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+	for i := r.backups - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.backups > 0 {
+		os.Rename(r.path, r.path+".1")
+	} else {
+		os.Remove(r.path)
+	}
+	return r.open()
+}
+
+// Write appends p to the file, rotating the file first when p doesn't fit.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if e := r.rotate(); e != nil {
+			return 0, e
+		}
+	}
+	n, e := r.file.Write(p)
+	r.size += int64(n)
+	return n, e
+}
+
+// enableHTTPTrace sets up --trace-file and --curl, the HTTP transports of
+// mc are wrapped with wrapHTTPTrace.
+func enableHTTPTrace(ctx *cli.Context) {
+	path := ctx.String("trace-file")
+	if path == "" {
+		path = ctx.GlobalString("trace-file")
+	}
+	curl := ctx.IsSet("curl") || ctx.GlobalIsSet("curl")
+
+	traceMu.Lock()
+	defer traceMu.Unlock()
+
+	if path != "" && traceFile == nil {
+		f, e := openRotatingFile(path, traceFileMaxSize, traceFileBackups)
+		if e != nil {
+			console.Fatalln(fmt.Sprintf("Unable to open the trace file %s: %v", path, e))
+		}
+		traceFile = f
+	}
+	if curl && !traceCurl {
+		traceCurl = true
+		onExit(printFailedRequestCurl)
+	}
+}
+
+// wrapHTTPTrace returns transport recording its requests when --trace-file
+// or --curl are set.
+func wrapHTTPTrace(transport http.RoundTripper) http.RoundTripper {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	if traceFile == nil && !traceCurl {
+		return transport
+	}
+	return httpTraceTransport{transport: transport}
+}
+
+// httpTraceTransport writes the requests and responses to the trace file
+// and remembers the last failed request.
+type httpTraceTransport struct {
+	transport http.RoundTripper
+}
+
+func (t httpTraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	duration := time.Since(start)
+
+	failed := err != nil || resp.StatusCode >= http.StatusBadRequest
+
+	traceMu.Lock()
+	defer traceMu.Unlock()
+
+	if failed && traceCurl {
+		traceLastFailed = req
+	}
+	if traceFile == nil {
+		return resp, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "---- %s %s %s\n", start.UTC().Format(time.RFC3339Nano), req.Method, redactURL(req.URL).String())
+	if dump, e := httputil.DumpRequestOut(redactRequest(req), false); e == nil {
+		b.Write(dump)
+	}
+	if err != nil {
+		fmt.Fprintf(&b, "ERROR: %v\n", err)
+	} else {
+		// Only the bodies of errors are traced, objects can be large.
+		dump, e := httputil.DumpResponse(resp, failed)
+		if e == nil {
+			b.Write(dump)
+		}
+	}
+	fmt.Fprintf(&b, "\nResponse Time: %s\n\n", duration)
+	traceFile.Write([]byte(b.String()))
+	return resp, err
+}
+
+// redactAuthorization removes the access key and the signature from an
+// Authorization header, other than SigV4 headers are redacted entirely.
+func redactAuthorization(auth string) string {
+	if !strings.HasPrefix(auth, sigV4Algorithm+" ") {
+		return "**REDACTED**"
+	}
+	auth = authCredentialRegex.ReplaceAllString(auth, "Credential=**REDACTED**/")
+	return authSignatureRegex.ReplaceAllString(auth, "Signature=**REDACTED**")
+}
+
+// redactURL returns u without the credentials of presigned requests.
+func redactURL(u *url.URL) *url.URL {
+	redacted := *u
+	query := u.Query()
+	for _, param := range redactedQueryParams {
+		if query.Get(param) != "" {
+			query.Set(param, "**REDACTED**")
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return &redacted
+}
+
+// redactRequest returns a copy of the request headers and URL without
+// credentials, fit for a trace.
+func redactRequest(req *http.Request) *http.Request {
+	redacted := req.Clone(req.Context())
+	redacted.Body = nil
+	redacted.URL = redactURL(req.URL)
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		redacted.Header.Set("Authorization", redactAuthorization(auth))
+	}
+	for _, name := range redactedHeaders {
+		if req.Header.Get(name) != "" {
+			redacted.Header.Set(name, "**REDACTED**")
+		}
+	}
+	return redacted
+}
+
+// curlCommand returns a curl command sending req again, signed by curl
+// with the credentials of the MC_ACCESS_KEY and MC_SECRET_KEY variables.
+func curlCommand(req *http.Request) string {
+	args := []string{"curl", "-X", req.Method}
+
+	region, service := "us-east-1", "s3"
+	if m := authScopeRegex.FindStringSubmatch(req.Header.Get("Authorization")); m != nil {
+		region, service = m[1], m[2]
+	} else if m := authScopeRegex.FindStringSubmatch("Credential=" + req.URL.Query().Get("X-Amz-Credential")); m != nil {
+		region, service = m[1], m[2]
+	}
+	args = append(args, "--aws-sigv4", shellQuote("aws:amz:"+region+":"+service),
+		"--user", "\"$MC_ACCESS_KEY:$MC_SECRET_KEY\"")
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		skip := false
+		for _, skipped := range curlSkippedHeaders {
+			if strings.EqualFold(name, skipped) {
+				skip = true
+			}
+		}
+		if !skip {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range redactRequest(req).Header[name] {
+			args = append(args, "-H", shellQuote(name+": "+value))
+		}
+	}
+	if req.ContentLength > 0 {
+		args = append(args, "--data-binary", "@BODY")
+	}
+
+	u := *req.URL
+	query := u.Query()
+	for name := range query {
+		if strings.HasPrefix(strings.ToLower(name), "x-amz-") {
+			query.Del(name)
+		}
+	}
+	u.RawQuery = query.Encode()
+	args = append(args, shellQuote(u.String()))
+	return strings.Join(args, " ")
+}
+
+// printFailedRequestCurl prints a curl command reproducing the last failed
+// request when mc exits with an error.
+func printFailedRequestCurl(exitStatus int) {
+	traceMu.Lock()
+	req := traceLastFailed
+	traceMu.Unlock()
+	if exitStatus == exitStatusSuccess || req == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Reproduce the last failed request with:")
+	fmt.Fprintln(os.Stderr, curlCommand(req))
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Values of the credentials and encryption keys of the traced requests.
+var testTraceSecrets = []string{"AKIAEXAMPLE", "0123abcd", "abcdef", "sessiontoken", "customerkey", "customermd5", "sourcekey", "sourcemd5"}
+
+func TestHTTPTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "trace.log")
+	f, e := openRotatingFile(path, 1<<10, 2)
+	if e != nil {
+		t.Fatal(e)
+	}
+	traceFile, traceCurl = f, true
+	defer func() {
+		traceFile, traceCurl, traceLastFailed = nil, false, nil
+	}()
+
+	client := http.Client{Transport: wrapHTTPTrace(http.DefaultTransport)}
+	for i := 0; i < 4; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/bucket/object?versionId=1&X-Amz-Signature=abcdef&X-Amz-Credential=AKIAEXAMPLE/20230101/eu-west-1/s3/aws4_request", nil)
+		req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=AKIAEXAMPLE/20230101/eu-west-1/s3/aws4_request, SignedHeaders=host, Signature=0123abcd")
+		req.Header.Set("X-Amz-Security-Token", "sessiontoken")
+		req.Header.Set("X-Amz-Server-Side-Encryption-Customer-Key", "customerkey")
+		req.Header.Set("X-Amz-Server-Side-Encryption-Customer-Key-MD5", "customermd5")
+		req.Header.Set("X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key", "sourcekey")
+		req.Header.Set("X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key-MD5", "sourcemd5")
+		req.Header.Set("Range", "bytes=0-10")
+		resp, e := client.Do(req)
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp.Body.Close()
+	}
+
+	trace, e := os.ReadFile(path)
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, secret := range testTraceSecrets {
+		if strings.Contains(string(trace), secret) {
+			t.Errorf("trace contains %q:\n%s", secret, trace)
+		}
+	}
+	if !strings.Contains(string(trace), "AccessDenied") {
+		t.Errorf("trace misses the error response:\n%s", trace)
+	}
+	if _, e = os.Stat(path + ".1"); e != nil {
+		t.Errorf("trace file not rotated: %v", e)
+	}
+	if _, e = os.Stat(path + ".3"); e == nil {
+		t.Errorf("too many rotated trace files")
+	}
+
+	if traceLastFailed == nil {
+		t.Fatal("failed request not recorded")
+	}
+	curl := curlCommand(traceLastFailed)
+	for _, secret := range testTraceSecrets {
+		if strings.Contains(curl, secret) {
+			t.Errorf("curl command contains %q: %s", secret, curl)
+		}
+	}
+	for _, want := range []string{"-X GET", "aws:amz:eu-west-1:s3", "Range:\\ bytes=0-10", "versionId=1"} {
+		if !strings.Contains(curl, want) {
+			t.Errorf("curl command misses %q: %s", want, curl)
+		}
+	}
+}
+
+func TestRedactAuthorization(t *testing.T) {
+	testCases := []struct {
+		auth     string
+		expected string
+	}{
+		{
+			"AWS4-HMAC-SHA256 Credential=AKIAEXAMPLE/20230101/eu-west-1/s3/aws4_request, SignedHeaders=host, Signature=0123abcd",
+			"AWS4-HMAC-SHA256 Credential=**REDACTED**/20230101/eu-west-1/s3/aws4_request, SignedHeaders=host, Signature=**REDACTED**",
+		},
+		// S3v2 signatures carry the access key and signature in the clear.
+		{"AWS AKIAEXAMPLE:frJIUN8DYpKDtOLCwo//yllqDzg=", "**REDACTED**"},
+		{"Bearer sessiontoken", "**REDACTED**"},
+	}
+	for i, testCase := range testCases {
+		if redacted := redactAuthorization(testCase.auth); redacted != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, redacted)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+const (
+	// TCP keep-alive period used when neither the alias nor the command line set it.
+	defaultTCPKeepAlive = 15 * time.Second
+	// Number of connections a transfer uses, the parts of multipart uploads
+	// are uploaded in parallel.
+	connsPerTransfer = 4
+)
+
+// parallelFlag sets the number of concurrent transfers of cp and mirror.
+var parallelFlag = cli.IntFlag{
+	Name:  "parallel, P",
+	Usage: "number of objects transferred concurrently, also scales the connection pool (default: number of CPUs)",
+}
+
+var (
+	globalMaxIdleConnsPerHost int
+	globalDisableHTTP2        *bool
+	globalTCPKeepAlive        time.Duration
+	globalParallel            int
+)
+
+// setHTTPTransportFromContext sets the transport settings given on the command line.
+func setHTTPTransportFromContext(ctx *cli.Context) {
+	switch {
+	case ctx.IsSet("max-idle-conns-per-host"):
+		globalMaxIdleConnsPerHost = ctx.Int("max-idle-conns-per-host")
+	case ctx.GlobalIsSet("max-idle-conns-per-host"):
+		globalMaxIdleConnsPerHost = ctx.GlobalInt("max-idle-conns-per-host")
+	}
+	if globalMaxIdleConnsPerHost < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("max-idle-conns-per-host")), "The number of idle connections cannot be negative.")
+	}
+
+	switch {
+	case ctx.IsSet("disable-http2"):
+		disable := ctx.Bool("disable-http2")
+		globalDisableHTTP2 = &disable
+	case ctx.GlobalIsSet("disable-http2"):
+		disable := ctx.GlobalBool("disable-http2")
+		globalDisableHTTP2 = &disable
+	}
+
+	switch {
+	case ctx.IsSet("tcp-keepalive"):
+		globalTCPKeepAlive = ctx.Duration("tcp-keepalive")
+	case ctx.GlobalIsSet("tcp-keepalive"):
+		globalTCPKeepAlive = ctx.GlobalDuration("tcp-keepalive")
+	}
+
+	if parallel := ctx.Int("parallel"); parallel > 0 {
+		globalParallel = parallel
+	}
+}
+
+// setAliasTransport stores the transport settings given on the command
+// line in the alias config.
+func setAliasTransport(aliasCfg *aliasConfigV10) {
+	if globalMaxIdleConnsPerHost > 0 {
+		aliasCfg.MaxIdleConnsPerHost = globalMaxIdleConnsPerHost
+	}
+	if globalDisableHTTP2 != nil {
+		disable := *globalDisableHTTP2
+		aliasCfg.DisableHTTP2 = &disable
+	}
+	if globalTCPKeepAlive != 0 {
+		aliasCfg.TCPKeepAlive = globalTCPKeepAlive.String()
+	}
+}
+
+// applyHTTPTransport sets the transport settings of the alias in config,
+// the command line takes precedence over the alias.
+func applyHTTPTransport(config *Config, aliasCfg *aliasConfigV10) {
+	if aliasCfg != nil {
+		config.Proxy = aliasCfg.Proxy
+		config.NoProxy = aliasCfg.NoProxy
+		config.MaxIdleConnsPerHost = aliasCfg.MaxIdleConnsPerHost
+		if aliasCfg.DisableHTTP2 != nil {
+			config.DisableHTTP2 = *aliasCfg.DisableHTTP2
+		}
+		if aliasCfg.TCPKeepAlive != "" {
+			d, e := time.ParseDuration(aliasCfg.TCPKeepAlive)
+			fatalIf(probe.NewError(e).Trace(aliasCfg.TCPKeepAlive), "Unable to parse the TCP keep-alive period of the alias.")
+			config.TCPKeepAlive = d
+		}
+	}
+	if globalMaxIdleConnsPerHost > 0 {
+		config.MaxIdleConnsPerHost = globalMaxIdleConnsPerHost
+	}
+	if globalDisableHTTP2 != nil {
+		config.DisableHTTP2 = *globalDisableHTTP2
+	}
+	if globalTCPKeepAlive != 0 {
+		config.TCPKeepAlive = globalTCPKeepAlive
+	}
+}
+
+// tcpKeepAlive returns the TCP keep-alive period of config, negative
+// when keep-alives are disabled.
+func (config *Config) tcpKeepAlive() time.Duration {
+	if config.TCPKeepAlive != 0 {
+		return config.TCPKeepAlive
+	}
+	return defaultTCPKeepAlive
+}
+
+// maxIdleConnsPerHost returns the size of the connection pool of config.
+// Unless set explicitly, the pool grows with --parallel so concurrent
+// transfers don't wait for new connections.
+func (config *Config) maxIdleConnsPerHost(defaultConns int) int {
+	if config.MaxIdleConnsPerHost > 0 {
+		return config.MaxIdleConnsPerHost
+	}
+	if conns := globalParallel * connsPerTransfer; conns > defaultConns {
+		return conns
+	}
+	return defaultConns
+}
+
+// tuneTransport applies the proxy, the TLS, the pool and the HTTP/2 settings of config to tr.
+func tuneTransport(tr *http.Transport, config *Config, defaultConns int) {
+	tr.Proxy = config.proxyFunc(tr.Proxy)
+	tr.TLSClientConfig = config.tlsConfig(tr.TLSClientConfig)
+	tr.MaxIdleConnsPerHost = config.maxIdleConnsPerHost(defaultConns)
+	if config.DisableHTTP2 {
+		// A non-nil empty map disables HTTP/2, also when the
+		// server or a proxy offers it.
+		tr.ForceAttemptHTTP2 = false
+		tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestApplyHTTPTransport(t *testing.T) {
+	defer func(conns int, disable *bool, keepAlive time.Duration) {
+		globalMaxIdleConnsPerHost, globalDisableHTTP2, globalTCPKeepAlive = conns, disable, keepAlive
+	}(globalMaxIdleConnsPerHost, globalDisableHTTP2, globalTCPKeepAlive)
+
+	enabled, disabled := false, true
+	testCases := []struct {
+		aliasCfg  *aliasConfigV10
+		conns     int
+		disable   *bool
+		keepAlive time.Duration
+		expected  Config
+	}{
+		{nil, 0, nil, 0, Config{}},
+		{
+			&aliasConfigV10{MaxIdleConnsPerHost: 64, DisableHTTP2: &disabled, TCPKeepAlive: "30s"},
+			0, nil, 0,
+			Config{MaxIdleConnsPerHost: 64, DisableHTTP2: true, TCPKeepAlive: 30 * time.Second},
+		},
+		// The command line takes precedence over the alias.
+		{
+			&aliasConfigV10{MaxIdleConnsPerHost: 64, DisableHTTP2: &disabled, TCPKeepAlive: "30s"},
+			128, &enabled, -1,
+			Config{MaxIdleConnsPerHost: 128, DisableHTTP2: false, TCPKeepAlive: -1},
+		},
+		{nil, 0, &disabled, 0, Config{DisableHTTP2: true}},
+	}
+	for i, testCase := range testCases {
+		globalMaxIdleConnsPerHost, globalDisableHTTP2, globalTCPKeepAlive = testCase.conns, testCase.disable, testCase.keepAlive
+		config := new(Config)
+		applyHTTPTransport(config, testCase.aliasCfg)
+		if config.MaxIdleConnsPerHost != testCase.expected.MaxIdleConnsPerHost ||
+			config.DisableHTTP2 != testCase.expected.DisableHTTP2 ||
+			config.TCPKeepAlive != testCase.expected.TCPKeepAlive {
+			t.Errorf("Test %d: expected %d %t %s, got %d %t %s", i+1,
+				testCase.expected.MaxIdleConnsPerHost, testCase.expected.DisableHTTP2, testCase.expected.TCPKeepAlive,
+				config.MaxIdleConnsPerHost, config.DisableHTTP2, config.TCPKeepAlive)
+		}
+	}
+}
+
+func TestMaxIdleConnsPerHost(t *testing.T) {
+	defer func(parallel int) { globalParallel = parallel }(globalParallel)
+
+	testCases := []struct {
+		conns    int
+		parallel int
+		expected int
+	}{
+		{0, 0, 16},
+		{0, 2, 16},
+		// The pool grows with --parallel.
+		{0, 8, 8 * connsPerTransfer},
+		{64, 8, 64},
+		{4, 0, 4},
+	}
+	for i, testCase := range testCases {
+		globalParallel = testCase.parallel
+		config := &Config{MaxIdleConnsPerHost: testCase.conns}
+		if conns := config.maxIdleConnsPerHost(16); conns != testCase.expected {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.expected, conns)
+		}
+	}
+}
+
+func TestTuneTransport(t *testing.T) {
+	testCases := []struct {
+		disableHTTP2 bool
+	}{
+		{false},
+		{true},
+	}
+	for i, testCase := range testCases {
+		tr := &http.Transport{ForceAttemptHTTP2: true}
+		tuneTransport(tr, &Config{DisableHTTP2: testCase.disableHTTP2, MaxIdleConnsPerHost: 32}, 16)
+		if tr.MaxIdleConnsPerHost != 32 {
+			t.Errorf("Test %d: expected 32 idle connections, got %d", i+1, tr.MaxIdleConnsPerHost)
+		}
+		// A non-nil TLSNextProto disables HTTP/2.
+		if http2 := tr.ForceAttemptHTTP2 && tr.TLSNextProto == nil; http2 == testCase.disableHTTP2 {
+			t.Errorf("Test %d: expected HTTP/2 disabled %t, got %+v", i+1, testCase.disableHTTP2, tr)
+		}
+	}
+	if (&Config{}).tcpKeepAlive() != defaultTCPKeepAlive {
+		t.Errorf("expected the default TCP keep-alive period")
+	}
+}
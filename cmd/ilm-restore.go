@@ -21,11 +21,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
 	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
 )
 
 // ilm restore specific flags.
@@ -48,6 +50,15 @@ var (
 			Name:  "version-id, vid",
 			Usage: "select a specific version id",
 		},
+		cli.StringFlag{
+			Name:  "tier-priority",
+			Value: string(minio.TierExpedited),
+			Usage: "retrieval priority of the restore where supported: 'Expedited', 'Standard' or 'Bulk'",
+		},
+		cli.BoolTFlag{
+			Name:  "follow",
+			Usage: "wait until the restores complete, use --follow=false to return once the requests are sent",
+		},
 	}
 )
 
@@ -66,7 +77,11 @@ USAGE:
 
 DESCRIPTION:
   Restore a copy of one or more objects from its remote tier. This copy automatically expires
-  after the specified number of days (Default 1 day).
+  after the specified number of days (Default 1 day). Objects which are not transitioned
+  to a remote tier are skipped when restoring recursively.
+
+  By default the command waits until the restores complete, checking the restore status of
+  the objects every 5 seconds.
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -87,6 +102,10 @@ EXAMPLES:
 
   5. Restore an SSE-C encrypted object.
      {{.Prompt}} {{.HelpName}} --encrypt-key "myminio/mybucket/=MzJieXRlc2xvbmdzZWNyZWFiY2RlZmcJZ2l2ZW5uMjE=" myminio/mybucket/myobject.txt
+
+  6. Restore all objects under a prefix for 3 days with the Bulk retrieval priority, without waiting
+     for the restores to complete.
+     {{.Prompt}} {{.HelpName}} --recursive --days 3 --tier-priority Bulk --follow=false s3/mybucket/dir/
 `,
 }
 
@@ -103,20 +122,35 @@ func checkILMRestoreSyntax(ctx *cli.Context) {
 	if ctx.Bool("version-id") && (ctx.Bool("recursive") || ctx.Bool("versions")) {
 		fatalIf(errDummy().Trace(), "You cannot combine --version-id with --recursive or --versions flags.")
 	}
+
+	if _, err := parseTierPriority(ctx.String("tier-priority")); err != nil {
+		fatalIf(err.Trace(ctx.String("tier-priority")), "Invalid --tier-priority flag.")
+	}
+}
+
+// parseTierPriority returns the retrieval tier of a restore request,
+// case insensitive.
+func parseTierPriority(priority string) (minio.TierType, *probe.Error) {
+	for _, tier := range []minio.TierType{minio.TierExpedited, minio.TierStandard, minio.TierBulk} {
+		if strings.EqualFold(priority, string(tier)) {
+			return tier, nil
+		}
+	}
+	return "", probe.NewError(fmt.Errorf("unknown tier priority `%s`, expected 'Expedited', 'Standard' or 'Bulk'", priority))
 }
 
 // Send Restore S3 API
-func restoreObject(ctx context.Context, targetAlias, targetURL, versionID string, days int) *probe.Error {
+func restoreObject(ctx context.Context, targetAlias, targetURL, versionID string, days int, tier minio.TierType) *probe.Error {
 	clnt, err := newClientFromAlias(targetAlias, targetURL)
 	if err != nil {
 		return err
 	}
 
-	return clnt.Restore(ctx, versionID, days)
+	return clnt.Restore(ctx, versionID, days, tier)
 }
 
 // Send restore S3 API request to one or more objects depending on the arguments
-func sendRestoreRequests(ctx context.Context, targetAlias, targetURL, targetVersionID string, recursive, applyOnVersions bool, days int, restoreSentReq chan *probe.Error) {
+func sendRestoreRequests(ctx context.Context, targetAlias, targetURL, targetVersionID string, recursive, applyOnVersions bool, days int, tier minio.TierType, restoreSentReq chan *probe.Error) {
 	defer close(restoreSentReq)
 
 	client, err := newClientFromAlias(targetAlias, targetURL)
@@ -126,7 +160,7 @@ func sendRestoreRequests(ctx context.Context, targetAlias, targetURL, targetVers
 	}
 
 	if !recursive {
-		err := restoreObject(ctx, targetAlias, targetURL, targetVersionID, days)
+		err := restoreObject(ctx, targetAlias, targetURL, targetVersionID, days, tier)
 		restoreSentReq <- err
 		return
 	}
@@ -141,7 +175,10 @@ func sendRestoreRequests(ctx context.Context, targetAlias, targetURL, targetVers
 			errorIf(content.Err.Trace(client.GetURL().String()), "Unable to list folder.")
 			continue
 		}
-		err := restoreObject(ctx, targetAlias, content.URL.String(), content.VersionID, days)
+		if !isTransitioned(content) {
+			continue
+		}
+		err := restoreObject(ctx, targetAlias, content.URL.String(), content.VersionID, days, tier)
 		if err != nil {
 			restoreSentReq <- err
 			continue
@@ -155,6 +192,12 @@ func sendRestoreRequests(ctx context.Context, targetAlias, targetURL, targetVers
 	}
 }
 
+// isTransitioned returns true if a listed object is in a remote tier and can
+// be restored.
+func isTransitioned(content *ClientContent) bool {
+	return !content.IsDeleteMarker && objectTier(content.StorageClass) != ""
+}
+
 // Wait until an object which receives restore request is completely restored in the fast tier
 func waitRestoreObject(ctx context.Context, targetAlias, targetURL, versionID string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
 	clnt, err := newClientFromAlias(targetAlias, targetURL)
@@ -207,6 +250,9 @@ func checkRestoreStatus(ctx context.Context, targetAlias, targetURL, targetVersi
 			restoreStatus <- content.Err
 			continue
 		}
+		if !isTransitioned(content) {
+			continue
+		}
 
 		err := waitRestoreObject(ctx, targetAlias, content.URL.String(), content.VersionID, encKeyDB)
 		if err != nil {
@@ -236,7 +282,7 @@ func printStatus(msg string, args ...interface{}) {
 }
 
 // Receive restore request & restore finished status and print in the console
-func showRestoreStatus(restoreReqStatus, restoreFinishedStatus chan *probe.Error, doneCh chan struct{}) {
+func showRestoreStatus(restoreReqStatus, restoreFinishedStatus chan *probe.Error, follow bool, doneCh chan struct{}) {
 	var sent, finished int
 	var done bool
 
@@ -264,7 +310,7 @@ func showRestoreStatus(restoreReqStatus, restoreFinishedStatus chan *probe.Error
 		fmt.Println("")
 	}
 
-	done = false
+	done = !follow
 
 	for !done {
 		select {
@@ -282,16 +328,20 @@ func showRestoreStatus(restoreReqStatus, restoreFinishedStatus chan *probe.Error
 		printStatus("%d/%d object(s) successfully restored", finished, sent)
 	}
 
-	if !globalJSON {
-		fmt.Println("")
-	} else {
+	// The counters are printed once done in JSON mode, the text mode
+	// printed them as they changed.
+	switch {
+	case globalJSON:
 		type ilmRestore struct {
 			Status   string `json:"status"`
+			Sent     int    `json:"sent"`
 			Restored int    `json:"restored"`
 		}
 
-		msgBytes, _ := json.Marshal(ilmRestore{Status: "success", Restored: sent})
+		msgBytes, _ := json.Marshal(ilmRestore{Status: "success", Sent: sent, Restored: finished})
 		fmt.Println(string(msgBytes))
+	case follow:
+		fmt.Println("")
 	}
 
 	close(doneCh)
@@ -310,6 +360,8 @@ func mainILMRestore(cliCtx *cli.Context) (cErr error) {
 	recursive := cliCtx.Bool("recursive")
 	includeVersions := cliCtx.Bool("versions")
 	days := cliCtx.Int("days")
+	follow := cliCtx.BoolT("follow")
+	tier, _ := parseTierPriority(cliCtx.String("tier-priority"))
 
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
@@ -325,11 +377,13 @@ func mainILMRestore(cliCtx *cli.Context) (cErr error) {
 	done := make(chan struct{})
 
 	go func() {
-		showRestoreStatus(restoreReqStatus, restoreStatus, done)
+		showRestoreStatus(restoreReqStatus, restoreStatus, follow, done)
 	}()
 
-	sendRestoreRequests(ctx, targetAlias, targetURL, versionID, recursive, includeVersions, days, restoreReqStatus)
-	checkRestoreStatus(ctx, targetAlias, targetURL, versionID, recursive, includeVersions, encKeyDB, restoreStatus)
+	sendRestoreRequests(ctx, targetAlias, targetURL, versionID, recursive, includeVersions, days, tier, restoreReqStatus)
+	if follow {
+		checkRestoreStatus(ctx, targetAlias, targetURL, versionID, recursive, includeVersions, encKeyDB, restoreStatus)
+	}
 
 	// Wait until the UI printed all the status
 	<-done
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+func TestParseTierPriority(t *testing.T) {
+	testCases := []struct {
+		priority string
+		tier     minio.TierType
+		invalid  bool
+	}{
+		{"Expedited", minio.TierExpedited, false},
+		{"standard", minio.TierStandard, false},
+		{"BULK", minio.TierBulk, false},
+		{"fast", "", true},
+	}
+	for i, testCase := range testCases {
+		tier, err := parseTierPriority(testCase.priority)
+		if testCase.invalid != (err != nil) {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if tier != testCase.tier {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.tier, tier)
+		}
+	}
+}
+
+func TestShowRestoreStatus(t *testing.T) {
+	defer func(json bool) { globalJSON = json }(globalJSON)
+	stdout := os.Stdout
+	defer func() { os.Stdout = stdout }()
+
+	testCases := []struct {
+		json, follow bool
+		output       string
+	}{
+		{true, true, `{"status":"success","sent":2,"restored":1}` + "\n"},
+		{true, false, `{"status":"success","sent":2,"restored":0}` + "\n"},
+		// The text mode prints the counters as they change, without JSON.
+		{false, true, "1/2 object(s) successfully restored"},
+		{false, false, "Sent restore requests to 2 object(s)"},
+	}
+	for i, testCase := range testCases {
+		f, e := os.Create(filepath.Join(t.TempDir(), "stdout"))
+		if e != nil {
+			t.Fatal(e)
+		}
+		os.Stdout = f
+		globalJSON = testCase.json
+
+		sent, finished := make(chan *probe.Error, 3), make(chan *probe.Error, 2)
+		sent <- nil
+		sent <- nil
+		sent <- probe.NewError(os.ErrPermission)
+		close(sent)
+		if testCase.follow {
+			finished <- nil
+		}
+		close(finished)
+		done := make(chan struct{})
+		showRestoreStatus(sent, finished, testCase.follow, done)
+		<-done
+		f.Close()
+		os.Stdout = stdout
+
+		output, _ := os.ReadFile(f.Name())
+		if testCase.json && string(output) != testCase.output {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.output, output)
+		}
+		if !testCase.json && (!strings.Contains(string(output), testCase.output) || strings.Contains(string(output), `"status"`)) {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.output, output)
+		}
+	}
+}
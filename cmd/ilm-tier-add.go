@@ -90,6 +90,10 @@ var adminTierAddFlags = []cli.Flag{
 		Hidden: true,
 		Usage:  "ignores in-use check for remote tier bucket/prefix",
 	},
+	cli.BoolFlag{
+		Name:  "verify",
+		Usage: "write and read back a probe object in the remote tier before saving it",
+	},
 }
 
 var adminTierAddCmd = cli.Command{
@@ -111,6 +115,11 @@ TYPE:
 NAME:
   Name of the remote tier target. e.g WARM-TIER
 
+VERIFY:
+  With --verify, mc writes, reads back and removes a probe object under the prefix of the remote
+  bucket of minio and s3 tiers before saving them. Azure, GCS and AWS role based tiers are verified
+  by the servers once saved, and removed again if the verification fails.
+
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
@@ -131,6 +140,10 @@ EXAMPLES:
   4. Configure a new remote tier which transitions objects to a bucket in Google Cloud Storage:
      {{.Prompt}} {{.HelpName}} gcs myminio GCSTIER --credentials-file /path/to/credentials.json \
         --bucket mygcsbucket  --prefix mygcsprefix/
+
+  5. Configure a new MinIO remote tier after checking its credentials can write and read in the bucket:
+     {{.Prompt}} {{.HelpName}} minio myminio WARM-MINIO-TIER --endpoint https://warm-minio.com \
+        --access-key ACCESSKEY --secret-key SECRETKEY --bucket mybucket --verify
 `,
 }
 
@@ -375,12 +388,24 @@ func mainAdminTierAdd(ctx *cli.Context) error {
 	fatalIf(cerr, "Unable to initialize admin connection.")
 
 	tCfg := fetchTierConfig(ctx, strings.ToUpper(tierName), tierType)
+	verifySaved := false
+	if ctx.Bool("verify") {
+		probed, err := probeTier(globalContext, tCfg)
+		fatalIf(err.Trace(args...), "Unable to verify remote tier target, it was not saved")
+		verifySaved = !probed
+	}
 	ignoreInUse := ctx.Bool("force")
 	if ignoreInUse {
 		fatalIf(probe.NewError(client.AddTierIgnoreInUse(globalContext, tCfg)).Trace(args...), "Unable to configure remote tier target")
 	} else {
 		fatalIf(probe.NewError(client.AddTier(globalContext, tCfg)).Trace(args...), "Unable to configure remote tier target")
 	}
+	if verifySaved {
+		if e := client.VerifyTier(globalContext, tCfg.Name); e != nil {
+			errorIf(probe.NewError(client.RemoveTier(globalContext, tCfg.Name)).Trace(args...), "Unable to remove remote tier target")
+			fatalIf(probe.NewError(e).Trace(args...), "Unable to verify remote tier target, it was removed")
+		}
+	}
 
 	msg := &tierMessage{
 		op:     ctx.Command.Name,
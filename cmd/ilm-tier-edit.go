@@ -18,7 +18,9 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
@@ -52,6 +54,10 @@ var adminTierEditFlags = []cli.Flag{
 		Value: "",
 		Usage: "path to Google Cloud Storage credentials file",
 	},
+	cli.BoolFlag{
+		Name:  "verify",
+		Usage: "write and read back a probe object in the remote tier with the new credentials before saving them",
+	},
 }
 
 var adminTierEditCmd = cli.Command{
@@ -83,6 +89,9 @@ EXAMPLES:
 
   3. Update credentials for an existing Google Cloud Storage remote tier:
      {{.Prompt}} {{.HelpName}} myminio GCSTIER --credentials-file /path/to/credentials.json
+
+  4. Update credentials for an existing MinIO remote tier after checking they can write and read in the bucket:
+     {{.Prompt}} {{.HelpName}} myminio WARM-TIER --access-key ACCESS-KEY --secret-key SECRET-KEY --verify
 `,
 }
 
@@ -135,8 +144,19 @@ func mainAdminTierEdit(ctx *cli.Context) error {
 		fatalIf(errInvalidArgument().Trace(args.Tail()...), "Insufficient credential information supplied to update remote tier target credentials")
 	}
 
+	verifySaved := false
+	if ctx.Bool("verify") {
+		probed, err := probeTierCreds(client, tierName, creds)
+		fatalIf(err.Trace(args...), "Unable to verify remote tier credentials, they were not saved")
+		verifySaved = !probed
+	}
+
 	e := client.EditTier(globalContext, tierName, creds)
 	fatalIf(probe.NewError(e).Trace(args...), "Unable to edit remote tier")
+	if verifySaved {
+		e = client.VerifyTier(globalContext, tierName)
+		fatalIf(probe.NewError(e).Trace(args...), "Remote tier credentials were saved, but the remote tier failed verification")
+	}
 
 	printMsg(&tierMessage{
 		op:       ctx.Command.Name,
@@ -145,3 +165,32 @@ func mainAdminTierEdit(ctx *cli.Context) error {
 	})
 	return nil
 }
+
+// probeTierCreds probes a remote tier with new credentials, see probeTier.
+func probeTierCreds(client *madmin.AdminClient, tierName string, creds madmin.TierCreds) (probed bool, err *probe.Error) {
+	if creds.AccessKey == "" {
+		return false, nil
+	}
+	tiers, e := client.ListTiers(globalContext)
+	if e != nil {
+		return false, probe.NewError(e)
+	}
+	for _, tier := range tiers {
+		if !strings.EqualFold(tier.Name, tierName) {
+			continue
+		}
+		cfg := *tier
+		switch cfg.Type {
+		case madmin.S3:
+			s3Cfg := *cfg.S3
+			s3Cfg.AccessKey, s3Cfg.SecretKey, s3Cfg.AWSRole = creds.AccessKey, creds.SecretKey, false
+			cfg.S3 = &s3Cfg
+		case madmin.MinIO:
+			minioCfg := *cfg.MinIO
+			minioCfg.AccessKey, minioCfg.SecretKey = creds.AccessKey, creds.SecretKey
+			cfg.MinIO = &minioCfg
+		}
+		return probeTier(globalContext, &cfg)
+	}
+	return false, probe.NewError(fmt.Errorf("remote tier %s not found", tierName))
+}
@@ -27,6 +27,7 @@ var ilmTierSubcommands = []cli.Command{
 	ilmTierUpdateCmd,
 	adminTierVerifyCmd,
 	ilmTierCheckCmd,
+	ilmTierMigrateCmd,
 	adminTierRmCmd,
 }
 
@@ -0,0 +1,279 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/pkg/console"
+)
+
+var ilmTierMigrateFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "bucket",
+		Usage: "only migrate the lifecycle rules of this bucket",
+	},
+	cli.BoolFlag{
+		Name:  "move-data",
+		Usage: "bring the objects already transitioned to OLD back, so that they are transitioned to NEW",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "show the rules and objects to migrate without changing them",
+	},
+}
+
+var ilmTierMigrateCmd = cli.Command{
+	Name:         "migrate",
+	Usage:        "re-point lifecycle rules from a remote tier to another",
+	Action:       mainILMTierMigrate,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(ilmTierMigrateFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET OLD NEW
+
+DESCRIPTION:
+  The transition and noncurrent version transition actions of the lifecycle rules of all buckets,
+  or of the bucket given with --bucket, using the remote tier OLD are changed to use NEW.
+
+  The objects already transitioned stay in OLD, which cannot be removed while they exist. With
+  --move-data, the latest version of each object stored in OLD is copied onto itself in the
+  STANDARD storage class, which reads its data back from OLD, and the migrated rules transition it
+  to NEW at their next run. On versioned buckets this creates a new version of these objects. The
+  noncurrent versions stored in OLD are counted but not copied, a copy would become the latest
+  version, they stay in OLD until they expire or are removed.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Re-point all lifecycle rules transitioning to WARM-TIER to COLD-TIER.
+     {{.Prompt}} {{.HelpName}} myminio WARM-TIER COLD-TIER
+
+  2. Show what migrating the rules and the data of 'mybucket' would change.
+     {{.Prompt}} {{.HelpName}} --bucket mybucket --move-data --dry-run myminio WARM-TIER COLD-TIER
+
+  3. Migrate the rules and the data of 'mybucket', whose objects are encrypted with SSE-C.
+     {{.Prompt}} {{.HelpName}} --bucket mybucket --move-data --encrypt-key "myminio/mybucket/=32byteslongsecretkeymustbegiven1" myminio WARM-TIER COLD-TIER
+`,
+}
+
+// tierMigrateMessage is the result of the migration of a bucket.
+type tierMigrateMessage struct {
+	Status  string   `json:"status"`
+	Bucket  string   `json:"bucket"`
+	OldTier string   `json:"oldTier"`
+	NewTier string   `json:"newTier"`
+	Rules   []string `json:"rules"`
+	Objects int      `json:"objects,omitempty"`
+	// Noncurrent is the number of noncurrent versions left in OldTier.
+	Noncurrent int  `json:"noncurrentVersions,omitempty"`
+	DryRun     bool `json:"dryRun,omitempty"`
+}
+
+// Colorized message for console printing.
+func (m tierMigrateMessage) String() string {
+	verb := "Migrated"
+	if m.DryRun {
+		verb = "Would migrate"
+	}
+	msg := fmt.Sprintf("%s %d rule(s) of `%s` from %s to %s", verb, len(m.Rules), m.Bucket, m.OldTier, m.NewTier)
+	if len(m.Rules) > 0 {
+		msg += " (" + strings.Join(m.Rules, ", ") + ")"
+	}
+	if m.Objects > 0 {
+		msg += fmt.Sprintf(", %d object(s) moved out of %s", m.Objects, m.OldTier)
+	}
+	if m.Noncurrent > 0 {
+		msg += fmt.Sprintf(", %d noncurrent version(s) left in %s", m.Noncurrent, m.OldTier)
+	}
+	return console.Colorize("TierMessage", msg+".")
+}
+
+// JSON'ified message for scripting.
+func (m tierMigrateMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// migrateTierRules changes the transitions of the rules of config from
+// oldTier to newTier, and returns the IDs of the changed rules.
+func migrateTierRules(config *lifecycle.Configuration, oldTier, newTier string) (ruleIDs []string) {
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+		changed := false
+		if strings.EqualFold(rule.Transition.StorageClass, oldTier) {
+			rule.Transition.StorageClass = newTier
+			changed = true
+		}
+		if strings.EqualFold(rule.NoncurrentVersionTransition.StorageClass, oldTier) {
+			rule.NoncurrentVersionTransition.StorageClass = newTier
+			changed = true
+		}
+		if changed {
+			ruleIDs = append(ruleIDs, rule.ID)
+		}
+	}
+	return ruleIDs
+}
+
+// moveTierObjects copies the latest versions of the objects of the bucket
+// stored in the tier onto themselves in the STANDARD storage class, and
+// returns their number with the number of noncurrent versions stored in
+// the tier, which are left there.
+func moveTierObjects(alias, bucket, tier string, dryRun bool, encKeyDB map[string][]prefixSSEPair) (n, noncurrent int, err *probe.Error) {
+	bucketURL := alias + "/" + bucket
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return 0, 0, err.Trace(bucketURL)
+	}
+	for content := range clnt.List(globalContext, ListOptions{Recursive: true, WithOlderVersions: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			return n, noncurrent, content.Err.Trace(bucketURL)
+		}
+		if content.IsDeleteMarker || !strings.EqualFold(content.StorageClass, tier) {
+			continue
+		}
+		if !content.IsLatest {
+			noncurrent++
+			continue
+		}
+		n++
+		if dryRun {
+			continue
+		}
+		objectURL := content.URL.String()
+		sse := getSSE(alias+content.URL.Path, encKeyDB[alias])
+		metadata, err := getAllMetadata(globalContext, alias, objectURL, sse, URLs{TargetContent: &ClientContent{}})
+		if err != nil {
+			return n, noncurrent, err.Trace(objectURL)
+		}
+		objectClnt, err := newClientFromAlias(alias, objectURL)
+		if err != nil {
+			return n, noncurrent, err.Trace(objectURL)
+		}
+		err = objectClnt.Copy(globalContext, content.URL.Path, CopyOptions{
+			versionID:    content.VersionID,
+			size:         content.Size,
+			srcSSE:       sse,
+			tgtSSE:       sse,
+			metadata:     metadata,
+			storageClass: s3Standard,
+		}, nil)
+		if err != nil {
+			return n, noncurrent, err.Trace(objectURL)
+		}
+	}
+	return n, noncurrent, nil
+}
+
+// mainILMTierMigrate is the handle for "mc ilm tier migrate" command.
+func mainILMTierMigrate(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) != 3 {
+		showCommandHelpAndExit(ctx, 1)
+	}
+	aliasedURL := args.Get(0)
+	oldTier, newTier := strings.ToUpper(args.Get(1)), strings.ToUpper(args.Get(2))
+	if oldTier == newTier {
+		fatalIf(errInvalidArgument().Trace(args...), "OLD and NEW must be different remote tiers.")
+	}
+	dryRun := ctx.Bool("dry-run")
+
+	console.SetColor("TierMessage", color.New(color.FgGreen))
+
+	// Create a new MinIO Admin Client
+	client, cerr := newAdminClient(aliasedURL)
+	fatalIf(cerr, "Unable to initialize admin connection.")
+
+	tiers, e := client.ListTiers(globalContext)
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to list remote tiers")
+	found := map[string]bool{}
+	for _, tier := range tiers {
+		found[strings.ToUpper(tier.Name)] = true
+	}
+	for _, tier := range []string{oldTier, newTier} {
+		if !found[tier] {
+			fatalIf(errInvalidArgument().Trace(tier), "Remote tier `%s` is not configured.", tier)
+		}
+	}
+
+	encKeyDB, err := getEncKeys(ctx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	alias, _, _ := mustExpandAlias(aliasedURL)
+	buckets := []string{}
+	if bucket := ctx.String("bucket"); bucket != "" {
+		buckets = append(buckets, bucket)
+	} else {
+		clnt, err := newClient(alias)
+		fatalIf(err.Trace(alias), "Unable to initialize client.")
+		contents, err := clnt.ListBuckets(globalContext)
+		fatalIf(err.Trace(alias), "Unable to list buckets.")
+		for _, content := range contents {
+			buckets = append(buckets, path.Base(content.URL.Path))
+		}
+	}
+
+	for _, bucket := range buckets {
+		bucketURL := alias + "/" + bucket
+		clnt, err := newClient(bucketURL)
+		fatalIf(err.Trace(bucketURL), "Unable to initialize client.")
+		config, err := clnt.GetLifecycle(globalContext)
+		if err != nil {
+			if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchLifecycleConfiguration" {
+				continue
+			}
+			fatalIf(err.Trace(bucketURL), "Unable to get the lifecycle configuration of `%s`.", bucket)
+		}
+		msg := tierMigrateMessage{
+			Bucket:  bucket,
+			OldTier: oldTier,
+			NewTier: newTier,
+			Rules:   migrateTierRules(config, oldTier, newTier),
+			DryRun:  dryRun,
+		}
+		if len(msg.Rules) == 0 {
+			continue
+		}
+		if !dryRun {
+			err = clnt.SetLifecycle(globalContext, config)
+			fatalIf(err.Trace(bucketURL), "Unable to set the lifecycle configuration of `%s`.", bucket)
+		}
+		if ctx.Bool("move-data") {
+			msg.Objects, msg.Noncurrent, err = moveTierObjects(alias, bucket, oldTier, dryRun, encKeyDB)
+			fatalIf(err.Trace(bucketURL), "Unable to move the objects of `%s` out of %s.", bucket, oldTier)
+		}
+		printMsg(msg)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+func TestMigrateTierRules(t *testing.T) {
+	config := &lifecycle.Configuration{Rules: []lifecycle.Rule{
+		{ID: "current", Transition: lifecycle.Transition{StorageClass: "WARM"}},
+		{ID: "noncurrent", NoncurrentVersionTransition: lifecycle.NoncurrentVersionTransition{StorageClass: "warm"}},
+		{ID: "other", Transition: lifecycle.Transition{StorageClass: "COLD"}},
+		{ID: "expiry", Expiration: lifecycle.Expiration{Days: 10}},
+	}}
+
+	ruleIDs := migrateTierRules(config, "WARM", "ARCHIVE")
+	if want := []string{"current", "noncurrent"}; !reflect.DeepEqual(ruleIDs, want) {
+		t.Fatalf("expected rules %v, got %v", want, ruleIDs)
+	}
+	if sc := config.Rules[0].Transition.StorageClass; sc != "ARCHIVE" {
+		t.Errorf("expected transition to ARCHIVE, got %s", sc)
+	}
+	if sc := config.Rules[1].NoncurrentVersionTransition.StorageClass; sc != "ARCHIVE" {
+		t.Errorf("expected noncurrent transition to ARCHIVE, got %s", sc)
+	}
+	if sc := config.Rules[2].Transition.StorageClass; sc != "COLD" {
+		t.Errorf("expected transition to COLD to be kept, got %s", sc)
+	}
+	if ruleIDs = migrateTierRules(config, "WARM", "ARCHIVE"); len(ruleIDs) != 0 {
+		t.Errorf("expected no rule left to migrate, got %v", ruleIDs)
+	}
+}
+
+const testTierVersions = `<ListVersionsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+<Name>bucket</Name><IsTruncated>false</IsTruncated>
+<Version><Key>a</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest><LastModified>2023-01-02T00:00:00.000Z</LastModified><ETag>"e2"</ETag><Size>4</Size><StorageClass>WARM</StorageClass></Version>
+<Version><Key>a</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><LastModified>2023-01-01T00:00:00.000Z</LastModified><ETag>"e1"</ETag><Size>4</Size><StorageClass>WARM</StorageClass></Version>
+<Version><Key>b</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest><LastModified>2023-01-01T00:00:00.000Z</LastModified><ETag>"e3"</ETag><Size>4</Size><StorageClass>STANDARD</StorageClass></Version>
+<DeleteMarker><Key>c</Key><VersionId>v4</VersionId><IsLatest>true</IsLatest><LastModified>2023-01-01T00:00:00.000Z</LastModified></DeleteMarker>
+</ListVersionsResult>`
+
+func TestMoveTierObjects(t *testing.T) {
+	var copies []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Has("location"):
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+		case query.Has("versions"):
+			w.Write([]byte(testTierVersions))
+		case r.Method == http.MethodHead:
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("ETag", `"e2"`)
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2023 00:00:00 GMT")
+			w.Header().Set("Content-Length", "4")
+		case r.Method == http.MethodPut:
+			copies = append(copies, r.Header.Clone())
+			w.Write([]byte(`<CopyObjectResult><ETag>"e5"</ETag><LastModified>2023-01-03T00:00:00.000Z</LastModified></CopyObjectResult>`))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	sse, e := encrypt.NewSSEC([]byte("32byteslongsecretkeymustbegiven1"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	encKeyDB := map[string][]prefixSSEPair{"myminio": {{Prefix: "myminio/bucket/", SSE: sse}}}
+
+	n, noncurrent, err := moveTierObjects("myminio", "bucket", "warm", true, encKeyDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || noncurrent != 1 || len(copies) != 0 {
+		t.Fatalf("expected 1 object and 1 noncurrent version without copy, got %d, %d and %d copies", n, noncurrent, len(copies))
+	}
+
+	if _, _, err = moveTierObjects("myminio", "bucket", "warm", false, encKeyDB); err != nil {
+		t.Fatal(err)
+	}
+	if len(copies) != 1 {
+		t.Fatalf("expected a single copy, got %d", len(copies))
+	}
+	if source := copies[0].Get("X-Amz-Copy-Source"); source != "bucket/a?versionId=v2" {
+		t.Errorf("unexpected copy source %s", source)
+	}
+	if copies[0].Get("X-Amz-Storage-Class") != s3Standard {
+		t.Errorf("expected a copy to %s, got %s", s3Standard, copies[0].Get("X-Amz-Storage-Class"))
+	}
+	if copies[0].Get("X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key") == "" || copies[0].Get("X-Amz-Server-Side-Encryption-Customer-Key") == "" {
+		t.Error("expected the SSE-C key of the source and the target")
+	}
+}
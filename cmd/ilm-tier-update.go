@@ -47,5 +47,8 @@ EXAMPLES:
 
   3. Update credentials for an existing Google Cloud Storage remote tier:
      {{.Prompt}} {{.HelpName}} myminio GCSTIER --credentials-file /path/to/credentials.json
+
+  4. Update credentials for an existing MinIO remote tier after checking they can write and read in the bucket:
+     {{.Prompt}} {{.HelpName}} myminio WARM-TIER --access-key ACCESS-KEY --secret-key SECRET-KEY --verify
 `,
 }
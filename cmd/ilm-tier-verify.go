@@ -18,7 +18,16 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
 	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
 	"github.com/kirolous/mc/pkg/probe"
 )
 
@@ -79,3 +88,65 @@ func mainAdminTierVerify(ctx *cli.Context) error {
 	})
 	return nil
 }
+
+// probeTier writes, reads back and removes a probe object in the bucket of
+// a S3 or MinIO remote tier with the credentials of the tier. Other tiers,
+// and AWS S3 tiers using the role of the servers, can only be verified by
+// the servers once saved: probed is false for them.
+func probeTier(ctx context.Context, cfg *madmin.TierConfig) (probed bool, err *probe.Error) {
+	var endpoint, accessKey, secretKey, bucket, prefix string
+	switch {
+	case cfg.Type == madmin.S3 && !cfg.S3.AWSRole:
+		endpoint, accessKey, secretKey = cfg.S3.Endpoint, cfg.S3.AccessKey, cfg.S3.SecretKey
+		bucket, prefix = cfg.S3.Bucket, cfg.S3.Prefix
+	case cfg.Type == madmin.MinIO:
+		endpoint, accessKey, secretKey = cfg.MinIO.Endpoint, cfg.MinIO.AccessKey, cfg.MinIO.SecretKey
+		bucket, prefix = cfg.MinIO.Bucket, cfg.MinIO.Prefix
+	default:
+		return false, nil
+	}
+	if endpoint == "" {
+		endpoint = "https://s3.amazonaws.com"
+	}
+
+	id := uuid.NewString()
+	objectURL := strings.TrimSuffix(endpoint, "/") + "/" + bucket + "/" + path.Join(prefix, ".mc-tier-probe-"+id)
+	clnt, err := S3New(NewS3Config(objectURL, &aliasConfigV10{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		API:       "S3v4",
+		Path:      "auto",
+	}))
+	if err != nil {
+		return true, err.Trace(objectURL)
+	}
+
+	body := []byte("mc ilm tier probe " + id)
+	if _, err = clnt.Put(ctx, bytes.NewReader(body), int64(len(body)), nil, PutOptions{}); err != nil {
+		return true, err.Trace(objectURL)
+	}
+	reader, err := clnt.Get(ctx, GetOptions{})
+	if err == nil {
+		got, e := io.ReadAll(reader)
+		reader.Close()
+		switch {
+		case e != nil:
+			err = probe.NewError(e)
+		case !bytes.Equal(got, body):
+			err = probe.NewError(errors.New("the probe object read back differs from the one written"))
+		}
+	}
+
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: clnt.GetURL()}
+	close(contentCh)
+	for result := range clnt.Remove(ctx, false, false, false, false, contentCh) {
+		if err == nil && result.Err != nil {
+			err = result.Err
+		}
+	}
+	if err != nil {
+		return true, err.Trace(objectURL)
+	}
+	return true, nil
+}
@@ -0,0 +1,527 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/parquet"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// inventoryRowsPerFile is the number of objects of each data file.
+const inventoryRowsPerFile = 1000000
+
+// Formats of the inventory files.
+const (
+	inventoryFormatCSV     = "csv"
+	inventoryFormatParquet = "parquet"
+)
+
+var inventoryGenerateFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "dest",
+		Usage: "prefix the inventory is written to, on an alias or the filesystem",
+	},
+	cli.StringFlag{
+		Name:  "file-format",
+		Value: inventoryFormatCSV,
+		Usage: "format of the inventory files, 'csv' or 'parquet'",
+	},
+	cli.StringFlag{
+		Name:  "id",
+		Value: "mc-inventory",
+		Usage: "identifier of the inventory, part of the path of the files",
+	},
+	cli.BoolFlag{
+		Name:  "versions",
+		Usage: "list all the versions and delete markers of the objects",
+	},
+	cli.BoolFlag{
+		Name:  "tags",
+		Usage: "include the tags of the objects, fetched with a request per object",
+	},
+	cli.IntFlag{
+		Name:  "parallel",
+		Value: 8,
+		Usage: "number of top level prefixes listed concurrently",
+	},
+}
+
+var inventoryGenerateCmd = cli.Command{
+	Name:         "generate",
+	Usage:        "generate an S3 inventory of a bucket on the client",
+	Action:       mainInventoryGenerate,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(inventoryGenerateFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS/BUCKET --dest TARGET
+
+  List a bucket and write an inventory in the format of S3 Inventory, for
+  servers which don't implement the inventory API. The data files are gzip
+  compressed CSV files, or Parquet files with --file-format parquet, written
+  to TARGET/BUCKET/ID/data/, the manifest.json and manifest.checksum files
+  to TARGET/BUCKET/ID/YYYY-MM-DDTHH-MMZ/.
+
+  The columns are Bucket, Key, VersionId, IsLatest, IsDeleteMarker (with
+  --versions), Size, LastModifiedDate, ETag, StorageClass,
+  IsMultipartUploaded and Tags (with --tags, URL encoded). The Parquet
+  columns are named bucket, key, version_id and so on, their keys are not
+  URL encoded. The top level prefixes of the bucket are listed concurrently.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Generate the inventory of a bucket into another bucket.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket --dest myminio/reports/
+
+  2. Generate the inventory of all the versions of the objects with their tags into a local folder.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket --versions --tags --dest /var/reports/
+
+  3. Generate a Parquet inventory of a bucket, to query it with Athena or Spark.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket --file-format parquet --dest myminio/reports/
+`,
+}
+
+// inventoryFile is a data file of the manifest.
+type inventoryFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5Checksum string `json:"MD5checksum"`
+}
+
+// inventoryManifest is the manifest.json of S3 Inventory.
+type inventoryManifest struct {
+	SourceBucket      string          `json:"sourceBucket"`
+	DestinationBucket string          `json:"destinationBucket"`
+	Version           string          `json:"version"`
+	CreationTimestamp string          `json:"creationTimestamp"`
+	FileFormat        string          `json:"fileFormat"`
+	FileSchema        string          `json:"fileSchema"`
+	Files             []inventoryFile `json:"files"`
+}
+
+// inventoryMessage container for a generated inventory
+type inventoryMessage struct {
+	Status   string `json:"status"`
+	Bucket   string `json:"bucket"`
+	Objects  int64  `json:"objects"`
+	Files    int    `json:"files"`
+	Manifest string `json:"manifest"`
+}
+
+func (i inventoryMessage) String() string {
+	return console.Colorize("Inventory", fmt.Sprintf("Generated the inventory of %d object(s) of `%s` in %d file(s), manifest `%s`.",
+		i.Objects, i.Bucket, i.Files, i.Manifest))
+}
+
+func (i inventoryMessage) JSON() string {
+	i.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(i, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// inventorySchema returns the columns of the inventory.
+func inventorySchema(versions, tags bool) []string {
+	schema := []string{"Bucket", "Key"}
+	if versions {
+		schema = append(schema, "VersionId", "IsLatest", "IsDeleteMarker")
+	}
+	schema = append(schema, "Size", "LastModifiedDate", "ETag", "StorageClass", "IsMultipartUploaded")
+	if tags {
+		schema = append(schema, "Tags")
+	}
+	return schema
+}
+
+// inventoryRow returns the CSV line of an object, all the values are
+// quoted and the key is URL encoded like in S3 Inventory.
+func inventoryRow(bucket, key string, content *ClientContent, versions bool, tags map[string]string, withTags bool) string {
+	etag := strings.Trim(content.ETag, "\"")
+	values := []string{bucket, url.QueryEscape(key)}
+	if versions {
+		values = append(values, content.VersionID, strconv.FormatBool(content.IsLatest), strconv.FormatBool(content.IsDeleteMarker))
+	}
+	if content.IsDeleteMarker {
+		values = append(values, "", content.Time.UTC().Format("2006-01-02T15:04:05.000Z"), "", "", "")
+	} else {
+		storageClass := content.StorageClass
+		if storageClass == "" {
+			storageClass = "STANDARD"
+		}
+		values = append(values, strconv.FormatInt(content.Size, 10), content.Time.UTC().Format("2006-01-02T15:04:05.000Z"),
+			etag, storageClass, strconv.FormatBool(strings.Contains(etag, "-")))
+	}
+	if withTags {
+		t := url.Values{}
+		for k, v := range tags {
+			t.Set(k, v)
+		}
+		values = append(values, t.Encode())
+	}
+	for i, v := range values {
+		values[i] = "\"" + strings.ReplaceAll(v, "\"", "\"\"") + "\""
+	}
+	return strings.Join(values, ",") + "\n"
+}
+
+// inventoryParquetColumns returns the columns of the Parquet inventory,
+// the values missing for delete markers are null.
+func inventoryParquetColumns(versions, tags bool) []parquet.Column {
+	columns := []parquet.Column{
+		{Name: "bucket", Type: parquet.String},
+		{Name: "key", Type: parquet.String},
+	}
+	if versions {
+		columns = append(columns,
+			parquet.Column{Name: "version_id", Type: parquet.String, Optional: true},
+			parquet.Column{Name: "is_latest", Type: parquet.Boolean},
+			parquet.Column{Name: "is_delete_marker", Type: parquet.Boolean})
+	}
+	columns = append(columns,
+		parquet.Column{Name: "size", Type: parquet.Int64, Optional: true},
+		parquet.Column{Name: "last_modified_date", Type: parquet.TimestampMillis},
+		parquet.Column{Name: "e_tag", Type: parquet.String, Optional: true},
+		parquet.Column{Name: "storage_class", Type: parquet.String, Optional: true},
+		parquet.Column{Name: "is_multipart_uploaded", Type: parquet.Boolean, Optional: true})
+	if tags {
+		columns = append(columns, parquet.Column{Name: "tags", Type: parquet.String, Optional: true})
+	}
+	return columns
+}
+
+// inventoryParquetRow returns the values of an object in the columns of
+// inventoryParquetColumns.
+func inventoryParquetRow(bucket, key string, content *ClientContent, versions bool, tags map[string]string, withTags bool) []interface{} {
+	values := []interface{}{bucket, key}
+	if versions {
+		var versionID interface{}
+		if content.VersionID != "" {
+			versionID = content.VersionID
+		}
+		values = append(values, versionID, content.IsLatest, content.IsDeleteMarker)
+	}
+	if content.IsDeleteMarker {
+		values = append(values, nil, content.Time, nil, nil, nil)
+	} else {
+		etag := strings.Trim(content.ETag, "\"")
+		storageClass := content.StorageClass
+		if storageClass == "" {
+			storageClass = "STANDARD"
+		}
+		values = append(values, content.Size, content.Time, etag, storageClass, strings.Contains(etag, "-"))
+	}
+	if withTags {
+		t := url.Values{}
+		for k, v := range tags {
+			t.Set(k, v)
+		}
+		values = append(values, t.Encode())
+	}
+	return values
+}
+
+// inventoryWriter writes the rows in gzip compressed CSV files, or in
+// Parquet files.
+type inventoryWriter struct {
+	ctx      context.Context
+	dataURL  string
+	format   string
+	versions bool
+	withTags bool
+	buf      bytes.Buffer
+	gz       *gzip.Writer
+	pq       *parquet.Writer
+	rows     int
+	files    []inventoryFile
+}
+
+func (w *inventoryWriter) write(bucket, key string, content *ClientContent, tags map[string]string) *probe.Error {
+	switch w.format {
+	case inventoryFormatParquet:
+		if w.pq == nil {
+			w.pq = parquet.NewWriter(inventoryParquetColumns(w.versions, w.withTags))
+		}
+		if e := w.pq.Write(inventoryParquetRow(bucket, key, content, w.versions, tags, w.withTags)...); e != nil {
+			return probe.NewError(e)
+		}
+	default:
+		if w.gz == nil {
+			w.buf.Reset()
+			w.gz = gzip.NewWriter(&w.buf)
+		}
+		w.gz.Write([]byte(inventoryRow(bucket, key, content, w.versions, tags, w.withTags)))
+	}
+	w.rows++
+	if w.rows == inventoryRowsPerFile {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush uploads the current data file.
+func (w *inventoryWriter) flush() *probe.Error {
+	var ext string
+	switch {
+	case w.pq != nil:
+		w.buf.Reset()
+		if _, e := w.pq.WriteTo(&w.buf); e != nil {
+			return probe.NewError(e)
+		}
+		w.pq, ext = nil, ".parquet"
+	case w.gz != nil:
+		if e := w.gz.Close(); e != nil {
+			return probe.NewError(e)
+		}
+		w.gz, ext = nil, ".csv.gz"
+	default:
+		return nil
+	}
+	w.rows = 0
+
+	fileURL := urlJoinPath(w.dataURL, uuid.New().String()+ext)
+	sum := md5.Sum(w.buf.Bytes())
+	key, err := putInventoryFile(w.ctx, fileURL, w.buf.Bytes())
+	if err != nil {
+		return err
+	}
+	w.files = append(w.files, inventoryFile{Key: key, Size: int64(w.buf.Len()), MD5Checksum: hex.EncodeToString(sum[:])})
+	return nil
+}
+
+// putInventoryFile writes a file of the inventory and returns its key in
+// the destination bucket, or its path on the filesystem.
+func putInventoryFile(ctx context.Context, fileURL string, data []byte) (string, *probe.Error) {
+	clnt, err := newClient(fileURL)
+	if err != nil {
+		return "", err.Trace(fileURL)
+	}
+	if _, err = clnt.Put(ctx, bytes.NewReader(data), int64(len(data)), nil, PutOptions{md5: true}); err != nil {
+		return "", err.Trace(fileURL)
+	}
+	u := clnt.GetURL()
+	if u.Type == objectStorage {
+		_, key := url2BucketAndObject(&u)
+		return key, nil
+	}
+	return u.Path, nil
+}
+
+// listInventory lists the objects of the bucket, the top level prefixes
+// are listed concurrently by parallel workers.
+func listInventory(ctx context.Context, alias, bucketURL string, versions bool, parallel int, fn func(content *ClientContent)) *probe.Error {
+	opts := ListOptions{WithOlderVersions: versions, WithDeleteMarkers: versions, ShowDir: DirFirst}
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr *probe.Error
+	)
+	setErr := func(err *probe.Error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+	}
+	prefixes := make(chan string)
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prefix := range prefixes {
+				pClnt, err := newClientFromAlias(alias, prefix)
+				if err != nil {
+					setErr(err)
+					continue
+				}
+				recursive := opts
+				recursive.Recursive, recursive.ShowDir = true, DirNone
+				for content := range pClnt.List(ctx, recursive) {
+					if content.Err != nil {
+						setErr(content.Err.Trace(prefix))
+						continue
+					}
+					fn(content)
+				}
+			}
+		}()
+	}
+
+	for content := range clnt.List(ctx, opts) {
+		if content.Err != nil {
+			setErr(content.Err.Trace(bucketURL))
+			continue
+		}
+		if content.Type.IsDir() {
+			prefixes <- content.URL.String()
+			continue
+		}
+		fn(content)
+	}
+	close(prefixes)
+	wg.Wait()
+	return firstErr
+}
+
+func checkInventoryGenerateSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+	if ctx.String("dest") == "" {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--dest is required.")
+	}
+	switch strings.ToLower(ctx.String("file-format")) {
+	case inventoryFormatCSV, inventoryFormatParquet:
+	case "orc":
+		fatalIf(errInvalidArgument().Trace(ctx.String("file-format")), "ORC inventories cannot be generated, use --file-format csv or parquet.")
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("file-format")), "Unknown --file-format.")
+	}
+	if ctx.Int("parallel") < 1 {
+		fatalIf(errInvalidArgument().Trace(), "--parallel must be at least 1.")
+	}
+	if strings.ContainsAny(ctx.String("id"), "/\\") {
+		fatalIf(errInvalidArgument().Trace(ctx.String("id")), "--id must not contain separators.")
+	}
+}
+
+// mainInventoryGenerate is the handle for "mc inventory generate" command.
+func mainInventoryGenerate(cliCtx *cli.Context) error {
+	ctx, cancelInventory := context.WithCancel(globalContext)
+	defer cancelInventory()
+
+	checkInventoryGenerateSyntax(cliCtx)
+	console.SetColor("Inventory", color.New(color.FgGreen))
+
+	aliasedURL := cliCtx.Args().Get(0)
+	alias, _, _ := mustExpandAlias(aliasedURL)
+	clnt, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize connection.")
+	u := clnt.GetURL()
+	bucket, prefix := url2BucketAndObject(&u)
+	if u.Type != objectStorage || bucket == "" || prefix != "" {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "The source must be a bucket.")
+	}
+
+	versions, withTags := cliCtx.Bool("versions"), cliCtx.Bool("tags")
+	id := cliCtx.String("id")
+	created := time.Now().UTC()
+	base := urlJoinPath(urlJoinPath(cliCtx.String("dest"), bucket), id)
+	w := &inventoryWriter{
+		ctx:      ctx,
+		dataURL:  urlJoinPath(base, "data"),
+		format:   strings.ToLower(cliCtx.String("file-format")),
+		versions: versions,
+		withTags: withTags,
+	}
+
+	var (
+		mu      sync.Mutex
+		objects int64
+		wErr    *probe.Error
+	)
+	err = listInventory(ctx, alias, aliasedURL, versions, cliCtx.Int("parallel"), func(content *ClientContent) {
+		_, key := url2BucketAndObject(&content.URL)
+		var tags map[string]string
+		if withTags && !content.IsDeleteMarker {
+			if tClnt, err := newClientFromAlias(alias, content.URL.String()); err == nil {
+				tags, err = tClnt.GetTags(ctx, content.VersionID)
+				errorIf(err.Trace(content.URL.String()), "Unable to get the tags of `"+key+"`.")
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if wErr == nil {
+			wErr = w.write(bucket, key, content, tags)
+			objects++
+		}
+	})
+	fatalIf(err, "Unable to list `"+aliasedURL+"`.")
+	fatalIf(wErr, "Unable to write the inventory.")
+	fatalIf(w.flush(), "Unable to write the inventory.")
+
+	sort.Slice(w.files, func(i, j int) bool { return w.files[i].Key < w.files[j].Key })
+	destClnt, err := newClient(cliCtx.String("dest"))
+	fatalIf(err.Trace(cliCtx.String("dest")), "Unable to initialize connection.")
+	var destBucket string
+	if destURL := destClnt.GetURL(); destURL.Type == objectStorage {
+		destBucket, _ = url2BucketAndObject(&destURL)
+		destBucket = "arn:aws:s3:::" + destBucket
+	}
+	manifest := inventoryManifest{
+		SourceBucket:      bucket,
+		DestinationBucket: destBucket,
+		Version:           "2016-11-30",
+		CreationTimestamp: strconv.FormatInt(created.UnixNano()/int64(time.Millisecond), 10),
+		FileFormat:        "CSV",
+		FileSchema:        strings.Join(inventorySchema(versions, withTags), ", "),
+		Files:             w.files,
+	}
+	if w.format == inventoryFormatParquet {
+		manifest.FileFormat = "Parquet"
+		manifest.FileSchema = parquet.NewWriter(inventoryParquetColumns(versions, withTags)).Schema("s3.inventory")
+	}
+	if manifest.Files == nil {
+		manifest.Files = []inventoryFile{}
+	}
+	data, e := json.MarshalIndent(manifest, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	manifestDir := urlJoinPath(base, created.Format("2006-01-02T15-04Z"))
+	manifestKey, err := putInventoryFile(ctx, urlJoinPath(manifestDir, "manifest.json"), data)
+	fatalIf(err, "Unable to write the manifest.")
+	sum := md5.Sum(data)
+	_, err = putInventoryFile(ctx, urlJoinPath(manifestDir, "manifest.checksum"), []byte(hex.EncodeToString(sum[:])))
+	fatalIf(err, "Unable to write the manifest.")
+
+	printMsg(inventoryMessage{
+		Bucket:   aliasedURL,
+		Objects:  objects,
+		Files:    len(w.files),
+		Manifest: manifestKey,
+	})
+	return nil
+}
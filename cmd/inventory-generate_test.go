@@ -0,0 +1,78 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kirolous/mc/pkg/parquet"
+)
+
+func TestInventoryRow(t *testing.T) {
+	modTime := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+	content := &ClientContent{Size: 42, Time: modTime, ETag: "\"e1d2-3\"", VersionID: "v1", IsLatest: true}
+
+	row := inventoryRow("bucket", "dir/my \"file\".txt", content, false, nil, false)
+	expected := `"bucket","dir%2Fmy+%22file%22.txt","42","2023-05-01T10:30:00.000Z","e1d2-3","STANDARD","true"` + "\n"
+	if row != expected {
+		t.Errorf("expected %s, got %s", expected, row)
+	}
+
+	row = inventoryRow("bucket", "a", &ClientContent{Time: modTime, VersionID: "v2", IsDeleteMarker: true}, true, nil, true)
+	expected = `"bucket","a","v2","false","true","","2023-05-01T10:30:00.000Z","","","",""` + "\n"
+	if row != expected {
+		t.Errorf("expected %s, got %s", expected, row)
+	}
+
+	row = inventoryRow("bucket", "a", content, false, map[string]string{"team": "data", "env": "prod"}, true)
+	expected = `"bucket","a","42","2023-05-01T10:30:00.000Z","e1d2-3","STANDARD","true","env=prod&team=data"` + "\n"
+	if row != expected {
+		t.Errorf("expected %s, got %s", expected, row)
+	}
+}
+
+func TestInventoryParquetRow(t *testing.T) {
+	modTime := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+	content := &ClientContent{Size: 42, Time: modTime, ETag: "\"e1d2-3\"", IsLatest: true}
+	deleteMarker := &ClientContent{Time: modTime, VersionID: "v2", IsDeleteMarker: true}
+
+	testCases := []struct {
+		content  *ClientContent
+		versions bool
+		tags     map[string]string
+		withTags bool
+		expected []interface{}
+	}{
+		{content, false, nil, false, []interface{}{"bucket", "dir/my file", int64(42), modTime, "e1d2-3", "STANDARD", true}},
+		{content, true, nil, false, []interface{}{"bucket", "dir/my file", nil, true, false, int64(42), modTime, "e1d2-3", "STANDARD", true}},
+		{deleteMarker, true, nil, true, []interface{}{"bucket", "dir/my file", "v2", false, true, nil, modTime, nil, nil, nil, ""}},
+		{content, false, map[string]string{"team": "data"}, true, []interface{}{"bucket", "dir/my file", int64(42), modTime, "e1d2-3", "STANDARD", true, "team=data"}},
+	}
+	for i, testCase := range testCases {
+		row := inventoryParquetRow("bucket", "dir/my file", testCase.content, testCase.versions, testCase.tags, testCase.withTags)
+		if !reflect.DeepEqual(row, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, row)
+		}
+		w := parquet.NewWriter(inventoryParquetColumns(testCase.versions, testCase.withTags))
+		if e := w.Write(row...); e != nil {
+			t.Errorf("Test %d: %v", i+1, e)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Versions of the schema of the JSON output, selected with --api-version.
+// Version 1 is the historical output and stays the default, so scripts
+// written against it keep working while the output evolves.
+const (
+	jsonAPIVersion1 = 1
+	// Version 2 adds the version of the schema to every message, and
+	// names the fields of stat like the ones of ls.
+	jsonAPIVersion2 = 2
+
+	jsonAPIVersionLatest = jsonAPIVersion2
+)
+
+// versionedMessage is implemented by messages whose JSON changed after
+// version 1 of the schema, JSON() still returns version 1.
+type versionedMessage interface {
+	message
+	JSONVersion(version int) string
+}
+
+// messageJSON returns the JSON of msg in the version of the schema selected
+// with --api-version.
+func messageJSON(msg message) string {
+	if globalAPIVersion == jsonAPIVersion1 {
+		return msg.JSON()
+	}
+	if vmsg, ok := msg.(versionedMessage); ok {
+		return withAPIVersion(vmsg.JSONVersion(globalAPIVersion))
+	}
+	return withAPIVersion(msg.JSON())
+}
+
+// withAPIVersion adds the apiVersion field to a JSON object from version 2
+// of the schema.
+func withAPIVersion(jsonStr string) string {
+	if globalAPIVersion == jsonAPIVersion1 {
+		return jsonStr
+	}
+	trimmed := strings.TrimSpace(jsonStr)
+	if !strings.HasPrefix(trimmed, "{") {
+		return jsonStr
+	}
+	field := fmt.Sprintf(`"apiVersion":%d`, globalAPIVersion)
+	if strings.TrimSpace(trimmed[1:]) == "}" {
+		return "{" + field + "}"
+	}
+	return "{" + field + "," + trimmed[1:]
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWithAPIVersion(t *testing.T) {
+	defer func(version int) { globalAPIVersion = version }(globalAPIVersion)
+
+	globalAPIVersion = jsonAPIVersion1
+	if got := withAPIVersion(`{"status":"success"}`); got != `{"status":"success"}` {
+		t.Errorf("expected version 1 to be unchanged, got %s", got)
+	}
+
+	globalAPIVersion = jsonAPIVersion2
+	testCases := map[string]string{
+		`{"status":"success"}`:           `{"apiVersion":2,"status":"success"}`,
+		"{\n \"status\": \"success\"\n}": "{\"apiVersion\":2,\n \"status\": \"success\"\n}",
+		`{}`:                             `{"apiVersion":2}`,
+		`["a"]`:                          `["a"]`,
+	}
+	for in, want := range testCases {
+		if got := withAPIVersion(in); got != want {
+			t.Errorf("withAPIVersion(%q): expected %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestStatMessageJSONVersion(t *testing.T) {
+	defer func(version int) { globalAPIVersion = version }(globalAPIVersion)
+
+	msg := statMessage{Key: "obj", VersionID: "v1", DeleteMarker: true}
+	fields := func(s string) map[string]interface{} {
+		m := map[string]interface{}{}
+		if e := json.Unmarshal([]byte(s), &m); e != nil {
+			t.Fatal(e)
+		}
+		return m
+	}
+
+	globalAPIVersion = jsonAPIVersion1
+	v1 := fields(messageJSON(msg))
+	if v1["name"] != "obj" || v1["versionID"] != "v1" || v1["apiVersion"] != nil {
+		t.Errorf("unexpected version 1 message %v", v1)
+	}
+
+	globalAPIVersion = jsonAPIVersion2
+	v2 := fields(messageJSON(msg))
+	if v2["key"] != "obj" || v2["versionId"] != "v1" || v2["isDeleteMarker"] != true || v2["apiVersion"] != float64(2) {
+		t.Errorf("unexpected version 2 message %v", v2)
+	}
+}
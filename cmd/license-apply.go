@@ -0,0 +1,150 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/minio/pkg/licverifier"
+	"github.com/tidwall/gjson"
+)
+
+var licenseApplyCmd = cli.Command{
+	Name:         "apply",
+	Usage:        "apply a license received for an offline registration",
+	OnUsageError: onUsageError,
+	Action:       mainLicenseApply,
+	Before:       setGlobalsFromContext,
+	Flags:        supportGlobalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS LICENSE-FILE
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Apply the license received from SUBNET for the registration token generated
+     with 'mc license register play --offline'
+     {{.Prompt}} {{.HelpName}} play token.lic
+`,
+}
+
+const licApplyMsgTag = "licenseApplyMessage"
+
+type licApplyMessage struct {
+	Status       string    `json:"status"`
+	Alias        string    `json:"alias"`
+	Organization string    `json:"organization,omitempty"`
+	Plan         string    `json:"plan,omitempty"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// String colorized license apply message
+func (li licApplyMessage) String() string {
+	return console.Colorize(licApplyMsgTag, fmt.Sprintf("License applied successfully for %s (%s, expires on %s)",
+		li.Alias, li.Plan, li.ExpiresAt.Format(time.RFC1123)))
+}
+
+// JSON jsonified license apply message
+func (li licApplyMessage) JSON() string {
+	return toJSON(li)
+}
+
+// extractLicenseFromFile - returns the license from the contents of a license file.
+// SUBNET either hands out the raw license or the JSON response containing it.
+func extractLicenseFromFile(data []byte) (string, error) {
+	lic := strings.TrimSpace(string(data))
+	if len(lic) == 0 {
+		return "", errors.New("license file is empty")
+	}
+
+	if strings.HasPrefix(lic, "{") {
+		if !gjson.Valid(lic) {
+			return "", errors.New("license file contains malformed JSON")
+		}
+		result := gjson.Get(lic, "license")
+		if !result.Exists() || len(result.String()) == 0 {
+			return "", errors.New("license file does not contain a 'license' field")
+		}
+		lic = result.String()
+	}
+	return lic, nil
+}
+
+// checkLicenseApplicable - validates that the license can be applied to the given deployment
+func checkLicenseApplicable(li *licverifier.LicenseInfo, deploymentID string, now time.Time) error {
+	if li.ExpiresAt.Before(now) {
+		return fmt.Errorf("license has expired on %s, please generate a new registration token", li.ExpiresAt.Format(time.RFC1123))
+	}
+	if li.DeploymentID != deploymentID {
+		return fmt.Errorf("license was issued for deployment %s, but this cluster has deployment %s", li.DeploymentID, deploymentID)
+	}
+	return nil
+}
+
+func mainLicenseApply(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	console.SetColor(licApplyMsgTag, color.New(color.FgGreen, color.Bold))
+
+	// license files are applied in environments without access to SUBNET,
+	// verify them against the bundled public key.
+	globalAirgapped = true
+
+	alias, _ := url2Alias(ctx.Args().Get(0))
+	licFile := ctx.Args().Get(1)
+
+	data, e := os.ReadFile(licFile)
+	fatalIf(probe.NewError(e), "Unable to read license file %s", licFile)
+
+	lic, e := extractLicenseFromFile(data)
+	fatalIf(probe.NewError(e), "Invalid license file %s:", licFile)
+
+	li, e := parseLicense(lic)
+	fatalIf(probe.NewError(e), "License in %s could not be verified, ensure it is the unmodified response from SUBNET:", licFile)
+
+	e = checkLicenseApplicable(li, getAdminInfo(alias).DeploymentID, time.Now())
+	fatalIf(probe.NewError(e), "Unable to apply license to %s:", alias)
+
+	setSubnetLicense(alias, lic)
+	if len(li.APIKey) > 0 {
+		setSubnetAPIKey(alias, li.APIKey)
+	}
+
+	printMsg(licApplyMessage{
+		Status:       "success",
+		Alias:        alias,
+		Organization: li.Organization,
+		Plan:         li.Plan,
+		ExpiresAt:    li.ExpiresAt,
+	})
+	return nil
+}
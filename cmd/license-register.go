@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
@@ -38,6 +39,14 @@ var licenseRegisterFlags = append([]cli.Flag{
 		Name:  "name",
 		Usage: "Specify the name to associate to this MinIO cluster in SUBNET",
 	},
+	cli.BoolFlag{
+		Name:  "offline",
+		Usage: "write the registration token to a file for registering from another machine",
+	},
+	cli.StringFlag{
+		Name:  "token-file",
+		Usage: "path of the registration token file written in offline mode (default: <alias>-registration.token)",
+	},
 }, subnetCommonFlags...)
 
 var licenseRegisterCmd = cli.Command{
@@ -70,6 +79,14 @@ EXAMPLES:
   4. Register MinIO cluster at alias 'play' on SUBNET, using alias as the cluster name.
      This asks for SUBNET credentials if the cluster is not already registered.
      {{.Prompt}} {{.HelpName}} play
+
+  5. Generate a registration token file for cluster at alias 'play', to be uploaded to SUBNET
+     from a machine with internet access. Apply the license received using 'mc license apply'.
+     {{.Prompt}} {{.HelpName}} play --offline --token-file play.token
+
+  6. Register MinIO cluster at alias 'play' reaching SUBNET through a relay proxy,
+     the proxy is remembered for subsequent SUBNET commands on 'play'.
+     {{.Prompt}} {{.HelpName}} play --airgap-proxy http://relay.internal:3128
 `,
 }
 
@@ -79,6 +96,7 @@ type licRegisterMessage struct {
 	Action string `json:"action,omitempty"`
 	Type   string `json:"type"`
 	URL    string `json:"url,omitempty"`
+	File   string `json:"file,omitempty"`
 }
 
 // String colorized license register message
@@ -90,6 +108,11 @@ func (li licRegisterMessage) String() string {
 	case "offline":
 		msg = fmt.Sprintln("Open the following URL in the browser to register", li.Alias, "on SUBNET:")
 		msg = console.Colorize(licRegisterMsgTag, msg) + console.Colorize(licRegisterLinkTag, li.URL)
+	case "token-file":
+		msg = console.Colorize(licRegisterMsgTag, fmt.Sprintf("Registration token for %s written to ", li.Alias)) +
+			console.Colorize(licRegisterLinkTag, li.File) + "\n"
+		msg += console.Colorize(licRegisterMsgTag, "Upload it at ") + console.Colorize(licRegisterLinkTag, li.URL) + "\n"
+		msg += console.Colorize(licRegisterMsgTag, fmt.Sprintf("and apply the license received using 'mc license apply %s <license-file>'", li.Alias))
 	}
 	return msg
 }
@@ -154,6 +177,14 @@ func mainLicenseRegister(ctx *cli.Context) error {
 	console.SetColor(licRegisterLinkTag, color.New(color.FgWhite, color.Bold))
 	checkLicenseRegisterSyntax(ctx)
 
+	// offline registration never talks to SUBNET directly
+	offline := ctx.Bool("offline")
+	if offline {
+		globalAirgapped = true
+	} else if ctx.IsSet("token-file") {
+		fatalIf(errInvalidArgument(), "'--token-file' is applicable only with '--offline'")
+	}
+
 	// Get the alias parameter from cli
 	aliasedURL := ctx.Args().Get(0)
 	alias, accAPIKey := initSubnetConnectivity(ctx, aliasedURL, true)
@@ -170,7 +201,21 @@ func mainLicenseRegister(ctx *cli.Context) error {
 	regInfo := getClusterRegInfo(getAdminInfo(aliasedURL), clusterName)
 
 	lrm := licRegisterMessage{Status: "success", Alias: alias}
-	if globalAirgapped {
+	if offline {
+		lrm.Type = "token-file"
+		lrm.File = ctx.String("token-file")
+		if len(lrm.File) == 0 {
+			lrm.File = alias + "-registration.token"
+		}
+
+		regToken, e := generateRegToken(regInfo)
+		fatalIf(probe.NewError(e), "Unable to generate registration token")
+
+		e = os.WriteFile(lrm.File, []byte(regToken), 0o600)
+		fatalIf(probe.NewError(e), "Unable to write registration token to %s", lrm.File)
+
+		lrm.URL = subnetBaseURL() + "/cluster/register"
+	} else if globalAirgapped {
 		lrm.Type = "offline"
 
 		regToken, e := generateRegToken(regInfo)
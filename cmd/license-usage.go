@@ -0,0 +1,241 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/minio/pkg/licverifier"
+)
+
+var licenseUsageCmd = cli.Command{
+	Name:         "usage",
+	Usage:        "display the licensed capacity against the used capacity",
+	OnUsageError: onUsageError,
+	Action:       mainLicenseUsage,
+	Before:       setGlobalsFromContext,
+	Flags:        append(supportGlobalFlags, subnetCommonFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS
+
+  The used capacity is the size of the data stored in the cluster. When site replication is enabled,
+  the usage of every site is displayed, the other sites are reached through local aliases pointing
+  at their endpoints.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Display the license usage of the cluster with alias 'play'
+     {{.Prompt}} {{.HelpName}} play
+
+  2. Display the license usage of the cluster with alias 'play' as JSON, for compliance dashboards
+     {{.Prompt}} {{.HelpName}} --json play
+`,
+}
+
+// licenseSiteUsage is the licensed and the used capacity of a cluster.
+type licenseSiteUsage struct {
+	Site             string     `json:"site,omitempty"`
+	Alias            string     `json:"alias,omitempty"`
+	Plan             string     `json:"plan"`
+	LicensedCapacity uint64     `json:"licensedCapacity,omitempty"`
+	UsedCapacity     uint64     `json:"usedCapacity"`
+	UsedPercent      float64    `json:"usedPercent,omitempty"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty"`
+	DaysToExpiry     *int       `json:"daysToExpiry,omitempty"`
+	Error            string     `json:"error,omitempty"`
+}
+
+type licenseUsageMessage struct {
+	Status string `json:"status"`
+	licenseSiteUsage
+	Sites []licenseSiteUsage `json:"sites,omitempty"`
+}
+
+func (u licenseSiteUsage) capacity() string {
+	used := humanize.Bytes(u.UsedCapacity)
+	if u.LicensedCapacity == 0 {
+		return used + " used"
+	}
+	return fmt.Sprintf("%s of %s used (%.1f%%)", used, humanize.Bytes(u.LicensedCapacity), u.UsedPercent)
+}
+
+func (u licenseSiteUsage) expiry() string {
+	if u.ExpiresAt == nil {
+		return "-"
+	}
+	days := *u.DaysToExpiry
+	s := u.ExpiresAt.Format("2006-01-02")
+	switch {
+	case days < 0:
+		return s + " " + console.Colorize(licInfoErrTag, fmt.Sprintf("(expired %d days ago)", -days))
+	case days <= 30:
+		return s + " " + console.Colorize(licInfoErrTag, fmt.Sprintf("(in %d days)", days))
+	}
+	return s + fmt.Sprintf(" (in %d days)", days)
+}
+
+func (m licenseUsageMessage) String() string {
+	var b strings.Builder
+	if m.Error != "" {
+		return licInfoErr(m.Error)
+	}
+	fmt.Fprintf(&b, "%s %s\n", licInfoField("Plan    :"), licInfoVal(m.Plan))
+	fmt.Fprintf(&b, "%s %s\n", licInfoField("Capacity:"), licInfoVal(m.capacity()))
+	fmt.Fprintf(&b, "%s %s", licInfoField("Expires :"), licInfoVal(m.expiry()))
+	if len(m.Sites) == 0 {
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "\n\n%s", licInfoField("Sites:"))
+	for _, site := range m.Sites {
+		name := site.Site
+		if site.Alias != "" {
+			name += " (" + site.Alias + ")"
+		}
+		if site.Error != "" {
+			fmt.Fprintf(&b, "\n  %s: %s", name, licInfoErr(site.Error))
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s: %s, %s, expires %s", name, site.Plan, site.capacity(), site.expiry())
+	}
+	return b.String()
+}
+
+func (m licenseUsageMessage) JSON() string {
+	m.Status = "success"
+	if m.Error != "" {
+		m.Status = "error"
+	}
+	jsonBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonBytes)
+}
+
+// getLicenseSiteUsage returns the licensed and the used capacity of the
+// cluster of alias, the subnet config of the cluster is read directly as
+// the cached one belongs to the alias of the command.
+func getLicenseSiteUsage(ctx context.Context, alias string, now time.Time) (u licenseSiteUsage) {
+	u.Alias = alias
+	client, err := newAdminClient(alias)
+	if err != nil {
+		u.Error = err.ToGoError().Error()
+		return u
+	}
+	info, e := client.ServerInfo(ctx)
+	if e != nil {
+		u.Error = e.Error()
+		return u
+	}
+	u.UsedCapacity = info.Usage.Size
+
+	var lic, apiKey string
+	scfg, e := getMinIOSubSysConfig(client, madmin.SubnetSubSys)
+	if e != nil && e.Error() != "unknown sub-system subnet" {
+		u.Error = e.Error()
+		return u
+	}
+	if len(scfg) > 0 {
+		lic, _ = scfg[0].Lookup("license")
+		apiKey, _ = scfg[0].Lookup("api_key")
+	} else {
+		lic = mcConfig().Aliases[alias].License
+		apiKey = mcConfig().Aliases[alias].APIKey
+	}
+	if lic == "" && apiKey != "" {
+		u.Error = fmt.Sprintf("%s is registered with SUBNET, license info not available, run `mc license update %s`", alias, alias)
+		return u
+	}
+	if lic == "" {
+		u.Plan = "AGPLv3"
+		return u
+	}
+	li, e := parseLicense(lic)
+	if e != nil {
+		u.Error = e.Error()
+		return u
+	}
+	u.setLicense(li, now)
+	return u
+}
+
+// setLicense sets the plan, the licensed capacity and the expiry of the license.
+func (u *licenseSiteUsage) setLicense(li *licverifier.LicenseInfo, now time.Time) {
+	u.Plan = li.Plan
+	u.LicensedCapacity = uint64(li.StorageCapacity) * humanize.TByte
+	if u.LicensedCapacity > 0 {
+		u.UsedPercent = float64(u.UsedCapacity) * 100 / float64(u.LicensedCapacity)
+	}
+	days := int(li.ExpiresAt.Sub(now).Hours() / 24)
+	u.ExpiresAt, u.DaysToExpiry = &li.ExpiresAt, &days
+}
+
+func mainLicenseUsage(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	initLicInfoColors()
+
+	aliasedURL := ctx.Args().Get(0)
+	alias, _ := initSubnetConnectivity(ctx, aliasedURL, false)
+
+	now := time.Now()
+	msg := licenseUsageMessage{licenseSiteUsage: getLicenseSiteUsage(globalContext, alias, now)}
+	if msg.Error != "" {
+		printMsg(msg)
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	client, err := newAdminClient(alias)
+	fatalIf(err, "Unable to initialize admin connection.")
+	srInfo, e := client.SiteReplicationInfo(globalContext)
+	fatalIf(probe.NewError(e), "Unable to get the site replication information.")
+	if srInfo.Enabled {
+		for _, site := range srInfo.Sites {
+			aliases := srAliasesOf(site.Endpoint)
+			if len(aliases) == 0 {
+				msg.Sites = append(msg.Sites, licenseSiteUsage{
+					Site:  site.Name,
+					Error: "no local alias for " + site.Endpoint,
+				})
+				continue
+			}
+			siteUsage := getLicenseSiteUsage(globalContext, aliases[0], now)
+			siteUsage.Site = site.Name
+			msg.Sites = append(msg.Sites, siteUsage)
+		}
+	}
+
+	printMsg(msg)
+	return nil
+}
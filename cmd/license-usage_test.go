@@ -0,0 +1,118 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/pkg/licverifier"
+)
+
+func TestLicenseSiteUsage(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		used     uint64
+		license  licverifier.LicenseInfo
+		percent  float64
+		days     int
+		capacity string
+		expiry   string
+	}{
+		{
+			used:     25 * humanize.TByte,
+			license:  licverifier.LicenseInfo{Plan: "ENTERPRISE", StorageCapacity: 100, ExpiresAt: now.AddDate(1, 0, 0)},
+			percent:  25,
+			days:     366,
+			capacity: "25 TB of 100 TB used (25.0%)",
+			expiry:   "2024-06-01 (in 366 days)",
+		},
+		// The usage may exceed the licensed capacity.
+		{
+			used:     150 * humanize.TByte,
+			license:  licverifier.LicenseInfo{Plan: "STANDARD", StorageCapacity: 100, ExpiresAt: now.AddDate(0, 0, 10)},
+			percent:  150,
+			days:     10,
+			capacity: "150 TB of 100 TB used (150.0%)",
+			expiry:   "(in 10 days)",
+		},
+		// A license without capacity only reports the usage.
+		{
+			used:     humanize.GByte,
+			license:  licverifier.LicenseInfo{Plan: "STANDARD", ExpiresAt: now.AddDate(0, 0, -3)},
+			days:     -3,
+			capacity: "1.0 GB used",
+			expiry:   "(expired 3 days ago)",
+		},
+	}
+	for i, testCase := range testCases {
+		u := licenseSiteUsage{UsedCapacity: testCase.used}
+		u.setLicense(&testCase.license, now)
+		if u.Plan != testCase.license.Plan {
+			t.Errorf("Test %d: expected plan %s, got %s", i+1, testCase.license.Plan, u.Plan)
+		}
+		if u.UsedPercent != testCase.percent {
+			t.Errorf("Test %d: expected %v%% used, got %v%%", i+1, testCase.percent, u.UsedPercent)
+		}
+		if u.DaysToExpiry == nil || *u.DaysToExpiry != testCase.days {
+			t.Errorf("Test %d: expected %d days to expiry, got %v", i+1, testCase.days, u.DaysToExpiry)
+		}
+		if capacity := u.capacity(); capacity != testCase.capacity {
+			t.Errorf("Test %d: expected capacity %q, got %q", i+1, testCase.capacity, capacity)
+		}
+		if expiry := u.expiry(); !strings.Contains(expiry, testCase.expiry) {
+			t.Errorf("Test %d: expected expiry %q, got %q", i+1, testCase.expiry, expiry)
+		}
+	}
+
+	// A cluster without license has no expiry.
+	if expiry := (licenseSiteUsage{Plan: "AGPLv3"}).expiry(); expiry != "-" {
+		t.Errorf("expected no expiry, got %q", expiry)
+	}
+}
+
+func TestLicenseUsageMessage(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	days := 100
+	msg := licenseUsageMessage{
+		licenseSiteUsage: licenseSiteUsage{Alias: "play", Plan: "ENTERPRISE", UsedCapacity: humanize.TByte, LicensedCapacity: 10 * humanize.TByte, UsedPercent: 10, ExpiresAt: &expiresAt, DaysToExpiry: &days},
+		Sites: []licenseSiteUsage{
+			{Site: "site1", Alias: "play", Plan: "ENTERPRISE", UsedCapacity: humanize.TByte},
+			{Site: "site2", Error: "no local alias for https://site2.example.com"},
+		},
+	}
+	s := msg.String()
+	for _, expected := range []string{"ENTERPRISE", "1.0 TB of 10 TB used (10.0%)", "2024-06-01", "site1 (play): ENTERPRISE, 1.0 TB used", "site2: ", "no local alias for https://site2.example.com"} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("expected %q in %q", expected, s)
+		}
+	}
+
+	j := msg.JSON()
+	for _, expected := range []string{`"status":"success"`, `"usedPercent":10`, `"daysToExpiry":100`, `"site":"site2"`} {
+		if !strings.Contains(j, expected) {
+			t.Errorf("expected %s in %s", expected, j)
+		}
+	}
+	msg.Error = "license info not available"
+	if j := msg.JSON(); !strings.Contains(j, `"status":"error"`) {
+		t.Errorf("expected an error status in %s", j)
+	}
+}
@@ -24,8 +24,10 @@ import (
 var licenseSubcommands = []cli.Command{
 	licenseRegisterCmd,
 	licenseInfoCmd,
+	licenseUsageCmd,
 	licenseUpdateCmd,
 	licenseUnregisterCmd,
+	licenseApplyCmd,
 }
 
 var licenseCmd = cli.Command{
@@ -60,6 +60,34 @@ var (
 			Name:  "zip",
 			Usage: "list files inside zip archive (MinIO servers only)",
 		},
+		cli.BoolFlag{
+			Name:  "replication",
+			Usage: "display the replication status of objects: PENDING, COMPLETED, FAILED or REPLICA",
+		},
+		cli.BoolFlag{
+			Name:  "tier",
+			Usage: "display the tier of transitioned objects",
+		},
+		cli.BoolFlag{
+			Name:  "restore",
+			Usage: "display the restore status of transitioned objects",
+		},
+		cli.BoolFlag{
+			Name:  "metadata",
+			Usage: "display the user metadata and the tags of objects",
+		},
+		cli.BoolFlag{
+			Name:  "tree",
+			Usage: "display the versions of each object as a tree, latest first, requires --versions",
+		},
+		cli.BoolFlag{
+			Name:  "only-delete-markers",
+			Usage: "list only delete markers, requires --versions",
+		},
+		cli.StringFlag{
+			Name:  "noncurrent-older-than",
+			Usage: "list only versions noncurrent for longer than the specified duration (e.g. 7d10h31s), requires --versions",
+		},
 	}
 )
 
@@ -112,6 +140,28 @@ EXAMPLES:
   
   10. List all objects on mybucket, for the GLACIER storage class
      {{.Prompt}} {{.HelpName}} --storage-class 'GLACIER' s3/mybucket 
+
+  11. List the objects of mybucket with their replication status, tier and restore status. Each object
+      is looked up with a HEAD request.
+     {{.Prompt}} {{.HelpName}} --recursive --replication --tier --restore myminio/mybucket
+
+  12. Display the versions of the objects of mybucket as a tree, only versions noncurrent for more than 30 days.
+     {{.Prompt}} {{.HelpName}} --recursive --versions --tree --noncurrent-older-than 30d myminio/mybucket
+
+  13. List the delete markers of mybucket.
+     {{.Prompt}} {{.HelpName}} --recursive --versions --only-delete-markers myminio/mybucket
+
+  14. List the objects of mybucket with their user metadata and tags. MinIO servers return them with
+      the listing, objects on other servers are looked up with a HEAD request.
+     {{.Prompt}} {{.HelpName}} --recursive --json --metadata myminio/mybucket
+
+  15. List the objects of an access point of AWS S3 given by its ARN, or of a multi-region access point
+      given by its alias, in place of a bucket.
+     {{.Prompt}} {{.HelpName}} s3/arn:aws:s3:us-west-2:123456789012:accesspoint/logs/2023/
+     {{.Prompt}} {{.HelpName}} s3/mfzwi23gnjvgw.mrap/reports/
+
+  16. List the objects of a directory bucket of S3 Express One Zone, on the endpoint of its zone.
+     {{.Prompt}} {{.HelpName}} --recursive s3/logs--usw2-az1--x-s3/2023/
 `,
 }
 
@@ -179,6 +229,21 @@ func checkListSyntax(cliCtx *cli.Context) ([]string, doListOptions) {
 	if listZip && (withOlderVersions || !timeRef.IsZero()) {
 		fatalIf(errInvalidArgument().Trace(args...), "Zip file listing can only be performed on the latest version")
 	}
+
+	versionTree := cliCtx.Bool("tree")
+	filter := versionFilter{onlyDeleteMarkers: cliCtx.Bool("only-delete-markers")}
+	if noncurrentOlderThan := cliCtx.String("noncurrent-older-than"); noncurrentOlderThan != "" {
+		duration, e := ParseDuration(noncurrentOlderThan)
+		fatalIf(probe.NewError(e), "Unable to parse --noncurrent-older-than argument")
+		if duration <= 0 {
+			fatalIf(errInvalidArgument().Trace(noncurrentOlderThan), "--noncurrent-older-than requires a positive duration")
+		}
+		filter.noncurrentOlderThan = time.Duration(duration)
+	}
+	if (versionTree || filter.any()) && !withOlderVersions {
+		fatalIf(errInvalidArgument().Trace(args...), "--tree, --only-delete-markers and --noncurrent-older-than require --versions")
+	}
+
 	storageClasss := cliCtx.String("storage-class")
 	opts := doListOptions{
 		timeRef:           timeRef,
@@ -188,12 +253,24 @@ func checkListSyntax(cliCtx *cli.Context) ([]string, doListOptions) {
 		withOlderVersions: withOlderVersions,
 		listZip:           listZip,
 		filter:            storageClasss,
+		state: objectState{
+			replication: cliCtx.Bool("replication"),
+			tier:        cliCtx.Bool("tier"),
+			restore:     cliCtx.Bool("restore"),
+		},
+		withMetadata:  cliCtx.Bool("metadata"),
+		versionTree:   versionTree,
+		versionFilter: filter,
 	}
 	return args, opts
 }
 
 // mainList - is a handler for mc ls command
 func mainList(cliCtx *cli.Context) error {
+	if runOnAliasGroup(cliCtx) {
+		return nil
+	}
+
 	ctx, cancelList := context.WithCancel(globalContext)
 	defer cancelList()
 
@@ -201,6 +278,7 @@ func mainList(cliCtx *cli.Context) error {
 	console.SetColor("File", color.New(color.Bold))
 	console.SetColor("DEL", color.New(color.FgRed))
 	console.SetColor("PUT", color.New(color.FgGreen))
+	console.SetColor("Latest", color.New(color.FgHiGreen, color.Bold))
 	console.SetColor("VersionID", color.New(color.FgHiBlue))
 	console.SetColor("VersionOrd", color.New(color.FgHiMagenta))
 	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
@@ -208,6 +286,11 @@ func mainList(cliCtx *cli.Context) error {
 	console.SetColor("Time", color.New(color.FgGreen))
 	console.SetColor("Summarize", color.New(color.Bold))
 	console.SetColor("SC", color.New(color.FgBlue))
+	console.SetColor("Replication", color.New(color.FgMagenta))
+	console.SetColor("Tier", color.New(color.FgHiBlue))
+	console.SetColor("Restore", color.New(color.FgCyan))
+	console.SetColor("Metadata", color.New(color.FgHiBlack))
+	console.SetColor("Tags", color.New(color.FgHiBlack))
 
 	// check 'ls' cliCtx arguments.
 	args, opts := checkListSyntax(cliCtx)
@@ -225,6 +308,7 @@ func mainList(cliCtx *cli.Context) error {
 				fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
 			}
 		}
+		opts.alias, _, _ = mustExpandAlias(targetURL)
 		if e := doList(ctx, clnt, opts); e != nil {
 			cErr = e
 		}
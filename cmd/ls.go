@@ -28,6 +28,8 @@ import (
 	"github.com/dustin/go-humanize"
 	json "github.com/minio/colorjson"
 	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/set"
 	"github.com/minio/pkg/console"
 )
 
@@ -49,11 +51,19 @@ type contentMessage struct {
 	VersionID      string `json:"versionId,omitempty"`
 	VersionOrd     int    `json:"versionOrdinal,omitempty"`
 	VersionIndex   int    `json:"versionIndex,omitempty"`
+	IsLatest       bool   `json:"isLatest,omitempty"`
 	IsDeleteMarker bool   `json:"isDeleteMarker,omitempty"`
 	StorageClass   string `json:"storageClass,omitempty"`
 
+	ReplicationStatus string `json:"replicationStatus,omitempty"`
+	Tier              string `json:"tier,omitempty"`
+	RestoreStatus     string `json:"restoreStatus,omitempty"`
+
 	Metadata map[string]string `json:"metadata,omitempty"`
 	Tags     map[string]string `json:"tags,omitempty"`
+
+	state        objectState
+	withMetadata bool
 }
 
 // String colorized string message.
@@ -66,6 +76,25 @@ func (c contentMessage) String() string {
 		message += " " + console.Colorize("SC", c.StorageClass)
 	}
 
+	// The state is only known for objects, "-" when there is none.
+	if c.Filetype != "folder" && !c.IsDeleteMarker {
+		orNone := func(value string) string {
+			if value == "" {
+				return "-"
+			}
+			return value
+		}
+		if c.state.replication {
+			message += " " + console.Colorize("Replication", orNone(c.ReplicationStatus))
+		}
+		if c.state.tier {
+			message += " " + console.Colorize("Tier", orNone(c.Tier))
+		}
+		if c.state.restore {
+			message += " " + console.Colorize("Restore", orNone(c.RestoreStatus))
+		}
+	}
+
 	if c.VersionID != "" {
 		fileDesc += console.Colorize("VersionID", " "+c.VersionID) + console.Colorize("VersionOrd", fmt.Sprintf(" v%d", c.VersionOrd))
 		if c.IsDeleteMarker {
@@ -82,9 +111,27 @@ func (c contentMessage) String() string {
 	} else {
 		message += console.Colorize("File", fileDesc)
 	}
+	if c.withMetadata && c.Filetype != "folder" && !c.IsDeleteMarker {
+		if len(c.Metadata) > 0 {
+			message += " " + console.Colorize("Metadata", formatKeyValues(c.Metadata))
+		}
+		if len(c.Tags) > 0 {
+			message += " " + console.Colorize("Tags", "tags:"+formatKeyValues(c.Tags))
+		}
+	}
 	return message
 }
 
+// formatKeyValues returns the sorted pairs of kv as "key=value,...".
+func formatKeyValues(kv map[string]string) string {
+	pairs := make([]string, 0, len(kv))
+	for k, v := range kv {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 // JSON jsonified content message.
 func (c contentMessage) JSON() string {
 	c.Status = "success"
@@ -111,7 +158,7 @@ func getKey(c *ClientContent) string {
 
 // Generate printable listing from a list of sorted client
 // contents, the latest created content comes first.
-func generateContentMessages(clntURL ClientURL, ctnts []*ClientContent, printAllVersions bool) (msgs []contentMessage) {
+func generateContentMessages(clntURL ClientURL, ctnts []*ClientContent, printAllVersions bool, state objectState, withMetadata bool) (msgs []contentMessage) {
 	prefixPath := clntURL.Path
 	prefixPath = filepath.ToSlash(prefixPath)
 	if !strings.HasSuffix(prefixPath, "/") {
@@ -142,6 +189,26 @@ func generateContentMessages(clntURL ClientURL, ctnts []*ClientContent, printAll
 		contentMsg.StorageClass = c.StorageClass
 		contentMsg.Metadata = c.Metadata
 		contentMsg.Tags = c.Tags
+		if withMetadata && len(c.UserMetadata) > 0 {
+			contentMsg.Metadata = make(map[string]string, len(c.Metadata)+len(c.UserMetadata))
+			for k, v := range c.Metadata {
+				contentMsg.Metadata[k] = v
+			}
+			for k, v := range c.UserMetadata {
+				contentMsg.Metadata[k] = v
+			}
+		}
+		contentMsg.state = state
+		contentMsg.withMetadata = withMetadata
+		if state.replication {
+			contentMsg.ReplicationStatus = c.ReplicationStatus
+		}
+		if state.tier {
+			contentMsg.Tier = objectTier(c.StorageClass)
+		}
+		if state.restore {
+			contentMsg.RestoreStatus = restoreStatus(c.Restore)
+		}
 
 		md5sum := strings.TrimPrefix(c.ETag, "\"")
 		md5sum = strings.TrimSuffix(md5sum, "\"")
@@ -149,6 +216,7 @@ func generateContentMessages(clntURL ClientURL, ctnts []*ClientContent, printAll
 		// Convert OS Type to match console file printing style.
 		contentMsg.Key = getKey(c)
 		contentMsg.VersionID = c.VersionID
+		contentMsg.IsLatest = c.IsLatest
 		contentMsg.IsDeleteMarker = c.IsDeleteMarker
 		contentMsg.VersionOrd = nrVersions - i
 		// URL is empty by default
@@ -197,13 +265,103 @@ func (s summaryMessage) JSON() string {
 	return string(jsonMessageBytes)
 }
 
-// Pretty print the list of versions belonging to one object
-func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, printAllVersions bool) {
+// versionTreeMessage container for the versions of one object displayed
+// as a tree, the latest version comes first.
+type versionTreeMessage struct {
+	Status   string           `json:"status"`
+	Key      string           `json:"key"`
+	URL      string           `json:"url,omitempty"`
+	Versions []contentMessage `json:"versions"`
+}
+
+// String colorized string message.
+func (v versionTreeMessage) String() string {
+	message := console.Colorize("File", v.Key)
+	for i, c := range v.Versions {
+		branch := "├─ "
+		if i == len(v.Versions)-1 {
+			branch = "└─ "
+		}
+		message += "\n" + branch + console.Colorize("Time", fmt.Sprintf("[%s]", c.Time.Format(printDate)))
+		message += console.Colorize("Size", fmt.Sprintf("%7s", strings.Join(strings.Fields(humanize.IBytes(uint64(c.Size))), "")))
+		message += console.Colorize("VersionID", " "+c.VersionID) + console.Colorize("VersionOrd", fmt.Sprintf(" v%d", c.VersionOrd))
+		if c.IsDeleteMarker {
+			message += console.Colorize("DEL", " DEL")
+		} else {
+			message += console.Colorize("PUT", " PUT")
+		}
+		if c.IsLatest {
+			message += console.Colorize("Latest", " LATEST")
+		}
+	}
+	return message
+}
+
+// JSON jsonified version tree message.
+func (v versionTreeMessage) JSON() string {
+	v.Status = "success"
+	for i := range v.Versions {
+		v.Versions[i].Status = "success"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(v, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// versionFilter selects the versions of objects displayed by ls --versions.
+type versionFilter struct {
+	onlyDeleteMarkers   bool
+	noncurrentOlderThan time.Duration
+}
+
+func (f versionFilter) any() bool {
+	return f.onlyDeleteMarkers || f.noncurrentOlderThan > 0
+}
+
+// apply returns the versions of one object, sorted latest first, which
+// match the filter. A version becomes noncurrent when the next version
+// of the object is created.
+func (f versionFilter) apply(versions []contentMessage, now time.Time) (filtered []contentMessage) {
+	for i, v := range versions {
+		if f.onlyDeleteMarkers && !v.IsDeleteMarker {
+			continue
+		}
+		if f.noncurrentOlderThan > 0 {
+			if i == 0 || v.IsLatest || now.Sub(versions[i-1].Time) < f.noncurrentOlderThan {
+				continue
+			}
+		}
+		filtered = append(filtered, v)
+	}
+	return filtered
+}
+
+// Pretty print the list of versions belonging to one object, returns the
+// printed versions.
+func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, o doListOptions) []contentMessage {
+	if len(ctntVersions) == 0 {
+		return nil
+	}
 	sortObjectVersions(ctntVersions)
-	msgs := generateContentMessages(clntURL, ctntVersions, printAllVersions)
+	msgs := generateContentMessages(clntURL, ctntVersions, o.withOlderVersions, o.state, o.withMetadata)
+	if o.versionFilter.any() {
+		msgs = o.versionFilter.apply(msgs, time.Now())
+	}
+	if o.versionTree {
+		if len(msgs) > 0 {
+			printMsg(versionTreeMessage{
+				Key:      msgs[0].Key,
+				URL:      msgs[0].URL,
+				Versions: msgs,
+			})
+		}
+		return msgs
+	}
 	for _, msg := range msgs {
 		printMsg(msg)
 	}
+	return msgs
 }
 
 type doListOptions struct {
@@ -214,6 +372,145 @@ type doListOptions struct {
 	withOlderVersions bool
 	listZip           bool
 	filter            string
+	state             objectState
+	withMetadata      bool
+	alias             string
+	versionTree       bool
+	versionFilter     versionFilter
+}
+
+// objectState selects the state of the objects displayed by ls. Listings
+// don't return it, each object is looked up with a HEAD request.
+type objectState struct {
+	replication bool
+	tier        bool
+	restore     bool
+}
+
+func (s objectState) any() bool {
+	return s.replication || s.tier || s.restore
+}
+
+// s3NonTierStorageClasses are the storage classes of S3 objects which are
+// not transitioned to a tier or archived.
+var s3NonTierStorageClasses = set.CreateStringSet("", "STANDARD", "REDUCED_REDUNDANCY", "STANDARD_IA",
+	"ONEZONE_IA", "INTELLIGENT_TIERING", "GLACIER_IR", "OUTPOSTS", "EXPRESS_ONEZONE")
+
+// objectTier returns the tier of an object with the storage class, empty if
+// the object is not transitioned. MinIO reports the name of the tier of
+// transitioned objects as storage class.
+func objectTier(storageClass string) string {
+	if s3NonTierStorageClasses.Contains(strings.ToUpper(storageClass)) {
+		return ""
+	}
+	return storageClass
+}
+
+// restoreStatus returns the state of the restore of a transitioned object,
+// empty if it is not being restored or the restored copy expired.
+func restoreStatus(restore *minio.RestoreInfo) string {
+	switch {
+	case restore == nil:
+		return ""
+	case restore.OngoingRestore:
+		return "ongoing"
+	case restore.ExpiryTime.IsZero():
+		return "restored"
+	case !restore.ExpiryTime.After(time.Now()):
+		return ""
+	}
+	return "restored until " + restore.ExpiryTime.Local().Format(printDate)
+}
+
+// isFiltered returns true if content is not of the storage class to list.
+func (o doListOptions) isFiltered(content *ClientContent) bool {
+	return content.StorageClass != "" && o.filter != "" && o.filter != "*" && content.StorageClass != o.filter
+}
+
+// lsLookupWorkers is the number of objects looked up in parallel by ls.
+const lsLookupWorkers = 16
+
+// needsLookup returns true if the listed content lacks what ls displays and
+// the object has to be looked up with a HEAD request. MinIO servers return
+// the user metadata and the tags of the objects in the listing, always with
+// the content type, other servers don't.
+func (o doListOptions) needsLookup(content *ClientContent) bool {
+	if content.Type.IsDir() || content.IsDeleteMarker {
+		return false
+	}
+	if o.state.any() {
+		return true
+	}
+	return o.withMetadata && len(content.UserMetadata) == 0 && len(content.Tags) == 0
+}
+
+// lookupObjectState updates content with the state of the object returned
+// by a HEAD request, with the user metadata and the tags if withMetadata
+// is set.
+func lookupObjectState(ctx context.Context, alias string, content *ClientContent, withMetadata bool) *probe.Error {
+	clnt, err := newClientFromAlias(alias, content.URL.String())
+	if err != nil {
+		return err
+	}
+	st, err := clnt.Stat(ctx, StatOptions{versionID: content.VersionID})
+	if err != nil {
+		return err
+	}
+	content.ReplicationStatus = st.ReplicationStatus
+	content.Restore = st.Restore
+	if st.StorageClass != "" {
+		content.StorageClass = st.StorageClass
+	}
+	if !withMetadata || len(content.UserMetadata) > 0 || len(content.Tags) > 0 {
+		return nil
+	}
+	// Same keys as the metadata of MinIO listings.
+	content.UserMetadata = map[string]string{}
+	for k, v := range st.Metadata {
+		switch {
+		case strings.EqualFold(k, "Content-Type"):
+			content.UserMetadata["content-type"] = v
+		case strings.HasPrefix(strings.ToLower(k), "x-amz-meta-"):
+			content.UserMetadata[k] = v
+		}
+	}
+	if count := st.Metadata["X-Amz-Tagging-Count"]; count != "" && count != "0" {
+		content.Tags, err = clnt.GetTags(ctx, content.VersionID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupContents calls lookup on the contents received from in with
+// parallel workers, the contents are sent to the returned channel in the
+// order they were received.
+func lookupContents(in <-chan *ClientContent, workers int, lookup func(*ClientContent)) <-chan *ClientContent {
+	pending := make(chan chan *ClientContent, workers)
+	go func() {
+		defer close(pending)
+		sem := make(chan struct{}, workers)
+		for content := range in {
+			done := make(chan *ClientContent, 1)
+			pending <- done
+			sem <- struct{}{}
+			go func(content *ClientContent) {
+				lookup(content)
+				<-sem
+				done <- content
+			}(content)
+		}
+	}()
+
+	out := make(chan *ClientContent)
+	go func() {
+		defer close(out)
+		for done := range pending {
+			out <- <-done
+		}
+	}()
+	return out
 }
 
 // doList - list all entities inside a folder.
@@ -226,38 +523,64 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 		totalObjects      int64
 	)
 
-	for content := range clnt.List(ctx, ListOptions{
+	printVersions := func() {
+		msgs := printObjectVersions(clnt.GetURL(), perObjectVersions, o)
+		// Only summarize the versions matching the filter.
+		if o.versionFilter.any() {
+			for _, msg := range msgs {
+				totalSize += msg.Size
+				totalObjects++
+			}
+		}
+	}
+
+	contents := clnt.List(ctx, ListOptions{
 		Recursive:         o.isRecursive,
 		Incomplete:        o.isIncomplete,
 		TimeRef:           o.timeRef,
 		WithOlderVersions: o.withOlderVersions || !o.timeRef.IsZero(),
 		WithDeleteMarkers: true,
+		WithMetadata:      o.withMetadata,
 		ShowDir:           DirNone,
 		ListZip:           o.listZip,
-	}) {
+	})
+	if o.state.any() || o.withMetadata {
+		contents = lookupContents(contents, lsLookupWorkers, func(content *ClientContent) {
+			if content.Err != nil || o.isFiltered(content) || !o.needsLookup(content) {
+				return
+			}
+			if err := lookupObjectState(ctx, o.alias, content, o.withMetadata); err != nil {
+				errorIf(err.Trace(content.URL.String()), "Unable to get the state of the object.")
+			}
+		})
+	}
+
+	for content := range contents {
 		if content.Err != nil {
 			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
 			cErr = exitStatus(globalErrorExitStatus) // Set the exit status.
 			continue
 		}
 
-		if content.StorageClass != "" && o.filter != "" && o.filter != "*" && content.StorageClass != o.filter {
+		if o.isFiltered(content) {
 			continue
 		}
 
 		if lastPath != content.URL.Path {
 			// Print any object in the current list before reinitializing it
-			printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+			printVersions()
 			lastPath = content.URL.Path
 			perObjectVersions = []*ClientContent{}
 		}
 
 		perObjectVersions = append(perObjectVersions, content)
-		totalSize += content.Size
-		totalObjects++
+		if !o.versionFilter.any() {
+			totalSize += content.Size
+			totalObjects++
+		}
 	}
 
-	printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+	printVersions()
 
 	if o.isSummary {
 		printMsg(summaryMessage{
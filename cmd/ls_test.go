@@ -16,3 +16,119 @@
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestVersionFilter(t *testing.T) {
+	now := time.Now()
+	versions := []contentMessage{
+		{VersionID: "v4", IsLatest: true, IsDeleteMarker: true, Time: now.Add(-24 * time.Hour)},
+		{VersionID: "v3", Time: now.Add(-40 * 24 * time.Hour)},
+		{VersionID: "v2", IsDeleteMarker: true, Time: now.Add(-50 * 24 * time.Hour)},
+		{VersionID: "v1", Time: now.Add(-60 * 24 * time.Hour)},
+	}
+	testCases := []struct {
+		filter   versionFilter
+		expected []string
+	}{
+		{versionFilter{}, []string{"v4", "v3", "v2", "v1"}},
+		{versionFilter{onlyDeleteMarkers: true}, []string{"v4", "v2"}},
+		// v3 became noncurrent one day ago, when v4 was created.
+		{versionFilter{noncurrentOlderThan: 30 * 24 * time.Hour}, []string{"v2", "v1"}},
+		{versionFilter{noncurrentOlderThan: 45 * 24 * time.Hour}, []string{"v1"}},
+		{versionFilter{onlyDeleteMarkers: true, noncurrentOlderThan: 30 * 24 * time.Hour}, []string{"v2"}},
+	}
+	for i, testCase := range testCases {
+		filtered := testCase.filter.apply(versions, now)
+		var got []string
+		for _, v := range filtered {
+			got = append(got, v.VersionID)
+		}
+		if len(got) != len(testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+		for j := range got {
+			if got[j] != testCase.expected[j] {
+				t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+			}
+		}
+	}
+}
+
+func TestLookupContents(t *testing.T) {
+	in := make(chan *ClientContent)
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			in <- &ClientContent{Size: int64(i)}
+		}
+	}()
+
+	// Later contents are looked up faster, the order must be kept.
+	out := lookupContents(in, 8, func(content *ClientContent) {
+		time.Sleep(time.Duration(100-content.Size) * 10 * time.Microsecond)
+		content.ETag = "looked-up"
+	})
+	var i int64
+	for content := range out {
+		if content.Size != i {
+			t.Fatalf("expected content %d, got %d", i, content.Size)
+		}
+		if content.ETag != "looked-up" {
+			t.Fatalf("content %d was not looked up", i)
+		}
+		i++
+	}
+	if i != 100 {
+		t.Fatalf("expected 100 contents, got %d", i)
+	}
+}
+
+func TestObjectTier(t *testing.T) {
+	testCases := []struct {
+		storageClass string
+		tier         string
+	}{
+		{"", ""},
+		{"STANDARD", ""},
+		{"standard", ""},
+		{"Intelligent_Tiering", ""},
+		{"GLACIER_IR", ""},
+		{"EXPRESS_ONEZONE", ""},
+		{"GLACIER", "GLACIER"},
+		{"DEEP_ARCHIVE", "DEEP_ARCHIVE"},
+		// MinIO reports the name of the tier.
+		{"WARM-TIER", "WARM-TIER"},
+	}
+	for i, testCase := range testCases {
+		if tier := objectTier(testCase.storageClass); tier != testCase.tier {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.tier, tier)
+		}
+	}
+}
+
+func TestRestoreStatus(t *testing.T) {
+	expiry := time.Now().Add(24 * time.Hour)
+	testCases := []struct {
+		restore *minio.RestoreInfo
+		status  string
+	}{
+		{nil, ""},
+		{&minio.RestoreInfo{OngoingRestore: true}, "ongoing"},
+		{&minio.RestoreInfo{OngoingRestore: true, ExpiryTime: expiry}, "ongoing"},
+		{&minio.RestoreInfo{}, "restored"},
+		{&minio.RestoreInfo{ExpiryTime: expiry}, "restored until " + expiry.Local().Format(printDate)},
+		// The restored copy was removed.
+		{&minio.RestoreInfo{ExpiryTime: time.Now().Add(-time.Hour)}, ""},
+	}
+	for i, testCase := range testCases {
+		if status := restoreStatus(testCase.restore); status != testCase.status {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.status, status)
+		}
+	}
+}
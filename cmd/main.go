@@ -67,8 +67,13 @@ COMMANDS:
 GLOBAL FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}{{end}}
+EXIT STATUS:
+  0 success, 1 error, 7 drift detected, 130 canceled. With --porcelain: 2 usage error,
+  3 authentication failure, 4 not found, 5 partial transfer failure, 6 quota exceeded
+
 TIP:
-  Use '{{.Name}} --autocompletion' to enable shell autocompletion
+  Use '{{.Name}} --autocompletion' to enable shell autocompletion, or
+  '{{.Name}} completion SHELL' to generate a completion script
 
 COPYRIGHT:
   Copyright (c) 2015-` + CopyrightYear + ` MinIO, Inc.
@@ -137,8 +142,15 @@ func Main(args []string) error {
 	// Wait until the user quits the pager
 	defer globalHelpPager.WaitForExit()
 
+	// Run the exit hooks on every exit path.
+	hookExit()
+
 	// Run the app
-	return registerApp(appName).Run(args)
+	e := registerApp(appName).Run(args)
+	if e == nil {
+		runExitHooks(0)
+	}
+	return e
 }
 
 func flagValue(f cli.Flag) reflect.Value {
@@ -193,7 +205,7 @@ func onUsageError(ctx *cli.Context, err error, _ bool) error {
 			fmt.Fprintf(&errMsg, "   %s%s%s\n", h.flagName, spaces, h.usage)
 		}
 	}
-	console.Fatal(errMsg.String())
+	fatalExit(porcelainExitStatus(exitStatusUsage), strings.TrimSuffix(errMsg.String(), "\n"))
 	return err
 }
 
@@ -359,6 +371,17 @@ func installAutoCompletion() {
 }
 
 func registerBefore(ctx *cli.Context) error {
+	profile := ctx.String("profile")
+	if profile == "" {
+		profile = ctx.GlobalString("profile")
+	}
+	if profile != "" {
+		if !isValidConfigProfile(profile) {
+			fatalIf(errInvalidArgument().Trace(profile), "Invalid profile name `"+profile+"`.")
+		}
+		setMcConfigProfile(profile)
+	}
+
 	if ctx.IsSet("config-dir") {
 		// Set the config directory.
 		setMcConfigDir(ctx.String("config-dir"))
@@ -428,7 +451,12 @@ var appCmds = []cli.Command{
 	rmCmd,
 	mirrorCmd,
 	catCmd,
+	getCmd,
+	putCmd,
+	appendCmd,
+	composeCmd,
 	headCmd,
+	tailCmd,
 	pipeCmd,
 	findCmd,
 	sqlCmd,
@@ -448,8 +476,10 @@ var appCmds = []cli.Command{
 	watchCmd,
 	undoCmd,
 	anonymousCmd,
+	accessReportCmd,
 	policyCmd,
 	tagCmd,
+	metaCmd,
 	diffCmd,
 	replicateCmd,
 	adminCmd,
@@ -459,6 +489,20 @@ var appCmds = []cli.Command{
 	pingCmd,
 	odCmd,
 	batchCmd,
+	sessionCmd,
+	completionCmd,
+	browseCmd,
+	shellCmd,
+	daemonCmd,
+	mountCmd,
+	pluginCmd,
+	applyCmd,
+	checksumCmd,
+	verifyCmd,
+	corsCmd,
+	websiteCmd,
+	inventoryCmd,
+	migrateCmd,
 }
 
 func printMCVersion(c *cli.Context) {
@@ -495,6 +539,11 @@ func registerApp(name string) *cli.App {
 			showAppHelpAndExit(ctx)
 		}
 
+		// Commands which are not part of mc run the plugin of the same name.
+		if path, ok := lookPlugin(ctx.Args().First()); ok {
+			return runPlugin(ctx.Args().First(), path, ctx.Args().Tail())
+		}
+
 		commandNotFound(ctx, app.Commands)
 		return exitStatus(globalErrorExitStatus)
 	}
@@ -525,12 +574,18 @@ func showCommandHelpAndExit(cliCtx *cli.Context, code int) {
 	cli.ShowCommandHelp(cliCtx, cliCtx.Command.Name)
 	// Wait until the user quits the pager
 	globalHelpPager.WaitForExit()
-	os.Exit(code)
+	if code == globalErrorExitStatus {
+		code = porcelainExitStatus(exitStatusUsage)
+	}
+	runExitHooks(code)
+	exitProcess(code)
 }
 
 func showAppHelpAndExit(cliCtx *cli.Context) {
 	cli.ShowAppHelp(cliCtx)
 	// Wait until the user quits the pager
 	globalHelpPager.WaitForExit()
-	os.Exit(globalErrorExitStatus)
+	code := porcelainExitStatus(exitStatusUsage)
+	runExitHooks(code)
+	exitProcess(code)
 }
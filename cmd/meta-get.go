@@ -0,0 +1,154 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var metaGetCmd = cli.Command{
+	Name:         "get",
+	Usage:        "show the metadata of object(s)",
+	Action:       mainMetaGet,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(metaFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [KEY...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Show the standard headers (Cache-Control, Content-Disposition, Content-Encoding,
+  Content-Language, Content-Type, Expires) and the user metadata of objects, or only
+  the given keys.
+
+EXAMPLES:
+  1. Show the metadata of an object.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket/report.pdf
+
+  2. Show the Content-Type and the 'owner' user metadata of the objects of a prefix.
+     {{.Prompt}} {{.HelpName}} --recursive myminio/mybucket/reports/ Content-Type owner
+
+  3. Show the metadata of an object version in JSON format.
+     {{.Prompt}} {{.HelpName}} --json --version-id "ieQq7aXsyhlhDt47YURGlrucYY3GxWHa" myminio/mybucket/report.pdf
+`,
+}
+
+// metaGetMessage is the metadata of an object.
+type metaGetMessage struct {
+	Status    string            `json:"status"`
+	URL       string            `json:"url"`
+	VersionID string            `json:"versionID,omitempty"`
+	Metadata  map[string]string `json:"metadata"`
+}
+
+// Colorized message for console printing.
+func (m metaGetMessage) String() string {
+	keys := make([]string, 0, len(m.Metadata))
+	maxKeyLen := 4 // len("Name")
+	for key := range m.Metadata {
+		keys = append(keys, key)
+		if len(key) > maxKeyLen {
+			maxKeyLen = len(key)
+		}
+	}
+	sort.Strings(keys)
+
+	maxKeyLen += 2 // add len(" :")
+	name := m.URL
+	if m.VersionID != "" {
+		name += " (" + m.VersionID + ")"
+	}
+	lines := []string{
+		fmt.Sprintf("%v%*v %v", console.Colorize("Name", "Name"), maxKeyLen-4, ":", console.Colorize("Name", name)),
+	}
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%v%*v %v", console.Colorize("Key", key), maxKeyLen-len(key), ":", console.Colorize("Value", m.Metadata[key])))
+	}
+	if len(keys) == 0 {
+		lines = append(lines, console.Colorize("NoMeta", "No metadata found"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON'ified message for scripting.
+func (m metaGetMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainMetaGet is the handle for "mc meta get" command.
+func mainMetaGet(cliCtx *cli.Context) error {
+	ctx, cancelMetaGet := context.WithCancel(globalContext)
+	defer cancelMetaGet()
+
+	checkMetaSyntax(cliCtx, 1)
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	console.SetColor("Name", color.New(color.Bold, color.FgCyan))
+	console.SetColor("Key", color.New(color.FgBlue))
+	console.SetColor("Value", color.New(color.FgGreen))
+	console.SetColor("NoMeta", color.New(color.FgRed))
+
+	args := cliCtx.Args()
+	targetURL := args.First()
+	keys := make([]string, 0, len(args)-1)
+	for _, key := range args.Tail() {
+		keys = append(keys, metaKey(key))
+	}
+
+	failed := forEachMetaObject(ctx, cliCtx, targetURL, "get", encKeyDB, func(alias string, content *ClientContent) *probe.Error {
+		metadata := editableMetadata(content)
+		if len(keys) > 0 {
+			selected := map[string]string{}
+			for _, key := range keys {
+				if v, ok := metadata[key]; ok {
+					selected[key] = v
+				}
+			}
+			metadata = selected
+		}
+		printMsg(metaGetMessage{
+			URL:       urlJoinPath(alias, content.URL.Path),
+			VersionID: content.VersionID,
+			Metadata:  metadata,
+		})
+		return nil
+	})
+	if failed > 0 {
+		fatalIf(errDummy(), fmt.Sprintf("Unable to get the metadata of %d object(s).", failed))
+	}
+	return nil
+}
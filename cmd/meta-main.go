@@ -0,0 +1,197 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+var metaSubcommands = []cli.Command{
+	metaGetCmd,
+	metaSetCmd,
+	metaRemoveCmd,
+}
+
+var metaCmd = cli.Command{
+	Name:            "meta",
+	Usage:           "manage the metadata of object(s)",
+	Action:          mainMeta,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	Subcommands:     metaSubcommands,
+}
+
+func mainMeta(ctx *cli.Context) error {
+	commandNotFound(ctx, metaSubcommands)
+	return nil
+}
+
+// metaFlags select the objects of the meta commands.
+var metaFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "version-id, vid",
+		Usage: "select an object version",
+	},
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "apply to all the objects under the prefix",
+	},
+	cli.StringFlag{
+		Name:  "older-than",
+		Usage: "select objects older than value in duration string (e.g. 7d10h31s)",
+	},
+	cli.StringFlag{
+		Name:  "newer-than",
+		Usage: "select objects newer than value in duration string (e.g. 7d10h31s)",
+	},
+}
+
+// metaStandardHeaders are the standard headers of an object which are
+// stored with its metadata.
+var metaStandardHeaders = []string{
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Type",
+	"Expires",
+}
+
+// metaKey returns the header of a metadata key, standard headers are
+// kept and other keys are user metadata.
+func metaKey(key string) string {
+	key = http.CanonicalHeaderKey(strings.TrimSpace(key))
+	for _, h := range metaStandardHeaders {
+		if key == h {
+			return key
+		}
+	}
+	if strings.HasPrefix(key, "X-Amz-Meta-") {
+		return key
+	}
+	return "X-Amz-Meta-" + key
+}
+
+// editableMetadata returns the standard headers and the user metadata of
+// an object, the other headers are managed by the server.
+func editableMetadata(content *ClientContent) map[string]string {
+	metadata := map[string]string{}
+	for k, v := range content.Metadata {
+		k = http.CanonicalHeaderKey(k)
+		if metaKey(k) == k {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
+// checkMetaSyntax validates the arguments common to the meta commands.
+func checkMetaSyntax(ctx *cli.Context, minArgs int) {
+	if len(ctx.Args()) < minArgs {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+	if ctx.String("version-id") != "" && ctx.Bool("recursive") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--version-id cannot be used with --recursive.")
+	}
+	if (ctx.IsSet("older-than") || ctx.IsSet("newer-than")) && !ctx.Bool("recursive") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--older-than and --newer-than need --recursive.")
+	}
+}
+
+// forEachMetaObject calls fn with the objects selected by the arguments of a
+// meta command. With --recursive the errors of the objects are printed and
+// counted, otherwise they are fatal.
+func forEachMetaObject(ctx context.Context, cliCtx *cli.Context, targetURL, action string, encKeyDB map[string][]prefixSSEPair, fn func(alias string, content *ClientContent) *probe.Error) (failed int) {
+	alias, urlStr, _ := mustExpandAlias(targetURL)
+	clnt, err := newClientFromAlias(alias, urlStr)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+	if _, ok := clnt.(*S3Client); !ok {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Object metadata can only be managed on S3 targets.")
+	}
+
+	if !cliCtx.Bool("recursive") {
+		content, err := clnt.Stat(ctx, StatOptions{
+			versionID: cliCtx.String("version-id"),
+			preserve:  true,
+			sse:       getSSE(targetURL, encKeyDB[alias]),
+		})
+		if err == nil {
+			err = fn(alias, content)
+		}
+		fatalIf(err.Trace(targetURL), "Unable to "+action+" the metadata of `"+targetURL+"`.")
+		return 0
+	}
+
+	olderThan, newerThan := cliCtx.String("older-than"), cliCtx.String("newer-than")
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(targetURL), "Unable to list `"+targetURL+"`.")
+			failed++
+			continue
+		}
+		if content.Type.IsDir() || isOlder(content.Time, olderThan) || isNewer(content.Time, newerThan) {
+			continue
+		}
+		objectURL := content.URL.String()
+		objectClnt, err := newClientFromAlias(alias, objectURL)
+		if err == nil {
+			sse := getSSE(filepath.ToSlash(filepath.Join(alias, content.URL.Path)), encKeyDB[alias])
+			content, err = objectClnt.Stat(ctx, StatOptions{preserve: true, sse: sse})
+		}
+		if err == nil {
+			err = fn(alias, content)
+		}
+		if err != nil {
+			errorIf(err.Trace(objectURL), "Unable to "+action+" the metadata of `"+objectURL+"`.")
+			failed++
+		}
+	}
+	return failed
+}
+
+// replaceMetadata rewrites the standard headers and the user metadata of an
+// object with a server side copy onto itself, which creates a new version in
+// versioned buckets.
+func replaceMetadata(ctx context.Context, alias string, content *ClientContent, metadata map[string]string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+	urlStr := content.URL.String()
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return err.Trace(urlStr)
+	}
+	sse := getSSE(filepath.ToSlash(filepath.Join(alias, content.URL.Path)), encKeyDB[alias])
+	storageClass := content.StorageClass
+	if storageClass == "STANDARD" {
+		storageClass = ""
+	}
+	return clnt.Copy(ctx, filepath.ToSlash(content.URL.Path), CopyOptions{
+		versionID:       content.VersionID,
+		size:            content.Size,
+		srcSSE:          sse,
+		tgtSSE:          sse,
+		metadata:        metadata,
+		storageClass:    storageClass,
+		replaceMetadata: true,
+	}, nil)
+}
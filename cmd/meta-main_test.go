@@ -0,0 +1,59 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetaKey(t *testing.T) {
+	for key, want := range map[string]string{
+		"content-type":      "Content-Type",
+		"Cache-Control":     "Cache-Control",
+		"owner":             "X-Amz-Meta-Owner",
+		"x-amz-meta-owner":  "X-Amz-Meta-Owner",
+		" expires ":         "Expires",
+		"X-Amz-Storage-Tag": "X-Amz-Meta-X-Amz-Storage-Tag",
+	} {
+		if got := metaKey(key); got != want {
+			t.Errorf("metaKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestEditableMetadata(t *testing.T) {
+	content := &ClientContent{Metadata: map[string]string{
+		"Content-Type":      "text/plain",
+		"etag":              "abc",
+		"Last-Modified":     "Mon, 01 Jan 2024 00:00:00 GMT",
+		"x-amz-meta-owner":  "bob",
+		"X-Amz-Version-Id":  "v1",
+		"Content-Length":    "6",
+		"Content-Encoding":  "gzip",
+		"X-Amz-Server-Side": "AES256",
+	}}
+	want := map[string]string{
+		"Content-Type":     "text/plain",
+		"Content-Encoding": "gzip",
+		"X-Amz-Meta-Owner": "bob",
+	}
+	if got := editableMetadata(content); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
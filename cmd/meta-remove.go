@@ -0,0 +1,71 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+)
+
+var metaRemoveCmd = cli.Command{
+	Name:         "remove",
+	Usage:        "remove metadata of object(s)",
+	Action:       mainMetaRemove,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(metaFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET KEY [KEY...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Remove standard headers and user metadata of existing objects, keeping their other metadata.
+  The objects are copied onto themselves on the server, which creates a new version of the
+  objects in versioned buckets.
+
+EXAMPLES:
+  1. Remove the 'owner' user metadata of an object.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket/report.pdf owner
+
+  2. Remove the Cache-Control and the Expires headers of the objects of a prefix.
+     {{.Prompt}} {{.HelpName}} --recursive myminio/mybucket/site/ Cache-Control Expires
+`,
+}
+
+// mainMetaRemove is the handle for "mc meta remove" command.
+func mainMetaRemove(cliCtx *cli.Context) error {
+	checkMetaSyntax(cliCtx, 2)
+	var keys []string
+	for _, key := range cliCtx.Args().Tail() {
+		keys = append(keys, metaKey(key))
+	}
+
+	return updateMetadata(cliCtx, func(metadata map[string]string) (changed bool) {
+		for _, key := range keys {
+			if _, ok := metadata[key]; ok {
+				delete(metadata, key)
+				changed = true
+			}
+		}
+		return changed
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var metaSetCmd = cli.Command{
+	Name:         "set",
+	Usage:        "set the metadata of object(s)",
+	Action:       mainMetaSet,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(metaFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET KEY=VALUE[;KEY=VALUE...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Set standard headers (Cache-Control, Content-Disposition, Content-Encoding, Content-Language,
+  Content-Type, Expires) and user metadata of existing objects, keeping their other metadata.
+  Other keys are user metadata, with or without the 'X-Amz-Meta-' prefix.
+
+  The objects are copied onto themselves on the server, which creates a new version of the
+  objects in versioned buckets.
+
+EXAMPLES:
+  1. Set the Content-Type and the Cache-Control of an object.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket/index.html "Content-Type=text/html;Cache-Control=max-age=3600"
+
+  2. Set the 'owner' user metadata of the objects of a prefix modified in the last 7 days.
+     {{.Prompt}} {{.HelpName}} --recursive --newer-than 7d myminio/mybucket/reports/ "owner=finance"
+`,
+}
+
+// metaSetMessage is the updated metadata of an object.
+type metaSetMessage struct {
+	Status    string            `json:"status"`
+	URL       string            `json:"url"`
+	VersionID string            `json:"versionID,omitempty"`
+	Metadata  map[string]string `json:"metadata"`
+	Unchanged bool              `json:"unchanged,omitempty"`
+}
+
+// Colorized message for console printing.
+func (m metaSetMessage) String() string {
+	if m.Unchanged {
+		return console.Colorize("MetaUnchanged", fmt.Sprintf("Metadata of `%s` is unchanged.", m.URL))
+	}
+	return console.Colorize("MetaSet", fmt.Sprintf("Metadata of `%s` updated.", m.URL))
+}
+
+// JSON'ified message for scripting.
+func (m metaSetMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// updateMetadata applies edit to the metadata of the objects selected by the
+// arguments of a meta command, objects with unchanged metadata are not copied.
+func updateMetadata(cliCtx *cli.Context, edit func(metadata map[string]string) bool) error {
+	ctx, cancelMeta := context.WithCancel(globalContext)
+	defer cancelMeta()
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	console.SetColor("MetaSet", color.New(color.FgGreen))
+	console.SetColor("MetaUnchanged", color.New(color.FgYellow))
+
+	failed := forEachMetaObject(ctx, cliCtx, cliCtx.Args().First(), "update", encKeyDB, func(alias string, content *ClientContent) *probe.Error {
+		metadata := editableMetadata(content)
+		msg := metaSetMessage{
+			URL:       urlJoinPath(alias, content.URL.Path),
+			VersionID: content.VersionID,
+			Metadata:  metadata,
+		}
+		if !edit(metadata) {
+			msg.Unchanged = true
+			printMsg(msg)
+			return nil
+		}
+		if err := replaceMetadata(ctx, alias, content, metadata, encKeyDB); err != nil {
+			return err
+		}
+		printMsg(msg)
+		return nil
+	})
+	if failed > 0 {
+		fatalIf(errDummy(), fmt.Sprintf("Unable to update the metadata of %d object(s).", failed))
+	}
+	return nil
+}
+
+// mainMetaSet is the handle for "mc meta set" command.
+func mainMetaSet(cliCtx *cli.Context) error {
+	checkMetaSyntax(cliCtx, 2)
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, globalErrorExitStatus)
+	}
+	entries, err := getMetaDataEntry(cliCtx.Args().Get(1))
+	fatalIf(err.Trace(cliCtx.Args().Get(1)), "Unable to parse the metadata.")
+	values := make(map[string]string, len(entries))
+	for k, v := range entries {
+		values[metaKey(k)] = v
+	}
+
+	return updateMetadata(cliCtx, func(metadata map[string]string) (changed bool) {
+		for k, v := range values {
+			if old, ok := metadata[k]; !ok || old != v {
+				metadata[k] = v
+				changed = true
+			}
+		}
+		return changed
+	})
+}
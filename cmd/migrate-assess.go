@@ -0,0 +1,540 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"github.com/minio/pkg/console"
+)
+
+var migrateAssessFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "bandwidth",
+		Value: "100MiB",
+		Usage: "bandwidth available to the migration per second, e.g. 1GiB",
+	},
+	cli.IntFlag{
+		Name:  "sample",
+		Value: 100,
+		Usage: "number of objects checked for ACLs, encryption and tags, with a request each",
+	},
+}
+
+var migrateAssessCmd = cli.Command{
+	Name:         "assess",
+	Usage:        "assess the migration of a bucket",
+	Action:       mainMigrateAssess,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(migrateAssessFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] SOURCE TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  List the bucket or the prefix SOURCE and report the distribution of the sizes
+  and the storage classes of its objects, its versions and the features it uses:
+  versioning, object lock, default encryption, lifecycle, policy and ACLs. The
+  ACLs, the encryption and the tags of the objects are checked on a random sample.
+
+  The features are compared with the ones of TARGET, which is not modified, to
+  report what a migration would lose. The duration is the one of the transfer
+  of the data at the given bandwidth, a lower bound. The report ends with the
+  recommended mirror invocation.
+
+EXAMPLES:
+  1. Assess the migration of a bucket of AWS S3 to MinIO over a 1 Gbit/s link.
+     {{.Prompt}} {{.HelpName}} --bandwidth 125MB s3/mybucket myminio/mybucket
+
+  2. Assess the migration of a prefix, checking 1000 objects for ACLs, encryption and tags.
+     {{.Prompt}} {{.HelpName}} --sample 1000 s3/mybucket/logs/ myminio/logs/
+`,
+}
+
+// migrateSizeRanges are the upper bounds of the ranges of the size
+// distribution, the ranges of the bucket usage of MinIO.
+var migrateSizeRanges = []struct {
+	name  string
+	limit int64
+}{
+	{"< 1 KiB", humanize.KiByte},
+	{"1 KiB - 1 MiB", humanize.MiByte},
+	{"1 MiB - 10 MiB", 10 * humanize.MiByte},
+	{"10 MiB - 64 MiB", 64 * humanize.MiByte},
+	{"64 MiB - 128 MiB", 128 * humanize.MiByte},
+	{"128 MiB - 512 MiB", 512 * humanize.MiByte},
+	{">= 512 MiB", -1},
+}
+
+// migrateArchiveClasses are the storage classes of AWS S3 which must be
+// restored before their objects can be read.
+var migrateArchiveClasses = map[string]bool{
+	"GLACIER":      true,
+	"DEEP_ARCHIVE": true,
+}
+
+// migrateTotal counts objects and their size.
+type migrateTotal struct {
+	Count int64 `json:"count"`
+	Size  int64 `json:"size"`
+}
+
+// migrateRange is a range of the size distribution.
+type migrateRange struct {
+	Range string `json:"range"`
+	migrateTotal
+}
+
+// migrateBucketFeatures are the features of a bucket a migration depends
+// on, Exists is false for the targets created by the migration.
+type migrateBucketFeatures struct {
+	Exists     bool   `json:"exists"`
+	AWS        bool   `json:"aws"`
+	Versioning string `json:"versioning,omitempty"`
+	ObjectLock bool   `json:"objectLock"`
+	Encryption string `json:"encryption,omitempty"`
+	Lifecycle  bool   `json:"lifecycle"`
+	Policy     string `json:"policy,omitempty"`
+	PublicACL  bool   `json:"publicACL"`
+}
+
+// migrateSample counts the features of the sampled objects.
+type migrateSample struct {
+	Objects   int `json:"objects"`
+	PublicACL int `json:"publicACL"`
+	Grants    int `json:"grants"`
+	SSEKMS    int `json:"sseKMS"`
+	SSES3     int `json:"sseS3"`
+	SSEC      int `json:"sseC"`
+	Tagged    int `json:"tagged"`
+}
+
+// migrateAssessMessage container for the assessment of a migration.
+type migrateAssessMessage struct {
+	Status            string                   `json:"status"`
+	Source            string                   `json:"source"`
+	Target            string                   `json:"target"`
+	Objects           migrateTotal             `json:"objects"`
+	Versions          migrateTotal             `json:"versions"`
+	DeleteMarkers     int64                    `json:"deleteMarkers"`
+	Sizes             []migrateRange           `json:"sizes"`
+	StorageClasses    map[string]*migrateTotal `json:"storageClasses"`
+	SourceFeatures    migrateBucketFeatures    `json:"sourceFeatures"`
+	TargetFeatures    migrateBucketFeatures    `json:"targetFeatures"`
+	Sample            migrateSample            `json:"sample"`
+	Bandwidth         uint64                   `json:"bandwidth"`
+	Duration          time.Duration            `json:"duration"`
+	Incompatibilities []string                 `json:"incompatibilities,omitempty"`
+	Recommendation    string                   `json:"recommendation"`
+}
+
+func (m migrateAssessMessage) String() string {
+	var b strings.Builder
+	line := func(key, format string, args ...interface{}) {
+		fmt.Fprintf(&b, "  %-16s: %s\n", key, fmt.Sprintf(format, args...))
+	}
+	fmt.Fprintln(&b, console.Colorize("MigrateTitle", "Source `"+m.Source+"`:"))
+	line("Objects", "%d, %s", m.Objects.Count, humanize.IBytes(uint64(m.Objects.Size)))
+	if m.SourceFeatures.Versioning != "" {
+		line("Older versions", "%d, %s", m.Versions.Count, humanize.IBytes(uint64(m.Versions.Size)))
+		line("Delete markers", "%d", m.DeleteMarkers)
+	}
+	for _, r := range m.Sizes {
+		if r.Count > 0 {
+			line(r.Range, "%d, %s", r.Count, humanize.IBytes(uint64(r.Size)))
+		}
+	}
+	classes := make([]string, 0, len(m.StorageClasses))
+	for class := range m.StorageClasses {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		line(class, "%d, %s", m.StorageClasses[class].Count, humanize.IBytes(uint64(m.StorageClasses[class].Size)))
+	}
+	m.SourceFeatures.write(&b)
+	if s := m.Sample; s.Objects > 0 {
+		line("Sampled", "%d object(s): %d public, %d with other grants, %d SSE-KMS, %d SSE-S3, %d SSE-C, %d tagged",
+			s.Objects, s.PublicACL, s.Grants, s.SSEKMS, s.SSES3, s.SSEC, s.Tagged)
+	}
+
+	fmt.Fprintln(&b, console.Colorize("MigrateTitle", "Target `"+m.Target+"`:"))
+	if !m.TargetFeatures.Exists {
+		line("Bucket", "created by the migration")
+	} else {
+		m.TargetFeatures.write(&b)
+	}
+
+	fmt.Fprintln(&b, console.Colorize("MigrateTitle", "Migration:"))
+	line("Duration", "%s at %s/s", m.Duration.Round(time.Second), humanize.IBytes(m.Bandwidth))
+	for _, incompatibility := range m.Incompatibilities {
+		fmt.Fprintln(&b, console.Colorize("MigrateWarning", "  - "+incompatibility))
+	}
+	line("Recommended", "%s", m.Recommendation)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (f migrateBucketFeatures) write(b *strings.Builder) {
+	line := func(key, value string) {
+		fmt.Fprintf(b, "  %-16s: %s\n", key, value)
+	}
+	versioning := f.Versioning
+	if versioning == "" {
+		versioning = "Off"
+	}
+	line("Versioning", versioning)
+	line("Object lock", strconv.FormatBool(f.ObjectLock))
+	if f.Encryption != "" {
+		line("Encryption", f.Encryption)
+	}
+	line("Lifecycle", strconv.FormatBool(f.Lifecycle))
+	if f.Policy != "" {
+		line("Anonymous", f.Policy)
+	}
+	if f.PublicACL {
+		line("Bucket ACL", "public")
+	}
+}
+
+func (m migrateAssessMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// add counts a current object in the totals, the size distribution and the
+// storage classes.
+func (m *migrateAssessMessage) add(content *ClientContent) {
+	m.Objects.Count++
+	m.Objects.Size += content.Size
+	for i, r := range migrateSizeRanges {
+		if r.limit < 0 || content.Size < r.limit {
+			m.Sizes[i].Count++
+			m.Sizes[i].Size += content.Size
+			break
+		}
+	}
+	class := content.StorageClass
+	if class == "" {
+		class = "STANDARD"
+	}
+	total, ok := m.StorageClasses[class]
+	if !ok {
+		total = &migrateTotal{}
+		m.StorageClasses[class] = total
+	}
+	total.Count++
+	total.Size += content.Size
+}
+
+// migrateDuration returns the time needed to transfer size bytes at the
+// bandwidth, in bytes per second.
+func migrateDuration(size int64, bandwidth uint64) time.Duration {
+	if bandwidth == 0 {
+		return 0
+	}
+	return time.Duration(float64(size) / float64(bandwidth) * float64(time.Second))
+}
+
+// migrateParallel returns the number of concurrent transfers for an average
+// object size, small objects are bound by the latency of the requests.
+func migrateParallel(objects migrateTotal) int {
+	if objects.Count == 0 {
+		return 8
+	}
+	switch average := objects.Size / objects.Count; {
+	case average < humanize.MiByte:
+		return 64
+	case average < 16*humanize.MiByte:
+		return 32
+	case average < 128*humanize.MiByte:
+		return 16
+	}
+	return 8
+}
+
+// assess reports what the migration would lose and the mirror invocation
+// which keeps as much as possible.
+func (m *migrateAssessMessage) assess() {
+	var issues []string
+	src, tgt := m.SourceFeatures, m.TargetFeatures
+
+	var archived, unsupported []string
+	for class, total := range m.StorageClasses {
+		switch {
+		case migrateArchiveClasses[class]:
+			archived = append(archived, fmt.Sprintf("%d in %s", total.Count, class))
+		case !tgt.AWS && class != "STANDARD" && class != "REDUCED_REDUNDANCY":
+			unsupported = append(unsupported, class)
+		}
+	}
+	sort.Strings(archived)
+	sort.Strings(unsupported)
+	if len(archived) > 0 {
+		issues = append(issues, "objects must be restored with `mc ilm restore` before they can be copied: "+strings.Join(archived, ", "))
+	}
+	if len(unsupported) > 0 {
+		issues = append(issues, "storage classes unsupported by the target, stored as STANDARD: "+strings.Join(unsupported, ", "))
+	}
+
+	if src.Versioning != "" && (m.Versions.Count > 0 || m.DeleteMarkers > 0) {
+		issues = append(issues, fmt.Sprintf("mirror copies the latest versions only, %d older version(s) and %d delete marker(s) are left behind",
+			m.Versions.Count, m.DeleteMarkers))
+	}
+	if src.Versioning == "Enabled" && tgt.Versioning != "Enabled" {
+		issues = append(issues, "the target is not versioned, enable it with `mc version enable`")
+	}
+	if src.ObjectLock && !tgt.ObjectLock {
+		issues = append(issues, "the source uses object lock, the target bucket must be created with `mc mb --with-lock` to keep the retentions")
+	}
+	if src.Lifecycle && !tgt.Lifecycle {
+		issues = append(issues, "the lifecycle rules are not migrated, copy them with `mc ilm rule export` and `mc ilm rule import`")
+	}
+	if m.Sample.SSEKMS > 0 && !strings.Contains(tgt.Encryption, "kms") {
+		issues = append(issues, "SSE-KMS objects are decrypted by the source and stored unencrypted unless the target has a KMS default encryption, set it with `mc encrypt set sse-kms`")
+	}
+	if m.Sample.SSEC > 0 {
+		issues = append(issues, "SSE-C objects can only be copied with their keys, pass them with --encrypt-key")
+	}
+	if (src.PublicACL || m.Sample.PublicACL > 0) && !tgt.AWS {
+		issues = append(issues, "the target doesn't support ACLs, map the public reads to policies with `mc cp --recursive --map-acl-to-policy`")
+	}
+	if m.Sample.Grants > 0 {
+		issues = append(issues, "ACL grants to other accounts have no equivalent, they must be replaced by IAM policies")
+	}
+	m.Incompatibilities = issues
+
+	args := []string{"mc", "mirror"}
+	if src.ObjectLock || (src.Policy != "" && src.Policy != "none") {
+		args = append(args, "--preserve")
+	}
+	if len(unsupported) > 0 {
+		args = append(args, "--storage-class", "STANDARD")
+	}
+	args = append(args, "--parallel", strconv.Itoa(migrateParallel(m.Objects)), m.Source, m.Target)
+	m.Recommendation = strings.Join(args, " ")
+}
+
+// newMigrateBucketClient returns the client of the bucket of an URL, the
+// configurations of buckets are read from their root.
+func newMigrateBucketClient(aliasedURL string) (Client, *probe.Error) {
+	clnt, err := newClient(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	u := clnt.GetURL()
+	if u.Type != objectStorage {
+		return clnt, nil
+	}
+	bucket, _ := url2BucketAndObject(&u)
+	if bucket == "" {
+		return nil, errInvalidArgument().Trace(aliasedURL)
+	}
+	u.Path = string(u.Separator) + bucket
+	alias, _, _ := mustExpandAlias(aliasedURL)
+	return newClientFromAlias(alias, u.String())
+}
+
+// migrateFeatures reads the features of a bucket, the configurations which
+// cannot be read are reported as absent.
+func migrateFeatures(ctx context.Context, clnt Client) (features migrateBucketFeatures, err *probe.Error) {
+	u := clnt.GetURL()
+	if u.Type != objectStorage {
+		_, err = clnt.Stat(ctx, StatOptions{})
+		features.Exists = err == nil
+		return features, nil
+	}
+	features.AWS = s3utils.IsAmazonEndpoint(url.URL{Host: u.Host})
+	versioning, err := clnt.GetVersion(ctx)
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchBucket" {
+			return features, nil
+		}
+		if _, ok := err.ToGoError().(BucketDoesNotExist); ok {
+			return features, nil
+		}
+		return features, err
+	}
+	features.Exists = true
+	features.Versioning = versioning.Status
+	if status, _, _, _, err := clnt.GetObjectLockConfig(ctx); err == nil {
+		features.ObjectLock = status == "Enabled"
+	}
+	if algorithm, _, err := clnt.GetEncryption(ctx); err == nil {
+		features.Encryption = algorithm
+	}
+	if config, err := clnt.GetLifecycle(ctx); err == nil && config != nil {
+		features.Lifecycle = len(config.Rules) > 0
+	}
+	if perm, _, err := clnt.GetAccess(ctx); err == nil {
+		features.Policy = string(stringToAccessPerm(perm))
+	}
+	if s3Clnt, ok := clnt.(*S3Client); ok {
+		if acl, err := s3Clnt.GetACL(ctx, ""); err == nil {
+			features.PublicACL, _ = mapACL(acl)
+		}
+	}
+	return features, nil
+}
+
+// sampleObject checks the ACL, the encryption and the tags of an object.
+func (s *migrateSample) sampleObject(ctx context.Context, alias string, content *ClientContent) {
+	clnt, err := newClientFromAlias(alias, content.URL.String())
+	if err != nil {
+		return
+	}
+	s.Objects++
+	st, err := clnt.Stat(ctx, StatOptions{versionID: content.VersionID})
+	switch {
+	case err != nil:
+		// HEAD fails with 400 without the key of SSE-C objects.
+		if minio.ToErrorResponse(err.ToGoError()).StatusCode == 400 {
+			s.SSEC++
+		}
+	default:
+		for k, v := range st.Metadata {
+			switch strings.ToLower(k) {
+			case serverEncryptionKeyPrefix:
+				if strings.HasPrefix(v, "aws:kms") {
+					s.SSEKMS++
+				} else {
+					s.SSES3++
+				}
+			case serverEncryptionKeyPrefix + "-customer-algorithm":
+				s.SSEC++
+			}
+		}
+	}
+	if tags, err := clnt.GetTags(ctx, content.VersionID); err == nil && len(tags) > 0 {
+		s.Tagged++
+	}
+	if s3Clnt, ok := clnt.(*S3Client); ok {
+		if acl, err := s3Clnt.GetACL(ctx, content.VersionID); err == nil {
+			public, unmapped := mapACL(acl)
+			if public {
+				s.PublicACL++
+			}
+			for _, grant := range unmapped {
+				if grant.Grantee != "AllUsers" {
+					s.Grants++
+					break
+				}
+			}
+		}
+	}
+}
+
+// mainMigrateAssess is the handle for "mc migrate assess" command.
+func mainMigrateAssess(cliCtx *cli.Context) error {
+	ctx, cancelMigrateAssess := context.WithCancel(globalContext)
+	defer cancelMigrateAssess()
+
+	console.SetColor("MigrateTitle", color.New(color.Bold))
+	console.SetColor("MigrateWarning", color.New(color.FgYellow))
+
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	sourceURL, targetURL := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+	bandwidth, e := humanize.ParseBytes(strings.TrimSuffix(cliCtx.String("bandwidth"), "/s"))
+	fatalIf(probe.NewError(e).Trace(cliCtx.String("bandwidth")), "Invalid --bandwidth.")
+	sampleSize := cliCtx.Int("sample")
+	if sampleSize < 0 {
+		fatalIf(errInvalidArgument().Trace(strconv.Itoa(sampleSize)), "--sample cannot be negative.")
+	}
+
+	srcClnt, err := newClient(sourceURL)
+	fatalIf(err.Trace(sourceURL), "Unable to initialize source `"+sourceURL+"`.")
+	if _, ok := srcClnt.(*S3Client); !ok {
+		fatalIf(errInvalidArgument().Trace(sourceURL), "The source must be a bucket or a prefix on an object storage.")
+	}
+	srcBucketClnt, err := newMigrateBucketClient(sourceURL)
+	fatalIf(err.Trace(sourceURL), "The source must be a bucket or a prefix on an object storage.")
+	tgtBucketClnt, err := newMigrateBucketClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+
+	msg := migrateAssessMessage{
+		Source:         sourceURL,
+		Target:         targetURL,
+		Sizes:          make([]migrateRange, len(migrateSizeRanges)),
+		StorageClasses: map[string]*migrateTotal{},
+		Bandwidth:      bandwidth,
+	}
+	for i, r := range migrateSizeRanges {
+		msg.Sizes[i].Range = r.name
+	}
+	msg.SourceFeatures, err = migrateFeatures(ctx, srcBucketClnt)
+	fatalIf(err.Trace(sourceURL), "Unable to read the configuration of the source.")
+	if !msg.SourceFeatures.Exists {
+		fatalIf(probe.NewError(BucketDoesNotExist{}).Trace(sourceURL), "The source bucket doesn't exist.")
+	}
+	msg.TargetFeatures, err = migrateFeatures(ctx, tgtBucketClnt)
+	fatalIf(err.Trace(targetURL), "Unable to read the configuration of the target.")
+
+	// The sample is drawn uniformly from the current objects with a
+	// reservoir, as their number is only known once they are listed.
+	var sample []*ClientContent
+	versioned := msg.SourceFeatures.Versioning != ""
+	for content := range srcClnt.List(ctx, ListOptions{Recursive: true, WithOlderVersions: versioned, WithDeleteMarkers: versioned, ShowDir: DirNone}) {
+		fatalIf(content.Err.Trace(sourceURL), "Unable to list the source.")
+		switch {
+		case content.IsDeleteMarker:
+			msg.DeleteMarkers++
+		case versioned && !content.IsLatest:
+			msg.Versions.Count++
+			msg.Versions.Size += content.Size
+		default:
+			msg.add(content)
+			if len(sample) < sampleSize {
+				sample = append(sample, content)
+			} else if i := rand.Int63n(msg.Objects.Count); i < int64(sampleSize) {
+				sample[i] = content
+			}
+		}
+	}
+	alias, _, _ := mustExpandAlias(sourceURL)
+	for _, content := range sample {
+		msg.Sample.sampleObject(ctx, alias, content)
+	}
+
+	msg.Duration = migrateDuration(msg.Objects.Size, bandwidth)
+	msg.assess()
+	printMsg(msg)
+	return nil
+}
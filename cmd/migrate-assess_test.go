@@ -0,0 +1,97 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+func TestMigrateAssess(t *testing.T) {
+	msg := migrateAssessMessage{
+		Source:         "s3/mybucket",
+		Target:         "myminio/mybucket",
+		Sizes:          make([]migrateRange, len(migrateSizeRanges)),
+		StorageClasses: map[string]*migrateTotal{},
+	}
+	for _, content := range []*ClientContent{
+		{Size: 100},
+		{Size: 2 * humanize.MiByte, StorageClass: "STANDARD_IA"},
+		{Size: 600 * humanize.MiByte, StorageClass: "GLACIER"},
+	} {
+		msg.add(content)
+	}
+	if msg.Objects != (migrateTotal{3, 100 + 602*humanize.MiByte}) {
+		t.Fatalf("unexpected totals %+v", msg.Objects)
+	}
+	var counts []int64
+	for _, r := range msg.Sizes {
+		counts = append(counts, r.Count)
+	}
+	if want := []int64{1, 0, 1, 0, 0, 0, 1}; !reflect.DeepEqual(counts, want) {
+		t.Fatalf("expected the size distribution %v, got %v", want, counts)
+	}
+	if msg.StorageClasses["STANDARD"].Count != 1 || msg.StorageClasses["GLACIER"].Size != 600*humanize.MiByte {
+		t.Fatalf("unexpected storage classes %v", msg.StorageClasses)
+	}
+
+	msg.SourceFeatures = migrateBucketFeatures{Exists: true, AWS: true, Versioning: "Enabled", ObjectLock: true, PublicACL: true}
+	msg.TargetFeatures = migrateBucketFeatures{Exists: true, Versioning: "Enabled"}
+	msg.Sample = migrateSample{Objects: 3, SSEKMS: 1}
+	msg.assess()
+	wants := []string{"mc ilm restore", "STANDARD_IA", "--with-lock", "sse-kms", "--map-acl-to-policy"}
+	if len(msg.Incompatibilities) != len(wants) {
+		t.Fatalf("expected %d incompatibilities, got %q", len(wants), msg.Incompatibilities)
+	}
+	for i, want := range wants {
+		if !strings.Contains(msg.Incompatibilities[i], want) {
+			t.Errorf("expected %q in %q", want, msg.Incompatibilities[i])
+		}
+	}
+	if want := "mc mirror --preserve --storage-class STANDARD --parallel 8 s3/mybucket myminio/mybucket"; msg.Recommendation != want {
+		t.Errorf("expected %q, got %q", want, msg.Recommendation)
+	}
+}
+
+func TestMigrateEstimates(t *testing.T) {
+	if d := migrateDuration(10*humanize.GiByte, 100*humanize.MiByte); d != 102400*time.Millisecond {
+		t.Errorf("expected 1m42.4s, got %s", d)
+	}
+	if d := migrateDuration(humanize.GiByte, 0); d != 0 {
+		t.Errorf("expected no duration without bandwidth, got %s", d)
+	}
+	testCases := []struct {
+		objects  migrateTotal
+		parallel int
+	}{
+		{migrateTotal{}, 8},
+		{migrateTotal{1000, 1000 * 100 * humanize.KiByte}, 64},
+		{migrateTotal{10, 10 * 4 * humanize.MiByte}, 32},
+		{migrateTotal{10, 10 * 100 * humanize.MiByte}, 16},
+		{migrateTotal{2, 2 * humanize.GiByte}, 8},
+	}
+	for i, testCase := range testCases {
+		if parallel := migrateParallel(testCase.objects); parallel != testCase.parallel {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.parallel, parallel)
+		}
+	}
+}
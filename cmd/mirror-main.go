@@ -63,6 +63,18 @@ var (
 			Name:  "dry-run",
 			Usage: "perform a fake mirror operation",
 		},
+		cli.StringFlag{
+			Name:  "plan",
+			Usage: "write the operations of a dry run to a replayable JSON plan, implies --dry-run",
+		},
+		cli.StringFlag{
+			Name:  "apply-plan",
+			Usage: "execute the operations of a plan written by --plan",
+		},
+		cli.BoolFlag{
+			Name:  "staging",
+			Usage: "mirror to a new release prefix of target, then promote it atomically by updating the 'current' pointer object",
+		},
 		cli.BoolFlag{
 			Name:  "watch, w",
 			Usage: "watch and synchronize changes",
@@ -113,6 +125,10 @@ var (
 			Name:  "storage-class, sc",
 			Usage: "specify storage class for new object(s) on target",
 		},
+		cli.StringFlag{
+			Name:  "storage-class-map",
+			Usage: "specify the storage class of the objects matching patterns, e.g. '*.log=REDUCED_REDUNDANCY,*.parquet=STANDARD'",
+		},
 		cli.StringFlag{
 			Name:  "encrypt",
 			Usage: "encrypt/decrypt objects (using server-side encryption with server managed keys)",
@@ -125,6 +141,12 @@ var (
 			Name:  "monitoring-address",
 			Usage: "if specified, a new prometheus endpoint will be created to report mirroring activity. (eg: localhost:8081)",
 		},
+		transformFlag,
+		parallelFlag,
+		maxDeleteFlag,
+		maxCopyBytesFlag,
+		noLimitFlag,
+		notifyOnCompletionFlag,
 	}
 )
 
@@ -141,13 +163,16 @@ var mirrorCmd = cli.Command{
 
 USAGE:
   {{.HelpName}} [FLAGS] SOURCE TARGET
+  {{.HelpName}} [FLAGS] --apply-plan FILE
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-   MC_ENCRYPT:      list of comma delimited prefixes
-   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+   MC_ENCRYPT:         list of comma delimited prefixes
+   MC_ENCRYPT_KEY:     list of comma delimited prefix=secret values
+   MC_MAX_DELETE:      default of --max-delete, lifted with --no-limit
+   MC_MAX_COPY_BYTES:  default of --max-copy-bytes, lifted with --no-limit
 
 EXAMPLES:
   01. Mirror a bucket recursively from MinIO cloud storage to a bucket on Amazon S3 cloud storage.
@@ -202,6 +227,33 @@ EXAMPLES:
   16. Cross mirror between sites in a active-active deployment.
       Site-A: {{.Prompt}} {{.HelpName}} --active-active siteA siteB
       Site-B: {{.Prompt}} {{.HelpName}} --active-active siteB siteA
+
+  17. Mirror a bucket with 64 concurrent transfers, the connection pool grows to match.
+      {{.Prompt}} {{.HelpName}} --parallel 64 play/photos s3/backup-photos
+
+  18. Mirror a bucket with the logs in the REDUCED_REDUNDANCY storage class and the parquet files in STANDARD.
+      {{.Prompt}} {{.HelpName}} --storage-class-map '*.log=REDUCED_REDUNDANCY,*.parquet=STANDARD' play/datalake s3/datalake
+
+  19. Mirror a folder compressing every file with gzip. The objects record their source, the files changed
+      since are transformed again with --overwrite.
+      {{.Prompt}} {{.HelpName}} --transform gzip ./logs s3/archive/logs
+
+  20. Simulate mirroring a bucket, listing the copies, overwrites, metadata and tags updates and deletes with their totals.
+      {{.Prompt}} {{.HelpName}} --dry-run --overwrite --remove -a play/photos s3/backup-photos
+
+  21. Write the operations of a dry run to a plan, review it, then execute it later.
+      {{.Prompt}} {{.HelpName}} --plan plan.json --overwrite --remove play/photos s3/backup-photos
+      {{.Prompt}} {{.HelpName}} --apply-plan plan.json
+
+  22. Deploy a static site to a new release under 's3/site/releases/', then point 's3/site/current' to it once fully uploaded.
+      {{.Prompt}} {{.HelpName}} --staging ./public s3/site
+
+  23. Mirror a bucket removing the extraneous objects, aborting if more than 1000 objects would be removed
+      or more than 1 TiB copied. The budgets apply to the initial mirror, not to the events of --watch.
+      {{.Prompt}} {{.HelpName}} --remove --max-delete 1000 --max-copy-bytes 1TiB s3/mybucket myminio/mybucket
+
+  24. Mirror a bucket and send an email with the "oncall" notify profile of the config when it finishes or fails.
+      {{.Prompt}} {{.HelpName}} --notify-on-completion oncall s3/mybucket myminio/mybucket
 `,
 }
 
@@ -256,6 +308,11 @@ type mirrorJob struct {
 	targetURL string
 
 	opts mirrorOptions
+
+	// plan counts the operations of a dry run.
+	plan *mirrorPlan
+	// replay is the plan given to --apply-plan.
+	replay *mirrorPlan
 }
 
 // mirrorMessage container for file mirror messages
@@ -363,6 +420,35 @@ func (mj *mirrorJob) doRemove(ctx context.Context, sURLs URLs) URLs {
 	return sURLs.WithError(nil)
 }
 
+// doTags - sets the tags of the source object on target.
+func (mj *mirrorJob) doTags(ctx context.Context, sURLs URLs) URLs {
+	if sURLs.Error != nil { // Erroneous sURLs passed.
+		return sURLs.WithError(sURLs.Error.Trace())
+	}
+	if mj.opts.isFake {
+		return sURLs.WithError(nil)
+	}
+
+	sourcePath := filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
+	clnt, err := newClient(targetPath)
+	if err != nil {
+		return sURLs.WithError(err)
+	}
+	mj.status.PrintMsg(mirrorMessage{
+		Source:     sourcePath,
+		Target:     targetPath,
+		TotalCount: sURLs.TotalCount,
+		TotalSize:  sURLs.TotalSize,
+	})
+	if len(sURLs.SourceContent.Tags) == 0 {
+		err = clnt.DeleteTags(ctx, "")
+	} else {
+		err = clnt.SetTags(ctx, "", encodeTags(sURLs.SourceContent.Tags))
+	}
+	return sURLs.WithError(err)
+}
+
 // doMirror - Mirror an object to multiple destination. URLs status contains a copy of sURLs and error if any.
 func (mj *mirrorJob) doMirrorWatch(ctx context.Context, targetPath string, tgtSSE encrypt.ServerSide, sURLs URLs) URLs {
 	shouldQueue := false
@@ -440,6 +526,12 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 	if mj.opts.storageClass != "" {
 		sURLs.TargetContent.StorageClass = mj.opts.storageClass
 	}
+	if storageClass := mj.opts.storageClassMap.lookup(targetURL); storageClass != "" {
+		sURLs.TargetContent.StorageClass = storageClass
+	}
+	if cacheControl := mj.opts.cacheControlMap.lookup(targetURL); cacheControl != "" {
+		sURLs.TargetContent.Metadata["Cache-Control"] = cacheControl
+	}
 
 	if mj.opts.activeActive {
 		srcModTime := getSourceModTimeKey(sURLs.SourceContent.Metadata)
@@ -452,6 +544,10 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 		}
 	}
 
+	if mj.opts.transform != "" {
+		sURLs.TargetContent.Metadata[transformSourceKey] = transformSourceID(sURLs.SourceContent)
+	}
+
 	// Initialize additional target user metadata.
 	sURLs.TargetContent.UserMetadata = mj.opts.userMetadata
 
@@ -466,6 +562,7 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 	})
 	sURLs.MD5 = mj.opts.md5
 	sURLs.DisableMultipart = mj.opts.disableMultipart
+	sURLs.Transform = mj.opts.transform
 
 	now := time.Now()
 	ret := uploadSourceToTargetURL(ctx, sURLs, mj.status, mj.opts.encKeyDB, mj.opts.isMetadata, false)
@@ -536,8 +633,11 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 		}
 
 		if sURLs.SourceContent != nil {
-			mirrorTotalUploadedBytes.Add(float64(sURLs.SourceContent.Size))
-		} else if sURLs.TargetContent != nil {
+			if sURLs.mirrorOp != mirrorPlanTags {
+				mirrorTotalUploadedBytes.Add(float64(sURLs.SourceContent.Size))
+			}
+			recordObjectDone(sURLs.SourceContent.URL.String(), sURLs.SourceContent.Size)
+		} else if sURLs.TargetContent != nil && !mj.opts.isFake {
 			// Construct user facing message and path.
 			targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
 			mj.status.PrintMsg(rmMessage{Key: targetPath})
@@ -721,7 +821,20 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 				}
 			}
 
-			if sURLs.SourceContent != nil {
+			if mj.plan != nil && (sURLs.SourceContent != nil || mj.opts.isRemove) {
+				step := planStep(sURLs)
+				mj.plan.add(step)
+				mj.status.PrintMsg(mirrorPlanMessage{mirrorPlanStep: step})
+			}
+			if sURLs.mirrorOp == mirrorPlanSkip {
+				continue
+			}
+			// Mirrored before the session was resumed.
+			if sURLs.SourceContent != nil && globalCommandSession.isDone(sURLs.SourceContent.URL.String()) {
+				continue
+			}
+
+			if sURLs.SourceContent != nil && sURLs.mirrorOp != mirrorPlanTags {
 				mj.status.Add(sURLs.SourceContent.Size)
 			}
 
@@ -733,7 +846,17 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 			// Save totalSize.
 			sURLs.TotalSize = mj.status.Get()
 
+			var size int64
 			if sURLs.SourceContent != nil {
+				size = sURLs.SourceContent.Size
+			}
+			mj.status.fatalIf(mj.checkBudget(sURLs.mirrorOp, size), "Aborting the mirror.")
+
+			if sURLs.mirrorOp == mirrorPlanTags {
+				mj.parallel.queueTask(func() URLs {
+					return mj.doTags(ctx, sURLs)
+				}, 0)
+			} else if sURLs.SourceContent != nil {
 				mj.parallel.queueTask(func() URLs {
 					return mj.doMirror(ctx, sURLs)
 				}, sURLs.SourceContent.Size)
@@ -750,6 +873,80 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 	}
 }
 
+// checkBudget counts an operation in the budget of --max-delete and
+// --max-copy-bytes, the dry runs are not counted.
+func (mj *mirrorJob) checkBudget(op string, size int64) *probe.Error {
+	if mj.opts.isFake {
+		return nil
+	}
+	switch op {
+	case mirrorPlanDelete:
+		return mj.opts.budget.remove()
+	case mirrorPlanCopy, mirrorPlanOverwrite, mirrorPlanMetadata:
+		return mj.opts.budget.copy(size)
+	}
+	return nil
+}
+
+// Queue the steps of the plan given to --apply-plan
+func (mj *mirrorJob) startPlan(ctx context.Context) {
+	for _, step := range mj.replay.Steps {
+		select {
+		case <-ctx.Done():
+			return
+		case <-mj.stopCh:
+			return
+		default:
+		}
+
+		step := step
+		switch step.Operation {
+		case mirrorPlanMakeBucket:
+			mj.parallel.queueTaskWithBarrier(func() URLs {
+				clnt, err := newClient(step.Target)
+				if err == nil {
+					err = clnt.MakeBucket(ctx, mj.replay.Region, true, false)
+				}
+				if err != nil {
+					return URLs{Error: err.Trace(step.Target), ErrorCond: differInUnknown}
+				}
+				mj.status.PrintMsg(mirrorMessage{Source: step.Source, Target: step.Target})
+				return URLs{}
+			}, 0)
+		case mirrorPlanRemoveBucket:
+			mj.parallel.queueTaskWithBarrier(func() URLs {
+				if err := deleteBucket(ctx, step.Target, false); err != nil {
+					return URLs{Error: err.Trace(step.Target), ErrorCond: differInUnknown}
+				}
+				mj.status.PrintMsg(rmMessage{Key: step.Target})
+				return URLs{}
+			}, 0)
+		default:
+			size := step.Size
+			if step.Operation == mirrorPlanDelete || step.Operation == mirrorPlanTags {
+				size = 0
+			}
+			mj.status.fatalIf(mj.checkBudget(step.Operation, step.Size).Trace(step.Target), "Aborting the mirror.")
+			mj.status.Add(size)
+			mj.status.SetTotal(mj.status.Get()).Update()
+			mj.status.AddCounts(1)
+			totalCount, totalSize := mj.status.GetCounts(), mj.status.Get()
+
+			mj.parallel.queueTask(func() URLs {
+				sURLs := planURLs(ctx, step, mj.opts.encKeyDB)
+				sURLs.TotalCount, sURLs.TotalSize = totalCount, totalSize
+				switch step.Operation {
+				case mirrorPlanDelete:
+					return mj.doRemove(ctx, sURLs)
+				case mirrorPlanTags:
+					return mj.doTags(ctx, sURLs)
+				}
+				return mj.doMirror(ctx, sURLs)
+			}, size)
+		}
+	}
+}
+
 // when using a struct for copying, we could save a lot of passing of variables
 func (mj *mirrorJob) mirror(ctx context.Context) bool {
 	var wg sync.WaitGroup
@@ -769,7 +966,11 @@ func (mj *mirrorJob) mirror(ctx context.Context) bool {
 	go func() {
 		defer wg.Done()
 		// startMirror locks and blocks itself.
-		mj.startMirror(ctx)
+		if mj.replay != nil {
+			mj.startPlan(ctx)
+		} else {
+			mj.startMirror(ctx)
+		}
 	}()
 
 	// Close statusCh when both watch & mirror quits
@@ -793,7 +994,7 @@ func newMirrorJob(srcURL, dstURL string, opts mirrorOptions) *mirrorJob {
 		watcher:   NewWatcher(UTCNow()),
 	}
 
-	mj.parallel = newParallelManager(mj.statusCh)
+	mj.parallel = newParallelManager(mj.statusCh, opts.parallel)
 
 	// we'll define the status to use here,
 	// do we want the quiet status? or the progressbar
@@ -887,7 +1088,10 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 	// preserve is also expected to be overwritten if necessary
 	isMetadata := cli.Bool("a") || isWatch || len(userMetadata) > 0
 	isOverwrite = isOverwrite || isMetadata
-	isFake := cli.Bool("fake") || cli.Bool("dry-run")
+	isFake := isMirrorFake(cli)
+
+	storageClassMap, e := parseStorageClassMap(cli.String("storage-class-map"))
+	fatalIf(probe.NewError(e), "Invalid --storage-class-map.")
 
 	mopts := mirrorOptions{
 		isFake:           isFake,
@@ -897,17 +1101,24 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		isMetadata:       isMetadata,
 		md5:              cli.Bool("md5"),
 		disableMultipart: cli.Bool("disable-multipart"),
+		transform:        cli.String("transform"),
 		excludeOptions:   cli.StringSlice("exclude"),
 		olderThan:        cli.String("older-than"),
 		newerThan:        cli.String("newer-than"),
 		storageClass:     cli.String("storage-class"),
+		storageClassMap:  storageClassMap,
 		userMetadata:     userMetadata,
 		encKeyDB:         encKeyDB,
 		activeActive:     isWatch,
+		parallel:         cli.Int("parallel"),
+		budget:           mustGetOpBudget(cli),
 	}
 
 	// Create a new mirror job and execute it
 	mj := newMirrorJob(srcURL, dstURL, mopts)
+	if isFake {
+		mj.plan = newMirrorPlan(srcURL, dstURL, mopts, cli.String("region"))
+	}
 
 	preserve := cli.Bool("preserve")
 
@@ -928,8 +1139,14 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 
 			if d.Diff == differInSecond {
 				diffBucket := strings.TrimPrefix(d.SecondURL, dstClt.GetURL().String())
-				if !isFake && isRemove {
+				if isRemove {
 					aliasedDstBucket := path.Join(dstURL, diffBucket)
+					if isFake {
+						step := mirrorPlanStep{Operation: mirrorPlanRemoveBucket, Target: aliasedDstBucket}
+						mj.plan.add(step)
+						mj.status.PrintMsg(mirrorPlanMessage{mirrorPlanStep: step})
+						continue
+					}
 					err := deleteBucket(ctx, aliasedDstBucket, false)
 					mj.status.fatalIf(err, "Failed to start mirroring.")
 				}
@@ -959,15 +1176,18 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 					}
 				}
 
+				if mj.opts.isFake {
+					step := mirrorPlanStep{Operation: mirrorPlanMakeBucket, Source: newSrcURL, Target: newTgtURL}
+					mj.plan.add(step)
+					mj.status.PrintMsg(mirrorPlanMessage{mirrorPlanStep: step})
+					continue
+				}
+
 				mj.status.PrintMsg(mirrorMessage{
 					Source: newSrcURL,
 					Target: newTgtURL,
 				})
 
-				if mj.opts.isFake {
-					continue
-				}
-
 				// Bucket only exists in the source, create the same bucket in the destination
 				if err := newDstClt.MakeBucket(ctx, cli.String("region"), false, withLock); err != nil {
 					errorIf(err, "Unable to create bucket at `"+newTgtURL+"`.")
@@ -1004,13 +1224,51 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		}
 	}
 
+	errorDetected := mj.mirror(ctx)
+	if mj.plan != nil {
+		summary := mirrorPlanSummaryMessage{Source: srcURL, Target: dstURL, Summary: mj.plan.Summary}
+		if file := cli.String("plan"); file != "" && !errorDetected {
+			fatalIf(mj.plan.save(file).Trace(file), "Unable to write the plan `"+file+"`.")
+			summary.Plan = file
+		}
+		printMsg(summary)
+	}
+	return errorDetected
+}
+
+// applyMirrorPlan - executes the plan given to --apply-plan
+func applyMirrorPlan(ctx context.Context, cli *cli.Context, encKeyDB map[string][]prefixSSEPair) bool {
+	file := cli.String("apply-plan")
+	plan, err := loadMirrorPlan(file)
+	fatalIf(err.Trace(file), "Unable to load the plan `"+file+"`.")
+
+	mj := newMirrorJob(plan.Source, plan.Target, mirrorOptions{
+		isOverwrite:      true,
+		isMetadata:       plan.Preserve,
+		userMetadata:     plan.Attr,
+		md5:              cli.Bool("md5"),
+		disableMultipart: cli.Bool("disable-multipart"),
+		transform:        cli.String("transform"),
+		storageClass:     cli.String("storage-class"),
+		encKeyDB:         encKeyDB,
+		parallel:         cli.Int("parallel"),
+		budget:           mustGetOpBudget(cli),
+	})
+	mj.replay = plan
 	return mj.mirror(ctx)
 }
 
+// isMirrorFake returns true when the mirror is only simulated.
+func isMirrorFake(cli *cli.Context) bool {
+	return cli.Bool("fake") || cli.Bool("dry-run") || cli.String("plan") != ""
+}
+
 // Main entry point for mirror command.
 func mainMirror(cliCtx *cli.Context) error {
 	// Additional command specific theme customization.
 	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	console.SetColor("MirrorPlan", color.New(color.FgYellow))
+	console.SetColor("MirrorPlanSummary", color.New(color.Bold))
 
 	ctx, cancelMirror := context.WithCancel(globalContext)
 	defer cancelMirror()
@@ -1019,9 +1277,42 @@ func mainMirror(cliCtx *cli.Context) error {
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	enableCompletionNotify(cliCtx)
+
+	if cliCtx.String("apply-plan") != "" {
+		if cliCtx.Args().Present() || isMirrorFake(cliCtx) || cliCtx.Bool("watch") {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--apply-plan takes neither SOURCE and TARGET nor --dry-run and --watch.")
+		}
+		startCommandSession("mirror", cliCtx)
+		if applyMirrorPlan(ctx, cliCtx, encKeyDB) {
+			return globalCommandSession.finish(transferExitStatus(nil))
+		}
+		return globalCommandSession.finish(nil)
+	}
+
 	// check 'mirror' cli arguments.
 	srcURL, tgtURL := checkMirrorSyntax(ctx, cliCtx, encKeyDB)
 
+	// With --staging the tree is mirrored to a new release, see mirror-staging.go.
+	mirrorURL := tgtURL
+	var release string
+	if cliCtx.Bool("staging") {
+		if cliCtx.Bool("watch") || cliCtx.Bool("active-active") || cliCtx.Bool("multi-master") || cliCtx.Bool("remove") {
+			fatalIf(errInvalidArgument().Trace(tgtURL), "--staging cannot be used with --watch, --active-active or --remove.")
+		}
+		fatalIf(checkStagingTarget(tgtURL).Trace(tgtURL), "Unable to stage the mirror to `"+tgtURL+"`.")
+		release = stagingRelease(UTCNow())
+		mirrorURL = urlJoinPath(tgtURL, release)
+	}
+
+	if cliCtx.String("plan") != "" && (cliCtx.Bool("watch") || cliCtx.Bool("active-active") || cliCtx.Bool("multi-master")) {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("plan")), "--plan cannot be used with --watch or --active-active.")
+	}
+
+	if !isMirrorFake(cliCtx) {
+		startCommandSession("mirror", cliCtx)
+	}
+
 	if prometheusAddress := cliCtx.String("monitoring-address"); prometheusAddress != "" {
 		http.Handle("/metrics", promhttp.Handler())
 		go func() {
@@ -1035,18 +1326,29 @@ func mainMirror(cliCtx *cli.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
-			return exitStatus(globalErrorExitStatus)
+			return globalCommandSession.finish(exitStatus(globalErrorExitStatus))
 		default:
-			errorDetected := runMirror(ctx, srcURL, tgtURL, cliCtx, encKeyDB)
+			errorDetected := runMirror(ctx, srcURL, mirrorURL, cliCtx, encKeyDB)
 			if cliCtx.Bool("watch") || cliCtx.Bool("multi-master") || cliCtx.Bool("active-active") {
 				mirrorRestarts.Inc()
 				time.Sleep(time.Duration(r.Float64() * float64(2*time.Second)))
 				continue
 			}
 			if errorDetected {
-				return exitStatus(globalErrorExitStatus)
+				return globalCommandSession.finish(transferExitStatus(nil))
 			}
-			return nil
+			if release != "" && !isMirrorFake(cliCtx) {
+				previous, err := readStagingPointer(ctx, tgtURL)
+				fatalIf(err.Trace(tgtURL), "Unable to read the current release of `"+tgtURL+"`.")
+				fatalIf(promoteStagingRelease(ctx, tgtURL, release).Trace(tgtURL), "Unable to promote the release `"+release+"`.")
+				printMsg(stagingMessage{
+					Target:   tgtURL,
+					Release:  release,
+					Previous: previous,
+					Pointer:  urlJoinPath(tgtURL, stagingPointerObject),
+				})
+			}
+			return globalCommandSession.finish(nil)
 		}
 	}
 }
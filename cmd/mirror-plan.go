@@ -0,0 +1,285 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// mirrorPlanVersion is the version of the plans written by --plan.
+const mirrorPlanVersion = "1"
+
+// Operations of a mirror plan.
+const (
+	mirrorPlanMakeBucket   = "make-bucket"   // bucket only in the source
+	mirrorPlanRemoveBucket = "remove-bucket" // bucket only in the target, with --remove
+	mirrorPlanCopy         = "copy"          // object only in the source
+	mirrorPlanOverwrite    = "overwrite"     // object differing in size or modification time
+	mirrorPlanMetadata     = "metadata"      // object differing only in metadata
+	mirrorPlanTags         = "tags"          // object differing only in tags
+	mirrorPlanDelete       = "delete"        // object only in the target, with --remove
+	mirrorPlanSkip         = "skip"          // object differing, without --overwrite
+)
+
+// mirrorPlanOperations lists the operations in the order they are summarized.
+var mirrorPlanOperations = []string{
+	mirrorPlanMakeBucket, mirrorPlanRemoveBucket, mirrorPlanCopy, mirrorPlanOverwrite,
+	mirrorPlanMetadata, mirrorPlanTags, mirrorPlanDelete, mirrorPlanSkip,
+}
+
+// mirrorOperation returns the operation mirroring a difference between
+// the source and the target.
+func mirrorOperation(diff differType, src, tgt *ClientContent) string {
+	switch diff {
+	case differInFirst:
+		return mirrorPlanCopy
+	case differInSecond:
+		return mirrorPlanDelete
+	case differInMetadata:
+		if src != nil && tgt != nil &&
+			(metadataEqual(src.UserMetadata, tgt.UserMetadata) || metadataEqual(src.Metadata, tgt.Metadata)) {
+			return mirrorPlanTags
+		}
+		return mirrorPlanMetadata
+	}
+	return mirrorPlanOverwrite
+}
+
+// mirrorPlanStep is an operation of a mirror plan, the paths include
+// their alias.
+type mirrorPlanStep struct {
+	Operation string            `json:"operation"`
+	Source    string            `json:"source,omitempty"`
+	Target    string            `json:"target"`
+	Size      int64             `json:"size,omitempty"`
+	ETag      string            `json:"etag,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// mirrorPlanTotal counts the objects and bytes of an operation.
+type mirrorPlanTotal struct {
+	Count int64 `json:"count"`
+	Bytes int64 `json:"bytes"`
+}
+
+// mirrorPlan is the result of `mc mirror --dry-run`, written by --plan
+// and executed later by --apply-plan.
+type mirrorPlan struct {
+	Version  string                     `json:"version"`
+	Source   string                     `json:"source"`
+	Target   string                     `json:"target"`
+	Created  time.Time                  `json:"created"`
+	Preserve bool                       `json:"preserve,omitempty"`
+	Attr     map[string]string          `json:"attr,omitempty"`
+	Region   string                     `json:"region,omitempty"`
+	Summary  map[string]mirrorPlanTotal `json:"summary"`
+	Steps    []mirrorPlanStep           `json:"steps"`
+
+	mu sync.Mutex
+}
+
+func newMirrorPlan(source, target string, opts mirrorOptions, region string) *mirrorPlan {
+	return &mirrorPlan{
+		Version:  mirrorPlanVersion,
+		Source:   source,
+		Target:   target,
+		Created:  UTCNow(),
+		Preserve: opts.isMetadata,
+		Attr:     opts.userMetadata,
+		Region:   region,
+		Summary:  make(map[string]mirrorPlanTotal),
+		Steps:    []mirrorPlanStep{},
+	}
+}
+
+// add counts a step, the skipped objects are not part of the steps
+// executed by --apply-plan.
+func (p *mirrorPlan) add(step mirrorPlanStep) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	total := p.Summary[step.Operation]
+	total.Count++
+	if step.Operation != mirrorPlanTags {
+		total.Bytes += step.Size
+	}
+	p.Summary[step.Operation] = total
+	if step.Operation != mirrorPlanSkip {
+		p.Steps = append(p.Steps, step)
+	}
+}
+
+// save writes the plan to file.
+func (p *mirrorPlan) save(file string) *probe.Error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	data, e := json.MarshalIndent(p, "", " ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	return probe.NewError(os.WriteFile(file, data, 0o644))
+}
+
+// loadMirrorPlan reads and validates a plan written by --plan.
+func loadMirrorPlan(file string) (*mirrorPlan, *probe.Error) {
+	data, e := os.ReadFile(file)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	var plan mirrorPlan
+	if e = json.Unmarshal(data, &plan); e != nil {
+		return nil, probe.NewError(e)
+	}
+	if plan.Version != mirrorPlanVersion {
+		return nil, probe.NewError(fmt.Errorf("unsupported plan version `%s`", plan.Version))
+	}
+	for i, step := range plan.Steps {
+		switch step.Operation {
+		case mirrorPlanCopy, mirrorPlanOverwrite, mirrorPlanMetadata, mirrorPlanTags:
+			if step.Source == "" {
+				return nil, probe.NewError(fmt.Errorf("step %d: %s without a source", i+1, step.Operation))
+			}
+		case mirrorPlanMakeBucket, mirrorPlanRemoveBucket, mirrorPlanDelete:
+		default:
+			return nil, probe.NewError(fmt.Errorf("step %d: unknown operation `%s`", i+1, step.Operation))
+		}
+		if step.Target == "" {
+			return nil, probe.NewError(fmt.Errorf("step %d: %s without a target", i+1, step.Operation))
+		}
+	}
+	return &plan, nil
+}
+
+// planStep returns the step of the plan for sURLs.
+func planStep(sURLs URLs) mirrorPlanStep {
+	step := mirrorPlanStep{
+		Operation: sURLs.mirrorOp,
+		Target:    filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path)),
+	}
+	if sURLs.SourceContent != nil {
+		step.Source = filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path))
+		step.Size = sURLs.SourceContent.Size
+		step.ETag = sURLs.SourceContent.ETag
+		if step.Operation == mirrorPlanTags {
+			step.Tags = sURLs.SourceContent.Tags
+		}
+	} else {
+		step.Size = sURLs.TargetContent.Size
+	}
+	return step
+}
+
+// planURLs returns the URLs executing a step of the plan. The source is
+// read again, so the objects changed since the plan was made are not
+// mirrored.
+func planURLs(ctx context.Context, step mirrorPlanStep, encKeyDB map[string][]prefixSSEPair) URLs {
+	targetAlias, targetURL, _ := mustExpandAlias(step.Target)
+	sURLs := URLs{
+		TargetAlias:   targetAlias,
+		TargetContent: &ClientContent{URL: *newClientURL(targetURL)},
+		encKeyDB:      encKeyDB,
+		mirrorOp:      step.Operation,
+	}
+	if step.Source == "" {
+		return sURLs
+	}
+
+	sourceAlias, sourceURL, _ := mustExpandAlias(step.Source)
+	sURLs.SourceAlias = sourceAlias
+	sURLs.SourceContent = &ClientContent{URL: *newClientURL(sourceURL), Size: step.Size, Tags: step.Tags}
+	if step.Operation == mirrorPlanTags {
+		return sURLs
+	}
+	_, content, err := url2Stat(ctx, step.Source, "", false, encKeyDB, time.Time{}, false)
+	if err != nil {
+		return sURLs.WithError(err.Trace(step.Source))
+	}
+	sURLs.SourceContent = content
+	if content.Size != step.Size || step.ETag != "" && content.ETag != "" && content.ETag != step.ETag {
+		return sURLs.WithError(probe.NewError(fmt.Errorf("`%s` changed since the plan was made", step.Source)))
+	}
+	return sURLs
+}
+
+// mirrorPlanMessage container for a step of `mc mirror --dry-run`
+type mirrorPlanMessage struct {
+	Status string `json:"status"`
+	mirrorPlanStep
+}
+
+func (m mirrorPlanMessage) String() string {
+	switch m.Operation {
+	case mirrorPlanMakeBucket, mirrorPlanRemoveBucket, mirrorPlanDelete:
+		return console.Colorize("MirrorPlan", fmt.Sprintf("%-13s `%s`", m.Operation, m.Target))
+	case mirrorPlanTags:
+		return console.Colorize("MirrorPlan", fmt.Sprintf("%-13s `%s` -> `%s`", m.Operation, m.Source, m.Target))
+	}
+	return console.Colorize("MirrorPlan", fmt.Sprintf("%-13s `%s` -> `%s` (%s)", m.Operation, m.Source, m.Target, humanize.IBytes(uint64(m.Size))))
+}
+
+func (m mirrorPlanMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mirrorPlanSummaryMessage container for the totals of `mc mirror --dry-run`
+type mirrorPlanSummaryMessage struct {
+	Status  string                     `json:"status"`
+	Source  string                     `json:"source"`
+	Target  string                     `json:"target"`
+	Summary map[string]mirrorPlanTotal `json:"summary"`
+	Plan    string                     `json:"plan,omitempty"`
+}
+
+func (m mirrorPlanSummaryMessage) String() string {
+	var b strings.Builder
+	b.WriteString(console.Colorize("MirrorPlanSummary", fmt.Sprintf("Dry run of `%s` -> `%s`:", m.Source, m.Target)))
+	for _, operation := range mirrorPlanOperations {
+		total, ok := m.Summary[operation]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %-13s %8d object(s) %10s", operation, total.Count, humanize.IBytes(uint64(total.Bytes)))
+	}
+	if len(m.Summary) == 0 {
+		b.WriteString("\n  Nothing to mirror.")
+	}
+	if m.Plan != "" {
+		b.WriteString("\n" + console.Colorize("MirrorPlanSummary", "Plan written to `"+m.Plan+"`."))
+	}
+	return b.String()
+}
+
+func (m mirrorPlanSummaryMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
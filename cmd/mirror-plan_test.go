@@ -0,0 +1,97 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMirrorOperation(t *testing.T) {
+	src := &ClientContent{UserMetadata: map[string]string{"a": "1"}, Metadata: map[string]string{"Content-Type": "text/plain"}}
+	sameMetadata := &ClientContent{UserMetadata: map[string]string{"a": "1"}, Metadata: map[string]string{"Content-Type": "text/plain"}}
+	otherMetadata := &ClientContent{UserMetadata: map[string]string{"a": "2"}, Metadata: map[string]string{"Content-Type": "text/html"}}
+	testCases := []struct {
+		diff     differType
+		src, tgt *ClientContent
+		want     string
+	}{
+		{differInFirst, src, nil, mirrorPlanCopy},
+		{differInSecond, nil, sameMetadata, mirrorPlanDelete},
+		{differInSize, src, sameMetadata, mirrorPlanOverwrite},
+		{differInAASourceMTime, src, sameMetadata, mirrorPlanOverwrite},
+		{differInMetadata, src, otherMetadata, mirrorPlanMetadata},
+		{differInMetadata, src, sameMetadata, mirrorPlanTags},
+	}
+	for i, testCase := range testCases {
+		if got := mirrorOperation(testCase.diff, testCase.src, testCase.tgt); got != testCase.want {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.want, got)
+		}
+	}
+}
+
+func TestMirrorPlan(t *testing.T) {
+	plan := newMirrorPlan("play/photos", "s3/backup", mirrorOptions{isMetadata: true}, "us-east-1")
+	steps := []mirrorPlanStep{
+		{Operation: mirrorPlanCopy, Source: "play/photos/a.jpg", Target: "s3/backup/a.jpg", Size: 10},
+		{Operation: mirrorPlanCopy, Source: "play/photos/b.jpg", Target: "s3/backup/b.jpg", Size: 20},
+		{Operation: mirrorPlanTags, Source: "play/photos/c.jpg", Target: "s3/backup/c.jpg", Size: 30, Tags: map[string]string{"k": "v"}},
+		{Operation: mirrorPlanDelete, Target: "s3/backup/d.jpg", Size: 40},
+		{Operation: mirrorPlanSkip, Source: "play/photos/e.jpg", Target: "s3/backup/e.jpg", Size: 50},
+	}
+	for _, step := range steps {
+		plan.add(step)
+	}
+	wantSummary := map[string]mirrorPlanTotal{
+		mirrorPlanCopy:   {Count: 2, Bytes: 30},
+		mirrorPlanTags:   {Count: 1},
+		mirrorPlanDelete: {Count: 1, Bytes: 40},
+		mirrorPlanSkip:   {Count: 1, Bytes: 50},
+	}
+	if !reflect.DeepEqual(plan.Summary, wantSummary) {
+		t.Fatalf("expected %v, got %v", wantSummary, plan.Summary)
+	}
+
+	file := filepath.Join(t.TempDir(), "plan.json")
+	if err := plan.save(file); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadMirrorPlan(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Steps, steps[:4]) || !loaded.Preserve || loaded.Region != "us-east-1" {
+		t.Fatalf("unexpected plan %+v", loaded)
+	}
+
+	for _, data := range []string{
+		`{"version":"2","steps":[]}`,
+		`{"version":"1","steps":[{"operation":"move","target":"s3/backup/a"}]}`,
+		`{"version":"1","steps":[{"operation":"copy","target":"s3/backup/a"}]}`,
+		`{"version":"1","steps":[{"operation":"delete"}]}`,
+	} {
+		if e := os.WriteFile(file, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		if _, err = loadMirrorPlan(file); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+}
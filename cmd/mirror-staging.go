@@ -0,0 +1,113 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// With --staging the tree is mirrored to a new release prefix of the
+// target, e.g. 'site/releases/20231015T120000Z/', then the pointer object
+// 'site/current' is overwritten with the name of the release. A single PUT
+// promotes the release, the readers following the pointer never see a
+// partially uploaded tree. The previous releases are kept for rollbacks.
+const (
+	stagingReleasesPrefix = "releases/"
+	stagingPointerObject  = "current"
+	stagingReleaseFormat  = "20060102T150405Z"
+	stagingPointerMaxSize = 1024
+)
+
+// stagingRelease returns the prefix of the release created at now.
+func stagingRelease(now time.Time) string {
+	return stagingReleasesPrefix + now.UTC().Format(stagingReleaseFormat) + "/"
+}
+
+// checkStagingTarget returns an error when the target of --staging has no
+// bucket, the releases and the pointer are created in the bucket.
+func checkStagingTarget(targetURL string) *probe.Error {
+	_, expandedURL, _ := mustExpandAlias(targetURL)
+	clnt := newClientURL(expandedURL)
+	if clnt.Type == objectStorage && strings.Trim(clnt.Path, string(clnt.Separator)) == "" {
+		return probe.NewError(errors.New("--staging needs a bucket or a prefix as target"))
+	}
+	return nil
+}
+
+// readStagingPointer returns the release named by the pointer of the
+// target, empty when no release was promoted.
+func readStagingPointer(ctx context.Context, targetURL string) (string, *probe.Error) {
+	pointerURL := urlJoinPath(targetURL, stagingPointerObject)
+	reader, err := getSourceStreamFromURL(ctx, pointerURL, nil, getSourceOpts{})
+	if err != nil {
+		switch err.ToGoError().(type) {
+		case ObjectMissing, PathNotFound:
+			return "", nil
+		}
+		return "", err.Trace(pointerURL)
+	}
+	defer reader.Close()
+	release, e := io.ReadAll(io.LimitReader(reader, stagingPointerMaxSize))
+	if e != nil {
+		return "", probe.NewError(e).Trace(pointerURL)
+	}
+	return strings.TrimSpace(string(release)), nil
+}
+
+// promoteStagingRelease overwrites the pointer of the target with release.
+func promoteStagingRelease(ctx context.Context, targetURL, release string) *probe.Error {
+	alias, expandedURL, _ := mustExpandAlias(urlJoinPath(targetURL, stagingPointerObject))
+	opts := PutOptions{metadata: map[string]string{
+		"Content-Type":  "text/plain",
+		"Cache-Control": "no-cache",
+	}}
+	_, err := putTargetStream(ctx, alias, expandedURL, "", "", "", strings.NewReader(release), int64(len(release)), nil, opts)
+	return err
+}
+
+// stagingMessage container for a release promoted by `mc mirror --staging`
+type stagingMessage struct {
+	Status   string `json:"status"`
+	Target   string `json:"target"`
+	Release  string `json:"release"`
+	Previous string `json:"previous,omitempty"`
+	Pointer  string `json:"pointer"`
+}
+
+func (s stagingMessage) String() string {
+	msg := "Promoted `" + urlJoinPath(s.Target, s.Release) + "` in `" + s.Pointer + "`."
+	if s.Previous != "" {
+		msg += " Previous release `" + s.Previous + "`."
+	}
+	return console.Colorize("Mirror", msg)
+}
+
+func (s stagingMessage) JSON() string {
+	s.Status = "success"
+	stagingMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(stagingMessageBytes)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestStagingPointer(t *testing.T) {
+	now := time.Date(2023, 10, 15, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	if got, want := stagingRelease(now), "releases/20231015T100000Z/"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newConfigV10(), nil }
+
+	target := t.TempDir()
+	if err := checkStagingTarget(target); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	release, err := readStagingPointer(ctx, target)
+	if err != nil || release != "" {
+		t.Fatalf("expected no release, got %q, %v", release, err)
+	}
+	for _, want := range []string{"releases/20231015T100000Z/", "releases/20231016T100000Z/"} {
+		if err = promoteStagingRelease(ctx, target, want); err != nil {
+			t.Fatal(err)
+		}
+		if release, err = readStagingPointer(ctx, target); err != nil || release != want {
+			t.Fatalf("expected %q, got %q, %v", want, release, err)
+		}
+	}
+}
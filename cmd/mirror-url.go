@@ -153,14 +153,25 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 		case differInType:
 			URLsCh <- URLs{Error: errInvalidTarget(diffMsg.SecondURL)}
 		case differInSize, differInMetadata, differInAASourceMTime:
-			if !opts.isOverwrite && !opts.isFake && !opts.activeActive {
-				// Size or time or etag differs but --overwrite not set.
-				URLsCh <- URLs{
-					Error:     errOverWriteNotAllowed(diffMsg.SecondURL),
-					ErrorCond: diffMsg.Diff,
-				}
+			if diffMsg.Diff == differInSize && opts.transform != "" &&
+				transformedUpToDate(ctx, targetAlias, diffMsg.firstContent, diffMsg.secondContent) {
+				// The size of transformed objects differs from the source.
 				continue
 			}
+			mirrorOp := mirrorOperation(diffMsg.Diff, diffMsg.firstContent, diffMsg.secondContent)
+			if !opts.isOverwrite && !opts.activeActive {
+				if opts.isFake {
+					// Counted by the dry run, not mirrored.
+					mirrorOp = mirrorPlanSkip
+				} else {
+					// Size or time or etag differs but --overwrite not set.
+					URLsCh <- URLs{
+						Error:     errOverWriteNotAllowed(diffMsg.SecondURL),
+						ErrorCond: diffMsg.Diff,
+					}
+					continue
+				}
+			}
 
 			sourceSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
 			// Either available only in source or size differs and force is set
@@ -172,6 +183,7 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 				SourceContent: sourceContent,
 				TargetAlias:   targetAlias,
 				TargetContent: targetContent,
+				mirrorOp:      mirrorOp,
 			}
 		case differInFirst:
 			// Only in first, always copy.
@@ -184,6 +196,7 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 				SourceContent: sourceContent,
 				TargetAlias:   targetAlias,
 				TargetContent: targetContent,
+				mirrorOp:      mirrorPlanCopy,
 			}
 		case differInSecond:
 			if !opts.isRemove && !opts.isFake {
@@ -192,6 +205,7 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 			URLsCh <- URLs{
 				TargetAlias:   targetAlias,
 				TargetContent: diffMsg.secondContent,
+				mirrorOp:      mirrorPlanDelete,
 			}
 		default:
 			URLsCh <- URLs{
@@ -210,7 +224,12 @@ type mirrorOptions struct {
 	md5, disableMultipart             bool
 	olderThan, newerThan              string
 	storageClass                      string
+	transform                         string
+	storageClassMap                   storageClassMap
+	cacheControlMap                   cacheControlMap
 	userMetadata                      map[string]string
+	parallel                          int
+	budget                            *opBudget
 }
 
 // Prepares urls that need to be copied or removed based on requested options.
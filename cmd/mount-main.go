@@ -0,0 +1,151 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var mountFlags = []cli.Flag{
+	cli.DurationFlag{
+		Name:  "cache-ttl",
+		Value: 5 * time.Second,
+		Usage: "duration the attributes and directory listings are cached",
+	},
+	cli.StringFlag{
+		Name:  "cache-dir",
+		Usage: "directory buffering the written files until they are uploaded, defaults to the temporary directory",
+	},
+	cli.BoolFlag{
+		Name:  "read-only",
+		Usage: "mount the bucket read-only",
+	},
+	cli.BoolFlag{
+		Name:  "allow-other",
+		Usage: "allow other users to access the mount point",
+	},
+}
+
+var mountCmd = cli.Command{
+	Name:         "mount",
+	Usage:        "mount a bucket as a local filesystem",
+	Action:       mainMount,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(mountFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET MOUNTPOINT
+
+  The bucket is served through FUSE until the command is interrupted. Files
+  written are buffered in the cache directory and uploaded when closed, large
+  files with a multipart upload. Suited for light workloads, the objects are
+  not locked while they are modified.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Mount the bucket 'mybucket' on '/mnt/mybucket'.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket /mnt/mybucket
+
+  2. Mount a prefix read-only, caching the listings for a minute.
+     {{.Prompt}} {{.HelpName}} --read-only --cache-ttl 1m myminio/mybucket/photos /mnt/photos
+`,
+}
+
+// mountOptions holds the options of the mounted filesystem.
+type mountOptions struct {
+	cacheTTL   time.Duration
+	cacheDir   string
+	readOnly   bool
+	allowOther bool
+}
+
+// mountMessage container for the mount messages
+type mountMessage struct {
+	Status     string `json:"status"`
+	Target     string `json:"target"`
+	MountPoint string `json:"mountPoint"`
+	Mounted    bool   `json:"mounted"`
+}
+
+func (m mountMessage) String() string {
+	if !m.Mounted {
+		return console.Colorize("MountMessage", "Unmounted `"+m.MountPoint+"`.")
+	}
+	return console.Colorize("MountMessage", "Mounted `"+m.Target+"` on `"+m.MountPoint+"`, interrupt to unmount.")
+}
+
+func (m mountMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func checkMountSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainMount is the handle for "mc mount" command.
+func mainMount(ctx *cli.Context) error {
+	checkMountSyntax(ctx)
+	console.SetColor("MountMessage", color.New(color.FgGreen))
+
+	target, mountPoint := ctx.Args().Get(0), ctx.Args().Get(1)
+	st, e := os.Stat(mountPoint)
+	fatalIf(probe.NewError(e).Trace(mountPoint), "Unable to access the mount point.")
+	if !st.IsDir() {
+		fatalIf(errInvalidArgument().Trace(mountPoint), "Mount point must be a directory.")
+	}
+
+	clnt, err := newClient(target)
+	fatalIf(err.Trace(target), "Unable to initialize target `"+target+"`.")
+	content, err := clnt.Stat(globalContext, StatOptions{})
+	fatalIf(err.Trace(target), "Unable to access `"+target+"`.")
+	if !content.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(target), "Target must be a bucket or a prefix.")
+	}
+
+	opts := mountOptions{
+		cacheTTL:   ctx.Duration("cache-ttl"),
+		cacheDir:   ctx.String("cache-dir"),
+		readOnly:   ctx.Bool("read-only"),
+		allowOther: ctx.Bool("allow-other"),
+	}
+	if opts.cacheDir == "" {
+		opts.cacheDir = os.TempDir()
+	}
+
+	fatalIf(mountTarget(target, mountPoint, opts, func() {
+		printMsg(mountMessage{Target: target, MountPoint: mountPoint, Mounted: true})
+	}).Trace(target, mountPoint), "Unable to mount `"+target+"`.")
+	printMsg(mountMessage{Target: target, MountPoint: mountPoint})
+	return nil
+}
@@ -0,0 +1,597 @@
+//go:build linux || darwin
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// # This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+// mountFS is the state shared by the nodes of a mounted target.
+type mountFS struct {
+	target string
+	opts   mountOptions
+
+	mu    sync.Mutex
+	attrs map[string]mountAttr
+}
+
+// mountAttr is a cached stat of a path, content is nil when the path
+// does not exist.
+type mountAttr struct {
+	content *ClientContent
+	expires time.Time
+}
+
+// mountNode is a file or a directory of the mounted target, its key is
+// the path relative to the target.
+type mountNode struct {
+	fs.Inode
+	mfs *mountFS
+}
+
+var (
+	_ fs.NodeLookuper  = (*mountNode)(nil)
+	_ fs.NodeReaddirer = (*mountNode)(nil)
+	_ fs.NodeGetattrer = (*mountNode)(nil)
+	_ fs.NodeSetattrer = (*mountNode)(nil)
+	_ fs.NodeOpener    = (*mountNode)(nil)
+	_ fs.NodeCreater   = (*mountNode)(nil)
+	_ fs.NodeMkdirer   = (*mountNode)(nil)
+	_ fs.NodeUnlinker  = (*mountNode)(nil)
+	_ fs.NodeRmdirer   = (*mountNode)(nil)
+	_ fs.NodeRenamer   = (*mountNode)(nil)
+)
+
+// toErrno maps the errors of the clients to FUSE status codes, the
+// errors without a status code, e.g. network errors, are I/O errors.
+func toErrno(err *probe.Error) syscall.Errno {
+	if err == nil {
+		return fs.OK
+	}
+	e := err.ToGoError()
+	switch e.(type) {
+	case ObjectMissing, PathNotFound, BucketDoesNotExist:
+		return syscall.ENOENT
+	case PathInsufficientPermission:
+		return syscall.EACCES
+	}
+	var errno syscall.Errno
+	switch {
+	case errors.As(e, &errno):
+		return errno
+	case os.IsNotExist(e):
+		return syscall.ENOENT
+	case os.IsPermission(e):
+		return syscall.EACCES
+	}
+	return syscall.EIO
+}
+
+func (m *mountFS) url(key string) string {
+	if key == "" {
+		return m.target
+	}
+	return urlJoinPath(m.target, key)
+}
+
+func (m *mountFS) client(key string) (Client, syscall.Errno) {
+	clnt, err := newClient(m.url(key))
+	if err != nil {
+		return nil, toErrno(err)
+	}
+	return clnt, fs.OK
+}
+
+func (m *mountFS) cache(key string, content *ClientContent) {
+	m.mu.Lock()
+	m.attrs[key] = mountAttr{content: content, expires: time.Now().Add(m.opts.cacheTTL)}
+	m.mu.Unlock()
+}
+
+// invalidate drops the cached stat of a path.
+func (m *mountFS) invalidate(key string) {
+	m.mu.Lock()
+	delete(m.attrs, key)
+	m.mu.Unlock()
+}
+
+// stat returns the attributes of a path, from the cache while it is fresh.
+func (m *mountFS) stat(ctx context.Context, key string) (*ClientContent, syscall.Errno) {
+	m.mu.Lock()
+	attr, ok := m.attrs[key]
+	m.mu.Unlock()
+	if ok && time.Now().Before(attr.expires) {
+		if attr.content == nil {
+			return nil, syscall.ENOENT
+		}
+		return attr.content, fs.OK
+	}
+
+	clnt, errno := m.client(key)
+	if errno != fs.OK {
+		return nil, errno
+	}
+	content, err := clnt.Stat(ctx, StatOptions{})
+	if errno = toErrno(err); errno == syscall.ENOENT {
+		m.cache(key, nil)
+	}
+	if errno != fs.OK {
+		return nil, errno
+	}
+	m.cache(key, content)
+	return content, fs.OK
+}
+
+func (m *mountFS) fillAttr(content *ClientContent, out *fuse.Attr) {
+	mode := uint32(0o644)
+	if content.Type.IsDir() {
+		mode = syscall.S_IFDIR | 0o755
+	} else {
+		mode |= syscall.S_IFREG
+		out.Size = uint64(content.Size)
+		out.Blocks = (out.Size + 511) / 512
+	}
+	if m.opts.readOnly {
+		mode &^= 0o222
+	}
+	out.Mode = mode
+	out.Nlink = 1
+	out.Owner = fuse.Owner{Uid: uint32(os.Getuid()), Gid: uint32(os.Getgid())}
+	if !content.Time.IsZero() {
+		out.SetTimes(nil, &content.Time, &content.Time)
+	}
+}
+
+func (n *mountNode) key() string {
+	return n.Path(nil)
+}
+
+func (n *mountNode) childKey(name string) string {
+	return path.Join(n.key(), name)
+}
+
+func (n *mountNode) newChild(ctx context.Context, content *ClientContent, out *fuse.EntryOut) *fs.Inode {
+	n.mfs.fillAttr(content, &out.Attr)
+	out.SetEntryTimeout(n.mfs.opts.cacheTTL)
+	out.SetAttrTimeout(n.mfs.opts.cacheTTL)
+	return n.NewInode(ctx, &mountNode{mfs: n.mfs}, fs.StableAttr{Mode: out.Attr.Mode & syscall.S_IFMT})
+}
+
+func (n *mountNode) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	content, errno := n.mfs.stat(ctx, n.childKey(name))
+	if errno != fs.OK {
+		return nil, errno
+	}
+	return n.newChild(ctx, content, out), fs.OK
+}
+
+func (n *mountNode) Readdir(ctx context.Context) (fs.DirStream, syscall.Errno) {
+	// List the content of the directory, not the directory itself.
+	clnt, err := newClient(n.mfs.url(n.key()) + "/")
+	if err != nil {
+		return nil, toErrno(err)
+	}
+	dir := strings.TrimSuffix(clnt.GetURL().Path, string(clnt.GetURL().Separator))
+
+	var entries []fuse.DirEntry
+	for content := range clnt.List(ctx, ListOptions{ShowDir: DirFirst}) {
+		if content.Err != nil {
+			return nil, toErrno(content.Err)
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(content.URL.Path, dir), string(content.URL.Separator))
+		name = strings.TrimPrefix(name, string(content.URL.Separator))
+		if name == "" || strings.Contains(name, string(content.URL.Separator)) {
+			continue
+		}
+		n.mfs.cache(path.Join(n.key(), name), content)
+		mode := uint32(syscall.S_IFREG)
+		if content.Type.IsDir() {
+			mode = syscall.S_IFDIR
+		}
+		entries = append(entries, fuse.DirEntry{Name: name, Mode: mode})
+	}
+	return fs.NewListDirStream(entries), fs.OK
+}
+
+func (n *mountNode) Getattr(ctx context.Context, f fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	if h, ok := f.(*mountHandle); ok && h.buffer != nil {
+		return h.getattr(out)
+	}
+	content, errno := n.mfs.stat(ctx, n.key())
+	if errno != fs.OK {
+		return errno
+	}
+	n.mfs.fillAttr(content, &out.Attr)
+	out.SetTimeout(n.mfs.opts.cacheTTL)
+	return fs.OK
+}
+
+func (n *mountNode) Setattr(ctx context.Context, f fs.FileHandle, in *fuse.SetAttrIn, out *fuse.AttrOut) syscall.Errno {
+	if size, ok := in.GetSize(); ok {
+		if n.mfs.opts.readOnly {
+			return syscall.EROFS
+		}
+		h, ok := f.(*mountHandle)
+		if !ok {
+			// Truncating a file which is not open goes through a buffer.
+			var errno syscall.Errno
+			if h, errno = n.openHandle(ctx, size != 0); errno != fs.OK {
+				return errno
+			}
+			defer h.Release(ctx)
+		}
+		if errno := h.truncate(int64(size)); errno != fs.OK {
+			return errno
+		}
+		if !ok {
+			if errno := h.Flush(ctx); errno != fs.OK {
+				return errno
+			}
+		}
+	}
+	// Modes, owners and times are not stored.
+	return n.Getattr(ctx, f, out)
+}
+
+// openHandle returns a handle writing to a local buffer, holding the
+// current content of the object unless it is truncated.
+func (n *mountNode) openHandle(ctx context.Context, load bool) (*mountHandle, syscall.Errno) {
+	h := &mountHandle{mfs: n.mfs, key: n.key()}
+	buffer, e := os.CreateTemp(n.mfs.opts.cacheDir, "mc-mount-")
+	if e != nil {
+		return nil, fs.ToErrno(e)
+	}
+	os.Remove(buffer.Name())
+	h.buffer = buffer
+	if !load {
+		h.dirty = true
+		return h, fs.OK
+	}
+
+	clnt, errno := n.mfs.client(h.key)
+	if errno != fs.OK {
+		h.Release(ctx)
+		return nil, errno
+	}
+	reader, err := clnt.Get(ctx, GetOptions{})
+	if err != nil {
+		h.Release(ctx)
+		return nil, toErrno(err)
+	}
+	defer reader.Close()
+	if _, e = io.Copy(buffer, reader); e != nil {
+		h.Release(ctx)
+		return nil, fs.ToErrno(e)
+	}
+	return h, fs.OK
+}
+
+func (n *mountNode) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR) == 0 {
+		// Reads stream the object without buffering it.
+		return &mountHandle{mfs: n.mfs, key: n.key()}, 0, fs.OK
+	}
+	if n.mfs.opts.readOnly {
+		return nil, 0, syscall.EROFS
+	}
+	h, errno := n.openHandle(ctx, flags&syscall.O_TRUNC == 0)
+	if errno != fs.OK {
+		return nil, 0, errno
+	}
+	return h, fuse.FOPEN_DIRECT_IO, fs.OK
+}
+
+func (n *mountNode) Create(ctx context.Context, name string, _, _ uint32, out *fuse.EntryOut) (*fs.Inode, fs.FileHandle, uint32, syscall.Errno) {
+	if n.mfs.opts.readOnly {
+		return nil, nil, 0, syscall.EROFS
+	}
+	key := n.childKey(name)
+	n.mfs.invalidate(key)
+	child := &mountNode{mfs: n.mfs}
+	content := &ClientContent{Time: time.Now()}
+	n.mfs.fillAttr(content, &out.Attr)
+	inode := n.NewInode(ctx, child, fs.StableAttr{Mode: syscall.S_IFREG})
+
+	h := &mountHandle{mfs: n.mfs, key: key, dirty: true}
+	buffer, e := os.CreateTemp(n.mfs.opts.cacheDir, "mc-mount-")
+	if e != nil {
+		return nil, nil, 0, fs.ToErrno(e)
+	}
+	os.Remove(buffer.Name())
+	h.buffer = buffer
+	return inode, h, fuse.FOPEN_DIRECT_IO, fs.OK
+}
+
+func (n *mountNode) Mkdir(ctx context.Context, name string, _ uint32, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	if n.mfs.opts.readOnly {
+		return nil, syscall.EROFS
+	}
+	key := n.childKey(name)
+	clnt, errno := n.mfs.client(key)
+	if errno != fs.OK {
+		return nil, errno
+	}
+	if err := clnt.MakeBucket(ctx, "", true, false); err != nil {
+		return nil, toErrno(err)
+	}
+	n.mfs.invalidate(key)
+	return n.newChild(ctx, &ClientContent{Type: os.ModeDir, Time: time.Now()}, out), fs.OK
+}
+
+func (m *mountFS) remove(ctx context.Context, key string, isDir bool) syscall.Errno {
+	clnt, errno := m.client(key)
+	if errno != fs.OK {
+		return errno
+	}
+	targetURL := clnt.GetURL()
+	if isDir && !strings.HasSuffix(targetURL.Path, string(targetURL.Separator)) {
+		targetURL.Path += string(targetURL.Separator)
+	}
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: targetURL}
+	close(contentCh)
+	for result := range clnt.Remove(ctx, false, false, false, false, contentCh) {
+		if result.Err != nil {
+			return toErrno(result.Err)
+		}
+	}
+	m.invalidate(key)
+	return fs.OK
+}
+
+func (n *mountNode) Unlink(ctx context.Context, name string) syscall.Errno {
+	if n.mfs.opts.readOnly {
+		return syscall.EROFS
+	}
+	return n.mfs.remove(ctx, n.childKey(name), false)
+}
+
+func (n *mountNode) Rmdir(ctx context.Context, name string) syscall.Errno {
+	if n.mfs.opts.readOnly {
+		return syscall.EROFS
+	}
+	key := n.childKey(name)
+	clnt, errno := n.mfs.client(key)
+	if errno != fs.OK {
+		return errno
+	}
+	for content := range clnt.List(ctx, ListOptions{Count: 1}) {
+		if content.Err == nil {
+			return syscall.ENOTEMPTY
+		}
+	}
+	return n.mfs.remove(ctx, key, true)
+}
+
+func (n *mountNode) Rename(ctx context.Context, name string, newParent fs.InodeEmbedder, newName string, _ uint32) syscall.Errno {
+	if n.mfs.opts.readOnly {
+		return syscall.EROFS
+	}
+	srcKey := n.childKey(name)
+	dstKey := path.Join(newParent.EmbeddedInode().Path(nil), newName)
+	content, errno := n.mfs.stat(ctx, srcKey)
+	if errno != fs.OK {
+		return errno
+	}
+	if content.Type.IsDir() {
+		// Renaming a prefix means copying every object below it.
+		return syscall.ENOTSUP
+	}
+
+	src, errno := n.mfs.client(srcKey)
+	if errno != fs.OK {
+		return errno
+	}
+	dst, errno := n.mfs.client(dstKey)
+	if errno != fs.OK {
+		return errno
+	}
+	reader, err := src.Get(ctx, GetOptions{})
+	if err != nil {
+		return toErrno(err)
+	}
+	defer reader.Close()
+	if _, err = dst.Put(ctx, reader, content.Size, nil, PutOptions{}); err != nil {
+		return toErrno(err)
+	}
+	n.mfs.invalidate(dstKey)
+	return n.mfs.remove(ctx, srcKey, false)
+}
+
+// mountHandle is an open file. Writes go to a local buffer which is
+// uploaded when the file is flushed, reads without a buffer stream the
+// object from the current offset.
+type mountHandle struct {
+	mfs *mountFS
+	key string
+
+	mu     sync.Mutex
+	buffer *os.File
+	dirty  bool
+	reader io.ReadCloser
+	offset int64
+}
+
+var (
+	_ fs.FileReader   = (*mountHandle)(nil)
+	_ fs.FileWriter   = (*mountHandle)(nil)
+	_ fs.FileFlusher  = (*mountHandle)(nil)
+	_ fs.FileFsyncer  = (*mountHandle)(nil)
+	_ fs.FileReleaser = (*mountHandle)(nil)
+)
+
+func (h *mountHandle) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.buffer != nil {
+		n, e := h.buffer.ReadAt(dest, off)
+		if e != nil && e != io.EOF {
+			return nil, fs.ToErrno(e)
+		}
+		return fuse.ReadResultData(dest[:n]), fs.OK
+	}
+
+	if h.reader == nil || h.offset != off {
+		if h.reader != nil {
+			h.reader.Close()
+			h.reader = nil
+		}
+		clnt, errno := h.mfs.client(h.key)
+		if errno != fs.OK {
+			return nil, errno
+		}
+		// The reader is not bound to the request, it serves the next reads.
+		reader, err := clnt.Get(context.Background(), GetOptions{RangeStart: off})
+		if err != nil {
+			if errno = toErrno(err); errno == syscall.EINVAL {
+				// Reading past the end of the object.
+				return fuse.ReadResultData(nil), fs.OK
+			}
+			return nil, errno
+		}
+		h.reader, h.offset = reader, off
+	}
+	n, e := io.ReadFull(h.reader, dest)
+	h.offset += int64(n)
+	if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+		return nil, fs.ToErrno(e)
+	}
+	return fuse.ReadResultData(dest[:n]), fs.OK
+}
+
+func (h *mountHandle) Write(_ context.Context, data []byte, off int64) (uint32, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.buffer == nil {
+		return 0, syscall.EBADF
+	}
+	n, e := h.buffer.WriteAt(data, off)
+	h.dirty = true
+	return uint32(n), fs.ToErrno(e)
+}
+
+func (h *mountHandle) truncate(size int64) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.buffer == nil {
+		return syscall.EBADF
+	}
+	h.dirty = true
+	return fs.ToErrno(h.buffer.Truncate(size))
+}
+
+func (h *mountHandle) getattr(out *fuse.AttrOut) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st, e := h.buffer.Stat()
+	if e != nil {
+		return fs.ToErrno(e)
+	}
+	h.mfs.fillAttr(&ClientContent{Size: st.Size(), Time: st.ModTime()}, &out.Attr)
+	return fs.OK
+}
+
+// Flush uploads the buffer when it was modified, large buffers are sent
+// with a multipart upload.
+func (h *mountHandle) Flush(ctx context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.buffer == nil || !h.dirty {
+		return fs.OK
+	}
+	st, e := h.buffer.Stat()
+	if e != nil {
+		return fs.ToErrno(e)
+	}
+	clnt, errno := h.mfs.client(h.key)
+	if errno != fs.OK {
+		return errno
+	}
+	reader := io.NewSectionReader(h.buffer, 0, st.Size())
+	if _, err := clnt.Put(ctx, reader, st.Size(), nil, PutOptions{}); err != nil {
+		return toErrno(err)
+	}
+	h.dirty = false
+	h.mfs.invalidate(h.key)
+	return fs.OK
+}
+
+func (h *mountHandle) Fsync(ctx context.Context, _ uint32) syscall.Errno {
+	return h.Flush(ctx)
+}
+
+func (h *mountHandle) Release(_ context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.reader != nil {
+		h.reader.Close()
+		h.reader = nil
+	}
+	if h.buffer != nil {
+		h.buffer.Close()
+		h.buffer = nil
+	}
+	return fs.OK
+}
+
+// mountTarget serves the target on the mount point until the command is
+// interrupted or the filesystem is unmounted.
+func mountTarget(target, mountPoint string, opts mountOptions, mounted func()) *probe.Error {
+	root := &mountNode{mfs: &mountFS{
+		target: target,
+		opts:   opts,
+		attrs:  map[string]mountAttr{},
+	}}
+	ttl := opts.cacheTTL
+	server, e := fs.Mount(mountPoint, root, &fs.Options{
+		EntryTimeout:    &ttl,
+		AttrTimeout:     &ttl,
+		NegativeTimeout: &ttl,
+		MountOptions: fuse.MountOptions{
+			FsName:     target,
+			Name:       "mc",
+			AllowOther: opts.allowOther,
+			Debug:      globalDebug,
+			// Mount without fusermount when running as root.
+			DirectMount: true,
+		},
+	})
+	if e != nil {
+		return probe.NewError(e)
+	}
+	// Unmount when mc is interrupted.
+	onExit(func(int) {
+		server.Unmount()
+	})
+	mounted()
+	server.Wait()
+	return nil
+}
@@ -0,0 +1,132 @@
+//go:build linux || darwin
+
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestToErrno(t *testing.T) {
+	testCases := []struct {
+		err      *probe.Error
+		expected syscall.Errno
+	}{
+		{nil, fs.OK},
+		{probe.NewError(ObjectMissing{}), syscall.ENOENT},
+		{probe.NewError(PathNotFound{Path: "a"}), syscall.ENOENT},
+		{probe.NewError(BucketDoesNotExist{Bucket: "bucket"}), syscall.ENOENT},
+		{probe.NewError(PathInsufficientPermission{Path: "a"}), syscall.EACCES},
+		// The errors of the system are kept.
+		{probe.NewError(syscall.ENOSPC), syscall.ENOSPC},
+		{probe.NewError(&os.PathError{Op: "open", Path: "a", Err: syscall.EROFS}), syscall.EROFS},
+		{probe.NewError(os.ErrNotExist), syscall.ENOENT},
+		{probe.NewError(os.ErrPermission), syscall.EACCES},
+		// The errors without a status code are I/O errors.
+		{probe.NewError(errors.New("connection reset")), syscall.EIO},
+	}
+	for i, testCase := range testCases {
+		if errno := toErrno(testCase.err); errno != testCase.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, errno)
+		}
+	}
+}
+
+func TestMountFillAttr(t *testing.T) {
+	modTime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		content  ClientContent
+		readOnly bool
+		mode     uint32
+		size     uint64
+		blocks   uint64
+	}{
+		{ClientContent{Size: 1000, Time: modTime}, false, syscall.S_IFREG | 0o644, 1000, 2},
+		{ClientContent{Size: 512}, false, syscall.S_IFREG | 0o644, 512, 1},
+		{ClientContent{Size: 1000, Time: modTime}, true, syscall.S_IFREG | 0o444, 1000, 2},
+		// The size of a directory is not reported.
+		{ClientContent{Type: os.ModeDir, Size: 4096}, false, syscall.S_IFDIR | 0o755, 0, 0},
+		{ClientContent{Type: os.ModeDir}, true, syscall.S_IFDIR | 0o555, 0, 0},
+	}
+	for i, testCase := range testCases {
+		m := &mountFS{opts: mountOptions{readOnly: testCase.readOnly}}
+		var out fuse.Attr
+		m.fillAttr(&testCase.content, &out)
+		if out.Mode != testCase.mode {
+			t.Errorf("Test %d: expected mode %o, got %o", i+1, testCase.mode, out.Mode)
+		}
+		if out.Size != testCase.size || out.Blocks != testCase.blocks {
+			t.Errorf("Test %d: expected size %d blocks %d, got %d %d", i+1, testCase.size, testCase.blocks, out.Size, out.Blocks)
+		}
+		if out.Uid != uint32(os.Getuid()) || out.Gid != uint32(os.Getgid()) || out.Nlink != 1 {
+			t.Errorf("Test %d: unexpected owner %+v", i+1, out)
+		}
+		if !testCase.content.Time.IsZero() && (out.Mtime != uint64(modTime.Unix()) || out.Ctime != uint64(modTime.Unix())) {
+			t.Errorf("Test %d: expected the times of the object, got %+v", i+1, out)
+		}
+	}
+}
+
+func TestMountStat(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) { return newConfigV10(), nil }
+
+	dir := t.TempDir()
+	if e := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("data"), 0o600); e != nil {
+		t.Fatal(e)
+	}
+	m := &mountFS{target: dir, opts: mountOptions{cacheTTL: time.Hour}, attrs: map[string]mountAttr{}}
+	ctx := context.Background()
+
+	content, errno := m.stat(ctx, "a.txt")
+	if errno != fs.OK || content.Size != 4 {
+		t.Fatalf("expected the stat of a.txt, got %+v %v", content, errno)
+	}
+	if _, errno = m.stat(ctx, "b.txt"); errno != syscall.ENOENT {
+		t.Fatalf("expected ENOENT, got %v", errno)
+	}
+
+	// The stats, missing paths included, are cached until invalidated.
+	if e := os.Rename(filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")); e != nil {
+		t.Fatal(e)
+	}
+	if _, errno = m.stat(ctx, "a.txt"); errno != fs.OK {
+		t.Errorf("expected the cached stat of a.txt, got %v", errno)
+	}
+	if _, errno = m.stat(ctx, "b.txt"); errno != syscall.ENOENT {
+		t.Errorf("expected b.txt to be cached as missing, got %v", errno)
+	}
+	m.invalidate("a.txt")
+	m.invalidate("b.txt")
+	if _, errno = m.stat(ctx, "a.txt"); errno != syscall.ENOENT {
+		t.Errorf("expected ENOENT once invalidated, got %v", errno)
+	}
+	if _, errno = m.stat(ctx, "b.txt"); errno != fs.OK {
+		t.Errorf("expected the stat of b.txt once invalidated, got %v", errno)
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+)
+
+const (
+	notifyDesktop = "desktop"
+	notifyWebhook = "webhook"
+	notifySMTP    = "smtp"
+
+	// notifyTimeout bounds the delivery of a notification, mc exits after it.
+	notifyTimeout = 30 * time.Second
+)
+
+var notifyOnCompletionFlag = cli.StringFlag{
+	Name:  "notify-on-completion",
+	Usage: "notify when the command finishes or fails, PROFILE is \"desktop\", a webhook URL or a notify profile of the config",
+}
+
+// completionNotification is sent when a command run with
+// --notify-on-completion finishes or fails.
+type completionNotification struct {
+	Status     string    `json:"status"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args"`
+	Host       string    `json:"host"`
+	Started    time.Time `json:"started"`
+	Finished   time.Time `json:"finished"`
+	Duration   string    `json:"duration"`
+	Objects    int64     `json:"objects"`
+	Bytes      int64     `json:"bytes"`
+	ExitStatus int       `json:"exitStatus"`
+}
+
+// Title is the subject of the notification.
+func (n completionNotification) Title() string {
+	if n.Status == "success" {
+		return fmt.Sprintf("mc %s finished on %s", n.Command, n.Host)
+	}
+	return fmt.Sprintf("mc %s failed on %s", n.Command, n.Host)
+}
+
+// Body is the text of the notification.
+func (n completionNotification) Body() string {
+	body := fmt.Sprintf("mc %s %s\n%d object(s), %s in %s, exit status %d.",
+		n.Command, strings.Join(n.Args, " "), n.Objects, humanize.IBytes(uint64(n.Bytes)), n.Duration, n.ExitStatus)
+	return strings.TrimSpace(body)
+}
+
+var (
+	notifyMu         sync.Mutex
+	notifyProfile    *notifyProfileV10
+	notifyStart      time.Time
+	notifyCommand    string
+	notifyArgs       []string
+	notifyRegistered bool
+
+	// Set by the commands whose outcome is not their own, see setNotifyOutcome.
+	notifyOutcome *completionNotification
+)
+
+// resolveNotifyProfile returns the profile of --notify-on-completion, a
+// profile of the config, "desktop" or a webhook URL.
+func resolveNotifyProfile(name string) (*notifyProfileV10, *probe.Error) {
+	var profiles map[string]notifyProfileV10
+	if loadMcConfig != nil {
+		if conf, err := loadMcConfig(); err == nil {
+			profiles = conf.Notify
+		}
+	}
+	profile, ok := profiles[name]
+	switch {
+	case ok:
+	case name == notifyDesktop:
+		profile = notifyProfileV10{Type: notifyDesktop}
+	case strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://"):
+		profile = notifyProfileV10{Type: notifyWebhook, Endpoint: name}
+	default:
+		return nil, probe.NewError(fmt.Errorf("no notify profile `%s` in the config", name))
+	}
+
+	switch profile.Type {
+	case notifyDesktop:
+		if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+			return nil, probe.NewError(fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS))
+		}
+	case notifyWebhook:
+		if profile.Endpoint == "" {
+			return nil, probe.NewError(fmt.Errorf("the webhook profile `%s` has no endpoint", name))
+		}
+	case notifySMTP:
+		if profile.Host == "" || profile.From == "" || len(profile.To) == 0 {
+			return nil, probe.NewError(fmt.Errorf("the smtp profile `%s` needs a host, a sender and recipients", name))
+		}
+		if _, _, e := net.SplitHostPort(profile.Host); e != nil {
+			return nil, probe.NewError(e).Trace(profile.Host)
+		}
+	default:
+		return nil, probe.NewError(fmt.Errorf("unknown type `%s` of the notify profile `%s`", profile.Type, name))
+	}
+	return &profile, nil
+}
+
+// enableCompletionNotify sends the notification of --notify-on-completion
+// when mc exits.
+func enableCompletionNotify(ctx *cli.Context) {
+	name := ctx.String("notify-on-completion")
+	if name == "" {
+		return
+	}
+	profile, err := resolveNotifyProfile(name)
+	fatalIf(err.Trace(name), "Invalid --notify-on-completion.")
+
+	notifyMu.Lock()
+	defer notifyMu.Unlock()
+
+	if !notifyRegistered {
+		notifyRegistered = true
+		onExit(sendCompletionNotification)
+	}
+	notifyProfile = profile
+	notifyStart = time.Now()
+	notifyCommand = commandPath(ctx)
+	notifyArgs = redactAuditArgs(notifyCommand, auditCommandArgs(ctx))
+	notifyOutcome = nil
+}
+
+// setNotifyOutcome overrides the objects, bytes and success of the
+// notification, for the commands following a job running elsewhere.
+func setNotifyOutcome(objects, bytes int64, failed bool) {
+	notifyMu.Lock()
+	defer notifyMu.Unlock()
+	notifyOutcome = &completionNotification{Objects: objects, Bytes: bytes, Status: "success"}
+	if failed {
+		notifyOutcome.Status = "failure"
+	}
+}
+
+// newCompletionNotification returns the notification of the running command.
+func newCompletionNotification(exitStatus int) completionNotification {
+	n := completionNotification{
+		Status:     "success",
+		Command:    notifyCommand,
+		Args:       notifyArgs,
+		Started:    notifyStart.UTC(),
+		Finished:   time.Now().UTC(),
+		Duration:   time.Since(notifyStart).Round(time.Second).String(),
+		Objects:    objectsDone(),
+		Bytes:      atomic.LoadInt64(&auditBytes),
+		ExitStatus: exitStatus,
+	}
+	n.Host, _ = os.Hostname()
+	if exitStatus != 0 {
+		n.Status = "failure"
+	}
+	if notifyOutcome != nil {
+		n.Objects, n.Bytes = notifyOutcome.Objects, notifyOutcome.Bytes
+		if notifyOutcome.Status != "success" {
+			n.Status = notifyOutcome.Status
+		}
+	}
+	return n
+}
+
+// sendCompletionNotification sends the notification of the command, once.
+// Failures are reported but do not change the exit status.
+func sendCompletionNotification(exitStatus int) {
+	notifyMu.Lock()
+	defer notifyMu.Unlock()
+
+	if notifyProfile == nil {
+		return
+	}
+	profile := *notifyProfile
+	notifyProfile = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	errorIf(sendNotification(ctx, profile, newCompletionNotification(exitStatus)).Trace(profile.Type),
+		"Unable to send the notification of --notify-on-completion.")
+}
+
+// sendNotification delivers a notification to a profile.
+func sendNotification(ctx context.Context, profile notifyProfileV10, n completionNotification) *probe.Error {
+	switch profile.Type {
+	case notifyDesktop:
+		return sendDesktopNotification(ctx, n)
+	case notifyWebhook:
+		return sendWebhookNotification(ctx, profile, n)
+	case notifySMTP:
+		return sendSMTPNotification(ctx, profile, n)
+	}
+	return probe.NewError(fmt.Errorf("unknown notify profile type `%s`", profile.Type))
+}
+
+func sendDesktopNotification(ctx context.Context, n completionNotification) *probe.Error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.CommandContext(ctx, "notify-send", n.Title(), n.Body())
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", n.Body(), n.Title())
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	default:
+		return probe.NewError(fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS))
+	}
+	if out, e := cmd.CombinedOutput(); e != nil {
+		return probe.NewError(fmt.Errorf("%v: %s", e, bytes.TrimSpace(out)))
+	}
+	return nil
+}
+
+func sendWebhookNotification(ctx context.Context, profile notifyProfileV10, n completionNotification) *probe.Error {
+	body, e := json.Marshal(n)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, profile.Endpoint, bytes.NewReader(body))
+	if e != nil {
+		return probe.NewError(e)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if profile.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+profile.AuthToken)
+	}
+	resp, e := http.DefaultClient.Do(req)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return probe.NewError(errors.New(resp.Status)).Trace(profile.Endpoint)
+	}
+	return nil
+}
+
+func sendSMTPNotification(ctx context.Context, profile notifyProfileV10, n completionNotification) *probe.Error {
+	var auth smtp.Auth
+	if profile.Username != "" {
+		host, _, _ := net.SplitHostPort(profile.Host)
+		auth = smtp.PlainAuth("", profile.Username, profile.Password, host)
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", profile.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(profile.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", n.Title())
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n", strings.ReplaceAll(n.Body(), "\n", "\r\n"))
+	if e := sendMail(ctx, profile.Host, auth, profile.From, profile.To, msg.Bytes()); e != nil {
+		return probe.NewError(e).Trace(profile.Host)
+	}
+	return nil
+}
+
+// sendMail is smtp.SendMail bounded by the context, the connection is
+// closed once the context is done.
+func sendMail(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) (err error) {
+	var dialer net.Dialer
+	conn, e := dialer.DialContext(ctx, "tcp", addr)
+	if e != nil {
+		return e
+	}
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+	}()
+
+	host, _, _ := net.SplitHostPort(addr)
+	c, e := smtp.NewClient(conn, host)
+	if e != nil {
+		return e
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if e = c.StartTLS(&tls.Config{ServerName: host}); e != nil {
+			return e
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if e = c.Auth(auth); e != nil {
+			return e
+		}
+	}
+	if e = c.Mail(from); e != nil {
+		return e
+	}
+	for _, rcpt := range to {
+		if e = c.Rcpt(rcpt); e != nil {
+			return e
+		}
+	}
+	w, e := c.Data()
+	if e != nil {
+		return e
+	}
+	if _, e = w.Write(msg); e != nil {
+		return e
+	}
+	if e = w.Close(); e != nil {
+		return e
+	}
+	return c.Quit()
+}
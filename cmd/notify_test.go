@@ -0,0 +1,182 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestResolveNotifyProfile(t *testing.T) {
+	defer func(load func() (*configV10, *probe.Error)) { loadMcConfig = load }(loadMcConfig)
+	loadMcConfig = func() (*configV10, *probe.Error) {
+		conf := newConfigV10()
+		conf.Notify = map[string]notifyProfileV10{
+			"ops":    {Type: notifyWebhook, Endpoint: "https://hooks.example.com/mc", AuthToken: "token"},
+			"oncall": {Type: notifySMTP, Host: "smtp.example.com:587", From: "mc@example.com", To: []string{"oncall@example.com"}},
+			"nohost": {Type: notifySMTP, From: "mc@example.com", To: []string{"oncall@example.com"}},
+			"noport": {Type: notifySMTP, Host: "smtp.example.com", From: "mc@example.com", To: []string{"oncall@example.com"}},
+			"empty":  {Type: notifyWebhook},
+			"pager":  {Type: "pager"},
+		}
+		return conf, nil
+	}
+
+	testCases := []struct {
+		name     string
+		typ      string
+		endpoint string
+		invalid  bool
+	}{
+		{name: "ops", typ: notifyWebhook, endpoint: "https://hooks.example.com/mc"},
+		{name: "oncall", typ: notifySMTP},
+		{name: "https://example.com/hook", typ: notifyWebhook, endpoint: "https://example.com/hook"},
+		{name: "nohost", invalid: true},
+		{name: "noport", invalid: true},
+		{name: "empty", invalid: true},
+		{name: "pager", invalid: true},
+		{name: "missing", invalid: true},
+	}
+	for i, testCase := range testCases {
+		profile, err := resolveNotifyProfile(testCase.name)
+		if testCase.invalid {
+			if err == nil {
+				t.Errorf("Test %d: expected an error for %q", i+1, testCase.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if profile.Type != testCase.typ || profile.Endpoint != testCase.endpoint {
+			t.Errorf("Test %d: unexpected profile %+v", i+1, profile)
+		}
+	}
+}
+
+func TestSendWebhookNotification(t *testing.T) {
+	var got completionNotification
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if e := json.NewDecoder(r.Body).Decode(&got); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	n := completionNotification{Status: "failure", Command: "mirror", Args: []string{"s3/a", "myminio/a"}, Host: "h", Objects: 3, ExitStatus: 1}
+	profile := notifyProfileV10{Type: notifyWebhook, Endpoint: server.URL, AuthToken: "token"}
+	if err := sendNotification(context.Background(), profile, n); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("expected a bearer token, got %q", auth)
+	}
+	if got.Status != "failure" || got.Command != "mirror" || got.Objects != 3 {
+		t.Errorf("unexpected notification %+v", got)
+	}
+	if want := "mc mirror failed on h"; n.Title() != want {
+		t.Errorf("expected %q, got %q", want, n.Title())
+	}
+
+	profile.Endpoint = server.URL + "/%zz"
+	if err := sendNotification(context.Background(), profile, n); err == nil {
+		t.Error("expected an error for an invalid endpoint")
+	}
+}
+
+func TestSendSMTPNotification(t *testing.T) {
+	l, e := net.Listen("tcp", "127.0.0.1:0")
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer l.Close()
+
+	// The server accepts the message of the first connection, the next
+	// ones are never answered.
+	messages := make(chan string, 1)
+	go func() {
+		conn, e := l.Accept()
+		if e != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, e := r.ReadString('\n')
+			if e != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "DATA"):
+				reply("354 go ahead")
+				for {
+					line, e = r.ReadString('\n')
+					if e != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				messages <- data.String()
+				reply("250 ok")
+			case strings.HasPrefix(cmd, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	n := completionNotification{Status: "success", Command: "mirror", Host: "h"}
+	profile := notifyProfileV10{Type: notifySMTP, Host: l.Addr().String(), From: "mc@example.com", To: []string{"oncall@example.com"}}
+	if err := sendNotification(context.Background(), profile, n); err != nil {
+		t.Fatal(err)
+	}
+	if msg := <-messages; !strings.Contains(msg, "Subject: "+n.Title()+"\r\n") {
+		t.Errorf("unexpected message %q", msg)
+	}
+
+	// The delivery is bounded by the context.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := sendNotification(ctx, profile, n)
+	if err == nil || !errors.Is(err.ToGoError(), context.DeadlineExceeded) {
+		t.Errorf("expected the deadline of the context, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the delivery to stop with the context, stopped after %v", elapsed)
+	}
+}
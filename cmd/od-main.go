@@ -20,7 +20,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	json "github.com/minio/colorjson"
@@ -45,11 +47,14 @@ USAGE:
   {{.HelpName}} [OPERANDS]
 
 OPERANDS:
-  if=        source stream to upload
-  of=        target path to upload to
-  size=      size of each part. If not specified, will be calculated from the source stream size.
-  parts=     number of parts to upload. If not specified, will calculated from the source file size.
-  skip=      number of parts to skip.
+  if=          source stream to upload
+  of=          target path to upload to, /dev/null to only measure reading the source
+  size=        size of each part. If not specified, will be calculated from the source stream size.
+  parts=       number of parts to upload. If not specified, will calculated from the source file size.
+  skip=        number of parts to skip.
+  iterations=  number of times to transfer the source, defaults to 1.
+  concurrency= number of transfers running at the same time, defaults to 1. Each concurrent
+               upload writes to the target path suffixed by '.N'.
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -63,6 +68,12 @@ EXAMPLES:
 
   3. Upload a full file to a bucket in 5 parts.
       {{.HelpName}} if=file.txt of=play/my-bucket/file.txt parts=5
+
+  4. Measure the download throughput and latency percentiles of an object over 20 reads.
+      {{.HelpName}} if=play/my-bucket/file.txt of=/dev/null iterations=20
+
+  5. Upload 1GiB from /dev/zero 16 times, 4 streams at a time, printing the results in JSON.
+      {{.HelpName}} --json if=/dev/zero of=play/my-bucket/zero size=64MiB parts=16 iterations=16 concurrency=4
 `,
 }
 
@@ -76,16 +87,58 @@ type odMessage struct {
 	Parts     int    `json:"parts"`
 	Skip      int    `json:"skip"`
 	Elapsed   int64  `json:"elapsed"`
+
+	Iterations  int        `json:"iterations,omitempty"`
+	Concurrency int        `json:"concurrency,omitempty"`
+	Throughput  float64    `json:"throughput,omitempty"`
+	Latency     *odLatency `json:"latency,omitempty"`
+	FirstByte   *odLatency `json:"firstByte,omitempty"`
+
+	// Durations of a single transfer, summarized in Latency and FirstByte.
+	elapsed   time.Duration
+	firstByte time.Duration
+}
+
+// odLatency summarizes the durations of the transfers, in milliseconds.
+type odLatency struct {
+	Min float64 `json:"min"`
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+func (l odLatency) String() string {
+	ms := func(v float64) time.Duration {
+		return time.Duration(v * float64(time.Millisecond)).Round(time.Microsecond)
+	}
+	return fmt.Sprintf("min %s, p50 %s, p90 %s, p99 %s, max %s", ms(l.Min), ms(l.P50), ms(l.P90), ms(l.P99), ms(l.Max))
 }
 
 func (o odMessage) String() string {
 	cleanSize := humanize.IBytes(uint64(o.TotalSize))
 	elapsed := time.Duration(o.Elapsed) * time.Millisecond
 	speed := humanize.IBytes(uint64(float64(o.TotalSize) / elapsed.Seconds()))
-	if o.Type == "S3toFS" && o.Parts == 0 {
-		return fmt.Sprintf("Transferred: %s, Full file, Time: %s, Speed: %s/s", cleanSize, elapsed, speed)
+	var msg string
+	if (o.Type == "S3toFS" || strings.HasSuffix(o.Type, "toNull")) && o.Parts == 0 {
+		msg = fmt.Sprintf("Transferred: %s, Full file, Time: %s, Speed: %s/s", cleanSize, elapsed, speed)
+	} else {
+		msg = fmt.Sprintf("Transferred: %s, Parts: %d, Time: %s, Speed: %s/s", cleanSize, o.Parts, elapsed, speed)
+	}
+	if o.Iterations > 1 {
+		msg += fmt.Sprintf(", Iterations: %d, Concurrency: %d", o.Iterations, o.Concurrency)
+		if o.Latency != nil {
+			msg += "\nLatency: " + o.Latency.String()
+		}
+	}
+	if o.FirstByte != nil {
+		if o.Iterations > 1 {
+			msg += "\nFirst byte: " + o.FirstByte.String()
+		} else {
+			msg += fmt.Sprintf("\nFirst byte: %s", time.Duration(o.FirstByte.P50*float64(time.Millisecond)).Round(time.Microsecond))
+		}
 	}
-	return fmt.Sprintf("Transferred: %s, Parts: %d, Time: %s, Speed: %s/s", cleanSize, o.Parts, elapsed, speed)
+	return msg
 }
 
 func (o odMessage) JSON() string {
@@ -100,6 +153,19 @@ func getOdUrls(ctx context.Context, args argKVS) (odURLs URLs, e error) {
 	inFile := args.Get("if")
 	outFile := args.Get("of")
 
+	// Reading to /dev/null only measures the source, do not write to it.
+	if outFile == odNull {
+		sourceAlias, _, _ := mustExpandAlias(inFile)
+		_, sourceContent, err := url2Stat(ctx, inFile, "", false, nil, time.Time{}, false)
+		if err != nil {
+			return URLs{}, err.ToGoError()
+		}
+		if sourceContent.Type.IsDir() {
+			return URLs{}, fmt.Errorf("invalid source path %s, source cannot be a directory", inFile)
+		}
+		return URLs{SourceAlias: sourceAlias, SourceContent: sourceContent}, nil
+	}
+
 	// Check if outFile is a folder or a file.
 	opts := prepareCopyURLsOpts{
 		sourceURLs: []string{inFile},
@@ -141,7 +207,10 @@ func prepareOdUrls(ctx context.Context, sourceURL, sourceVersion string, targetU
 }
 
 // odCheckType checks if request is a download or upload and calls the appropriate function
-func odCheckType(ctx context.Context, odURLs URLs, args argKVS) (message, error) {
+func odCheckType(ctx context.Context, odURLs URLs, args argKVS) (odMessage, error) {
+	if odURLs.TargetContent == nil {
+		return odRead(ctx, odURLs, args)
+	}
 	if odURLs.SourceAlias != "" && odURLs.TargetAlias == "" {
 		return odDownload(ctx, odURLs, args)
 	}
@@ -176,10 +245,59 @@ func mainOD(cliCtx *cli.Context) error {
 	odURLs, e := getOdUrls(ctx, kvsArgs)
 	fatalIf(probe.NewError(e), "Unable to get source and target URLs")
 
-	message, e := odCheckType(ctx, odURLs, kvsArgs)
-	fatalIf(probe.NewError(e), "Unable to transfer object")
+	iterations, e := odCount(kvsArgs, "iterations")
+	fatalIf(probe.NewError(e), "Invalid number of iterations")
+	concurrency, e := odCount(kvsArgs, "concurrency")
+	fatalIf(probe.NewError(e), "Invalid concurrency")
+	if concurrency > iterations {
+		concurrency = iterations
+	}
+
+	results := make([]odMessage, iterations)
+	runs := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		workerURLs := odURLs
+		if concurrency > 1 && odURLs.TargetContent != nil {
+			// Concurrent uploads must not race on the same target.
+			targetContent := *odURLs.TargetContent
+			targetContent.URL.Path += "." + strconv.Itoa(w+1)
+			workerURLs.TargetContent = &targetContent
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range runs {
+				message, e := odCheckType(ctx, workerURLs, kvsArgs)
+				fatalIf(probe.NewError(e), "Unable to transfer object")
+				results[i] = message
+			}
+		}()
+	}
+	for i := 0; i < iterations; i++ {
+		runs <- i
+	}
+	close(runs)
+	wg.Wait()
 
 	// Print message.
-	printMsg(message)
+	printMsg(odSummarize(results, time.Since(start), concurrency))
 	return nil
 }
+
+// odCount parses a positive count operand, defaulting to 1.
+func odCount(args argKVS, key string) (int, error) {
+	v := args.Get(key)
+	if v == "" {
+		return 1, nil
+	}
+	n, e := strconv.Atoi(v)
+	if e != nil {
+		return 0, e
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("%s must be at least 1", key)
+	}
+	return n, nil
+}
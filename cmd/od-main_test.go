@@ -0,0 +1,102 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestODCount(t *testing.T) {
+	testCases := []struct {
+		value   string
+		count   int
+		invalid bool
+	}{
+		{"", 1, false},
+		{"8", 8, false},
+		{"0", 0, true},
+		{"-2", 0, true},
+		{"many", 0, true},
+	}
+	for i, testCase := range testCases {
+		var args argKVS
+		if testCase.value != "" {
+			args.Set("iterations", testCase.value)
+		}
+		count, e := odCount(args, "iterations")
+		if testCase.invalid != (e != nil) {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if count != testCase.count {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.count, count)
+		}
+	}
+}
+
+func TestODPercentiles(t *testing.T) {
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	expected := odLatency{Min: 1, P50: 50, P90: 90, P99: 99, Max: 100}
+	if l := odPercentiles(durations); *l != expected {
+		t.Errorf("expected %+v, got %+v", expected, *l)
+	}
+
+	expected = odLatency{Min: 7, P50: 7, P90: 7, P99: 7, Max: 7}
+	if l := odPercentiles([]time.Duration{7 * time.Millisecond}); *l != expected {
+		t.Errorf("expected %+v, got %+v", expected, *l)
+	}
+}
+
+func TestODSummarize(t *testing.T) {
+	results := []odMessage{
+		{Type: "S3toNull", TotalSize: 100, elapsed: 30 * time.Millisecond, firstByte: 3 * time.Millisecond},
+		{Type: "S3toNull", TotalSize: 100, elapsed: 10 * time.Millisecond, firstByte: time.Millisecond},
+		{Type: "S3toNull", TotalSize: 100, elapsed: 20 * time.Millisecond, firstByte: 2 * time.Millisecond},
+		{Type: "S3toNull", TotalSize: 100, elapsed: 40 * time.Millisecond},
+	}
+	summary := odSummarize(results, 2*time.Second, 2)
+	if summary.TotalSize != 400 || summary.Iterations != 4 || summary.Concurrency != 2 || summary.Elapsed != 2000 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if summary.Throughput != 200 {
+		t.Errorf("expected 200 bytes/s, got %v", summary.Throughput)
+	}
+	if summary.Latency == nil || summary.Latency.Min != 10 || summary.Latency.P50 != 20 || summary.Latency.Max != 40 {
+		t.Errorf("unexpected latency %+v", summary.Latency)
+	}
+	// The transfers without first byte are not counted.
+	if summary.FirstByte == nil || summary.FirstByte.Min != 1 || summary.FirstByte.Max != 3 {
+		t.Errorf("unexpected first byte latency %+v", summary.FirstByte)
+	}
+	if msg := summary.String(); !strings.Contains(msg, "Iterations: 4, Concurrency: 2") || !strings.Contains(msg, "Latency: min 10ms") {
+		t.Errorf("unexpected message %q", msg)
+	}
+
+	// A single transfer has no latency distribution.
+	summary = odSummarize(results[:1], 30*time.Millisecond, 1)
+	if summary.Latency != nil || summary.FirstByte == nil || summary.FirstByte.P50 != 3 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if msg := summary.String(); !strings.Contains(msg, "Full file") || !strings.Contains(msg, "First byte: 3ms") {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"io"
 	"math"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"time"
 
@@ -174,6 +175,7 @@ func odCopy(ctx context.Context, odURLs URLs, args argKVS, odType string) (odMes
 		Parts:     parts,
 		Skip:      int(uint64(skip) / partSize),
 		Elapsed:   elapsed.Milliseconds(),
+		elapsed:   elapsed,
 	}
 
 	return message, nil
@@ -256,11 +258,120 @@ func odDownload(ctx context.Context, odURLs URLs, args argKVS) (odMessage, error
 		Parts:     parts,
 		Skip:      skip,
 		Elapsed:   elapsed.Milliseconds(),
+		elapsed:   elapsed,
 	}
 
 	return message, nil
 }
 
+// odNull is the target discarding the source, to measure reads alone.
+const odNull = "/dev/null"
+
+// odRead reads a file/object and discards it.
+func odRead(ctx context.Context, odURLs URLs, args argKVS) (odMessage, error) {
+	parts, skip, e := odSetParts(args)
+	if e != nil {
+		return odMessage{}, e
+	}
+
+	sourceAlias := odURLs.SourceAlias
+	sourceURL := odURLs.SourceContent.URL
+	sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
+	odType := "FStoNull"
+	if sourceAlias != "" {
+		odType = "S3toNull"
+	}
+
+	cli, err := newClientFromAlias(sourceAlias, sourceURL.String())
+	fatalIf(err, "Unable to initialize client")
+
+	start := time.Now()
+	var reader io.Reader
+	if parts == 0 {
+		rc, err := cli.Get(ctx, GetOptions{})
+		fatalIf(err.Trace(sourcePath), "Unable to download object")
+		defer rc.Close()
+		reader = rc
+	} else {
+		reader = multiGet(ctx, cli, parts, skip)
+	}
+
+	// Time to first byte, the latency of the request itself.
+	buf := make([]byte, 1)
+	n, e := io.ReadFull(reader, buf)
+	if e != nil && e != io.EOF {
+		return odMessage{}, e
+	}
+	firstByte := time.Since(start)
+	total, e := io.Copy(io.Discard, reader)
+	if e != nil {
+		return odMessage{}, e
+	}
+	total += int64(n)
+	elapsed := time.Since(start)
+
+	return odMessage{
+		Status:    "success",
+		Type:      odType,
+		Source:    sourcePath,
+		Target:    odNull,
+		TotalSize: total,
+		Parts:     parts,
+		Skip:      skip,
+		Elapsed:   elapsed.Milliseconds(),
+		elapsed:   elapsed,
+		firstByte: firstByte,
+	}, nil
+}
+
+// odSummarize combines the results of all the transfers, the throughput
+// is computed over the wall clock time of all of them.
+func odSummarize(results []odMessage, wallClock time.Duration, concurrency int) odMessage {
+	summary := results[0]
+	summary.TotalSize = 0
+	elapsed := make([]time.Duration, 0, len(results))
+	firstByte := make([]time.Duration, 0, len(results))
+	for _, result := range results {
+		summary.TotalSize += result.TotalSize
+		elapsed = append(elapsed, result.elapsed)
+		if result.firstByte > 0 {
+			firstByte = append(firstByte, result.firstByte)
+		}
+	}
+	summary.Elapsed = wallClock.Milliseconds()
+	summary.Iterations = len(results)
+	summary.Concurrency = concurrency
+	if wallClock > 0 {
+		summary.Throughput = float64(summary.TotalSize) / wallClock.Seconds()
+	}
+	if len(results) > 1 {
+		summary.Latency = odPercentiles(elapsed)
+	}
+	if len(firstByte) > 0 {
+		summary.FirstByte = odPercentiles(firstByte)
+	}
+	return summary
+}
+
+// odPercentiles returns the nearest-rank percentiles of the durations.
+func odPercentiles(durations []time.Duration) *odLatency {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(durations)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return float64(durations[i]) / float64(time.Millisecond)
+	}
+	return &odLatency{
+		Min: rank(0),
+		P50: rank(50),
+		P90: rank(90),
+		P99: rank(99),
+		Max: rank(100),
+	}
+}
+
 // singleGet helps odDownload download a single part.
 func singleGet(ctx context.Context, cli Client) io.ReadCloser {
 	reader, err := cli.GetPart(ctx, 0)
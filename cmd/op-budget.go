@@ -0,0 +1,112 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dustin/go-humanize"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+)
+
+// The budgets are usually set in the environment, so that every recursive
+// operation is bounded, and lifted with --no-limit by the scripts which
+// really mean to operate on more objects.
+var (
+	maxDeleteFlag = cli.Int64Flag{
+		Name:   "max-delete",
+		Usage:  "abort once more than N objects would be removed",
+		EnvVar: "MC_MAX_DELETE",
+	}
+	maxCopyBytesFlag = cli.StringFlag{
+		Name:   "max-copy-bytes",
+		Usage:  "abort once more than SIZE would be copied, e.g. 100GiB",
+		EnvVar: "MC_MAX_COPY_BYTES",
+	}
+	noLimitFlag = cli.BoolFlag{
+		Name:  "no-limit",
+		Usage: "lift the --max-delete and --max-copy-bytes budgets",
+	}
+)
+
+// opBudget bounds the removals and the copied bytes of a command, so that
+// an operation broader than expected aborts instead of going on with
+// millions of objects. A nil budget has no limits.
+type opBudget struct {
+	maxDelete    int64
+	maxCopyBytes int64
+
+	deletes   int64
+	copyBytes int64
+}
+
+// newOpBudget returns the budget set by the flags of a command, nil when
+// it has none or with --no-limit.
+func newOpBudget(cliCtx *cli.Context) (*opBudget, *probe.Error) {
+	if cliCtx.Bool("no-limit") {
+		return nil, nil
+	}
+	b := &opBudget{maxDelete: cliCtx.Int64("max-delete")}
+	if b.maxDelete < 0 {
+		return nil, probe.NewError(fmt.Errorf("--max-delete cannot be negative"))
+	}
+	if size := cliCtx.String("max-copy-bytes"); size != "" {
+		maxCopyBytes, e := humanize.ParseBytes(size)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(size)
+		}
+		b.maxCopyBytes = int64(maxCopyBytes)
+	}
+	if b.maxDelete == 0 && b.maxCopyBytes == 0 {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// mustGetOpBudget is newOpBudget exiting on invalid budgets.
+func mustGetOpBudget(cliCtx *cli.Context) *opBudget {
+	b, err := newOpBudget(cliCtx)
+	fatalIf(err, "Invalid --max-delete or --max-copy-bytes.")
+	return b
+}
+
+// remove counts a removal, it fails once the removals exceed the budget.
+func (b *opBudget) remove() *probe.Error {
+	if b == nil || b.maxDelete == 0 {
+		return nil
+	}
+	if atomic.AddInt64(&b.deletes, 1) > b.maxDelete {
+		return probe.NewError(fmt.Errorf("the removals exceed the budget of %d object(s) of --max-delete, pass --no-limit to lift it", b.maxDelete))
+	}
+	return nil
+}
+
+// copy counts the bytes of a copy, it fails once the copies exceed the
+// budget.
+func (b *opBudget) copy(size int64) *probe.Error {
+	if b == nil || b.maxCopyBytes == 0 {
+		return nil
+	}
+	if atomic.AddInt64(&b.copyBytes, size) > b.maxCopyBytes {
+		return probe.NewError(fmt.Errorf("the copies exceed the budget of %s of --max-copy-bytes, pass --no-limit to lift it",
+			humanize.IBytes(uint64(b.maxCopyBytes))))
+	}
+	return nil
+}
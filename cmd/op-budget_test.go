@@ -0,0 +1,96 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+func TestOpBudget(t *testing.T) {
+	newContext := func(args ...string) *cli.Context {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		for _, f := range []cli.Flag{maxDeleteFlag, maxCopyBytesFlag, noLimitFlag} {
+			f.Apply(set)
+		}
+		if e := set.Parse(args); e != nil {
+			t.Fatal(e)
+		}
+		return cli.NewContext(nil, set, nil)
+	}
+
+	testCases := []struct {
+		args         []string
+		maxDelete    int64
+		maxCopyBytes int64
+		noBudget     bool
+		invalid      bool
+	}{
+		{args: nil, noBudget: true},
+		{args: []string{"--max-delete", "2"}, maxDelete: 2},
+		{args: []string{"--max-copy-bytes", "1KiB"}, maxCopyBytes: 1024},
+		{args: []string{"--max-delete", "2", "--no-limit"}, noBudget: true},
+		{args: []string{"--max-delete", "-1"}, invalid: true},
+		{args: []string{"--max-copy-bytes", "lots"}, invalid: true},
+	}
+	for i, testCase := range testCases {
+		b, err := newOpBudget(newContext(testCase.args...))
+		if testCase.invalid {
+			if err == nil {
+				t.Errorf("Test %d: expected an error", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if testCase.noBudget {
+			if b != nil {
+				t.Errorf("Test %d: expected no budget, got %+v", i+1, b)
+			}
+			continue
+		}
+		if b.maxDelete != testCase.maxDelete || b.maxCopyBytes != testCase.maxCopyBytes {
+			t.Errorf("Test %d: unexpected budget %+v", i+1, b)
+		}
+	}
+
+	b := &opBudget{maxDelete: 2, maxCopyBytes: 100}
+	for i := 0; i < 2; i++ {
+		if err := b.remove(); err != nil {
+			t.Fatalf("removal %d: %v", i+1, err)
+		}
+	}
+	if err := b.remove(); err == nil {
+		t.Fatal("expected the third removal to exceed the budget")
+	}
+	if err := b.copy(100); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.copy(1); err == nil {
+		t.Fatal("expected the copies to exceed the budget")
+	}
+
+	// No budget, no limits.
+	var nb *opBudget
+	if nb.remove() != nil || nb.copy(1<<40) != nil {
+		t.Fatal("expected no limits without a budget")
+	}
+}
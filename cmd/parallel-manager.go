@@ -240,8 +240,9 @@ func availableMemory() (available uint64) {
 	return
 }
 
-// newParallelManager starts new workers waiting for executing tasks
-func newParallelManager(resultCh chan URLs) *ParallelManager {
+// newParallelManager starts new workers waiting for executing tasks,
+// workers is the number of workers, zero starts one per CPU.
+func newParallelManager(resultCh chan URLs, workers int) *ParallelManager {
 	p := &ParallelManager{
 		wg:            &sync.WaitGroup{},
 		workersNum:    0,
@@ -251,8 +252,11 @@ func newParallelManager(resultCh chan URLs) *ParallelManager {
 		maxMem:        availableMemory(),
 	}
 
-	// Start with runtime.NumCPU().
-	for i := 0; i < runtime.NumCPU(); i++ {
+	// Start with runtime.NumCPU() unless set with --parallel.
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	for i := 0; i < workers; i++ {
 		p.addWorker()
 	}
 
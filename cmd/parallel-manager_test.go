@@ -0,0 +1,48 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewParallelManagerWorkers(t *testing.T) {
+	defaultWorkers := runtime.NumCPU()
+	if defaultWorkers > maxParallelWorkers {
+		defaultWorkers = maxParallelWorkers
+	}
+	testCases := []struct {
+		workers  int
+		expected int
+	}{
+		{0, defaultWorkers},
+		{-1, defaultWorkers},
+		{1, 1},
+		{3, 3},
+		{maxParallelWorkers + 1, maxParallelWorkers},
+	}
+	for i, testCase := range testCases {
+		p := newParallelManager(make(chan URLs), testCase.workers)
+		if workers := int(atomic.LoadUint32(&p.workersNum)); workers != testCase.expected {
+			t.Errorf("Test %d: expected %d workers, got %d", i+1, testCase.expected, workers)
+		}
+		p.stopAndWait()
+	}
+}
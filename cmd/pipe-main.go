@@ -66,6 +66,7 @@ var pipeFlags = []cli.Flag{
 		Usage:  "increase the pipe buffer size to a custom value",
 		Hidden: true,
 	},
+	uploadChecksumFlag,
 }
 
 // Display contents of a file.
@@ -75,7 +76,7 @@ var pipeCmd = cli.Command{
 	Action:       mainPipe,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(pipeFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(pipeFlags, codecFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -110,6 +111,12 @@ EXAMPLES:
 
   7. Set tags to the uploaded objects
       {{.Prompt}} tar cvf - . | {{.HelpName}} --tags "category=prod&type=backup" play/mybucket/backup.tar
+
+  8. Stream a database dump compressed with zstd and encrypted with gpg for a recipient.
+      {{.Prompt}} pg_dump accounts | {{.HelpName}} --compress zstd --encrypt-with backup@example.com play/sql-backups/accounts.sql
+
+  9. Stream a tarball with the CRC32C checksums of its parts sent and verified by the server.
+      {{.Prompt}} tar cvf - . | {{.HelpName}} --checksum crc32c play/mybucket/backup.tar
 `,
 }
 
@@ -154,10 +161,26 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 		multipartThreads: uint(multipartThreads),
 		concurrentStream: ctx.IsSet("concurrent"),
 	}
+	checksum := checkUploadChecksum(ctx)
+	if _, _, hostCfg, _ := expandAlias(alias); hostCfg == nil {
+		// Files have no additional checksums.
+		checksum = ""
+	}
+	opts.checksum = checksum
 
 	pg := newProgressBar(0)
 
-	_, err := putTargetStreamWithURL(targetURL, io.TeeReader(os.Stdin, pg), -1, opts)
+	var reader io.Reader = io.TeeReader(os.Stdin, pg)
+	if compress, encryptWith := ctx.String("compress"), ctx.String("encrypt-with"); compress != "" || encryptWith != "" {
+		encoded, codecMeta := newCodecEncoder(reader, compress, encryptWith)
+		defer encoded.Close()
+		for k, v := range codecMeta {
+			opts.metadata[k] = v
+		}
+		reader = encoded
+	}
+
+	_, err := putTargetStreamWithURL(targetURL, reader, -1, opts)
 	// TODO: See if this check is necessary.
 	switch e := err.ToGoError().(type) {
 	case *os.PathError:
@@ -166,6 +189,12 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 			return nil
 		}
 	}
+	if err == nil && checksum != "" {
+		var msg uploadChecksumMessage
+		if msg, err = verifyUploadChecksum(globalContext, alias, targetURL, checksum, sseKey); err == nil {
+			printMsg(msg)
+		}
+	}
 	return err.Trace(targetURL)
 }
 
@@ -174,6 +203,10 @@ func checkPipeSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) > 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code.
 	}
+	if e := checkCodecOptions(ctx.String("compress"), ctx.String("encrypt-with")); e != nil {
+		fatalIf(probe.NewError(e), "Invalid client side compression or encryption.")
+	}
+	checkUploadChecksum(ctx)
 }
 
 // mainPipe is the main entry point for pipe command.
@@ -0,0 +1,165 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/pkg/console"
+)
+
+var pluginListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list the plugins found in PATH",
+	Action:       mainPluginList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+  Plugins are executables named 'mc-NAME' in PATH, 'mc NAME ARGS...' runs
+  the first one found when NAME is not a command of mc. They receive the
+  context of mc in the environment:
+    MC_PLUGIN_NAME   name of the plugin
+    MC_EXECUTABLE    path of mc, to run its commands
+    MC_CONFIG_DIR    configuration directory of mc
+    MC_ALIAS         current alias, when one is set
+    MC_JSON, MC_QUIET, MC_NO_COLOR, MC_INSECURE, MC_DEBUG
+                     global flags, 'true' or 'false'
+  mc reads MC_CONFIG_DIR, MC_ALIAS and the global flags from the same
+  variables, the commands a plugin runs with MC_EXECUTABLE inherit them.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List the plugins.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Run the plugin 'mc-backup', found in PATH.
+     {{.Prompt}} mc backup --help
+`,
+}
+
+// pluginMessage container for a plugin found in PATH
+type pluginMessage struct {
+	Status string `json:"status"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	// Warning is set for the plugins which can't run.
+	Warning string `json:"warning,omitempty"`
+}
+
+func (p pluginMessage) String() string {
+	msg := console.Colorize("PluginName", p.Name) + "  " + console.Colorize("PluginPath", p.Path)
+	if p.Warning != "" {
+		msg += "  " + console.Colorize("PluginWarning", "("+p.Warning+")")
+	}
+	return msg
+}
+
+func (p pluginMessage) JSON() string {
+	p.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// isExecutableFile checks the file may be run by exec.LookPath.
+func isExecutableFile(path string, st os.FileInfo) bool {
+	if st.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.HasSuffix(strings.ToLower(path), ".exe")
+	}
+	return st.Mode()&0o111 != 0
+}
+
+// findPlugins lists the plugins of every PATH directory in order, the
+// later plugins with the same name are shadowed.
+func findPlugins(commands []cli.Command) []pluginMessage {
+	builtins := map[string]bool{}
+	for _, cmd := range commands {
+		for _, name := range cmd.Names() {
+			builtins[name] = true
+		}
+	}
+
+	var plugins []pluginMessage
+	found := map[string]string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, e := os.ReadDir(dir)
+		if e != nil {
+			continue
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+		for _, entry := range entries {
+			name := pluginName(entry.Name())
+			if name == "" {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			st, e := os.Stat(path)
+			if e != nil || !isExecutableFile(path, st) {
+				continue
+			}
+			msg := pluginMessage{Name: name, Path: path}
+			switch {
+			case builtins[name]:
+				msg.Warning = "overshadowed by the command `" + name + "`"
+			case !isValidPluginName(name):
+				msg.Warning = "invalid plugin name"
+			case found[name] != "":
+				msg.Warning = "overshadowed by " + found[name]
+			default:
+				found[name] = path
+			}
+			plugins = append(plugins, msg)
+		}
+	}
+	return plugins
+}
+
+// mainPluginList is the handle for "mc plugin list" command.
+func mainPluginList(ctx *cli.Context) error {
+	if ctx.Args().Present() {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	console.SetColor("PluginName", color.New(color.FgCyan, color.Bold))
+	console.SetColor("PluginPath", color.New(color.FgWhite))
+	console.SetColor("PluginWarning", color.New(color.FgYellow))
+
+	for _, msg := range findPlugins(ctx.App.Commands) {
+		printMsg(msg)
+	}
+	return nil
+}
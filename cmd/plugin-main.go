@@ -0,0 +1,126 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+// pluginPrefix is the prefix of the executables extending mc, `mc foo`
+// runs `mc-foo` when foo is not a command of mc.
+const pluginPrefix = "mc-"
+
+var pluginSubcommands = []cli.Command{
+	pluginListCmd,
+}
+
+var pluginCmd = cli.Command{
+	Name:            "plugin",
+	Usage:           "manage the plugins extending mc",
+	Action:          mainPlugin,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	Subcommands:     pluginSubcommands,
+}
+
+func mainPlugin(ctx *cli.Context) error {
+	commandNotFound(ctx, pluginSubcommands)
+	return nil
+}
+
+// pluginName returns the command implemented by a plugin executable,
+// or an empty string when the file is not a plugin.
+func pluginName(file string) string {
+	name := filepath.Base(file)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(strings.ToLower(name), ".exe")
+	}
+	if !strings.HasPrefix(name, pluginPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(name, pluginPrefix)
+}
+
+// isValidPluginName checks a command can be run as a plugin, it must
+// not look like an alias, a path or a flag.
+func isValidPluginName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return false
+	}
+	return !strings.ContainsAny(name, `/\.:`)
+}
+
+// lookPlugin returns the path of the plugin implementing a command.
+func lookPlugin(name string) (string, bool) {
+	if !isValidPluginName(name) {
+		return "", false
+	}
+	path, e := exec.LookPath(pluginPrefix + name)
+	if e != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// pluginEnv is the environment of the plugins, it describes the
+// configuration and the global flags of mc.
+func pluginEnv(name string) []string {
+	boolEnv := func(b bool) string {
+		return strconv.FormatBool(b)
+	}
+	env := append(os.Environ(),
+		"MC_PLUGIN_NAME="+name,
+		"MC_CONFIG_DIR="+mustGetMcConfigDir(),
+		"MC_JSON="+boolEnv(globalJSON),
+		"MC_QUIET="+boolEnv(globalQuiet),
+		"MC_NO_COLOR="+boolEnv(globalNoColor),
+		"MC_INSECURE="+boolEnv(globalInsecure),
+		"MC_DEBUG="+boolEnv(globalDebug),
+	)
+	if executable, e := os.Executable(); e == nil {
+		env = append(env, "MC_EXECUTABLE="+executable)
+	}
+	if alias, _ := getCurrentAlias(); alias != "" {
+		env = append(env, mcEnvAlias+"="+alias)
+	}
+	return env
+}
+
+// runPlugin runs a plugin with the remaining arguments, mc exits with
+// the exit status of the plugin.
+func runPlugin(name, path string, args []string) error {
+	cmd := exec.Command(path, args...)
+	cmd.Env = pluginEnv(name)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	e := cmd.Run()
+	if e == nil {
+		return nil
+	}
+	if _, ok := e.(*exec.ExitError); !ok {
+		fatalIf(probe.NewError(e).Trace(path), "Unable to run plugin `"+name+"`.")
+	}
+	return exitStatus(getExitStatus(e))
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+func TestPluginName(t *testing.T) {
+	testCases := []struct {
+		file string
+		name string
+	}{
+		{"/usr/local/bin/mc-backup", "backup"},
+		{"mc-foo-bar", "foo-bar"},
+		{"/usr/bin/mc", ""},
+		{"/usr/bin/mcli", ""},
+	}
+	if runtime.GOOS == "windows" {
+		testCases = append(testCases, struct {
+			file string
+			name string
+		}{`C:\bin\MC-Backup.exe`, "backup"})
+	}
+	for i, testCase := range testCases {
+		if name := pluginName(testCase.file); name != testCase.name {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.name, name)
+		}
+	}
+}
+
+func TestIsValidPluginName(t *testing.T) {
+	testCases := []struct {
+		name  string
+		valid bool
+	}{
+		{"backup", true},
+		{"foo-bar", true},
+		{"", false},
+		{"--help", false},
+		{"myminio/bucket", false},
+		{"../mc-evil", false},
+		{"C:evil", false},
+	}
+	for i, testCase := range testCases {
+		if valid := isValidPluginName(testCase.name); valid != testCase.valid {
+			t.Errorf("Test %d: expected %t for %q", i+1, testCase.valid, testCase.name)
+		}
+	}
+}
+
+// The variables exported to the plugins set the global flags of the
+// commands they run with MC_EXECUTABLE.
+func TestPluginEnvGlobalFlags(t *testing.T) {
+	t.Setenv("MC_CONFIG_DIR", "/tmp/mc-plugin")
+	t.Setenv("MC_QUIET", "true")
+	t.Setenv("MC_JSON", "false")
+	t.Setenv("MC_INSECURE", "true")
+
+	var configDir string
+	var quiet, json, insecure bool
+	app := cli.NewApp()
+	app.Commands = []cli.Command{{
+		Name:  "ls",
+		Flags: globalFlags,
+		Action: func(ctx *cli.Context) error {
+			configDir = ctx.String("config-dir")
+			quiet, json, insecure = ctx.Bool("quiet"), ctx.Bool("json"), ctx.Bool("insecure")
+			return nil
+		},
+	}}
+	if e := app.Run([]string{"mc", "ls"}); e != nil {
+		t.Fatal(e)
+	}
+	if configDir != "/tmp/mc-plugin" || !quiet || json || !insecure {
+		t.Errorf("unexpected flags config-dir=%q quiet=%t json=%t insecure=%t", configDir, quiet, json, insecure)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/dustin/go-humanize"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/mattn/go-runewidth"
+	"github.com/minio/pkg/console"
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats supported by --output in addition to text and json.
+const (
+	outputYAML  = "yaml"
+	outputTable = "table"
+	outputCSV   = "csv"
+)
+
+// outputTableSampleRows is the number of rows sizing the columns of a
+// table, the following rows are printed as they come.
+const outputTableSampleRows = 100
+
+var (
+	outputMu     sync.Mutex
+	outputCount  int
+	outputHeader []string
+	outputCSVBuf bytes.Buffer
+
+	outputTableRows    [][]string // header and rows waiting for the column widths
+	outputTableWidths  []int
+	outputTablePrinted bool
+)
+
+// decodeOrdered decodes a JSON value, objects are decoded as
+// yaml.MapSlice to preserve the order of the fields.
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, e := dec.Token()
+	if e != nil {
+		return nil, e
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			var m yaml.MapSlice
+			for dec.More() {
+				key, e := dec.Token()
+				if e != nil {
+					return nil, e
+				}
+				v, e := decodeOrdered(dec)
+				if e != nil {
+					return nil, e
+				}
+				m = append(m, yaml.MapItem{Key: key, Value: v})
+			}
+			_, e = dec.Token()
+			return m, e
+		case '[':
+			l := []interface{}{}
+			for dec.More() {
+				v, e := decodeOrdered(dec)
+				if e != nil {
+					return nil, e
+				}
+				l = append(l, v)
+			}
+			_, e = dec.Token()
+			return l, e
+		}
+	case json.Number:
+		if i, e := t.Int64(); e == nil {
+			return i, nil
+		}
+		f, e := t.Float64()
+		return f, e
+	}
+	return tok, nil
+}
+
+// plainValue converts a decoded value back to plain maps and slices.
+func plainValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case yaml.MapSlice:
+		m := make(map[string]interface{}, len(t))
+		for _, item := range t {
+			m[fmt.Sprint(item.Key)] = plainValue(item.Value)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i := range t {
+			l[i] = plainValue(t[i])
+		}
+		return l
+	}
+	return v
+}
+
+// cellValue renders a field as a single CSV or table cell, nested
+// values are rendered as compact JSON.
+func cellValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case yaml.MapSlice, []interface{}:
+		buf, _ := json.Marshal(plainValue(t))
+		return string(buf)
+	}
+	return fmt.Sprint(v)
+}
+
+// formatOutput renders the JSON form of a message in the --output format.
+func formatOutput(jsonStr string) string {
+	dec := json.NewDecoder(strings.NewReader(jsonStr))
+	dec.UseNumber()
+	v, e := decodeOrdered(dec)
+	if e != nil {
+		return jsonStr
+	}
+	fields, ok := v.(yaml.MapSlice)
+	if !ok {
+		fields = yaml.MapSlice{{Key: "value", Value: v}}
+	}
+
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	defer func() { outputCount++ }()
+
+	if globalOutput == outputYAML {
+		buf, e := yaml.Marshal(fields)
+		if e != nil {
+			return jsonStr
+		}
+		if outputCount > 0 {
+			return "---\n" + string(buf)
+		}
+		return string(buf)
+	}
+
+	header := make([]string, 0, len(fields))
+	row := make([]string, 0, len(fields))
+	for _, field := range fields {
+		header = append(header, fmt.Sprint(field.Key))
+		row = append(row, cellValue(field.Value))
+	}
+	newHeader := strings.Join(header, ",") != strings.Join(outputHeader, ",")
+	outputHeader = header
+
+	if globalOutput == outputTable {
+		return tableOutput(header, row, newHeader)
+	}
+
+	outputCSVBuf.Reset()
+	w := csv.NewWriter(&outputCSVBuf)
+	if newHeader {
+		w.Write(header)
+	}
+	w.Write(row)
+	w.Flush()
+	return outputCSVBuf.String()
+}
+
+// tableOutput returns the lines of the table to print for the row.
+func tableOutput(header, row []string, newHeader bool) string {
+	var s string
+	if newHeader {
+		// A new set of columns starts with a new table.
+		s = renderTableRows()
+		if outputTablePrinted {
+			s += "\n"
+		}
+		outputTableRows, outputTableWidths = [][]string{header}, nil
+	}
+	if outputTableWidths != nil {
+		return s + renderTableRow(row)
+	}
+	outputTableRows = append(outputTableRows, row)
+	if len(outputTableRows)-1 >= outputTableSampleRows {
+		s += renderTableRows()
+	}
+	return s
+}
+
+// renderTableRows sizes the columns with the rows waiting for it and
+// renders them.
+func renderTableRows() string {
+	if len(outputTableRows) == 0 {
+		return ""
+	}
+	outputTableWidths = make([]int, len(outputTableRows[0]))
+	for _, row := range outputTableRows {
+		for i, cell := range row {
+			if w := runewidth.StringWidth(cell); i < len(outputTableWidths) && w > outputTableWidths[i] {
+				outputTableWidths[i] = w
+			}
+		}
+	}
+	var s strings.Builder
+	for _, row := range outputTableRows {
+		s.WriteString(renderTableRow(row))
+	}
+	outputTableRows = nil
+	outputTablePrinted = true
+	return s.String()
+}
+
+// renderTableRow aligns the cells of a row on the column widths, the
+// cells wider than their column shift the following ones.
+func renderTableRow(row []string) string {
+	var s strings.Builder
+	for i, cell := range row {
+		if i > 0 {
+			s.WriteString("  ")
+		}
+		s.WriteString(cell)
+		if i < len(row)-1 && i < len(outputTableWidths) {
+			s.WriteString(strings.Repeat(" ", max(outputTableWidths[i]-runewidth.StringWidth(cell), 0)))
+		}
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+// flushTableOutput prints the rows of a table shorter than the sample.
+func flushTableOutput(_ int) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	if s := renderTableRows(); s != "" {
+		console.Print(s)
+	}
+}
+
+// formatFuncs are the functions available to --format templates.
+var formatFuncs = template.FuncMap{
+	"json": func(v interface{}) string {
+		buf, _ := json.Marshal(v)
+		return string(buf)
+	},
+	"size":  func(v int64) string { return humanize.IBytes(uint64(v)) },
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// parseFormat parses the --format template, \t and \n are accepted
+// as tab and newline.
+func parseFormat(format string) (*template.Template, *probe.Error) {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+	tmpl, e := template.New("format").Funcs(formatFuncs).Parse(format)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return tmpl, nil
+}
+
+// formatMsg renders a message with the --format template.
+func formatMsg(msg message) (string, *probe.Error) {
+	var buf bytes.Buffer
+	if e := globalFormat.Execute(&buf, msg); e != nil {
+		return "", probe.NewError(e)
+	}
+	return buf.String(), nil
+}
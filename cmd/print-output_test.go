@@ -0,0 +1,123 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+func TestFormatOutput(t *testing.T) {
+	defer func() {
+		globalOutput, outputCount, outputHeader = "", 0, nil
+		outputTableRows, outputTableWidths, outputTablePrinted = nil, nil, false
+	}()
+
+	globalOutput, outputCount, outputHeader = outputYAML, 0, nil
+	if got, want := formatOutput(`{"status":"success","size":3,"key":"y"}`), "status: success\nsize: 3\nkey: \"y\"\n"; got != want {
+		t.Errorf("yaml: got %q, want %q", got, want)
+	}
+	if got, want := formatOutput(`{"status":"success"}`), "---\nstatus: success\n"; got != want {
+		t.Errorf("yaml: got %q, want %q", got, want)
+	}
+
+	globalOutput, outputCount, outputHeader = outputCSV, 0, nil
+	testCases := []struct {
+		input, want string
+	}{
+		{`{"key":"a","size":1}`, "key,size\na,1\n"},
+		{`{"key":"b,c","size":2.5}`, "\"b,c\",2.5\n"},
+		{`{"key":"d","tags":{"k":"v"}}`, "key,tags\nd,\"{\"\"k\"\":\"\"v\"\"}\"\n"},
+	}
+	for i, testCase := range testCases {
+		if got := formatOutput(testCase.input); got != testCase.want {
+			t.Errorf("csv %d: got %q, want %q", i+1, got, testCase.want)
+		}
+	}
+}
+
+func TestTableOutput(t *testing.T) {
+	defer func() {
+		globalOutput, outputCount, outputHeader = "", 0, nil
+		outputTableRows, outputTableWidths, outputTablePrinted = nil, nil, false
+	}()
+	globalOutput, outputCount, outputHeader = outputTable, 0, nil
+
+	// The rows are held until the sample sizing the columns is complete.
+	var out strings.Builder
+	for i := 0; i < outputTableSampleRows-1; i++ {
+		out.WriteString(formatOutput(fmt.Sprintf(`{"key":"k%d","size":%d}`, i, i)))
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected the sample rows to be held, got %q", out.String())
+	}
+	out.WriteString(formatOutput(`{"key":"last","size":1}`))
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != outputTableSampleRows+1 || lines[0] != "key   size" || lines[1] != "k0    0" {
+		t.Fatalf("unexpected table %q", lines[:2])
+	}
+
+	// The following rows are printed right away, aligned on the sample.
+	if got, want := formatOutput(`{"key":"a","size":2}`), "a     2\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := formatOutput(`{"key":"longer-key","size":3}`), "longer-key  3\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// New columns start a new table, sized at exit when shorter than the sample.
+	if got, want := formatOutput(`{"bucket":"b"}`), "\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := renderTableRows(), "bucket\nb\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFormatMsg(t *testing.T) {
+	defer func() { globalFormat = nil }()
+
+	msg := aliasMessage{Alias: "myminio", URL: "https://minio"}
+	testCases := []struct {
+		format  string
+		want    string
+		invalid bool
+	}{
+		{format: `{{.Alias}}\t{{.URL}}`, want: "myminio\thttps://minio"},
+		{format: `{{upper .Alias}}\n`, want: "MYMINIO\n"},
+		{format: `{{size 1024}}`, want: "1.0 KiB"},
+		{format: `{{json .Alias}}`, want: `"myminio"`},
+		{format: `{{.Alias`, invalid: true},
+		{format: `{{.NoSuchField}}`, invalid: true},
+	}
+	for i, testCase := range testCases {
+		var err *probe.Error
+		if globalFormat, err = parseFormat(testCase.format); err == nil {
+			var got string
+			if got, err = formatMsg(msg); err == nil && got != testCase.want {
+				t.Errorf("Test %d: got %q, want %q", i+1, got, testCase.want)
+			}
+		}
+		if (err != nil) != testCase.invalid {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"strings"
 
+	"github.com/kirolous/mc/pkg/probe"
 	"github.com/minio/pkg/console"
 )
 
@@ -34,11 +35,20 @@ type message interface {
 // printMsg prints message string or JSON structure depending on the type of output console.
 func printMsg(msg message) {
 	var msgStr string
-	if !globalJSON {
+	if globalFormat != nil {
+		var err *probe.Error
+		msgStr, err = formatMsg(msg)
+		fatalIf(err, "Unable to apply the format template.")
+	} else if !globalJSON {
 		msgStr = msg.String()
 	} else {
-		msgStr = msg.JSON()
-		if globalJSONLine && strings.ContainsRune(msgStr, '\n') {
+		msgStr = messageJSON(msg)
+		if globalOutput != "" {
+			msgStr = formatOutput(msgStr)
+			if msgStr == "" {
+				return
+			}
+		} else if globalJSONLine && strings.ContainsRune(msgStr, '\n') {
 			// Reformat.
 			var dst bytes.Buffer
 			if err := json.Compact(&dst, []byte(msgStr)); err == nil {
@@ -0,0 +1,171 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var putCmd = cli.Command{
+	Name:         "put",
+	Usage:        "upload a file as an object and verify its checksum",
+	Action:       mainPut,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(ioFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] FILE ALIAS/BUCKET/OBJECT
+
+  Upload a single file, '-' for stdin. Every request is sent with the MD5 of
+  its content which is verified by the server, the ETag of the object is
+  compared with the MD5 of the file when the object is not encrypted. The
+  name of the file is appended to targets ending with '/'.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Upload a file.
+     {{.Prompt}} {{.HelpName}} backup.tar myminio/mybucket/backups/backup.tar
+
+  2. Upload a file under a prefix with the same name.
+     {{.Prompt}} {{.HelpName}} backup.tar myminio/mybucket/backups/
+
+  3. Upload the output of a command.
+     {{.Prompt}} pg_dump mydb | {{.HelpName}} - myminio/mybucket/dumps/mydb.sql
+`,
+}
+
+// putMessage container for an uploaded object
+type putMessage struct {
+	Status    string `json:"status"`
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+	Size      int64  `json:"size"`
+	ETag      string `json:"etag"`
+	VersionID string `json:"versionId,omitempty"`
+	MD5       string `json:"md5"`
+}
+
+func (p putMessage) String() string {
+	msg := console.Colorize("Put", fmt.Sprintf("`%s` -> `%s`", p.Source, p.Target)) +
+		fmt.Sprintf(" (%s", humanize.IBytes(uint64(p.Size)))
+	if p.VersionID != "" {
+		msg += ", version " + p.VersionID
+	}
+	return msg + ")"
+}
+
+func (p putMessage) JSON() string {
+	p.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func checkPutSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+	source, target := ctx.Args().Get(0), ctx.Args().Get(1)
+	if alias, _, _ := mustExpandAlias(target); alias == "" {
+		fatalIf(errInvalidArgument().Trace(target), "The target must be an object on an alias, use 'mc cp' to copy to local files.")
+	}
+	if source == "-" && strings.HasSuffix(target, "/") {
+		fatalIf(errInvalidArgument().Trace(target), "The target must be an object name when uploading from stdin.")
+	}
+}
+
+// mainPut is the handle for "mc put" command.
+func mainPut(cliCtx *cli.Context) error {
+	ctx, cancelPut := context.WithCancel(globalContext)
+	defer cancelPut()
+
+	checkPutSyntax(cliCtx)
+	console.SetColor("Put", color.New(color.FgGreen))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	source, targetURL := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+	if strings.HasSuffix(targetURL, "/") {
+		targetURL += filepath.Base(source)
+	}
+
+	var reader io.Reader = os.Stdin
+	size := int64(-1)
+	if source != "-" {
+		f, e := os.Open(source)
+		fatalIf(probe.NewError(e), "Unable to open `"+source+"`.")
+		defer f.Close()
+		fi, e := f.Stat()
+		fatalIf(probe.NewError(e), "Unable to open `"+source+"`.")
+		if fi.IsDir() {
+			fatalIf(errInvalidArgument().Trace(source), "The source must be a file, use 'mc cp --recursive' to upload folders.")
+		}
+		reader, size = f, fi.Size()
+	}
+
+	alias, _, _ := mustExpandAlias(targetURL)
+	clnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize the client.")
+	sse := getSSE(filepath.Join(alias, clnt.GetURL().Path), encKeyDB[alias])
+
+	h := md5.New()
+	n, err := clnt.Put(ctx, io.TeeReader(reader, h), size, nil, PutOptions{sse: sse, md5: true})
+	fatalIf(err.Trace(targetURL), "Unable to upload `"+source+"`.")
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	st, err := clnt.Stat(ctx, StatOptions{sse: sse})
+	fatalIf(err.Trace(targetURL), "Unable to verify `"+targetURL+"`.")
+	if st.Size != n {
+		fatalIf(errDummy().Trace(targetURL), fmt.Sprintf("The size of `%s` is %d, expected %d.", targetURL, st.Size, n))
+	}
+	// Multipart and encrypted objects don't have the MD5 as ETag, their
+	// parts were verified by the server.
+	if etag := strings.ToLower(strings.Trim(st.ETag, "\"")); md5Rx.MatchString(etag) && sse == nil &&
+		!strings.HasPrefix(st.Metadata["X-Amz-Server-Side-Encryption"], "aws:kms") && etag != sum {
+		fatalIf(errDummy().Trace(targetURL), fmt.Sprintf("The ETag of `%s` is %s, expected %s.", targetURL, etag, sum))
+	}
+
+	printMsg(putMessage{
+		Source:    source,
+		Target:    targetURL,
+		Size:      n,
+		ETag:      strings.Trim(st.ETag, "\""),
+		VersionID: st.VersionID,
+		MD5:       sum,
+	})
+	return nil
+}
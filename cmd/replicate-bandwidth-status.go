@@ -0,0 +1,177 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var replicateBandwidthStatusCmd = cli.Command{
+	Name:         "status",
+	Usage:        "show the configured and current bandwidth of replication targets",
+	Action:       mainReplicateBandwidthStatus,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} TARGET
+
+FLAGS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}
+EXAMPLES:
+  1. Show the bandwidth limit and the current bandwidth of the replication targets of bucket "mybucket".
+       {{.Prompt}} {{.HelpName}} myminio/mybucket
+
+  2. Show the bandwidth of the replication targets of all the buckets of alias "myminio".
+       {{.Prompt}} {{.HelpName}} myminio
+
+  3. Limit the replication of rule "kxYD.491" to 100 megabits per second during business hours, then lift the limit.
+       {{.Prompt}} mc replicate update myminio/mybucket --id "kxYD.491" --bandwidth 100M
+       {{.Prompt}} mc replicate update myminio/mybucket --id "kxYD.491" --bandwidth 0
+`,
+}
+
+// replicateBandwidthTarget is the bandwidth of a replication target.
+type replicateBandwidthTarget struct {
+	Bucket       string  `json:"bucket"`
+	Arn          string  `json:"arn"`
+	Endpoint     string  `json:"endpoint"`
+	TargetBucket string  `json:"targetBucket"`
+	Limit        int64   `json:"limitInBytesPerSecond"`
+	Current      float64 `json:"currentInBytesPerSecond"`
+}
+
+type replicateBandwidthMessage struct {
+	Op      string                     `json:"op"`
+	Status  string                     `json:"status"`
+	URL     string                     `json:"url"`
+	Targets []replicateBandwidthTarget `json:"targets"`
+}
+
+func (m replicateBandwidthMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (m replicateBandwidthMessage) String() string {
+	if len(m.Targets) == 0 {
+		return console.Colorize("THeader", "No replication targets found for "+m.URL+".")
+	}
+	fields := []Field{{"THeader", 20}, {"THeader", 40}, {"Values", 14}, {"Values", 14}, {"Values", 6}}
+	lines := []string{console.Colorize("TgtHeaders", newPrettyTable(" | ", fields...).buildRow(
+		"Bucket", "Target", "Limit", "Current", "Usage"))}
+	for _, t := range m.Targets {
+		limit, usage := "unlimited", "-"
+		if t.Limit > 0 {
+			limit = formatBitsPerSecond(float64(t.Limit))
+			usage = fmt.Sprintf("%.0f%%", t.Current*100/float64(t.Limit))
+		}
+		row := newPrettyTable(" | ", fields...).buildRow(t.Bucket, t.Endpoint+"/"+t.TargetBucket,
+			limit, formatBitsPerSecond(t.Current), usage)
+		if t.Limit > 0 && t.Current >= float64(t.Limit)*0.9 {
+			row = console.Colorize("Throttled", row)
+		}
+		lines = append(lines, row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatBitsPerSecond formats a bandwidth in bytes per second in bits per
+// second, the unit of the --bandwidth flags.
+func formatBitsPerSecond(bytesPerSecond float64) string {
+	return strings.TrimSuffix(humanize.Bytes(uint64(bytesPerSecond*8)), "B") + "bit/s"
+}
+
+// checkReplicateBandwidthStatusSyntax - validate all the passed arguments
+func checkReplicateBandwidthStatusSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+func mainReplicateBandwidthStatus(cliCtx *cli.Context) error {
+	ctx, cancelReplicateBandwidthStatus := context.WithCancel(globalContext)
+	defer cancelReplicateBandwidthStatus()
+
+	console.SetColor("THeader", color.New(color.FgWhite))
+	console.SetColor("Values", color.New(color.FgGreen))
+	console.SetColor("TgtHeaders", color.New(color.Bold, color.FgCyan))
+	console.SetColor("Throttled", color.New(color.FgYellow))
+
+	checkReplicateBandwidthStatusSyntax(cliCtx)
+
+	// Get the alias parameter from cli
+	args := cliCtx.Args()
+	aliasedURL := args.Get(0)
+	alias, urlPath := url2Alias(aliasedURL)
+	bucket := strings.SplitN(strings.TrimPrefix(urlPath, "/"), "/", 2)[0]
+
+	// Create a new MinIO Admin Client
+	admClient, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+	targets, e := admClient.ListRemoteTargets(ctx, bucket, string(madmin.ReplicationService))
+	fatalIf(probe.NewError(e).Trace(args...), "Unable to fetch remote targets.")
+
+	byBucket := make(map[string][]madmin.BucketTarget)
+	for _, t := range targets {
+		byBucket[t.SourceBucket] = append(byBucket[t.SourceBucket], t)
+	}
+	buckets := make([]string, 0, len(byBucket))
+	for b := range byBucket {
+		buckets = append(buckets, b)
+	}
+	sort.Strings(buckets)
+
+	msg := replicateBandwidthMessage{Op: cliCtx.Command.Name, URL: aliasedURL}
+	for _, b := range buckets {
+		// The current bandwidth comes from the replication metrics of the
+		// bucket, the limit from the remote target.
+		client, err := newClient(alias + "/" + b)
+		fatalIf(err.Trace(b), "Unable to initialize connection.")
+		metrics, err := client.GetReplicationMetrics(ctx)
+		fatalIf(err.Trace(b), "Unable to get replication metrics of bucket `"+b+"`.")
+		for _, t := range byBucket[b] {
+			msg.Targets = append(msg.Targets, replicateBandwidthTarget{
+				Bucket:       b,
+				Arn:          t.Arn,
+				Endpoint:     t.Endpoint,
+				TargetBucket: t.TargetBucket,
+				Limit:        t.BandwidthLimit,
+				Current:      metrics.Stats[t.Arn].CurrentBandwidthInBytesPerSecond,
+			})
+		}
+	}
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestFormatBitsPerSecond(t *testing.T) {
+	testCases := []struct {
+		bytesPerSecond float64
+		formatted      string
+	}{
+		{0, "0 bit/s"},
+		{125, "1.0 kbit/s"},
+		{12500000, "100 Mbit/s"},
+	}
+	for i, testCase := range testCases {
+		if formatted := formatBitsPerSecond(testCase.bytesPerSecond); formatted != testCase.formatted {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.formatted, formatted)
+		}
+	}
+}
+
+func TestReplicateBandwidthMessage(t *testing.T) {
+	msg := replicateBandwidthMessage{URL: "myminio/mybucket", Targets: []replicateBandwidthTarget{
+		{Bucket: "mybucket", Endpoint: "siteb:9000", TargetBucket: "b1", Limit: 12500000, Current: 6250000},
+		{Bucket: "mybucket", Endpoint: "sitec:9000", TargetBucket: "b2"},
+	}}
+	out := msg.String()
+	for _, want := range []string{"100 Mbit/s", "50 Mbit/s", "50%", "unlimited"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if out := (replicateBandwidthMessage{URL: "myminio"}).String(); !strings.Contains(out, "No replication targets found") {
+		t.Errorf("unexpected message %q", out)
+	}
+}
+
+// The bandwidth of a target is updated without new credentials.
+func TestModifyRemoteTargetBandwidth(t *testing.T) {
+	arn := "arn:minio:replication::c5be6b16-769d-432a-9ef1-4567081f3566:target"
+	credentials := &madmin.Credentials{AccessKey: "access", SecretKey: "secret"}
+	targets := []madmin.BucketTarget{{
+		SourceBucket: "mybucket", TargetBucket: "target", Endpoint: "siteb:9000", Arn: arn,
+		Credentials: credentials, BandwidthLimit: 100,
+	}}
+
+	testCases := []struct {
+		args  []string
+		limit int64
+		ops   []madmin.TargetUpdateType
+	}{
+		{[]string{"--bandwidth", "100M"}, 12500000, []madmin.TargetUpdateType{madmin.BandwidthLimitUpdateType}},
+		{[]string{"--bandwidth", "0"}, 0, []madmin.TargetUpdateType{madmin.BandwidthLimitUpdateType}},
+		{[]string{"--sync", "enable"}, 100, []madmin.TargetUpdateType{madmin.SyncUpdateType}},
+	}
+	for i, testCase := range testCases {
+		var target *madmin.BucketTarget
+		var ops []madmin.TargetUpdateType
+		app := cli.NewApp()
+		app.Commands = []cli.Command{{
+			Name:  "update",
+			Flags: replicateUpdateFlags,
+			Action: func(ctx *cli.Context) error {
+				target, ops = modifyRemoteTarget(ctx, targets, arn)
+				return nil
+			},
+		}}
+		args := append(append([]string{"mc", "update"}, testCase.args...), "myminio/mybucket")
+		if e := app.Run(args); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if target.BandwidthLimit != testCase.limit || !reflect.DeepEqual(ops, testCase.ops) {
+			t.Errorf("Test %d: expected limit %d and %v, got %d and %v", i+1, testCase.limit, testCase.ops, target.BandwidthLimit, ops)
+		}
+		// Clone() leaves out the secret key, the server keeps it without CredentialsUpdateType.
+		if target.Credentials == nil || target.Credentials.AccessKey != credentials.AccessKey || target.Endpoint != "siteb:9000" {
+			t.Errorf("Test %d: the remote target changed: %+v", i+1, target)
+		}
+	}
+}
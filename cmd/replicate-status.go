@@ -257,6 +257,10 @@ func (s replicateStatusMessage) String() string {
 }
 
 func mainReplicateStatus(cliCtx *cli.Context) error {
+	if runOnAliasGroup(cliCtx) {
+		return nil
+	}
+
 	ctx, cancelReplicateStatus := context.WithCancel(globalContext)
 	defer cancelReplicateStatus()
 
@@ -0,0 +1,140 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/signer"
+)
+
+// requestPayerRequester is the only payer accepted by AWS S3, the requests
+// to requester pays buckets are charged to the account sending them.
+const requestPayerRequester = "requester"
+
+// globalRequestPayer is the payer given on the command line, empty when not given.
+var globalRequestPayer string
+
+// setRequestPayerFromContext sets the payer given on the command line.
+func setRequestPayerFromContext(ctx *cli.Context) {
+	switch {
+	case ctx.IsSet("request-payer"):
+		globalRequestPayer = ctx.String("request-payer")
+	case ctx.GlobalIsSet("request-payer"):
+		globalRequestPayer = ctx.GlobalString("request-payer")
+	}
+	globalRequestPayer = strings.ToLower(globalRequestPayer)
+	if globalRequestPayer != "" && globalRequestPayer != requestPayerRequester {
+		fatalIf(errInvalidArgument().Trace(globalRequestPayer), "The request payer can only be `"+requestPayerRequester+"`.")
+	}
+}
+
+// setAliasRequestPayer stores the payer given on the command line in the alias config.
+func setAliasRequestPayer(aliasCfg *aliasConfigV10) {
+	if globalRequestPayer != "" {
+		aliasCfg.RequestPayer = globalRequestPayer
+	}
+}
+
+// applyRequestPayer sets the payer of the alias in config, the command
+// line takes precedence over the alias.
+func applyRequestPayer(config *Config, aliasCfg *aliasConfigV10) {
+	if aliasCfg != nil {
+		config.RequestPayer = aliasCfg.RequestPayer
+	}
+	if globalRequestPayer != "" {
+		config.RequestPayer = globalRequestPayer
+	}
+}
+
+// withRequestPayer returns transport sending x-amz-request-payer with the
+// requests when payer is set. The header must be signed, the requests
+// signed by the SDK with SigV4 are signed again with creds. The requests
+// presigned by the SDK are sent unchanged. Streaming signatures chain the
+// signatures of the chunks to the one of the headers and are rejected,
+// uploads with a payer are not streamed.
+func withRequestPayer(transport http.RoundTripper, payer string, creds *credentials.Credentials) http.RoundTripper {
+	if payer == "" {
+		return transport
+	}
+	return requestPayerTransport{transport: transport, payer: payer, creds: creds}
+}
+
+// unsignedPayloadTrailer is the payload of the uploads with an unsigned
+// checksum trailer, only their headers are signed.
+const unsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+
+var errRequestPayerStreaming = errors.New("streaming signatures cannot carry the request payer, upload over HTTPS or without --request-payer")
+
+type requestPayerTransport struct {
+	transport http.RoundTripper
+	payer     string
+	creds     *credentials.Credentials
+}
+
+func (t requestPayerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("X-Amz-Signature") != "" {
+		return t.transport.RoundTrip(req)
+	}
+	authorization := req.Header.Get("Authorization")
+	region := sigV4Region(authorization)
+	if authorization != "" && region == "" {
+		// Signatures not computed again, e.g. SigV2.
+		return t.transport.RoundTrip(req)
+	}
+	if payload := req.Header.Get("X-Amz-Content-Sha256"); strings.HasPrefix(payload, "STREAMING-") && payload != unsignedPayloadTrailer {
+		return nil, errRequestPayerStreaming
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Amz-Request-Payer", t.payer)
+	if authorization == "" {
+		// Unsigned, or signed by the next transport.
+		return t.transport.RoundTrip(req)
+	}
+	value, e := t.creds.Get()
+	if e != nil {
+		return nil, e
+	}
+	req.Header.Del("Authorization")
+	req = signer.SignV4(*req, value.AccessKeyID, value.SecretAccessKey, value.SessionToken, region)
+	return t.transport.RoundTrip(req)
+}
+
+// sigV4Region returns the region of the credential scope of a SigV4
+// Authorization header, empty for other signatures.
+func sigV4Region(authorization string) string {
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 ") {
+		return ""
+	}
+	_, credential, ok := strings.Cut(authorization, "Credential=")
+	if !ok {
+		return ""
+	}
+	credential, _, _ = strings.Cut(credential, ",")
+	// access-key/date/region/service/aws4_request
+	scope := strings.Split(credential, "/")
+	if len(scope) < 5 {
+		return ""
+	}
+	return scope[len(scope)-3]
+}
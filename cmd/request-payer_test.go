@@ -0,0 +1,165 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/signer"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRequestPayerTransport(t *testing.T) {
+	creds := credentials.NewStaticV4("access", "secret", "")
+	var sent *http.Request
+	transport := withRequestPayer(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}), requestPayerRequester, creds)
+
+	req, e := http.NewRequest(http.MethodGet, "https://s3.eu-west-1.amazonaws.com/bucket/object", nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+	req = signer.SignV4(*req, "access", "secret", "", "eu-west-1")
+	if _, e = transport.RoundTrip(req); e != nil {
+		t.Fatal(e)
+	}
+	if got := sent.Header.Get("X-Amz-Request-Payer"); got != requestPayerRequester {
+		t.Fatalf("expected x-amz-request-payer %q, got %q", requestPayerRequester, got)
+	}
+	authorization := sent.Header.Get("Authorization")
+	if !strings.Contains(authorization, "x-amz-request-payer") || sigV4Region(authorization) != "eu-west-1" {
+		t.Fatalf("expected a signature of x-amz-request-payer in eu-west-1, got %q", authorization)
+	}
+
+	// Presigned requests are sent unchanged.
+	req, e = http.NewRequest(http.MethodGet, "https://s3.amazonaws.com/bucket/object?X-Amz-Signature=abc", nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if _, e = transport.RoundTrip(req); e != nil {
+		t.Fatal(e)
+	}
+	if got := sent.Header.Get("X-Amz-Request-Payer"); got != "" {
+		t.Fatalf("expected no x-amz-request-payer, got %q", got)
+	}
+}
+
+func TestRequestPayerStreaming(t *testing.T) {
+	creds := credentials.NewStaticV4("access", "secret", "")
+	var sent *http.Request
+	transport := withRequestPayer(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}), requestPayerRequester, creds)
+
+	testCases := []struct {
+		payload string
+		success bool
+	}{
+		// Only the headers of uploads with an unsigned trailer are signed.
+		{unsignedPayloadTrailer, true},
+		{"STREAMING-AWS4-HMAC-SHA256-PAYLOAD", false},
+		{"STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER", false},
+	}
+	for i, testCase := range testCases {
+		sent = nil
+		req, e := http.NewRequest(http.MethodPut, "https://s3.eu-west-1.amazonaws.com/bucket/object", strings.NewReader("hello"))
+		if e != nil {
+			t.Fatal(e)
+		}
+		req.Header.Set("X-Amz-Content-Sha256", testCase.payload)
+		req = signer.SignV4(*req, "access", "secret", "", "eu-west-1")
+		_, e = transport.RoundTrip(req)
+		if (e == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, e)
+		}
+		if !testCase.success {
+			if sent != nil {
+				t.Errorf("Test %d: expected the request not to be sent", i+1)
+			}
+			continue
+		}
+		if !strings.Contains(sent.Header.Get("Authorization"), "x-amz-request-payer") {
+			t.Errorf("Test %d: expected a signature of x-amz-request-payer, got %q", i+1, sent.Header.Get("Authorization"))
+		}
+	}
+}
+
+func TestRequestPayerPut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		// Uploads over plain HTTP are streamed unless a payer is set.
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") || r.Header.Get("X-Amz-Request-Payer") != requestPayerRequester {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/object"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	conf.RequestPayer = requestPayerRequester
+	s3c, err := S3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s3c.Put(context.Background(), strings.NewReader("hello"), 5, nil, PutOptions{
+		metadata: map[string]string{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSigV4Region(t *testing.T) {
+	testCases := []struct {
+		authorization, want string
+	}{
+		{"", ""},
+		{"AWS access:signature", ""},
+		{"AWS4-HMAC-SHA256 Credential=access/20230101/us-west-2/s3/aws4_request, SignedHeaders=host, Signature=abc", "us-west-2"},
+		{"AWS4-ECDSA-P256-SHA256 Credential=access/20230101/s3/aws4_request, SignedHeaders=host, Signature=abc", ""},
+	}
+	for _, testCase := range testCases {
+		if got := sigV4Region(testCase.authorization); got != testCase.want {
+			t.Errorf("%q: expected %q, got %q", testCase.authorization, testCase.want, got)
+		}
+	}
+}
@@ -124,6 +124,14 @@ func getRetainUntilDate(validity uint64, unit minio.ValidityUnit) (string, *prob
 	return timeStr, nil
 }
 
+// retentionSessionKey identifies an object version in the session journal.
+func retentionSessionKey(urlPath, versionID string) string {
+	if versionID == "" {
+		return urlPath
+	}
+	return urlPath + "?versionId=" + versionID
+}
+
 func setRetentionSingle(ctx context.Context, op lockOpType, alias, url, versionID string, mode minio.RetentionMode, retainUntil time.Time, bypassGovernance bool) *probe.Error {
 	newClnt, err := newClientFromAlias(alias, url)
 	if err != nil {
@@ -143,6 +151,7 @@ func setRetentionSingle(ctx context.Context, op lockOpType, alias, url, versionI
 		msg.Status = "failure"
 	} else {
 		msg.Status = "success"
+		recordObjectDone(retentionSessionKey(msg.URLPath, versionID), 0)
 	}
 
 	printMsg(msg)
@@ -240,6 +249,12 @@ func applyRetention(ctx context.Context, op lockOpType, target, versionID string
 			break
 		}
 
+		// Set before the session was resumed.
+		if globalCommandSession.isDone(retentionSessionKey(urlJoinPath(alias, content.URL.String()), content.VersionID)) {
+			atLeastOneRetentionApplied = true
+			continue
+		}
+
 		err := setRetentionSingle(ctx, op, alias, content.URL.String(), content.VersionID, mode, until, bypassGovernance)
 		if err != nil {
 			errorIf(err.Trace(clnt.GetURL().String()), "Invalid URL")
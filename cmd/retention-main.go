@@ -25,6 +25,7 @@ var retentionSubcommands = []cli.Command{
 	retentionSetCmd,
 	retentionClearCmd,
 	retentionInfoCmd,
+	retentionReportCmd,
 }
 
 var retentionCmd = cli.Command{
@@ -0,0 +1,273 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
+)
+
+var retentionReportFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "all",
+		Usage: "show the retention of every object version, not only the lapsed ones",
+	},
+}
+
+var retentionReportCmd = cli.Command{
+	Name:         "report",
+	Usage:        "summarize the object lock retention of a bucket",
+	Action:       mainRetentionReport,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(retentionReportFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Report the default retention of the bucket, the number of object versions in GOVERNANCE
+  and COMPLIANCE mode, the soonest and latest retain until dates and the object versions
+  whose retention has lapsed. The retention of each version is looked up with a request.
+
+EXAMPLES:
+  1. Summarize the retention of all object versions of mybucket.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+
+  2. Summarize the retention of the object versions under a prefix.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket/prefix/
+
+  3. Export the retention of every object version of mybucket as CSV.
+     {{.Prompt}} {{.HelpName}} --all --output csv myminio/mybucket > retention.csv
+`,
+}
+
+// retentionReportEntryMessage is the retention of one object version.
+type retentionReportEntryMessage struct {
+	Status      string              `json:"status"`
+	Key         string              `json:"key"`
+	VersionID   string              `json:"versionId"`
+	Mode        minio.RetentionMode `json:"mode"`
+	RetainUntil string              `json:"retainUntil"`
+	Lapsed      bool                `json:"lapsed"`
+}
+
+// Colorized message for console printing.
+func (m retentionReportEntryMessage) String() string {
+	mode := console.Colorize("RetentionNotFound", fmt.Sprintf("%-12s", "NO RETENTION"))
+	if m.Mode != "" {
+		mode = console.Colorize("RetentionSuccess", fmt.Sprintf("%-12s", m.Mode))
+	}
+	state := console.Colorize("RetentionSuccess", fmt.Sprintf("%-7s", ""))
+	if m.Lapsed {
+		state = console.Colorize("RetentionExpired", fmt.Sprintf("%-7s", "LAPSED"))
+	}
+	until := fmt.Sprintf("%-20s", m.RetainUntil)
+	msg := "[ " + state + mode + " ] " + until + " "
+	if m.VersionID != "" {
+		msg += console.Colorize("RetentionVersionID", m.VersionID+"  ")
+	}
+	return msg + m.Key
+}
+
+// JSON'ified message for scripting.
+func (m retentionReportEntryMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// retentionReportMessage summarizes the retention of the object
+// versions of a bucket.
+type retentionReportMessage struct {
+	Status          string              `json:"status"`
+	URL             string              `json:"url"`
+	DefaultMode     minio.RetentionMode `json:"defaultMode"`
+	DefaultValidity string              `json:"defaultValidity"`
+	Versions        int64               `json:"versions"`
+	Governance      int64               `json:"governance"`
+	Compliance      int64               `json:"compliance"`
+	NoRetention     int64               `json:"noRetention"`
+	Lapsed          int64               `json:"lapsed"`
+	SoonestUntil    *time.Time          `json:"soonestRetainUntil,omitempty"`
+	LatestUntil     *time.Time          `json:"latestRetainUntil,omitempty"`
+}
+
+// add counts the retention of one object version. The soonest and latest
+// retain until dates are computed from the retention still in effect.
+func (m *retentionReportMessage) add(mode minio.RetentionMode, until, now time.Time) (lapsed bool) {
+	m.Versions++
+	switch mode {
+	case minio.Governance:
+		m.Governance++
+	case minio.Compliance:
+		m.Compliance++
+	default:
+		m.NoRetention++
+		return false
+	}
+	if !until.After(now) {
+		m.Lapsed++
+		return true
+	}
+	if m.SoonestUntil == nil || until.Before(*m.SoonestUntil) {
+		m.SoonestUntil = &until
+	}
+	if m.LatestUntil == nil || until.After(*m.LatestUntil) {
+		m.LatestUntil = &until
+	}
+	return false
+}
+
+// Colorized message for console printing.
+func (m retentionReportMessage) String() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "Bucket         : %s\n", console.Colorize("RetentionSuccess", m.URL))
+	if m.DefaultMode == "" {
+		fmt.Fprintf(&msg, "Default        : %s\n", console.Colorize("RetentionNotFound", "NO RETENTION"))
+	} else {
+		fmt.Fprintf(&msg, "Default        : %s for %s\n", console.Colorize("RetentionSuccess", m.DefaultMode), m.DefaultValidity)
+	}
+	fmt.Fprintf(&msg, "Versions       : %d\n", m.Versions)
+	fmt.Fprintf(&msg, "  GOVERNANCE   : %d\n", m.Governance)
+	fmt.Fprintf(&msg, "  COMPLIANCE   : %d\n", m.Compliance)
+	fmt.Fprintf(&msg, "  No retention : %d\n", m.NoRetention)
+	lapsed := fmt.Sprint(m.Lapsed)
+	if m.Lapsed > 0 {
+		lapsed = console.Colorize("RetentionExpired", lapsed)
+	}
+	fmt.Fprintf(&msg, "Lapsed         : %s\n", lapsed)
+	if m.SoonestUntil != nil {
+		fmt.Fprintf(&msg, "Soonest until  : %s\n", m.SoonestUntil.Format(time.RFC3339))
+		fmt.Fprintf(&msg, "Latest until   : %s\n", m.LatestUntil.Format(time.RFC3339))
+	}
+	return msg.String()
+}
+
+// JSON'ified message for scripting.
+func (m retentionReportMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// main for retention report command.
+func mainRetentionReport(cliCtx *cli.Context) error {
+	ctx, cancelRetentionReport := context.WithCancel(globalContext)
+	defer cancelRetentionReport()
+
+	console.SetColor("RetentionSuccess", color.New(color.FgGreen, color.Bold))
+	console.SetColor("RetentionNotFound", color.New(color.FgYellow))
+	console.SetColor("RetentionVersionID", color.New(color.FgGreen))
+	console.SetColor("RetentionExpired", color.New(color.FgRed, color.Bold))
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1)
+	}
+	target := cliCtx.Args().Get(0)
+	showAll := cliCtx.Bool("all")
+
+	fatalIfBucketLockNotEnabled(ctx, target)
+
+	clnt, err := newClient(target)
+	fatalIf(err.Trace(target), "Unable to parse the provided url.")
+	s3Clnt, ok := clnt.(*S3Client)
+	if !ok {
+		fatal(errDummy().Trace(), "Retention is supported only for S3 servers.")
+	}
+
+	alias, _, _ := mustExpandAlias(target)
+	bucket, _ := s3Clnt.url2BucketAndObject()
+	bucketURL := alias + "/" + bucket
+	bucketClnt, err := newClient(bucketURL)
+	fatalIf(err.Trace(bucketURL), "Unable to initialize bucket `%s`.", bucketURL)
+
+	report := retentionReportMessage{URL: bucketURL}
+	_, mode, validity, unit, err := bucketClnt.GetObjectLockConfig(ctx)
+	fatalIf(err.Trace(target), "Unable to get bucket lock configuration.")
+	if mode != "" {
+		report.DefaultMode = mode
+		report.DefaultValidity = fmt.Sprintf("%d%s", validity, unit)
+	}
+
+	var cErr error
+	now := time.Now()
+	for content := range clnt.List(ctx, ListOptions{
+		Recursive:         true,
+		WithOlderVersions: true,
+		ShowDir:           DirNone,
+	}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		// Delete markers have no retention.
+		if content.IsDeleteMarker {
+			continue
+		}
+
+		versionClnt, err := newClientFromAlias(alias, content.URL.String())
+		if err != nil {
+			errorIf(err.Trace(content.URL.String()), "Invalid URL")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		mode, until, err := versionClnt.GetObjectRetention(ctx, content.VersionID)
+		if err != nil {
+			if minio.ToErrorResponse(err.ToGoError()).Code != "NoSuchObjectLockConfiguration" {
+				errorIf(err.Trace(content.URL.String()), "Unable to get object retention.")
+				cErr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+			mode, until = "", time.Time{}
+		}
+
+		lapsed := report.add(mode, until, now)
+		if lapsed || showAll {
+			entry := retentionReportEntryMessage{
+				Key:       urlJoinPath(alias, content.URL.String()),
+				VersionID: content.VersionID,
+				Mode:      mode,
+				Lapsed:    lapsed,
+			}
+			if !until.IsZero() {
+				entry.RetainUntil = until.Format(time.RFC3339)
+			}
+			printMsg(entry)
+		}
+	}
+
+	printMsg(report)
+	return cErr
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestRetentionReportAdd(t *testing.T) {
+	now := time.Now()
+	soonest := now.Add(24 * time.Hour)
+	latest := now.Add(365 * 24 * time.Hour)
+
+	var report retentionReportMessage
+	if report.add(minio.Governance, latest, now) {
+		t.Fatal("retention in effect reported as lapsed")
+	}
+	if report.add(minio.Compliance, soonest, now) {
+		t.Fatal("retention in effect reported as lapsed")
+	}
+	if !report.add(minio.Governance, now.Add(-time.Hour), now) {
+		t.Fatal("lapsed retention not reported")
+	}
+	if report.add("", time.Time{}, now) {
+		t.Fatal("version without retention reported as lapsed")
+	}
+
+	if report.Versions != 4 || report.Governance != 2 || report.Compliance != 1 || report.NoRetention != 1 || report.Lapsed != 1 {
+		t.Fatalf("unexpected counts %+v", report)
+	}
+	if !report.SoonestUntil.Equal(soonest) || !report.LatestUntil.Equal(latest) {
+		t.Fatalf("unexpected retain until dates %v %v", report.SoonestUntil, report.LatestUntil)
+	}
+}
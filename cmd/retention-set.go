@@ -158,5 +158,8 @@ func mainRetentionSet(cliCtx *cli.Context) error {
 		rewind = time.Now().UTC()
 	}
 
-	return setRetention(ctx, target, versionID, rewind, withVersions, recursive, mode, validity, unit, bypass)
+	if recursive {
+		startCommandSession("retention", cliCtx)
+	}
+	return globalCommandSession.finish(setRetention(ctx, target, versionID, rewind, withVersions, recursive, mode, validity, unit, bypass))
 }
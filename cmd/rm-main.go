@@ -101,6 +101,8 @@ var (
 			Usage:  "attempt a prefix purge, requires confirmation please use with caution - only works with '--force'",
 			Hidden: true,
 		},
+		maxDeleteFlag,
+		noLimitFlag,
 	}
 )
 
@@ -123,6 +125,7 @@ FLAGS:
   {{end}}
 ENVIRONMENT VARIABLES:
   MC_ENCRYPT_KEY: list of comma delimited prefix=secret values
+  MC_MAX_DELETE:  default of --max-delete, lifted with --no-limit
 
 EXAMPLES:
   01. Remove a file.
@@ -167,6 +170,9 @@ EXAMPLES:
   14. Perform a fake removal of object(s) versions that are non-current and older than 10 days. If top-level version is a delete 
   marker, this will also be deleted when --non-current flag is specified.
       {{.Prompt}} {{.HelpName}} s3/docs/ --recursive --force --versions --non-current --older-than 10d --dry-run
+
+  15. Remove the logs of a day, aborting if more than 10000 objects would be removed.
+      {{.Prompt}} {{.HelpName}} --recursive --force --max-delete 10000 s3/logs/2023-10-15/
 `,
 }
 
@@ -334,6 +340,7 @@ func removeSingle(url, versionID string, opts removeOpts) error {
 			targetURL = targetURL + string(clnt.GetURL().Separator)
 		}
 
+		fatalIf(opts.budget.remove().Trace(url), "Aborting the removal.")
 		contentCh := make(chan *ClientContent, 1)
 		contentURL := *newClientURL(targetURL)
 		contentCh <- &ClientContent{URL: contentURL, VersionID: versionID}
@@ -379,6 +386,7 @@ type removeOpts struct {
 	olderThan         string
 	newerThan         string
 	encKeyDB          map[string][]prefixSSEPair
+	budget            *opBudget
 }
 
 func printDryRunMsg(content *ClientContent, printModTime bool) {
@@ -476,6 +484,7 @@ func listAndRemove(url string, opts removeOpts) error {
 						continue
 					}
 
+					fatalIf(opts.budget.remove().Trace(url), "Aborting the removal.")
 					sent := false
 					for !sent {
 						select {
@@ -503,6 +512,7 @@ func listAndRemove(url string, opts removeOpts) error {
 								msg.VersionID = result.DeleteMarkerVersionID
 							}
 							printMsg(msg)
+							recordObjectDone(msg.Key, 0)
 						}
 					}
 				}
@@ -534,6 +544,7 @@ func listAndRemove(url string, opts removeOpts) error {
 		}
 
 		if !opts.isFake {
+			fatalIf(opts.budget.remove().Trace(url), "Aborting the removal.")
 			sent := false
 			for !sent {
 				select {
@@ -565,6 +576,7 @@ func listAndRemove(url string, opts removeOpts) error {
 						msg.VersionID = result.DeleteMarkerVersionID
 					}
 					printMsg(msg)
+					recordObjectDone(msg.Key, 0)
 				}
 			}
 		} else {
@@ -597,6 +609,7 @@ func listAndRemove(url string, opts removeOpts) error {
 				continue
 			}
 
+			fatalIf(opts.budget.remove().Trace(url), "Aborting the removal.")
 			sent := false
 			for !sent {
 				select {
@@ -624,6 +637,7 @@ func listAndRemove(url string, opts removeOpts) error {
 						msg.VersionID = result.DeleteMarkerVersionID
 					}
 					printMsg(msg)
+					recordObjectDone(msg.Key, 0)
 				}
 			}
 		}
@@ -653,6 +667,7 @@ func listAndRemove(url string, opts removeOpts) error {
 			msg.VersionID = result.DeleteMarkerVersionID
 		}
 		printMsg(msg)
+		recordObjectDone(msg.Key, 0)
 	}
 
 	if !atLeastOneObjectFound {
@@ -694,6 +709,7 @@ func mainRm(cliCtx *cli.Context) error {
 	withVersions := cliCtx.Bool("versions")
 	versionID := cliCtx.String("version-id")
 	rewind := parseRewindFlag(cliCtx.String("rewind"))
+	budget := mustGetOpBudget(cliCtx)
 
 	if withVersions && rewind.IsZero() {
 		rewind = time.Now().UTC()
@@ -702,6 +718,10 @@ func mainRm(cliCtx *cli.Context) error {
 	// Set color.
 	console.SetColor("Removed", color.New(color.FgGreen, color.Bold))
 
+	if (isRecursive || withVersions) && !isFake && !isStdin {
+		startCommandSession("rm", cliCtx)
+	}
+
 	var rerr error
 	var e error
 	// Support multiple targets.
@@ -719,6 +739,7 @@ func mainRm(cliCtx *cli.Context) error {
 				olderThan:         olderThan,
 				newerThan:         newerThan,
 				encKeyDB:          encKeyDB,
+				budget:            budget,
 			})
 		} else {
 			e = removeSingle(url, versionID, removeOpts{
@@ -730,6 +751,7 @@ func mainRm(cliCtx *cli.Context) error {
 				olderThan:    olderThan,
 				newerThan:    newerThan,
 				encKeyDB:     encKeyDB,
+				budget:       budget,
 			})
 		}
 		if rerr == nil {
@@ -738,7 +760,11 @@ func mainRm(cliCtx *cli.Context) error {
 	}
 
 	if !isStdin {
-		return rerr
+		if rerr != nil && objectsDone() > 0 {
+			// Some objects were removed before the errors.
+			rerr = exitStatus(porcelainExitStatus(exitStatusPartial))
+		}
+		return globalCommandSession.finish(rerr)
 	}
 
 	scanner := bufio.NewScanner(os.Stdin)
@@ -757,6 +783,7 @@ func mainRm(cliCtx *cli.Context) error {
 				olderThan:         olderThan,
 				newerThan:         newerThan,
 				encKeyDB:          encKeyDB,
+				budget:            budget,
 			})
 		} else {
 			e = removeSingle(url, versionID, removeOpts{
@@ -768,6 +795,7 @@ func mainRm(cliCtx *cli.Context) error {
 				olderThan:    olderThan,
 				newerThan:    newerThan,
 				encKeyDB:     encKeyDB,
+				budget:       budget,
 			})
 		}
 		if rerr == nil {
@@ -0,0 +1,102 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var sessionAbortFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "all",
+		Usage: "abort all the sessions",
+	},
+}
+
+var sessionAbortCmd = cli.Command{
+	Name:         "abort",
+	Usage:        "abort an interrupted session",
+	Action:       mainSessionAbort,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(sessionAbortFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [SESSION-ID|--all]
+
+  Only the session record is removed, the work already done is kept.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Abort the session "mirror-3f2a9c0d11b7".
+     {{.Prompt}} {{.HelpName}} mirror-3f2a9c0d11b7
+
+  2. Abort all the sessions.
+     {{.Prompt}} {{.HelpName}} --all
+`,
+}
+
+// sessionAbortMessage container for an aborted session
+type sessionAbortMessage struct {
+	Status    string `json:"status"`
+	SessionID string `json:"sessionId"`
+}
+
+func (m sessionAbortMessage) String() string {
+	return console.Colorize("SessionMessage", "Session `"+shortSessionID(m.SessionID)+"` aborted.")
+}
+
+func (m sessionAbortMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+// mainSessionAbort is the handle for "mc session abort" command.
+func mainSessionAbort(ctx *cli.Context) error {
+	if (len(ctx.Args()) == 1) == ctx.Bool("all") || len(ctx.Args()) > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setSessionColors()
+
+	var sids []string
+	if ctx.Bool("all") {
+		if isSessionDirExists() {
+			sids = getSessionIDs()
+		}
+	} else {
+		sid := ctx.Args().Get(0)
+		s, ok := findSession(sid)
+		if !ok {
+			fatalIf(errDummy().Trace(sid), "Session `"+sid+"` not found.")
+		}
+		s.DataFP.Close()
+		sids = []string{s.SessionID}
+	}
+
+	for _, sid := range sids {
+		s, err := loadSessionV8(sid)
+		fatalIf(err.Trace(sid), "Unable to load session `"+sid+"`.")
+		fatalIf(s.Delete().Trace(sid), "Unable to abort session `"+sid+"`.")
+		printMsg(sessionAbortMessage{SessionID: sid})
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var sessionListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list resumable sessions",
+	Action:       mainSessionList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+  Sessions are recorded for cp --continue, mirror, rm --recursive and
+  retention set --recursive, and removed once the command succeeds.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all resumable sessions.
+     {{.Prompt}} {{.HelpName}}
+`,
+}
+
+// sessionListMessage container for a resumable session
+type sessionListMessage struct {
+	Status      string    `json:"status"`
+	SessionID   string    `json:"sessionId"`
+	Time        time.Time `json:"time"`
+	CommandType string    `json:"commandType"`
+	CommandArgs []string  `json:"commandArgs"`
+	Resumable   bool      `json:"resumable"`
+	Done        int64     `json:"done"`
+	Total       int64     `json:"total,omitempty"`
+	Last        string    `json:"last,omitempty"`
+}
+
+// shortSessionID returns the ID shown to the user, long enough to be unique.
+func shortSessionID(sid string) string {
+	if i := strings.Index(sid, "-"); i >= 0 && len(sid) > i+13 {
+		return sid[:i+13]
+	}
+	return sid
+}
+
+func (m sessionListMessage) String() string {
+	progress := fmt.Sprintf("%d done", m.Done)
+	if m.Total > 0 {
+		progress = fmt.Sprintf("%d/%d done", m.Done, m.Total)
+	}
+	if !m.Resumable {
+		progress += ", not resumable"
+	}
+	return console.Colorize("SessionID", shortSessionID(m.SessionID)) + " " +
+		console.Colorize("SessionTime", "["+m.Time.Local().Format(printDate)+"]") +
+		console.Colorize("Command", fmt.Sprintf(" %s %s", m.CommandType, strings.Join(m.CommandArgs, " "))) +
+		" (" + progress + ")"
+}
+
+func (m sessionListMessage) JSON() string {
+	m.Status = "success"
+	return toJSON(m)
+}
+
+// mainSessionList is the handle for "mc session list" command.
+func mainSessionList(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setSessionColors()
+
+	if !isSessionDirExists() {
+		return nil
+	}
+
+	var msgs []sessionListMessage
+	for _, sid := range getSessionIDs() {
+		s, err := loadSessionV8(sid)
+		if err != nil {
+			errorIf(err.Trace(sid), "Unable to load session `"+sid+"`.")
+			continue
+		}
+		s.DataFP.Close()
+
+		last := s.Header.LastCopied
+		if last == "" {
+			last = s.Header.LastRemoved
+		}
+		msgs = append(msgs, sessionListMessage{
+			SessionID:   sid,
+			Time:        s.Header.When,
+			CommandType: s.Header.CommandType,
+			CommandArgs: s.Header.CommandArgs,
+			Resumable:   len(s.Header.CommandLine) > 0,
+			Done:        s.Header.ObjectsDone,
+			Total:       s.Header.TotalObjects,
+			Last:        last,
+		})
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Time.Before(msgs[j].Time) })
+	for _, msg := range msgs {
+		printMsg(msg)
+	}
+	return nil
+}
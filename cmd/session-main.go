@@ -0,0 +1,187 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var sessionSubcommands = []cli.Command{
+	sessionListCmd,
+	sessionResumeCmd,
+	sessionAbortCmd,
+}
+
+var sessionCmd = cli.Command{
+	Name:            "session",
+	Usage:           "resume interrupted operations",
+	Action:          mainSession,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     sessionSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainSession is the handle for "mc session" command.
+func mainSession(ctx *cli.Context) error {
+	commandNotFound(ctx, sessionSubcommands)
+	return nil
+	// Sub-commands like "list", "resume" have their own main.
+}
+
+func setSessionColors() {
+	console.SetColor("SessionID", color.New(color.FgYellow, color.Bold))
+	console.SetColor("SessionTime", color.New(color.FgGreen))
+	console.SetColor("Command", color.New(color.FgWhite, color.Bold))
+	console.SetColor("SessionMessage", color.New(color.FgGreen))
+}
+
+// commandSession records a running command and its progress, the command
+// is resumed by running it again with 'mc session resume'. The session is
+// removed once the command succeeds, it is kept when the command fails or
+// is interrupted. The objects done are journaled in the session data file,
+// one per line, and skipped when the session is resumed.
+type commandSession struct {
+	*sessionV8
+	mu       sync.Mutex
+	lastSave time.Time
+	done     map[string]bool
+}
+
+// globalCommandSession is the session of the running command, if any.
+var globalCommandSession *commandSession
+
+// startCommandSession registers the running command as a session.
+func startCommandSession(cmdType string, cliCtx *cli.Context) {
+	fatalIf(createSessionDir().Trace(), "Unable to create session folder.")
+
+	sessionID := getHash(cmdType, os.Args[1:])
+	var s *sessionV8
+	if isSessionExists(sessionID) {
+		var err *probe.Error
+		s, err = loadSessionV8(sessionID)
+		fatalIf(err.Trace(sessionID), "Unable to load session.")
+	} else {
+		s = newSessionV8(sessionID)
+		s.Header.CommandType = cmdType
+		s.Header.CommandArgs = cliCtx.Args()
+		s.Header.CommandLine = os.Args[1:]
+		s.Header.RootPath, _ = os.Getwd()
+	}
+	fatalIf(s.Save().Trace(sessionID), "Unable to save session.")
+	globalCommandSession = newCommandSession(s)
+}
+
+// newCommandSession loads the journal of the objects already done.
+func newCommandSession(s *sessionV8) *commandSession {
+	cs := &commandSession{sessionV8: s, lastSave: time.Now(), done: map[string]bool{}}
+	scanner := bufio.NewScanner(s.NewDataReader())
+	for scanner.Scan() {
+		if object := scanner.Text(); object != "" {
+			cs.done[object] = true
+		}
+	}
+	// Journaled objects are counted once, whatever was saved last.
+	s.Header.ObjectsDone = int64(len(cs.done))
+	s.DataFP.Seek(0, io.SeekEnd)
+	return cs
+}
+
+// isDone returns true when the object was done before the session was resumed.
+func (s *commandSession) isDone(object string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done[object]
+}
+
+// checkpoint records an object processed by the command, see recordObjectDone().
+func (s *commandSession) checkpoint(object string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done[object] {
+		return
+	}
+	s.done[object] = true
+	fmt.Fprintln(s.DataFP, object)
+	s.Header.ObjectsDone++
+	if s.Header.CommandType == "rm" {
+		s.Header.LastRemoved = object
+	} else {
+		s.Header.LastCopied = object
+	}
+	// Saving after every object would slow down the command.
+	if time.Since(s.lastSave) > time.Second {
+		s.Save()
+		s.lastSave = time.Now()
+	}
+}
+
+// finish removes the session if the command succeeded, and saves its
+// progress otherwise. Returns the error of the command.
+func (s *commandSession) finish(e error) error {
+	if s == nil {
+		return e
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e == nil && globalContext.Err() == nil {
+		s.Delete()
+		return nil
+	}
+	s.Save()
+	if !globalJSON {
+		console.Infoln("Run 'mc session resume " + shortSessionID(s.SessionID) + "' to resume.")
+	}
+	return e
+}
+
+// findSession returns the session whose ID starts with prefix.
+func findSession(prefix string) (*sessionV8, bool) {
+	var match string
+	for _, sid := range getSessionIDs() {
+		if len(prefix) <= len(sid) && sid[:len(prefix)] == prefix {
+			if match != "" {
+				fatalIf(errDummy().Trace(prefix), "Session ID `"+prefix+"` is ambiguous.")
+			}
+			match = sid
+		}
+	}
+	if match == "" {
+		return nil, false
+	}
+	s, err := loadSessionV8(match)
+	fatalIf(err.Trace(match), "Unable to load session `"+match+"`.")
+	return s, true
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+)
+
+var sessionResumeCmd = cli.Command{
+	Name:         "resume",
+	Usage:        "resume an interrupted session",
+	Action:       mainSessionResume,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} SESSION-ID
+
+  The command of the session is run again from the folder it was
+  started in, skipping the work already done where the command supports it.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Resume the session "mirror-3f2a9c0d11b7".
+     {{.Prompt}} {{.HelpName}} mirror-3f2a9c0d11b7
+`,
+}
+
+// mainSessionResume is the handle for "mc session resume" command.
+func mainSessionResume(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	setSessionColors()
+
+	sid := ctx.Args().Get(0)
+	s, ok := findSession(sid)
+	if !ok {
+		fatalIf(errDummy().Trace(sid), "Session `"+sid+"` not found.")
+	}
+	s.DataFP.Close()
+
+	if len(s.Header.CommandLine) == 0 {
+		fatalIf(errDummy().Trace(sid), "Session `"+sid+"` was created by an older mc and cannot be resumed, run its command again.")
+	}
+
+	executable, e := os.Executable()
+	fatalIf(probe.NewError(e), "Unable to find the mc executable.")
+
+	cmd := exec.CommandContext(globalContext, executable, s.Header.CommandLine...)
+	cmd.Dir = s.Header.RootPath
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if e = cmd.Run(); e != nil {
+		if exitErr, ok := e.(*exec.ExitError); ok {
+			return exitStatus(exitErr.ExitCode())
+		}
+		fatalIf(probe.NewError(e), "Unable to resume session `"+sid+"`.")
+	}
+	return nil
+}
@@ -52,6 +52,8 @@ type sessionV8Header struct {
 	TotalBytes         int64             `json:"totalBytes"`
 	TotalObjects       int64             `json:"totalObjects"`
 	UserMetaData       map[string]string `json:"metaData"`
+	CommandLine        []string          `json:"commandLine,omitempty"`
+	ObjectsDone        int64             `json:"objectsDone,omitempty"`
 }
 
 // sessionMessage container for session messages
@@ -72,3 +72,35 @@ func (s *TestSuite) TestSession(c *C) {
 	_, e = os.Stat(session.DataFP.Name())
 	c.Assert(e, NotNil)
 }
+
+func (s *TestSuite) TestCommandSessionResume(c *C) {
+	err := createSessionDir()
+	c.Assert(err, IsNil)
+
+	session := newSessionV8(getHash("mirror", []string{"dir", "myminio/mybucket"}))
+	cs := newCommandSession(session)
+	cs.checkpoint("dir/a")
+	cs.checkpoint("dir/b")
+	cs.checkpoint("dir/a")
+	c.Assert(cs.Header.ObjectsDone, Equals, int64(2))
+	c.Assert(cs.Save(), IsNil)
+	c.Assert(session.Close(), IsNil)
+
+	// The resumed session skips the objects done and counts them once.
+	savedSession, err := loadSessionV8(session.SessionID)
+	c.Assert(err, IsNil)
+	savedSession.Header.ObjectsDone = 5
+	resumed := newCommandSession(savedSession)
+	c.Assert(resumed.Header.ObjectsDone, Equals, int64(2))
+	c.Assert(resumed.isDone("dir/a"), Equals, true)
+	c.Assert(resumed.isDone("dir/c"), Equals, false)
+	resumed.checkpoint("dir/b")
+	resumed.checkpoint("dir/c")
+	c.Assert(resumed.Header.ObjectsDone, Equals, int64(3))
+
+	var none *commandSession
+	c.Assert(none.isDone("dir/a"), Equals, false)
+
+	c.Assert(savedSession.Close(), IsNil)
+	c.Assert(savedSession.Delete(), IsNil)
+}
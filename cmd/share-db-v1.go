@@ -33,6 +33,31 @@ type shareEntryV1 struct {
 	Date        time.Time     `json:"date"`
 	Expiry      time.Duration `json:"expiry"`
 	ContentType string        `json:"contentType,omitempty"` // Only used by upload cmd.
+	Label       string        `json:"label,omitempty"`
+	// Alias and access key of the service account signing a revocable share.
+	Alias     string `json:"alias,omitempty"`
+	AccessKey string `json:"accessKey,omitempty"`
+	Revoked   bool   `json:"revoked,omitempty"`
+}
+
+// Expired and revoked shares are kept this long for 'mc share list --expired'.
+const shareExpiredRetention = 30 * 24 * time.Hour
+
+// Share states.
+const (
+	shareStateActive  = "active"
+	shareStateExpired = "expired"
+	shareStateRevoked = "revoked"
+)
+
+func (s shareEntryV1) state() string {
+	switch {
+	case s.Revoked:
+		return shareStateRevoked
+	case s.Expiry-time.Since(s.Date) <= 0:
+		return shareStateExpired
+	}
+	return shareStateActive
 }
 
 // JSON file to persist previously shared uploads.
@@ -55,16 +80,12 @@ func newShareDBV1() *shareDBV1 {
 }
 
 // Set upload info for each share.
-func (s *shareDBV1) Set(objectURL string, shareURL string, expiry time.Duration, contentType string) {
+func (s *shareDBV1) Set(shareURL string, share shareEntryV1) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s.Shares[shareURL] = shareEntryV1{
-		URL:         objectURL,
-		Date:        UTCNow(),
-		Expiry:      expiry,
-		ContentType: contentType,
-	}
+	share.Date = UTCNow()
+	s.Shares[shareURL] = share
 }
 
 // Delete upload info if it exists.
@@ -75,10 +96,10 @@ func (s *shareDBV1) Delete(objectURL string) {
 	delete(s.Shares, objectURL)
 }
 
-// Delete all expired uploads.
+// Delete all the shares expired for longer than the retention.
 func (s *shareDBV1) deleteAllExpired() {
 	for shareURL, share := range s.Shares {
-		if (share.Expiry - time.Since(share.Date)) <= 0 {
+		if (share.Expiry + shareExpiredRetention - time.Since(share.Date)) <= 0 {
 			// Expired entry. Safe to drop.
 			delete(s.Shares, shareURL)
 		}
@@ -110,7 +131,7 @@ func (s *shareDBV1) Load(filename string) *probe.Error {
 		s.Shares[k] = v
 	}
 
-	// Filter out expired entries and save changes back to disk.
+	// Filter out old expired entries and save changes back to disk.
 	s.deleteAllExpired()
 	s.save(filename)
 
@@ -0,0 +1,53 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShareDBExpired(t *testing.T) {
+	now := UTCNow()
+	db := newShareDBV1()
+	db.Shares["active"] = shareEntryV1{Date: now, Expiry: time.Hour, Label: "a"}
+	db.Shares["revoked"] = shareEntryV1{Date: now, Expiry: time.Hour, Revoked: true}
+	db.Shares["expired"] = shareEntryV1{Date: now.Add(-2 * time.Hour), Expiry: time.Hour}
+	db.Shares["old"] = shareEntryV1{Date: now.Add(-shareExpiredRetention - 2*time.Hour), Expiry: time.Hour}
+
+	db.deleteAllExpired()
+	if _, ok := db.Shares["old"]; ok || len(db.Shares) != 3 {
+		t.Fatalf("expected only the share expired before the retention to be dropped, got %v", db.Shares)
+	}
+	for shareURL, state := range map[string]string{
+		"active":  shareStateActive,
+		"revoked": shareStateRevoked,
+		"expired": shareStateExpired,
+	} {
+		if got := db.Shares[shareURL].state(); got != state {
+			t.Errorf("%s: expected state %s, got %s", shareURL, state, got)
+		}
+	}
+
+	if !matchShare("active", db.Shares["active"], "a", nil) || matchShare("expired", db.Shares["expired"], "a", nil) {
+		t.Error("unexpected match by label")
+	}
+	if !matchShare("expired", db.Shares["expired"], "", []string{"expired"}) {
+		t.Error("expected a match by share URL")
+	}
+}
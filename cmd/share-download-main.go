@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"context"
+	"mime"
 	"strings"
 	"time"
 
@@ -36,6 +37,20 @@ var shareDownloadFlags = []cli.Flag{
 		Usage: "share a particular object version",
 	},
 	shareFlagExpire,
+	cli.StringFlag{
+		Name:  "content-type",
+		Usage: "override the Content-Type of the download",
+	},
+	cli.StringFlag{
+		Name:  "content-disposition",
+		Usage: "override the Content-Disposition of the download",
+	},
+	cli.StringFlag{
+		Name:  "filename",
+		Usage: "download as an attachment saved with the file name",
+	},
+	shareFlagLabel,
+	shareFlagRevocable,
 }
 
 // Share documents via URL.
@@ -67,6 +82,12 @@ EXAMPLES:
 
   4. Share all objects under this bucket and all its folders and sub-folders with 5 days expiry.
      {{.Prompt}} {{.HelpName}} --recursive --expire=120h s3/backup/
+
+  5. Share this object with a partner, with URLs which can be revoked before they expire.
+     {{.Prompt}} {{.HelpName}} --revocable --label partner myminio/reports/2023.pdf
+
+  6. Share a previous version of an object, downloaded as 'report-v1.pdf'.
+     {{.Prompt}} {{.HelpName}} --version-id "3ddac055-89a7-40fa-8cd3-530a5581b6b8" --filename report-v1.pdf myminio/reports/report.pdf
 `,
 }
 
@@ -100,11 +121,19 @@ func checkShareDownloadSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB
 	if versionID != "" && isRecursive {
 		fatalIf(errDummy().Trace(), "--version-id cannot be specified with --recursive flag.")
 	}
+	if cliCtx.String("filename") != "" {
+		if isRecursive {
+			fatalIf(errDummy().Trace(), "--filename cannot be specified with --recursive flag.")
+		}
+		if cliCtx.String("content-disposition") != "" {
+			fatalIf(errDummy().Trace(), "--filename cannot be specified with --content-disposition flag.")
+		}
+	}
 
 	// Validate if object exists only if the `--recursive` flag was NOT specified
 	if !isRecursive {
 		for _, url := range cliCtx.Args() {
-			_, _, err := url2Stat(ctx, url, "", false, encKeyDB, time.Time{}, false)
+			_, _, err := url2Stat(ctx, url, versionID, false, encKeyDB, time.Time{}, false)
 			if err != nil {
 				fatalIf(err.Trace(url), "Unable to stat `"+url+"`.")
 			}
@@ -113,7 +142,7 @@ func checkShareDownloadSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB
 }
 
 // doShareURL share files from target.
-func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecursive bool, expiry time.Duration) *probe.Error {
+func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecursive bool, expiry time.Duration, responseHeaders map[string]string, label string, revocable bool) *probe.Error {
 	targetAlias, targetURLFull, _, err := expandAlias(targetURL)
 	if err != nil {
 		return err.Trace(targetURL)
@@ -161,6 +190,11 @@ func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecu
 		}()
 	}
 
+	signer, err := newShareSigner(ctx, targetAlias, targetURLFull, "s3:GetObject", expiry, revocable)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+
 	// Iterate over all objects to generate share URL
 	for content := range objectsCh {
 		if content.Err != nil {
@@ -172,13 +206,13 @@ func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecu
 		}
 		objectURL := content.URL.String()
 		objectVersionID := content.VersionID
-		newClnt, err := newClientFromAlias(targetAlias, objectURL)
+		newClnt, err := signer.client(objectURL)
 		if err != nil {
 			return err.Trace(objectURL)
 		}
 
 		// Generate share URL.
-		shareURL, err := newClnt.ShareDownload(ctx, objectVersionID, expiry)
+		shareURL, err := newClnt.ShareDownload(ctx, objectVersionID, expiry, responseHeaders)
 		if err != nil {
 			// add objectURL and expiry as part of the trace arguments.
 			return err.Trace(objectURL, "expiry="+expiry.String())
@@ -186,12 +220,14 @@ func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecu
 
 		// Make new entries to shareDB.
 		contentType := "" // Not useful for download shares.
-		shareDB.Set(objectURL, shareURL, expiry, contentType)
+		shareDB.Set(shareURL, signer.entry(objectURL, expiry, contentType, label))
 		printMsg(shareMesssage{
 			ObjectURL:   objectURL,
 			ShareURL:    shareURL,
 			TimeLeft:    expiry,
 			ContentType: contentType,
+			Label:       label,
+			Revocable:   revocable,
 		})
 	}
 
@@ -199,6 +235,21 @@ func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecu
 	return shareDB.Save(shareDownloadsFile)
 }
 
+// shareResponseHeaders returns the response headers overridden by a download share.
+func shareResponseHeaders(cliCtx *cli.Context) map[string]string {
+	headers := map[string]string{}
+	if contentType := cliCtx.String("content-type"); contentType != "" {
+		headers["Content-Type"] = contentType
+	}
+	if disposition := cliCtx.String("content-disposition"); disposition != "" {
+		headers["Content-Disposition"] = disposition
+	}
+	if filename := cliCtx.String("filename"); filename != "" {
+		headers["Content-Disposition"] = mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	}
+	return headers
+}
+
 // main for share download.
 func mainShareDownload(cliCtx *cli.Context) error {
 	ctx, cancelShareDownload := context.WithCancel(globalContext)
@@ -228,7 +279,7 @@ func mainShareDownload(cliCtx *cli.Context) error {
 	}
 
 	for _, targetURL := range cliCtx.Args() {
-		err := doShareDownloadURL(ctx, targetURL, versionID, isRecursive, expiry)
+		err := doShareDownloadURL(ctx, targetURL, versionID, isRecursive, expiry, shareResponseHeaders(cliCtx), cliCtx.String("label"), cliCtx.Bool("revocable"))
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case APINotImplemented:
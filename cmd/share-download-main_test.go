@@ -0,0 +1,121 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestShareResponseHeaders(t *testing.T) {
+	newContext := func(args ...string) *cli.Context {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		for _, f := range shareDownloadFlags {
+			f.Apply(set)
+		}
+		if e := set.Parse(args); e != nil {
+			t.Fatal(e)
+		}
+		return cli.NewContext(nil, set, nil)
+	}
+
+	testCases := []struct {
+		args     []string
+		expected map[string]string
+	}{
+		{nil, map[string]string{}},
+		{
+			[]string{"--content-type", "application/pdf"},
+			map[string]string{"Content-Type": "application/pdf"},
+		},
+		{
+			[]string{"--content-disposition", "inline"},
+			map[string]string{"Content-Disposition": "inline"},
+		},
+		{
+			[]string{"--filename", "report v1.pdf", "--content-type", "application/pdf"},
+			map[string]string{
+				"Content-Type":        "application/pdf",
+				"Content-Disposition": `attachment; filename="report v1.pdf"`,
+			},
+		},
+	}
+	for i, testCase := range testCases {
+		headers := shareResponseHeaders(newContext(testCase.args...))
+		if !reflect.DeepEqual(headers, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, headers)
+		}
+	}
+}
+
+func TestShareDownloadResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/reports/report.pdf"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := S3New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shareURL, err := s3c.ShareDownload(context.Background(), "v1", time.Hour, map[string]string{
+		"Content-Type":        "application/pdf",
+		"Content-Disposition": `attachment; filename="report-v1.pdf"`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, e := url.Parse(shareURL)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if u.Path != "/bucket/reports/report.pdf" {
+		t.Errorf("unexpected path %s", u.Path)
+	}
+	query := u.Query()
+	for k, v := range map[string]string{
+		"versionId":                    "v1",
+		"response-content-type":        "application/pdf",
+		"response-content-disposition": `attachment; filename="report-v1.pdf"`,
+	} {
+		if query.Get(k) != v {
+			t.Errorf("expected %s=%s, got %q", k, v, query.Get(k))
+		}
+	}
+	if query.Get("X-Amz-Signature") == "" {
+		t.Error("expected a signed URL")
+	}
+}
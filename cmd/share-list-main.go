@@ -25,7 +25,20 @@ import (
 	"github.com/kirolous/mc/pkg/probe"
 )
 
-var shareListFlags = []cli.Flag{}
+var shareListFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "active",
+		Usage: "list the shares which are still valid, the default",
+	},
+	cli.BoolFlag{
+		Name:  "expired",
+		Usage: "list the shares which expired or were revoked in the last 30 days",
+	},
+	cli.StringFlag{
+		Name:  "label",
+		Usage: "only list the shares with the label",
+	},
+}
 
 // Share documents via URL.
 var shareList = cli.Command{
@@ -40,18 +53,24 @@ var shareList = cli.Command{
   {{.HelpName}} COMMAND - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} COMMAND
+  {{.HelpName}} [FLAGS] COMMAND
 
 COMMAND:
   upload:   list previously shared access to uploads.
   download: list previously shared access to downloads.
 
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
 EXAMPLES:
   1. List previously shared downloads, that haven't expired yet.
       {{.Prompt}} {{.HelpName}} download
 
   2. List previously shared uploads, that haven't expired yet.
       {{.Prompt}} {{.HelpName}} upload
+
+  3. List the downloads shared with the label 'partner', expired or not.
+      {{.Prompt}} {{.HelpName}} --active --expired --label partner download
 `,
 }
 
@@ -64,7 +83,7 @@ func checkShareListSyntax(ctx *cli.Context) {
 }
 
 // doShareList list shared url's.
-func doShareList(cmd string) *probe.Error {
+func doShareList(cmd string, active, expired bool, label string) *probe.Error {
 	if cmd != "upload" && cmd != "download" {
 		return probe.NewError(fmt.Errorf("Unknown argument `%s` passed", cmd))
 	}
@@ -92,11 +111,21 @@ func doShareList(cmd string) *probe.Error {
 
 	// Print previously shared entries.
 	for shareURL, share := range shareDB.Shares {
+		state := share.state()
+		if (state == shareStateActive && !active) || (state != shareStateActive && !expired) {
+			continue
+		}
+		if label != "" && share.Label != label {
+			continue
+		}
 		printMsg(shareMesssage{
 			ObjectURL:   share.URL,
 			ShareURL:    shareURL,
 			TimeLeft:    share.Expiry - time.Since(share.Date),
 			ContentType: share.ContentType,
+			Label:       share.Label,
+			State:       state,
+			Revocable:   share.AccessKey != "",
 		})
 	}
 	return nil
@@ -114,6 +143,10 @@ func mainShareList(ctx *cli.Context) error {
 	initShareConfig()
 
 	// List shares.
-	fatalIf(doShareList(ctx.Args().First()).Trace(), "Unable to list previously shared URLs.")
+	active, expired := ctx.Bool("active"), ctx.Bool("expired")
+	if !active && !expired {
+		active = true
+	}
+	fatalIf(doShareList(ctx.Args().First(), active, expired, ctx.String("label")).Trace(), "Unable to list previously shared URLs.")
 	return nil
 }
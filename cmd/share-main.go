@@ -32,6 +32,7 @@ var shareSubcommands = []cli.Command{
 	shareDownload,
 	shareUpload,
 	shareList,
+	shareRevoke,
 }
 
 // Share documents via URL.
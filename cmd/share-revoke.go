@@ -0,0 +1,229 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var shareRevokeFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "label",
+		Usage: "revoke the shares with the label",
+	},
+}
+
+// Revoke shares.
+var shareRevoke = cli.Command{
+	Name:         "revoke",
+	Usage:        "revoke previously shared revocable URLs",
+	Action:       mainShareRevoke,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(shareRevokeFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [URL...]
+
+  URL is either a share URL or the URL of a shared object, as shown by 'mc share list'. Only
+  shares created with --revocable can be revoked: their MinIO service account is deleted,
+  which invalidates all the URLs signed with it.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Revoke the shares labeled 'partner'.
+     {{.Prompt}} {{.HelpName}} --label partner
+
+  2. Revoke the shares of an object.
+     {{.Prompt}} {{.HelpName}} https://play.min.io/mybucket/report.pdf
+`,
+}
+
+// shareSigner signs the shares of a target with the credentials of its
+// alias, or with a dedicated service account for revocable shares.
+type shareSigner struct {
+	alias     string
+	accessKey string
+	secretKey string
+}
+
+// newShareSigner returns the signer of the shares of a target. Revocable
+// shares get a MinIO service account, expiring with the shares, only
+// allowed to run the action under the target.
+func newShareSigner(ctx context.Context, alias, targetURLFull, action string, expiry time.Duration, revocable bool) (shareSigner, *probe.Error) {
+	signer := shareSigner{alias: alias}
+	if !revocable {
+		return signer, nil
+	}
+
+	resource := strings.TrimPrefix(newClientURL(targetURLFull).Path, "/")
+	if resource == "" {
+		return signer, probe.NewError(BucketNameEmpty{})
+	}
+	policy := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["%s"],"Resource":["arn:aws:s3:::%s*"]}]}`, action, resource)
+	expiration := time.Now().Add(expiry)
+
+	client, err := newAdminClient(alias)
+	if err != nil {
+		return signer, err.Trace(alias)
+	}
+	creds, e := client.AddServiceAccount(ctx, madmin.AddServiceAccountReq{
+		Policy:     []byte(policy),
+		Comment:    "mc share",
+		Expiration: &expiration,
+	})
+	if e != nil {
+		return signer, probe.NewError(fmt.Errorf("unable to create the service account of a revocable share, which needs a MinIO server: %w", e))
+	}
+	signer.accessKey, signer.secretKey = creds.AccessKey, creds.SecretKey
+	return signer, nil
+}
+
+// client returns the client signing the shares of an URL.
+func (s shareSigner) client(urlStr string) (Client, *probe.Error) {
+	if s.accessKey == "" {
+		return newClientFromAlias(s.alias, urlStr)
+	}
+	_, _, hostCfg, err := expandAlias(s.alias)
+	if err != nil {
+		return nil, err.Trace(s.alias)
+	}
+	cfg := *hostCfg
+	cfg.AccessKey, cfg.SecretKey = s.accessKey, s.secretKey
+	cfg.SessionToken, cfg.SecretStore, cfg.CredentialSource = "", "", nil
+	return S3New(NewS3Config(urlStr, &cfg))
+}
+
+// entry returns the entry saved for a share.
+func (s shareSigner) entry(objectURL string, expiry time.Duration, contentType, label string) shareEntryV1 {
+	share := shareEntryV1{
+		URL:         objectURL,
+		Expiry:      expiry,
+		ContentType: contentType,
+		Label:       label,
+	}
+	if s.accessKey != "" {
+		share.Alias, share.AccessKey = s.alias, s.accessKey
+	}
+	return share
+}
+
+// shareRevokeMessage is a service account deleted to revoke shares.
+type shareRevokeMessage struct {
+	Status    string `json:"status"`
+	Alias     string `json:"alias"`
+	AccessKey string `json:"accessKey"`
+	Shares    int    `json:"shares"`
+}
+
+func (s shareRevokeMessage) String() string {
+	return console.Colorize("Share", fmt.Sprintf("Revoked %d share(s) signed by `%s` on `%s`.", s.Shares, s.AccessKey, s.Alias))
+}
+
+func (s shareRevokeMessage) JSON() string {
+	s.Status = "success"
+	shareMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(shareMessageBytes)
+}
+
+// matchShare reports whether a share is selected by a label or by its URLs.
+func matchShare(shareURL string, share shareEntryV1, label string, urls []string) bool {
+	if label != "" && share.Label == label {
+		return true
+	}
+	for _, u := range urls {
+		if u == shareURL || u == share.URL {
+			return true
+		}
+	}
+	return false
+}
+
+// main for share revoke command.
+func mainShareRevoke(cliCtx *cli.Context) error {
+	label := cliCtx.String("label")
+	urls := cliCtx.Args()
+	if label == "" && len(urls) == 0 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code.
+	}
+
+	initShareConfig()
+	shareSetColor()
+
+	type serviceAccount struct{ alias, accessKey string }
+	var revoke []serviceAccount
+	counts := map[serviceAccount]int{}
+	dbs := map[string]*shareDBV1{}
+	for _, filename := range []string{getShareDownloadsFile(), getShareUploadsFile()} {
+		shareDB := newShareDBV1()
+		fatalIf(shareDB.Load(filename).Trace(filename), "Unable to load previously shared URLs.")
+		dbs[filename] = shareDB
+		for shareURL, share := range shareDB.Shares {
+			if share.Revoked || !matchShare(shareURL, share, label, urls) {
+				continue
+			}
+			if share.AccessKey == "" {
+				fatalIf(errDummy().Trace(share.URL), fmt.Sprintf("Share of `%s` is not revocable.", share.URL))
+			}
+			sa := serviceAccount{share.Alias, share.AccessKey}
+			if counts[sa] == 0 {
+				revoke = append(revoke, sa)
+			}
+			counts[sa]++
+		}
+	}
+	if len(revoke) == 0 {
+		fatalIf(errDummy().Trace(urls...), "No active share matches.")
+	}
+
+	ctx, cancelShareRevoke := context.WithCancel(globalContext)
+	defer cancelShareRevoke()
+	for _, sa := range revoke {
+		client, err := newAdminClient(sa.alias)
+		fatalIf(err.Trace(sa.alias), "Unable to initialize admin connection.")
+		e := client.DeleteServiceAccount(ctx, sa.accessKey)
+		fatalIf(probe.NewError(e).Trace(sa.alias, sa.accessKey), "Unable to delete the service account `"+sa.accessKey+"`.")
+
+		// All the shares signed by the service account are revoked.
+		for filename, shareDB := range dbs {
+			for shareURL, share := range shareDB.Shares {
+				if share.Alias == sa.alias && share.AccessKey == sa.accessKey {
+					share.Revoked = true
+					shareDB.Shares[shareURL] = share
+				}
+			}
+			fatalIf(shareDB.Save(filename).Trace(filename), "Unable to save the revoked shares.")
+		}
+		printMsg(shareRevokeMessage{Alias: sa.alias, AccessKey: sa.accessKey, Shares: counts[sa]})
+	}
+	return nil
+}
@@ -34,6 +34,8 @@ var shareUploadFlags = []cli.Flag{
 		Usage: "recursively upload any object matching the prefix",
 	},
 	shareFlagExpire,
+	shareFlagLabel,
+	shareFlagRevocable,
 	shareFlagContentType,
 }
 
@@ -66,6 +68,9 @@ EXAMPLES:
 
   4. Generate a curl command to allow upload access to any objects matching the key prefix 'backup/'. Command expires in 2 hours.
      {{.Prompt}} {{.HelpName}} --recursive --expire=2h s3/backup/2007-Mar-2/backup/
+
+  5. Generate a revocable curl command to allow a partner to upload to a folder for 2 days.
+     {{.Prompt}} {{.HelpName}} --recursive --expire=48h --revocable --label partner myminio/incoming/partner/
 `,
 }
 
@@ -135,7 +140,7 @@ func makeCurlCmd(key, postURL string, isRecursive bool, uploadInfo map[string]st
 }
 
 // save shared URL to disk.
-func saveSharedURL(objectURL string, shareURL string, expiry time.Duration, contentType string) *probe.Error {
+func saveSharedURL(shareURL string, share shareEntryV1) *probe.Error {
 	// Load previously saved upload-shares.
 	shareDB := newShareDBV1()
 	if err := shareDB.Load(getShareUploadsFile()); err != nil {
@@ -143,15 +148,23 @@ func saveSharedURL(objectURL string, shareURL string, expiry time.Duration, cont
 	}
 
 	// Make new entries to uploadsDB.
-	shareDB.Set(objectURL, shareURL, expiry, contentType)
+	shareDB.Set(shareURL, share)
 	shareDB.Save(getShareUploadsFile())
 
 	return nil
 }
 
 // doShareUploadURL uploads files to the target.
-func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, expiry time.Duration, contentType string) *probe.Error {
-	clnt, err := newClient(objectURL)
+func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, expiry time.Duration, contentType, label string, revocable bool) *probe.Error {
+	alias, urlStrFull, _, err := expandAlias(objectURL)
+	if err != nil {
+		return err.Trace(objectURL)
+	}
+	signer, err := newShareSigner(ctx, alias, urlStrFull, "s3:PutObject", expiry, revocable)
+	if err != nil {
+		return err.Trace(objectURL)
+	}
+	clnt, err := signer.client(urlStrFull)
 	if err != nil {
 		return err.Trace(objectURL)
 	}
@@ -176,10 +189,12 @@ func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, e
 		ShareURL:    curlCmd,
 		TimeLeft:    expiry,
 		ContentType: contentType,
+		Label:       label,
+		Revocable:   revocable,
 	})
 
 	// save shared URL to disk.
-	return saveSharedURL(objectURL, curlCmd, expiry, contentType)
+	return saveSharedURL(curlCmd, signer.entry(objectURL, expiry, contentType, label))
 }
 
 // main for share upload command.
@@ -208,7 +223,7 @@ func mainShareUpload(cliCtx *cli.Context) error {
 	}
 
 	for _, targetURL := range cliCtx.Args() {
-		err := doShareUploadURL(ctx, targetURL, isRecursive, expiry, contentType)
+		err := doShareUploadURL(ctx, targetURL, isRecursive, expiry, contentType, cliCtx.String("label"), cliCtx.Bool("revocable"))
 		if err != nil {
 			switch err.ToGoError().(type) {
 			case APINotImplemented:
@@ -48,6 +48,14 @@ var (
 		Value: "168h",
 		Usage: "set expiry in NN[h|m|s]",
 	}
+	shareFlagLabel = cli.StringFlag{
+		Name:  "label",
+		Usage: "label the share to find it with 'mc share list' and 'mc share revoke'",
+	}
+	shareFlagRevocable = cli.BoolFlag{
+		Name:  "revocable",
+		Usage: "sign with a dedicated MinIO service account which 'mc share revoke' deletes",
+	}
 )
 
 // Structured share command message.
@@ -57,12 +65,25 @@ type shareMesssage struct {
 	ShareURL    string        `json:"share"`
 	TimeLeft    time.Duration `json:"timeLeft"`
 	ContentType string        `json:"contentType,omitempty"` // Only used by upload cmd.
+	Label       string        `json:"label,omitempty"`
+	State       string        `json:"state,omitempty"` // Only used by list cmd.
+	Revocable   bool          `json:"revocable,omitempty"`
 }
 
 // String - Themefied string message for console printing.
 func (s shareMesssage) String() string {
 	msg := console.Colorize("URL", fmt.Sprintf("URL: %s\n", s.ObjectURL))
-	msg += console.Colorize("Expire", fmt.Sprintf("Expire: %s\n", timeDurationToHumanizedDuration(s.TimeLeft)))
+	if s.Label != "" {
+		msg += fmt.Sprintf("Label: %s\n", s.Label)
+	}
+	if s.State == shareStateExpired || s.State == shareStateRevoked {
+		msg += console.Colorize("File", fmt.Sprintf("Expire: %s\n", s.State))
+	} else {
+		msg += console.Colorize("Expire", fmt.Sprintf("Expire: %s\n", timeDurationToHumanizedDuration(s.TimeLeft)))
+	}
+	if s.Revocable {
+		msg += "Revocable: yes\n"
+	}
 	if s.ContentType != "" {
 		msg += console.Colorize("Content-type", fmt.Sprintf("Content-Type: %s\n", s.ContentType))
 	}
@@ -0,0 +1,409 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/color"
+	"github.com/google/shlex"
+	"github.com/minio/cli"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/posener/complete"
+	"golang.org/x/term"
+)
+
+var shellCmd = cli.Command{
+	Name:         "shell",
+	Usage:        "start an interactive shell keeping connections warm",
+	Action:       mainShell,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [ALIAS[/BUCKET[/PREFIX]]]
+
+  Commands are typed without the leading 'mc', connections and
+  credentials are reused between the commands. Tab completes commands,
+  aliases, buckets and objects, up and down browse the history.
+
+  Arguments which do not start with an alias are relative to the
+  current location, set with 'cd'. 'ls', 'tree', 'du' and 'find'
+  without arguments list the current location.
+
+BUILTINS:
+  cd [PATH|..]  change the current location, without PATH clear it
+  pwd           print the current location
+  history       print the command history
+  exit, quit    leave the shell
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Start a shell in the bucket "mybucket" of the alias "myminio".
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+     mc myminio/mybucket/> ls
+     mc myminio/mybucket/> cp photos/a.jpg photos/b.jpg
+     mc myminio/mybucket/> cd ..
+     mc myminio/> admin info myminio
+`,
+}
+
+// shellExit interrupts a command run by the shell instead of exiting mc.
+type shellExit struct {
+	status int
+}
+
+// shellGlobals are the globals set by the flags of a command, restored
+// before each command.
+type shellGlobals struct {
+	quiet, json, porcelain, jsonLine, debug bool
+	noColor, insecure, devMode, airgapped   bool
+	output                                  string
+	format                                  *template.Template
+	colorOff                                bool
+}
+
+func saveShellGlobals() shellGlobals {
+	return shellGlobals{
+		quiet: globalQuiet, json: globalJSON, porcelain: globalPorcelain, jsonLine: globalJSONLine,
+		debug: globalDebug, noColor: globalNoColor, insecure: globalInsecure, devMode: globalDevMode,
+		airgapped: globalAirgapped, output: globalOutput, format: globalFormat, colorOff: color.NoColor,
+	}
+}
+
+func (g shellGlobals) restore() {
+	globalQuiet, globalJSON, globalPorcelain, globalJSONLine = g.quiet, g.json, g.porcelain, g.jsonLine
+	globalDebug, globalNoColor, globalInsecure, globalDevMode = g.debug, g.noColor, g.insecure, g.devMode
+	globalAirgapped, globalOutput, globalFormat, color.NoColor = g.airgapped, g.output, g.format, g.colorOff
+	globalCurrentAlias = ""
+	outputCount, outputHeader = 0, nil
+}
+
+// globalShell is set while `mc shell` runs.
+var globalShell bool
+
+type mcShell struct {
+	app         *cli.App
+	cwd         string
+	globals     shellGlobals
+	history     []string
+	historyPath string
+	term        *term.Terminal
+	completer   complete.Command
+}
+
+func (sh *mcShell) prompt() string {
+	return "mc " + sh.cwd + "> "
+}
+
+// commandsWithDefaultTarget list the current location when they are
+// run without arguments.
+var commandsWithDefaultTarget = map[string]bool{
+	"ls": true, "tree": true, "du": true, "find": true,
+}
+
+// resolvePath resolves a path relative to the current location.
+func (sh *mcShell) resolvePath(p string) string {
+	if sh.cwd == "" || isExplicitLocalPath(p) {
+		return p
+	}
+	if alias, _ := url2Alias(p); alias != "" && mustGetHostConfig(alias) != nil {
+		return p
+	}
+	return sh.cwd + p
+}
+
+// cd changes the current location.
+func (sh *mcShell) cd(args []string) *probe.Error {
+	if len(args) == 0 {
+		sh.cwd = ""
+		return probe.NewError(os.Unsetenv(mcEnvAlias))
+	}
+
+	target := args[0]
+	switch {
+	case target == "..":
+		trimmed := strings.TrimSuffix(sh.cwd, "/")
+		target = ""
+		if i := strings.LastIndex(trimmed, "/"); i >= 0 {
+			target = trimmed[:i+1]
+		}
+	default:
+		target = sh.resolvePath(target)
+	}
+	if target == "" {
+		return sh.cd(nil)
+	}
+
+	if !strings.HasSuffix(target, "/") {
+		target += "/"
+	}
+	alias, _ := url2Alias(target)
+	if mustGetHostConfig(alias) == nil {
+		return errInvalidAliasedURL(target).Trace(target)
+	}
+	if _, err := newClient(target); err != nil {
+		return err.Trace(target)
+	}
+	sh.cwd = target
+	// Arguments of the commands are resolved relative to the current location.
+	return probe.NewError(os.Setenv(mcEnvAlias, strings.TrimSuffix(target, "/")))
+}
+
+// run runs a command in this process, fatal errors and exit statuses
+// only end the command.
+func (sh *mcShell) run(args []string) (status int) {
+	sh.globals.restore()
+	resetAuditLog()
+	globalContext, globalCancel = context.WithCancel(context.Background())
+	globalHelpPager = newTermPager()
+
+	defer func() {
+		if r := recover(); r != nil {
+			exit, ok := r.(shellExit)
+			if !ok {
+				panic(r)
+			}
+			status = exit.status
+		}
+		globalHelpPager.WaitForExit()
+		runExitHooks(status)
+		resetExitHooks()
+		globalCancel()
+	}()
+
+	if commandsWithDefaultTarget[args[0]] && sh.cwd != "" {
+		positional := false
+		for _, arg := range args[1:] {
+			positional = positional || !strings.HasPrefix(arg, "-")
+		}
+		if !positional {
+			args = append(args, sh.cwd)
+		}
+	}
+
+	if isTerminal() {
+		sh.app.HelpWriter = globalHelpPager
+	}
+	if e := sh.app.Run(append([]string{sh.app.Name}, args...)); e != nil {
+		console.Errorln(e)
+		return globalErrorExitStatus
+	}
+	return 0
+}
+
+// autoComplete completes builtins, commands, flags, aliases, buckets
+// and objects, relative to the current location.
+func (sh *mcShell) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	head, tail := line[:pos], line[pos:]
+
+	fields := strings.Fields(head)
+	if len(fields) == 0 || strings.HasSuffix(head, " ") {
+		fields = append(fields, "")
+	}
+	last := fields[len(fields)-1]
+	a := complete.Args{All: fields, Completed: fields[:len(fields)-1], Last: last}
+	if len(a.Completed) > 0 {
+		a.LastCompleted = a.Completed[len(a.Completed)-1]
+	}
+
+	prefix := ""
+	if len(fields) > 1 && !strings.HasPrefix(last, "-") {
+		if resolved := sh.resolvePath(last); resolved != last || (last == "" && sh.cwd != "") {
+			prefix = sh.cwd
+			a.Last = sh.cwd + last
+		}
+	}
+
+	var options []string
+	if len(fields) == 1 {
+		options = append(options, "cd", "pwd", "history", "exit", "quit")
+	}
+	if len(fields) == 2 && fields[0] == "cd" {
+		// cd only completes the current alias, buckets and prefixes.
+		for _, option := range s3Completer.Predict(a) {
+			if strings.HasSuffix(option, "/") {
+				options = append(options, option)
+			}
+		}
+	} else {
+		options = append(options, sh.completer.Predict(a)...)
+	}
+
+	var matches []string
+	seen := map[string]bool{}
+	for _, option := range options {
+		if strings.HasPrefix(option, a.Last) && !seen[option] {
+			seen[option] = true
+			matches = append(matches, strings.TrimPrefix(option, prefix))
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return line, pos, true
+	case 1:
+		completed := matches[0]
+		if !strings.HasSuffix(completed, "/") {
+			completed += " "
+		}
+		head = head[:len(head)-len(last)] + completed
+		return head + tail, len(head), true
+	}
+
+	common := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(common) > len(last) {
+		head = head[:len(head)-len(last)] + common
+		return head + tail, len(head), true
+	}
+
+	// Show the options and repaint the line being edited below them, the
+	// line editor is left unaware of it.
+	fmt.Fprint(os.Stdout, "\r\n"+strings.Join(matches, "  ")+"\r\n"+sh.prompt()+line)
+	if len(tail) > 0 {
+		fmt.Fprintf(os.Stdout, "\x1b[%dD", len([]rune(tail)))
+	}
+	return "", 0, false
+}
+
+func (sh *mcShell) loadHistory() {
+	buf, e := os.ReadFile(sh.historyPath)
+	if e != nil {
+		return
+	}
+	for _, line := range strings.Split(string(buf), "\n") {
+		if line != "" {
+			sh.history = append(sh.history, line)
+		}
+	}
+}
+
+func (sh *mcShell) addHistory(line string) {
+	sh.history = append(sh.history, line)
+	f, e := os.OpenFile(sh.historyPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if e != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// mainShell is the handle for "mc shell" command.
+func mainShell(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fatalIf(errInvalidArgument(), "`mc shell` requires a terminal.")
+	}
+
+	sh := &mcShell{
+		app:         ctx.App,
+		globals:     saveShellGlobals(),
+		historyPath: filepath.Join(mustGetMcConfigDir(), "shell_history"),
+		completer:   mcCompleteCommand(ctx.App.Commands),
+	}
+	if ctx.Args().Present() {
+		fatalIf(sh.cd(ctx.Args()), "Unable to change the location.")
+	}
+	sh.loadHistory()
+
+	globalShell = true
+	// Fatal errors and exit statuses end the command, not mc.
+	exitProcess = func(code int) { panic(shellExit{status: code}) }
+
+	sh.term = term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, sh.prompt())
+	sh.term.AutoCompleteCallback = sh.autoComplete
+
+	for {
+		state, e := term.MakeRaw(fd)
+		fatalIf(probe.NewError(e), "Unable to initialize the terminal.")
+		if w, h, e := term.GetSize(fd); e == nil && w > 0 {
+			sh.term.SetSize(w, h)
+		}
+		sh.term.SetPrompt(sh.prompt())
+		line, e := sh.term.ReadLine()
+		term.Restore(fd, state)
+		if e == io.EOF {
+			fmt.Println()
+			return nil
+		}
+		if e != nil {
+			fatalIf(probe.NewError(e), "Unable to read the command.")
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		sh.addHistory(line)
+
+		args, e := shlex.Split(line)
+		if e != nil {
+			console.Errorln("Unable to parse the command:", e)
+			continue
+		}
+		switch args[0] {
+		case "exit", "quit":
+			return nil
+		case "pwd":
+			fmt.Println(sh.cwd)
+		case "history":
+			for i, line := range sh.history {
+				fmt.Printf("%5d  %s\n", i+1, line)
+			}
+		case "cd":
+			if err := sh.cd(args[1:]); err != nil {
+				console.Errorln("Unable to change the location:", err.ToGoError())
+			}
+		case "shell":
+			console.Errorln("Already in a shell.")
+		case "mc":
+			if len(args) > 1 {
+				sh.run(args[1:])
+			}
+		default:
+			sh.run(args)
+		}
+	}
+}
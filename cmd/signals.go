@@ -32,8 +32,13 @@ func trapSignals(sig ...os.Signal) {
 	// receive notifications of the specified signals.
 	signal.Notify(sigCh, sig...)
 
-	// Wait for the signal.
+	// Wait for the signal, an interrupt only cancels the
+	// running command of `mc shell`.
 	s := <-sigCh
+	for s == os.Interrupt && globalShell {
+		globalCancel()
+		s = <-sigCh
+	}
 
 	// Once signal has been received stop signal Notify handler.
 	signal.Stop(sigCh)
@@ -56,5 +61,6 @@ func trapSignals(sig ...os.Signal) {
 	default:
 		exitCode = globalErrorExitStatus
 	}
+	runExitHooks(exitCode)
 	os.Exit(exitCode)
 }
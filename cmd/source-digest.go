@@ -0,0 +1,90 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// parseSourceDigest parses a digest given as ALGORITHM:HEX.
+func parseSourceDigest(digest string) (hash.Hash, []byte, error) {
+	algorithm, sum, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, nil, errors.New("digest must be of the form ALGORITHM:HEX")
+	}
+	var h hash.Hash
+	switch strings.ToLower(algorithm) {
+	case "md5":
+		h = md5.New()
+	case "sha1":
+		h = sha1.New()
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil, nil, fmt.Errorf("unsupported digest algorithm %s, valid options are [md5, sha1, sha256, sha512]", algorithm)
+	}
+	want, e := hex.DecodeString(sum)
+	if e != nil || len(want) != h.Size() {
+		return nil, nil, fmt.Errorf("invalid %s digest %s", algorithm, sum)
+	}
+	return h, want, nil
+}
+
+// digestReader verifies the digest of the data read. The last bytes are
+// withheld when the digest does not match, so that the uploads reading
+// exactly the size of the source fail rather than complete.
+type digestReader struct {
+	io.ReadCloser
+	h    hash.Hash
+	want []byte
+	size int64
+	n    int64
+}
+
+// newDigestReader returns a reader verifying the digest of the source,
+// size is -1 when unknown.
+func newDigestReader(reader io.ReadCloser, digest string, size int64) (io.ReadCloser, error) {
+	h, want, e := parseSourceDigest(digest)
+	if e != nil {
+		return nil, e
+	}
+	return &digestReader{ReadCloser: reader, h: h, want: want, size: size}, nil
+}
+
+func (r *digestReader) Read(p []byte) (int, error) {
+	n, e := r.ReadCloser.Read(p)
+	r.h.Write(p[:n])
+	r.n += int64(n)
+	if e == io.EOF || (r.size >= 0 && r.n == r.size) {
+		if sum := r.h.Sum(nil); !bytes.Equal(sum, r.want) {
+			return 0, fmt.Errorf("digest mismatch, expected %x but the source has %x", r.want, sum)
+		}
+	}
+	return n, e
+}
@@ -135,6 +135,10 @@ func parseAndCheckStatSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB
 
 // mainStat - is a handler for mc stat command
 func mainStat(cliCtx *cli.Context) error {
+	if runOnAliasGroup(cliCtx) {
+		return nil
+	}
+
 	ctx, cancelStat := context.WithCancel(globalContext)
 	defer cancelStat()
 
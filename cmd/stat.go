@@ -50,6 +50,8 @@ type statMessage struct {
 	Expiration        *time.Time         `json:"expiration,omitempty"`
 	ExpirationRuleID  string             `json:"expirationRuleID,omitempty"`
 	ReplicationStatus string             `json:"replicationStatus,omitempty"`
+	Tier              string             `json:"tier,omitempty"`
+	RestoreStatus     string             `json:"restoreStatus,omitempty"`
 	Metadata          map[string]string  `json:"metadata,omitempty"`
 	VersionID         string             `json:"versionID,omitempty"`
 	DeleteMarker      bool               `json:"deleteMarker,omitempty"`
@@ -86,8 +88,11 @@ func (stat statMessage) String() (msg string) {
 		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s (lifecycle-rule-id: %s) ", "Expiration",
 			stat.Expiration.Local().Format(printDate), stat.ExpirationRuleID) + "\n")
 	}
+	if stat.Tier != "" {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Tier", stat.Tier) + "\n")
+	}
 	if stat.Restore != nil {
-		msgBuilder.WriteString(fmt.Sprintf("%-10s:", "Restore") + "\n")
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s", "Restore", stat.RestoreStatus) + "\n")
 		msgBuilder.WriteString(fmt.Sprintf("  %-10s: %s", "ExpiryTime",
 			stat.Restore.ExpiryTime.Local().Format(printDate)) + "\n")
 		msgBuilder.WriteString(fmt.Sprintf("  %-10s: %t", "Ongoing",
@@ -143,6 +148,39 @@ func (stat statMessage) JSON() string {
 	return string(jsonMessageBytes)
 }
 
+// statMessageV2 is statMessage in version 2 of the JSON schema, the name
+// and version of the object are named like in ls.
+type statMessageV2 struct {
+	Status            string             `json:"status"`
+	Key               string             `json:"key"`
+	Date              time.Time          `json:"lastModified"`
+	Size              int64              `json:"size"`
+	ETag              string             `json:"etag"`
+	Type              string             `json:"type,omitempty"`
+	Expires           *time.Time         `json:"expires,omitempty"`
+	Expiration        *time.Time         `json:"expiration,omitempty"`
+	ExpirationRuleID  string             `json:"expirationRuleID,omitempty"`
+	ReplicationStatus string             `json:"replicationStatus,omitempty"`
+	Tier              string             `json:"tier,omitempty"`
+	RestoreStatus     string             `json:"restoreStatus,omitempty"`
+	Metadata          map[string]string  `json:"metadata,omitempty"`
+	VersionID         string             `json:"versionId,omitempty"`
+	DeleteMarker      bool               `json:"isDeleteMarker,omitempty"`
+	Restore           *minio.RestoreInfo `json:"restore,omitempty"`
+}
+
+// JSONVersion returns the JSON of the message in a version of the schema.
+func (stat statMessage) JSONVersion(version int) string {
+	if version < jsonAPIVersion2 {
+		return stat.JSON()
+	}
+	stat.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(statMessageV2(stat), "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
 // parseStat parses client Content container into statMessage struct.
 func parseStat(c *ClientContent) statMessage {
 	content := statMessage{}
@@ -169,6 +207,8 @@ func parseStat(c *ClientContent) statMessage {
 	content.ExpirationRuleID = c.ExpirationRuleID
 	content.ReplicationStatus = c.ReplicationStatus
 	content.Restore = c.Restore
+	content.RestoreStatus = restoreStatus(c.Restore)
+	content.Tier = objectTier(c.StorageClass)
 	return content
 }
 
@@ -275,6 +315,9 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 		}
 
 		url := targetAlias + getKey(content)
+		if isHTTPSourceURL(targetURL) {
+			url = content.URL.String()
+		}
 		standardizedURL := getStandardizedURL(targetURL)
 
 		if !isRecursive && !strings.HasPrefix(filepath.FromSlash(url), standardizedURL) && !filepath.IsAbs(url) {
@@ -0,0 +1,84 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// storageClassRule sets the storage class of the objects matching a
+// pattern.
+type storageClassRule struct {
+	pattern      string
+	storageClass string
+}
+
+// storageClassMap maps the objects to storage classes, the first matching
+// rule applies.
+type storageClassMap []storageClassRule
+
+// parseStorageClassMap parses a comma separated list of PATTERN=CLASS.
+func parseStorageClassMap(s string) (storageClassMap, error) {
+	var m storageClassMap
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pattern, class, ok := strings.Cut(entry, "=")
+		pattern, class = strings.TrimSpace(pattern), strings.TrimSpace(class)
+		if !ok || pattern == "" || class == "" {
+			return nil, fmt.Errorf("invalid storage class mapping `%s`, expected PATTERN=CLASS", entry)
+		}
+		if _, e := path.Match(pattern, ""); e != nil {
+			return nil, fmt.Errorf("invalid pattern `%s`: %w", pattern, e)
+		}
+		m = append(m, storageClassRule{pattern: pattern, storageClass: strings.ToUpper(class)})
+	}
+	return m, nil
+}
+
+// lookup returns the storage class of an object, empty when no rule
+// matches.
+func (m storageClassMap) lookup(u ClientURL) string {
+	for _, rule := range m {
+		if matchObjectPattern(rule.pattern, u) {
+			return rule.storageClass
+		}
+	}
+	return ""
+}
+
+// matchObjectPattern returns true when an object matches pattern. Patterns
+// without a separator match the name of the object, the others its key in
+// the bucket.
+func matchObjectPattern(pattern string, u ClientURL) bool {
+	p := strings.TrimPrefix(u.Path, string(u.Separator))
+	if u.Type == objectStorage {
+		if i := strings.Index(p, string(u.Separator)); i >= 0 {
+			p = p[i+1:]
+		}
+	}
+	p = strings.ReplaceAll(p, string(u.Separator), "/")
+	if !strings.Contains(pattern, "/") {
+		p = path.Base(p)
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
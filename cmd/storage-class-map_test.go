@@ -0,0 +1,48 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+package cmd
+
+import "testing"
+
+func TestStorageClassMap(t *testing.T) {
+	m, e := parseStorageClassMap("*.log=reduced_redundancy, tmp/*=STANDARD ,*.parquet=STANDARD_IA")
+	if e != nil {
+		t.Fatal(e)
+	}
+	testCases := []struct {
+		url          string
+		storageClass string
+	}{
+		{"http://localhost:9000/bucket/app.log", "REDUCED_REDUNDANCY"},
+		{"http://localhost:9000/bucket/logs/2023/app.log", "REDUCED_REDUNDANCY"},
+		{"http://localhost:9000/bucket/tmp/data.parquet", "STANDARD"},
+		{"http://localhost:9000/bucket/tables/data.parquet", "STANDARD_IA"},
+		{"http://localhost:9000/bucket/tmp/a/b.bin", ""},
+		{"http://localhost:9000/bucket/readme.md", ""},
+	}
+	for _, testCase := range testCases {
+		if storageClass := m.lookup(*newClientURL(testCase.url)); storageClass != testCase.storageClass {
+			t.Errorf("%s: expected %q, got %q", testCase.url, testCase.storageClass, storageClass)
+		}
+	}
+
+	for _, invalid := range []string{"*.log", "=STANDARD", "[.log=STANDARD"} {
+		if _, e := parseStorageClassMap(invalid); e == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
@@ -70,6 +70,10 @@ mr/cKCUyBL7rcAvg0zNq1vcSrUSGlAmY3SEDCu3GOKnjG/U4E7+p957ocWSV+mQU
 			Usage:  "API Key of the account on SUBNET",
 			EnvVar: "_MC_SUBNET_API_KEY",
 		},
+		cli.StringFlag{
+			Name:  "airgap-proxy",
+			Usage: "relay proxy URL used to reach SUBNET, remembered for the alias (pass an empty value to clear)",
+		},
 	}
 )
 
@@ -302,6 +306,10 @@ func setGlobalSubnetProxyFromConfig(alias string) error {
 	if env, ok := os.LookupEnv("_MC_SUBNET_PROXY_URL"); ok {
 		proxy = env
 		supported = env != ""
+	} else if aliasProxy := getSubnetProxyFromMcConfig(alias); len(aliasProxy) > 0 {
+		// relay proxy remembered for this alias via --airgap-proxy
+		proxy = aliasProxy
+		supported = true
 	} else {
 		proxy, supported = getKeyFromSubnetConfig(alias, "proxy")
 	}
@@ -365,6 +373,35 @@ func setSubnetLicenseInMcConfig(alias string, lic string) {
 	setAlias(alias, aliasCfg)
 }
 
+func getSubnetProxyFromMcConfig(alias string) string {
+	return mcConfig().Aliases[alias].SubnetProxy
+}
+
+// setSubnetProxyInMcConfig - persists the SUBNET relay proxy for the alias,
+// an empty proxy removes the setting.
+func setSubnetProxyInMcConfig(alias string, proxy string) {
+	aliasCfg := mcConfig().Aliases[alias]
+	aliasCfg.SubnetProxy = proxy
+	setAlias(alias, aliasCfg)
+}
+
+// parseSubnetProxy - validates the proxy URL passed via --airgap-proxy
+func parseSubnetProxy(proxy string) (*url.URL, error) {
+	proxyURL, e := url.Parse(proxy)
+	if e != nil {
+		return nil, e
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme '%s', supported schemes are http, https and socks5", proxyURL.Scheme)
+	}
+	if len(proxyURL.Host) == 0 {
+		return nil, fmt.Errorf("proxy URL '%s' has no host", proxy)
+	}
+	return proxyURL, nil
+}
+
 func setSubnetConfig(alias string, subKey string, cfgVal string) {
 	client, err := newAdminClient(alias)
 	fatalIf(err, "Unable to initialize admin connection.")
@@ -800,6 +837,15 @@ func initSubnetConnectivity(ctx *cli.Context, aliasedURL string, forUpload bool)
 	apiKey, e := getAPIKeyFlag(ctx)
 	fatalIf(probe.NewError(e), "Error in reading --api-key flag:")
 
+	if ctx.IsSet("airgap-proxy") {
+		proxy := ctx.String("airgap-proxy")
+		if len(proxy) > 0 {
+			globalSubnetProxyURL, e = parseSubnetProxy(proxy)
+			fatalIf(probe.NewError(e), "Invalid --airgap-proxy:")
+		}
+		setSubnetProxyInMcConfig(alias, proxy)
+	}
+
 	// if `--airgap` is provided no need to test SUBNET connectivity.
 	if !globalAirgapped {
 		e = setGlobalSubnetProxyFromConfig(alias)
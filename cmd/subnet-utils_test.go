@@ -32,3 +32,26 @@ func TestSubnetBaseURL(t *testing.T) {
 		t.Fatalf("Expected TestSubnetBaseURL() to return an https url, received %s", u.Scheme)
 	}
 }
+
+func TestParseSubnetProxy(t *testing.T) {
+	testCases := []struct {
+		proxy   string
+		success bool
+	}{
+		{"http://relay.internal:3128", true},
+		{"https://relay.internal", true},
+		{"socks5://relay.internal:1080", true},
+		{"ftp://relay.internal", false},
+		{"http://", false},
+		{"relay.internal:3128", false},
+	}
+	for i, testCase := range testCases {
+		_, e := parseSubnetProxy(testCase.proxy)
+		if testCase.success && e != nil {
+			t.Errorf("Test %d: expected success for %s, got %v", i+1, testCase.proxy, e)
+		}
+		if !testCase.success && e == nil {
+			t.Errorf("Test %d: expected failure for %s", i+1, testCase.proxy)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	gojson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+const callhomePreviewFile = "callhome-preview"
+
+// callhomePayloadSection is a part of the diagnostics payload and its size.
+type callhomePayloadSection struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+// callhomeDiagPreview is the diagnostics payload uploaded at the next cycle.
+type callhomeDiagPreview struct {
+	Enabled  bool                     `json:"enabled"`
+	File     string                   `json:"file,omitempty"`
+	Size     int                      `json:"size"`
+	Sections []callhomePayloadSection `json:"sections,omitempty"`
+}
+
+// callhomeLogsPreview is where the server logs are pushed.
+type callhomeLogsPreview struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+type supportCallhomePreviewMessage struct {
+	Status string               `json:"status"`
+	Diag   *callhomeDiagPreview `json:"diag,omitempty"`
+	Logs   *callhomeLogsPreview `json:"logs,omitempty"`
+}
+
+// String colorized callhome preview message.
+func (s supportCallhomePreviewMessage) String() string {
+	var b strings.Builder
+	if d := s.Diag; d != nil {
+		fmt.Fprintf(&b, "Diagnostics (%s): %s saved at '%s'\n", featureStatusStr(d.Enabled), humanize.IBytes(uint64(d.Size)), d.File)
+		for _, section := range d.Sections {
+			fmt.Fprintf(&b, "  %-30s %s\n", section.Name, humanize.IBytes(uint64(section.Size)))
+		}
+	}
+	if l := s.Logs; l != nil {
+		if l.Enabled {
+			fmt.Fprintf(&b, "Logs (enabled): server logs are pushed in real-time to %s\n", l.Endpoint)
+		} else {
+			b.WriteString("Logs (disabled): no server logs are pushed\n")
+		}
+	}
+	return console.Colorize(supportSuccessMsgTag, strings.TrimSuffix(b.String(), "\n"))
+}
+
+// JSON jsonified callhome preview message.
+func (s supportCallhomePreviewMessage) JSON() string {
+	s.Status = "success"
+	jsonBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonBytes)
+}
+
+// callhomePayloadSections returns the sections of a diagnostics payload, the
+// objects of the top level are split in their own sections.
+func callhomePayloadSections(payload []byte) ([]callhomePayloadSection, error) {
+	var top map[string]gojson.RawMessage
+	if e := gojson.Unmarshal(payload, &top); e != nil {
+		return nil, e
+	}
+	var sections []callhomePayloadSection
+	for name, value := range top {
+		var sub map[string]gojson.RawMessage
+		if gojson.Unmarshal(value, &sub) != nil || len(sub) == 0 {
+			sections = append(sections, callhomePayloadSection{Name: name, Size: len(value)})
+			continue
+		}
+		for subName, subValue := range sub {
+			sections = append(sections, callhomePayloadSection{Name: name + "." + subName, Size: len(subValue)})
+		}
+	}
+	sort.Slice(sections, func(i, j int) bool { return sections[i].Name < sections[j].Name })
+	return sections, nil
+}
+
+// fetchCallhomeDiag collects the diagnostics the server sends at every
+// callhome cycle, which is the last state of the health info stream.
+func fetchCallhomeDiag(client *madmin.AdminClient) ([]byte, error) {
+	resp, _, e := client.ServerHealthInfo(globalContext, madmin.HealthDataTypesList, time.Minute)
+	if e != nil {
+		return nil, e
+	}
+	defer resp.Body.Close()
+
+	var payload gojson.RawMessage
+	decoder := gojson.NewDecoder(resp.Body)
+	for {
+		var info gojson.RawMessage
+		if e = decoder.Decode(&info); e != nil {
+			if errors.Is(e, io.EOF) {
+				return payload, nil
+			}
+			return nil, e
+		}
+		payload = info
+	}
+}
+
+// previewCallhome shows the payload uploaded by the enabled callhome
+// features without uploading it.
+func previewCallhome(alias string, diag, logs bool) {
+	client, err := newAdminClient(alias)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	msg := supportCallhomePreviewMessage{}
+	if diag {
+		payload, e := fetchCallhomeDiag(client)
+		fatalIf(probe.NewError(e), "Unable to fetch the diagnostics of `"+alias+"`.")
+		sections, e := callhomePayloadSections(payload)
+		fatalIf(probe.NewError(e), "Unable to parse the diagnostics of `"+alias+"`.")
+
+		file := callhomePreviewFile + "-" + time.Now().Format(dateTimeFormatFilename) + ".json"
+		e = os.WriteFile(file, payload, 0o600)
+		fatalIf(probe.NewError(e), "Unable to save the diagnostics preview.")
+		msg.Diag = &callhomeDiagPreview{
+			Enabled:  isDiagCallhomeEnabled(alias),
+			File:     file,
+			Size:     len(payload),
+			Sections: sections,
+		}
+	}
+	if logs {
+		msg.Logs = &callhomeLogsPreview{Enabled: isLogsCallhomeEnabled(alias)}
+		if msg.Logs.Enabled {
+			msg.Logs.Endpoint = subnetLogWebhookURL()
+		}
+	}
+	printMsg(msg)
+}
@@ -50,12 +50,13 @@ var supportCallhomeCmd = cli.Command{
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} enable|disable|status ALIAS
+  {{.HelpName}} enable|disable|status|preview ALIAS
 
 OPTIONS:
   enable - Enable callhome
   disable - Disable callhome
   status - Display callhome settings
+  preview - Save the diagnostics uploaded at the next cycle and show where logs are pushed, without uploading
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -78,6 +79,9 @@ EXAMPLES:
 
   6. Check logs callhome status for cluster with alias 'myminio'
      {{.Prompt}} {{.HelpName}} status myminio --logs
+
+  7. Preview the diagnostics uploaded by callhome for cluster with alias 'myminio'
+     {{.Prompt}} {{.HelpName}} preview myminio --diag
 `,
 }
 
@@ -122,6 +126,12 @@ func isDiagCallhomeEnabled(alias string) bool {
 
 func mainCallhome(ctx *cli.Context) error {
 	setSuccessMessageColor()
+	if len(ctx.Args()) == 2 && ctx.Args().Get(0) == "preview" {
+		alias, _ := url2Alias(ctx.Args().Get(1))
+		diag, logs := parseCallhomeFlags(ctx)
+		previewCallhome(alias, diag, logs)
+		return nil
+	}
 	alias, arg := checkToggleCmdSyntax(ctx)
 
 	diag, logs := parseCallhomeFlags(ctx)
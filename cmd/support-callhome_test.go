@@ -0,0 +1,40 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCallhomePayloadSections(t *testing.T) {
+	payload := []byte(`{"version":"3","sys":{"cpus":[1,2],"mem":{}},"minio":{"info":{"a":1}}}`)
+	sections, e := callhomePayloadSections(payload)
+	if e != nil {
+		t.Fatal(e)
+	}
+	want := []callhomePayloadSection{
+		{Name: "minio.info", Size: 7},
+		{Name: "sys.cpus", Size: 5},
+		{Name: "sys.mem", Size: 2},
+		{Name: "version", Size: 3},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Fatalf("expected %v, got %v", want, sections)
+	}
+}
@@ -18,8 +18,11 @@
 package cmd
 
 import (
+	"archive/zip"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -44,6 +47,14 @@ var (
 			Usage: "profiler type, possible values are 'cpu', 'cpuio', 'mem', 'block', 'mutex', 'trace', 'threads' and 'goroutines'",
 			Value: "cpu,mem,block,mutex,goroutines",
 		},
+		cli.BoolFlag{
+			Name:  "extract",
+			Usage: "unpack the downloaded profile data into a directory",
+		},
+		cli.StringFlag{
+			Name:  "pprof",
+			Usage: "unpack the profile data and open the profiles of the given type with 'go tool pprof -http'",
+		},
 	}, subnetCommonFlags...)
 )
 
@@ -78,6 +89,9 @@ EXAMPLES:
 
   4. Profile CPU for 10 seconds on cluster with alias 'myminio', save and upload to SUBNET manually
      {{.Prompt}} {{.HelpName}} --type cpu --airgap myminio
+
+  5. Profile CPU for 30 seconds on cluster with alias 'myminio' and open the flamegraph of all the nodes in a browser
+     {{.Prompt}} {{.HelpName}} --type cpu --duration 30 --airgap --pprof cpu myminio
 `,
 }
 
@@ -107,6 +121,16 @@ func checkAdminProfileSyntax(ctx *cli.Context) {
 	if ctx.Int("duration") < 10 {
 		fatal(errDummy().Trace(), "profiling must be run for atleast 10 seconds")
 	}
+
+	if pprof := strings.ToLower(ctx.String("pprof")); pprof != "" {
+		found := false
+		for _, profiler := range profilers {
+			found = found || profiler == pprof
+		}
+		if !found {
+			fatalIf(errInvalidArgument().Trace(pprof), "--pprof must be one of the profiler types given with --type.")
+		}
+	}
 }
 
 // moveFile - os.Rename cannot handle cross device renames, in our situation
@@ -163,6 +187,97 @@ func saveProfileFile(data io.ReadCloser) {
 	fatalIf(probe.NewError(moveFile(tmpFile.Name(), profileFile)), "Unable to save profile data")
 }
 
+// extractProfileFile unpacks the profile data into a directory and returns
+// the paths of the unpacked files.
+func extractProfileFile(zipFile, dir string) ([]string, *probe.Error) {
+	r, e := zip.OpenReader(zipFile)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer r.Close()
+
+	if e = os.MkdirAll(dir, 0o755); e != nil {
+		return nil, probe.NewError(e)
+	}
+	var files []string
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		// The archive is flat, only keep the base name so that no
+		// file can be written outside of the directory.
+		path := filepath.Join(dir, filepath.Base(f.Name))
+		if e = extractZipFile(f, path); e != nil {
+			return nil, probe.NewError(e).Trace(f.Name)
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func extractZipFile(f *zip.File, path string) error {
+	src, e := f.Open()
+	if e != nil {
+		return e
+	}
+	defer src.Close()
+	dst, e := os.Create(path)
+	if e != nil {
+		return e
+	}
+	if _, e = io.Copy(dst, src); e != nil {
+		dst.Close()
+		return e
+	}
+	return dst.Close()
+}
+
+// selectProfiles returns the pprof files of a profiler type, one per node.
+func selectProfiles(files []string, profiler string) (selected []string) {
+	for _, file := range files {
+		if strings.HasSuffix(filepath.Base(file), "-"+profiler+".pprof") {
+			selected = append(selected, file)
+		}
+	}
+	return selected
+}
+
+// openProfiles starts 'go tool pprof -http' on the given profiles, which
+// merges the profiles of all the nodes. Without a Go toolchain the command
+// to run is printed instead.
+func openProfiles(files []string) {
+	args := append([]string{"tool", "pprof", "-http", "localhost:0"}, files...)
+	goBin, e := exec.LookPath("go")
+	if e != nil {
+		console.Infof("Install Go and run 'go %s' to view the flamegraph.\n", strings.Join(args, " "))
+		return
+	}
+	console.Infof("Opening the profiles with 'go %s', press Ctrl-C to exit.\n", strings.Join(args, " "))
+	cmd := exec.Command(goBin, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	fatalIf(probe.NewError(cmd.Run()), "Unable to run 'go tool pprof'.")
+}
+
+// unpackProfileFile unpacks the saved profile data when requested and returns
+// the directory and the profiles selected with --pprof.
+func unpackProfileFile(ctx *cli.Context) (dir string, profiles []string) {
+	pprof := strings.ToLower(ctx.String("pprof"))
+	if !ctx.Bool("extract") && pprof == "" {
+		return "", nil
+	}
+	dir = strings.TrimSuffix(profileFile, ".zip") + "-" + time.Now().Format(dateTimeFormatFilename)
+	files, err := extractProfileFile(profileFile, dir)
+	fatalIf(err.Trace(profileFile), "Unable to unpack the profile data.")
+	if pprof == "" {
+		return dir, nil
+	}
+	profiles = selectProfiles(files, pprof)
+	if len(profiles) == 0 {
+		fatalIf(errDummy().Trace(pprof), "No "+pprof+" profile found in the profile data.")
+	}
+	return dir, profiles
+}
+
 // mainSupportProfile is the handle for "mc support profile" command.
 func mainSupportProfile(ctx *cli.Context) error {
 	// Check for command syntax
@@ -202,6 +317,8 @@ func execSupportProfile(ctx *cli.Context, client *madmin.AdminClient, alias stri
 	fatalIf(probe.NewError(e), "Unable to save profile data")
 
 	saveProfileFile(data)
+	// Unpack before uploading, the upload removes the profile data.
+	dir, profiles := unpackProfileFile(ctx)
 
 	successClr := color.New(color.FgGreen, color.Bold)
 	failureClr := color.New(color.FgRed, color.Bold)
@@ -210,10 +327,19 @@ func execSupportProfile(ctx *cli.Context, client *madmin.AdminClient, alias stri
 		if e != nil {
 			failureClr.Println("\nUnable to upload profile file to SUBNET:", e.Error())
 			successClr.Printf("Profiling data are saved locally at '%s'\n", profileFile)
-			return
+		} else {
+			successClr.Println("uploaded successfully to SUBNET.")
 		}
-		successClr.Println("uploaded successfully to SUBNET.")
 	} else {
 		successClr.Printf("saved successfully at '%s'\n", profileFile)
 	}
+	if dir == "" {
+		return
+	}
+	console.Infof("Profile data unpacked in '%s'\n", dir)
+	if len(profiles) == 0 {
+		console.Infof("Run 'go tool pprof -http localhost:0 %s' to view the flamegraph of a profile.\n", filepath.Join(dir, "FILE.pprof"))
+		return
+	}
+	openProfiles(profiles)
 }
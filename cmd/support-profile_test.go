@@ -0,0 +1,70 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractProfileFile(t *testing.T) {
+	dir := t.TempDir()
+	zipFile := filepath.Join(dir, "profile.zip")
+	f, e := os.Create(zipFile)
+	if e != nil {
+		t.Fatal(e)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{
+		"profile-node1:9000-cpu.pprof",
+		"profile-node1:9000-cpuio.pprof",
+		"profile-node2:9000-cpu.pprof",
+		"../profile-node2:9000-mem.pprof",
+	} {
+		w, e := zw.Create(name)
+		if e != nil {
+			t.Fatal(e)
+		}
+		w.Write([]byte(name))
+	}
+	zw.Close()
+	f.Close()
+
+	out := filepath.Join(dir, "out")
+	files, err := extractProfileFile(zipFile, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 4 {
+		t.Fatalf("expected 4 files, got %v", files)
+	}
+	if _, e := os.Stat(filepath.Join(out, "profile-node2:9000-mem.pprof")); e != nil {
+		t.Fatalf("expected the file to be unpacked in the directory: %v", e)
+	}
+
+	want := []string{
+		filepath.Join(out, "profile-node1:9000-cpu.pprof"),
+		filepath.Join(out, "profile-node2:9000-cpu.pprof"),
+	}
+	if got := selectProfiles(files, "cpu"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
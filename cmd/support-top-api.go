@@ -19,11 +19,13 @@ package cmd
 
 import (
 	"context"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/minio/cli"
 	"github.com/minio/madmin-go/v2"
 	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/set"
 )
 
 var supportTopAPIFlags = []cli.Flag{
@@ -43,6 +45,20 @@ var supportTopAPIFlags = []cli.Flag{
 		Name:  "errors, e",
 		Usage: "summarize current API calls throwing only errors",
 	},
+	cli.StringFlag{
+		Name:  "by",
+		Usage: "group API calls by 'api', 'bucket', 'user' (access key) or 'ip' (client address)",
+		Value: "api",
+	},
+	cli.StringFlag{
+		Name:  "sort",
+		Usage: "sort by 'name', 'calls', 'rx', 'tx' or 'errors'",
+		Value: "name",
+	},
+	cli.IntFlag{
+		Name:  "top",
+		Usage: "display only the first N rows",
+	},
 }
 
 var supportTopAPICmd = cli.Command{
@@ -68,6 +84,15 @@ EXAMPLES:
 
    2. Display current in-progress all 's3.PutObject' API calls.
       {{.Prompt}} {{.HelpName}} --name s3.PutObject myminio/
+
+   3. Display the 10 access keys making the most API calls.
+      {{.Prompt}} {{.HelpName}} --by user --sort calls --top 10 myminio/
+
+   4. Display the buckets receiving the most data.
+      {{.Prompt}} {{.HelpName}} --by bucket --sort rx myminio/
+
+   5. Display the client addresses with the most failing calls.
+      {{.Prompt}} {{.HelpName}} --by ip --sort errors --top 5 myminio/
 `,
 }
 
@@ -76,6 +101,15 @@ func checkSupportTopAPISyntax(ctx *cli.Context) {
 	if len(ctx.Args()) == 0 || len(ctx.Args()) > 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
+	if !set.CreateStringSet(topAPIGroups...).Contains(ctx.String("by")) {
+		fatalIf(errInvalidArgument().Trace(ctx.String("by")), "Unable to group API calls, --by must be one of "+strings.Join(topAPIGroups, ", ")+".")
+	}
+	if !set.CreateStringSet(topAPISorts...).Contains(ctx.String("sort")) {
+		fatalIf(errInvalidArgument().Trace(ctx.String("sort")), "Unable to sort API calls, --sort must be one of "+strings.Join(topAPISorts, ", ")+".")
+	}
+	if ctx.Int("top") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("top")), "--top must not be negative.")
+	}
 }
 
 func mainSupportTopAPI(ctx *cli.Context) error {
@@ -103,7 +137,11 @@ func mainSupportTopAPI(ctx *cli.Context) error {
 	// Start listening on all trace activity.
 	traceCh := client.ServiceTrace(ctxt, opts)
 
-	p := tea.NewProgram(initTraceUI())
+	p := tea.NewProgram(initTraceUI(topAPIOpts{
+		groupBy: ctx.String("by"),
+		sortBy:  ctx.String("sort"),
+		top:     ctx.Int("top"),
+	}))
 	go func() {
 		for apiCallInfo := range traceCh {
 			if apiCallInfo.Err != nil {
@@ -18,9 +18,15 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
@@ -40,6 +46,28 @@ var supportTopLocksFlag = []cli.Flag{
 		Hidden: true,
 		Value:  10,
 	},
+	cli.BoolFlag{
+		Name:  "watch, w",
+		Usage: "refresh the locks in place until interrupted",
+	},
+	cli.DurationFlag{
+		Name:  "interval",
+		Usage: "refresh interval in watch mode",
+		Value: 5 * time.Second,
+	},
+	cli.DurationFlag{
+		Name:  "highlight",
+		Usage: "highlight the locks held for longer than the duration",
+		Value: time.Hour,
+	},
+	cli.BoolFlag{
+		Name:  "force-unlock",
+		Usage: "force unlock the locks held for longer than --highlight, after a confirmation",
+	},
+	cli.BoolFlag{
+		Name:  "yes, y",
+		Usage: "force unlock without a confirmation",
+	},
 }
 
 var supportTopLocksCmd = cli.Command{
@@ -53,7 +81,11 @@ var supportTopLocksCmd = cli.Command{
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} TARGET
+  {{.HelpName}} [FLAGS] TARGET[/BUCKET[/PREFIX]]
+
+  Only the locks of the bucket and prefix of TARGET are listed. Locks held for longer than
+  --highlight are highlighted, --force-unlock releases them once confirmed. Force unlocking
+  a lock which is still in use can corrupt the object, only use it for stuck locks.
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -61,49 +93,70 @@ FLAGS:
 EXAMPLES:
   1. Get a list of the 10 oldest locks on a MinIO cluster.
      {{.Prompt}} {{.HelpName}} myminio/
+
+  2. Watch the locks of the bucket 'mybucket', highlighting the locks held for more than 5 minutes.
+     {{.Prompt}} {{.HelpName}} --watch --highlight 5m myminio/mybucket
+
+  3. Force unlock the locks of 'mybucket/logs/' held for more than 2 hours.
+     {{.Prompt}} {{.HelpName}} --highlight 2h --force-unlock myminio/mybucket/logs/
 `,
 }
 
 // lockMessage struct to list lock information.
 type lockMessage struct {
-	Status string           `json:"status"`
-	Lock   madmin.LockEntry `json:"locks"`
+	Status    string           `json:"status"`
+	Lock      madmin.LockEntry `json:"locks"`
+	highlight time.Duration
 }
 
-func getLockDuration(duration time.Duration) (string, string) {
+func getLockDuration(duration time.Duration) string {
 	hours := int(duration.Hours())
 	minutes := int(duration.Minutes()) % 60
 	seconds := int(duration.Seconds()) % 60
 	if hours == 0 {
 		if minutes == 0 {
-			return "Lock", fmt.Sprint(seconds, " seconds")
+			return fmt.Sprint(seconds, " seconds")
 		}
-		return "Lock", fmt.Sprint(minutes, " minutes")
+		return fmt.Sprint(minutes, " minutes")
 	}
-	return "StaleLock", fmt.Sprint(hours, " hours")
+	return fmt.Sprint(hours, " hours")
 }
 
-// String colorized oldest locks message.
-func (u lockMessage) String() string {
+// elapsed returns for how long the lock has been held.
+func (u lockMessage) elapsed() time.Duration {
+	// elapsed can be zero with older MinIO versions,
+	// so this code is deprecated and can be removed later.
+	if u.Lock.Elapsed == 0 {
+		return time.Now().UTC().Sub(u.Lock.Timestamp)
+	}
+	return u.Lock.Elapsed
+}
+
+// highlighted reports whether the lock is held for longer than the threshold.
+func (u lockMessage) highlighted() bool {
+	return u.highlight > 0 && u.elapsed() >= u.highlight
+}
+
+func lockTable() PrettyTable {
 	const (
 		timeFieldMaxLen     = 20
 		resourceFieldMaxLen = -1
 		typeFieldMaxLen     = 6
 	)
-
-	elapsed := u.Lock.Elapsed
-	// elapsed can be zero with older MinIO versions,
-	// so this code is deprecated and can be removed later.
-	if elapsed == 0 {
-		elapsed = time.Now().UTC().Sub(u.Lock.Timestamp)
-	}
-
-	lockState, timeDiff := getLockDuration(elapsed)
-	return console.Colorize(lockState, newPrettyTable("  ",
+	return newPrettyTable("  ",
 		Field{"Time", timeFieldMaxLen},
 		Field{"Type", typeFieldMaxLen},
 		Field{"Resource", resourceFieldMaxLen},
-	).buildRow(timeDiff, u.Lock.Type, u.Lock.Resource))
+	)
+}
+
+// String colorized oldest locks message.
+func (u lockMessage) String() string {
+	lockState := "Lock"
+	if u.highlighted() {
+		lockState = "StaleLock"
+	}
+	return console.Colorize(lockState, lockTable().buildRow(getLockDuration(u.elapsed()), u.Lock.Type, u.Lock.Resource))
 }
 
 // JSON jsonified top oldest locks message.
@@ -119,29 +172,73 @@ func (u lockMessage) JSON() string {
 		ID         string    `json:"id"`         // UID to uniquely identify request of client.
 		// Represents quorum number of servers required to hold this lock, used to look for stale locks.
 		Quorum int `json:"quorum"`
+		// Highlighted is true when the lock is held for longer than --highlight.
+		Highlighted bool `json:"highlighted"`
 	}
 
 	le := lockEntry{
-		Timestamp:  u.Lock.Timestamp,
-		Elapsed:    u.Lock.Elapsed.Round(time.Second).String(),
-		Resource:   u.Lock.Resource,
-		Type:       u.Lock.Type,
-		Source:     u.Lock.Source,
-		ServerList: u.Lock.ServerList,
-		Owner:      u.Lock.Owner,
-		ID:         u.Lock.ID,
-		Quorum:     u.Lock.Quorum,
+		Timestamp:   u.Lock.Timestamp,
+		Elapsed:     u.Lock.Elapsed.Round(time.Second).String(),
+		Resource:    u.Lock.Resource,
+		Type:        u.Lock.Type,
+		Source:      u.Lock.Source,
+		ServerList:  u.Lock.ServerList,
+		Owner:       u.Lock.Owner,
+		ID:          u.Lock.ID,
+		Quorum:      u.Lock.Quorum,
+		Highlighted: u.highlighted(),
 	}
 	statusJSONBytes, e := json.MarshalIndent(le, "", " ")
 	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
 	return string(statusJSONBytes)
 }
 
+// forceUnlockMessage is the result of a force unlock.
+type forceUnlockMessage struct {
+	Status    string   `json:"status"`
+	Resources []string `json:"resources"`
+}
+
+func (u forceUnlockMessage) String() string {
+	if len(u.Resources) == 0 {
+		return console.Colorize("Lock", "No lock to force unlock.")
+	}
+	return console.Colorize("Headers", fmt.Sprintf("Force unlocked %d lock(s).", len(u.Resources)))
+}
+
+func (u forceUnlockMessage) JSON() string {
+	u.Status = "success"
+	statusJSONBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(statusJSONBytes)
+}
+
 // checkAdminTopLocksSyntax - validate all the passed arguments
 func checkSupportTopLocksSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) == 0 || len(ctx.Args()) > 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
+	if ctx.Bool("force-unlock") && ctx.Bool("watch") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--force-unlock cannot be used with --watch.")
+	}
+	if ctx.Bool("force-unlock") && ctx.Duration("highlight") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--force-unlock needs a positive --highlight duration.")
+	}
+}
+
+// filterLocks returns the locks of the resources under the prefix.
+func filterLocks(locks madmin.LockEntries, prefix string) madmin.LockEntries {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if prefix == "" {
+		return locks
+	}
+	filtered := make(madmin.LockEntries, 0, len(locks))
+	for _, lock := range locks {
+		if strings.HasPrefix(lock.Resource, prefix) {
+			filtered = append(filtered, lock)
+		}
+	}
+	return filtered
 }
 
 func mainSupportTopLocks(ctx *cli.Context) error {
@@ -149,46 +246,193 @@ func mainSupportTopLocks(ctx *cli.Context) error {
 	// Get the alias parameter from cli
 	args := ctx.Args()
 	aliasedURL := args.Get(0)
-	alias, _ := url2Alias(aliasedURL)
+	alias, prefix := url2Alias(aliasedURL)
 	validateClusterRegistered(alias, false)
 
 	// Create a new MinIO Admin Client
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 
-	// Call top locks API
-	entries, e := client.TopLocksWithOpts(globalContext, madmin.TopLockOpts{
-		Count: ctx.Int("count"),
-		Stale: ctx.Bool("stale"),
-	})
-	fatalIf(probe.NewError(e), "Unable to get server locks list.")
-
 	console.SetColor("StaleLock", color.New(color.FgRed, color.Bold))
 	console.SetColor("Lock", color.New(color.FgBlue, color.Bold))
 	console.SetColor("Headers", color.New(color.FgGreen, color.Bold))
 
+	highlight := ctx.Duration("highlight")
+	getLocks := func() (madmin.LockEntries, error) {
+		// Call top locks API
+		entries, e := client.TopLocksWithOpts(globalContext, madmin.TopLockOpts{
+			Count: ctx.Int("count"),
+			Stale: ctx.Bool("stale"),
+		})
+		return filterLocks(entries, prefix), e
+	}
+
+	if ctx.Bool("watch") {
+		watchLocks(getLocks, ctx.Duration("interval"), highlight)
+		return nil
+	}
+
+	entries, e := getLocks()
+	fatalIf(probe.NewError(e), "Unable to get server locks list.")
+
 	// Print
-	printLocks(entries)
+	printLocks(entries, highlight)
+
+	if ctx.Bool("force-unlock") {
+		forceUnlockLocks(client, entries, highlight, ctx.Bool("yes"))
+	}
 	return nil
 }
 
+// forceUnlockLocks releases the locks held for longer than the threshold once
+// confirmed.
+func forceUnlockLocks(client *madmin.AdminClient, locks madmin.LockEntries, highlight time.Duration, autoConfirm bool) {
+	var resources []string
+	for _, lock := range locks {
+		if (lockMessage{Lock: lock, highlight: highlight}).highlighted() {
+			resources = append(resources, lock.Resource)
+		}
+	}
+	if len(resources) == 0 {
+		printMsg(forceUnlockMessage{})
+		return
+	}
+
+	if !autoConfirm {
+		if !isTerminal() {
+			fatalIf(errDummy().Trace(resources...), "--force-unlock needs --yes when not run interactively.")
+		}
+		fmt.Printf("Force unlock %d lock(s) held for more than %s, please confirm [y/N]: ", len(resources), highlight)
+		answer, e := bufio.NewReader(os.Stdin).ReadString('\n')
+		fatalIf(probe.NewError(e), "Unable to parse user input.")
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("Force unlock aborted!")
+			return
+		}
+	}
+
+	e := client.ForceUnlock(globalContext, resources...)
+	fatalIf(probe.NewError(e).Trace(resources...), "Unable to force unlock the locks.")
+	printMsg(forceUnlockMessage{Resources: resources})
+}
+
 func printHeaders() {
-	timeFieldMaxLen := 20
-	resourceFieldMaxLen := -1
-	typeFieldMaxLen := 6
-	console.Println(console.Colorize("Headers", newPrettyTable("  ",
-		Field{"Time", timeFieldMaxLen},
-		Field{"Type", typeFieldMaxLen},
-		Field{"Resource", resourceFieldMaxLen},
-	).buildRow("Time", "Type", "Resource")))
+	console.Println(console.Colorize("Headers", lockTable().buildRow("Time", "Type", "Resource")))
 }
 
 // Prints oldest locks.
-func printLocks(locks madmin.LockEntries) {
+func printLocks(locks madmin.LockEntries, highlight time.Duration) {
 	if !globalJSON {
 		printHeaders()
 	}
 	for _, entry := range locks {
-		printMsg(lockMessage{Lock: entry})
+		printMsg(lockMessage{Lock: entry, highlight: highlight})
+	}
+}
+
+// watchLocks refreshes the locks every interval until interrupted. With
+// --json, every lock is printed on its own line at every refresh.
+func watchLocks(getLocks func() (madmin.LockEntries, error), interval, highlight time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if globalJSON {
+		for {
+			locks, e := getLocks()
+			fatalIf(probe.NewError(e), "Unable to get server locks list.")
+			printLocks(locks, highlight)
+			select {
+			case <-globalContext.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}
+
+	ui := tea.NewProgram(initTopLocksUI(highlight))
+	go func() {
+		for {
+			locks, e := getLocks()
+			if e != nil {
+				ui.Send(e)
+				return
+			}
+			ui.Send(locks)
+			select {
+			case <-globalContext.Done():
+				ui.Quit()
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	m, e := ui.Run()
+	fatalIf(probe.NewError(e), "Unable to get server locks list.")
+	if e = m.(*topLocksUI).err; e != nil {
+		fatalIf(probe.NewError(e), "Unable to get server locks list.")
+	}
+}
+
+func initTopLocksUI(highlight time.Duration) *topLocksUI {
+	s := spinner.New()
+	s.Spinner = spinner.Points
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return &topLocksUI{spinner: s, highlight: highlight}
+}
+
+type topLocksUI struct {
+	spinner   spinner.Model
+	highlight time.Duration
+	locks     madmin.LockEntries
+	updated   time.Time
+	err       error
+	quitting  bool
+}
+
+func (m *topLocksUI) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m *topLocksUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			m.quitting = true
+			return m, tea.Quit
+		default:
+			return m, nil
+		}
+	case madmin.LockEntries:
+		m.locks = msg
+		m.updated = time.Now()
+		return m, nil
+	case error:
+		m.err = msg
+		m.quitting = true
+		return m, tea.Quit
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	default:
+		return m, nil
+	}
+}
+
+func (m *topLocksUI) View() string {
+	var s strings.Builder
+	if !m.quitting {
+		s.WriteString(m.spinner.View())
+	}
+	if m.updated.IsZero() {
+		s.WriteString("\n")
+		return s.String()
+	}
+	s.WriteString(" Updated " + m.updated.Format("15:04:05") + "\n")
+	s.WriteString(console.Colorize("Headers", lockTable().buildRow("Time", "Type", "Resource")) + "\n")
+	for _, lock := range m.locks {
+		s.WriteString(lockMessage{Lock: lock, highlight: m.highlight}.String() + "\n")
 	}
+	return s.String()
 }
@@ -0,0 +1,50 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func TestFilterLocks(t *testing.T) {
+	locks := madmin.LockEntries{
+		{Resource: "bucket/logs/a", Elapsed: 2 * time.Hour},
+		{Resource: "bucket/data/b", Elapsed: time.Minute},
+		{Resource: "bucket2/logs/c", Elapsed: time.Hour},
+	}
+	if got := filterLocks(locks, "/"); len(got) != 3 {
+		t.Fatalf("expected all the locks, got %v", got)
+	}
+	got := filterLocks(locks, "/bucket/logs/")
+	if len(got) != 1 || got[0].Resource != "bucket/logs/a" {
+		t.Fatalf("expected the locks of bucket/logs/, got %v", got)
+	}
+
+	var highlighted []string
+	for _, lock := range locks {
+		if (lockMessage{Lock: lock, highlight: time.Hour}).highlighted() {
+			highlighted = append(highlighted, lock.Resource)
+		}
+	}
+	if len(highlighted) != 2 || highlighted[0] != "bucket/logs/a" || highlighted[1] != "bucket2/logs/c" {
+		t.Fatalf("unexpected highlighted locks %v", highlighted)
+	}
+}
@@ -0,0 +1,407 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/pkg/console"
+)
+
+const (
+	// tailChunk is the size of the first read from the end of the object,
+	// doubled until it holds the lines to print.
+	tailChunk = 64 * 1024
+	// tailKeep is the number of the last bytes read which are compared
+	// with the object, to tell an append from a replacement.
+	tailKeep = 64
+)
+
+var tailFlags = []cli.Flag{
+	cli.Int64Flag{
+		Name:  "n,lines",
+		Usage: "print the last 'n' lines",
+		Value: 10,
+	},
+	cli.BoolFlag{
+		Name:  "follow, f",
+		Usage: "print the data appended to the object, and the versions replacing it, as they come",
+	},
+	cli.DurationFlag{
+		Name:  "interval",
+		Usage: "interval between the checks of the object with --follow",
+		Value: 2 * time.Second,
+	},
+}
+
+var tailCmd = cli.Command{
+	Name:         "tail",
+	Usage:        "display last 'n' lines of an object, optionally following it",
+	Action:       mainTail,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(tailFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+  With --follow the object is checked every --interval, and as soon as the
+  server notifies a change when it sends bucket notifications. The bytes
+  appended to the object, e.g. with 'mc append', are printed as they come.
+  When the object is replaced by another content, a notice is printed on
+  the standard error and the new version is printed from its start.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+
+EXAMPLES:
+  1. Display the last 10 lines of a log object.
+     {{.Prompt}} {{.HelpName}} myminio/logs/app.log
+
+  2. Follow a log object which is appended to, printing its last 100 lines first.
+     {{.Prompt}} {{.HelpName}} -n 100 --follow myminio/logs/app.log
+
+  3. Follow a status object replaced every minute, checking it every 30 seconds.
+     {{.Prompt}} {{.HelpName}} -n 0 --follow --interval 30s s3/reports/status.json
+`,
+}
+
+// tailFollower prints the end of an object and what changes after.
+type tailFollower struct {
+	clnt    Client
+	sse     encrypt.ServerSide
+	nlines  int64
+	out     io.Writer
+	notices io.Writer
+
+	// st is the version printed, nil once the object is removed. offset is
+	// the number of its bytes consumed and last holds the last of them.
+	st     *ClientContent
+	offset int64
+	last   []byte
+}
+
+// Write prints data of the object followed.
+func (t *tailFollower) Write(p []byte) (int, error) {
+	n, e := t.out.Write(p)
+	t.offset += int64(n)
+	t.keep(p[:n])
+	return n, e
+}
+
+// keep remembers the last bytes consumed.
+func (t *tailFollower) keep(p []byte) {
+	t.last = append(t.last, p...)
+	if len(t.last) > tailKeep {
+		t.last = append([]byte{}, t.last[len(t.last)-tailKeep:]...)
+	}
+}
+
+// notice prints a change of the object on the notices writer.
+func (t *tailFollower) notice(format string, args ...interface{}) {
+	msg := fmt.Sprintf("==> `%s` %s <==", t.clnt.GetURL(), fmt.Sprintf(format, args...))
+	fmt.Fprintln(t.notices, console.Colorize("TailNotice", msg))
+}
+
+// get returns a reader of a version from offset to its end.
+func (t *tailFollower) get(ctx context.Context, st *ClientContent, offset int64) (io.ReadCloser, *probe.Error) {
+	reader, err := t.clnt.Get(ctx, GetOptions{SSE: t.sse, VersionID: st.VersionID, RangeStart: offset})
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(reader, st.Size-offset), reader}, nil
+}
+
+// lastLinesIndex returns the index of the last n lines of buf, -1 when
+// buf holds less than n line breaks before its last line.
+func lastLinesIndex(buf []byte, n int64) int {
+	end := len(buf)
+	if end > 0 && buf[end-1] == '\n' {
+		end--
+	}
+	for i := end - 1; i >= 0; i-- {
+		if buf[i] == '\n' {
+			n--
+			if n == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// printLast prints the last lines of a version.
+func (t *tailFollower) printLast(ctx context.Context, st *ClientContent) *probe.Error {
+	t.st, t.offset, t.last = st, 0, nil
+	nlines := t.nlines
+	// Negative number of lines means default number of lines.
+	if nlines < 0 {
+		nlines = 10
+	}
+	if st.Size == 0 {
+		return nil
+	}
+	if nlines == 0 {
+		// Nothing to print, only the end to compare with the next versions.
+		start := st.Size - tailKeep
+		if start < 0 {
+			start = 0
+		}
+		buf, err := t.read(ctx, st, start)
+		if err != nil {
+			return err
+		}
+		t.offset = st.Size
+		t.keep(buf)
+		return nil
+	}
+	for chunk := int64(tailChunk); ; chunk *= 2 {
+		start := st.Size - chunk
+		if start < 0 {
+			start = 0
+		}
+		buf, err := t.read(ctx, st, start)
+		if err != nil {
+			return err
+		}
+		i := lastLinesIndex(buf, nlines)
+		if i < 0 && start > 0 {
+			continue
+		}
+		if i < 0 {
+			i = 0
+		}
+		t.offset = start + int64(i)
+		_, e := t.Write(buf[i:])
+		return probe.NewError(e)
+	}
+}
+
+func (t *tailFollower) read(ctx context.Context, st *ClientContent, start int64) ([]byte, *probe.Error) {
+	reader, err := t.get(ctx, st, start)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	buf, e := io.ReadAll(reader)
+	return buf, probe.NewError(e)
+}
+
+// printAppended prints the bytes appended to the object, it returns false
+// when the object was replaced instead.
+func (t *tailFollower) printAppended(ctx context.Context, st *ClientContent) (bool, *probe.Error) {
+	if st.Size < t.offset {
+		return false, nil
+	}
+	start := t.offset - int64(len(t.last))
+	reader, err := t.get(ctx, st, start)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+	prefix := make([]byte, len(t.last))
+	if _, e := io.ReadFull(reader, prefix); e != nil {
+		return false, probe.NewError(e)
+	}
+	if !bytes.Equal(prefix, t.last) {
+		return false, nil
+	}
+	t.st = st
+	_, e := io.Copy(t, reader)
+	return true, probe.NewError(e)
+}
+
+// printAll prints a version from its start.
+func (t *tailFollower) printAll(ctx context.Context, st *ClientContent) *probe.Error {
+	t.st, t.offset, t.last = st, 0, nil
+	if st.Size == 0 {
+		return nil
+	}
+	reader, err := t.get(ctx, st, 0)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, e := io.Copy(t, reader)
+	return probe.NewError(e)
+}
+
+// poll checks the object and prints what changed since the last check.
+func (t *tailFollower) poll(ctx context.Context) *probe.Error {
+	st, err := t.clnt.Stat(ctx, StatOptions{sse: t.sse})
+	if err != nil {
+		var pathNotFound PathNotFound
+		if !errors.As(err.ToGoError(), &ObjectMissing{}) && !errors.As(err.ToGoError(), &pathNotFound) {
+			return err
+		}
+		if t.st != nil {
+			t.notice("was removed")
+			t.st, t.offset, t.last = nil, 0, nil
+		}
+		return nil
+	}
+
+	switch {
+	case t.st == nil:
+		t.notice("was created")
+		return t.printAll(ctx, st)
+	case st.Size == t.offset && st.ETag == t.st.ETag && st.VersionID == t.st.VersionID && st.Time.Equal(t.st.Time):
+		return nil
+	}
+
+	appended, err := t.printAppended(ctx, st)
+	if err != nil || appended {
+		return err
+	}
+	if st.VersionID != "" {
+		t.notice("was replaced by version %s", st.VersionID)
+	} else {
+		t.notice("was replaced")
+	}
+	return t.printAll(ctx, st)
+}
+
+// tailWatch returns a channel receiving the notifications of changes of
+// the object, when the server sends them.
+func tailWatch(ctx context.Context, clnt Client) <-chan struct{} {
+	changed := make(chan struct{}, 1)
+	wo, err := clnt.Watch(ctx, WatchOptions{Events: []string{"put", "delete"}})
+	if err != nil {
+		return changed
+	}
+	go func() {
+		defer close(wo.DoneChan)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-wo.Events():
+				if !ok {
+					return
+				}
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			case _, ok := <-wo.Errors():
+				// Without notifications the object is only polled.
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return changed
+}
+
+// isStdoutClosed tells whether the output was closed by the user.
+func isStdoutClosed(err *probe.Error) bool {
+	return err != nil && errors.Is(err.ToGoError(), syscall.EPIPE)
+}
+
+// checkTailSyntax - validate all the passed arguments
+func checkTailSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if ctx.Duration("interval") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--interval must be positive.")
+	}
+}
+
+// mainTail is the main entry point for tail command.
+func mainTail(cliCtx *cli.Context) error {
+	ctx, cancelTail := context.WithCancel(globalContext)
+	defer cancelTail()
+
+	checkTailSyntax(cliCtx)
+	console.SetColor("TailNotice", color.New(color.FgYellow))
+
+	// Parse encryption keys per command.
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	targetURL := cliCtx.Args().Get(0)
+	alias, _, _ := mustExpandAlias(targetURL)
+	clnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+
+	t := &tailFollower{
+		clnt:    clnt,
+		sse:     getSSE(targetURL, encKeyDB[alias]),
+		nlines:  cliCtx.Int64("lines"),
+		out:     os.Stdout,
+		notices: os.Stderr,
+	}
+	// In case of a user showing the object content in a terminal,
+	// avoid printing control and other bad characters to avoid
+	// terminal session corruption
+	if isTerminal() {
+		t.out = newPrettyStdout(os.Stdout)
+	}
+
+	st, err := clnt.Stat(ctx, StatOptions{sse: t.sse})
+	fatalIf(err.Trace(targetURL), "Unable to stat `"+targetURL+"`.")
+	if st.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(targetURL), "The target must be an object.")
+	}
+	if err = t.printLast(ctx, st); isStdoutClosed(err) {
+		return nil
+	}
+	fatalIf(err.Trace(targetURL), "Unable to read from `"+targetURL+"`.")
+	if !cliCtx.Bool("follow") {
+		return nil
+	}
+
+	changed := tailWatch(ctx, clnt)
+	ticker := time.NewTicker(cliCtx.Duration("interval"))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-changed:
+		}
+		err = t.poll(ctx)
+		if isStdoutClosed(err) {
+			return nil
+		}
+		// Like tail, keep following through the transient errors.
+		errorIf(err.Trace(targetURL), "Unable to follow `"+targetURL+"`.")
+	}
+}
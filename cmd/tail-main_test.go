@@ -0,0 +1,106 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLastLinesIndex(t *testing.T) {
+	testCases := []struct {
+		buf   string
+		n     int64
+		index int
+	}{
+		{"a\nb\nc\n", 2, 2},
+		{"a\nb\nc", 2, 2},
+		{"a\nb\nc\n", 3, -1},
+		{"a\nb\nc\n", 1, 4},
+		{"abc", 1, -1},
+		{"", 1, -1},
+	}
+	for i, testCase := range testCases {
+		if index := lastLinesIndex([]byte(testCase.buf), testCase.n); index != testCase.index {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.index, index)
+		}
+	}
+}
+
+func TestTailFollower(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	write := func(data string) {
+		if e := os.WriteFile(path, []byte(data), 0o600); e != nil {
+			t.Fatal(e)
+		}
+	}
+	write("a\nb\nc\n")
+
+	clnt, err := fsNew(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out, notices bytes.Buffer
+	tf := &tailFollower{clnt: clnt, nlines: 2, out: &out, notices: &notices}
+	ctx := context.Background()
+	st, err := clnt.Stat(ctx, StatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tf.printLast(ctx, st); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		data   string // "" removes the object
+		out    string
+		notice string
+	}{
+		{data: "a\nb\nc\nd\n", out: "d\n"},
+		{data: "a\nb\nc\nd\n"},
+		{data: "x\ny\n", out: "x\ny\n", notice: "was replaced"},
+		{data: "x\ny\nz\n", out: "z\n"},
+		{notice: "was removed"},
+		{data: "new\n", out: "new\n", notice: "was created"},
+	}
+	if out.String() != "b\nc\n" {
+		t.Fatalf("expected the last 2 lines, got %q", out.String())
+	}
+	for i, step := range steps {
+		out.Reset()
+		notices.Reset()
+		if step.data == "" {
+			os.Remove(path)
+		} else {
+			write(step.data)
+		}
+		if err = tf.poll(ctx); err != nil {
+			t.Fatalf("Step %d: %v", i+1, err)
+		}
+		if out.String() != step.out {
+			t.Errorf("Step %d: expected %q, got %q", i+1, step.out, out.String())
+		}
+		if !strings.Contains(notices.String(), step.notice) || (step.notice == "") != (notices.Len() == 0) {
+			t.Errorf("Step %d: expected the notice %q, got %q", i+1, step.notice, notices.String())
+		}
+	}
+}
@@ -82,8 +82,8 @@ func promptTrustSelfSignedCert(ctx context.Context, endpoint, alias string) (*x5
 		return nil, probe.NewError(e)
 	}
 
-	// no need to probe certs for http endpoints.
-	if req.URL.Scheme == "http" {
+	// no need to probe certs for http and sftp endpoints.
+	if req.URL.Scheme != "https" {
 		return nil, nil
 	}
 
@@ -19,6 +19,9 @@ package cmd
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -72,12 +75,76 @@ func (s *topAPIStats) loadAPIErrors() uint64 {
 	return atomic.LoadUint64(&s.TotalErrors)
 }
 
+// topAPIOpts selects how the API calls are summarized.
+type topAPIOpts struct {
+	groupBy string // one of topAPIGroups
+	sortBy  string // one of topAPISorts
+	top     int    // maximum number of rows, 0 for all
+}
+
+// Keys the API calls can be grouped by, the first one is the default.
+var topAPIGroups = []string{"api", "bucket", "user", "ip"}
+
+// Columns the summary can be sorted by, the first one is the default.
+var topAPISorts = []string{"name", "calls", "rx", "tx", "errors"}
+
+var traceCredentialRx = regexp.MustCompile(`Credential=([^/,\s]+)/`)
+
+// traceAccessKey returns the access key which signed a traced request.
+func traceAccessKey(req madmin.TraceRequestInfo) string {
+	auth := req.Headers.Get("Authorization")
+	if m := traceCredentialRx.FindStringSubmatch(auth); m != nil {
+		return m[1]
+	}
+	if strings.HasPrefix(auth, "AWS ") {
+		accessKey, _, _ := strings.Cut(strings.TrimPrefix(auth, "AWS "), ":")
+		return accessKey
+	}
+	// Presigned requests carry the credentials in the query.
+	if query, e := url.ParseQuery(req.RawQuery); e == nil {
+		if credential := query.Get("X-Amz-Credential"); credential != "" {
+			accessKey, _, _ := strings.Cut(credential, "/")
+			return accessKey
+		}
+		if accessKey := query.Get("AWSAccessKeyId"); accessKey != "" {
+			return accessKey
+		}
+	}
+	return "anonymous"
+}
+
+// topAPIGroupKey returns the row of the summary a traced call counts in.
+func topAPIGroupKey(groupBy string, t madmin.TraceInfo) string {
+	switch groupBy {
+	case "bucket":
+		if bucket, _, _ := strings.Cut(strings.TrimPrefix(t.Path, "/"), "/"); bucket != "" {
+			return bucket
+		}
+		return "-"
+	case "user":
+		if t.HTTP == nil {
+			return "-"
+		}
+		return traceAccessKey(t.HTTP.ReqInfo)
+	case "ip":
+		if t.HTTP == nil || t.HTTP.ReqInfo.Client == "" {
+			return "-"
+		}
+		if host, _, e := net.SplitHostPort(t.HTTP.ReqInfo.Client); e == nil {
+			return host
+		}
+		return t.HTTP.ReqInfo.Client
+	}
+	return t.FuncName
+}
+
 type traceUI struct {
 	spinner     spinner.Model
 	quitting    bool
 	startTime   time.Time
 	result      topAPIResult
 	lastResult  topAPIResult
+	opts        topAPIOpts
 	apiStatsMap map[string]*topAPIStats
 }
 
@@ -86,12 +153,13 @@ type topAPIResult struct {
 	apiCallInfo madmin.ServiceTraceInfo
 }
 
-func initTraceUI() *traceUI {
+func initTraceUI(opts topAPIOpts) *traceUI {
 	s := spinner.New()
 	s.Spinner = spinner.Points
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 	return &traceUI{
 		spinner:     s,
+		opts:        opts,
 		apiStatsMap: make(map[string]*topAPIStats),
 	}
 }
@@ -115,6 +183,7 @@ func (m *traceUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.result.apiCallInfo.Trace.FuncName != "" {
 			m.lastResult = m.result
 		}
+		m.addResult(msg.apiCallInfo.Trace)
 		if msg.final {
 			m.quitting = true
 			return m, tea.Quit
@@ -127,6 +196,63 @@ func (m *traceUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// addResult counts a traced call in the summary.
+func (m *traceUI) addResult(t madmin.TraceInfo) {
+	if m.startTime.IsZero() && !t.Time.IsZero() {
+		m.startTime = t.Time
+	}
+	if t.FuncName == "" || t.FuncName == "errorResponseHandler" {
+		return
+	}
+	key := topAPIGroupKey(m.opts.groupBy, t)
+	traceSt, ok := m.apiStatsMap[key]
+	if !ok {
+		traceSt = &topAPIStats{}
+		m.apiStatsMap[key] = traceSt
+	}
+	traceSt.addAPICall(1)
+	if t.HTTP != nil {
+		traceSt.addAPIBytesRX(t.HTTP.CallStats.InputBytes)
+		traceSt.addAPIBytesTX(t.HTTP.CallStats.OutputBytes)
+		if t.HTTP.RespInfo.StatusCode >= 499 {
+			traceSt.addAPIErrors(1)
+		}
+	}
+}
+
+// sortedStats returns the rows of the summary in display order.
+func (m *traceUI) sortedStats() []string {
+	keys := make([]string, 0, len(m.apiStatsMap))
+	for k := range m.apiStatsMap {
+		keys = append(keys, k)
+	}
+	value := func(k string) uint64 {
+		stats := m.apiStatsMap[k]
+		switch m.opts.sortBy {
+		case "calls":
+			return stats.loadAPICall()
+		case "rx":
+			return stats.loadAPIBytesRX()
+		case "tx":
+			return stats.loadAPIBytesTX()
+		case "errors":
+			return stats.loadAPIErrors()
+		}
+		return 0
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		// Numeric columns are sorted in descending order.
+		if vi, vj := value(keys[i]), value(keys[j]); vi != vj {
+			return vi > vj
+		}
+		return keys[i] < keys[j]
+	})
+	if m.opts.top > 0 && len(keys) > m.opts.top {
+		keys = keys[:m.opts.top]
+	}
+	return keys
+}
+
 func (m *traceUI) View() string {
 	var s strings.Builder
 	s.WriteString("\n")
@@ -145,30 +271,15 @@ func (m *traceUI) View() string {
 	table.SetTablePadding("\t") // pad with tabs
 	table.SetNoWhiteSpace(true)
 
-	res := m.result.apiCallInfo
-	if m.startTime.IsZero() && !res.Trace.Time.IsZero() {
-		m.startTime = res.Trace.Time
-	}
-	if res.Trace.FuncName != "" && res.Trace.FuncName != "errorResponseHandler" {
-		traceSt, ok := m.apiStatsMap[res.Trace.FuncName]
-		if !ok {
-			traceSt = &topAPIStats{}
-		}
-		traceSt.addAPICall(1)
-		if res.Trace.HTTP != nil {
-			traceSt.addAPIBytesRX(res.Trace.HTTP.CallStats.InputBytes)
-			traceSt.addAPIBytesTX(res.Trace.HTTP.CallStats.OutputBytes)
-		}
-		if res.Trace.HTTP.RespInfo.StatusCode >= 499 {
-			traceSt.addAPIErrors(1)
-		}
-		m.apiStatsMap[res.Trace.FuncName] = traceSt
+	groupHeader := map[string]string{"api": "API", "bucket": "BUCKET", "user": "ACCESS KEY", "ip": "CLIENT IP"}[m.opts.groupBy]
+	if groupHeader == "" {
+		groupHeader = "API"
 	}
-
-	table.SetHeader([]string{"API", "RX", "TX", "CALLS", "ERRORS"})
+	table.SetHeader([]string{groupHeader, "RX", "TX", "CALLS", "ERRORS"})
 	data := make([][]string, 0, len(m.apiStatsMap))
 
-	for k, stats := range m.apiStatsMap {
+	for _, k := range m.sortedStats() {
+		stats := m.apiStatsMap[k]
 		data = append(data, []string{
 			k,
 			whiteStyle.Render(humanize.IBytes(stats.loadAPIBytesRX())),
@@ -177,10 +288,6 @@ func (m *traceUI) View() string {
 			whiteStyle.Render(fmt.Sprintf("%d", stats.loadAPIErrors())),
 		})
 	}
-	sort.Slice(data, func(i, j int) bool {
-		return data[i][0] < data[j][0]
-	})
-
 	table.AppendBulk(data)
 	table.Render()
 
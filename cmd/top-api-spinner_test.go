@@ -0,0 +1,110 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/minio/madmin-go/v2"
+)
+
+func testAPITrace(funcName, path, auth, rawQuery, client string, status int, rx, tx int) madmin.TraceInfo {
+	headers := http.Header{}
+	if auth != "" {
+		headers.Set("Authorization", auth)
+	}
+	return madmin.TraceInfo{
+		FuncName: funcName,
+		Path:     path,
+		HTTP: &madmin.TraceHTTPStats{
+			ReqInfo:   madmin.TraceRequestInfo{Headers: headers, RawQuery: rawQuery, Client: client},
+			RespInfo:  madmin.TraceResponseInfo{StatusCode: status},
+			CallStats: madmin.TraceCallStats{InputBytes: rx, OutputBytes: tx},
+		},
+	}
+}
+
+func TestTopAPIGroupKey(t *testing.T) {
+	sigV4 := "AWS4-HMAC-SHA256 Credential=AKIAV4/20231015/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=abc"
+	testCases := []struct {
+		groupBy string
+		trace   madmin.TraceInfo
+		key     string
+	}{
+		{"api", testAPITrace("s3.GetObject", "/bucket/object", "", "", "", 200, 0, 0), "s3.GetObject"},
+		{"bucket", testAPITrace("s3.GetObject", "/bucket/dir/object", "", "", "", 200, 0, 0), "bucket"},
+		{"bucket", testAPITrace("s3.ListBuckets", "/", "", "", "", 200, 0, 0), "-"},
+		{"user", testAPITrace("s3.GetObject", "/bucket/object", sigV4, "", "", 200, 0, 0), "AKIAV4"},
+		{"user", testAPITrace("s3.GetObject", "/bucket/object", "AWS AKIAV2:signature", "", "", 200, 0, 0), "AKIAV2"},
+		{"user", testAPITrace("s3.GetObject", "/bucket/object", "", "X-Amz-Credential=AKIAPRE%2F20231015%2Fus-east-1%2Fs3%2Faws4_request", "", 200, 0, 0), "AKIAPRE"},
+		{"user", testAPITrace("s3.GetObject", "/bucket/object", "", "", "", 200, 0, 0), "anonymous"},
+		{"user", madmin.TraceInfo{FuncName: "s3.GetObject"}, "-"},
+		{"ip", testAPITrace("s3.GetObject", "/bucket/object", "", "", "10.0.0.1:51000", 200, 0, 0), "10.0.0.1"},
+		{"ip", testAPITrace("s3.GetObject", "/bucket/object", "", "", "[::1]:51000", 200, 0, 0), "::1"},
+		{"ip", testAPITrace("s3.GetObject", "/bucket/object", "", "", "", 200, 0, 0), "-"},
+	}
+	for i, testCase := range testCases {
+		if key := topAPIGroupKey(testCase.groupBy, testCase.trace); key != testCase.key {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.key, key)
+		}
+	}
+}
+
+func TestTopAPISortedStats(t *testing.T) {
+	traces := []madmin.TraceInfo{
+		testAPITrace("s3.GetObject", "/b/o", "", "", "", 200, 0, 100),
+		testAPITrace("s3.GetObject", "/b/o", "", "", "", 503, 0, 10),
+		testAPITrace("s3.PutObject", "/b/o", "", "", "", 200, 500, 0),
+		testAPITrace("s3.ListObjectsV2", "/b", "", "", "", 200, 0, 1),
+		testAPITrace("s3.ListObjectsV2", "/b", "", "", "", 200, 0, 1),
+		testAPITrace("s3.ListObjectsV2", "/b", "", "", "", 200, 0, 1),
+		// Not counted.
+		testAPITrace("errorResponseHandler", "/b", "", "", "", 400, 0, 0),
+	}
+	testCases := []struct {
+		opts topAPIOpts
+		keys []string
+	}{
+		{topAPIOpts{groupBy: "api", sortBy: "name"}, []string{"s3.GetObject", "s3.ListObjectsV2", "s3.PutObject"}},
+		{topAPIOpts{groupBy: "api", sortBy: "calls"}, []string{"s3.ListObjectsV2", "s3.GetObject", "s3.PutObject"}},
+		{topAPIOpts{groupBy: "api", sortBy: "rx"}, []string{"s3.PutObject", "s3.GetObject", "s3.ListObjectsV2"}},
+		{topAPIOpts{groupBy: "api", sortBy: "tx", top: 1}, []string{"s3.GetObject"}},
+		{topAPIOpts{groupBy: "api", sortBy: "errors", top: 2}, []string{"s3.GetObject", "s3.ListObjectsV2"}},
+		{topAPIOpts{groupBy: "bucket", sortBy: "name"}, []string{"b"}},
+	}
+	for i, testCase := range testCases {
+		m := initTraceUI(testCase.opts)
+		for _, trace := range traces {
+			m.addResult(trace)
+		}
+		if keys := m.sortedStats(); !reflect.DeepEqual(keys, testCase.keys) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.keys, keys)
+		}
+	}
+
+	m := initTraceUI(topAPIOpts{groupBy: "api"})
+	for _, trace := range traces {
+		m.addResult(trace)
+	}
+	stats := m.apiStatsMap["s3.GetObject"]
+	if stats.loadAPICall() != 2 || stats.loadAPIBytesTX() != 110 || stats.loadAPIErrors() != 1 {
+		t.Errorf("unexpected stats %+v", *stats)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Built-in transforms of --transform, any other value is a shell command.
+const (
+	transformGzip   = "gzip"
+	transformGunzip = "gunzip"
+)
+
+// transformSourceKey records the source of a transformed object, mirror
+// compares it since the size of the object differs from the source.
+const transformSourceKey = "X-Amz-Meta-Mc-Transform-Source"
+
+// transformSourceID identifies the content of a source, its ETag or for
+// files without ETag their size and modification time.
+func transformSourceID(source *ClientContent) string {
+	if source.ETag != "" {
+		return source.ETag
+	}
+	return fmt.Sprintf("%d-%d", source.Size, source.Time.UnixNano())
+}
+
+// transformedUpToDate tells if the target holds the transform of the
+// current source, from the source recorded in the metadata of objects or
+// from the modification time of files.
+func transformedUpToDate(ctx context.Context, targetAlias string, source, target *ClientContent) bool {
+	if target.URL.Type == fileSystem {
+		return target.Time.After(source.Time)
+	}
+	clnt, err := newClientFromAlias(targetAlias, target.URL.String())
+	if err != nil {
+		return false
+	}
+	st, err := clnt.Stat(ctx, StatOptions{})
+	if err != nil {
+		return false
+	}
+	return st.Metadata[transformSourceKey] == transformSourceID(source)
+}
+
+// newTransformReader pipes the reader through the transform of --transform,
+// 'gzip', 'gunzip' or a shell command reading the object on its standard
+// input and writing the result on its standard output.
+func newTransformReader(ctx context.Context, reader io.Reader, transform string) (io.ReadCloser, error) {
+	switch transform {
+	case transformGzip:
+		pr, pw := io.Pipe()
+		go func() {
+			w := gzip.NewWriter(pw)
+			_, e := io.Copy(w, reader)
+			if e == nil {
+				e = w.Close()
+			}
+			pw.CloseWithError(e)
+		}()
+		return pr, nil
+	case transformGunzip:
+		r, e := gzip.NewReader(reader)
+		if e != nil {
+			return nil, fmt.Errorf("gunzip: %w", e)
+		}
+		return r, nil
+	}
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	p := &transformProcess{cmd: exec.CommandContext(ctx, shell, flag, transform), name: transform}
+	p.cmd.Stdin = reader
+	p.cmd.Stderr = &p.stderr
+	var e error
+	if p.stdout, e = p.cmd.StdoutPipe(); e != nil {
+		return nil, e
+	}
+	if e = p.cmd.Start(); e != nil {
+		return nil, e
+	}
+	return p, nil
+}
+
+// transformProcess reads the output of a transform command, its exit
+// status is returned at the end of the output.
+type transformProcess struct {
+	cmd    *exec.Cmd
+	name   string
+	stdout io.ReadCloser
+	stderr bytes.Buffer
+	done   bool
+	err    error
+}
+
+// wait returns the error of the command once it exits.
+func (p *transformProcess) wait() error {
+	if !p.done {
+		p.done = true
+		if e := p.cmd.Wait(); e != nil {
+			p.err = fmt.Errorf("%s: %v: %s", p.name, e, strings.TrimSpace(p.stderr.String()))
+		}
+	}
+	return p.err
+}
+
+func (p *transformProcess) Read(b []byte) (int, error) {
+	n, e := p.stdout.Read(b)
+	if e == io.EOF {
+		if we := p.wait(); we != nil {
+			return n, we
+		}
+	}
+	return n, e
+}
+
+// Close discards the rest of the output and waits for the command.
+func (p *transformProcess) Close() error {
+	if !p.done {
+		io.Copy(io.Discard, p.stdout)
+	}
+	return p.wait()
+}
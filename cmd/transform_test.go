@@ -0,0 +1,109 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransformReader(t *testing.T) {
+	transform := func(input, transform string) (string, error) {
+		r, e := newTransformReader(context.Background(), strings.NewReader(input), transform)
+		if e != nil {
+			return "", e
+		}
+		defer r.Close()
+		data, e := io.ReadAll(r)
+		return string(data), e
+	}
+
+	gzipped, e := transform("hello", transformGzip)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if got, e := transform(gzipped, transformGunzip); e != nil || got != "hello" {
+		t.Fatalf("expected the gzip transform to be reversed, got %q, %v", got, e)
+	}
+	if _, e = transform("hello", transformGunzip); e == nil {
+		t.Error("expected gunzip to fail on plain data")
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if got, e := transform("hello", "tr a-z A-Z"); e != nil || got != "HELLO" {
+		t.Errorf("unexpected output of the command %q, %v", got, e)
+	}
+	if _, e = transform("hello", "cat >/dev/null; exit 3"); e == nil {
+		t.Error("expected the exit status of the command to be returned")
+	}
+}
+
+func TestTransformedUpToDate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`))
+			return
+		}
+		w.Header().Set("Last-Modified", time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat))
+		w.Header().Set("ETag", `"gzipped"`)
+		w.Header().Set("Content-Length", "10")
+		w.Header().Set(transformSourceKey, "etag-v1")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "http://", "http://minio:minio123@", 1))
+
+	target := &ClientContent{URL: *newClientURL(server.URL + "/bucket/logs/a.log")}
+	modTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		source   *ClientContent
+		target   *ClientContent
+		upToDate bool
+	}{
+		{&ClientContent{ETag: "etag-v1", Size: 20}, target, true},
+		{&ClientContent{ETag: "etag-v2", Size: 20}, target, false},
+		// Files without ETag are identified by their size and time.
+		{&ClientContent{Size: 20, Time: modTime}, target, false},
+		{
+			&ClientContent{Size: 20, Time: modTime},
+			&ClientContent{URL: *newClientURL("/tmp/logs/a.log"), Time: modTime.Add(time.Hour)},
+			true,
+		},
+		{
+			&ClientContent{Size: 20, Time: modTime.Add(2 * time.Hour)},
+			&ClientContent{URL: *newClientURL("/tmp/logs/a.log"), Time: modTime.Add(time.Hour)},
+			false,
+		},
+	}
+	for i, testCase := range testCases {
+		if upToDate := transformedUpToDate(context.Background(), "myminio", testCase.source, testCase.target); upToDate != testCase.upToDate {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.upToDate, upToDate)
+		}
+	}
+	if id := transformSourceID(&ClientContent{Size: 20, Time: modTime}); id != "20-1672531200000000000" {
+		t.Errorf("unexpected source id %s", id)
+	}
+}
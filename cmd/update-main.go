@@ -18,6 +18,7 @@
 package cmd
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/tls"
 	"encoding/hex"
@@ -55,12 +56,31 @@ var updateCmd = cli.Command{
 			Name:  "json",
 			Usage: "enable JSON lines formatted output",
 		},
+		cli.StringFlag{
+			Name:  "version",
+			Usage: "install the given release instead of the latest one, e.g. RELEASE.2023-04-12T02-21-51Z",
+		},
+		cli.StringFlag{
+			Name:  "channel",
+			Usage: "release channel to update from, one of 'stable', 'rc' or 'nightly'",
+			Value: "stable",
+		},
 	},
 	CustomHelpTemplate: `Name:
    {{.HelpName}} - {{.Usage}}
 
 USAGE:
-   {{.HelpName}}{{if .VisibleFlags}} [FLAGS]{{end}}
+   {{.HelpName}}{{if .VisibleFlags}} [FLAGS]{{end}} [RELEASE-INFO-URL]
+   {{.HelpName}} rollback
+
+   The replaced binary is kept next to mc, 'rollback' restores it.
+
+   Only the 'stable' channel is published for mc at the moment, updates
+   from the 'rc' and 'nightly' channels fail until they are published.
+
+   Downloaded binaries must match the minisign signature of their release,
+   signed by the MinIO release key. Set MC_UPDATE_MINISIGN_PUBKEY to the
+   public key of the releases served by a custom RELEASE-INFO-URL.
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -73,6 +93,15 @@ EXIT STATUS:
 EXAMPLES:
   1. Check and update mc:
      {{.Prompt}} {{.HelpName}}
+
+  2. Install a specific release of mc, which can be older than the running one:
+     {{.Prompt}} {{.HelpName}} --version RELEASE.2023-04-12T02-21-51Z
+
+  3. Restore the mc binary replaced by the last update:
+     {{.Prompt}} {{.HelpName}} rollback
+
+  4. Update mc from the stable release channel:
+     {{.Prompt}} {{.HelpName}} --channel stable
 `,
 }
 
@@ -81,9 +110,33 @@ const (
 	mcOSARCH               = runtime.GOOS + "-" + runtime.GOARCH
 	mcReleaseURL           = "https://dl.min.io/client/mc/release/" + mcOSARCH + "/"
 
+	// minisignPubKey is the public key of the MinIO releases, the
+	// environment variable replaces it for custom release URLs.
+	minisignPubKey    = "RWTx5Zr1tiHQLwG9keckT0c45M3AGeHD6IvimQHpyRywVWGbP1aVSGav"
 	envMinisignPubKey = "MC_UPDATE_MINISIGN_PUBKEY"
 )
 
+// mcReleaseChannels are the release channels of mc, the channels
+// without a release URL are not published.
+var mcReleaseChannels = map[string]string{
+	"stable":  mcReleaseURL,
+	"rc":      "",
+	"nightly": "",
+}
+
+// checkUpdateChannel returns an error if no releases of mc are published
+// in the channel.
+func checkUpdateChannel(channel string) *probe.Error {
+	releaseURL, ok := mcReleaseChannels[channel]
+	if !ok {
+		return probe.NewError(fmt.Errorf("unknown release channel '%s', use 'stable', 'rc' or 'nightly'", channel))
+	}
+	if releaseURL == "" {
+		return probe.NewError(fmt.Errorf("no '%s' releases of mc are published, use the 'stable' channel", channel))
+	}
+	return nil
+}
+
 // For windows our files have .exe additionally.
 var mcReleaseWindowsInfoURL = mcReleaseURL + "mc.exe.sha256sum"
 
@@ -331,6 +384,60 @@ func getDownloadURL(customReleaseURL string, releaseTag string) (downloadURL str
 	return u.String()
 }
 
+// pinnedReleaseTag returns the release tag of a version given as a release
+// tag or as a release time.
+func pinnedReleaseTag(version string) (string, *probe.Error) {
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "mc."), "RELEASE.")
+	releaseTag := "RELEASE." + version
+	if _, err := releaseTagToReleaseTime(releaseTag); err != nil {
+		return "", probe.NewError(fmt.Errorf("%s is not a release of mc, e.g. RELEASE.2023-04-12T02-21-51Z", version))
+	}
+	return releaseTag, nil
+}
+
+// getPinnedUpdateInfo returns the checksum of a given release, which is
+// published next to its binary.
+func getPinnedUpdateInfo(customReleaseURL, version string, timeout time.Duration) (sha256Hex string, currentReleaseTime, releaseTime time.Time, releaseTag string, err *probe.Error) {
+	currentReleaseTime, err = GetCurrentReleaseTime()
+	if err != nil {
+		return sha256Hex, currentReleaseTime, releaseTime, releaseTag, err.Trace()
+	}
+	if releaseTag, err = pinnedReleaseTag(version); err != nil {
+		return sha256Hex, currentReleaseTime, releaseTime, releaseTag, err
+	}
+	if IsDocker() {
+		return sha256Hex, currentReleaseTime, releaseTime, releaseTag,
+			probe.NewError(fmt.Errorf("mc runs in docker, use `%s` instead", getDownloadURL(customReleaseURL, releaseTag)))
+	}
+
+	data, err := DownloadReleaseData(getDownloadURL(customReleaseURL, releaseTag)+".sha256sum", timeout)
+	if err != nil {
+		return sha256Hex, currentReleaseTime, releaseTime, releaseTag, err.Trace(releaseTag)
+	}
+	var tag string
+	sha256Hex, releaseTime, tag, err = parseReleaseData(data)
+	if err != nil {
+		return sha256Hex, currentReleaseTime, releaseTime, releaseTag, err.Trace(releaseTag)
+	}
+	if tag != releaseTag {
+		return sha256Hex, currentReleaseTime, releaseTime, releaseTag,
+			probe.NewError(fmt.Errorf("release data of %s is for %s", releaseTag, tag))
+	}
+	return sha256Hex, currentReleaseTime, releaseTime, releaseTag, nil
+}
+
+// previousBinaryPath returns where the binary replaced by an update is kept.
+func previousBinaryPath() (string, error) {
+	path, e := os.Executable()
+	if e != nil {
+		return "", e
+	}
+	if path, e = filepath.EvalSymlinks(path); e != nil {
+		return "", e
+	}
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".previous"), nil
+}
+
 func getUpdateInfo(customReleaseURL string, timeout time.Duration) (updateMsg string, sha256Hex string, currentReleaseTime, latestReleaseTime time.Time, releaseTag string, err *probe.Error) {
 	currentReleaseTime, err = GetCurrentReleaseTime()
 	if err != nil {
@@ -422,6 +529,19 @@ func getUpdateReaderFromURL(u *url.URL, transport http.RoundTripper) (io.ReadClo
 	return newProgressReader(resp.Body, "mc", resp.ContentLength), nil
 }
 
+// getUpdateVerifier loads the minisign signature published next to the
+// binary of the release, the downloaded binary is rejected unless it
+// matches the signature.
+func getUpdateVerifier(u *url.URL, releaseTag string, transport http.RoundTripper) (*selfupdate.Verifier, error) {
+	signatureURL := *u
+	signatureURL.Path = path.Dir(u.Path) + "/mc." + releaseTag + ".minisig"
+	v := selfupdate.NewVerifier()
+	if e := v.LoadFromURL(signatureURL.String(), env.Get(envMinisignPubKey, minisignPubKey), transport); e != nil {
+		return nil, fmt.Errorf("unable to load the signature of %s: %w", releaseTag, e)
+	}
+	return v, nil
+}
+
 func doUpdate(customReleaseURL string, sha256Hex string, latestReleaseTime time.Time, releaseTag string, ok bool) (updateStatusMsg string, err *probe.Error) {
 	fmtReleaseTime := latestReleaseTime.Format(mcReleaseTagTimeLayout)
 	if !ok {
@@ -452,15 +572,14 @@ func doUpdate(customReleaseURL string, sha256Hex string, latestReleaseTime time.
 		Hash:     crypto.SHA256,
 		Checksum: sha256Sum,
 	}
+	// Keep the replaced binary for 'mc update rollback'.
+	if previousPath, e := previousBinaryPath(); e == nil {
+		os.Remove(previousPath)
+		opts.OldSavePath = previousPath
+	}
 
-	minisignPubkey := env.Get(envMinisignPubKey, "")
-	if minisignPubkey != "" {
-		v := selfupdate.NewVerifier()
-		u.Path = path.Dir(u.Path) + "/mc." + releaseTag + ".minisig"
-		if e = v.LoadFromURL(u.String(), minisignPubkey, transport); e != nil {
-			return updateStatusMsg, probe.NewError(e)
-		}
-		opts.Verifier = v
+	if opts.Verifier, e = getUpdateVerifier(u, releaseTag, transport); e != nil {
+		return updateStatusMsg, probe.NewError(e)
 	}
 
 	if e := opts.CheckPermissions(); e != nil {
@@ -491,6 +610,40 @@ func doUpdate(customReleaseURL string, sha256Hex string, latestReleaseTime time.
 	return colorGreenBold("mc updated to version RELEASE.%s successfully.", fmtReleaseTime), nil
 }
 
+// rollbackUpdate swaps the running binary with the one replaced by the last
+// update, a second rollback restores the updated binary.
+func rollbackUpdate() (updateStatusMsg string, err *probe.Error) {
+	previousPath, e := previousBinaryPath()
+	if e != nil {
+		return updateStatusMsg, probe.NewError(e)
+	}
+	data, e := os.ReadFile(previousPath)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return updateStatusMsg, probe.NewError(errors.New("no previous binary of mc was kept by an update"))
+		}
+		return updateStatusMsg, probe.NewError(e)
+	}
+
+	opts := selfupdate.Options{OldSavePath: previousPath}
+	if e = opts.CheckPermissions(); e != nil {
+		return updateStatusMsg, probe.NewError(e)
+	}
+	// Windows cannot rename over an existing file.
+	if e = os.Remove(previousPath); e != nil {
+		return updateStatusMsg, probe.NewError(e)
+	}
+	if e = selfupdate.Apply(bytes.NewReader(data), opts); e != nil {
+		if re := selfupdate.RollbackError(e); re != nil {
+			return updateStatusMsg, probe.NewError(fmt.Errorf("%w, unable to restore the running binary: %v", e, re))
+		}
+		// Keep the previous binary for another attempt.
+		os.WriteFile(previousPath, data, 0o755)
+		return updateStatusMsg, probe.NewError(e)
+	}
+	return colorGreenBold("mc restored the binary replaced by the last update."), nil
+}
+
 type updateMessage struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
@@ -520,20 +673,52 @@ func mainUpdate(ctx *cli.Context) {
 
 	customReleaseURL := ctx.Args().Get(0)
 
-	updateMsg, sha256Hex, _, latestReleaseTime, releaseTag, err := getUpdateInfo(customReleaseURL, 10*time.Second)
+	if err := checkUpdateChannel(ctx.String("channel")); err != nil {
+		errorIf(err.Trace(ctx.String("channel")), "Unable to update ‘mc’.")
+		exitProcess(-1)
+	}
+
+	if customReleaseURL == "rollback" {
+		updateStatusMsg, err := rollbackUpdate()
+		if err != nil {
+			errorIf(err, "Unable to rollback ‘mc’.")
+			exitProcess(-1)
+		}
+		printMsg(updateMessage{Status: "success", Message: updateStatusMsg})
+		exitProcess(1)
+	}
+
+	var (
+		updateMsg, sha256Hex, releaseTag string
+		latestReleaseTime                time.Time
+		err                              *probe.Error
+	)
+	if version := ctx.String("version"); version != "" {
+		var currentReleaseTime time.Time
+		sha256Hex, currentReleaseTime, latestReleaseTime, releaseTag, err = getPinnedUpdateInfo(customReleaseURL, version, 10*time.Second)
+		if err == nil && !latestReleaseTime.Equal(currentReleaseTime) {
+			updateMsg = colorGreenBold("Installing mc %s.", releaseTag)
+		}
+	} else {
+		updateMsg, sha256Hex, _, latestReleaseTime, releaseTag, err = getUpdateInfo(customReleaseURL, 10*time.Second)
+	}
 	if err != nil {
 		errorIf(err, "Unable to update ‘mc’.")
-		os.Exit(-1)
+		exitProcess(-1)
 	}
 
 	// Nothing to update running the latest release.
 	color.New(color.FgGreen, color.Bold)
 	if updateMsg == "" {
+		message := colorGreenBold("You are already running the most recent version of ‘mc’.")
+		if ctx.String("version") != "" {
+			message = colorGreenBold("You are already running ‘mc’ %s.", releaseTag)
+		}
 		printMsg(updateMessage{
 			Status:  "success",
-			Message: colorGreenBold("You are already running the most recent version of ‘mc’."),
+			Message: message,
 		})
-		os.Exit(0)
+		exitProcess(0)
 	}
 
 	printMsg(updateMessage{
@@ -548,9 +733,9 @@ func mainUpdate(ctx *cli.Context) {
 		updateStatusMsg, err = doUpdate(customReleaseURL, sha256Hex, latestReleaseTime, releaseTag, true)
 		if err != nil {
 			errorIf(err, "Unable to update ‘mc’.")
-			os.Exit(-1)
+			exitProcess(-1)
 		}
 		printMsg(updateMessage{Status: "success", Message: updateStatusMsg})
-		os.Exit(1)
+		exitProcess(1)
 	}
 }
@@ -0,0 +1,113 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"aead.dev/minisign"
+)
+
+func TestPinnedReleaseTag(t *testing.T) {
+	for _, version := range []string{
+		"RELEASE.2023-04-12T02-21-51Z",
+		"2023-04-12T02-21-51Z",
+		"mc.RELEASE.2023-04-12T02-21-51Z",
+	} {
+		tag, err := pinnedReleaseTag(version)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", version, err)
+		}
+		if tag != "RELEASE.2023-04-12T02-21-51Z" {
+			t.Fatalf("%s: unexpected release tag %s", version, tag)
+		}
+	}
+	if _, err := pinnedReleaseTag("v1.2.3"); err == nil {
+		t.Fatal("expected an error for a version which is not a release")
+	}
+}
+
+func TestGetUpdateVerifier(t *testing.T) {
+	publicKey, privateKey, e := minisign.GenerateKey(rand.Reader)
+	if e != nil {
+		t.Fatal(e)
+	}
+	binary := []byte("mc binary")
+	releaseTag := "RELEASE.2023-04-12T02-21-51Z"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/client/mc/release/linux-amd64/mc."+releaseTag+".minisig" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(minisign.Sign(privateKey, binary))
+	}))
+	defer server.Close()
+
+	key, e := publicKey.MarshalText()
+	if e != nil {
+		t.Fatal(e)
+	}
+	t.Setenv(envMinisignPubKey, string(key))
+
+	u, _ := url.Parse(server.URL + "/client/mc/release/linux-amd64/mc." + releaseTag)
+	v, e := getUpdateVerifier(u, releaseTag, http.DefaultTransport)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e = v.Verify(binary); e != nil {
+		t.Errorf("expected a valid signature, got %v", e)
+	}
+	if e = v.Verify([]byte("tampered binary")); e == nil {
+		t.Error("expected the tampered binary to be rejected")
+	}
+
+	if _, e = getUpdateVerifier(u, "RELEASE.2023-05-12T02-21-51Z", http.DefaultTransport); e == nil {
+		t.Error("expected an error for a release without signature")
+	}
+
+	// The MinIO release key is used unless the environment replaces it.
+	t.Setenv(envMinisignPubKey, "")
+	if v, e = getUpdateVerifier(u, releaseTag, http.DefaultTransport); e != nil {
+		t.Fatal(e)
+	}
+	if e = v.Verify(binary); e == nil {
+		t.Error("expected the signature to be rejected by the MinIO release key")
+	}
+}
+
+func TestCheckUpdateChannel(t *testing.T) {
+	testCases := []struct {
+		channel string
+		success bool
+	}{
+		{"stable", true},
+		{"rc", false},
+		{"nightly", false},
+		{"beta", false},
+		{"", false},
+	}
+	for i, testCase := range testCases {
+		if err := checkUpdateChannel(testCase.channel); (err == nil) != testCase.success {
+			t.Errorf("Test %d: expected success %t, got %v", i+1, testCase.success, err)
+		}
+	}
+}
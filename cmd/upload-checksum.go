@@ -0,0 +1,163 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/pkg/console"
+)
+
+// maxSinglePutSize is the largest object uploaded with a single request.
+const maxSinglePutSize = 5 * 1024 * 1024 * 1024
+
+// minUploadPartSize is the default part size of the multipart uploads.
+const minUploadPartSize = 16 * 1024 * 1024
+
+// uploadChecksumAlgorithms are the S3 additional checksums of uploads.
+var uploadChecksumAlgorithms = []string{"crc32", "crc32c", "sha1", "sha256"}
+
+var uploadChecksumFlag = cli.StringFlag{
+	Name:  "checksum",
+	Usage: "send and verify an additional checksum of the uploads: crc32, crc32c, sha1 or sha256",
+}
+
+// globalTrailingHeaders enables the checksums sent as trailers of the
+// parts of multipart uploads, see 'mc cp --checksum crc32c'.
+var globalTrailingHeaders = false
+
+// checkUploadChecksum validates the --checksum algorithm, and enables the
+// trailing checksums of the S3 clients.
+func checkUploadChecksum(ctx *cli.Context) string {
+	algorithm := strings.ToLower(ctx.String("checksum"))
+	if algorithm == "" {
+		return ""
+	}
+	for _, a := range uploadChecksumAlgorithms {
+		if a == algorithm {
+			globalTrailingHeaders = true
+			return algorithm
+		}
+	}
+	fatalIf(errInvalidArgument().Trace(algorithm), "Unsupported checksum algorithm, must be one of %s.", strings.Join(uploadChecksumAlgorithms, ", "))
+	return ""
+}
+
+// setUploadChecksum makes opts send a checksum of the content. The checksum
+// of seekable content up to the size of a single PUT is computed before the
+// upload. Other content is only supported with crc32c, which is sent for
+// every part of a multipart upload, as a trailer for files. The size to
+// upload is returned, -1 forces small streams to be uploaded in parts.
+func setUploadChecksum(reader io.Reader, size int64, algorithm string, opts *minio.PutObjectOptions) (int64, error) {
+	if rs, ok := reader.(io.ReadSeeker); ok && size >= 0 && size <= maxSinglePutSize {
+		if offset, e := rs.Seek(0, io.SeekCurrent); e == nil {
+			h, e := checksumHash(algorithm)
+			if e != nil {
+				return size, e
+			}
+			if _, e = io.CopyN(h, rs, size); e != nil {
+				return size, e
+			}
+			if _, e = rs.Seek(offset, io.SeekStart); e != nil {
+				return size, e
+			}
+			if opts.UserMetadata == nil {
+				opts.UserMetadata = map[string]string{}
+			}
+			opts.UserMetadata["X-Amz-Checksum-"+strings.ToUpper(algorithm[:1])+algorithm[1:]] = base64.StdEncoding.EncodeToString(h.Sum(nil))
+			opts.DisableMultipart = true
+			return size, nil
+		}
+	}
+	if algorithm != "crc32c" {
+		return size, fmt.Errorf("%s checksums need seekable content of at most 5 GiB, use crc32c for streams and larger objects", algorithm)
+	}
+	if opts.DisableMultipart {
+		return size, fmt.Errorf("crc32c checksums of streams need multipart uploads")
+	}
+	// The parts are sent with their CRC32C instead of their MD5.
+	opts.SendContentMd5 = false
+	// Known sizes smaller than a part are sent with a single PUT.
+	partSize := int64(opts.PartSize)
+	if partSize == 0 {
+		partSize = minUploadPartSize
+	}
+	if size >= 0 && size < partSize {
+		return -1, nil
+	}
+	return size, nil
+}
+
+// uploadChecksumMessage is the checksum of an uploaded object stored by the
+// server.
+type uploadChecksumMessage struct {
+	Status    string `json:"status"`
+	Target    string `json:"target"`
+	Algorithm string `json:"algorithm"`
+	Checksum  string `json:"checksum"`
+}
+
+// Colorized message for console printing.
+func (u uploadChecksumMessage) String() string {
+	return console.Colorize("UploadChecksum", fmt.Sprintf("`%s` %s checksum verified by the server: %s", u.Target, u.Algorithm, u.Checksum))
+}
+
+// JSON'ified message for scripting.
+func (u uploadChecksumMessage) JSON() string {
+	u.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// verifyUploadChecksum checks the server stored the checksum of an uploaded
+// object, servers without additional checksums ignore them.
+func verifyUploadChecksum(ctx context.Context, alias, urlStr, algorithm string, sse encrypt.ServerSide) (uploadChecksumMessage, *probe.Error) {
+	console.SetColor("UploadChecksum", color.New(color.FgGreen))
+
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return uploadChecksumMessage{}, err.Trace(urlStr)
+	}
+	if _, ok := clnt.(*S3Client); !ok {
+		return uploadChecksumMessage{}, probe.NewError(fmt.Errorf("additional checksums are only supported by S3 targets")).Trace(urlStr)
+	}
+	st, err := clnt.Stat(ctx, StatOptions{sse: sse, checksum: true})
+	if err != nil {
+		return uploadChecksumMessage{}, err.Trace(urlStr)
+	}
+	sum := st.Checksums[algorithm]
+	if sum == "" {
+		return uploadChecksumMessage{}, probe.NewError(fmt.Errorf("the server did not store a %s checksum of the object", algorithm)).Trace(urlStr)
+	}
+	return uploadChecksumMessage{
+		Target:    urlJoinPath(alias, clnt.GetURL().Path),
+		Algorithm: algorithm,
+		Checksum:  sum,
+	}, nil
+}
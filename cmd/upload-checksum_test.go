@@ -0,0 +1,56 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestSetUploadChecksum(t *testing.T) {
+	var opts minio.PutObjectOptions
+	reader := bytes.NewReader([]byte("hello"))
+	size, e := setUploadChecksum(reader, 5, "sha256", &opts)
+	if e != nil || size != 5 {
+		t.Fatalf("unexpected size %d, error %v", size, e)
+	}
+	if got := opts.UserMetadata["X-Amz-Checksum-Sha256"]; got != "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=" {
+		t.Fatalf("unexpected checksum %q", got)
+	}
+	if !opts.DisableMultipart {
+		t.Fatal("expected a single PUT")
+	}
+	if offset, _ := reader.Seek(0, io.SeekCurrent); offset != 0 {
+		t.Fatalf("reader not rewound, offset %d", offset)
+	}
+
+	opts = minio.PutObjectOptions{}
+	if _, e = setUploadChecksum(strings.NewReader("hello"), -1, "sha256", &opts); e == nil {
+		t.Fatal("expected an error for a sha256 checksum of a stream")
+	}
+
+	opts = minio.PutObjectOptions{SendContentMd5: true}
+	size, e = setUploadChecksum(io.LimitReader(strings.NewReader("hello"), 5), 5, "crc32c", &opts)
+	if e != nil || size != -1 || opts.SendContentMd5 {
+		t.Fatalf("unexpected size %d, md5 %v, error %v", size, opts.SendContentMd5, e)
+	}
+}
@@ -31,7 +31,17 @@ type URLs struct {
 	TotalSize        int64
 	MD5              bool
 	DisableMultipart bool
+	SourceDigest     string
+	Checksum         string
+	Compress         string
+	Transform        string
+	EncryptWith      string
+	ReplaceMetadata  bool
 	encKeyDB         map[string][]prefixSSEPair
+	manifest         *copyManifest
+	manifestChecksum string
+	aclMapper        *aclPolicyMapper
+	mirrorOp         string
 	Error            *probe.Error `json:"-"`
 	ErrorCond        differType   `json:"-"`
 }
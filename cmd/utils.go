@@ -143,6 +143,7 @@ func NewS3Config(urlStr string, aliasCfg *aliasConfigV10) *Config {
 	s3Config.ConnWriteDeadline = globalConnWriteDeadline
 	s3Config.UploadLimit = int64(globalLimitUpload)
 	s3Config.DownloadLimit = int64(globalLimitDownload)
+	s3Config.TrailingHeaders = globalTrailingHeaders
 
 	s3Config.HostURL = urlStr
 	if aliasCfg != nil {
@@ -151,6 +152,17 @@ func NewS3Config(urlStr string, aliasCfg *aliasConfigV10) *Config {
 		s3Config.SessionToken = aliasCfg.SessionToken
 		s3Config.Signature = aliasCfg.API
 		s3Config.Lookup = getLookupType(aliasCfg.Path)
+		s3Config.Region = aliasCfg.Region
+		s3Config.AuthScheme = aliasCfg.Signature
+		if aliasCfg.CredentialSource != nil {
+			s3Config.Creds = aliasCfg.CredentialSource.getCredentials()
+		}
+	}
+	applyHTTPTimeouts(s3Config, aliasCfg)
+	applyHTTPTransport(s3Config, aliasCfg)
+	applyRequestPayer(s3Config, aliasCfg)
+	if aliasCfg != nil {
+		fatalIf(loadAliasTLS(s3Config, aliasCfg), "Unable to load the TLS certificates of the alias.")
 	}
 	return s3Config
 }
@@ -0,0 +1,233 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+// eventTimeFormat is the format of the time of events sent by the server.
+const eventTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// watchCursorSaveInterval is the delay between the writes of the cursor
+// file, the events received meanwhile are only recorded in memory.
+const watchCursorSaveInterval = time.Second
+
+// watchPosition is the time of the last event received on a target, with
+// the objects of the events which happened at that time: the listing has
+// the same precision as the events, the objects listed at that time may
+// or may not have been received.
+type watchPosition struct {
+	Time time.Time
+	Seen []string
+}
+
+// watchEventID identifies the version of an object by its path and ETag.
+func watchEventID(path, etag string) string {
+	return path + "@" + strings.Trim(etag, "\"")
+}
+
+// add moves the position to the time of the event, if it is not older.
+func (p *watchPosition) add(event EventInfo) {
+	t := eventTime(event)
+	id := watchEventID(event.Path, event.ETag)
+	switch {
+	case t.After(p.Time):
+		p.Time, p.Seen = t, []string{id}
+	case t.Equal(p.Time) && !p.seen(id):
+		p.Seen = append(p.Seen, id)
+	}
+}
+
+func (p watchPosition) seen(id string) bool {
+	for _, s := range p.Seen {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
+
+// watchCursor records the position of each watched target, so that the
+// events missed while mc watch was not running can be replayed when it
+// is started again.
+type watchCursor struct {
+	mu    sync.Mutex
+	path  string
+	dirty bool
+	Last  map[string]time.Time `json:"last"`
+	Seen  map[string][]string  `json:"seen,omitempty"`
+}
+
+// loadWatchCursor reads the cursor file, a missing file is an empty cursor.
+func loadWatchCursor(path string) (*watchCursor, *probe.Error) {
+	c := &watchCursor{path: path, Last: map[string]time.Time{}, Seen: map[string][]string{}}
+	data, e := os.ReadFile(path)
+	if os.IsNotExist(e) {
+		return c, nil
+	}
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if e = json.Unmarshal(data, c); e != nil {
+		return nil, probe.NewError(e)
+	}
+	if c.Last == nil {
+		c.Last = map[string]time.Time{}
+	}
+	if c.Seen == nil {
+		c.Seen = map[string][]string{}
+	}
+	return c, nil
+}
+
+// position returns the position of target.
+func (c *watchCursor) position(target string) watchPosition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return watchPosition{Time: c.Last[target], Seen: c.Seen[target]}
+}
+
+// update records the events received for target, the cursor is written
+// by save.
+func (c *watchCursor) update(target string, events []EventInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p := watchPosition{Time: c.Last[target], Seen: c.Seen[target]}
+	for _, event := range events {
+		p.add(event)
+	}
+	if p.Time.Equal(c.Last[target]) && len(p.Seen) == len(c.Seen[target]) {
+		return
+	}
+	c.Last[target], c.Seen[target] = p.Time, p.Seen
+	c.dirty = true
+}
+
+// save writes the cursor if it changed, the file is replaced atomically
+// to survive a crash.
+func (c *watchCursor) save() *probe.Error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+
+	data, e := json.Marshal(c)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	tmp, e := os.CreateTemp(filepath.Dir(c.path), "."+filepath.Base(c.path)+".*")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if _, e = tmp.Write(data); e == nil {
+		e = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if e == nil {
+		e = os.Rename(tmp.Name(), c.path)
+	}
+	if e != nil {
+		os.Remove(tmp.Name())
+		return probe.NewError(e)
+	}
+	c.dirty = false
+	return nil
+}
+
+// eventTime parses the time of an event, the current time is returned if
+// the time cannot be parsed.
+func eventTime(event EventInfo) time.Time {
+	t, e := time.Parse(time.RFC3339Nano, event.Time)
+	if e != nil {
+		return time.Now().UTC()
+	}
+	return t
+}
+
+// replayEvents returns the events which happened on the objects of a
+// target since a position. The server keeps no history of notifications,
+// the events are rebuilt from the listing: the versions created since
+// then are put events, the delete markers are delete events. Only the
+// last put of each object is known if the bucket is not versioned.
+func replayEvents(ctx context.Context, clnt Client, options WatchOptions, since watchPosition) ([]EventInfo, *probe.Error) {
+	var withPut, withDelete bool
+	for _, event := range options.Events {
+		switch event {
+		case "put":
+			withPut = true
+		case "delete":
+			withDelete = true
+		}
+	}
+
+	var events []EventInfo
+	for content := range clnt.List(ctx, ListOptions{
+		Recursive:         true,
+		WithOlderVersions: withDelete,
+		WithDeleteMarkers: withDelete,
+		ShowDir:           DirNone,
+	}) {
+		if content.Err != nil {
+			return nil, content.Err
+		}
+		if content.Time.Before(since.Time) {
+			continue
+		}
+		if content.Time.Equal(since.Time) && since.seen(watchEventID(content.URL.String(), content.ETag)) {
+			continue
+		}
+		if options.Suffix != "" && !strings.HasSuffix(content.URL.Path, options.Suffix) {
+			continue
+		}
+		event := EventInfo{
+			Time:         content.Time.UTC().Format(eventTimeFormat),
+			Size:         content.Size,
+			UserMetadata: content.UserMetadata,
+			Path:         content.URL.String(),
+			ETag:         strings.Trim(content.ETag, "\""),
+			Type:         notification.ObjectCreatedPut,
+		}
+		if content.IsDeleteMarker {
+			if !withDelete {
+				continue
+			}
+			event.Type = notification.ObjectRemovedDeleteMarkerCreated
+		} else if !withPut {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	return events, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchCursor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursor")
+
+	cursor, err := loadWatchCursor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cursor.position("play/photos").Time.IsZero() {
+		t.Fatal("expected an empty cursor")
+	}
+
+	last := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := func(path, etag string, t time.Time) EventInfo {
+		return EventInfo{Path: path, ETag: etag, Time: t.Format(eventTimeFormat)}
+	}
+	cursor.update("play/photos", []EventInfo{event("a.jpg", "1", last.Add(-time.Second)), event("b.jpg", "2", last)})
+	// Events at the same time are recorded, older events don't move the
+	// cursor back.
+	cursor.update("play/photos", []EventInfo{event("c.jpg", "3", last), event("d.jpg", "4", last.Add(-time.Hour))})
+
+	// The cursor is only written by save.
+	if _, e := os.Stat(path); !os.IsNotExist(e) {
+		t.Fatalf("expected no cursor file before save, got %v", e)
+	}
+	if err = cursor.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cursor, err = loadWatchCursor(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := cursor.position("play/photos")
+	if !p.Time.Equal(last) || !reflect.DeepEqual(p.Seen, []string{"b.jpg@2", "c.jpg@3"}) {
+		t.Fatalf("unexpected position %+v", p)
+	}
+	if !cursor.position("play/videos").Time.IsZero() {
+		t.Fatal("expected no cursor for play/videos")
+	}
+}
+
+func TestReplayEvents(t *testing.T) {
+	dir := t.TempDir()
+	last := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	for name, modTime := range map[string]time.Time{"old": last.Add(-time.Minute), "seen": last, "missed": last, "new": last.Add(time.Minute)} {
+		file := filepath.Join(dir, name)
+		if e := os.WriteFile(file, []byte(name), 0o600); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.Chtimes(file, modTime, modTime); e != nil {
+			t.Fatal(e)
+		}
+	}
+	clnt, err := fsNew(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The events at the time of the position which were not received
+	// are replayed.
+	since := watchPosition{Time: last, Seen: []string{watchEventID(filepath.Join(dir, "seen"), "")}}
+	events, err := replayEvents(context.Background(), clnt, WatchOptions{Events: []string{"put"}}, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replayed []string
+	for _, event := range events {
+		replayed = append(replayed, filepath.Base(event.Path))
+	}
+	if !reflect.DeepEqual(replayed, []string{"missed", "new"}) {
+		t.Errorf("expected missed and new to be replayed, got %v", replayed)
+	}
+}
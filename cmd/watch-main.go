@@ -19,9 +19,11 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
@@ -50,6 +52,14 @@ var watchFlags = []cli.Flag{
 		Name:  "recursive",
 		Usage: "recursively watch for events",
 	},
+	cli.StringFlag{
+		Name:  "cursor",
+		Usage: "record the time of the last event of each target in a file, replay the events missed since then on start and after reconnecting",
+	},
+	cli.StringFlag{
+		Name:  "since",
+		Usage: "replay the events since a date or a duration (e.g. 2023.01.01T10:00, 1h), rebuilt from the object versions",
+	},
 }
 
 var watchCmd = cli.Command{
@@ -63,11 +73,19 @@ var watchCmd = cli.Command{
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} [FLAGS] TARGET
+  {{.HelpName}} [FLAGS] TARGET [TARGET...]
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
+DESCRIPTION:
+  The watch of each target is reestablished with an exponential backoff when the connection is lost.
+  The server keeps no history of events, with --cursor or --since the missed events are rebuilt from
+  the listing of the objects: the versions created are put events and the delete markers are delete
+  events. The cursor file is written at most once per second, the events received during the second
+  before a crash may be delivered twice. Only the last put of each object can be replayed if the bucket
+  is not versioned.
+
 EXAMPLES:
   1. Watch new S3 operations on a MinIO server
      {{.Prompt}} {{.HelpName}} play/testbucket
@@ -86,16 +104,40 @@ EXAMPLES:
 
   6. Watch for events on local directory.
      {{.Prompt}} {{.HelpName}} /usr/share
+
+  7. Watch new events on several buckets, without losing events when mc or the connection are interrupted.
+     {{.Prompt}} {{.HelpName}} --cursor ~/.watch-cursor play/photos play/videos
+
+  8. Replay the put and delete events of the last hour and watch new events.
+     {{.Prompt}} {{.HelpName}} --events put,delete --since 1h play/testbucket
 `,
 }
 
 // checkWatchSyntax - validate all the passed arguments
 func checkWatchSyntax(ctx *cli.Context) {
-	if len(ctx.Args()) != 1 {
+	if len(ctx.Args()) < 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
 }
 
+// parseWatchSince parses --since, a date or a duration before now.
+func parseWatchSince(since string) time.Time {
+	if since == "" {
+		return time.Time{}
+	}
+	for _, format := range rewindSupportedFormat {
+		if t, e := time.ParseInLocation(format, since, time.Local); e == nil {
+			return t
+		}
+	}
+	duration, e := ParseDuration(since)
+	fatalIf(probe.NewError(e).Trace(since), "Unable to parse --since argument.")
+	if duration < 0 {
+		fatalIf(errInvalidArgument().Trace(since), "Unable to parse --since argument, negative duration is not supported.")
+	}
+	return time.Now().Add(-time.Duration(duration))
+}
+
 // watchMessage container to hold one event notification
 type watchMessage struct {
 	Status string `json:"status"`
@@ -110,6 +152,7 @@ type watchMessage struct {
 		Port      string `json:"port,omitempty"`
 		UserAgent string `json:"userAgent,omitempty"`
 	} `json:"source,omitempty"`
+	Replayed bool `json:"replayed,omitempty"`
 }
 
 func (u watchMessage) JSON() string {
@@ -128,90 +171,197 @@ func (u watchMessage) String() string {
 	}
 	msg += console.Colorize("EventType", fmt.Sprintf("%s ", u.Event.Type))
 	msg += console.Colorize("ObjectName", u.Event.Path)
+	if u.Replayed {
+		msg += console.Colorize("Replayed", " (replayed)")
+	}
 	return msg
 }
 
+// Bounds of the delay before reconnecting a watch.
+const (
+	watchMinBackoff = time.Second
+	watchMaxBackoff = time.Minute
+)
+
+// watchEvents are events received by the watch of a target.
+type watchEvents struct {
+	target   string
+	events   []EventInfo
+	replayed bool
+}
+
+// watchTarget sends the events of target to eventsCh until ctx is done,
+// the watch is reestablished with an exponential backoff when it fails.
+// Events are replayed since the position unless it is zero, and after
+// reconnecting when replay is set.
+func watchTarget(ctx context.Context, target string, options WatchOptions, since watchPosition, replay bool, eventsCh chan<- watchEvents) {
+	clnt, err := newClient(target)
+	fatalIf(err.Trace(target), "Unable to parse the provided url.")
+
+	var replayClnt Client = clnt
+	if options.Prefix != "" {
+		replayClnt, err = newClient(urlJoinPath(target, options.Prefix))
+		fatalIf(err.Trace(target, options.Prefix), "Unable to parse the provided url.")
+	}
+
+	backoff := watchMinBackoff
+	for {
+		connectedAt := time.Now().UTC()
+		var wo *WatchObject
+		wo, err = clnt.Watch(ctx, options)
+		if err == nil {
+			if !since.Time.IsZero() {
+				events, rerr := replayEvents(ctx, replayClnt, options, since)
+				errorIf(rerr.Trace(target), "Unable to replay the events of `%s`.", target)
+				if len(events) > 0 {
+					eventsCh <- watchEvents{target: target, events: events, replayed: true}
+				}
+			}
+			since = watchPosition{Time: connectedAt}
+			err = receiveWatchEvents(ctx, target, wo, eventsCh, func(events []EventInfo) {
+				for _, event := range events {
+					since.add(event)
+				}
+				backoff = watchMinBackoff
+			})
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if _, ok := err.ToGoError().(APINotImplemented); ok {
+			fatalIf(err.Trace(target), "Unable to watch on `%s`.", target)
+		}
+		if !replay {
+			since = watchPosition{}
+		}
+		errorIf(err.Trace(target), "Unable to watch for events on `%s`, reconnecting in %s.", target, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > watchMaxBackoff {
+			backoff = watchMaxBackoff
+		}
+	}
+}
+
+// receiveWatchEvents sends the events of a watch to eventsCh until the
+// watch fails or ctx is done, received is called with the events.
+func receiveWatchEvents(ctx context.Context, target string, wo *WatchObject, eventsCh chan<- watchEvents, received func([]EventInfo)) *probe.Error {
+	for {
+		select {
+		case <-ctx.Done():
+			// Signal received we are done.
+			close(wo.DoneChan)
+			return nil
+		case events, ok := <-wo.Events():
+			if !ok {
+				return probe.NewError(errors.New("connection closed"))
+			}
+			if len(events) == 0 {
+				continue
+			}
+			eventsCh <- watchEvents{target: target, events: events}
+			received(events)
+		case err, ok := <-wo.Errors():
+			if !ok {
+				return probe.NewError(errors.New("connection closed"))
+			}
+			if err != nil {
+				close(wo.DoneChan)
+				return err
+			}
+		}
+	}
+}
+
 func mainWatch(cliCtx *cli.Context) error {
 	console.SetColor("Time", color.New(color.FgGreen))
 	console.SetColor("Size", color.New(color.FgYellow))
 	console.SetColor("EventType", color.New(color.FgCyan, color.Bold))
 	console.SetColor("ObjectName", color.New(color.Bold))
+	console.SetColor("Replayed", color.New(color.FgHiBlack))
 
 	checkWatchSyntax(cliCtx)
 
-	args := cliCtx.Args()
-	path := args[0]
-
-	prefix := cliCtx.String("prefix")
-	suffix := cliCtx.String("suffix")
-	events := strings.Split(cliCtx.String("events"), ",")
-	recursive := cliCtx.Bool("recursive")
-
-	s3Client, pErr := newClient(path)
-	if pErr != nil {
-		fatalIf(pErr.Trace(), "Unable to parse the provided url.")
+	options := WatchOptions{
+		Recursive: cliCtx.Bool("recursive"),
+		Events:    strings.Split(cliCtx.String("events"), ","),
+		Prefix:    cliCtx.String("prefix"),
+		Suffix:    cliCtx.String("suffix"),
 	}
+	since := parseWatchSince(cliCtx.String("since"))
 
-	options := WatchOptions{
-		Recursive: recursive,
-		Events:    events,
-		Prefix:    prefix,
-		Suffix:    suffix,
+	var cursor *watchCursor
+	if cursorFile := cliCtx.String("cursor"); cursorFile != "" {
+		var err *probe.Error
+		cursor, err = loadWatchCursor(cursorFile)
+		fatalIf(err.Trace(cursorFile), "Unable to read the cursor file.")
 	}
 
 	ctx, cancelWatch := context.WithCancel(globalContext)
 	defer cancelWatch()
 
-	// Start watching on events
-	wo, err := s3Client.Watch(ctx, options)
-	fatalIf(err, "Unable to watch on the specified bucket.")
+	eventsCh := make(chan watchEvents)
 
-	// Initialize.. waitgroup to track the go-routine.
+	// Start watching on the events of all targets.
 	var wg sync.WaitGroup
-
-	// Increment wait group to wait subsequent routine.
-	wg.Add(1)
-
-	// Start routine to watching on events.
-	go func() {
-		defer wg.Done()
-
-		// Wait for all events.
-		for {
-			select {
-			case <-globalContext.Done():
-				// Signal received we are done.
-				close(wo.DoneChan)
-				return
-			case events, ok := <-wo.Events():
-				if !ok {
-					return
-				}
-				for _, event := range events {
-					msg := watchMessage{}
-					msg.Event.Path = event.Path
-					msg.Event.Size = event.Size
-					msg.Event.Time = event.Time
-					msg.Event.Type = event.Type
-					msg.Source.Host = event.Host
-					msg.Source.Port = event.Port
-					msg.Source.UserAgent = event.UserAgent
-					printMsg(msg)
-				}
-			case err, ok := <-wo.Errors():
-				if !ok {
-					return
-				}
-				if err != nil {
-					errorIf(err, "Unable to watch for events.")
-					return
-				}
+	for _, target := range cliCtx.Args() {
+		targetSince := watchPosition{Time: since}
+		if cursor != nil {
+			if p := cursor.position(target); p.Time.After(since) {
+				targetSince = p
 			}
 		}
+		wg.Add(1)
+		go func(target string, since watchPosition) {
+			defer wg.Done()
+			watchTarget(ctx, target, options, since, cursor != nil || !since.Time.IsZero(), eventsCh)
+		}(target, targetSince)
+	}
+	go func() {
+		wg.Wait()
+		close(eventsCh)
 	}()
 
-	// Wait on the routine to be finished or exit.
-	wg.Wait()
+	// The cursor is saved at most once per watchCursorSaveInterval and
+	// when the watch ends.
+	var saveCursor <-chan time.Time
+	if cursor != nil {
+		ticker := time.NewTicker(watchCursorSaveInterval)
+		defer ticker.Stop()
+		saveCursor = ticker.C
+		defer func() {
+			errorIf(cursor.save().Trace(cursor.path), "Unable to update the cursor file.")
+		}()
+	}
 
-	return nil
+	for {
+		var received watchEvents
+		select {
+		case <-saveCursor:
+			errorIf(cursor.save().Trace(cursor.path), "Unable to update the cursor file.")
+			continue
+		case r, ok := <-eventsCh:
+			if !ok {
+				return nil
+			}
+			received = r
+		}
+		for _, event := range received.events {
+			msg := watchMessage{Replayed: received.replayed}
+			msg.Event.Path = event.Path
+			msg.Event.Size = event.Size
+			msg.Event.Time = event.Time
+			msg.Event.Type = event.Type
+			msg.Source.Host = event.Host
+			msg.Source.Port = event.Port
+			msg.Source.UserAgent = event.UserAgent
+			printMsg(msg)
+		}
+		if cursor != nil {
+			cursor.update(received.target, received.events)
+		}
+	}
 }
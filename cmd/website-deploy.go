@@ -0,0 +1,196 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/minio-go/v7/pkg/policy"
+	"github.com/minio/pkg/console"
+)
+
+var websiteDeployFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "index",
+		Usage: "object served for the folders of the site",
+		Value: "index.html",
+	},
+	cli.StringFlag{
+		Name:  "error",
+		Usage: "object served for the missing pages of the site, e.g. 404.html",
+	},
+	cli.StringSliceFlag{
+		Name:  "cache-control",
+		Usage: "set the Cache-Control of the objects matching a pattern, e.g. '*.html=no-cache'",
+	},
+	cli.BoolFlag{
+		Name:  "remove",
+		Usage: "remove the objects of the bucket missing from the site",
+	},
+	cli.BoolFlag{
+		Name:  "private",
+		Usage: "do not allow anonymous reads, e.g. for sites served by a proxy with credentials",
+	},
+	parallelFlag,
+}
+
+var websiteDeployCmd = cli.Command{
+	Name:         "deploy",
+	Usage:        "deploy a static website to a bucket",
+	Action:       mainWebsiteDeploy,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(websiteDeployFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] SOURCE TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Mirror the folder SOURCE to the bucket TARGET, created if missing, then set the
+  index and error documents of the bucket and allow anonymous reads. The content
+  types of the objects are guessed from the extensions of the files. The servers
+  without website configurations, like MinIO, serve the site through a proxy.
+
+EXAMPLES:
+  1. Deploy the site in the folder "./public" to the bucket "site".
+     {{.Prompt}} {{.HelpName}} ./public s3/site
+
+  2. Deploy a site with an error page, the pages are never cached and the assets are cached for a year.
+     {{.Prompt}} {{.HelpName}} --error 404.html --cache-control '*.html=no-cache' \
+        --cache-control 'assets/*=public, max-age=31536000, immutable' ./public s3/site
+
+  3. Deploy a site, removing the pages of the previous deployment which are no longer part of it.
+     {{.Prompt}} {{.HelpName}} --remove ./public s3/site
+`,
+}
+
+// websiteDeployMessage container for a site deployed by `mc website deploy`
+type websiteDeployMessage struct {
+	Status    string         `json:"status"`
+	Source    string         `json:"source"`
+	URL       string         `json:"url"`
+	Config    *websiteConfig `json:"config"`
+	Website   bool           `json:"website"`
+	Anonymous string         `json:"anonymous"`
+}
+
+func (w websiteDeployMessage) String() string {
+	msg := fmt.Sprintf("Deployed `%s` to `%s` with the index document `%s`", w.Source, w.URL, w.Config.IndexDocument.Suffix)
+	if w.Config.ErrorDocument != nil {
+		msg += fmt.Sprintf(" and the error document `%s`", w.Config.ErrorDocument.Key)
+	}
+	msg += ", anonymous access `" + w.Anonymous + "`."
+	if !w.Website {
+		msg += "\nThe server doesn't support website configurations, serve the site through a proxy."
+	}
+	return console.Colorize("WebsiteMessage", msg)
+}
+
+func (w websiteDeployMessage) JSON() string {
+	w.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(w, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainWebsiteDeploy is the handle for "mc website deploy" command.
+func mainWebsiteDeploy(cliCtx *cli.Context) error {
+	ctx, cancelWebsiteDeploy := context.WithCancel(globalContext)
+	defer cancelWebsiteDeploy()
+
+	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	console.SetColor("WebsiteMessage", color.New(color.FgGreen))
+
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	sourceURL, targetURL := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+	config := newWebsiteConfig(cliCtx.String("index"), cliCtx.String("error"))
+	if config.IndexDocument.Suffix == "" {
+		fatalIf(errInvalidArgument().Trace(targetURL), "The index document cannot be empty.")
+	}
+	cacheControlMap, e := parseCacheControlMap(cliCtx.StringSlice("cache-control"))
+	fatalIf(probe.NewError(e), "Invalid --cache-control.")
+
+	_, content, err := url2Stat(ctx, sourceURL, "", false, nil, time.Time{}, false)
+	fatalIf(err.Trace(sourceURL), "Unable to stat source `"+sourceURL+"`.")
+	if !content.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(sourceURL), "Source `"+sourceURL+"` is not a folder.")
+	}
+	if _, _, err = url2Stat(ctx, urlJoinPath(sourceURL, config.IndexDocument.Suffix), "", false, nil, time.Time{}, false); err != nil {
+		fatalIf(err.Trace(sourceURL), "The index document `"+config.IndexDocument.Suffix+"` is missing from `"+sourceURL+"`.")
+	}
+	if _, _, aliasCfg := mustExpandAlias(sourceURL); aliasCfg == nil && !filepath.IsAbs(sourceURL) {
+		if absURL, e := filepath.Abs(sourceURL); e == nil {
+			sourceURL = absURL
+		}
+	}
+
+	clnt := newWebsiteClient(targetURL)
+	fatalIf(clnt.MakeBucket(ctx, "", true, false).Trace(targetURL), "Unable to create the bucket `"+targetURL+"`.")
+
+	mj := newMirrorJob(sourceURL, targetURL, mirrorOptions{
+		isOverwrite:     true,
+		isRemove:        cliCtx.Bool("remove"),
+		cacheControlMap: cacheControlMap,
+		encKeyDB:        map[string][]prefixSSEPair{},
+		parallel:        cliCtx.Int("parallel"),
+	})
+	if mj.mirror(ctx) {
+		return transferExitStatus(nil)
+	}
+
+	website := true
+	if err = clnt.SetWebsite(ctx, config); err != nil {
+		if !isWebsiteNotImplemented(err) {
+			fatalIf(err.Trace(targetURL), "Unable to set the website configuration of `"+targetURL+"`.")
+		}
+		website = false
+	}
+
+	anonymous := accessDownload
+	if cliCtx.Bool("private") {
+		anonymous = accessPrivate
+	}
+	perm := policy.BucketPolicyReadOnly
+	if anonymous == accessPrivate {
+		perm = policy.BucketPolicyNone
+	}
+	fatalIf(clnt.SetAccess(ctx, string(perm), false).Trace(targetURL), "Unable to set the anonymous access of `"+targetURL+"`.")
+
+	printMsg(websiteDeployMessage{
+		Source:    cliCtx.Args().Get(0),
+		URL:       targetURL,
+		Config:    config,
+		Website:   website,
+		Anonymous: string(anonymous),
+	})
+	return nil
+}
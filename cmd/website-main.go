@@ -0,0 +1,143 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7"
+)
+
+var websiteSubcommands = []cli.Command{
+	websiteDeployCmd,
+	websiteStatusCmd,
+}
+
+var websiteCmd = cli.Command{
+	Name:            "website",
+	Usage:           "deploy and inspect static websites",
+	HideHelpCommand: true,
+	Action:          mainWebsite,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     websiteSubcommands,
+}
+
+// mainWebsite is the handle for "mc website" command.
+func mainWebsite(ctx *cli.Context) error {
+	commandNotFound(ctx, websiteSubcommands)
+	return nil
+	// Sub-commands like "deploy", "status" have their own main.
+}
+
+// websiteConfig is the website configuration of a bucket.
+type websiteConfig struct {
+	XMLName       xml.Name              `xml:"WebsiteConfiguration" json:"-"`
+	IndexDocument websiteIndexDocument  `xml:"IndexDocument" json:"indexDocument"`
+	ErrorDocument *websiteErrorDocument `xml:"ErrorDocument,omitempty" json:"errorDocument,omitempty"`
+}
+
+// websiteIndexDocument is the object served for the folders of a site.
+type websiteIndexDocument struct {
+	Suffix string `xml:"Suffix" json:"suffix"`
+}
+
+// websiteErrorDocument is the object served for the missing pages of a site.
+type websiteErrorDocument struct {
+	Key string `xml:"Key" json:"key"`
+}
+
+// newWebsiteConfig returns the configuration of a site, errorDocument is
+// optional.
+func newWebsiteConfig(indexDocument, errorDocument string) *websiteConfig {
+	config := &websiteConfig{IndexDocument: websiteIndexDocument{Suffix: indexDocument}}
+	if errorDocument != "" {
+		config.ErrorDocument = &websiteErrorDocument{Key: errorDocument}
+	}
+	return config
+}
+
+// newWebsiteClient returns the S3 client of a bucket, websites are served
+// from the root of buckets.
+func newWebsiteClient(aliasedURL string) *S3Client {
+	client, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize connection.")
+
+	s3Client, ok := client.(*S3Client)
+	if !ok {
+		fatalIf(errDummy().Trace(aliasedURL), "The provided url doesn't point to a S3 server.")
+	}
+	if bucket, object := s3Client.url2BucketAndObject(); bucket == "" || object != "" {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "Websites are served from the root of a bucket, e.g. `myminio/site`.")
+	}
+	return s3Client
+}
+
+// isWebsiteNotImplemented returns true when the server doesn't support
+// website configurations, like MinIO. The sites are then served by a proxy.
+func isWebsiteNotImplemented(err *probe.Error) bool {
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "NotImplemented", "MethodNotAllowed":
+		return true
+	}
+	return false
+}
+
+// cacheControlRule sets the Cache-Control of the objects matching a
+// pattern.
+type cacheControlRule struct {
+	pattern      string
+	cacheControl string
+}
+
+// cacheControlMap maps the objects to Cache-Control headers, the first
+// matching rule applies.
+type cacheControlMap []cacheControlRule
+
+// parseCacheControlMap parses PATTERN=CACHE-CONTROL entries, they aren't
+// comma separated as Cache-Control lists its directives with commas.
+func parseCacheControlMap(entries []string) (cacheControlMap, error) {
+	var m cacheControlMap
+	for _, entry := range entries {
+		pattern, cacheControl, ok := strings.Cut(entry, "=")
+		pattern, cacheControl = strings.TrimSpace(pattern), strings.TrimSpace(cacheControl)
+		if !ok || pattern == "" || cacheControl == "" {
+			return nil, fmt.Errorf("invalid cache control `%s`, expected PATTERN=CACHE-CONTROL", entry)
+		}
+		if _, e := path.Match(pattern, ""); e != nil {
+			return nil, fmt.Errorf("invalid pattern `%s`: %w", pattern, e)
+		}
+		m = append(m, cacheControlRule{pattern: pattern, cacheControl: cacheControl})
+	}
+	return m, nil
+}
+
+// lookup returns the Cache-Control of an object, empty when no rule matches.
+func (m cacheControlMap) lookup(u ClientURL) string {
+	for _, rule := range m {
+		if matchObjectPattern(rule.pattern, u) {
+			return rule.cacheControl
+		}
+	}
+	return ""
+}
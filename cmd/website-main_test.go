@@ -0,0 +1,85 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestCacheControlMap(t *testing.T) {
+	m, e := parseCacheControlMap([]string{"*.html=no-cache", "assets/*=public, max-age=31536000, immutable"})
+	if e != nil {
+		t.Fatal(e)
+	}
+	testCases := []struct {
+		url          string
+		cacheControl string
+	}{
+		{"http://localhost:9000/site/index.html", "no-cache"},
+		{"http://localhost:9000/site/blog/post.html", "no-cache"},
+		{"http://localhost:9000/site/assets/app.js", "public, max-age=31536000, immutable"},
+		{"http://localhost:9000/site/assets/img/logo.png", ""},
+		{"/var/www/site/index.html", "no-cache"},
+	}
+	for _, testCase := range testCases {
+		if cacheControl := m.lookup(*newClientURL(testCase.url)); cacheControl != testCase.cacheControl {
+			t.Errorf("%s: expected %q, got %q", testCase.url, testCase.cacheControl, cacheControl)
+		}
+	}
+
+	for _, invalid := range []string{"*.html", "=no-cache", "[.html=no-cache"} {
+		if _, e := parseCacheControlMap([]string{invalid}); e == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestWebsiteConfig(t *testing.T) {
+	testCases := []struct {
+		config *websiteConfig
+		want   string
+	}{
+		{
+			newWebsiteConfig("index.html", ""),
+			"<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument></WebsiteConfiguration>",
+		},
+		{
+			newWebsiteConfig("index.html", "404.html"),
+			"<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>404.html</Key></ErrorDocument></WebsiteConfiguration>",
+		},
+	}
+	for _, testCase := range testCases {
+		data, e := xml.Marshal(testCase.config)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if string(data) != testCase.want {
+			t.Errorf("expected %s, got %s", testCase.want, data)
+		}
+		config := &websiteConfig{}
+		if e = xml.Unmarshal(data, config); e != nil {
+			t.Fatal(e)
+		}
+		config.XMLName = xml.Name{}
+		if !reflect.DeepEqual(config, testCase.config) {
+			t.Errorf("expected %+v, got %+v", testCase.config, config)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/kirolous/mc/pkg/probe"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/pkg/console"
+)
+
+var websiteStatusCmd = cli.Command{
+	Name:         "status",
+	Usage:        "show the status of a static website",
+	Action:       mainWebsiteStatus,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the website configuration, the anonymous access and the content of the bucket "site".
+     {{.Prompt}} {{.HelpName}} s3/site
+`,
+}
+
+// websiteStatusMessage container for the status of a site
+type websiteStatusMessage struct {
+	Status       string         `json:"status"`
+	URL          string         `json:"url"`
+	Supported    bool           `json:"supported"`
+	Config       *websiteConfig `json:"config,omitempty"`
+	Anonymous    string         `json:"anonymous"`
+	Objects      int64          `json:"objects"`
+	Size         int64          `json:"size"`
+	LastModified time.Time      `json:"lastModified"`
+	IndexMissing bool           `json:"indexMissing,omitempty"`
+}
+
+func (w websiteStatusMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, console.Colorize("WebsiteTitle", w.URL+":"))
+	switch {
+	case !w.Supported:
+		fmt.Fprintf(&b, "  %-14s: %s\n", "Website", "not supported by the server, served by a proxy")
+	case w.Config == nil:
+		fmt.Fprintf(&b, "  %-14s: %s\n", "Website", console.Colorize("WebsiteWarning", "not configured"))
+	default:
+		fmt.Fprintf(&b, "  %-14s: %s\n", "Index document", w.Config.IndexDocument.Suffix)
+		if w.Config.ErrorDocument != nil {
+			fmt.Fprintf(&b, "  %-14s: %s\n", "Error document", w.Config.ErrorDocument.Key)
+		}
+	}
+	if w.IndexMissing {
+		fmt.Fprintf(&b, "  %-14s: %s\n", "Index", console.Colorize("WebsiteWarning", "missing from the bucket"))
+	}
+	fmt.Fprintf(&b, "  %-14s: %s\n", "Anonymous", w.Anonymous)
+	fmt.Fprintf(&b, "  %-14s: %d object(s), %s\n", "Content", w.Objects, humanize.IBytes(uint64(w.Size)))
+	if !w.LastModified.IsZero() {
+		fmt.Fprintf(&b, "  %-14s: %s\n", "Last modified", w.LastModified.Format(printDate))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (w websiteStatusMessage) JSON() string {
+	w.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(w, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainWebsiteStatus is the handle for "mc website status" command.
+func mainWebsiteStatus(cliCtx *cli.Context) error {
+	ctx, cancelWebsiteStatus := context.WithCancel(globalContext)
+	defer cancelWebsiteStatus()
+
+	console.SetColor("WebsiteTitle", color.New(color.Bold))
+	console.SetColor("WebsiteWarning", color.New(color.FgYellow))
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	aliasedURL := cliCtx.Args().Get(0)
+	clnt := newWebsiteClient(aliasedURL)
+
+	msg := websiteStatusMessage{URL: aliasedURL, Supported: true}
+	config, err := clnt.GetWebsite(ctx)
+	if err != nil {
+		if !isWebsiteNotImplemented(err) {
+			fatalIf(err.Trace(aliasedURL), "Unable to get the website configuration.")
+		}
+		msg.Supported = false
+	}
+	msg.Config = config
+
+	perm, _, err := clnt.GetAccess(ctx)
+	fatalIf(err.Trace(aliasedURL), "Unable to get the anonymous access.")
+	msg.Anonymous = string(stringToAccessPerm(perm))
+
+	index := ""
+	if config != nil {
+		index = config.IndexDocument.Suffix
+	}
+	msg.IndexMissing = index != ""
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		fatalIf(content.Err.Trace(aliasedURL), "Unable to list the bucket.")
+		msg.Objects++
+		msg.Size += content.Size
+		if content.Time.After(msg.LastModified) {
+			msg.LastModified = content.Time
+		}
+		if _, object := url2BucketAndObject(&content.URL); object == index {
+			msg.IndexMissing = false
+		}
+	}
+	printMsg(msg)
+	return nil
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package parquet writes flat Apache Parquet files, made of a single row
+// group with a plain encoded and gzip compressed page per column.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Type is the type of the values of a column.
+type Type int
+
+// Types of the columns.
+const (
+	Boolean Type = iota
+	Int64
+	String
+	TimestampMillis
+)
+
+// Physical, converted and encoding types of the Parquet format.
+const (
+	physicalBoolean   = 0
+	physicalInt64     = 2
+	physicalByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecGzip    = 2
+	pageTypeData = 0
+)
+
+var magic = []byte("PAR1")
+
+// Column describes a column of a file, the values of optional columns
+// may be null.
+type Column struct {
+	Name     string
+	Type     Type
+	Optional bool
+}
+
+type column struct {
+	Column
+	values  bytes.Buffer
+	bools   []bool
+	defined []bool
+}
+
+// Writer buffers the rows of a file until it is written.
+type Writer struct {
+	columns []*column
+	rows    int
+}
+
+// NewWriter returns a writer of the columns.
+func NewWriter(columns []Column) *Writer {
+	w := &Writer{}
+	for _, c := range columns {
+		w.columns = append(w.columns, &column{Column: c})
+	}
+	return w
+}
+
+// Rows returns the number of rows written.
+func (w *Writer) Rows() int {
+	return w.rows
+}
+
+// Write adds a row, the values are bool, int64, string or time.Time
+// values matching the type of their column, or nil for null.
+func (w *Writer) Write(values ...interface{}) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("parquet: %d values for %d columns", len(values), len(w.columns))
+	}
+	for i, c := range w.columns {
+		if values[i] == nil && !c.Optional {
+			return fmt.Errorf("parquet: null value for required column %s", c.Name)
+		}
+		if !validValue(c.Type, values[i]) {
+			return fmt.Errorf("parquet: invalid value %v for column %s", values[i], c.Name)
+		}
+	}
+	for i, c := range w.columns {
+		if c.Optional {
+			c.defined = append(c.defined, values[i] != nil)
+		}
+		switch v := values[i].(type) {
+		case bool:
+			c.bools = append(c.bools, v)
+		case int64:
+			binary.Write(&c.values, binary.LittleEndian, v)
+		case time.Time:
+			binary.Write(&c.values, binary.LittleEndian, v.UnixMilli())
+		case string:
+			binary.Write(&c.values, binary.LittleEndian, uint32(len(v)))
+			c.values.WriteString(v)
+		}
+	}
+	w.rows++
+	return nil
+}
+
+func validValue(typ Type, value interface{}) bool {
+	switch value.(type) {
+	case nil:
+		return true
+	case bool:
+		return typ == Boolean
+	case int64:
+		return typ == Int64
+	case string:
+		return typ == String
+	case time.Time:
+		return typ == TimestampMillis
+	}
+	return false
+}
+
+func (c *column) physicalType() int32 {
+	switch c.Type {
+	case Boolean:
+		return physicalBoolean
+	case String:
+		return physicalByteArray
+	}
+	return physicalInt64
+}
+
+func (c *column) repetition() int32 {
+	if c.Optional {
+		return repetitionOptional
+	}
+	return repetitionRequired
+}
+
+// page returns the uncompressed data page of the column: the definition
+// levels of optional columns followed by the plain encoded values.
+func (c *column) page() []byte {
+	var page bytes.Buffer
+	if c.Optional {
+		levels := encodeLevels(c.defined)
+		binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+	if c.Type == Boolean {
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, v := range c.bools {
+			if v {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		page.Write(packed)
+	}
+	page.Write(c.values.Bytes())
+	return page.Bytes()
+}
+
+// encodeLevels encodes definition levels of bit width 1 as RLE runs of
+// the hybrid RLE/bit-packed encoding.
+func encodeLevels(defined []bool) []byte {
+	var buf bytes.Buffer
+	var b [binary.MaxVarintLen64]byte
+	for i := 0; i < len(defined); {
+		j := i
+		for j < len(defined) && defined[j] == defined[i] {
+			j++
+		}
+		buf.Write(b[:binary.PutUvarint(b[:], uint64(j-i)<<1)])
+		if defined[i] {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+		i = j
+	}
+	return buf.Bytes()
+}
+
+// Schema returns the message type of the file, as printed by the Parquet
+// tools.
+func (w *Writer) Schema(name string) string {
+	fields := make([]string, 0, len(w.columns))
+	for _, c := range w.columns {
+		repetition := "required"
+		if c.Optional {
+			repetition = "optional"
+		}
+		switch c.Type {
+		case Boolean:
+			fields = append(fields, repetition+" boolean "+c.Name)
+		case Int64:
+			fields = append(fields, repetition+" int64 "+c.Name)
+		case String:
+			fields = append(fields, repetition+" binary "+c.Name+" (UTF8)")
+		case TimestampMillis:
+			fields = append(fields, repetition+" int64 "+c.Name+" (TIMESTAMP_MILLIS)")
+		}
+	}
+	return "message " + name + " { " + strings.Join(fields, "; ") + "; }"
+}
+
+type columnChunk struct {
+	offset       int64
+	uncompressed int64
+	compressed   int64
+}
+
+// WriteTo writes the file.
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	var file bytes.Buffer
+	file.Write(magic)
+
+	chunks := make([]columnChunk, len(w.columns))
+	var totalSize int64
+	for i, c := range w.columns {
+		page := c.page()
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		if _, e := gz.Write(page); e != nil {
+			return 0, e
+		}
+		if e := gz.Close(); e != nil {
+			return 0, e
+		}
+
+		var header thriftWriter
+		header.begin()
+		header.i32(1, pageTypeData)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(compressed.Len()))
+		header.structField(5)
+		header.i32(1, int32(w.rows))
+		header.i32(2, encodingPlain)
+		header.i32(3, encodingRLE)
+		header.i32(4, encodingRLE)
+		header.end()
+		header.end()
+
+		chunks[i] = columnChunk{
+			offset:       int64(file.Len()),
+			uncompressed: int64(header.buf.Len() + len(page)),
+			compressed:   int64(header.buf.Len() + compressed.Len()),
+		}
+		totalSize += chunks[i].uncompressed
+		file.Write(header.buf.Bytes())
+		file.Write(compressed.Bytes())
+	}
+
+	var meta thriftWriter
+	meta.begin()
+	meta.i32(1, 1)
+	meta.list(2, thriftStruct, len(w.columns)+1)
+	meta.begin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(w.columns)))
+	meta.end()
+	for _, c := range w.columns {
+		meta.begin()
+		meta.i32(1, c.physicalType())
+		meta.i32(3, c.repetition())
+		meta.binary(4, c.Name)
+		switch c.Type {
+		case String:
+			meta.i32(6, convertedUTF8)
+		case TimestampMillis:
+			meta.i32(6, convertedTimestampMillis)
+		}
+		meta.end()
+	}
+	meta.i64(3, int64(w.rows))
+	meta.list(4, thriftStruct, 1)
+	meta.begin()
+	meta.list(1, thriftStruct, len(w.columns))
+	for i, c := range w.columns {
+		meta.begin()
+		meta.i64(2, chunks[i].offset)
+		meta.structField(3)
+		meta.i32(1, c.physicalType())
+		meta.list(2, thriftI32, 2)
+		meta.zigzag(encodingPlain)
+		meta.zigzag(encodingRLE)
+		meta.list(3, thriftBinary, 1)
+		meta.str(c.Name)
+		meta.i32(4, codecGzip)
+		meta.i64(5, int64(w.rows))
+		meta.i64(6, chunks[i].uncompressed)
+		meta.i64(7, chunks[i].compressed)
+		meta.i64(9, chunks[i].offset)
+		meta.end()
+		meta.end()
+	}
+	meta.i64(2, totalSize)
+	meta.i64(3, int64(w.rows))
+	meta.end()
+	meta.binary(6, "mc")
+	meta.end()
+
+	file.Write(meta.buf.Bytes())
+	binary.Write(&file, binary.LittleEndian, uint32(meta.buf.Len()))
+	file.Write(magic)
+	return file.WriteTo(out)
+}
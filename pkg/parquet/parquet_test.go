@@ -0,0 +1,200 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// thriftReader decodes the Thrift compact protocol into maps of the field
+// ids to int64, string, []interface{} and nested maps.
+type thriftReader struct {
+	r *bytes.Reader
+}
+
+func (t thriftReader) zigzag() int64 {
+	v, _ := binary.ReadUvarint(t.r)
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (t thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case thriftI32, thriftI64:
+		return t.zigzag()
+	case thriftBinary:
+		n, _ := binary.ReadUvarint(t.r)
+		b := make([]byte, n)
+		io.ReadFull(t.r, b)
+		return string(b)
+	case thriftList:
+		h, _ := t.r.ReadByte()
+		size := int(h >> 4)
+		if size == 15 {
+			n, _ := binary.ReadUvarint(t.r)
+			size = int(n)
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = t.value(h & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return t.readStruct()
+	}
+	panic("unexpected thrift type")
+}
+
+func (t thriftReader) readStruct() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var id int16
+	for {
+		h, _ := t.r.ReadByte()
+		if h == 0 {
+			return fields
+		}
+		if delta := int16(h >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(t.zigzag())
+		}
+		fields[id] = t.value(h & 0x0f)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	w := NewWriter([]Column{
+		{Name: "key", Type: String},
+		{Name: "size", Type: Int64, Optional: true},
+		{Name: "is_latest", Type: Boolean, Optional: true},
+		{Name: "last_modified_date", Type: TimestampMillis},
+	})
+	modTime := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+	rows := [][]interface{}{
+		{"a", int64(42), true, modTime},
+		{"dir/b", nil, false, modTime.Add(time.Second)},
+		{"c", int64(-1), nil, modTime},
+	}
+	for i, row := range rows {
+		if e := w.Write(row...); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+	}
+	for i, row := range [][]interface{}{{"d"}, {nil, nil, nil, modTime}, {"d", "42", nil, modTime}} {
+		if e := w.Write(row...); e == nil {
+			t.Errorf("Test %d: expected an invalid row", i+1)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, e := w.WriteTo(&buf); e != nil {
+		t.Fatal(e)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, magic) || !bytes.HasSuffix(data, magic) {
+		t.Fatal("expected the magic of Parquet files")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := thriftReader{bytes.NewReader(data[len(data)-8-size : len(data)-8])}.readStruct()
+
+	if meta[3] != int64(3) {
+		t.Errorf("expected 3 rows, got %v", meta[3])
+	}
+	var names []interface{}
+	for _, e := range meta[2].([]interface{})[1:] {
+		names = append(names, e.(map[int16]interface{})[4])
+	}
+	if !reflect.DeepEqual(names, []interface{}{"key", "size", "is_latest", "last_modified_date"}) {
+		t.Errorf("unexpected schema %v", meta[2])
+	}
+
+	// Decode the page of each column.
+	rowGroup := meta[4].([]interface{})[0].(map[int16]interface{})
+	var values [][]interface{}
+	for i, chunk := range rowGroup[1].([]interface{}) {
+		md := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+		r := bytes.NewReader(data[md[9].(int64):])
+		header := thriftReader{r}.readStruct()
+		compressed := make([]byte, header[3].(int64))
+		io.ReadFull(r, compressed)
+		gz, e := gzip.NewReader(bytes.NewReader(compressed))
+		if e != nil {
+			t.Fatal(e)
+		}
+		page, _ := io.ReadAll(gz)
+		if int64(len(page)) != header[2].(int64) {
+			t.Fatalf("Column %d: unexpected page size", i+1)
+		}
+
+		defined := []bool{true, true, true}
+		if i == 1 || i == 2 {
+			n := binary.LittleEndian.Uint32(page)
+			levels := bytes.NewReader(page[4 : 4+n])
+			page = page[4+n:]
+			defined = nil
+			for levels.Len() > 0 {
+				run, _ := binary.ReadUvarint(levels)
+				v, _ := levels.ReadByte()
+				for j := uint64(0); j < run>>1; j++ {
+					defined = append(defined, v == 1)
+				}
+			}
+		}
+		var column []interface{}
+		var bit int
+		for _, d := range defined {
+			if !d {
+				column = append(column, nil)
+				continue
+			}
+			switch i {
+			case 0:
+				n := binary.LittleEndian.Uint32(page)
+				column = append(column, string(page[4:4+n]))
+				page = page[4+n:]
+			case 1:
+				column = append(column, int64(binary.LittleEndian.Uint64(page)))
+				page = page[8:]
+			case 2:
+				column = append(column, page[bit/8]&(1<<(bit%8)) != 0)
+				bit++
+			case 3:
+				column = append(column, time.UnixMilli(int64(binary.LittleEndian.Uint64(page))).UTC())
+				page = page[8:]
+			}
+		}
+		values = append(values, column)
+	}
+	for i, row := range rows {
+		for j, v := range row {
+			if !reflect.DeepEqual(values[j][i], v) {
+				t.Errorf("Row %d, column %d: expected %v, got %v", i+1, j+1, v, values[j][i])
+			}
+		}
+	}
+
+	expected := "message s3.inventory { required binary key (UTF8); optional int64 size; optional boolean is_latest; required int64 last_modified_date (TIMESTAMP_MILLIS); }"
+	if schema := w.Schema("s3.inventory"); schema != expected {
+		t.Errorf("expected %s, got %s", expected, schema)
+	}
+}